@@ -40,17 +40,17 @@ func (m *MockContainerEngine) EXPECT() *MockContainerEngineMockRecorder {
 }
 
 // Build mocks base method.
-func (m *MockContainerEngine) Build(arg0, arg1, arg2 string, arg3 map[string]string, arg4 []string) error {
+func (m *MockContainerEngine) Build(arg0, arg1, arg2 string, arg3 map[string]string, arg4 []string, arg5 *containerengine.BuildOpts) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Build", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "Build", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Build indicates an expected call of Build.
-func (mr *MockContainerEngineMockRecorder) Build(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+func (mr *MockContainerEngineMockRecorder) Build(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Build", reflect.TypeOf((*MockContainerEngine)(nil).Build), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Build", reflect.TypeOf((*MockContainerEngine)(nil).Build), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // ContainerCreate mocks base method.
@@ -112,6 +112,36 @@ func (mr *MockContainerEngineMockRecorder) ImagePull(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImagePull", reflect.TypeOf((*MockContainerEngine)(nil).ImagePull), arg0, arg1)
 }
 
+// ImageSize mocks base method.
+func (m *MockContainerEngine) ImageSize(arg0 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImageSize", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImageSize indicates an expected call of ImageSize.
+func (mr *MockContainerEngineMockRecorder) ImageSize(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageSize", reflect.TypeOf((*MockContainerEngine)(nil).ImageSize), arg0)
+}
+
+// Info mocks base method.
+func (m *MockContainerEngine) Info() (*containerengine.EngineInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Info")
+	ret0, _ := ret[0].(*containerengine.EngineInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockContainerEngineMockRecorder) Info() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockContainerEngine)(nil).Info))
+}
+
 // ListImages mocks base method.
 func (m *MockContainerEngine) ListImages(arg0, arg1 string) ([]containerengine.Image, error) {
 	m.ctrl.T.Helper()
@@ -155,6 +185,21 @@ func (mr *MockContainerEngineMockRecorder) RemoveByLabel(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveByLabel", reflect.TypeOf((*MockContainerEngine)(nil).RemoveByLabel), arg0)
 }
 
+// RepoDigest mocks base method.
+func (m *MockContainerEngine) RepoDigest(arg0 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepoDigest", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepoDigest indicates an expected call of RepoDigest.
+func (mr *MockContainerEngineMockRecorder) RepoDigest(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepoDigest", reflect.TypeOf((*MockContainerEngine)(nil).RepoDigest), arg0)
+}
+
 // Start mocks base method.
 func (m *MockContainerEngine) Start(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -183,6 +228,20 @@ func (mr *MockContainerEngineMockRecorder) Stop(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockContainerEngine)(nil).Stop), arg0, arg1)
 }
 
+// Tag mocks base method.
+func (m *MockContainerEngine) Tag(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tag", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Tag indicates an expected call of Tag.
+func (mr *MockContainerEngineMockRecorder) Tag(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tag", reflect.TypeOf((*MockContainerEngine)(nil).Tag), arg0, arg1)
+}
+
 // Type mocks base method.
 func (m *MockContainerEngine) Type() string {
 	m.ctrl.T.Helper()
@@ -0,0 +1,80 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apiclient generates typed clients for the APIs collected from a project's code.
+package apiclient
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// generators return the client code of an API, called with the URL the API is served from.
+var generators = map[string]struct {
+	ext      string
+	generate func(doc *openapi3.T, baseUrl string) (string, error)
+}{
+	"ts": {ext: ".ts", generate: TypeScript},
+}
+
+// Languages are the languages clients can be generated in.
+func Languages() []string {
+	langs := []string{}
+	for l := range generators {
+		langs = append(langs, l)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Generate writes a client for each API to dir, endpoints are the URLs the APIs are served from.
+// It returns the files written.
+func Generate(lang, dir string, apis map[string]*openapi3.T, endpoints map[string]string) ([]string, error) {
+	g, ok := generators[lang]
+	if !ok {
+		return nil, utils.NewNotSupportedErr(fmt.Sprintf("api clients can not be generated in %s, use one of %v", lang, Languages()))
+	}
+
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for name, doc := range apis {
+		code, err := g.generate(doc, endpoints[name])
+		if err != nil {
+			return nil, err
+		}
+
+		f := filepath.Join(dir, name+g.ext)
+		if err := ioutil.WriteFile(f, []byte(code), 0644); err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files, nil
+}
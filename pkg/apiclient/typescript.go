@@ -0,0 +1,208 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiclient
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const tsHeader = `// Code generated by nitric api client, DO NOT EDIT.
+
+export const baseUrl = %q;
+
+export interface RequestOptions {
+  baseUrl?: string;
+  headers?: Record<string, string>;
+}
+
+async function request<T>(method: string, path: string, body?: unknown, options: RequestOptions = {}): Promise<T> {
+  const res = await fetch(` + "`${options.baseUrl ?? baseUrl}${path}`" + `, {
+    method,
+    headers: { "Content-Type": "application/json", ...options.headers },
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  if (!res.ok) {
+    throw new Error(` + "`${method} ${path} failed with ${res.status}: ${await res.text()}`" + `);
+  }
+  const text = await res.text();
+  return (text ? JSON.parse(text) : undefined) as T;
+}
+`
+
+// tsType is the TypeScript type of the schema, unknown when there isn't one.
+func tsType(ref *openapi3.SchemaRef) string {
+	if ref == nil || ref.Value == nil {
+		return "unknown"
+	}
+	s := ref.Value
+
+	switch s.Type {
+	case "string":
+		if len(s.Enum) > 0 {
+			values := []string{}
+			for _, e := range s.Enum {
+				values = append(values, fmt.Sprintf("%q", e))
+			}
+			return strings.Join(values, " | ")
+		}
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		return "Array<" + tsType(s.Items) + ">"
+	case "object":
+		if len(s.Properties) == 0 {
+			return "Record<string, unknown>"
+		}
+		required := map[string]bool{}
+		for _, r := range s.Required {
+			required[r] = true
+		}
+		names := []string{}
+		for n := range s.Properties {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+
+		fields := []string{}
+		for _, n := range names {
+			opt := "?"
+			if required[n] {
+				opt = ""
+			}
+			fields = append(fields, fmt.Sprintf("%q%s: %s", n, opt, tsType(s.Properties[n])))
+		}
+		return "{ " + strings.Join(fields, "; ") + " }"
+	default:
+		return "unknown"
+	}
+}
+
+// jsonSchema returns the application/json schema of the content.
+func jsonSchema(content openapi3.Content) *openapi3.SchemaRef {
+	if mt := content.Get("application/json"); mt != nil {
+		return mt.Schema
+	}
+	return nil
+}
+
+// tsFunctionName names an operation from its method and path, e.g. GET /orders/{id} is getOrdersById.
+func tsFunctionName(method, path string) string {
+	words := []string{strings.ToLower(method)}
+	for _, seg := range strings.Split(path, "/") {
+		if seg == "" {
+			continue
+		}
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			words = append(words, "by", tsIdentifier(strings.Trim(seg, "{}")))
+			continue
+		}
+		words = append(words, tsIdentifier(seg))
+	}
+
+	name := words[0]
+	for _, w := range words[1:] {
+		if w != "" {
+			name += strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return name
+}
+
+// tsIdentifier removes the characters that can't be used in an identifier.
+func tsIdentifier(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// TypeScript returns a client with a function for each operation of the API.
+func TypeScript(doc *openapi3.T, baseUrl string) (string, error) {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, tsHeader, baseUrl)
+
+	paths := []string{}
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	names := map[string]int{}
+	for _, p := range paths {
+		item := doc.Paths[p]
+
+		methods := []string{}
+		for m := range item.Operations() {
+			methods = append(methods, m)
+		}
+		sort.Strings(methods)
+
+		for _, m := range methods {
+			op := item.GetOperation(m)
+
+			name := tsFunctionName(m, p)
+			names[name]++
+			if names[name] > 1 {
+				name = fmt.Sprintf("%s%d", name, names[name])
+			}
+
+			params := []string{}
+			urlPath := p
+			for _, pr := range append(item.Parameters, op.Parameters...) {
+				if pr.Value == nil || pr.Value.In != openapi3.ParameterInPath {
+					continue
+				}
+				id := tsIdentifier(pr.Value.Name)
+				params = append(params, fmt.Sprintf("%s: string", id))
+				urlPath = strings.ReplaceAll(urlPath, "{"+pr.Value.Name+"}", "${encodeURIComponent("+id+")}")
+			}
+
+			body := "undefined"
+			if m == http.MethodPost || m == http.MethodPut || m == http.MethodPatch {
+				bodyType := "unknown"
+				if op.RequestBody != nil && op.RequestBody.Value != nil {
+					bodyType = tsType(jsonSchema(op.RequestBody.Value.Content))
+				}
+				params = append(params, "body: "+bodyType)
+				body = "body"
+			}
+			params = append(params, "options?: RequestOptions")
+
+			resultType := "unknown"
+			if resp := op.Responses.Get(200); resp != nil && resp.Value != nil {
+				resultType = tsType(jsonSchema(resp.Value.Content))
+			}
+
+			fmt.Fprintf(sb, "\n/** %s %s */\n", m, p)
+			fmt.Fprintf(sb, "export function %s(%s): Promise<%s> {\n", name, strings.Join(params, ", "), resultType)
+			fmt.Fprintf(sb, "  return request<%s>(%q, `%s`, %s, options);\n", resultType, m, urlPath, body)
+			fmt.Fprintf(sb, "}\n")
+		}
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,77 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiclient
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestTsFunctionName(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{method: "GET", path: "/", want: "get"},
+		{method: "GET", path: "/orders/", want: "getOrders"},
+		{method: "DELETE", path: "/orders/{id}/", want: "deleteOrdersById"},
+		{method: "POST", path: "/user-profiles/{user_id}/", want: "postUserprofilesByUser_id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tsFunctionName(tt.method, tt.path); got != tt.want {
+				t.Errorf("tsFunctionName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypeScript(t *testing.T) {
+	doc := &openapi3.T{Paths: openapi3.Paths{
+		"/orders/{id}/": &openapi3.PathItem{
+			Parameters: openapi3.Parameters{
+				{Value: &openapi3.Parameter{In: "path", Name: "id", Required: true}},
+			},
+			Get: &openapi3.Operation{Responses: openapi3.NewResponses()},
+			Put: &openapi3.Operation{
+				RequestBody: &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithJSONSchema(
+					openapi3.NewObjectSchema().WithProperty("status", openapi3.NewStringSchema()),
+				)},
+				Responses: openapi3.NewResponses(),
+			},
+		},
+	}}
+
+	got, err := TypeScript(doc, "https://abc.execute-api.us-east-1.amazonaws.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`export const baseUrl = "https://abc.execute-api.us-east-1.amazonaws.com";`,
+		"export function getOrdersById(id: string, options?: RequestOptions): Promise<unknown> {",
+		"return request<unknown>(\"GET\", `/orders/${encodeURIComponent(id)}/`, undefined, options);",
+		`export function putOrdersById(id: string, body: { "status"?: string }, options?: RequestOptions): Promise<unknown> {`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("TypeScript() missing %s in\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pterm/pterm"
+
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// Config is where a project records its stack operations, in nitric.yaml under audit.
+type Config struct {
+	// File is appended with an entry per operation as JSON lines, relative to the project.
+	File string `yaml:"file,omitempty"`
+	// Url is POSTed each entry as JSON.
+	Url string `yaml:"url,omitempty"`
+}
+
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Entry records who ran a stack operation and how it went.
+type Entry struct {
+	Time     time.Time `json:"time"`
+	User     string    `json:"user"`
+	Command  string    `json:"command"`
+	Target   string    `json:"target"`
+	Stack    string    `json:"stack"`
+	Result   string    `json:"result"`
+	Error    string    `json:"error,omitempty"`
+	Duration float64   `json:"durationSeconds"`
+	GitSha   string    `json:"gitSha,omitempty"`
+}
+
+var client = &http.Client{Timeout: 5 * time.Second}
+
+// Run runs the operation and records it in the project's audit log, returning the operation's error.
+func Run(c *Config, dir, command, target, stack string, operation func() error) error {
+	if c == nil {
+		return operation()
+	}
+
+	start := time.Now()
+	err := operation()
+
+	e := &Entry{
+		Time:     start.UTC(),
+		User:     utils.GitUser(dir),
+		Command:  command,
+		Target:   target,
+		Stack:    stack,
+		Result:   ResultSuccess,
+		Duration: time.Since(start).Round(time.Millisecond).Seconds(),
+		GitSha:   utils.GitCommit(dir),
+	}
+	if err != nil {
+		e.Result = ResultFailure
+		e.Error = err.Error()
+	}
+
+	if rerr := Record(c, dir, e); rerr != nil {
+		// the operation has already happened, so it isn't failed because it couldn't be recorded
+		pterm.Warning.Printf("unable to record the audit entry: %v\n", rerr)
+	}
+	return err
+}
+
+// Record appends the entry to the audit file and sends it to the audit url, those that are configured.
+func Record(c *Config, dir string, e *Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if c.File != "" {
+		file := c.File
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(append(b, '\n'))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.Url != "" {
+		resp, err := client.Post(c.Url, "application/json", bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("audit url %s returned %s", c.Url, resp.Status)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	c := &Config{File: ".nitric/audit.jsonl"}
+
+	if err := Run(c, dir, "stack update", "aws", "prod", func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	failed := errors.New("deployment failed")
+	if err := Run(c, dir, "stack down", "aws", "prod", func() error { return failed }); err != failed {
+		t.Fatalf("Run() error = %v, want %v", err, failed)
+	}
+
+	f, err := os.Open(filepath.Join(dir, ".nitric", "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	entries := []Entry{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		e := Entry{}
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, e)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].Command != "stack update" || entries[0].Result != ResultSuccess || entries[0].Stack != "prod" || entries[0].Target != "aws" {
+		t.Errorf("unexpected first entry %+v", entries[0])
+	}
+	if entries[1].Command != "stack down" || entries[1].Result != ResultFailure || entries[1].Error != failed.Error() {
+		t.Errorf("unexpected second entry %+v", entries[1])
+	}
+}
+
+func TestRunNotConfigured(t *testing.T) {
+	called := false
+	if err := Run(nil, t.TempDir(), "stack update", "aws", "prod", func() error {
+		called = true
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !called {
+		t.Error("the operation was not run")
+	}
+}
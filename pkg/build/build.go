@@ -21,24 +21,80 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/nitrictech/cli/pkg/containerengine"
 	"github.com/nitrictech/cli/pkg/project"
 	"github.com/nitrictech/cli/pkg/runtime"
 	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
 )
 
+// now is when the images are labelled as created, replaced in tests.
+var now = time.Now
+
 func dynamicDockerfile(dir, name string) (*os.File, error) {
 	// create a more stable file name for the hashing
 	return os.CreateTemp(dir, "nitric.dynamic.Dockerfile.*")
 }
 
-func Create(s *project.Project, t *stack.Config) error {
+// buildOpts resolves the project's build secrets relative to the project directory.
+func buildOpts(s *project.Project) *containerengine.BuildOpts {
+	opts := &containerengine.BuildOpts{SSH: s.Build.SSH}
+	if len(s.Build.Secrets) > 0 {
+		opts.Secrets = map[string]string{}
+		for id, src := range s.Build.Secrets {
+			if !filepath.IsAbs(src) {
+				src = filepath.Join(s.Dir, src)
+			}
+			opts.Secrets[id] = src
+		}
+	}
+	return opts
+}
+
+// imageLabels identify the project, stack and compute unit an image was built for, and the commit it
+// was built from, so the deployed images can be traced back to their source.
+func imageLabels(s *project.Project, t *stack.Config, name string, created time.Time) map[string]string {
+	labels := map[string]string{
+		"org.opencontainers.image.title":   s.Name + "-" + name,
+		"org.opencontainers.image.created": created.UTC().Format(time.RFC3339),
+		"io.nitric.project":                s.Name,
+		"io.nitric.stack":                  t.Name,
+		"io.nitric.function":               name,
+	}
+	if commit := utils.GitCommit(s.Dir); commit != "" {
+		labels["org.opencontainers.image.revision"] = commit
+	}
+	return labels
+}
+
+// Preflight checks the container engine can build the project's images for the platform.
+func Preflight(s *project.Project, platform string) error {
+	return containerengine.Preflight(platform, buildOpts(s))
+}
+
+// Create builds the project's images, functions are built from the lockedImages digests when given.
+func Create(s *project.Project, t *stack.Config, lockedImages map[string]string) error {
 	cr, err := containerengine.Discover()
 	if err != nil {
 		return err
 	}
+
+	created := now()
+	funcOpts := buildOpts(s)
+	funcOpts.Platform = containerengine.DeployPlatform
+	if len(lockedImages) > 0 {
+		if err := RestoreImages(lockedImages); err != nil {
+			return err
+		}
+		funcOpts.Pinned = true
+	}
+
 	for _, f := range s.Functions {
+		if !t.DeploysFunction(f.Name) {
+			continue
+		}
 		fh, err := dynamicDockerfile(s.Dir, f.Name)
 		if err != nil {
 			return err
@@ -49,35 +105,64 @@ func Create(s *project.Project, t *stack.Config) error {
 		if err != nil {
 			return err
 		}
-		err = rt.FunctionDockerfile(s.Dir, f.VersionString(s), t.Provider, fh)
+		version := f.VersionString(s)
+		if t.MembraneVersion != "" {
+			version = t.MembraneVersion
+		}
+		err = rt.FunctionDockerfile(s.Dir, version, t.Provider, fh)
 		if err != nil {
 			return err
 		}
 		fh.Close()
 
 		buildArgs := map[string]string{"PROVIDER": t.Provider}
-		err = cr.Build(filepath.Base(fh.Name()), s.Dir, f.ImageTagName(s, t.Provider), buildArgs, rt.BuildIgnore())
+		opts := *funcOpts
+		opts.Timeout = f.BuildTimeout
+		opts.Labels = imageLabels(s, t, f.Name, created)
+		err = cr.Build(filepath.Base(fh.Name()), s.Dir, f.ImageTagName(s, t.Provider), buildArgs, rt.BuildIgnore(), &opts)
 		if err != nil {
 			return err
 		}
+
+		if t.GeneratesSBOM() {
+			if err := GenerateSBOM(s, f.ImageTagName(s, t.Provider)); err != nil {
+				return err
+			}
+		}
 	}
 
 	for _, c := range s.Containers {
 		buildArgs := map[string]string{"PROVIDER": t.Provider}
-		err := cr.Build(filepath.Join(s.Dir, c.Dockerfile), s.Dir, c.ImageTagName(s, t.Provider), buildArgs, []string{})
+		containerOpts := buildOpts(s)
+		containerOpts.Platform = containerengine.DeployPlatform
+		containerOpts.Timeout = c.BuildTimeout
+		containerOpts.Labels = imageLabels(s, t, c.Name, created)
+		err := cr.Build(filepath.Join(s.Dir, c.Dockerfile), s.Dir, c.ImageTagName(s, t.Provider), buildArgs, []string{}, containerOpts)
 		if err != nil {
 			return err
 		}
+
+		if t.GeneratesSBOM() {
+			if err := GenerateSBOM(s, c.ImageTagName(s, t.Provider)); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
-// CreateBaseDev builds images for code-as-config
-func CreateBaseDev(s *project.Project) error {
+// CreateBaseDev builds images for code-as-config and local run, for the host platform unless one is given
+func CreateBaseDev(s *project.Project, platform string) error {
 	ce, err := containerengine.Discover()
 	if err != nil {
 		return err
 	}
+	if platform == "" {
+		platform = containerengine.HostPlatform()
+	}
+	devOpts := buildOpts(s)
+	devOpts.Platform = platform
+
 	imagesToBuild := map[string]string{}
 	for _, f := range s.Functions {
 		rt, err := runtime.NewRunTimeFromHandler(f.Handler)
@@ -104,7 +189,7 @@ func CreateBaseDev(s *project.Project) error {
 			return err
 		}
 
-		if err := ce.Build(filepath.Base(f.Name()), s.Dir, rt.DevImageName(), map[string]string{}, rt.BuildIgnore()); err != nil {
+		if err := ce.Build(filepath.Base(f.Name()), s.Dir, rt.DevImageName(), map[string]string{}, rt.BuildIgnore(), devOpts); err != nil {
 			return err
 		}
 		imagesToBuild[lang] = rt.DevImageName()
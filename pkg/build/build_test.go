@@ -19,7 +19,9 @@ package build
 import (
 	"io/ioutil"
 	"os"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
@@ -42,41 +44,105 @@ func TestCreateBaseDev(t *testing.T) {
 	s := project.New(&project.Config{Name: "", Dir: dir})
 	s.Functions = map[string]project.Function{"foo": {Handler: "functions/list.ts"}}
 
-	me.EXPECT().Build(gomock.Any(), dir, "nitric-ts-dev", map[string]string{}, []string{"node_modules/", ".nitric/", ".git/", ".idea/"})
+	me.EXPECT().Build(gomock.Any(), dir, "nitric-ts-dev", map[string]string{}, []string{"node_modules/", ".nitric/", ".git/", ".idea/"}, &containerengine.BuildOpts{Platform: containerengine.HostPlatform()})
 
 	containerengine.DiscoveredEngine = me
 
-	if err := CreateBaseDev(s); err != nil {
+	if err := CreateBaseDev(s, ""); err != nil {
 		t.Errorf("CreateBaseDev() error = %v", err)
 	}
 }
 
 func TestCreate(t *testing.T) {
+	created := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	now = func() time.Time { return created }
+	defer func() { now = time.Now }()
+
+	s := &project.Project{
+		Name: "test-stack",
+		Dir:  ".",
+		Functions: map[string]project.Function{
+			"list": {
+				Handler:     "functions/list.ts",
+				ComputeUnit: project.ComputeUnit{},
+			},
+		},
+		Containers: map[string]project.Container{
+			"doit": {
+				Dockerfile:  "Dockerfile.custom",
+				ComputeUnit: project.ComputeUnit{},
+			},
+		},
+	}
+
+	sc := &stack.Config{Provider: "aws", Region: "eastus"}
+
 	ctrl := gomock.NewController(t)
 	me := mock_containerengine.NewMockContainerEngine(ctrl)
-	me.EXPECT().Build(gomock.Any(), ".", "test-stack--aws", map[string]string{"PROVIDER": "aws"}, []string{"node_modules/", ".nitric/", ".git/", ".idea/"})
-	me.EXPECT().Build("Dockerfile.custom", ".", "test-stack--aws", map[string]string{"PROVIDER": "aws"}, []string{})
+	me.EXPECT().Build(gomock.Any(), ".", "test-stack--aws", map[string]string{"PROVIDER": "aws"}, []string{"node_modules/", ".nitric/", ".git/", ".idea/"}, &containerengine.BuildOpts{Platform: containerengine.DeployPlatform, Labels: imageLabels(s, sc, "", created)})
+	me.EXPECT().Build("Dockerfile.custom", ".", "test-stack--aws", map[string]string{"PROVIDER": "aws"}, []string{}, &containerengine.BuildOpts{Platform: containerengine.DeployPlatform, Labels: imageLabels(s, sc, "", created)})
 
 	containerengine.DiscoveredEngine = me
 
+	if err := Create(s, sc, nil); err != nil {
+		t.Errorf("CreateBaseDev() error = %v", err)
+	}
+}
+
+func TestImageLabels(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-nitric-labels")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := &project.Project{Name: "shop", Dir: dir}
+	got := imageLabels(s, &stack.Config{Name: "prod"}, "orders", time.Date(2022, 5, 1, 10, 0, 0, 0, time.FixedZone("AEST", 10*60*60)))
+	want := map[string]string{
+		"org.opencontainers.image.title":   "shop-orders",
+		"org.opencontainers.image.created": "2022-05-01T00:00:00Z",
+		"io.nitric.project":                "shop",
+		"io.nitric.stack":                  "prod",
+		"io.nitric.function":               "orders",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("imageLabels() = %v, want %v", got, want)
+	}
+}
+
+func TestImageSizes(t *testing.T) {
 	s := &project.Project{
 		Name: "test-stack",
 		Dir:  ".",
 		Functions: map[string]project.Function{
 			"list": {
 				Handler:     "functions/list.ts",
-				ComputeUnit: project.ComputeUnit{},
+				ComputeUnit: project.ComputeUnit{Name: "list"},
 			},
 		},
 		Containers: map[string]project.Container{
 			"doit": {
 				Dockerfile:  "Dockerfile.custom",
-				ComputeUnit: project.ComputeUnit{},
+				ComputeUnit: project.ComputeUnit{Name: "doit"},
 			},
 		},
 	}
 
-	if err := Create(s, &stack.Config{Provider: "aws", Region: "eastus"}); err != nil {
-		t.Errorf("CreateBaseDev() error = %v", err)
+	ctrl := gomock.NewController(t)
+	me := mock_containerengine.NewMockContainerEngine(ctrl)
+	want := map[string]int64{}
+	for i, c := range s.Computes() {
+		size := int64(i+1) * 1024 * 1024
+		me.EXPECT().ImageSize(c.ImageTagName(s, "aws")).Return(size, nil)
+		want[c.Unit().Name] = size
+	}
+	containerengine.DiscoveredEngine = me
+
+	got, err := ImageSizes(s, "aws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ImageSizes() = %v, want %v", got, want)
 	}
 }
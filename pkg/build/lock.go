@@ -0,0 +1,86 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package build
+
+import (
+	"sort"
+
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/containerengine"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/runtime"
+)
+
+// BaseImages returns the images that the project's functions are built from.
+func BaseImages(s *project.Project) ([]string, error) {
+	unique := map[string]bool{}
+	for _, f := range s.Functions {
+		rt, err := runtime.NewRunTimeFromHandler(f.Handler)
+		if err != nil {
+			return nil, err
+		}
+		for _, img := range rt.BaseImages() {
+			unique[img] = true
+		}
+	}
+
+	images := []string{}
+	for img := range unique {
+		images = append(images, img)
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+// ImageDigests returns the repository digest of each of the local images.
+func ImageDigests(images []string) (map[string]string, error) {
+	ce, err := containerengine.Discover()
+	if err != nil {
+		return nil, err
+	}
+
+	digests := map[string]string{}
+	for _, img := range images {
+		digests[img], err = ce.RepoDigest(img)
+		if err != nil {
+			return nil, errors.WithMessage(err, img)
+		}
+	}
+	return digests, nil
+}
+
+// RestoreImages pulls the locked digests and tags them with the image names used by the builds.
+func RestoreImages(digests map[string]string) error {
+	ce, err := containerengine.Discover()
+	if err != nil {
+		return err
+	}
+
+	for img, digest := range digests {
+		if current, err := ce.RepoDigest(img); err == nil && current == digest {
+			continue
+		}
+		if err := ce.ImagePull(digest, types.ImagePullOptions{}); err != nil {
+			return errors.WithMessage(err, "restoring "+img)
+		}
+		if err := ce.Tag(digest, img); err != nil {
+			return errors.WithMessage(err, "restoring "+img)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/containerengine"
+	"github.com/nitrictech/cli/pkg/project"
+)
+
+// ImageSizes returns the size in bytes of the image built for each of the project's functions and containers.
+func ImageSizes(s *project.Project, provider string) (map[string]int64, error) {
+	ce, err := containerengine.Discover()
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := map[string]int64{}
+	for _, c := range s.Computes() {
+		sizes[c.Unit().Name], err = ce.ImageSize(c.ImageTagName(s, provider))
+		if err != nil {
+			return nil, errors.WithMessage(err, "the image of "+c.Unit().Name+" has not been built")
+		}
+	}
+	return sizes, nil
+}
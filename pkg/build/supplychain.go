@@ -0,0 +1,99 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// syftPredicateType is the in-toto predicate type of syft's json format, as used by syft attest.
+const syftPredicateType = "https://syft.dev/bom"
+
+// SBOMFile returns where the SBOM of the local image is written, in syft's json format.
+func SBOMFile(s *project.Project, image string) string {
+	return filepath.Join(utils.NitricLogDir(s.Dir), "sbom", image+".syft.json")
+}
+
+// GenerateSBOM writes the SBOM of the local image with syft.
+func GenerateSBOM(s *project.Project, image string) error {
+	if _, err := exec.LookPath("syft"); err != nil {
+		return errors.New("syft is required to generate SBOMs, see https://github.com/anchore/syft#installation")
+	}
+
+	stderr := &bytes.Buffer{}
+	cmd := exec.Command("syft", image, "-o", "syft-json", "-q")
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return errors.WithMessagef(err, "syft %s: %s", image, strings.TrimSpace(stderr.String()))
+	}
+
+	file := SBOMFile(s, image)
+	if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, out, 0644)
+}
+
+// Sign signs the pushed images with cosign, attaching the SBOM of the local image each was built from as an attestation.
+// The images are the pushed image names by local image name, as returned in the deployment.
+func Sign(s *project.Project, key string, images map[string]string) error {
+	if _, err := exec.LookPath("cosign"); err != nil {
+		return errors.New("cosign is required to sign images, see https://docs.sigstore.dev/cosign/installation")
+	}
+
+	locals := []string{}
+	for local := range images {
+		locals = append(locals, local)
+	}
+	sort.Strings(locals)
+
+	for _, local := range locals {
+		pushed := images[local]
+		if err := cosign("sign", "--key", key, "--yes", pushed); err != nil {
+			return err
+		}
+
+		sbom := SBOMFile(s, local)
+		if _, err := os.Stat(sbom); err != nil {
+			continue
+		}
+		if err := cosign("attest", "--key", key, "--yes", "--type", syftPredicateType, "--predicate", sbom, pushed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func cosign(args ...string) error {
+	out, err := exec.Command("cosign", args...).CombinedOutput()
+	if err != nil {
+		return errors.WithMessagef(err, "cosign %s %s: %s", args[0], args[len(args)-1], strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cli lets other tools, e.g. IDE extensions, run nitric commands in process rather than
+// running the nitric binary.
+package cli
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/nitrictech/cli/pkg/cmd"
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// Config is how the embedded commands are run, the zero value behaves like the nitric binary.
+type Config struct {
+	// In is read by commands that take input from stdin
+	In io.Reader
+	// Out receives the output and progress of the commands
+	Out io.Writer
+	// Err receives cobra's usage and help errors
+	Err io.Writer
+	// Dir is the project directory, like --cwd. The process is in it while a command runs.
+	Dir string
+	// CI disables the styling and interactive progress of the output, like --ci
+	CI bool
+	// Offline uses cached templates, plugins and base images, like --offline
+	Offline bool
+}
+
+// Command is the nitric root command, run a command with SetArgs and Execute.
+type Command struct {
+	*cobra.Command
+	config Config
+}
+
+// New returns the nitric root command configured with c.
+// The commands share the process' state, so only one should run at a time.
+func New(c Config) (*Command, error) {
+	root := &Command{Command: cmd.RootCommand(), config: c}
+	if err := root.configure(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// Execute runs the command given by SetArgs. Flags given to a previous command are reset first, and the
+// process returns to its working directory afterwards.
+func (c *Command) Execute() error {
+	if err := c.configure(); err != nil {
+		return err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+
+	return c.Command.Execute()
+}
+
+// configure resets every flag of the command tree to its default, then applies the config.
+func (c *Command) configure() error {
+	if err := resetFlags(c.Command); err != nil {
+		return err
+	}
+
+	if c.config.In != nil {
+		c.SetIn(c.config.In)
+	}
+	if c.config.Out != nil {
+		c.SetOut(c.config.Out)
+		output.Out = c.config.Out
+		pterm.SetDefaultOutput(c.config.Out)
+	}
+	if c.config.Err != nil {
+		c.SetErr(c.config.Err)
+	}
+
+	flags := map[string]string{}
+	if c.config.Dir != "" {
+		flags["cwd"] = c.config.Dir
+	}
+	if c.config.CI {
+		flags["ci"] = strconv.FormatBool(c.config.CI)
+	}
+	if c.config.Offline {
+		flags["offline"] = strconv.FormatBool(c.config.Offline)
+	}
+	for name, value := range flags {
+		if err := c.PersistentFlags().Set(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resetFlags sets the flags of c and its subcommands back to their defaults, the flags are bound to
+// package variables that would otherwise keep the values of the previous command.
+func resetFlags(c *cobra.Command) error {
+	var err error
+	reset := func(f *pflag.Flag) {
+		if err != nil {
+			return
+		}
+		switch v := f.Value.(type) {
+		case interface{ Reset() }:
+			v.Reset()
+		case pflag.SliceValue:
+			// none of the repeated flags have defaults
+			err = v.Replace([]string{})
+		default:
+			err = v.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+
+	c.Flags().VisitAll(reset)
+	c.PersistentFlags().VisitAll(reset)
+	for _, sub := range c.Commands() {
+		if err == nil {
+			err = resetFlags(sub)
+		}
+	}
+	return err
+}
+
+// ExitCode is the code the nitric binary would exit with after the error returned by Execute.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	exitErr := &utils.ExitError{Err: err, Code: 1}
+	errors.As(err, &exitErr)
+	return exitErr.Code
+}
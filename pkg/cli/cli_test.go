@@ -0,0 +1,96 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/pflagext"
+)
+
+func TestNew(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitric-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	root, err := New(Config{Out: out, CI: true, Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root.SetArgs([]string{"version"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Nitric CLI:") {
+		t.Errorf("output = %q, want the version", out.String())
+	}
+	if got, _ := os.Getwd(); got != wd {
+		t.Errorf("working directory = %s, want %s", got, wd)
+	}
+
+	root.SetArgs([]string{"stack", "diff", "only-one"})
+	if err := root.Execute(); ExitCode(err) != 1 {
+		t.Errorf("ExitCode() = %d, want 1", ExitCode(err))
+	}
+}
+
+func TestResetFlags(t *testing.T) {
+	var (
+		yes    bool
+		logs   []string
+		env    map[string]string
+		format string
+	)
+	root := &cobra.Command{Use: "root"}
+	root.PersistentFlags().BoolVarP(&yes, "yes", "y", false, "")
+	sub := &cobra.Command{Use: "sub", Run: func(cmd *cobra.Command, args []string) {}}
+	sub.Flags().StringSliceVar(&logs, "logs", nil, "")
+	sub.Flags().Var(pflagext.NewStringMapVar(&env, nil), "env", "")
+	if err := pflagext.AddStringEnumVarP(sub, &format, "output", "o", []string{"json", "yaml"}, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	root.AddCommand(sub)
+
+	root.SetArgs([]string{"sub", "-y", "--logs", "a,b", "--env", "A=1", "-o", "yaml"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if err := resetFlags(root); err != nil {
+		t.Fatalf("resetFlags() error = %v", err)
+	}
+
+	if yes || len(logs) != 0 || len(env) != 0 || format != "" {
+		t.Errorf("after resetFlags() yes = %v, logs = %v, env = %v, output = %q, want the defaults", yes, logs, env, format)
+	}
+	if sub.Flags().Changed("logs") {
+		t.Error("after resetFlags() --logs is changed")
+	}
+}
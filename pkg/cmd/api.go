@@ -0,0 +1,147 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/joho/godotenv"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/apiclient"
+	"github.com/nitrictech/cli/pkg/codeconfig"
+	"github.com/nitrictech/cli/pkg/i18n"
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/run"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/tasklet"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var (
+	clientLang string
+	clientDir  string
+)
+
+var apiCmd = &cobra.Command{
+	Use:   "api",
+	Short: "Work with the APIs of a project",
+	Long:  `Work with the APIs of a project.`,
+}
+
+var apiClientCmd = &cobra.Command{
+	Use:   "client [-s stack]",
+	Short: "Generate typed clients for the project's APIs",
+	Long: `Generate typed clients for the project's APIs, for use by frontends.
+
+The clients call the APIs of 'nitric run' unless a deployed stack is chosen with -s.
+To regenerate the clients each time a stack is deployed, add apiClient to the stack:
+
+  apiClient:
+    lang: ts
+    dir: web/src/api`,
+	Example: `nitric api client
+
+nitric api client --lang ts --dir web/src/api -s aws`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var s *stack.Config
+		var err error
+		if stack.OptionsChosen() {
+			s, err = stack.ConfigFromOptions()
+			if err != nil {
+				return err
+			}
+		}
+
+		config, err := project.ConfigFromFile(s)
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.FromConfig(config)
+		if err != nil {
+			return err
+		}
+
+		if s != nil {
+			if err := proj.FilterFunctions(s); err != nil {
+				return err
+			}
+		}
+
+		envFiles := utils.FilesExisting(".env")
+		envMap := map[string]string{}
+		if len(envFiles) > 0 {
+			envMap, err = godotenv.Read(envFiles...)
+			if err != nil {
+				return err
+			}
+		}
+
+		codeAsConfig := tasklet.Runner{
+			StartMsg: i18n.T("gather.start"),
+			Runner: func(_ output.Progress) error {
+				proj, err = codeconfig.Populate(proj, envMap)
+				return err
+			},
+			StopMsg: i18n.T("gather.stop"),
+		}
+		if err := tasklet.Run(codeAsConfig, tasklet.Opts{}); err != nil {
+			return err
+		}
+
+		endpoints := map[string]string{}
+		if s != nil {
+			p, err := chosenProvider()
+			if err != nil {
+				return err
+			}
+			d, err := p.Deployment()
+			if err != nil {
+				return err
+			}
+			endpoints = d.ApiEndpoints
+		} else {
+			status := run.NewLocalServices(proj).Status()
+			for name := range proj.ApiDocs {
+				endpoints[name] = status.ApiEndpoint(name)
+			}
+		}
+
+		files, err := apiclient.Generate(clientLang, clientDir, proj.ApiDocs, endpoints)
+		if err != nil {
+			return err
+		}
+
+		for _, f := range files {
+			pterm.Success.Println("Generated", f)
+		}
+		return nil
+	},
+	Args: cobra.ExactArgs(0),
+}
+
+func apiCommand() *cobra.Command {
+	apiClientCmd.Flags().StringVar(&clientLang, "lang", "ts", "the language of the clients")
+	apiClientCmd.Flags().StringVar(&clientDir, "dir", "apiclient", "the directory the clients are written to")
+	cobra.CheckErr(apiClientCmd.RegisterFlagCompletionFunc("lang", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return apiclient.Languages(), cobra.ShellCompDirectiveDefault
+	}))
+	cobra.CheckErr(stack.AddOptionalOptions(apiClientCmd))
+	apiCmd.AddCommand(apiClientCmd)
+	return apiCmd
+}
@@ -0,0 +1,174 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/run"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+// bucketStore returns the buckets of the locally running project, or of the deployed stack when one is chosen.
+func bucketStore() (types.BucketStore, error) {
+	if stack.OptionsChosen() {
+		p, err := chosenProvider()
+		if err != nil {
+			return nil, err
+		}
+		return p.Buckets()
+	}
+
+	status, err := localStatus()
+	if err != nil {
+		return nil, err
+	}
+	return run.NewLocalBuckets(status)
+}
+
+// bucketLocation splits bucket:key, ok is false for local paths.
+func bucketLocation(arg string) (bucket string, key string, ok bool) {
+	if filepath.VolumeName(arg) != "" {
+		return "", "", false
+	}
+	parts := strings.SplitN(arg, ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+var bucketsCmd = &cobra.Command{
+	Use:   "buckets",
+	Short: "Work with the files of a project's buckets",
+	Long: `Work with the files of a project's buckets.
+
+The buckets of 'nitric run' are used unless a deployed stack is chosen with -s,
+deployed buckets are found from the stack, so use the bucket's name in the project.`,
+}
+
+var bucketsLsCmd = &cobra.Command{
+	Use:   "ls [bucket] [prefix] [-s stack]",
+	Short: "List the files in a bucket",
+	Long:  `List the files in a bucket, optionally only those whose key starts with the prefix.`,
+	Example: `nitric buckets ls images
+
+nitric buckets ls images thumbnails/ -s aws`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefix := ""
+		if len(args) > 1 {
+			prefix = args[1]
+		}
+
+		bs, err := bucketStore()
+		if err != nil {
+			return err
+		}
+
+		files, err := bs.List(args[0], prefix)
+		if err != nil {
+			return err
+		}
+
+		output.Print(files)
+		return nil
+	},
+	Args: cobra.RangeArgs(1, 2),
+}
+
+var bucketsCpCmd = &cobra.Command{
+	Use:   "cp [source] [destination] [-s stack]",
+	Short: "Copy a file to or from a bucket",
+	Long: `Copy a file to or from a bucket.
+
+Files in buckets are written as bucket:key, when the key is empty or ends with / the
+name of the local file is added to it. Downloading to - writes the file to stdout.`,
+	Example: `nitric buckets cp ./logo.png images:
+
+nitric buckets cp images:thumbnails/logo.png . -s gcp
+
+nitric buckets cp images:report.csv - | head`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		srcBucket, srcKey, srcRemote := bucketLocation(args[0])
+		dstBucket, dstKey, dstRemote := bucketLocation(args[1])
+		if srcRemote == dstRemote {
+			return errors.New("copy between a local file and a bucket, written as bucket:key")
+		}
+
+		bs, err := bucketStore()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case srcRemote:
+			dst := args[1]
+			if fi, err := os.Stat(dst); err == nil && fi.IsDir() {
+				dst = filepath.Join(dst, path.Base(srcKey))
+			}
+
+			if dst == "-" {
+				return bs.Read(srcBucket, srcKey, cmd.OutOrStdout())
+			}
+
+			f, err := os.Create(dst)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			if err := bs.Read(srcBucket, srcKey, f); err != nil {
+				return err
+			}
+			pterm.Success.Printf("Copied %s to %s\n", args[0], dst)
+		default:
+			if dstKey == "" || strings.HasSuffix(dstKey, "/") {
+				dstKey += filepath.Base(args[0])
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			if err := bs.Write(dstBucket, dstKey, f); err != nil {
+				return err
+			}
+			pterm.Success.Printf("Copied %s to %s:%s\n", args[0], dstBucket, dstKey)
+		}
+		return nil
+	},
+	Args: cobra.ExactArgs(2),
+}
+
+func bucketsCommand() *cobra.Command {
+	for _, c := range []*cobra.Command{bucketsLsCmd, bucketsCpCmd} {
+		cobra.CheckErr(stack.AddOptionalOptions(c))
+		bucketsCmd.AddCommand(c)
+	}
+	return bucketsCmd
+}
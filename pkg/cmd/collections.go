@@ -0,0 +1,182 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/pflagext"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/run"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var (
+	queryWhere map[string]string
+	queryLimit int
+)
+
+// documentStore returns the collections of the locally running project, or of the deployed stack when one is chosen.
+func documentStore() (types.DocumentStore, error) {
+	if stack.OptionsChosen() {
+		p, err := chosenProvider()
+		if err != nil {
+			return nil, err
+		}
+		return p.Documents()
+	}
+
+	status, err := localStatus()
+	if err != nil {
+		return nil, err
+	}
+	return run.NewLocalDocuments(status.MembraneAddress), nil
+}
+
+// parseWhere reads the field=value filters, values are JSON when they parse as it (e.g. 3 or true) and strings otherwise.
+func parseWhere(filters map[string]string) map[string]interface{} {
+	where := map[string]interface{}{}
+	for field, value := range filters {
+		var v interface{}
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			v = value
+		}
+		where[field] = v
+	}
+	return where
+}
+
+var collectionsCmd = &cobra.Command{
+	Use:   "collections",
+	Short: "Work with the documents of a project's collections",
+	Long: `Work with the documents of a project's collections.
+
+The documents of 'nitric run' are used unless a deployed stack is chosen with -s.`,
+}
+
+var collectionsQueryCmd = &cobra.Command{
+	Use:   "query [collection] [-s stack]",
+	Short: "List the documents of a collection",
+	Long:  `List the documents of a collection, optionally only those whose fields equal the given values.`,
+	Example: `nitric collections query orders
+
+nitric collections query orders --where status=pending --where total=20 --limit 10 -s aws`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ds, err := documentStore()
+		if err != nil {
+			return err
+		}
+
+		docs, err := ds.Query(args[0], parseWhere(queryWhere), queryLimit)
+		if err != nil {
+			return err
+		}
+
+		output.Print(docs)
+		return nil
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+var collectionsGetCmd = &cobra.Command{
+	Use:     "get [collection] [id] [-s stack]",
+	Short:   "Print a document",
+	Long:    `Print a document of a collection.`,
+	Example: `nitric collections get orders 1234 -s aws -o json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ds, err := documentStore()
+		if err != nil {
+			return err
+		}
+
+		doc, err := ds.Get(args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		output.Print(doc)
+		return nil
+	},
+	Args: cobra.ExactArgs(2),
+}
+
+var collectionsPutCmd = &cobra.Command{
+	Use:   "put [collection] [id] [content] [-s stack]",
+	Short: "Create or replace a document",
+	Long: `Create or replace a document of a collection.
+
+The content is a JSON object given as an argument, read from a file with --file or from stdin.`,
+	Example: `nitric collections put orders 1234 '{"status": "shipped"}'
+
+nitric collections put orders 1234 -f order.json -s gcp`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		content, err := utils.ReadPayload(args[2:], payloadFile, cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+
+		ds, err := documentStore()
+		if err != nil {
+			return err
+		}
+
+		if err := ds.Put(args[0], args[1], content); err != nil {
+			return err
+		}
+		pterm.Success.Printf("Put %s/%s\n", args[0], args[1])
+		return nil
+	},
+	Args: cobra.RangeArgs(2, 3),
+}
+
+var collectionsDeleteCmd = &cobra.Command{
+	Use:     "delete [collection] [id] [-s stack]",
+	Short:   "Delete a document",
+	Long:    `Delete a document of a collection.`,
+	Example: `nitric collections delete orders 1234`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ds, err := documentStore()
+		if err != nil {
+			return err
+		}
+
+		if err := ds.Delete(args[0], args[1]); err != nil {
+			return err
+		}
+		pterm.Success.Printf("Deleted %s/%s\n", args[0], args[1])
+		return nil
+	},
+	Args:    cobra.ExactArgs(2),
+	Aliases: []string{"rm"},
+}
+
+func collectionsCommand() *cobra.Command {
+	cobra.CheckErr(pflagext.AddStringMapVarP(collectionsQueryCmd, &queryWhere, "where", "", nil, nil, "only documents where field=value, can be repeated"))
+	collectionsQueryCmd.Flags().IntVar(&queryLimit, "limit", 0, "return at most this many documents")
+	collectionsPutCmd.Flags().StringVarP(&payloadFile, "file", "f", "", "read the content from this file, - for stdin")
+
+	for _, c := range []*cobra.Command{collectionsQueryCmd, collectionsGetCmd, collectionsPutCmd, collectionsDeleteCmd} {
+		cobra.CheckErr(stack.AddOptionalOptions(c))
+		collectionsCmd.AddCommand(c)
+	}
+	return collectionsCmd
+}
@@ -0,0 +1,96 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var confirmDiscover bool
+
+var discoverCmd = &cobra.Command{
+	Use:   "discover",
+	Short: "Find handlers that use the nitric SDK and add them to nitric.yaml",
+	Long: `Find handlers that use the nitric SDK and add them to nitric.yaml.
+
+The project is scanned for TypeScript, JavaScript, Python and Go (main package) files
+importing the nitric SDK, the ones not matched by the existing handler globs are proposed.`,
+	Example: `nitric discover
+
+# To add the handlers without being prompted, use -y
+nitric discover -y`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := project.RawConfigFromFile()
+		if err != nil {
+			return err
+		}
+
+		found, err := project.DiscoverHandlers(config.Dir)
+		if err != nil {
+			return err
+		}
+
+		matched := map[string]bool{}
+		for _, h := range config.Handlers {
+			files, err := utils.GlobInDir(config.Dir, h)
+			if err != nil {
+				return err
+			}
+			for _, f := range files {
+				matched[f] = true
+			}
+		}
+
+		proposed := []string{}
+		for _, f := range found {
+			if !matched[f] {
+				proposed = append(proposed, f)
+			}
+		}
+
+		if len(proposed) == 0 {
+			pterm.Info.Println("All the handlers found are already in nitric.yaml")
+			return nil
+		}
+
+		output.Print(proposed)
+
+		if !confirmDiscover {
+			err = survey.AskOne(&survey.Confirm{
+				Message: "Add these handlers to nitric.yaml?",
+			}, &confirmDiscover)
+			if err != nil {
+				return err
+			}
+		}
+
+		if confirmDiscover {
+			config.Handlers = append(config.Handlers, proposed...)
+			if err := config.ToFile(); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Args: cobra.ExactArgs(0),
+}
@@ -0,0 +1,147 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/docs"
+	"github.com/nitrictech/cli/pkg/pflagext"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+var (
+	docsFile      string
+	docsFormat    string
+	docsEndpoints bool
+)
+
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate documentation of a project",
+}
+
+var docsResourcesCmd = &cobra.Command{
+	Use:   "resources [-s stack] [--format markdown|html] [-f file]",
+	Short: "Generate a report of the project's resources and the endpoints of its stacks",
+	Long: `Generate a report of the project's resources and the endpoints of its stacks.
+
+The report lists the functions with what triggers them, the api routes, topics, schedules, buckets,
+queues, collections, secrets and permissions gathered from the code, followed by the endpoints each
+stack is deployed to, as markdown or a standalone HTML page for internal docs portals.
+With -s only that stack is included, use --endpoints=false to leave the stacks out.`,
+	Example: `nitric docs resources -f docs/resources.md
+
+nitric docs resources --format html -f resources.html -s prod`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var s *stack.Config
+		var err error
+		if stack.OptionsChosen() {
+			s, err = stack.ConfigFromOptions()
+			if err != nil {
+				return err
+			}
+		}
+
+		proj, err := gatherProject(s)
+		if err != nil {
+			return err
+		}
+
+		envs := []docs.Environment{}
+		if docsEndpoints {
+			envs, err = docsEnvironments(proj, s)
+			if err != nil {
+				return err
+			}
+		}
+
+		buf := &bytes.Buffer{}
+		if err := docs.NewReport(proj.Spec(), envs).Render(docsFormat, buf); err != nil {
+			return err
+		}
+
+		if docsFile == "" {
+			fmt.Fprint(cmd.OutOrStdout(), buf.String())
+			return nil
+		}
+		if err := ioutil.WriteFile(docsFile, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+		pterm.Success.Println("Wrote the report to", docsFile)
+		return nil
+	},
+	Args: cobra.ExactArgs(0),
+}
+
+// docsEnvironments returns the endpoints of the chosen stack, or of every stack of the project, with the
+// reason for the stacks that can't be read, e.g. because they are not deployed.
+func docsEnvironments(proj *project.Project, chosen *stack.Config) ([]docs.Environment, error) {
+	stacks := []*stack.Config{}
+	if chosen != nil {
+		stacks = append(stacks, chosen)
+	} else {
+		names, err := stack.Names()
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			s, err := stack.ConfigFromName(name)
+			if err != nil {
+				return nil, err
+			}
+			stacks = append(stacks, s)
+		}
+	}
+
+	envs := []docs.Environment{}
+	for _, s := range stacks {
+		target := s.Provider
+		if s.Region != "" {
+			target += "/" + s.Region
+		}
+
+		p, err := provider.NewProvider(proj, s, map[string]string{})
+		if err != nil {
+			envs = append(envs, docs.Environment{Stack: s.Name, Target: target, Error: err.Error()})
+			continue
+		}
+		d, err := p.Deployment()
+		if err != nil {
+			envs = append(envs, docs.Environment{Stack: s.Name, Target: target, Error: "The endpoints could not be read, the stack may not be deployed."})
+			continue
+		}
+		envs = append(envs, docs.NewEnvironment(s.Name, target, d))
+	}
+	return envs, nil
+}
+
+func docsCommand() *cobra.Command {
+	cobra.CheckErr(stack.AddOptionalOptions(docsResourcesCmd))
+	docsResourcesCmd.Flags().StringVarP(&docsFile, "file", "f", "", "write the report to this file rather than printing it")
+	docsResourcesCmd.Flags().Var(pflagext.NewStringEnumVar(&docsFormat, docs.Formats, "markdown"), "format", "the format of the report")
+	docsResourcesCmd.Flags().BoolVar(&docsEndpoints, "endpoints", true, "include the endpoints of the project's stacks")
+	docsCmd.AddCommand(docsResourcesCmd)
+	return docsCmd
+}
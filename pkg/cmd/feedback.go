@@ -32,7 +32,7 @@ var feedbackCmd = &cobra.Command{
 	Short:   "Provide feedback on your experience with nitric",
 	Long:    `Provide feedback on your experience with nitric.`,
 	Example: `nitric feedback`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		answers := struct {
 			Repo  string
 			Kind  string
@@ -41,10 +41,14 @@ var feedbackCmd = &cobra.Command{
 		}{}
 
 		d, err := ghissue.Gather()
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 
 		diag, err := yaml.Marshal(d)
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 
 		qs := []*survey.Question{
 			{
@@ -78,9 +82,12 @@ var feedbackCmd = &cobra.Command{
 			},
 		}
 		err = survey.Ask(qs, &answers)
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 
 		pterm.Info.Println("Please create a github issue by clicking on the link below")
-		fmt.Println(ghissue.IssueLink(answers.Repo, answers.Kind, answers.Title, answers.Body))
+		fmt.Fprintln(cmd.OutOrStdout(), ghissue.IssueLink(answers.Repo, answers.Kind, answers.Title, answers.Body))
+		return nil
 	},
 }
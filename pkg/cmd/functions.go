@@ -0,0 +1,126 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/codeconfig"
+	"github.com/nitrictech/cli/pkg/i18n"
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/tasklet"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var functionsCmd = &cobra.Command{
+	Use:   "functions",
+	Short: "Inspect the functions of a project",
+}
+
+var functionsListCmd = &cobra.Command{
+	Use:   "list [-s stack]",
+	Short: "List the functions found in the project with their triggers and resources",
+	Long: `List the functions found in the project with their triggers and resources.
+
+The handlers are found with the globs of nitric.yaml and run to gather their configuration, then each
+function is printed with the api routes it serves, the topics it is subscribed to, the schedules and
+bucket notifications that trigger it and the resources it uses. Use it to check the handler globs
+matched the functions you expect. With -s only the functions the stack deploys are listed.`,
+	Example: `nitric functions list
+
+nitric functions list -s aws -o yaml`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var s *stack.Config
+		var err error
+		if stack.OptionsChosen() {
+			s, err = stack.ConfigFromOptions()
+			if err != nil {
+				return err
+			}
+		}
+
+		config, err := project.ConfigFromFile(s)
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.FromConfig(config)
+		if err != nil {
+			return err
+		}
+
+		envFiles := utils.FilesExisting(".env")
+		envMap := map[string]string{}
+		if len(envFiles) > 0 {
+			envMap, err = godotenv.Read(envFiles...)
+			if err != nil {
+				return err
+			}
+		}
+
+		codeAsConfig := tasklet.Runner{
+			StartMsg: i18n.T("gather.start"),
+			Runner: func(_ output.Progress) error {
+				proj, err = codeconfig.Populate(proj, envMap)
+				return err
+			},
+			StopMsg: i18n.T("gather.stop"),
+		}
+		if err := tasklet.Run(codeAsConfig, tasklet.Opts{}); err != nil {
+			return err
+		}
+
+		if s != nil {
+			if err := proj.FilterFunctions(s); err != nil {
+				return err
+			}
+		}
+
+		summaries := proj.Spec().FunctionSummaries()
+		if output.OutputTypeFlag.String() != "table" {
+			output.Print(summaries)
+			return nil
+		}
+
+		rows := [][]string{{"Function", "Source", "Routes", "Subscriptions", "Schedules", "Buckets", "Resources"}}
+		for _, f := range summaries {
+			rows = append(rows, []string{
+				f.Name,
+				f.Source,
+				strings.Join(f.Routes, ", "),
+				strings.Join(f.Subscriptions, ", "),
+				strings.Join(f.Schedules, ", "),
+				strings.Join(f.Buckets, ", "),
+				strings.Join(f.Resources, ", "),
+			})
+		}
+		return pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(rows).Render()
+	},
+	Args: cobra.ExactArgs(0),
+}
+
+func functionsCommand() *cobra.Command {
+	cobra.CheckErr(stack.AddOptionalOptions(functionsListCmd))
+	functionsCmd.AddCommand(functionsListCmd)
+	return functionsCmd
+}
@@ -0,0 +1,127 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/pflagext"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+var (
+	iamTarget string
+	iamCreate bool
+)
+
+var iamCmd = &cobra.Command{
+	Use:   "iam",
+	Short: "Work with the cloud identities used to deploy a project",
+}
+
+var iamBootstrapCmd = &cobra.Command{
+	Use:   "bootstrap -t target [--create]",
+	Short: "Generate the least privilege role that deploys the project's stacks",
+	Long: `Generate the least privilege role that deploys the project's stacks.
+
+The role has only the permissions the target's provider needs to deploy stacks, so
+stacks can be deployed from CI without owner credentials while developers keep their own.
+The policy is printed with the commands that create it, --create creates the role with the
+current credentials where the target supports it.`,
+	Example: `nitric iam bootstrap -t gcp
+
+nitric iam bootstrap -t aws --create
+
+nitric iam bootstrap -t azure -o json > deployer.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := project.ConfigFromFile(nil)
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.FromConfig(config)
+		if err != nil {
+			return err
+		}
+
+		p, err := provider.NewProvider(proj, &stack.Config{Name: "deployer", Provider: iamTarget}, map[string]string{})
+		if err != nil {
+			return err
+		}
+
+		role, err := p.Deployer(iamCreate)
+		if err != nil {
+			return err
+		}
+
+		if output.OutputTypeFlag.String() != "table" {
+			output.Print(role)
+			return nil
+		}
+
+		if iamCreate {
+			pterm.Success.Printf("Created %s %s\n", role.Name, role.ID)
+			return nil
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), role.Policy)
+		for _, i := range role.Instructions {
+			pterm.Info.Println(i)
+		}
+		return nil
+	},
+	Args: cobra.ExactArgs(0),
+}
+
+func iamCommand() *cobra.Command {
+	iamBootstrapCmd.Flags().VarP(pflagext.NewStringEnumVar(&iamTarget, stack.Providers, ""), "target", "t", "the target to bootstrap the deployer role of")
+	cobra.CheckErr(iamBootstrapCmd.MarkFlagRequired("target"))
+	cobra.CheckErr(iamBootstrapCmd.RegisterFlagCompletionFunc("target", completeTargets))
+	iamBootstrapCmd.Flags().BoolVar(&iamCreate, "create", false, "create the role with the current credentials, rather than printing it")
+	iamCmd.AddCommand(iamBootstrapCmd)
+	return iamCmd
+}
+
+// completeTargets completes --target with the targets of the configured stacks, or every target when
+// no stacks are configured yet.
+func completeTargets(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names, err := stack.Names()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	targets := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		s, err := stack.ConfigFromName(name)
+		if err != nil || seen[s.Provider] {
+			continue
+		}
+		targets = append(targets, s.Provider)
+		seen[s.Provider] = true
+	}
+	if len(targets) == 0 {
+		targets = stack.Providers
+	}
+	return targets, cobra.ShellCompDirectiveNoFileComp
+}
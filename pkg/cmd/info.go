@@ -27,9 +27,12 @@ var infoCmd = &cobra.Command{
 	Use:   "info",
 	Short: "Gather information about Nitric and the environment",
 	Long:  `Gather information about Nitric and the environment.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		d, err := ghissue.Gather()
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 		output.Print(d)
+		return nil
 	},
 }
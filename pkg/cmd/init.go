@@ -0,0 +1,106 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/i18n"
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/project"
+)
+
+var (
+	initName    string
+	confirmInit bool
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create nitric.yaml for existing code",
+	Long: `Create nitric.yaml for existing code.
+
+The project is named after the directory unless --name is given, and the handlers
+that use the nitric SDK are found as with 'nitric discover', unless the handler_globs
+setting is set.
+To start a project from a template use 'nitric new' instead.`,
+	Example: `nitric init
+
+# To create nitric.yaml without being prompted, use -y
+nitric init --name my-project -y`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(filepath.Join(wd, "nitric.yaml")); err == nil {
+			return errors.New("nitric.yaml already exists, use 'nitric discover' to add handlers to it")
+		}
+
+		config, err := project.NewConfig(wd)
+		if err != nil {
+			return err
+		}
+
+		if initName != "" {
+			if err := validateName(initName); err != nil {
+				return err
+			}
+			config.Name = initName
+		} else if !confirmInit {
+			err = survey.AskOne(&survey.Input{
+				Message: i18n.T("project.name.prompt"),
+				Default: config.Name,
+			}, &config.Name, survey.WithValidator(validateName))
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(config.Handlers) == 0 {
+			pterm.Warning.Println("No handlers using the nitric SDK were found, add them to nitric.yaml once they are written")
+		} else {
+			output.Print(config.Handlers)
+		}
+
+		if !confirmInit {
+			err = survey.AskOne(&survey.Confirm{
+				Message: "Create nitric.yaml?",
+				Default: true,
+			}, &confirmInit)
+			if err != nil {
+				return err
+			}
+		}
+
+		if confirmInit {
+			if err := config.ToFile(); err != nil {
+				return err
+			}
+			pterm.Success.Println("Created nitric.yaml")
+		}
+		return nil
+	},
+	Args: cobra.ExactArgs(0),
+}
@@ -0,0 +1,171 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"io"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/run"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var payloadFile string
+
+// chosenProvider returns the provider of the stack chosen with --stack.
+func chosenProvider() (types.Provider, error) {
+	s, err := stack.ConfigFromOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := project.ConfigFromFile(s)
+	if err != nil {
+		return nil, err
+	}
+
+	proj, err := project.FromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.NewProvider(proj, s, map[string]string{})
+}
+
+// localStatus returns the services of the project running locally.
+func localStatus() (*run.LocalServicesStatus, error) {
+	config, err := project.ConfigFromFile(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	proj := project.New(config)
+	if !run.NewLocalServices(proj).Running() {
+		return nil, errors.New("the project is not running locally, start it with 'nitric run' or choose a deployed stack with -s")
+	}
+
+	return run.ReadLocalStatus(proj)
+}
+
+// sendMessage sends the payload to the locally running project, or to the deployed stack when one is chosen.
+func sendMessage(in io.Reader, args []string, local func(address string, payload map[string]interface{}) (string, error), deployed func(p types.Provider, payload map[string]interface{}) (string, error)) error {
+	payload, err := utils.ReadPayload(args[1:], payloadFile, in)
+	if err != nil {
+		return err
+	}
+
+	var id string
+	if stack.OptionsChosen() {
+		p, err := chosenProvider()
+		if err != nil {
+			return err
+		}
+		id, err = deployed(p, payload)
+		if err != nil {
+			return err
+		}
+	} else {
+		status, err := localStatus()
+		if err != nil {
+			return err
+		}
+		id, err = local(status.MembraneAddress, payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	pterm.Success.Printf("Sent message %s to %s\n", id, args[0])
+	return nil
+}
+
+var topicsCmd = &cobra.Command{
+	Use:   "topics",
+	Short: "Work with the topics of a project",
+	Long:  `Work with the topics of a project.`,
+}
+
+var topicsPublishCmd = &cobra.Command{
+	Use:   "publish [topic] [payload] [-s stack]",
+	Short: "Publish a message to a topic",
+	Long: `Publish a message to a topic, for manually testing its subscribers.
+
+The payload is a JSON object given as an argument, read from a file with --file or from stdin.
+The message goes to 'nitric run' unless a deployed stack is chosen with -s.`,
+	Example: `nitric topics publish sales '{"item": "pen"}'
+
+nitric topics publish sales -f order.json -s aws
+
+echo '{"item": "pen"}' | nitric topics publish sales`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return sendMessage(cmd.InOrStdin(), args,
+			func(address string, payload map[string]interface{}) (string, error) {
+				return run.Publish(address, args[0], payload)
+			},
+			func(p types.Provider, payload map[string]interface{}) (string, error) {
+				return p.Publish(args[0], payload)
+			})
+	},
+	Args: cobra.RangeArgs(1, 2),
+}
+
+var queuesCmd = &cobra.Command{
+	Use:   "queues",
+	Short: "Work with the queues of a project",
+	Long:  `Work with the queues of a project.`,
+}
+
+var queuesSendCmd = &cobra.Command{
+	Use:   "send [queue] [payload] [-s stack]",
+	Short: "Send a task to a queue",
+	Long: `Send a task to a queue, for manually testing the functions that receive from it.
+
+The payload is a JSON object given as an argument, read from a file with --file or from stdin.
+The task goes to 'nitric run' unless a deployed stack is chosen with -s.`,
+	Example: `nitric queues send checkout '{"order": 42}'
+
+nitric queues send checkout -f task.json -s gcp`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return sendMessage(cmd.InOrStdin(), args,
+			func(address string, payload map[string]interface{}) (string, error) {
+				return run.Send(address, args[0], payload)
+			},
+			func(p types.Provider, payload map[string]interface{}) (string, error) {
+				return p.Send(args[0], payload)
+			})
+	},
+	Args: cobra.RangeArgs(1, 2),
+}
+
+func messagesCommands() []*cobra.Command {
+	topicsPublishCmd.Flags().StringVarP(&payloadFile, "file", "f", "", "read the payload from this file, - for stdin")
+	cobra.CheckErr(stack.AddOptionalOptions(topicsPublishCmd))
+	topicsCmd.AddCommand(topicsPublishCmd)
+
+	queuesSendCmd.Flags().StringVarP(&payloadFile, "file", "f", "", "read the payload from this file, - for stdin")
+	cobra.CheckErr(stack.AddOptionalOptions(queuesSendCmd))
+	queuesCmd.AddCommand(queuesSendCmd)
+
+	return []*cobra.Command{topicsCmd, queuesCmd}
+}
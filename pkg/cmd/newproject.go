@@ -28,16 +28,18 @@ import (
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 
+	"github.com/nitrictech/cli/pkg/i18n"
 	"github.com/nitrictech/cli/pkg/project"
 	"github.com/nitrictech/cli/pkg/templates"
 )
 
 var (
 	force         bool
+	withExamples  bool
 	nameRegex     = regexp.MustCompile(`^([a-zA-Z0-9-])*$`)
 	projectNameQu = survey.Question{
 		Name:     "projectName",
-		Prompt:   &survey.Input{Message: "What is the name of the project?"},
+		Prompt:   &survey.Input{Message: i18n.T("project.name.prompt")},
 		Validate: validateName,
 	}
 	templateNameQu = survey.Question{
@@ -53,8 +55,11 @@ var newProjectCmd = &cobra.Command{
 nitric new
 
 # For a non-interactive command use the arguments.
-nitric new hello-world "official/TypeScript - Starter" "functions/*.ts" `,
-	Run: func(cmd *cobra.Command, args []string) {
+nitric new hello-world "official/TypeScript - Starter" "functions/*.ts"
+
+# To include example handlers for apis, topics, schedules and buckets
+nitric new hello-world "official/TypeScript - Starter" "functions/*.ts" --examples`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		answers := struct {
 			ProjectName  string
 			TemplateName string
@@ -63,7 +68,9 @@ nitric new hello-world "official/TypeScript - Starter" "functions/*.ts" `,
 
 		downloadr := templates.NewDownloader()
 		dirs, err := downloadr.Names()
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 
 		templateNameQu.Prompt = &survey.Select{
 			Message: "Choose a template:",
@@ -129,11 +136,24 @@ nitric new hello-world "official/TypeScript - Starter" "functions/*.ts" `,
 
 		if len(qs) > 0 {
 			err = survey.Ask(qs, &answers)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
+
+			if !cmd.Flags().Changed("examples") {
+				err = survey.AskOne(&survey.Confirm{
+					Message: "Add example handlers using an api, topic, schedule, bucket and queue?",
+				}, &withExamples)
+				if err != nil {
+					return err
+				}
+			}
 		}
 
 		cd, err := filepath.Abs(".")
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 		p := project.Config{
 			Dir:      path.Join(cd, answers.ProjectName),
 			Name:     answers.ProjectName,
@@ -141,9 +161,21 @@ nitric new hello-world "official/TypeScript - Starter" "functions/*.ts" `,
 		}
 
 		err = downloadr.DownloadDirectoryContents(answers.TemplateName, p.Dir, force)
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
+
+		if withExamples {
+			err = templates.WriteExamples(answers.Handlers, p.Dir)
+			if err != nil {
+				return err
+			}
+		}
 		err = p.ToFile()
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
+		return nil
 	},
 	Args: cobra.MaximumNArgs(3),
 }
@@ -0,0 +1,127 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/codeconfig"
+	"github.com/nitrictech/cli/pkg/i18n"
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/tasklet"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var permissionsCmd = &cobra.Command{
+	Use:   "permissions",
+	Short: "Review the permissions granted to the functions of a project",
+}
+
+var permissionsReportCmd = &cobra.Command{
+	Use:   "report [-s stack]",
+	Short: "Print the permissions the stack grants each function",
+	Long: `Print the permissions the stack grants each function.
+
+The policies gathered from the code are mapped to the provider's permissions and roles, so what each
+function is allowed to do can be reviewed before the stack is deployed, or audited after. Only the
+functions the stack deploys are included. On azure every function is assigned the roles on the
+stack's resource group, whatever its policies.`,
+	Example: `nitric permissions report -s aws
+
+nitric permissions report -s gcp -o json > permissions.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := stack.ConfigFromOptions()
+		if err != nil {
+			return err
+		}
+
+		config, err := project.ConfigFromFile(s)
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.FromConfig(config)
+		if err != nil {
+			return err
+		}
+
+		envFiles := utils.FilesExisting(".env", ".env.production")
+		envMap := map[string]string{}
+		if len(envFiles) > 0 {
+			envMap, err = godotenv.Read(envFiles...)
+			if err != nil {
+				return err
+			}
+		}
+
+		codeAsConfig := tasklet.Runner{
+			StartMsg: i18n.T("gather.start"),
+			Runner: func(_ output.Progress) error {
+				proj, err = codeconfig.Populate(proj, envMap)
+				return err
+			},
+			StopMsg: i18n.T("gather.stop"),
+		}
+		if err := tasklet.Run(codeAsConfig, tasklet.Opts{}); err != nil {
+			return err
+		}
+
+		if err := proj.FilterFunctions(s); err != nil {
+			return err
+		}
+
+		p, err := provider.NewProvider(proj, s, envMap)
+		if err != nil {
+			return err
+		}
+
+		perms, err := p.Permissions()
+		if err != nil {
+			return err
+		}
+
+		if output.OutputTypeFlag.String() != "table" {
+			output.Print(perms)
+			return nil
+		}
+
+		if len(perms) == 0 {
+			pterm.Info.Println("The functions are granted no permissions")
+			return nil
+		}
+
+		rows := [][]string{{"Function", "Resource", "Actions", "Grants"}}
+		for _, perm := range perms {
+			rows = append(rows, []string{perm.Function, perm.Resource, strings.Join(perm.Actions, ", "), strings.Join(perm.Grants, ", ")})
+		}
+		return pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(rows).Render()
+	},
+	Args: cobra.ExactArgs(0),
+}
+
+func permissionsCommand() *cobra.Command {
+	cobra.CheckErr(stack.AddOptions(permissionsReportCmd, false))
+	permissionsCmd.AddCommand(permissionsReportCmd)
+	return permissionsCmd
+}
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -28,6 +29,8 @@ import (
 	cmdstack "github.com/nitrictech/cli/pkg/cmd/stack"
 	"github.com/nitrictech/cli/pkg/ghissue"
 	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/utils"
 )
 
 const usageTemplate = `Nitric - The fastest way to build serverless apps
@@ -44,12 +47,24 @@ func usageString() string {
 	return fmt.Sprintf(usageTemplate, strings.Join(CommonCommandsUsage(), "\n"))
 }
 
+var workDir string
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "nitric",
 	Short: "CLI for Nitric applications",
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		if output.VerboseLevel > 1 {
+	// errors are printed by Execute, usage is only printed for mistakes in the arguments and flags
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		// like 'git -C', everything that follows is relative to the chosen directory
+		if workDir != "" {
+			if err := os.Chdir(workDir); err != nil {
+				return err
+			}
+		}
+		if output.Verbose(output.VerboseDiagnostics) {
 			pterm.EnableDebugMessages()
 		}
 		if output.VerboseLevel == 0 {
@@ -58,6 +73,11 @@ var rootCmd = &cobra.Command{
 		if output.CI {
 			pterm.DisableStyling()
 		}
+		if utils.Offline {
+			// the pulumi cli checks for a newer release on every command
+			return os.Setenv("PULUMI_SKIP_UPDATE_CHECK", "true")
+		}
+		return nil
 	},
 }
 
@@ -71,25 +91,64 @@ func Execute() {
 		}
 	}()
 
-	cobra.CheckErr(rootCmd.Execute())
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(ExitCode(err))
+	}
+}
+
+// RootCommand returns the nitric command with all its subcommands, for running them in process.
+func RootCommand() *cobra.Command {
+	return rootCmd
+}
+
+// ExitCode prints err, unless it has already been shown, and returns the code the cli exits with.
+func ExitCode(err error) int {
+	exitErr := &utils.ExitError{Err: err, Code: 1}
+	errors.As(err, &exitErr)
+	if !exitErr.Reported {
+		pterm.Error.Println(err)
+	}
+	return exitErr.Code
 }
 
 func init() {
-	rootCmd.PersistentFlags().IntVarP(&output.VerboseLevel, "verbose", "v", 1, "set the verbosity of output (larger is more verbose)")
+	rootCmd.PersistentFlags().StringVarP(&workDir, "cwd", "C", "", "run as if nitric was started in this directory")
+	cobra.CheckErr(rootCmd.MarkPersistentFlagDirname("cwd"))
+	rootCmd.PersistentFlags().BoolVar(&project.AutoInit, "auto-init", false, "create nitric.yaml, rather than failing, when the project has none")
+	rootCmd.PersistentFlags().IntVarP(&output.VerboseLevel, "verbose", "v", 1, "set the verbosity of output, 2 adds the pulumi diagnostics and docker build output, 3 adds the debug logs of the providers")
 	rootCmd.PersistentFlags().BoolVar(&output.CI, "ci", false, "CI output mode, disable all output styling")
+	rootCmd.PersistentFlags().BoolVar(&utils.Offline, "offline", utils.Offline, "use cached templates, plugins and base images rather than downloading them, also set by NITRIC_OFFLINE")
 	rootCmd.PersistentFlags().VarP(output.OutputTypeFlag, "output", "o", "output format")
-	err := rootCmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return output.OutputTypeFlag.Allowed, cobra.ShellCompDirectiveDefault
-	})
-	cobra.CheckErr(err)
+	rootCmd.PersistentFlags().BoolVarP(&output.Quiet, "quiet", "q", false, "only print names or IDs, one per line")
+	rootCmd.PersistentFlags().StringVar(&output.SortBy, "sort-by", "", "sort table output by this column")
+	rootCmd.PersistentFlags().StringArrayVar(&output.Filters, "filter", []string{}, "only output rows where column=value, can be repeated")
+	cobra.CheckErr(rootCmd.RegisterFlagCompletionFunc("output", output.OutputTypeFlag.Complete))
 
 	newProjectCmd.Flags().BoolVarP(&force, "force", "f", false, "force project creation, even in non-empty directories.")
+	newProjectCmd.Flags().BoolVar(&withExamples, "examples", false, "add example handlers using an api, topic, schedule, bucket and queue.")
 	rootCmd.AddCommand(newProjectCmd)
 	rootCmd.AddCommand(cmdstack.RootCommand())
 	rootCmd.AddCommand(run.RootCommand())
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(feedbackCmd)
 	rootCmd.AddCommand(infoCmd)
+	rootCmd.AddCommand(verifyInstallCmd)
+	discoverCmd.Flags().BoolVarP(&confirmDiscover, "yes", "y", false, "add the discovered handlers without prompting")
+	rootCmd.AddCommand(discoverCmd)
+	initCmd.Flags().StringVar(&initName, "name", "", "the name of the project, defaults to the directory name")
+	initCmd.Flags().BoolVarP(&confirmInit, "yes", "y", false, "create nitric.yaml without prompting")
+	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(messagesCommands()...)
+	rootCmd.AddCommand(collectionsCommand())
+	rootCmd.AddCommand(bucketsCommand())
+	rootCmd.AddCommand(apiCommand())
+	rootCmd.AddCommand(testCommand())
+	rootCmd.AddCommand(iamCommand())
+	rootCmd.AddCommand(specCommand())
+	rootCmd.AddCommand(docsCommand())
+	rootCmd.AddCommand(permissionsCommand())
+	rootCmd.AddCommand(functionsCommand())
+	rootCmd.AddCommand(secretsCommand())
 	addAlias("stack update", "up", true)
 	addAlias("stack down", "down", true)
 	addAlias("stack list", "list", false)
@@ -104,21 +163,24 @@ func addAlias(from, to string, commonCommand bool) {
 		cmd.Annotations = map[string]string{}
 	}
 	cmd.Annotations["alias:to"] = to
+	// the alias runs the command directly and shares its flags, so help, completion and exit codes are
+	// the same as the command's
 	alias := &cobra.Command{
-		Annotations: map[string]string{"alias:from": from},
-		Use:         to,
-		Short:       cmd.Short,
-		Long:        cmd.Long,
-		Example:     cmd.Example,
-		Run: func(cmd *cobra.Command, args []string) {
-			newArgs := []string{os.Args[0]}
-			newArgs = append(newArgs, strings.Split(from, " ")...)
-			newArgs = append(newArgs, args...)
-			os.Args = newArgs
-			cobra.CheckErr(rootCmd.Execute())
-		},
-		DisableFlagParsing: true, // the real command will parse the flags
+		Annotations:       map[string]string{"alias:from": from},
+		Use:               strings.Replace(cmd.Use, cmd.Name(), to, 1),
+		Short:             cmd.Short,
+		Long:              cmd.Long,
+		Example:           cmd.Example,
+		Args:              cmd.Args,
+		ValidArgsFunction: cmd.ValidArgsFunction,
+		PreRun:            cmd.PreRun,
+		PreRunE:           cmd.PreRunE,
+		Run:               cmd.Run,
+		RunE:              cmd.RunE,
+		PostRun:           cmd.PostRun,
+		PostRunE:          cmd.PostRunE,
 	}
+	alias.Flags().AddFlagSet(cmd.Flags())
 	if commonCommand {
 		alias.Annotations["commonCommand"] = "yes"
 	}
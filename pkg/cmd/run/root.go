@@ -19,6 +19,7 @@ package run
 import (
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"strings"
@@ -34,6 +35,7 @@ import (
 	"github.com/nitrictech/cli/pkg/build"
 	"github.com/nitrictech/cli/pkg/codeconfig"
 	"github.com/nitrictech/cli/pkg/containerengine"
+	"github.com/nitrictech/cli/pkg/i18n"
 	"github.com/nitrictech/cli/pkg/output"
 	"github.com/nitrictech/cli/pkg/project"
 	"github.com/nitrictech/cli/pkg/run"
@@ -41,15 +43,41 @@ import (
 	"github.com/nitrictech/cli/pkg/utils"
 )
 
-var envFile string
+var (
+	envFile    string
+	platform   string
+	recordFile string
+	replayFile string
+	traces     bool
+	logs       []string
+	attempts   int
+	lease      time.Duration
+)
 
 var runCmd = &cobra.Command{
-	Use:         "run",
-	Short:       "Run your project locally for development and testing",
-	Long:        `Run your project locally for development and testing`,
-	Example:     `nitric run`,
+	Use:   "run",
+	Short: "Run your project locally for development and testing",
+	Long:  `Run your project locally for development and testing`,
+	Example: `nitric run
+
+# Record the api requests and topic messages received during the session
+nitric run --record requests.jsonl
+
+# Replay a recording against a new session
+nitric run --replay requests.jsonl
+
+# View the traces of the requests and messages handled by the functions
+nitric run --traces
+
+# Print the logs of all the functions, or only those of the orders function
+nitric run --logs all
+nitric run --logs orders
+
+# Deliver the queue tasks that aren't completed within a minute, and the topic messages whose subscribers
+# fail, up to 3 times before sending them to their dead letter queue, e.g. orders-dlq for orders
+nitric run --max-attempts 3 --lease 1m`,
 	Annotations: map[string]string{"commonCommand": "yes"},
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		term := make(chan os.Signal, 1)
 		signal.Notify(term, os.Interrupt, syscall.SIGTERM)
 		signal.Notify(term, os.Interrupt, syscall.SIGINT)
@@ -57,49 +85,113 @@ var runCmd = &cobra.Command{
 		// Divert default log output to pterm debug
 		log.SetOutput(output.NewPtermWriter(pterm.Debug))
 
-		config, err := project.ConfigFromFile()
-		cobra.CheckErr(err)
+		// the functions mount the project directory and call back to the membrane on this machine,
+		// neither of which a container engine on another machine can do
+		if host := containerengine.RemoteHost(); host != "" {
+			return fmt.Errorf("nitric run needs a container engine on this machine, %s is remote, switch to a local one with 'docker context use default' or by unsetting DOCKER_HOST", host)
+		}
+
+		config, err := project.ConfigFromFile(nil)
+		if err != nil {
+			return err
+		}
 
 		proj, err := project.FromConfig(config)
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 
 		envFiles := utils.FilesExisting(".env", ".env.development", envFile)
 		envMap := map[string]string{}
 		if len(envFiles) > 0 {
 			envMap, err = godotenv.Read(envFiles...)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 		}
 
 		codeAsConfig := tasklet.Runner{
-			StartMsg: "Gathering configuration from code..",
+			StartMsg: i18n.T("gather.start"),
 			Runner: func(_ output.Progress) error {
 				proj, err = codeconfig.Populate(proj, envMap)
 				return err
 			},
-			StopMsg: "Configuration gathered",
+			StopMsg: i18n.T("gather.stop"),
+		}
+		if err := tasklet.Run(codeAsConfig, tasklet.Opts{}); err != nil {
+			return err
+		}
+		if err := proj.ValidateTriggers(); err != nil {
+			return err
 		}
-		tasklet.MustRun(codeAsConfig, tasklet.Opts{})
 
 		ls := run.NewLocalServices(proj)
 		if ls.Running() {
-			pterm.Error.Println("Only one instance of Nitric can be run locally at a time, please check that you have ended all other instances and try again")
-			os.Exit(2)
+			return utils.NewExitErr(2, errors.New("only one instance of Nitric can be run locally at a time, please check that you have ended all other instances and try again"))
+		}
+
+		var replay []run.RecordedRequest
+		if replayFile != "" {
+			replay, err = run.ReadRecording(replayFile)
+			if err != nil {
+				return err
+			}
+		}
+
+		if recordFile != "" {
+			rf, err := os.Create(recordFile)
+			if err != nil {
+				return err
+			}
+			defer rf.Close()
+
+			ls.Record(run.NewRecorder(rf))
+		}
+
+		if traces {
+			ls.Trace()
+		}
+
+		if attempts > 0 {
+			ls.Redeliver(run.Redelivery{MaxAttempts: attempts, Lease: lease})
 		}
 
 		ce, err := containerengine.Discover()
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
+
+		if platform != "" && platform != containerengine.HostPlatform() {
+			pterm.Warning.Printf("Running %s images on a %s host uses emulation and will be slower\n", platform, containerengine.HostPlatform())
+		}
 
 		logger := ce.Logger(proj.Dir)
-		cobra.CheckErr(logger.Start())
+		var printer *run.LogPrinter
+		if len(logs) > 0 {
+			printer, err = run.NewLogPrinter(proj, logs, cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+
+			if err := logger.Follow(printer.Print); err != nil {
+				pterm.Warning.Println(err)
+				printer = nil
+			}
+		}
+		if err := logger.Start(); err != nil {
+			return err
+		}
 
 		createBaseImage := tasklet.Runner{
 			StartMsg: "Creating Dev Image",
 			Runner: func(_ output.Progress) error {
-				return build.CreateBaseDev(proj)
+				return build.CreateBaseDev(proj, platform)
 			},
 			StopMsg: "Created Dev Image!",
 		}
-		tasklet.MustRun(createBaseImage, tasklet.Opts{Signal: term})
+		if err := tasklet.Run(createBaseImage, tasklet.Opts{Signal: term}); err != nil {
+			return err
+		}
 
 		memerr := make(chan error)
 		pool := run.NewRunProcessPool()
@@ -130,16 +222,16 @@ var runCmd = &cobra.Command{
 			},
 			StopMsg: "Started Local Services!",
 		}
-		tasklet.MustRun(startLocalServices, tasklet.Opts{
-			Signal: term,
-		})
+		if err := tasklet.Run(startLocalServices, tasklet.Opts{Signal: term}); err != nil {
+			return err
+		}
 
 		var functions []*run.Function
 
 		startFunctions := tasklet.Runner{
 			StartMsg: "Starting Functions",
 			Runner: func(_ output.Progress) error {
-				functions, err = run.FunctionsFromHandlers(proj)
+				functions, err = run.FunctionsFromHandlers(proj, ls.Status())
 				if err != nil {
 					return err
 				}
@@ -153,13 +245,59 @@ var runCmd = &cobra.Command{
 			},
 			StopMsg: "Started Functions!",
 		}
-		tasklet.MustRun(startFunctions, tasklet.Opts{Signal: term})
+		if err := tasklet.Run(startFunctions, tasklet.Opts{Signal: term}); err != nil {
+			return err
+		}
+
+		if len(replay) > 0 {
+			replayRequests := tasklet.Runner{
+				StartMsg: fmt.Sprintf("Replaying %d requests from %s", len(replay), replayFile),
+				Runner: func(progress output.Progress) error {
+					failed := 0
+					for _, res := range run.Replay(ls.Status().GatewayURL(), replay) {
+						if res.Err != nil || res.StatusCode >= 500 {
+							failed++
+							progress.Failf("%s\n", res)
+						} else {
+							progress.Debugf("%s\n", res)
+						}
+					}
+					if failed > 0 {
+						pterm.Warning.Printf("%d of %d replayed requests failed\n", failed, len(replay))
+					}
+					return nil
+				},
+				StopMsg: "Replayed requests",
+			}
+			if err := tasklet.Run(replayRequests, tasklet.Opts{Signal: term}); err != nil {
+				return err
+			}
+		}
 
 		pterm.DefaultBasicText.Println("Local running, use ctrl-C to stop")
+		if dc := containerengine.Detect(); dc != nil {
+			_, port, _ := net.SplitHostPort(ls.Status().GatewayAddress)
+			if url := dc.ForwardedURL(port); url != "" {
+				pterm.Info.Printf("The gateway is forwarded from the codespace to %s\n", url)
+			} else {
+				pterm.Info.Printf("Running in a dev container, forward port %s to reach the gateway at http://localhost:%s\n", port, port)
+			}
+		}
+		if url := ls.Status().TracesURL; url != "" {
+			pterm.DefaultBasicText.Printf("View traces at %s\n", url)
+		}
+		for name := range proj.Workflows {
+			pterm.DefaultBasicText.Printf("Start workflow %s with POST http://localhost:9001/workflow/%s\n", name, name)
+		}
 
 		stackState := run.NewStackState()
 
-		area, _ := pterm.DefaultArea.Start()
+		// the area redraws over the lines above it, so tables are printed as they change when logs are printed
+		var area *pterm.AreaPrinter
+		if printer == nil {
+			area, _ = pterm.DefaultArea.Start()
+		}
+		lastTables := ""
 		lck := sync.Mutex{}
 		// React to worker pool state and update services table
 		pool.Listen(func(we run.WorkerEvent) {
@@ -184,31 +322,54 @@ var runCmd = &cobra.Command{
 			if rows > 0 {
 				tables = append(tables, table)
 			}
-			area.Update(strings.Join(tables, "\n\n"))
+
+			content := strings.Join(tables, "\n\n")
+			if area != nil {
+				area.Update(content)
+			} else if content != lastTables {
+				pterm.DefaultBasicText.Println(content)
+			}
+			lastTables = content
 		})
 
 		select {
 		case membraneError := <-memerr:
-			fmt.Println(errors.WithMessage(membraneError, "membrane error, exiting"))
+			fmt.Fprintln(cmd.OutOrStdout(), errors.WithMessage(membraneError, "membrane error, exiting"))
 		case <-term:
-			fmt.Println("Shutting down services - exiting")
+			fmt.Fprintln(cmd.OutOrStdout(), "Shutting down services - exiting")
 		}
 
 		for _, f := range functions {
 			if err = f.Stop(); err != nil {
-				fmt.Println(f.Name(), " stop error ", err)
+				fmt.Fprintln(cmd.OutOrStdout(), f.Name(), " stop error ", err)
 			}
 		}
 
-		_ = area.Stop()
+		if area != nil {
+			_ = area.Stop()
+		}
 		_ = logger.Stop()
 		// Stop the membrane
-		cobra.CheckErr(ls.Stop())
+		return ls.Stop()
 	},
 	Args: cobra.ExactArgs(0),
 }
 
 func RootCommand() *cobra.Command {
 	runCmd.Flags().StringVarP(&envFile, "env-file", "e", "", "--env-file config/.my-env")
+	runCmd.Flags().StringVar(&platform, "platform", "", "run the functions on this platform (e.g. linux/amd64), defaults to the host's")
+	runCmd.Flags().StringVar(&recordFile, "record", "", "record the api requests and topic messages received to this file")
+	runCmd.Flags().StringVar(&replayFile, "replay", "", "replay the requests recorded with --record once the functions have started")
+	runCmd.Flags().IntVar(&attempts, "max-attempts", 0, "deliver the queue tasks that aren't completed within --lease, and the topic messages whose subscribers fail, up to this many times before sending them to the <name>-dlq queue")
+	runCmd.Flags().DurationVar(&lease, "lease", 30*time.Second, "how long a received queue task has to be completed before it is delivered again, with --max-attempts")
+	runCmd.Flags().BoolVar(&traces, "traces", false, "start a local OpenTelemetry collector and Jaeger UI, with the functions configured to export their traces to it")
+	runCmd.Flags().StringSliceVar(&logs, "logs", nil, "print the logs of these functions (or all) prefixed with the function name, membrane and service logs remain in the log file")
+	cobra.CheckErr(runCmd.RegisterFlagCompletionFunc("logs", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		names, err := project.FunctionNames()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return append([]string{"all"}, names...), cobra.ShellCompDirectiveNoFileComp
+	}))
 	return runCmd
 }
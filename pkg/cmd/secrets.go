@@ -0,0 +1,86 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/run"
+)
+
+var secretsLocal bool
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Work with the values of a project's secrets",
+}
+
+var secretsSetCmd = &cobra.Command{
+	Use:   "set [secret] [value] --local",
+	Short: "Set the value 'nitric run' serves for a secret",
+	Long: `Set the value 'nitric run' serves for a secret.
+
+The value is encrypted into .nitric/secrets.yaml in the project with a key kept in the
+nitric config dir, so handlers that access the secret work locally without real cloud
+secret stores. Values put by the handlers while running take precedence over it.
+The value is read from stdin when it is not given.`,
+	Example: `nitric secrets set api-key 0123456789abcdef --local
+
+cat api-key.txt | nitric secrets set api-key --local`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !secretsLocal {
+			return errors.New("only local secret values can be set, use --local")
+		}
+
+		var value []byte
+		if len(args) > 1 {
+			value = []byte(args[1])
+		} else {
+			b, err := ioutil.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			value = []byte(strings.TrimSuffix(string(b), "\n"))
+		}
+
+		config, err := project.ConfigFromFile(nil)
+		if err != nil {
+			return err
+		}
+
+		if err := run.SetLocalSecret(config.Dir, args[0], value); err != nil {
+			return err
+		}
+
+		pterm.Success.Printf("Set the local value of %s\n", args[0])
+		return nil
+	},
+	Args:        cobra.RangeArgs(1, 2),
+	Annotations: map[string]string{"commonCommand": "yes"},
+}
+
+func secretsCommand() *cobra.Command {
+	secretsSetCmd.Flags().BoolVar(&secretsLocal, "local", false, "set the value served by 'nitric run'")
+	secretsCmd.AddCommand(secretsSetCmd)
+	return secretsCmd
+}
@@ -0,0 +1,138 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/joho/godotenv"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/nitrictech/cli/pkg/codeconfig"
+	"github.com/nitrictech/cli/pkg/i18n"
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/tasklet"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var specFile string
+
+var specCmd = &cobra.Command{
+	Use:   "spec [-s stack]",
+	Short: "Print the resources of the project, gathered from its code",
+	Long: `Print the resources of the project, gathered from its code, as a versioned YAML or JSON document.
+
+The document lists the functions, api routes, topics, queues, buckets, collections, secrets, schedules
+and policies of the project, sorted so it can be diffed between branches, kept for audits or read by other
+tools. With -s only the functions the stack deploys are included.`,
+	Example: `nitric spec
+
+# Compare the resources of two branches
+nitric spec --file main.yaml && git checkout feature && nitric spec --file feature.yaml
+
+nitric spec -o json --file spec.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var s *stack.Config
+		var err error
+		if stack.OptionsChosen() {
+			s, err = stack.ConfigFromOptions()
+			if err != nil {
+				return err
+			}
+		}
+
+		proj, err := gatherProject(s)
+		if err != nil {
+			return err
+		}
+
+		var b []byte
+		if output.OutputTypeFlag.String() == "json" {
+			b, err = json.MarshalIndent(proj.Spec(), "", "  ")
+		} else {
+			b, err = yaml.Marshal(proj.Spec())
+		}
+		if err != nil {
+			return err
+		}
+
+		if specFile == "" {
+			fmt.Fprintln(cmd.OutOrStdout(), string(b))
+			return nil
+		}
+		if err := ioutil.WriteFile(specFile, b, 0644); err != nil {
+			return err
+		}
+		pterm.Success.Println("Wrote the spec to", specFile)
+		return nil
+	},
+	Args: cobra.ExactArgs(0),
+}
+
+// gatherProject returns the project with the resources gathered from its code, only the functions the
+// stack deploys are kept when a stack is given.
+func gatherProject(s *stack.Config) (*project.Project, error) {
+	config, err := project.ConfigFromFile(s)
+	if err != nil {
+		return nil, err
+	}
+
+	proj, err := project.FromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	envFiles := utils.FilesExisting(".env")
+	envMap := map[string]string{}
+	if len(envFiles) > 0 {
+		envMap, err = godotenv.Read(envFiles...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	codeAsConfig := tasklet.Runner{
+		StartMsg: i18n.T("gather.start"),
+		Runner: func(_ output.Progress) error {
+			proj, err = codeconfig.Populate(proj, envMap)
+			return err
+		},
+		StopMsg: i18n.T("gather.stop"),
+	}
+	if err := tasklet.Run(codeAsConfig, tasklet.Opts{}); err != nil {
+		return nil, err
+	}
+
+	if s != nil {
+		if err := proj.FilterFunctions(s); err != nil {
+			return nil, err
+		}
+	}
+	return proj, nil
+}
+
+func specCommand() *cobra.Command {
+	cobra.CheckErr(stack.AddOptionalOptions(specCmd))
+	specCmd.Flags().StringVarP(&specFile, "file", "f", "", "write the spec to this file rather than printing it")
+	return specCmd
+}
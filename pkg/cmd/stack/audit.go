@@ -0,0 +1,30 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package project
+
+import (
+	"github.com/nitrictech/cli/pkg/audit"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/tasklet"
+)
+
+// runAudited runs the stack operation like tasklet.Run, recording it in the project's audit log.
+func runAudited(command string, config *project.Config, s *stack.Config, runner tasklet.Runner, opts tasklet.Opts) error {
+	return audit.Run(config.Audit, config.Dir, command, s.Provider, s.Name, func() error {
+		return tasklet.Run(runner, opts)
+	})
+}
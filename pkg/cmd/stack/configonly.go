@@ -0,0 +1,49 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"github.com/pterm/pterm"
+
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/tasklet"
+)
+
+// printConfigChanges prints the configuration changes that stack update --config-only will make to the functions.
+func printConfigChanges(p types.Provider) error {
+	changes := []types.Change{}
+	preview := tasklet.Runner{
+		StartMsg: "Previewing the configuration changes..",
+		Runner: func(progress output.Progress) error {
+			var err error
+			changes, err = p.Preview(progress)
+			return err
+		},
+		StopMsg: "Previewed",
+	}
+	if err := tasklet.Run(preview, tasklet.Opts{}); err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		pterm.Info.Println("No configuration changes")
+		return nil
+	}
+	output.Print(changes)
+	return nil
+}
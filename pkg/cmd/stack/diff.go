@@ -0,0 +1,65 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package project
+
+import (
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// resolvedStack is a stack's configuration and the project resources it deploys, as compared by stack diff.
+type resolvedStack struct {
+	Stack   *stack.Config    `yaml:"stack"`
+	Project *project.Project `yaml:"project"`
+}
+
+func resolveStack(name string) (*resolvedStack, error) {
+	s, err := stack.ConfigFromName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := project.ConfigFromFile(s)
+	if err != nil {
+		return nil, err
+	}
+
+	proj, err := project.FromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := proj.FilterFunctions(s); err != nil {
+		return nil, err
+	}
+
+	// the names always differ, the rest of the stack is compared
+	s.Name = ""
+	return &resolvedStack{Stack: s, Project: proj}, nil
+}
+
+// diffStacks compares the configuration of two stacks, without looking at what is deployed.
+func diffStacks(from, to string) ([]utils.Difference, error) {
+	f, err := resolveStack(from)
+	if err != nil {
+		return nil, err
+	}
+	t, err := resolveStack(to)
+	if err != nil {
+		return nil, err
+	}
+	return utils.Diff(f, t)
+}
@@ -0,0 +1,82 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package project
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+var stackEncryptCmd = &cobra.Command{
+	Use:   "encrypt [value] [-s stack]",
+	Short: "Encrypt a value with the stack's encryption key",
+	Long: `Encrypt a value with the stack's encryption key.
+
+The stack's encryptionKey is a KMS key id, ARN or alias on aws, a Key Vault key id on azure
+and a Cloud KMS key name on gcp. The printed reference replaces the value in the stack file,
+so API keys and webhook URLs can be committed, it is decrypted when the stack is deployed.
+The value is read from stdin when it is not given.`,
+	Example: `nitric stack encrypt https://hooks.slack.com/services/T000/B000/XXXX -s aws
+
+cat api-key.txt | nitric stack encrypt -s gcp`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := stack.ConfigFromOptions()
+		if err != nil {
+			return err
+		}
+
+		value := ""
+		if len(args) > 0 {
+			value = args[0]
+		} else {
+			b, err := ioutil.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			value = strings.TrimSuffix(string(b), "\n")
+		}
+
+		config, err := project.ConfigFromFile(s)
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.FromConfig(config)
+		if err != nil {
+			return err
+		}
+
+		p, err := newProvider(proj, s, map[string]string{})
+		if err != nil {
+			return err
+		}
+
+		ref, err := p.Encrypt(value)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), ref)
+		return nil
+	},
+	Args: cobra.MaximumNArgs(1),
+}
@@ -0,0 +1,123 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package project
+
+import (
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/i18n"
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/tasklet"
+)
+
+var (
+	gcDryRun  bool
+	confirmGc bool
+)
+
+var stackGcCmd = &cobra.Command{
+	Use:   "gc [-s stack]",
+	Short: "Delete the resources tagged with a stack that are no longer in its state",
+	Long: `Delete the resources tagged with a stack that are no longer in its state.
+
+Failed deletes and renames can leave resources behind that still carry the stack's tags, but that pulumi
+no longer manages. They are listed and, once confirmed, deleted. Resources that nitric can't delete are
+reported so they can be deleted by hand.`,
+	Example: `# List the orphaned resources without deleting them
+nitric stack gc -s aws --dry-run
+
+# To not be prompted, use -y
+nitric stack gc -s aws -y`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := stack.ConfigFromOptions()
+		if err != nil {
+			return err
+		}
+
+		config, err := project.ConfigFromFile(s)
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.FromConfig(config)
+		if err != nil {
+			return err
+		}
+
+		p, err := newProvider(proj, s, map[string]string{})
+		if err != nil {
+			return err
+		}
+
+		orphans := []types.Orphan{}
+		find := tasklet.Runner{
+			StartMsg: "Finding orphaned resources..",
+			Runner: func(progress output.Progress) error {
+				orphans, err = p.Orphans(progress)
+				return err
+			},
+			StopMsg: "Orphaned resources found",
+		}
+		if err := tasklet.Run(find, tasklet.Opts{}); err != nil {
+			return err
+		}
+
+		if len(orphans) == 0 {
+			pterm.Success.Println("The stack has no orphaned resources")
+			return nil
+		}
+
+		rows := [][]string{{"Type", "ID"}}
+		for _, o := range orphans {
+			rows = append(rows, []string{o.Type, o.ID})
+		}
+		_ = pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(rows).Render()
+
+		if gcDryRun {
+			return nil
+		}
+
+		if !confirmGc {
+			confirm := ""
+			err := survey.AskOne(&survey.Select{
+				Message: i18n.T("stack.gc.confirm", len(orphans)),
+				Default: "No",
+				Options: []string{"Yes", "No"},
+			}, &confirm)
+			if err != nil {
+				return err
+			}
+			if confirm != "Yes" {
+				pterm.Info.Println("Cancelling command")
+				return nil
+			}
+		}
+
+		return runAudited("stack gc", config, s, tasklet.Runner{
+			StartMsg: "Deleting orphaned resources..",
+			Runner: func(progress output.Progress) error {
+				return p.DeleteOrphans(orphans, progress)
+			},
+			StopMsg: "Orphaned resources deleted",
+		}, tasklet.Opts{})
+	},
+	Args: cobra.ExactArgs(0),
+}
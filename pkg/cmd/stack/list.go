@@ -0,0 +1,72 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package project
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+// listAllTargets lists the stacks of every stack configured in the project, in one table.
+func listAllTargets() (interface{}, error) {
+	names, err := stack.Names()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no stacks are configured, run `nitric stack new` to create one")
+	}
+
+	var all reflect.Value
+	for _, name := range names {
+		s, err := stack.ConfigFromName(name)
+		if err != nil {
+			return nil, err
+		}
+
+		config, err := project.ConfigFromFile(s)
+		if err != nil {
+			return nil, err
+		}
+
+		proj, err := project.FromConfig(config)
+		if err != nil {
+			return nil, err
+		}
+
+		p, err := newProvider(proj, s, map[string]string{})
+		if err != nil {
+			return nil, errors.WithMessage(err, "stack "+name)
+		}
+
+		deps, err := p.List()
+		if err != nil {
+			return nil, errors.WithMessage(err, "stack "+name)
+		}
+
+		// each provider lists a slice of its own summaries, they are joined so they print as one table
+		if !all.IsValid() {
+			all = reflect.ValueOf(deps)
+		} else {
+			all = reflect.AppendSlice(all, reflect.ValueOf(deps))
+		}
+	}
+	return all.Interface(), nil
+}
@@ -0,0 +1,148 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/build"
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/tasklet"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var (
+	lockReason   string
+	overrideLock bool
+)
+
+var stackLockCmd = &cobra.Command{
+	Use:   "lock --reason reason [-s stack]",
+	Short: "Freeze a deployed stack so it can't be updated or deleted",
+	Long: `Freeze a deployed stack so it can't be updated or deleted.
+
+The lock is kept with the stack in the Pulumi backend, so "nitric stack update" and "nitric stack down"
+fail for everyone with the reason and who locked it until it is unlocked, or they use --override-lock.`,
+	Example: `nitric stack lock --reason "release 1.4 freeze" -s aws`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return lockStack(true)
+	},
+	Args: cobra.ExactArgs(0),
+}
+
+var stackUnlockCmd = &cobra.Command{
+	Use:     "unlock [-s stack]",
+	Short:   "Remove the lock of a deployed stack",
+	Long:    `Remove the lock of a deployed stack, so it can be updated and deleted again.`,
+	Example: `nitric stack unlock -s aws`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return lockStack(false)
+	},
+	Args: cobra.ExactArgs(0),
+}
+
+func lockStack(locking bool) error {
+	s, err := stack.ConfigFromOptions()
+	if err != nil {
+		return err
+	}
+
+	config, err := project.ConfigFromFile(s)
+	if err != nil {
+		return err
+	}
+
+	proj, err := project.FromConfig(config)
+	if err != nil {
+		return err
+	}
+
+	p, err := newProvider(proj, s, map[string]string{})
+	if err != nil {
+		return err
+	}
+
+	if !locking {
+		return runAudited("stack unlock", config, s, tasklet.Runner{
+			StartMsg: "Unlocking the stack..",
+			Runner: func(_ output.Progress) error {
+				return p.Unlock()
+			},
+			StopMsg: "Stack unlocked",
+		}, tasklet.Opts{})
+	}
+
+	var lock *types.StackLock
+	err = runAudited("stack lock", config, s, tasklet.Runner{
+		StartMsg: "Locking the stack..",
+		Runner: func(_ output.Progress) error {
+			lock, err = p.Lock(lockReason)
+			return err
+		},
+		StopMsg: "Stack locked",
+	}, tasklet.Opts{})
+	if err != nil {
+		return err
+	}
+
+	if output.OutputTypeFlag.String() != "table" {
+		output.Print(lock)
+		return nil
+	}
+	pterm.Info.Printf("%s is locked by %s: %s\n", s.Name, lock.Owner, lock.Reason)
+	return nil
+}
+
+// verifyLock checks that the deployment tooling matches what the target was locked to.
+func verifyLock(tl *project.TargetLock, p types.Provider) error {
+	if tl.CliVersion != utils.Version {
+		pterm.Warning.Printf("%s was written by nitric %s, this is nitric %s\n", project.LockFile, tl.CliVersion, utils.Version)
+	}
+
+	for name, version := range p.PluginVersions() {
+		if locked, ok := tl.Plugins[name]; ok && locked != version {
+			return fmt.Errorf("%s pins the %s plugin to %s but this nitric uses %s, use --update-lock to accept the change", project.LockFile, name, locked, version)
+		}
+	}
+	return nil
+}
+
+// writeLock records the versions and base images that the target was deployed with.
+func writeLock(l *project.Lock, target string, proj *project.Project, p types.Provider) error {
+	images, err := build.BaseImages(proj)
+	if err != nil {
+		return err
+	}
+
+	digests, err := build.ImageDigests(images)
+	if err != nil {
+		return err
+	}
+
+	l.Targets[target] = &project.TargetLock{
+		CliVersion: utils.Version,
+		Plugins:    p.PluginVersions(),
+		Images:     digests,
+	}
+	return l.ToFile(proj.Dir)
+}
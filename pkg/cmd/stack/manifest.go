@@ -0,0 +1,111 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v2"
+
+	"github.com/nitrictech/cli/pkg/build"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// deployManifest records what a stack update deployed, to attach to a release or to deploy the same
+// images and configuration again.
+type deployManifest struct {
+	Project    string            `yaml:"project"`
+	Stack      string            `yaml:"stack"`
+	Deployed   time.Time         `yaml:"deployed"`
+	GitCommit  string            `yaml:"gitCommit,omitempty"`
+	CliVersion string            `yaml:"cliVersion"`
+	Plugins    map[string]string `yaml:"plugins"`
+	// Images are the pushed images, by the local image they were built from
+	Images []manifestImage `yaml:"images"`
+	// BaseImages are the repository digests of the runtime images the functions were built on
+	BaseImages map[string]string `yaml:"baseImages,omitempty"`
+	Outputs    manifestOutputs   `yaml:"outputs"`
+	// Config is the stack as it was deployed, with its parameters resolved. Encrypted values are
+	// left encrypted.
+	Config stack.Config `yaml:"config"`
+}
+
+type manifestImage struct {
+	Local  string `yaml:"local"`
+	Pushed string `yaml:"pushed"`
+	Digest string `yaml:"digest,omitempty"`
+}
+
+type manifestOutputs struct {
+	Apis  map[string]string `yaml:"apis,omitempty"`
+	Cdns  map[string]string `yaml:"cdns,omitempty"`
+	Sites map[string]string `yaml:"sites,omitempty"`
+}
+
+// newDeployManifest records the deployment of the project to the stack. Image digests are left out
+// when the container engine can't provide them.
+func newDeployManifest(proj *project.Project, s stack.Config, d *types.Deployment, plugins map[string]string) *deployManifest {
+	m := &deployManifest{
+		Project:    proj.Name,
+		Stack:      s.Name,
+		Deployed:   time.Now().UTC(),
+		GitCommit:  utils.GitCommit(proj.Dir),
+		CliVersion: utils.Version,
+		Plugins:    plugins,
+		Images:     []manifestImage{},
+		Outputs: manifestOutputs{
+			Apis:  d.ApiEndpoints,
+			Cdns:  d.CdnEndpoints,
+			Sites: d.SiteEndpoints,
+		},
+		Config: s,
+	}
+
+	for local, pushed := range d.Images {
+		img := manifestImage{Local: local, Pushed: pushed}
+		if digests, err := build.ImageDigests([]string{local}); err == nil {
+			img.Digest = digests[local]
+		} else {
+			pterm.Debug.Println("no digest for", local, err)
+		}
+		m.Images = append(m.Images, img)
+	}
+	sort.Slice(m.Images, func(i, j int) bool {
+		return m.Images[i].Local < m.Images[j].Local
+	})
+
+	if images, err := build.BaseImages(proj); err == nil {
+		if digests, err := build.ImageDigests(images); err == nil {
+			m.BaseImages = digests
+		}
+	}
+	return m
+}
+
+func (m *deployManifest) toFile(file string) error {
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, b, 0644)
+}
@@ -0,0 +1,61 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"github.com/nitrictech/cli/pkg/i18n"
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/tasklet"
+)
+
+var mockApis bool
+
+// updateMocks deploys only the mocked apis of the project, which respond with the examples of their
+// openapi files, without gathering or building the functions.
+func updateMocks(config *project.Config, s *stack.Config) error {
+	proj, err := project.MockFromConfig(config)
+	if err != nil {
+		return err
+	}
+
+	p, err := newProvider(proj, s, map[string]string{})
+	if err != nil {
+		return err
+	}
+	if err := p.Preflight(); err != nil {
+		return err
+	}
+
+	d := &types.Deployment{}
+	deploy := tasklet.Runner{
+		StartMsg: i18n.T("deploy.start"),
+		Runner: func(progress output.Progress) error {
+			d, err = p.Up(progress)
+			return err
+		},
+		StopMsg: i18n.T("deploy.stop"),
+	}
+	if err := runAudited("stack update --mock", config, s, deploy, tasklet.Opts{SuccessPrefix: "Deployed"}); err != nil {
+		return err
+	}
+
+	printEndpoints(d)
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package project
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var (
+	watchOutputs    bool
+	outputsInterval time.Duration
+)
+
+var stackOutputsCmd = &cobra.Command{
+	Use:   "outputs [-s stack]",
+	Short: "Print the endpoints and custom domains of a deployed stack",
+	Long: `Print the endpoints and custom domains of a deployed stack.
+
+Custom domains can take minutes to be validated, and have their certificates issued, after the stack is
+updated. With --watch their state is polled until they are all ready, printing the DNS records each one
+needs whenever they change.`,
+	Example: `nitric stack outputs -s azure
+
+nitric stack outputs -s azure --watch --interval 1m`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := stack.ConfigFromOptions()
+		if err != nil {
+			return err
+		}
+
+		config, err := project.ConfigFromFile(s)
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.FromConfig(config)
+		if err != nil {
+			return err
+		}
+
+		p, err := newProvider(proj, s, map[string]string{})
+		if err != nil {
+			return err
+		}
+
+		d, err := p.Deployment()
+		if err != nil {
+			return err
+		}
+		printEndpoints(d)
+
+		domains, err := p.Domains()
+		if _, ok := err.(*utils.NotSupportedError); ok && !watchOutputs {
+			// there are no custom domains to wait for
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		printDomains(domains, nil)
+
+		for watchOutputs && pendingDomains(domains) > 0 {
+			time.Sleep(outputsInterval)
+
+			next, err := p.Domains()
+			if err != nil {
+				return err
+			}
+			printDomains(next, domains)
+			domains = next
+		}
+		return nil
+	},
+	Args:        cobra.ExactArgs(0),
+	Annotations: map[string]string{"commonCommand": "yes"},
+}
+
+// printDomains prints the domains whose state or records changed since the last poll, all of them when
+// there was none.
+func printDomains(domains, last []types.DomainStatus) {
+	previous := map[string]types.DomainStatus{}
+	for _, d := range last {
+		previous[d.Domain] = d
+	}
+
+	for _, d := range domains {
+		if p, ok := previous[d.Domain]; ok && p.State == d.State && fmt.Sprint(p.Records) == fmt.Sprint(d.Records) {
+			continue
+		}
+		if d.Ready {
+			pterm.Success.Printf("%s is ready\n", d.Domain)
+			continue
+		}
+		pterm.Info.Printf("%s is %s\n", d.Domain, d.State)
+		if len(d.Records) == 0 {
+			continue
+		}
+
+		rows := [][]string{{"Type", "Name", "Value"}}
+		for _, r := range d.Records {
+			rows = append(rows, []string{r.Type, r.Name, r.Value})
+		}
+		pterm.Warning.Printf("create these DNS records for %s to be validated\n", d.Domain)
+		_ = pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(rows).Render()
+	}
+}
+
+// pendingDomains is the number of domains that are not ready yet.
+func pendingDomains(domains []types.DomainStatus) int {
+	n := 0
+	for _, d := range domains {
+		if !d.Ready {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,190 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package project
+
+import (
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/build"
+	"github.com/nitrictech/cli/pkg/codeconfig"
+	"github.com/nitrictech/cli/pkg/containerengine"
+	"github.com/nitrictech/cli/pkg/i18n"
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/tasklet"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var (
+	securityOnly bool
+	savePlan     string
+)
+
+var stackPreviewCmd = &cobra.Command{
+	Use:   "preview [-s stack]",
+	Short: "Preview the changes an update would make to a stack",
+	Long: `Preview the changes an update would make to a stack, without making them.
+
+The project is gathered and built as it is for an update. With --security only the changes to identities
+and permissions are listed (roles, policies, role assignments and service accounts), so they can be
+reviewed separately from the rest of the infrastructure.
+
+With --save-plan the changes are written to a file for "nitric stack update --from-plan", which fails
+without changing anything when the stack would no longer make exactly those changes.`,
+	Example: `nitric stack preview -s aws
+
+# List only the IAM and RBAC changes
+nitric stack preview -s aws --security
+
+nitric stack preview -s gcp --security -o json
+
+# Plan in a pull request, apply once it is approved
+nitric stack preview -s aws --save-plan plan.json
+nitric stack update -s aws --from-plan plan.json
+
+# List the changes to the environment and resources of the functions
+nitric stack preview -s aws --config-only`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := stack.ConfigFromOptions()
+		if err != nil {
+			return err
+		}
+
+		config, err := project.ConfigFromFile(s)
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.FromConfig(config)
+		if err != nil {
+			return err
+		}
+		if err := proj.FilterFunctions(s); err != nil {
+			return err
+		}
+
+		envFiles := utils.FilesExisting(".env", ".env.production", envFile)
+		envMap := map[string]string{}
+		if len(envFiles) > 0 {
+			envMap, err = godotenv.Read(envFiles...)
+			if err != nil {
+				return err
+			}
+		}
+
+		// the deployed images are reused
+		reuseImages := skipBuild || configOnly
+
+		if !reuseImages {
+			if err := build.Preflight(proj, containerengine.DeployPlatform); err != nil {
+				return err
+			}
+		}
+
+		if skipGather {
+			proj, err = codeconfig.FromCache(proj)
+			if err != nil {
+				return err
+			}
+			if err := proj.FilterFunctions(s); err != nil {
+				return err
+			}
+		} else {
+			codeAsConfig := tasklet.Runner{
+				StartMsg: i18n.T("gather.start"),
+				Runner: func(_ output.Progress) error {
+					proj, err = codeconfig.Populate(proj, envMap)
+					return err
+				},
+				StopMsg: i18n.T("gather.stop"),
+			}
+			if err := tasklet.Run(codeAsConfig, tasklet.Opts{}); err != nil {
+				return err
+			}
+		}
+		if err := proj.ValidateTriggers(); err != nil {
+			return err
+		}
+
+		p, err := newProvider(proj, s, envMap)
+		if err != nil {
+			return err
+		}
+		p.SetConfigOnly(configOnly)
+
+		if !reuseImages {
+			buildImages := tasklet.Runner{
+				StartMsg: i18n.T("build.start"),
+				Runner: func(_ output.Progress) error {
+					return build.Create(proj, s, nil)
+				},
+				StopMsg: i18n.T("build.stop"),
+			}
+			if err := tasklet.Run(buildImages, tasklet.Opts{}); err != nil {
+				return err
+			}
+		}
+
+		changes := []types.Change{}
+		preview := tasklet.Runner{
+			StartMsg: "Previewing..",
+			Runner: func(progress output.Progress) error {
+				changes, err = p.Preview(progress)
+				return err
+			},
+			StopMsg: "Previewed",
+		}
+		if err := tasklet.Run(preview, tasklet.Opts{}); err != nil {
+			return err
+		}
+
+		if savePlan != "" {
+			plan := &types.Plan{Stack: s.Name, Created: time.Now().UTC(), Changes: changes}
+			if err := plan.ToFile(savePlan); err != nil {
+				return err
+			}
+			pterm.Info.Printf("Saved the plan of %d changes to %s\n", len(changes), savePlan)
+		}
+
+		if securityOnly {
+			security := []types.Change{}
+			for _, c := range changes {
+				if c.Security {
+					security = append(security, c)
+				}
+			}
+			changes = security
+		}
+
+		if len(changes) == 0 {
+			if securityOnly {
+				pterm.Info.Println("No identity or permission changes")
+			} else {
+				pterm.Info.Println("No changes")
+			}
+			return nil
+		}
+		output.Print(changes)
+		return nil
+	},
+	Args: cobra.ExactArgs(0),
+}
@@ -0,0 +1,43 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"github.com/pterm/pterm"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// warnQuotas warns about the account quotas the stack needs more of, before the images are built. The
+// quotas are only read to warn, so failing to read them does not stop the deploy.
+func warnQuotas(p types.Provider) {
+	quotas, err := p.Quotas()
+	if _, ok := err.(*utils.NotSupportedError); ok {
+		return
+	}
+	if err != nil {
+		pterm.Debug.Printf("unable to check the account quotas: %v\n", err)
+		return
+	}
+
+	for _, q := range quotas {
+		if q.Exceeded() {
+			pterm.Warning.Printf("the stack needs %g of the %s quota, which is %g, the deploy may fail until it is increased at %s\n", q.Needed, q.Name, q.Limit, q.IncreaseUrl)
+		}
+	}
+}
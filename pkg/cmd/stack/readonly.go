@@ -0,0 +1,45 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+var (
+	readOnly    bool
+	showSecrets bool
+)
+
+// newProvider returns the stack's provider, read-only when the read_only setting is set unless
+// --read-only says otherwise, revealing the secret outputs with --show-secrets and changing a locked stack
+// with --override-lock.
+func newProvider(proj *project.Project, s *stack.Config, envMap map[string]string) (types.Provider, error) {
+	p, err := provider.NewProvider(proj, s, envMap)
+	if err != nil {
+		return nil, err
+	}
+	if stackCmd.PersistentFlags().Changed("read-only") {
+		p.SetReadOnly(readOnly)
+	}
+	p.SetShowSecrets(showSecrets)
+	p.SetOverrideLock(overrideLock)
+	return p, nil
+}
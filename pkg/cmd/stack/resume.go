@@ -0,0 +1,165 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pterm/pterm"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var resumeUpdate bool
+
+// updateProgress records how far an update of a stack got, so that after a failed deploy --resume can
+// skip the gather and build stages that already completed.
+type updateProgress struct {
+	lock sync.Mutex
+
+	Stack           string   `json:"stack"`
+	GitCommit       string   `json:"gitCommit,omitempty"`
+	Gathered        bool     `json:"gathered"`
+	Built           bool     `json:"built"`
+	FailedStage     string   `json:"failedStage,omitempty"`
+	FailedResources []string `json:"failedResources,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+var _ types.EventListener = &updateProgress{}
+
+func newUpdateProgress(stackName, dir string) *updateProgress {
+	return &updateProgress{Stack: stackName, GitCommit: utils.GitCommit(dir)}
+}
+
+// updateProgressFile holds the progress of the last update of the stack.
+func updateProgressFile(dir, stackName string) string {
+	return filepath.Join(utils.NitricLogDir(dir), "update-"+stackName+".json")
+}
+
+// loadUpdateProgress returns the progress of the last update of the stack that failed, nil when there is none.
+func loadUpdateProgress(dir, stackName string) (*updateProgress, error) {
+	b, err := ioutil.ReadFile(updateProgressFile(dir, stackName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	u := &updateProgress{}
+	if err := json.Unmarshal(b, u); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", updateProgressFile(dir, stackName), err)
+	}
+	return u, nil
+}
+
+func (u *updateProgress) OnEvent(evt types.Event) {
+	if evt.Type != types.EventResourceFailed {
+		return
+	}
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	u.FailedResources = append(u.FailedResources, evt.Resource)
+}
+
+// done records a stage completed.
+func (u *updateProgress) done(dir, stage string) error {
+	switch stage {
+	case "gather":
+		u.Gathered = true
+	case "build":
+		u.Built = true
+	}
+	return u.save(dir)
+}
+
+// fail records the stage the update failed at, returning err.
+func (u *updateProgress) fail(dir, stage string, err error) error {
+	u.FailedStage = stage
+	u.Error = err.Error()
+	if saveErr := u.save(dir); saveErr != nil {
+		return fmt.Errorf("%w, and the progress could not be saved to resume from: %v", err, saveErr)
+	}
+	return err
+}
+
+// failure describes where the update failed.
+func (u *updateProgress) failure() string {
+	if len(u.FailedResources) > 0 {
+		return fmt.Sprintf("the %s stage, on %s", u.FailedStage, strings.Join(u.FailedResources, ", "))
+	}
+	return "the " + u.FailedStage + " stage"
+}
+
+func (u *updateProgress) save(dir string) error {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	b, err := json.MarshalIndent(u, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(utils.NitricLogDir(dir), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(updateProgressFile(dir, u.Stack), b, 0644)
+}
+
+// clear removes the progress once the stack is deployed, as there is nothing left to resume.
+func (u *updateProgress) clear(dir string) error {
+	err := os.Remove(updateProgressFile(dir, u.Stack))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// skips returns whether the gather and build stages are skipped, either with the flags or because the
+// update being resumed completed them.
+func (u *updateProgress) skips(skipGather, skipBuild bool) (bool, bool) {
+	return skipGather || u.Gathered, skipBuild || u.Built
+}
+
+// resumeFrom returns the progress of the last failed update of the stack, to skip the stages it completed.
+// It fails when the code has been committed to since, as the gathered configuration and images are stale.
+func resumeFrom(stackName, dir string) (*updateProgress, error) {
+	last, err := loadUpdateProgress(dir, stackName)
+	if err != nil {
+		return nil, err
+	}
+	if last == nil || last.FailedStage == "" {
+		return nil, fmt.Errorf("there is no failed update of stack %s to resume", stackName)
+	}
+	if commit := utils.GitCommit(dir); last.GitCommit != commit {
+		return nil, fmt.Errorf("the code has changed since the update of stack %s failed at commit %s, please update without --resume", stackName, last.GitCommit)
+	}
+
+	pterm.Info.Printf("Resuming the update of stack %s that failed at %s\n", stackName, last.failure())
+	last.FailedStage = ""
+	last.FailedResources = nil
+	last.Error = ""
+	return last, nil
+}
@@ -0,0 +1,176 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func writeUpdateProgress(t *testing.T, dir, stackName, contents string) {
+	if err := os.MkdirAll(filepath.Join(dir, ".nitric"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(updateProgressFile(dir, stackName), []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadUpdateProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     *updateProgress
+		wantErr  bool
+	}{
+		{
+			name: "no progress",
+		},
+		{
+			name:     "failed deploy",
+			contents: `{"stack": "aws", "gathered": true, "built": true, "failedStage": "deploy", "failedResources": ["api"]}`,
+			want:     &updateProgress{Stack: "aws", Gathered: true, Built: true, FailedStage: "deploy", FailedResources: []string{"api"}},
+		},
+		{
+			name:     "invalid json",
+			contents: `{"stack": `,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "test-nitric-resume")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			if tt.contents != "" {
+				writeUpdateProgress(t, dir, "aws", tt.contents)
+			}
+
+			got, err := loadUpdateProgress(dir, "aws")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loadUpdateProgress() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !cmp.Equal(tt.want, got, cmpopts.IgnoreUnexported(updateProgress{})) {
+				t.Error(cmp.Diff(tt.want, got, cmpopts.IgnoreUnexported(updateProgress{})))
+			}
+		})
+	}
+}
+
+func TestResumeFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     *updateProgress
+		wantErr  bool
+	}{
+		{
+			name:    "no progress",
+			wantErr: true,
+		},
+		{
+			name:     "update that did not fail",
+			contents: `{"stack": "aws", "gathered": true, "built": true}`,
+			wantErr:  true,
+		},
+		{
+			name:     "code committed to since",
+			contents: `{"stack": "aws", "gitCommit": "1234", "gathered": true, "failedStage": "build"}`,
+			wantErr:  true,
+		},
+		{
+			name:     "failed deploy",
+			contents: `{"stack": "aws", "gathered": true, "built": true, "failedStage": "deploy", "failedResources": ["api"], "error": "timeout"}`,
+			want:     &updateProgress{Stack: "aws", Gathered: true, Built: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the temp dir isn't a git repo, so its commit is empty
+			dir, err := ioutil.TempDir("", "test-nitric-resume")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			if tt.contents != "" {
+				writeUpdateProgress(t, dir, "aws", tt.contents)
+			}
+
+			got, err := resumeFrom("aws", dir)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resumeFrom() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !cmp.Equal(tt.want, got, cmpopts.IgnoreUnexported(updateProgress{})) {
+				t.Error(cmp.Diff(tt.want, got, cmpopts.IgnoreUnexported(updateProgress{})))
+			}
+		})
+	}
+}
+
+func TestUpdateProgressSkips(t *testing.T) {
+	tests := []struct {
+		name       string
+		progress   *updateProgress
+		skipGather bool
+		skipBuild  bool
+		wantGather bool
+		wantBuild  bool
+	}{
+		{
+			name:     "failed while gathering",
+			progress: &updateProgress{},
+		},
+		{
+			name:       "failed while building",
+			progress:   &updateProgress{Gathered: true},
+			wantGather: true,
+		},
+		{
+			name:       "failed while deploying",
+			progress:   &updateProgress{Gathered: true, Built: true},
+			wantGather: true,
+			wantBuild:  true,
+		},
+		{
+			name:       "skipped with the flags",
+			progress:   &updateProgress{},
+			skipGather: true,
+			skipBuild:  true,
+			wantGather: true,
+			wantBuild:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotGather, gotBuild := tt.progress.skips(tt.skipGather, tt.skipBuild)
+			if gotGather != tt.wantGather || gotBuild != tt.wantBuild {
+				t.Errorf("skips() = %v, %v, want %v, %v", gotGather, gotBuild, tt.wantGather, tt.wantBuild)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+var revisionTraffic map[string]string
+
+var stackRevisionsCmd = &cobra.Command{
+	Use:   "revisions [function] [-s stack]",
+	Short: "List the revisions of a function, or split its traffic between them",
+	Long: `List the revisions of a deployed function, or split its traffic between them.
+
+With --traffic the weights are saved to the function's revisions in the stack file, which is rewritten,
+and the next stack update applies them. Keeping them in the stack stops later updates resetting them.
+"latest" is the revision the update deploys, other revisions are named as they are listed. Sending
+traffic to them switches the function to the multiple revisions mode, so they stay active.
+
+Revisions are supported on Azure.`,
+	Example: `nitric stack revisions orders -s azure
+
+# send a tenth of the requests to the revision deployed by the next update
+nitric stack revisions orders -s azure --traffic latest=10 --traffic orders-app--v1=90`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(revisionTraffic) > 0 {
+			return saveTraffic(args[0])
+		}
+
+		s, err := stack.ConfigFromOptions()
+		if err != nil {
+			return err
+		}
+
+		config, err := project.ConfigFromFile(s)
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.FromConfig(config)
+		if err != nil {
+			return err
+		}
+
+		p, err := newProvider(proj, s, map[string]string{})
+		if err != nil {
+			return err
+		}
+
+		revs, err := p.Revisions(args[0])
+		if err != nil {
+			return err
+		}
+
+		if output.OutputTypeFlag.String() != "table" {
+			output.Print(revs)
+			return nil
+		}
+
+		rows := [][]string{{"Revision", "Created", "Active", "Traffic", "Replicas", "State"}}
+		for _, r := range revs {
+			rows = append(rows, []string{r.Name, r.Created, strconv.FormatBool(r.Active), fmt.Sprintf("%d%%", r.Traffic), strconv.Itoa(r.Replicas), r.State})
+		}
+		return pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(rows).Render()
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+// validateWeight checks a --traffic weight is a whole percentage.
+func validateWeight(revision, weight string) error {
+	if _, err := strconv.Atoi(weight); err != nil {
+		return fmt.Errorf("the traffic weight of %s must be a whole number, not %s", revision, weight)
+	}
+	return nil
+}
+
+// saveTraffic writes the --traffic weights to the function's revisions in the stack file.
+func saveTraffic(function string) error {
+	file, err := stack.ChosenFile()
+	if err != nil {
+		return err
+	}
+	s, err := stack.RawConfigFromOptions()
+	if err != nil {
+		return err
+	}
+
+	r := s.Revisions[function]
+	r.Traffic = map[string]int{}
+	for name, weight := range revisionTraffic {
+		// the weights were checked by validateWeight
+		r.Traffic[name], _ = strconv.Atoi(weight)
+		if name != stack.LatestRevision {
+			r.Mode = stack.RevisionsMultiple
+		}
+	}
+	if err := r.Validate(); err != nil {
+		return err
+	}
+
+	if s.Revisions == nil {
+		s.Revisions = map[string]stack.Revisions{}
+	}
+	s.Revisions[function] = r
+	if err := s.ToFile(file); err != nil {
+		return err
+	}
+
+	pterm.Success.Printf("Saved the traffic of %s to %s, run nitric stack update to apply it\n", function, file)
+	return nil
+}
@@ -17,21 +17,28 @@
 package project
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/joho/godotenv"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 
+	"github.com/nitrictech/cli/pkg/apiclient"
 	"github.com/nitrictech/cli/pkg/build"
 	"github.com/nitrictech/cli/pkg/codeconfig"
+	"github.com/nitrictech/cli/pkg/containerengine"
+	"github.com/nitrictech/cli/pkg/i18n"
 	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/pflagext"
 	"github.com/nitrictech/cli/pkg/project"
-	"github.com/nitrictech/cli/pkg/provider"
+	"github.com/nitrictech/cli/pkg/provider/pulumi"
 	"github.com/nitrictech/cli/pkg/provider/types"
 	"github.com/nitrictech/cli/pkg/stack"
 	"github.com/nitrictech/cli/pkg/tasklet"
@@ -39,8 +46,23 @@ import (
 )
 
 var (
-	confirmDown bool
-	envFile     string
+	allTargets          bool
+	configOnly          bool
+	confirmDown         bool
+	createMissingTopics bool
+	deleteData          bool
+	emptyBuckets        bool
+	envFile             string
+	eventsWebhook       string
+	exportFile          string
+	fromPlan            string
+	logsDeploy          bool
+	manifestFile        string
+	membraneVersion     string
+	skipBuild           bool
+	skipGather          bool
+	timingsFile         string
+	updateLock          bool
 )
 
 var stackCmd = &cobra.Command{
@@ -48,10 +70,18 @@ var stackCmd = &cobra.Command{
 	Short: "Manage stacks (the deployed app containing multiple resources e.g. collection, bucket, topic)",
 	Long: `Manage stacks (the deployed app containing multiple resources e.g. collection, bucket, topic).
 
-A stack is a named update target, and a single project may have many of them.`,
+A stack is a named update target, and a single project may have many of them.
+
+With --read-only, or the read_only setting, the stacks are only listed and inspected. Commands that would
+change a stack fail before they start, and its state is not refreshed, so credentials that can only read the
+cloud account and the Pulumi backend are enough.
+
+Outputs marked as secret, e.g. those holding connection strings or keys, are masked in every output format
+unless --show-secrets is given.`,
 	Example: `nitric stack up
 nitric stack down
 nitric stack list
+nitric stack outputs -s aws --read-only
 `,
 }
 
@@ -59,108 +89,428 @@ var newStackCmd = &cobra.Command{
 	Use:   "new",
 	Short: "Create a new Nitric stack",
 	Long:  `Creates a new Nitric stack.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		name := ""
 		err := survey.AskOne(&survey.Input{
-			Message: "What do you want to call your new stack?",
+			Message: i18n.T("stack.name.prompt"),
 		}, &name)
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 
 		pName := ""
 		err = survey.AskOne(&survey.Select{
-			Message: "Which Cloud do you wish to deploy to?",
+			Message: i18n.T("stack.cloud.prompt"),
 			Default: stack.Aws,
 			Options: stack.Providers,
 		}, &pName)
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 
-		pc, err := project.ConfigFromFile()
-		cobra.CheckErr(err)
+		pc, err := project.RawConfigFromFile()
+		if err != nil {
+			return err
+		}
 
-		prov, err := provider.NewProvider(project.New(pc), &stack.Config{Name: name, Provider: pName}, map[string]string{})
-		cobra.CheckErr(err)
+		prov, err := newProvider(project.New(pc), &stack.Config{Name: name, Provider: pName}, map[string]string{})
+		if err != nil {
+			return err
+		}
 
 		sc, err := prov.Ask()
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 
 		err = sc.ToFile(filepath.Join(pc.Dir, fmt.Sprintf("nitric-%s.yaml", sc.Name)))
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
+		return nil
 	},
 	Args:        cobra.MaximumNArgs(2),
 	Annotations: map[string]string{"commonCommand": "yes"},
 }
 
+var stackCloneCmd = &cobra.Command{
+	Use:   "clone [name] [-s stack]",
+	Short: "Copy a stack's configuration to a new stack",
+	Long: `Copy a stack's configuration to a new stack, e.g. to promote staging to prod.
+
+Only the configuration is copied, not the deployed state. You are prompted for the
+values that are specific to the new target, such as the region.`,
+	Example: `nitric stack clone prod -s staging`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := stack.RawConfigFromOptions()
+		if err != nil {
+			return err
+		}
+
+		pc, err := project.RawConfigFromFile()
+		if err != nil {
+			return err
+		}
+
+		file := filepath.Join(pc.Dir, fmt.Sprintf("nitric-%s.yaml", args[0]))
+		if _, err := os.Stat(file); err == nil {
+			return fmt.Errorf("stack %s already exists", args[0])
+		}
+
+		pName := ""
+		err = survey.AskOne(&survey.Select{
+			Message: i18n.T("stack.cloud.prompt"),
+			Default: s.Provider,
+			Options: stack.Providers,
+		}, &pName)
+		if err != nil {
+			return err
+		}
+
+		prov, err := newProvider(project.New(pc), &stack.Config{Name: args[0], Provider: pName}, map[string]string{})
+		if err != nil {
+			return err
+		}
+
+		target, err := prov.Ask()
+		if err != nil {
+			return err
+		}
+
+		sc, err := s.Promote(target)
+		if err != nil {
+			return err
+		}
+
+		if sc.Cdn != nil {
+			pterm.Warning.Println("CDN domains and certificates were copied, check they are correct for the new stack")
+		}
+
+		return sc.ToFile(file)
+	},
+	Args: cobra.ExactArgs(1),
+}
+
 var stackUpdateCmd = &cobra.Command{
-	Use:     "update [-s stack]",
-	Short:   "Create or update a deployed stack",
-	Long:    `Create or update a deployed stack`,
-	Example: `nitric stack update -s aws`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:   "update [-s stack]",
+	Short: "Create or update a deployed stack",
+	Long:  `Create or update a deployed stack`,
+	Example: `nitric stack update -s aws
+
+# fail unless every function is serving requests within 5 minutes of the deploy, and every api and topic
+# is reachable
+nitric stack update -s aws --verify --verify-timeout 5m
+
+# record the deployed images, configuration and outputs, e.g. to attach to a release
+nitric stack update -s aws --save-manifest release/manifest.yaml
+
+# after a deploy fails, e.g. with a timeout, deploy again without gathering and building again
+nitric stack update -s aws --resume
+
+# deploy only the apis in the mocks of nitric.yaml, responding with the examples of their openapi files,
+# so frontends can integrate before the functions exist
+nitric stack update -s azure --mock
+
+# after changing only the environment or memory of functions, show the configuration diff and update them
+# with the deployed images, without building or pushing them
+nitric stack update -s aws --config-only`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		s, err := stack.ConfigFromOptions()
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
+
+		if membraneVersion != "" {
+			s.MembraneVersion = membraneVersion
+		}
+
+		config, err := project.ConfigFromFile(s)
+		if err != nil {
+			return err
+		}
 
-		config, err := project.ConfigFromFile()
-		cobra.CheckErr(err)
+		if mockApis {
+			return updateMocks(config, s)
+		}
 
 		proj, err := project.FromConfig(config)
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
+		if err := proj.FilterFunctions(s); err != nil {
+			return err
+		}
 
 		log.SetOutput(output.NewPtermWriter(pterm.Debug))
 
+		progress := newUpdateProgress(s.Name, proj.Dir)
+		noGather, noBuild := skipGather, skipBuild
+		if resumeUpdate {
+			progress, err = resumeFrom(s.Name, proj.Dir)
+			if err != nil {
+				return err
+			}
+			noGather, noBuild = progress.skips(skipGather, skipBuild)
+		}
+		// the deployed images are reused
+		reuseImages := noBuild || configOnly
+
 		envFiles := utils.FilesExisting(".env", ".env.production", envFile)
 		envMap := map[string]string{}
 		if len(envFiles) > 0 {
 			envMap, err = godotenv.Read(envFiles...)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 		}
 
-		codeAsConfig := tasklet.Runner{
-			StartMsg: "Gathering configuration from code..",
-			Runner: func(_ output.Progress) error {
-				proj, err = codeconfig.Populate(proj, envMap)
+		// fail fast on backend problems, gathering and building takes minutes
+		pre, err := newProvider(proj, s, envMap)
+		if err != nil {
+			return err
+		}
+		if err := pre.Preflight(); err != nil {
+			return err
+		}
+		if !reuseImages {
+			if err := build.Preflight(proj, containerengine.DeployPlatform); err != nil {
 				return err
-			},
-			StopMsg: "Configuration gathered",
+			}
 		}
-		tasklet.MustRun(codeAsConfig, tasklet.Opts{})
 
-		p, err := provider.NewProvider(proj, s, envMap)
-		cobra.CheckErr(err)
+		timings := tasklet.NewTimings()
+
+		if noGather {
+			proj, err = codeconfig.FromCache(proj)
+			if err != nil {
+				return err
+			}
+			// the cache may have been gathered for a stack with different functions
+			if err := proj.FilterFunctions(s); err != nil {
+				return err
+			}
+		} else {
+			codeAsConfig := tasklet.Runner{
+				StartMsg: i18n.T("gather.start"),
+				Runner: func(_ output.Progress) error {
+					proj, err = codeconfig.Populate(proj, envMap)
+					return err
+				},
+				StopMsg: i18n.T("gather.stop"),
+			}
+			if err := tasklet.Run(codeAsConfig, tasklet.Opts{Timings: timings, Stage: "gather"}); err != nil {
+				return progress.fail(proj.Dir, "gather", err)
+			}
+		}
+		if err := progress.done(proj.Dir, "gather"); err != nil {
+			return err
+		}
+
+		if createMissingTopics {
+			for _, t := range proj.CreateMissingTopics() {
+				pterm.Info.Printf("Creating topic %s, it is subscribed to but not declared\n", t)
+			}
+		}
+		if err := proj.ValidateTriggers(); err != nil {
+			return err
+		}
+
+		p, err := newProvider(proj, s, envMap)
+		if err != nil {
+			return err
+		}
+
+		resourceTimings := types.NewResourceTimings()
+		listeners := []types.EventListener{resourceTimings, progress}
+		if eventsWebhook != "" {
+			listeners = append(listeners, types.NewWebhookListener(eventsWebhook))
+		}
+		p.SetEventListener(types.NewMultiListener(listeners...))
+		p.SetDeleteData(deleteData)
+		p.SetConfigOnly(configOnly)
+
+		if fromPlan != "" {
+			plan, err := types.ReadPlan(fromPlan)
+			if err != nil {
+				return err
+			}
+			if plan.Stack != s.Name {
+				return fmt.Errorf("%s is a plan for stack %s, not %s", fromPlan, plan.Stack, s.Name)
+			}
+			p.SetPlan(plan)
+		}
+
+		lock, err := project.LockFromFile(proj.Dir)
+		if err != nil {
+			return err
+		}
+
+		lockedImages := map[string]string{}
+		if tl, ok := lock.Targets[s.Name]; ok && !updateLock {
+			if err := verifyLock(tl, p); err != nil {
+				return err
+			}
+			lockedImages = tl.Images
+		}
 
 		if err := p.TryPullImages(); err != nil {
 			pterm.Info.Print(err)
 		}
 
-		buildImages := tasklet.Runner{
-			StartMsg: "Building Images",
-			Runner: func(_ output.Progress) error {
-				return build.Create(proj, s)
-			},
-			StopMsg: "Images built",
+		warnQuotas(p)
+
+		if !reuseImages {
+			buildImages := tasklet.Runner{
+				StartMsg: i18n.T("build.start"),
+				Runner: func(_ output.Progress) error {
+					return build.Create(proj, s, lockedImages)
+				},
+				StopMsg: i18n.T("build.stop"),
+			}
+			if err := tasklet.Run(buildImages, tasklet.Opts{Timings: timings, Stage: "build"}); err != nil {
+				return progress.fail(proj.Dir, "build", err)
+			}
+		}
+		if err := progress.done(proj.Dir, "build"); err != nil {
+			return err
+		}
+
+		if configOnly {
+			if err := printConfigChanges(p); err != nil {
+				return progress.fail(proj.Dir, "deploy", err)
+			}
 		}
-		tasklet.MustRun(buildImages, tasklet.Opts{})
+
+		// the provider decrypts its copy of the stack, the manifest keeps the values encrypted
+		deployed := *s
 
 		d := &types.Deployment{}
 		deploy := tasklet.Runner{
-			StartMsg: "Deploying..",
+			StartMsg: i18n.T("deploy.start"),
 			Runner: func(progress output.Progress) error {
 				d, err = p.Up(progress)
 				return err
 			},
-			StopMsg: "Stack",
+			StopMsg: i18n.T("deploy.stop"),
+		}
+		if err := runAudited("stack update", config, s, deploy, tasklet.Opts{SuccessPrefix: "Deployed", Timings: timings, Stage: "deploy"}); err != nil {
+			return progress.fail(proj.Dir, "deploy", err)
+		}
+		if err := progress.clear(proj.Dir); err != nil {
+			pterm.Warning.Printf("unable to remove the progress of the update: %v\n", err)
 		}
-		tasklet.MustRun(deploy, tasklet.Opts{SuccessPrefix: "Deployed"})
 
-		rows := [][]string{{"API", "Endpoint"}}
-		for k, v := range d.ApiEndpoints {
-			rows = append(rows, []string{k, v})
+		printEndpoints(d)
+
+		if manifestFile != "" {
+			if err := newDeployManifest(proj, deployed, d, p.PluginVersions()).toFile(manifestFile); err != nil {
+				return err
+			}
+			pterm.Info.Println("Wrote the deployment manifest to", manifestFile)
+		}
+
+		if verifyFunctions {
+			verify := tasklet.Runner{
+				StartMsg: i18n.T("verify.start"),
+				Runner: func(progress output.Progress) error {
+					return verifyHealth(p, progress)
+				},
+				StopMsg: i18n.T("verify.stop"),
+			}
+			if err := tasklet.Run(verify, tasklet.Opts{Timings: timings, Stage: "verify"}); err != nil {
+				return err
+			}
+
+			checks := []smokeCheck{}
+			smoke := tasklet.Runner{
+				StartMsg: i18n.T("smoke.start"),
+				Runner: func(_ output.Progress) error {
+					checks = smokeTest(p, proj, d)
+					return nil
+				},
+				StopMsg: i18n.T("smoke.stop"),
+			}
+			if err := tasklet.Run(smoke, tasklet.Opts{Timings: timings, Stage: "smoke"}); err != nil {
+				return err
+			}
+			if err := printSmokeTest(checks); err != nil {
+				return err
+			}
+		}
+
+		if key := s.SigningKey(); key != "" {
+			signImages := tasklet.Runner{
+				StartMsg: i18n.T("sign.start"),
+				Runner: func(_ output.Progress) error {
+					return build.Sign(proj, key, d.Images)
+				},
+				StopMsg: i18n.T("sign.stop"),
+			}
+			if err := tasklet.Run(signImages, tasklet.Opts{Timings: timings, Stage: "sign"}); err != nil {
+				return err
+			}
+		}
+
+		if s.ApiClient != nil {
+			files, err := apiclient.Generate(s.ApiClient.Lang, s.ApiClient.Dir, proj.ApiDocs, d.ApiEndpoints)
+			if err != nil {
+				pterm.Warning.Printf("unable to generate the api clients: %v\n", err)
+			}
+			for _, f := range files {
+				pterm.Info.Println("Generated", f)
+			}
 		}
-		_ = pterm.DefaultTable.WithBoxed().WithData(rows).Render()
+
+		if _, ok := lock.Targets[s.Name]; !ok || updateLock {
+			if err := writeLock(lock, s.Name, proj, p); err != nil {
+				pterm.Warning.Printf("unable to update %s: %v\n", project.LockFile, err)
+			}
+		}
+
+		dt := newDeploymentTimings(timings, resourceTimings)
+		dt.print()
+		if timingsFile != "" {
+			if err := dt.toFile(timingsFile); err != nil {
+				return err
+			}
+		}
+		return nil
 	},
 	Args:    cobra.MinimumNArgs(0),
 	Aliases: []string{"up"},
 }
 
+// printEndpoints prints the endpoints of the deployed APIs, CDNs and sites.
+func printEndpoints(d *types.Deployment) {
+	if output.OutputTypeFlag.String() != "table" {
+		// e.g. -o markdown to post the endpoints in a pull request comment
+		endpoints := map[string]string{}
+		for k, v := range d.ApiEndpoints {
+			endpoints[k] = v
+		}
+		for k, v := range d.CdnEndpoints {
+			endpoints["cdn:"+k] = v
+		}
+		for k, v := range d.SiteEndpoints {
+			endpoints["site:"+k] = v
+		}
+		output.Print(endpoints)
+		return
+	}
+
+	rows := [][]string{{"API", "Endpoint"}}
+	for k, v := range d.ApiEndpoints {
+		rows = append(rows, []string{k, v})
+	}
+	for k, v := range d.CdnEndpoints {
+		rows = append(rows, []string{"cdn:" + k, v})
+	}
+	for k, v := range d.SiteEndpoints {
+		rows = append(rows, []string{"site:" + k, v})
+	}
+	_ = pterm.DefaultTable.WithBoxed().WithData(rows).Render()
+}
+
 var stackDeleteCmd = &cobra.Command{
 	Use:   "down [-s stack]",
 	Short: "Undeploy a previously deployed stack, deleting resources",
@@ -168,42 +518,68 @@ var stackDeleteCmd = &cobra.Command{
 	Example: `nitric stack down -s aws
 
 # To not be prompted, use -y
-nitric stack down -e aws -y`,
-	Run: func(cmd *cobra.Command, args []string) {
+nitric stack down -e aws -y
+
+# Buckets, collections and secrets are protected, to delete them and their data use --delete-data
+nitric stack down -s aws --delete-data
+
+# Buckets that hold files can't be deleted, to delete the files first use --empty-buckets
+nitric stack down -s aws --delete-data --empty-buckets`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if emptyBuckets && !deleteData {
+			return errors.New("--empty-buckets deletes the files in the buckets, it must be used with --delete-data")
+		}
+
 		if !confirmDown {
 			confirm := ""
 			err := survey.AskOne(&survey.Select{
-				Message: "Warning - This operation will destroy your stack, all deployed resources will be removed. Are you sure you want to proceed?",
+				Message: i18n.T("stack.down.confirm"),
 				Default: "No",
 				Options: []string{"Yes", "No"},
 			}, &confirm)
-			cobra.CheckErr(err)
+			if err != nil {
+				return err
+			}
 			if confirm != "Yes" {
 				pterm.Info.Println("Cancelling command")
-				os.Exit(0)
+				return nil
 			}
 		}
 
 		s, err := stack.ConfigFromOptions()
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 
-		config, err := project.ConfigFromFile()
-		cobra.CheckErr(err)
+		config, err := project.ConfigFromFile(s)
+		if err != nil {
+			return err
+		}
 
 		proj, err := project.FromConfig(config)
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
+
+		p, err := newProvider(proj, s, map[string]string{})
+		if err != nil {
+			return err
+		}
 
-		p, err := provider.NewProvider(proj, s, map[string]string{})
-		cobra.CheckErr(err)
+		if eventsWebhook != "" {
+			p.SetEventListener(types.NewWebhookListener(eventsWebhook))
+		}
+		p.SetDeleteData(deleteData)
+		p.SetEmptyBuckets(emptyBuckets)
 
 		deploy := tasklet.Runner{
-			StartMsg: "Deleting..",
+			StartMsg: i18n.T("delete.start"),
 			Runner: func(progress output.Progress) error {
 				return p.Down(progress)
 			},
-			StopMsg: "Stack",
+			StopMsg: i18n.T("delete.stop"),
 		}
-		tasklet.MustRun(deploy, tasklet.Opts{
+		return runAudited("stack down", config, s, deploy, tasklet.Opts{
 			SuccessPrefix: "Deleted",
 		})
 	},
@@ -213,45 +589,393 @@ nitric stack down -e aws -y`,
 var stackListCmd = &cobra.Command{
 	Use:   "list [-s stack]",
 	Short: "List all project stacks and their status",
-	Long:  `List all project stacks and their status`,
+	Long: `List all project stacks and their status.
+
+With --all-targets the stacks of every target configured in the project are listed together.`,
 	Example: `nitric stack list
 
 nitric stack list -s aws
+
+nitric stack list --all-targets -o json
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if allTargets {
+			deps, err := listAllTargets()
+			if err != nil {
+				return err
+			}
+
+			output.Print(deps)
+			return nil
+		}
+
 		s, err := stack.ConfigFromOptions()
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 
-		config, err := project.ConfigFromFile()
-		cobra.CheckErr(err)
+		config, err := project.ConfigFromFile(s)
+		if err != nil {
+			return err
+		}
 
 		proj, err := project.FromConfig(config)
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 
-		p, err := provider.NewProvider(proj, s, map[string]string{})
-		cobra.CheckErr(err)
+		p, err := newProvider(proj, s, map[string]string{})
+		if err != nil {
+			return err
+		}
 
 		deps, err := p.List()
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 
 		output.Print(deps)
+		return nil
 	},
 	Args:    cobra.ExactArgs(0),
 	Aliases: []string{"ls"},
 }
 
+var stackEnvCmd = &cobra.Command{
+	Use:   "env [function] [-s stack]",
+	Short: "Print the environment a function will receive when deployed",
+	Long: `Print the environment a function will receive when deployed.
+
+This merges the provider injected values with your env files, and notes where each value comes from.`,
+	Example: `nitric stack env hello -s aws
+
+nitric stack env hello -s aws -e config/.my-env -o json
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := stack.ConfigFromOptions()
+		if err != nil {
+			return err
+		}
+
+		config, err := project.ConfigFromFile(s)
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.FromConfig(config)
+		if err != nil {
+			return err
+		}
+		if err := proj.FilterFunctions(s); err != nil {
+			return err
+		}
+
+		envFiles := utils.FilesExisting(".env", ".env.production", envFile)
+		envMap := map[string]string{}
+		if len(envFiles) > 0 {
+			envMap, err = godotenv.Read(envFiles...)
+			if err != nil {
+				return err
+			}
+		}
+
+		codeAsConfig := tasklet.Runner{
+			StartMsg: i18n.T("gather.start"),
+			Runner: func(_ output.Progress) error {
+				proj, err = codeconfig.Populate(proj, envMap)
+				return err
+			},
+			StopMsg: i18n.T("gather.stop"),
+		}
+		if err := tasklet.Run(codeAsConfig, tasklet.Opts{}); err != nil {
+			return err
+		}
+
+		p, err := newProvider(proj, s, envMap)
+		if err != nil {
+			return err
+		}
+
+		env, err := p.Env(args[0])
+		if err != nil {
+			return err
+		}
+
+		output.Print(env)
+		return nil
+	},
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names, err := project.FunctionNames()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	},
+}
+
+var stackLogsCmd = &cobra.Command{
+	Use:   "logs --deploy [-s stack]",
+	Short: "Replay the engine events of the last deployment",
+	Long: `Replay the engine events of the last deployment.
+
+The events of each update and down are recorded in .nitric/history, so a failed deployment
+can be investigated after the fact without running it again.`,
+	Example: `nitric stack logs --deploy -s aws
+
+# Save the raw events as JSON lines, e.g. as a CI artifact
+nitric stack logs --deploy -s aws --export deploy-events.jsonl`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !logsDeploy {
+			return errors.New("only deployment logs are currently supported, use --deploy")
+		}
+
+		s, err := stack.ConfigFromOptions()
+		if err != nil {
+			return err
+		}
+
+		config, err := project.ConfigFromFile(s)
+		if err != nil {
+			return err
+		}
+
+		file, err := pulumi.LastDeployLog(config.Dir, s.Name)
+		if err != nil {
+			return err
+		}
+
+		if exportFile != "" {
+			b, err := ioutil.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			if err := ioutil.WriteFile(exportFile, b, 0644); err != nil {
+				return err
+			}
+			pterm.Success.Printf("Exported %s to %s\n", filepath.Base(file), exportFile)
+			return nil
+		}
+
+		replay := tasklet.Runner{
+			StartMsg: i18n.T("replay.start", filepath.Base(file)),
+			Runner: func(progress output.Progress) error {
+				return pulumi.ReplayDeployLog(file, progress)
+			},
+			StopMsg: i18n.T("replay.stop", filepath.Base(file)),
+		}
+		return tasklet.Run(replay, tasklet.Opts{})
+	},
+	Args: cobra.ExactArgs(0),
+}
+
+var stackDiffCmd = &cobra.Command{
+	Use:   "diff [stackA] [stackB]",
+	Short: "Compare the configuration of two stacks",
+	Long: `Compare the configuration of two stacks.
+
+The resolved stack configuration and the project resources each stack deploys are compared setting by setting,
+e.g. to see how staging differs from prod before promoting changes. What is currently deployed is not compared.`,
+	Example: `nitric stack diff staging prod
+
+nitric stack diff staging prod -o json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		diffs, err := diffStacks(args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		if len(diffs) == 0 {
+			pterm.Info.Printf("Stacks %s and %s have the same configuration\n", args[0], args[1])
+			return nil
+		}
+		output.Print(diffs)
+		return nil
+	},
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 1 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names, err := stack.Names()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		// a stack isn't compared with itself
+		stacks := []string{}
+		for _, n := range names {
+			if len(args) == 0 || n != args[0] {
+				stacks = append(stacks, n)
+			}
+		}
+		return stacks, cobra.ShellCompDirectiveNoFileComp
+	},
+}
+
+var stackSleepCmd = &cobra.Command{
+	Use:   "sleep [-s stack]",
+	Short: "Scale the always running compute of a deployed stack to zero",
+	Long: `Scale the always running compute of a deployed stack to zero.
+
+Services and functions with a minScale stop running until the stack is woken up with "nitric stack wake",
+or until the next wake schedule when the stack has one.`,
+	Example: `nitric stack sleep -s aws`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return sleepStack(true)
+	},
+	Args: cobra.ExactArgs(0),
+}
+
+var stackWakeCmd = &cobra.Command{
+	Use:     "wake [-s stack]",
+	Short:   "Scale the always running compute of a deployed stack back up",
+	Long:    `Scale the always running compute of a deployed stack back up to its minScale.`,
+	Example: `nitric stack wake -s aws`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return sleepStack(false)
+	},
+	Args: cobra.ExactArgs(0),
+}
+
+func sleepStack(sleeping bool) error {
+	s, err := stack.ConfigFromOptions()
+	if err != nil {
+		return err
+	}
+
+	config, err := project.ConfigFromFile(s)
+	if err != nil {
+		return err
+	}
+
+	proj, err := project.FromConfig(config)
+	if err != nil {
+		return err
+	}
+
+	p, err := newProvider(proj, s, map[string]string{})
+	if err != nil {
+		return err
+	}
+
+	command, startMsg, stopMsg := "stack wake", "Waking up..", "Stack woken up"
+	if sleeping {
+		command, startMsg, stopMsg = "stack sleep", "Going to sleep..", "Stack asleep"
+	}
+	return runAudited(command, config, s, tasklet.Runner{
+		StartMsg: startMsg,
+		Runner: func(_ output.Progress) error {
+			return p.Sleep(sleeping)
+		},
+		StopMsg: stopMsg,
+	}, tasklet.Opts{})
+}
+
 func RootCommand() *cobra.Command {
+	stackCmd.PersistentFlags().BoolVar(&readOnly, "read-only", false, "fail before changing the stack, so it can be listed and inspected with credentials that can only read it")
+	stackCmd.PersistentFlags().BoolVar(&showSecrets, "show-secrets", false, "print the outputs marked as secret, e.g. connection strings, instead of masking them")
+
 	stackCmd.AddCommand(newStackCmd)
 
+	stackCmd.AddCommand(stackCloneCmd)
+	cobra.CheckErr(stack.AddOptions(stackCloneCmd, false))
+
 	stackCmd.AddCommand(stackUpdateCmd)
 	cobra.CheckErr(stack.AddOptions(stackUpdateCmd, false))
 	stackUpdateCmd.Flags().StringVarP(&envFile, "env-file", "e", "", "--env-file config/.my-env")
+	stackUpdateCmd.Flags().StringVar(&eventsWebhook, "events-webhook", "", "POST deployment progress events as JSON to this URL")
+	stackUpdateCmd.Flags().StringVar(&timingsFile, "timings-file", "", "write the time taken by each stage and resource as JSON to this file")
+	stackUpdateCmd.Flags().BoolVar(&updateLock, "update-lock", false, "deploy with the current plugins and base images and record them in nitric.lock")
+	stackUpdateCmd.Flags().StringVar(&membraneVersion, "membrane-version", "", "build the functions with this nitric membrane release (e.g. v0.16.0 or latest) instead of the stack's")
+	stackUpdateCmd.Flags().BoolVar(&skipBuild, "skip-build", false, "deploy the images from the last build instead of building them")
+	stackUpdateCmd.Flags().BoolVar(&skipGather, "skip-gather", false, "use the configuration gathered from code by the last run instead of gathering it again")
+	stackUpdateCmd.Flags().BoolVar(&resumeUpdate, "resume", false, "continue the last update of the stack from the stage it failed at, skipping the gather and build stages that completed")
+	stackUpdateCmd.Flags().BoolVar(&deleteData, "delete-data", false, "allow buckets, collections and secrets to be replaced or removed, deleting their data")
+	stackUpdateCmd.Flags().StringVar(&fromPlan, "from-plan", "", "apply the changes saved by stack preview --save-plan, failing if the stack would now make different changes")
+	stackUpdateCmd.Flags().BoolVar(&createMissingTopics, "create-missing-topics", false, "create the topics that functions subscribe to but are not declared")
+	stackUpdateCmd.Flags().BoolVar(&verifyFunctions, "verify", false, "fail unless every function is serving requests after the deploy, then call the root route of each api and publish a message with nitricVerify set to each topic, failing if any is unreachable")
+	stackUpdateCmd.Flags().DurationVar(&verifyTimeout, "verify-timeout", 5*time.Minute, "how long --verify waits for the functions to serve requests")
+	stackUpdateCmd.Flags().BoolVar(&overrideLock, "override-lock", false, "update the stack even though it is locked with nitric stack lock")
+	stackUpdateCmd.Flags().BoolVar(&mockApis, "mock", false, "deploy only the apis in the mocks of nitric.yaml, responding with the examples of their openapi files, without any functions")
+	stackUpdateCmd.Flags().BoolVar(&configOnly, "config-only", false, "update only the environment and resources of the functions with their deployed images, failing if anything else would change")
+	stackUpdateCmd.Flags().StringVar(&manifestFile, "save-manifest", "", "write the deployed images and their digests, the resolved stack, its outputs and the plugin versions as YAML to this file")
+
+	stackCmd.AddCommand(stackWatchCmd)
+	cobra.CheckErr(stack.AddOptions(stackWatchCmd, false))
+	stackWatchCmd.Flags().StringVarP(&envFile, "env-file", "e", "", "--env-file config/.my-env")
+	stackWatchCmd.Flags().DurationVar(&watchDebounce, "debounce", 2*time.Second, "wait until the files have been unchanged for this long before updating")
+
+	stackCmd.AddCommand(stackSizeCmd)
+	cobra.CheckErr(stack.AddOptions(stackSizeCmd, false))
+	stackSizeCmd.Flags().StringVarP(&envFile, "env-file", "e", "", "--env-file config/.my-env")
+	stackSizeCmd.Flags().BoolVar(&skipBuild, "skip-build", false, "report the sizes of the images from the last build instead of building them")
+	stackSizeCmd.Flags().BoolVar(&skipGather, "skip-gather", false, "use the configuration gathered from code by the last run instead of gathering it again")
+	stackSizeCmd.Flags().BoolVar(&skipPreview, "skip-preview", false, "don't count the resources the update would create, which needs access to the stack")
+
+	stackCmd.AddCommand(stackPreviewCmd)
+	cobra.CheckErr(stack.AddOptions(stackPreviewCmd, false))
+	stackPreviewCmd.Flags().StringVarP(&envFile, "env-file", "e", "", "--env-file config/.my-env")
+	stackPreviewCmd.Flags().BoolVar(&securityOnly, "security", false, "list only the changes to identities and permissions, e.g. roles, policies and role assignments")
+	stackPreviewCmd.Flags().BoolVar(&skipBuild, "skip-build", false, "preview with the images from the last build instead of building them")
+	stackPreviewCmd.Flags().StringVar(&savePlan, "save-plan", "", "write the changes to this file, to apply them later with stack update --from-plan")
+	stackPreviewCmd.Flags().BoolVar(&configOnly, "config-only", false, "preview only the changes to the environment and resources of the functions, with their deployed images")
+	stackPreviewCmd.Flags().BoolVar(&skipGather, "skip-gather", false, "use the configuration gathered from code by the last run instead of gathering it again")
 
 	stackCmd.AddCommand(stackDeleteCmd)
 	stackDeleteCmd.Flags().BoolVarP(&confirmDown, "yes", "y", false, "confirm the destruction of the stack")
+	stackDeleteCmd.Flags().StringVar(&eventsWebhook, "events-webhook", "", "POST deployment progress events as JSON to this URL")
+	stackDeleteCmd.Flags().BoolVar(&deleteData, "delete-data", false, "also delete buckets, collections and secrets, and all the data they hold")
+	stackDeleteCmd.Flags().BoolVar(&overrideLock, "override-lock", false, "delete the stack even though it is locked with nitric stack lock")
+	stackDeleteCmd.Flags().BoolVar(&emptyBuckets, "empty-buckets", false, "delete the files in every bucket so the buckets can be deleted, requires --delete-data")
 	cobra.CheckErr(stack.AddOptions(stackDeleteCmd, false))
 
+	stackCmd.AddCommand(stackRevisionsCmd)
+	cobra.CheckErr(stack.AddOptions(stackRevisionsCmd, false))
+	cobra.CheckErr(pflagext.AddStringMapVarP(stackRevisionsCmd, &revisionTraffic, "traffic", "", validateWeight, nil, "the percentage of requests a revision gets, e.g. latest=10, can be repeated"))
+
 	stackCmd.AddCommand(stackListCmd)
 	cobra.CheckErr(stack.AddOptions(stackListCmd, false))
+	stackListCmd.Flags().BoolVar(&allTargets, "all-targets", false, "list the stacks of every configured target")
+
+	stackCmd.AddCommand(stackLogsCmd)
+	cobra.CheckErr(stack.AddOptions(stackLogsCmd, false))
+	stackLogsCmd.Flags().BoolVar(&logsDeploy, "deploy", false, "show the engine events of the last update or down")
+	stackLogsCmd.Flags().StringVar(&exportFile, "export", "", "write the raw engine events as JSON lines to this file instead")
+
+	stackCmd.AddCommand(stackEnvCmd)
+	cobra.CheckErr(stack.AddOptions(stackEnvCmd, false))
+	stackEnvCmd.Flags().StringVarP(&envFile, "env-file", "e", "", "--env-file config/.my-env")
+
+	stackCmd.AddCommand(stackDiffCmd)
+
+	stackCmd.AddCommand(stackSleepCmd)
+	cobra.CheckErr(stack.AddOptions(stackSleepCmd, false))
+
+	stackCmd.AddCommand(stackWakeCmd)
+	cobra.CheckErr(stack.AddOptions(stackWakeCmd, false))
+
+	stackCmd.AddCommand(stackLockCmd)
+	cobra.CheckErr(stack.AddOptions(stackLockCmd, false))
+	stackLockCmd.Flags().StringVar(&lockReason, "reason", "", "why the stack is locked, shown to anyone updating or deleting it")
+	cobra.CheckErr(stackLockCmd.MarkFlagRequired("reason"))
+
+	stackCmd.AddCommand(stackUnlockCmd)
+	cobra.CheckErr(stack.AddOptions(stackUnlockCmd, false))
+
+	stackCmd.AddCommand(stackEncryptCmd)
+	cobra.CheckErr(stack.AddOptions(stackEncryptCmd, false))
+
+	stackCmd.AddCommand(stackOutputsCmd)
+	cobra.CheckErr(stack.AddOptions(stackOutputsCmd, false))
+	stackOutputsCmd.Flags().BoolVar(&watchOutputs, "watch", false, "poll the custom domains until they are all validated")
+	stackOutputsCmd.Flags().DurationVar(&outputsInterval, "interval", 30*time.Second, "how often the custom domains are polled with --watch")
+
+	stackCmd.AddCommand(stackGcCmd)
+	cobra.CheckErr(stack.AddOptions(stackGcCmd, false))
+	stackGcCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "list the orphaned resources without deleting them")
+	stackGcCmd.Flags().BoolVarP(&confirmGc, "yes", "y", false, "delete the orphaned resources without being prompted")
 	return stackCmd
 }
@@ -0,0 +1,287 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/joho/godotenv"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/build"
+	"github.com/nitrictech/cli/pkg/codeconfig"
+	"github.com/nitrictech/cli/pkg/containerengine"
+	"github.com/nitrictech/cli/pkg/i18n"
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/tasklet"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// largeImageMB is the image size that is warned about, large images are slow to push and to cold start.
+const largeImageMB = 500
+
+// defaultMaxScale is the most instances of a compute unit without a maxScale, as the providers deploy them.
+const defaultMaxScale = 10
+
+var skipPreview bool
+
+type functionSize struct {
+	Function string  `yaml:"function" json:"function"`
+	ImageMB  float64 `yaml:"imageMB" json:"imageMB"`
+	MemoryMB int     `yaml:"memoryMB" json:"memoryMB"`
+	Cpu      float64 `yaml:"cpu" json:"cpu"`
+	MaxScale int     `yaml:"maxScale" json:"maxScale"`
+}
+
+type stackSize struct {
+	Functions      []functionSize `yaml:"functions" json:"functions"`
+	ImageMB        float64        `yaml:"imageMB" json:"imageMB"`
+	MaxMemoryMB    int            `yaml:"maxMemoryMB" json:"maxMemoryMB"`
+	MaxCpu         float64        `yaml:"maxCpu" json:"maxCpu"`
+	Resources      map[string]int `yaml:"resources,omitempty" json:"resources,omitempty"`
+	ResourcesTotal int            `yaml:"resourcesTotal,omitempty" json:"resourcesTotal,omitempty"`
+}
+
+var stackSizeCmd = &cobra.Command{
+	Use:   "size [-s stack]",
+	Short: "Report the size of the images, compute and resources of a stack",
+	Long: `Report the size of the image built for each function, the memory and vCPUs of its instances and
+the totals when every function is scaled to its maxScale, and the number of each type of cloud resource
+the next update would create.
+
+The project is gathered and built as it is for an update, the resources are counted from a preview of it.
+The memory and vCPUs of functions without a compute class are the defaults of the provider's compute, on
+AWS a Lambda's share of a vCPU is proportional to its memory.`,
+	Example: `nitric stack size -s aws
+
+# report on the last build, without counting the resources
+nitric stack size -s aws --skip-build --skip-preview
+
+nitric stack size -s gcp -o json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := stack.ConfigFromOptions()
+		if err != nil {
+			return err
+		}
+
+		config, err := project.ConfigFromFile(s)
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.FromConfig(config)
+		if err != nil {
+			return err
+		}
+		if err := proj.FilterFunctions(s); err != nil {
+			return err
+		}
+
+		envFiles := utils.FilesExisting(".env", ".env.production", envFile)
+		envMap := map[string]string{}
+		if len(envFiles) > 0 {
+			envMap, err = godotenv.Read(envFiles...)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !skipBuild {
+			if err := build.Preflight(proj, containerengine.DeployPlatform); err != nil {
+				return err
+			}
+		}
+
+		if skipGather {
+			proj, err = codeconfig.FromCache(proj)
+			if err != nil {
+				return err
+			}
+			if err := proj.FilterFunctions(s); err != nil {
+				return err
+			}
+		} else {
+			codeAsConfig := tasklet.Runner{
+				StartMsg: i18n.T("gather.start"),
+				Runner: func(_ output.Progress) error {
+					proj, err = codeconfig.Populate(proj, envMap)
+					return err
+				},
+				StopMsg: i18n.T("gather.stop"),
+			}
+			if err := tasklet.Run(codeAsConfig, tasklet.Opts{}); err != nil {
+				return err
+			}
+		}
+
+		if !skipBuild {
+			buildImages := tasklet.Runner{
+				StartMsg: i18n.T("build.start"),
+				Runner: func(_ output.Progress) error {
+					return build.Create(proj, s, nil)
+				},
+				StopMsg: i18n.T("build.stop"),
+			}
+			if err := tasklet.Run(buildImages, tasklet.Opts{}); err != nil {
+				return err
+			}
+		}
+
+		images, err := build.ImageSizes(proj, s.Provider)
+		if err != nil {
+			return err
+		}
+		size := newStackSize(proj, s.Provider, images)
+
+		if !skipPreview {
+			p, err := newProvider(proj, s, envMap)
+			if err != nil {
+				return err
+			}
+
+			changes := []types.Change{}
+			preview := tasklet.Runner{
+				StartMsg: "Previewing..",
+				Runner: func(progress output.Progress) error {
+					changes, err = p.Preview(progress)
+					return err
+				},
+				StopMsg: "Previewed",
+			}
+			if err := tasklet.Run(preview, tasklet.Opts{}); err != nil {
+				return err
+			}
+			size.countCreates(changes)
+		}
+
+		if output.OutputTypeFlag.String() != "table" {
+			output.Print(size)
+			return nil
+		}
+		return size.print()
+	},
+	Args: cobra.ExactArgs(0),
+}
+
+// newStackSize returns the image sizes and compute of each of the project's functions, images are keyed by function.
+func newStackSize(proj *project.Project, provider string, images map[string]int64) *stackSize {
+	size := &stackSize{Functions: []functionSize{}}
+	for _, c := range proj.Computes() {
+		u := c.Unit()
+		memory, cpu := instanceSize(provider, u)
+		f := functionSize{
+			Function: u.Name,
+			ImageMB:  float64(images[u.Name]) / 1024 / 1024,
+			MemoryMB: memory,
+			Cpu:      cpu,
+			MaxScale: u.MaxScale,
+		}
+		if f.MaxScale <= 0 {
+			f.MaxScale = defaultMaxScale
+		}
+
+		size.Functions = append(size.Functions, f)
+		size.ImageMB += f.ImageMB
+		size.MaxMemoryMB += f.MemoryMB * f.MaxScale
+		size.MaxCpu += f.Cpu * float64(f.MaxScale)
+	}
+	sort.Slice(size.Functions, func(i, j int) bool {
+		return size.Functions[i].Function < size.Functions[j].Function
+	})
+	return size
+}
+
+// instanceSize returns the memory in MB and vCPUs of an instance of the compute unit, those of its class or
+// the defaults of the compute it is deployed to.
+func instanceSize(provider string, u *project.ComputeUnit) (int, float64) {
+	if class := u.ComputeClass(); class != nil {
+		return class.Memory, class.Cpu
+	}
+
+	switch provider {
+	case stack.Aws:
+		if u.AlwaysOn {
+			// fargate tasks have a quarter of a vCPU per 512MB
+			memory := u.MemoryOrDefault(512)
+			return memory, float64(memory) / 2048
+		}
+		// lambdas have a vCPU per 1769MB
+		memory := u.MemoryOrDefault(128)
+		return memory, float64(memory) / 1769
+	case stack.Azure:
+		// container apps without a class have the default resources, the memory is not set on its own
+		return 1024, 0.5
+	default:
+		return u.MemoryOrDefault(512), 1
+	}
+}
+
+// countCreates counts the resources of each type the changes create.
+func (s *stackSize) countCreates(changes []types.Change) {
+	s.Resources = map[string]int{}
+	for _, c := range changes {
+		if c.Op == "create" {
+			s.Resources[c.Type]++
+			s.ResourcesTotal++
+		}
+	}
+}
+
+// print renders the functions and resources as tables, warning about the large images.
+func (s *stackSize) print() error {
+	rows := [][]string{{"Function", "Image", "Memory", "vCPU", "Max scale"}}
+	for _, f := range s.Functions {
+		rows = append(rows, []string{f.Function, fmt.Sprintf("%.0fMB", f.ImageMB), fmt.Sprintf("%dMB", f.MemoryMB), fmt.Sprintf("%.2f", f.Cpu), strconv.Itoa(f.MaxScale)})
+	}
+	rows = append(rows, []string{"Total at max scale", fmt.Sprintf("%.0fMB", s.ImageMB), fmt.Sprintf("%dMB", s.MaxMemoryMB), fmt.Sprintf("%.2f", s.MaxCpu), ""})
+	if err := pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(rows).Render(); err != nil {
+		return err
+	}
+
+	for _, f := range s.Functions {
+		if f.ImageMB > largeImageMB {
+			pterm.Warning.Printf("the image of %s is %.0fMB, large images are slow to push and to start\n", f.Function, f.ImageMB)
+		}
+	}
+
+	if s.Resources == nil {
+		return nil
+	}
+	if s.ResourcesTotal == 0 {
+		pterm.Info.Println("The update would not create any resources")
+		return nil
+	}
+
+	resourceTypes := []string{}
+	for t := range s.Resources {
+		resourceTypes = append(resourceTypes, t)
+	}
+	sort.Strings(resourceTypes)
+
+	rows = [][]string{{"Resource type", "Created"}}
+	for _, t := range resourceTypes {
+		rows = append(rows, []string{t, strconv.Itoa(s.Resources[t])})
+	}
+	rows = append(rows, []string{"Total", strconv.Itoa(s.ResourcesTotal)})
+	return pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(rows).Render()
+}
@@ -0,0 +1,90 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package project
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/tasklet"
+)
+
+// maxResourceTimings limits the resources shown in the summary to the slowest ones.
+const maxResourceTimings = 10
+
+type deploymentTimings struct {
+	Stages    []tasklet.Timing `json:"stages"`
+	Resources []tasklet.Timing `json:"resources"`
+}
+
+func newDeploymentTimings(stages *tasklet.Timings, resources *types.ResourceTimings) *deploymentTimings {
+	t := &deploymentTimings{
+		Stages:    stages.Stages(),
+		Resources: []tasklet.Timing{},
+	}
+
+	// images are pushed by the deploy stage, so report the time they took separately.
+	var push time.Duration
+	for name, elapsed := range resources.Elapsed() {
+		if strings.HasPrefix(name, "Image/") {
+			push += elapsed
+		}
+		t.Resources = append(t.Resources, tasklet.Timing{Name: name, Elapsed: elapsed})
+	}
+	if push > 0 {
+		t.Stages = append(t.Stages, tasklet.Timing{Name: "push (during deploy)", Elapsed: push})
+	}
+
+	sort.SliceStable(t.Resources, func(i, j int) bool {
+		return t.Resources[i].Elapsed > t.Resources[j].Elapsed
+	})
+	return t
+}
+
+func (t *deploymentTimings) print() {
+	rows := [][]string{{"Stage", "Duration"}}
+	for _, s := range t.Stages {
+		rows = append(rows, []string{s.Name, s.Elapsed.Round(time.Second).String()})
+	}
+	_ = pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(rows).Render()
+
+	if len(t.Resources) == 0 {
+		return
+	}
+
+	rows = [][]string{{"Slowest resources", "Duration"}}
+	for i, r := range t.Resources {
+		if i == maxResourceTimings {
+			break
+		}
+		rows = append(rows, []string{r.Name, r.Elapsed.Round(time.Second).String()})
+	}
+	_ = pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(rows).Render()
+}
+
+func (t *deploymentTimings) toFile(file string) error {
+	b, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, b, 0644)
+}
@@ -0,0 +1,190 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var (
+	verifyFunctions bool
+	verifyTimeout   time.Duration
+)
+
+// verifyInterval is how often the functions are checked until they are all serving.
+const verifyInterval = 10 * time.Second
+
+// verifyHealth checks the deployed functions until they are all serving requests, failing with the
+// reasons of those that are not when the timeout passes.
+func verifyHealth(p types.Provider, log output.Progress) error {
+	deadline := time.Now().Add(verifyTimeout)
+	for {
+		hs, err := p.Health()
+		if err != nil {
+			return err
+		}
+
+		failing := unhealthy(hs)
+		if len(failing) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			reasons := []string{}
+			for _, h := range failing {
+				reasons = append(reasons, h.Function+": "+h.Reason)
+			}
+			return fmt.Errorf("the stack was deployed but these functions are not serving after %s, fix them and update the stack again\n%s", verifyTimeout, strings.Join(reasons, "\n"))
+		}
+
+		log.Progressf(len(hs)-len(failing), len(hs), "waiting for the functions to serve requests")
+		time.Sleep(verifyInterval)
+	}
+}
+
+// unhealthy returns the functions that are not serving requests.
+func unhealthy(hs []types.FunctionHealth) []types.FunctionHealth {
+	failing := []types.FunctionHealth{}
+	for _, h := range hs {
+		if !h.Healthy {
+			failing = append(failing, h)
+		}
+	}
+	return failing
+}
+
+const (
+	smokePassed  = "pass"
+	smokeFailed  = "fail"
+	smokeSkipped = "skipped"
+)
+
+// smokeTimeout is how long a request to a deployed api may take.
+const smokeTimeout = 30 * time.Second
+
+// smokeCheck is the result of calling a deployed api or publishing to a deployed topic.
+type smokeCheck struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Result string `json:"result"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// smokeTest calls the root route of each deployed api and publishes a verify message to each topic,
+// returning a check for each. An api is reachable when it responds without a server error, the root
+// route need not exist.
+func smokeTest(p types.Provider, proj *project.Project, d *types.Deployment) []smokeCheck {
+	checks := []smokeCheck{}
+	client := &http.Client{Timeout: smokeTimeout}
+
+	apis := []string{}
+	for name := range d.ApiEndpoints {
+		apis = append(apis, name)
+	}
+	sort.Strings(apis)
+	for _, name := range apis {
+		c := smokeCheck{Kind: "api", Name: name, Result: smokePassed}
+		status, err := getStatus(client, d.ApiEndpoints[name])
+		switch {
+		case err != nil:
+			c.Result, c.Detail = smokeFailed, err.Error()
+		case status >= http.StatusInternalServerError:
+			c.Result, c.Detail = smokeFailed, fmt.Sprintf("%s responded %d", d.ApiEndpoints[name], status)
+		default:
+			c.Detail = fmt.Sprintf("%s responded %d", d.ApiEndpoints[name], status)
+		}
+		checks = append(checks, c)
+	}
+
+	topics := []string{}
+	for name := range proj.Topics {
+		topics = append(topics, name)
+	}
+	sort.Strings(topics)
+	for _, name := range topics {
+		c := smokeCheck{Kind: "topic", Name: name}
+		if proj.Topics[name].Schema != "" {
+			c.Result, c.Detail = smokeSkipped, "the verify message would not match the topic's schema"
+			checks = append(checks, c)
+			continue
+		}
+
+		id, err := p.Publish(name, map[string]interface{}{"nitricVerify": true, "time": time.Now().UTC().Format(time.RFC3339)})
+		_, notSupported := err.(*utils.NotSupportedError)
+		switch {
+		case notSupported:
+			c.Result, c.Detail = smokeSkipped, err.Error()
+		case err != nil:
+			c.Result, c.Detail = smokeFailed, err.Error()
+		default:
+			c.Result, c.Detail = smokePassed, "published "+id
+		}
+		checks = append(checks, c)
+	}
+
+	return checks
+}
+
+// getStatus returns the status of a GET of the url.
+func getStatus(client *http.Client, url string) (int, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// printSmokeTest prints the checks, returning an error when any failed.
+func printSmokeTest(checks []smokeCheck) error {
+	if len(checks) == 0 {
+		return nil
+	}
+
+	if output.OutputTypeFlag.String() != "table" {
+		output.Print(checks)
+	} else {
+		rows := [][]string{{"Kind", "Name", "Result", "Detail"}}
+		for _, c := range checks {
+			rows = append(rows, []string{c.Kind, c.Name, c.Result, c.Detail})
+		}
+		if err := pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(rows).Render(); err != nil {
+			return err
+		}
+	}
+
+	failed := []string{}
+	for _, c := range checks {
+		if c.Result == smokeFailed {
+			failed = append(failed, c.Kind+" "+c.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("the stack was deployed but these are unreachable: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
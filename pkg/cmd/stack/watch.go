@@ -0,0 +1,182 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package project
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/build"
+	"github.com/nitrictech/cli/pkg/codeconfig"
+	"github.com/nitrictech/cli/pkg/containerengine"
+	"github.com/nitrictech/cli/pkg/i18n"
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/tasklet"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var watchDebounce time.Duration
+
+var stackWatchCmd = &cobra.Command{
+	Use:   "watch [-s stack]",
+	Short: "Update a dev stack each time the project changes",
+	Long: `Update a dev stack each time the project changes, for developing against cloud resources.
+
+The stack is updated when the command starts, then the project directory is watched and the stack is
+gathered, built and updated again once the changed files have been left alone for the debounce time.
+A failed update is reported and the next change is waited for.
+
+Only stacks with "dev: true" in their stack file can be watched, so a production stack is never
+updated by saving a file.`,
+	Example: `nitric stack watch -s dev
+
+# Wait for the files to settle for longer, e.g. while switching branches
+nitric stack watch -s dev --debounce 10s`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := stack.ConfigFromOptions()
+		if err != nil {
+			return err
+		}
+
+		if !s.Dev {
+			return fmt.Errorf("stack %s is not a dev stack, set dev: true in its stack file to watch it", s.Name)
+		}
+
+		config, err := project.ConfigFromFile(s)
+		if err != nil {
+			return err
+		}
+
+		if err := watchUpdate(s); err != nil {
+			printWatchErr(err)
+		}
+
+		stop := make(chan struct{})
+		term := make(chan os.Signal, 1)
+		signal.Notify(term, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-term
+			close(stop)
+		}()
+
+		pterm.Info.Printf("Watching %s for changes, press Ctrl+C to stop\n", config.Dir)
+		w := utils.NewWatcher(config.Dir, watchDebounce)
+		return w.Watch(stop, func(files []string) {
+			pterm.Info.Printf("%d files changed, e.g. %s, updating stack %s\n", len(files), files[0], s.Name)
+			if err := watchUpdate(s); err != nil {
+				printWatchErr(err)
+			}
+		})
+	},
+	Args: cobra.ExactArgs(0),
+}
+
+// watchUpdate gathers, builds and updates the stack like stack update, returning rather than exiting on
+// errors so the watch continues. The project and env files are read again as they may have changed.
+func watchUpdate(s *stack.Config) error {
+	config, err := project.ConfigFromFile(s)
+	if err != nil {
+		return err
+	}
+
+	proj, err := project.FromConfig(config)
+	if err != nil {
+		return err
+	}
+	if err := proj.FilterFunctions(s); err != nil {
+		return err
+	}
+
+	envFiles := utils.FilesExisting(".env", ".env.development", envFile)
+	envMap := map[string]string{}
+	if len(envFiles) > 0 {
+		envMap, err = godotenv.Read(envFiles...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := build.Preflight(proj, containerengine.DeployPlatform); err != nil {
+		return err
+	}
+
+	codeAsConfig := tasklet.Runner{
+		StartMsg: i18n.T("gather.start"),
+		Runner: func(_ output.Progress) error {
+			proj, err = codeconfig.Populate(proj, envMap)
+			return err
+		},
+		StopMsg: i18n.T("gather.stop"),
+	}
+	if err := tasklet.Run(codeAsConfig, tasklet.Opts{}); err != nil {
+		return err
+	}
+	if err := proj.ValidateTriggers(); err != nil {
+		return err
+	}
+
+	p, err := newProvider(proj, s, envMap)
+	if err != nil {
+		return err
+	}
+
+	buildImages := tasklet.Runner{
+		StartMsg: i18n.T("build.start"),
+		Runner: func(_ output.Progress) error {
+			return build.Create(proj, s, nil)
+		},
+		StopMsg: i18n.T("build.stop"),
+	}
+	if err := tasklet.Run(buildImages, tasklet.Opts{}); err != nil {
+		return err
+	}
+
+	d := &types.Deployment{}
+	deploy := tasklet.Runner{
+		StartMsg: i18n.T("deploy.start"),
+		Runner: func(progress output.Progress) error {
+			d, err = p.Up(progress)
+			return err
+		},
+		StopMsg: i18n.T("deploy.stop"),
+	}
+	if err := tasklet.Run(deploy, tasklet.Opts{SuccessPrefix: "Deployed"}); err != nil {
+		return err
+	}
+
+	printEndpoints(d)
+	return nil
+}
+
+// printWatchErr prints an update failure that hasn't already been shown by its tasklet.
+func printWatchErr(err error) {
+	var exitErr *utils.ExitError
+	if errors.As(err, &exitErr) && exitErr.Reported {
+		return
+	}
+	pterm.Error.Println(err)
+}
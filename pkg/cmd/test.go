@@ -0,0 +1,238 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/build"
+	"github.com/nitrictech/cli/pkg/codeconfig"
+	"github.com/nitrictech/cli/pkg/containerengine"
+	"github.com/nitrictech/cli/pkg/i18n"
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/run"
+	"github.com/nitrictech/cli/pkg/tasklet"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var (
+	testEnvFile string
+	testTimeout time.Duration
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Test a project locally",
+	Long:  `Test a project locally.`,
+}
+
+var testTriggersCmd = &cobra.Command{
+	Use:   "triggers",
+	Short: "Invoke each handler with a sample trigger and check it responds",
+	Long: `Invoke each handler with a sample trigger and check it responds.
+
+The functions are started locally, as with 'nitric run', then a request is sent to each api
+operation, a message to each subscribed topic and an event for each schedule.
+Api requests fail when the handler responds with a server error, messages and events
+fail when a subscriber does not ack. No cloud access is needed, so this can run in CI.`,
+	Example: `nitric test triggers
+
+nitric test triggers --timeout 2m`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		failed, err := testTriggers()
+		if err != nil {
+			return err
+		}
+		if failed > 0 {
+			// the failures have been printed
+			return utils.NewExitErr(1, nil)
+		}
+		return nil
+	},
+	Args: cobra.ExactArgs(0),
+}
+
+func testCommand() *cobra.Command {
+	testTriggersCmd.Flags().StringVarP(&testEnvFile, "env-file", "e", "", "--env-file config/.my-env")
+	testTriggersCmd.Flags().DurationVar(&testTimeout, "timeout", time.Minute, "how long to wait for the handlers to register")
+	testCmd.AddCommand(testTriggersCmd)
+	return testCmd
+}
+
+// testTriggers starts the project locally and invokes each contract trigger, returning the number that failed.
+func testTriggers() (int, error) {
+	config, err := project.ConfigFromFile(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	proj, err := project.FromConfig(config)
+	if err != nil {
+		return 0, err
+	}
+
+	envFiles := utils.FilesExisting(".env", ".env.development", testEnvFile)
+	envMap := map[string]string{}
+	if len(envFiles) > 0 {
+		envMap, err = godotenv.Read(envFiles...)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	codeAsConfig := tasklet.Runner{
+		StartMsg: i18n.T("gather.start"),
+		Runner: func(_ output.Progress) error {
+			proj, err = codeconfig.Populate(proj, envMap)
+			return err
+		},
+		StopMsg: i18n.T("gather.stop"),
+	}
+	if err := tasklet.Run(codeAsConfig, tasklet.Opts{}); err != nil {
+		return 0, err
+	}
+	if err := proj.ValidateTriggers(); err != nil {
+		return 0, err
+	}
+
+	contractTriggers, err := run.ContractTriggers(proj)
+	if err != nil {
+		return 0, err
+	}
+	if len(contractTriggers) == 0 {
+		pterm.Info.Println("The project has no apis, subscriptions or schedules to test")
+		return 0, nil
+	}
+
+	ls := run.NewLocalServices(proj)
+	if ls.Running() {
+		return 0, errors.New("only one instance of Nitric can be run locally at a time, please stop 'nitric run' and try again")
+	}
+
+	ce, err := containerengine.Discover()
+	if err != nil {
+		return 0, err
+	}
+
+	logger := ce.Logger(proj.Dir)
+	if err := logger.Start(); err != nil {
+		return 0, err
+	}
+	defer func() { _ = logger.Stop() }()
+
+	createBaseImage := tasklet.Runner{
+		StartMsg: "Creating Dev Image",
+		Runner: func(_ output.Progress) error {
+			return build.CreateBaseDev(proj, "")
+		},
+		StopMsg: "Created Dev Image!",
+	}
+	if err := tasklet.Run(createBaseImage, tasklet.Opts{}); err != nil {
+		return 0, err
+	}
+
+	memerr := make(chan error, 1)
+	pool := run.NewRunProcessPool()
+	go func() {
+		memerr <- ls.Start(pool)
+	}()
+	defer func() {
+		if ls.Running() {
+			_ = ls.Stop()
+		}
+	}()
+
+	var functions []*run.Function
+	defer func() {
+		for _, f := range functions {
+			_ = f.Stop()
+		}
+	}()
+
+	startFunctions := tasklet.Runner{
+		StartMsg: "Starting Functions",
+		Runner: func(progress output.Progress) error {
+			for !ls.Running() {
+				select {
+				case err := <-memerr:
+					return err
+				default:
+				}
+				progress.Busyf("Waiting for Local Services to be ready")
+				time.Sleep(time.Second)
+			}
+
+			functions, err = run.FunctionsFromHandlers(proj, ls.Status())
+			if err != nil {
+				return err
+			}
+			for _, f := range functions {
+				if err := f.Start(envMap); err != nil {
+					return err
+				}
+			}
+
+			deadline := time.Now().Add(testTimeout)
+			for {
+				waiting := 0
+				for _, t := range contractTriggers {
+					if !t.Ready(pool) {
+						waiting++
+					}
+				}
+				if waiting == 0 {
+					return nil
+				}
+				if time.Now().After(deadline) {
+					return fmt.Errorf("%d triggers have no handler registered after %v", waiting, testTimeout)
+				}
+				progress.Busyf("Waiting for handlers of %d triggers to register", waiting)
+				time.Sleep(time.Second)
+			}
+		},
+		StopMsg: "Started Functions!",
+	}
+	if err := tasklet.Run(startFunctions, tasklet.Opts{}); err != nil {
+		return 0, err
+	}
+
+	tableData := pterm.TableData{{"Kind", "Trigger", "Result"}}
+	failed := 0
+	for _, t := range contractTriggers {
+		result := pterm.Green("ok")
+		if err := t.Invoke(pool); err != nil {
+			failed++
+			result = pterm.Red(err.Error())
+		}
+		tableData = append(tableData, []string{string(t.Kind), t.Name, result})
+	}
+	_ = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+
+	if failed > 0 {
+		pterm.Error.Printf("%d of %d triggers failed\n", failed, len(contractTriggers))
+	} else {
+		pterm.Success.Printf("All %d triggers succeeded\n", len(contractTriggers))
+	}
+	return failed, nil
+}
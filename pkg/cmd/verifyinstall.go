@@ -0,0 +1,197 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/nitrictech/cli/pkg/containerengine"
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/provider/pulumi"
+	"github.com/nitrictech/cli/pkg/templates"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+const (
+	checkPassed  = "pass"
+	checkFailed  = "fail"
+	checkSkipped = "skipped"
+)
+
+// installCheck is the result of checking one of the dependencies of the cli.
+type installCheck struct {
+	Check  string `json:"check"`
+	Result string `json:"result"`
+	Detail string `json:"detail,omitempty"`
+}
+
+var verifyInstallCmd = &cobra.Command{
+	Use:   "verify-install",
+	Short: "Check everything nitric depends on is installed and reachable",
+	Long: `Check everything nitric depends on is installed and reachable, printing whether each check passed.
+
+The container engine must be running, the pulumi cli must be on the PATH, the pulumi plugins of
+every provider are installed, downloading those that are missing, a template is downloaded
+and the directories nitric writes to must be writable. Nothing needs a project, so this can run
+straight after installing nitric with a package manager. It exits with 1 when a check fails.`,
+	Example: `nitric verify-install
+
+# skip the downloads, checking the plugins and the list of templates are cached
+nitric verify-install --offline`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		checks := verifyInstall()
+
+		if output.OutputTypeFlag.String() != "table" {
+			output.Print(checks)
+		} else {
+			rows := [][]string{{"Check", "Result", "Detail"}}
+			for _, c := range checks {
+				rows = append(rows, []string{c.Check, c.Result, c.Detail})
+			}
+			if err := pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(rows).Render(); err != nil {
+				return err
+			}
+		}
+
+		for _, c := range checks {
+			if c.Result == checkFailed {
+				// the failures have been printed
+				return utils.NewExitErr(1, nil)
+			}
+		}
+		return nil
+	},
+	Args: cobra.ExactArgs(0),
+}
+
+// verifyInstall runs every check, rather than stopping at the first failure, so a machine missing several
+// dependencies can be fixed in one go.
+func verifyInstall() []installCheck {
+	checks := []installCheck{checkContainerEngine()}
+
+	pulumiCheck := checkPulumi()
+	checks = append(checks, pulumiCheck)
+	for _, plug := range pulumi.Plugins() {
+		c := installCheck{Check: "pulumi plugin " + plug.Name}
+		if pulumiCheck.Result == checkPassed {
+			c.Result, c.Detail = result(pulumi.InstallPlugin(plug), plug.Version)
+		} else {
+			c.Result, c.Detail = checkSkipped, "needs the pulumi cli"
+		}
+		checks = append(checks, c)
+	}
+
+	checks = append(checks, checkTemplates())
+
+	dirs := []struct{ name, dir string }{
+		{"temp dir", os.TempDir()},
+		{"config dir", utils.NitricConfigDir()},
+		{"templates dir", utils.NitricTemplatesDir()},
+		{"run dir", utils.NitricRunDir()},
+	}
+	for _, d := range dirs {
+		c := installCheck{Check: d.name}
+		c.Result, c.Detail = result(writable(d.dir), d.dir)
+		checks = append(checks, c)
+	}
+
+	return checks
+}
+
+// result returns the result and detail of a check that failed with err.
+func result(err error, detail string) (string, string) {
+	if err != nil {
+		return checkFailed, err.Error()
+	}
+	return checkPassed, detail
+}
+
+func checkContainerEngine() installCheck {
+	c := installCheck{Check: "container engine"}
+	ce, err := containerengine.Discover()
+	if err != nil {
+		c.Result, c.Detail = checkFailed, "docker or podman must be installed and running: "+err.Error()
+		return c
+	}
+	if _, err := ce.Info(); err != nil {
+		c.Result, c.Detail = checkFailed, fmt.Sprintf("%s is not responding: %v", ce.Type(), err)
+		return c
+	}
+	c.Result, c.Detail = checkPassed, ce.Type()+" "+ce.Version()
+	return c
+}
+
+func checkPulumi() installCheck {
+	c := installCheck{Check: "pulumi cli"}
+	out, err := exec.Command("pulumi", "version").Output()
+	if err != nil {
+		c.Result, c.Detail = checkFailed, "install pulumi from https://www.pulumi.com/docs/get-started/install/: "+err.Error()
+		return c
+	}
+	c.Result, c.Detail = checkPassed, strings.TrimSpace(string(out))
+	return c
+}
+
+// checkTemplates lists the templates and downloads the first, which needs git, unless nitric is offline.
+func checkTemplates() installCheck {
+	c := installCheck{Check: "templates"}
+	d := templates.NewDownloader()
+	names, err := d.Names()
+	if err != nil {
+		c.Result, c.Detail = checkFailed, err.Error()
+		return c
+	}
+	if len(names) == 0 {
+		c.Result, c.Detail = checkFailed, "the list of templates is empty"
+		return c
+	}
+	if utils.Offline {
+		c.Result, c.Detail = checkSkipped, fmt.Sprintf("%d templates are cached, downloading them needs network access", len(names))
+		return c
+	}
+
+	tmp, err := ioutil.TempDir("", "nitric-verify-")
+	if err != nil {
+		c.Result, c.Detail = checkFailed, err.Error()
+		return c
+	}
+	defer os.RemoveAll(tmp)
+
+	c.Result, c.Detail = result(d.DownloadDirectoryContents(names[0], filepath.Join(tmp, "template"), false), "downloaded "+names[0])
+	return c
+}
+
+// writable checks a file can be created in dir, creating dir when it doesn't exist.
+func writable(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(dir, ".nitric-verify-")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
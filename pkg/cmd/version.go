@@ -29,11 +29,12 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the version number of this CLI",
 	Long:  `All software has versions. This is Nitric's`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("Go Version: %s\n", runtime.Version())
-		fmt.Printf("Go OS/Arch: %s/%s\n", runtime.GOOS, runtime.GOARCH)
-		fmt.Printf("Git commit: %s\n", utils.Commit)
-		fmt.Printf("Build time: %s\n", utils.BuildTime)
-		fmt.Printf("Nitric CLI: %s\n", utils.Version)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Fprintf(cmd.OutOrStdout(), "Go Version: %s\n", runtime.Version())
+		fmt.Fprintf(cmd.OutOrStdout(), "Go OS/Arch: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+		fmt.Fprintf(cmd.OutOrStdout(), "Git commit: %s\n", utils.Commit)
+		fmt.Fprintf(cmd.OutOrStdout(), "Build time: %s\n", utils.BuildTime)
+		fmt.Fprintf(cmd.OutOrStdout(), "Nitric CLI: %s\n", utils.Version)
+		return nil
 	},
 }
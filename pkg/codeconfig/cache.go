@@ -0,0 +1,78 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package codeconfig
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// cacheFile holds the configuration from the last successful gather, so that it can be reused
+// when nothing relevant has changed.
+func cacheFile(dir string) string {
+	return filepath.Join(utils.NitricLogDir(dir), "codeconfig.json")
+}
+
+func saveCache(p *project.Project) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(utils.NitricLogDir(p.Dir), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(cacheFile(p.Dir), b, 0644)
+}
+
+// FromCache returns the configuration gathered by the last Populate, instead of running the functions again.
+func FromCache(initial *project.Project) (*project.Project, error) {
+	b, err := ioutil.ReadFile(cacheFile(initial.Dir))
+	if os.IsNotExist(err) {
+		return nil, errors.New("no previously gathered configuration found, please run without skipping the gather stage first")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p := &project.Project{}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, errors.WithMessage(err, "reading "+cacheFile(initial.Dir))
+	}
+	// settings from nitric.yaml are always current
+	p.Dir = initial.Dir
+	p.Sites = initial.Sites
+	p.Workflows = initial.Workflows
+	p.Databases = initial.Databases
+	p.Caches = initial.Caches
+	p.Emails = initial.Emails
+	p.Build = initial.Build
+	if p.Topics == nil {
+		p.Topics = map[string]project.Topic{}
+	}
+	for k, t := range initial.Topics {
+		p.Topics[k] = t
+	}
+
+	return p, nil
+}
@@ -19,11 +19,13 @@ package codeconfig
 import (
 	"bytes"
 	"fmt"
-	"log"
 	"net"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"regexp"
 	osruntime "runtime"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -36,6 +38,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/pterm/pterm"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/nitrictech/cli/pkg/build"
 	"github.com/nitrictech/cli/pkg/containerengine"
@@ -43,6 +46,7 @@ import (
 	"github.com/nitrictech/cli/pkg/output"
 	"github.com/nitrictech/cli/pkg/project"
 	"github.com/nitrictech/cli/pkg/runtime"
+	"github.com/nitrictech/cli/pkg/settings"
 	"github.com/nitrictech/cli/pkg/utils"
 	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
 )
@@ -59,6 +63,13 @@ type codeConfig struct {
 	initialProject *project.Project
 	envMap         map[string]string
 	lock           sync.RWMutex
+	// run the handlers on the host when their toolchain is installed
+	native bool
+	server settings.CollectServer
+	// hashes are of the sources of each function, cached are the functions replayed from the last
+	// gather because their sources have not changed
+	hashes map[string]string
+	cached map[string]*FunctionDependencies
 }
 
 func New(p *project.Project, envMap map[string]string) (CodeConfig, error) {
@@ -67,6 +78,8 @@ func New(p *project.Project, envMap map[string]string) (CodeConfig, error) {
 		functions:      map[string]*FunctionDependencies{},
 		lock:           sync.RWMutex{},
 		envMap:         envMap,
+		native:         settings.Get().NativeCollect,
+		server:         settings.Get().CollectServer,
 	}, nil
 }
 
@@ -76,17 +89,48 @@ func Populate(initial *project.Project, envMap map[string]string) (*project.Proj
 		return nil, err
 	}
 
-	err = build.CreateBaseDev(initial)
+	c := cc.(*codeConfig)
+	c.useCache()
+
+	// the dev images are only needed by the handlers that can't run on the host
+	if c.needsContainers() {
+		err = build.CreateBaseDev(initial, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = cc.Collect()
 	if err != nil {
 		return nil, err
 	}
 
-	err = cc.Collect()
+	p, err := cc.ToProject()
 	if err != nil {
 		return nil, err
 	}
 
-	return cc.ToProject()
+	if c.hashes != nil {
+		if err := saveCachedHandlers(initial.Dir, c.hashes, c.functions); err != nil {
+			pterm.Debug.Println("unable to cache the gathered handlers:", err)
+		}
+	}
+	return p, saveCache(p)
+}
+
+// useCache replays the functions whose sources have not changed since they were last gathered,
+// so only the changed handlers are run.
+func (c *codeConfig) useCache() {
+	hashes, err := handlerHashes(c.initialProject, c.envMap)
+	if err != nil {
+		pterm.Debug.Println("gathering every handler:", err)
+		return
+	}
+	c.hashes = hashes
+	c.cached = loadCachedHandlers(c.initialProject.Dir, hashes)
+	if len(c.cached) > 0 {
+		pterm.Debug.Printf("reusing the configuration of %d unchanged handlers\n", len(c.cached))
+	}
 }
 
 // Collect - Collects information about all functions for a nitric project
@@ -95,6 +139,11 @@ func (c *codeConfig) Collect() error {
 	errList := utils.NewErrorList()
 
 	for _, f := range c.initialProject.Functions {
+		if fun, ok := c.cached[f.Name]; ok {
+			c.addFunction(fun, f.Name)
+			continue
+		}
+
 		wg.Add(1)
 
 		// run files in parallel
@@ -106,7 +155,7 @@ func (c *codeConfig) Collect() error {
 				return
 			}
 
-			err = c.collectOne(rel)
+			err = c.collectOne(fn.Name, rel)
 			if err != nil {
 				errList.Add(err)
 				return
@@ -141,15 +190,11 @@ func (c *codeConfig) apiSpec(api string) (*openapi3.T, error) {
 	workers := make([]*apiHandler, 0)
 
 	// Collect all workers
-	for handler, f := range c.functions {
-		rt, err := runtime.NewRunTimeFromHandler(handler)
-		if err != nil {
-			return nil, err
-		}
+	for name, f := range c.functions {
 		if f.apis[api] != nil {
 			for _, w := range f.apis[api].workers {
 				workers = append(workers, &apiHandler{
-					target: rt.ContainerName(),
+					target: name,
 					worker: w,
 				})
 			}
@@ -195,12 +240,12 @@ func (c *codeConfig) apiSpec(api string) (*openapi3.T, error) {
 		}
 	}
 
-	if output.VerboseLevel > 3 {
+	if output.VerboseLevel > output.VerboseDebug {
 		b, err := doc.MarshalJSON()
 		if err != nil {
 			return nil, err
 		}
-		fmt.Println("discovered api doc", string(b))
+		pterm.Debug.Println("discovered api doc", string(b))
 	}
 	return doc, nil
 }
@@ -248,15 +293,11 @@ func useHostInterface(hc *container.HostConfig, iface string, port int) ([]strin
 	if err != nil {
 		return nil, err
 	}
-	fmt.Println("dockerInternalAddr ", dockerInternalAddr)
+	pterm.Debug.Println("dockerInternalAddr ", dockerInternalAddr)
 
 	hc.NetworkMode = "host"
 
-	return []string{
-		fmt.Sprintf("SERVICE_ADDRESS=%s:%d", dockerInternalAddr, port),
-		fmt.Sprintf("NITRIC_SERVICE_PORT=%d", port),
-		fmt.Sprintf("NITRIC_SERVICE_HOST=%s", dockerInternalAddr),
-	}, nil
+	return serviceEnv(dockerInternalAddr, port), nil
 }
 
 func useDockerInternal(hc *container.HostConfig, port int) []string {
@@ -266,31 +307,78 @@ func useDockerInternal(hc *container.HostConfig, port int) []string {
 		hc.ExtraHosts = []string{"host.docker.internal:172.17.0.1"}
 	}
 
+	return serviceEnv(containerengine.CLIHost(), port)
+}
+
+// serviceEnv tells the sdk where the server is.
+func serviceEnv(host string, port int) []string {
 	return []string{
-		fmt.Sprintf("SERVICE_ADDRESS=%s:%d", "host.docker.internal", port),
+		fmt.Sprintf("SERVICE_ADDRESS=%s", net.JoinHostPort(host, strconv.Itoa(port))),
 		fmt.Sprintf("NITRIC_SERVICE_PORT=%d", port),
-		fmt.Sprintf("NITRIC_SERVICE_HOST=%s", "host.docker.internal"),
+		fmt.Sprintf("NITRIC_SERVICE_HOST=%s", host),
+	}
+}
+
+// maxPortAttempts is how many ports from the configured port are tried.
+const maxPortAttempts = 100
+
+// listen binds to the first free port from port, so the handlers gathered at the same time, by
+// this or another process, each get their own. A port of 0 binds a random port.
+func listen(host string, port int) (net.Listener, error) {
+	if port == 0 {
+		return net.Listen("tcp", net.JoinHostPort(host, "0"))
+	}
+
+	var err error
+	for p := port; p < port+maxPortAttempts; p++ {
+		var lis net.Listener
+		lis, err = net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(p)))
+		if err == nil {
+			return lis, nil
+		}
 	}
+	return nil, errors.WithMessagef(err, "no free port from %d", port)
+}
+
+// serverOptions serves TLS when a certificate is configured.
+func (c *codeConfig) serverOptions() ([]grpc.ServerOption, error) {
+	if c.server.TLSCert == "" && c.server.TLSKey == "" {
+		return nil, nil
+	}
+	if c.server.TLSCert == "" || c.server.TLSKey == "" {
+		return nil, errors.New("collect_server needs both a tls_cert and a tls_key")
+	}
+
+	creds, err := credentials.NewServerTLSFromFile(c.server.TLSCert, c.server.TLSKey)
+	if err != nil {
+		return nil, errors.WithMessage(err, "loading the collect_server certificate")
+	}
+	return []grpc.ServerOption{grpc.Creds(creds)}, nil
 }
 
 // collectOne - Collects information about a function for a nitric stack
+// name - the name of the function, several functions can share a handler
 // handler - the specific handler for the application
-func (c *codeConfig) collectOne(handler string) error {
+func (c *codeConfig) collectOne(name, handler string) error {
 	rt, err := runtime.NewRunTimeFromHandler(handler)
 	if err != nil {
 		return errors.WithMessage(err, "error getting the runtime from handler "+handler)
 	}
 
-	name := rt.ContainerName()
 	fun := NewFunction(name)
 
+	srvOpts, err := c.serverOptions()
+	if err != nil {
+		return err
+	}
+
 	srv := NewServer(name, fun)
-	grpcSrv := grpc.NewServer()
+	grpcSrv := grpc.NewServer(srvOpts...)
 
 	v1.RegisterResourceServiceServer(grpcSrv, srv)
 	v1.RegisterFaasServiceServer(grpcSrv, srv)
 
-	lis, err := net.Listen("tcp", ":0")
+	lis, err := listen(c.server.Host, c.server.Port)
 	if err != nil {
 		return err
 	}
@@ -304,6 +392,83 @@ func (c *codeConfig) collectOne(handler string) error {
 		errChan <- grpcSrv.Serve(lis)
 	}(errChan)
 
+	errs := utils.NewErrorList().WithSubject(name)
+	if cmd := c.nativeCommand(rt); cmd != nil {
+		errs.Add(c.runNative(name, cmd, port))
+	} else {
+		errs.Add(c.runContainer(name, rt, port))
+	}
+
+	// When the handler exits stop the server
+	grpcSrv.Stop()
+	errs.Add(<-errChan)
+
+	// Add the function
+	c.addFunction(fun, name)
+	return errs.Aggregate()
+}
+
+// nativeCommand returns the command to run the handler on the host with, or nil when it has to
+// run in a container.
+func (c *codeConfig) nativeCommand(rt runtime.Runtime) []string {
+	if !c.native {
+		return nil
+	}
+	cmd, err := rt.NativeCommandForFunctionCollect(c.initialProject.Dir)
+	if err != nil {
+		pterm.Debug.Println(err, "- falling back to a container")
+		return nil
+	}
+	return cmd
+}
+
+// needsContainers is true when any of the handlers has to run in a container.
+func (c *codeConfig) needsContainers() bool {
+	for _, f := range c.initialProject.Functions {
+		if _, ok := c.cached[f.Name]; ok {
+			continue
+		}
+		rt, err := runtime.NewRunTimeFromHandler(f.Handler)
+		if err != nil || c.nativeCommand(rt) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// runNative runs the handler on the host until it exits.
+func (c *codeConfig) runNative(name string, command []string, port int) error {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = c.initialProject.Dir
+	host := "127.0.0.1"
+	if c.server.Address != "" {
+		host = c.server.Address
+	}
+	cmd.Env = append(os.Environ(), serviceEnv(host, port)...)
+	// this is to tell the sdk that we are running in the build and not proper runtime.
+	cmd.Env = append(cmd.Env, "NITRIC_ENVIRONMENT=build")
+	for k, v := range c.envMap {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	pterm.Debug.Println(name, strings.Join(command, " "))
+
+	logWriter := output.VerboseWriter(output.VerboseDiagnostics)
+	logRW := &bytes.Buffer{}
+	if !output.Verbose(output.VerboseDiagnostics) {
+		logWriter = logRW
+	}
+	cmd.Stdout = logWriter
+	cmd.Stderr = logWriter
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error executing %s (%v) %s", filepath.Base(command[0]), err, strings.TrimSpace(logRW.String()))
+	}
+	return nil
+}
+
+// runContainer runs the handler in the runtime's dev container until it exits.
+func (c *codeConfig) runContainer(name string, rt runtime.Runtime, port int) error {
 	// run the handler in a container
 	// Specify the service bind as the port with the docker gateway IP (running in bridge mode)
 	ce, err := containerengine.Discover()
@@ -318,11 +483,13 @@ func (c *codeConfig) collectOne(handler string) error {
 
 	hostConfig := &container.HostConfig{
 		AutoRemove: true,
-		Mounts:     opts.Mounts,
+		Mounts:     containerengine.HostMounts(opts.Mounts),
 	}
 
 	var env []string
-	if os.Getenv("HOST_DOCKER_INTERNAL_IFACE") != "" {
+	if c.server.Address != "" {
+		env = serviceEnv(c.server.Address, port)
+	} else if os.Getenv("HOST_DOCKER_INTERNAL_IFACE") != "" {
 		env, err = useHostInterface(hostConfig, os.Getenv("HOST_DOCKER_INTERNAL_IFACE"), port)
 	} else {
 		env = useDockerInternal(hostConfig, port)
@@ -348,11 +515,11 @@ func (c *codeConfig) collectOne(handler string) error {
 		WorkingDir:   opts.TargetWD,
 	}
 
-	if output.VerboseLevel > 2 {
+	if output.Verbose(output.VerboseDebug) {
 		pterm.Debug.Println(containerengine.Cli(cc, hostConfig))
 	}
 
-	cn := strings.Join([]string{c.initialProject.Name, "codeAsConfig", rt.ContainerName()}, "-")
+	cn := strings.Join([]string{c.initialProject.Name, "codeAsConfig", name}, "-")
 	cID, err := ce.ContainerCreate(cc, hostConfig, &network.NetworkingConfig{
 		EndpointsConfig: map[string]*network.EndpointSettings{},
 	}, cn)
@@ -374,9 +541,9 @@ func (c *codeConfig) collectOne(handler string) error {
 		return err
 	}
 
-	logWriter := log.Writer()
+	logWriter := output.VerboseWriter(output.VerboseDiagnostics)
 	logRW := &bytes.Buffer{}
-	if output.VerboseLevel <= 1 {
+	if !output.Verbose(output.VerboseDiagnostics) {
 		// if we are running in non-verbose then store the container logs in a buffer in case
 		// there are errors.
 		logWriter = logRW
@@ -385,7 +552,6 @@ func (c *codeConfig) collectOne(handler string) error {
 		_, _ = stdcopy.StdCopy(logWriter, logWriter, logreader)
 	}()
 
-	errs := utils.NewErrorList().WithSubject(handler)
 	waitChan, cErrChan := ce.ContainerWait(cID, container.WaitConditionNextExit)
 	select {
 	case done := <-waitChan:
@@ -403,26 +569,19 @@ func (c *codeConfig) collectOne(handler string) error {
 			}
 		}
 		if done.StatusCode != 0 {
-			errs.Add(fmt.Errorf("error executing in container (code %d) %s", done.StatusCode, msg))
+			return fmt.Errorf("error executing in container (code %d) %s", done.StatusCode, msg)
 		}
 	case cErr := <-cErrChan:
-		errs.Add(cErr)
+		return cErr
 	}
-
-	// When the container exits stop the server
-	grpcSrv.Stop()
-	errs.Add(<-errChan)
-
-	// Add the function
-	c.addFunction(fun, handler)
-	return errs.Aggregate()
+	return nil
 }
 
-func (c *codeConfig) addFunction(fun *FunctionDependencies, handler string) {
+func (c *codeConfig) addFunction(fun *FunctionDependencies, name string) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	c.functions[handler] = fun
+	c.functions[name] = fun
 }
 
 func (c *codeConfig) ToProject() (*project.Project, error) {
@@ -434,7 +593,7 @@ func (c *codeConfig) ToProject() (*project.Project, error) {
 	}
 
 	errs := utils.NewErrorList()
-	for handler, f := range c.functions {
+	for name, f := range c.functions {
 		topicTriggers := make([]string, 0, len(f.subscriptions)+len(f.schedules))
 
 		for k := range f.apis {
@@ -463,9 +622,8 @@ func (c *codeConfig) ToProject() (*project.Project, error) {
 
 		for k, v := range f.schedules {
 			// Create a new topic target
-			// replace spaced with hyphens
-			topicName := strings.ToLower(strings.ReplaceAll(k, " ", "-"))
-			s.Topics[topicName] = project.Topic{}
+			topicName := project.ScheduleTopic(k)
+			s.AddTopic(topicName)
 
 			topicTriggers = append(topicTriggers, topicName)
 
@@ -511,32 +669,39 @@ func (c *codeConfig) ToProject() (*project.Project, error) {
 		}
 
 		for k := range f.topics {
-			s.Topics[k] = project.Topic{}
+			s.AddTopic(k)
 		}
 
+		// subscriptions to topics that are not declared are reported by ValidateTriggers,
+		// as the topic may be declared by another function
 		for k := range f.subscriptions {
-			if _, ok := f.topics[k]; !ok {
-				errs.Add(fmt.Errorf("subscription to topic %s defined, but topic does not exist", k))
-			} else {
-				topicTriggers = append(topicTriggers, k)
-			}
+			topicTriggers = append(topicTriggers, k)
 		}
 
-		fun, ok := s.Functions[f.name]
+		fun, ok := s.Functions[name]
 		if !ok {
-			fun, err = project.FunctionFromHandler(handler, s.Dir)
-			if err != nil {
-				errs.Add(fmt.Errorf("can not create function from %s %w", handler, err))
-				continue
-			}
-		}
-		fun.ComputeUnit.Triggers = project.Triggers{
-			Topics: topicTriggers,
+			errs.Add(fmt.Errorf("function %s is not in the project", name))
+			continue
 		}
+		// keep the triggers declared in nitric.yaml
+		fun.ComputeUnit.Triggers.Topics = mergeTopics(fun.ComputeUnit.Triggers.Topics, topicTriggers)
 		// set the functions worker count
 		fun.WorkerCount = f.WorkerCount()
-		s.Functions[f.name] = fun
+		s.Functions[name] = fun
 	}
 
 	return s, errs.Aggregate()
 }
+
+// mergeTopics returns the topics in both lists, without duplicates.
+func mergeTopics(declared, found []string) []string {
+	seen := map[string]bool{}
+	topics := []string{}
+	for _, t := range append(append([]string{}, declared...), found...) {
+		if !seen[t] {
+			seen[t] = true
+			topics = append(topics, t)
+		}
+	}
+	return topics
+}
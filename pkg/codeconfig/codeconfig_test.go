@@ -17,6 +17,7 @@
 package codeconfig
 
 import (
+	"net"
 	"reflect"
 	"testing"
 
@@ -99,3 +100,34 @@ func Test_splitPath(t *testing.T) {
 		})
 	}
 }
+
+func Test_listen(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+
+	port := busy.Addr().(*net.TCPAddr).Port
+
+	lis, err := listen("127.0.0.1", port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	if got := lis.Addr().(*net.TCPAddr).Port; got <= port || got >= port+maxPortAttempts {
+		t.Errorf("listen() port = %d, want the next free port after %d", got, port)
+	}
+}
+
+func Test_serviceEnv(t *testing.T) {
+	want := []string{
+		"SERVICE_ADDRESS=host.docker.internal:50051",
+		"NITRIC_SERVICE_PORT=50051",
+		"NITRIC_SERVICE_HOST=host.docker.internal",
+	}
+	if got := serviceEnv("host.docker.internal", 50051); !reflect.DeepEqual(got, want) {
+		t.Errorf("serviceEnv() = %v, want %v", got, want)
+	}
+}
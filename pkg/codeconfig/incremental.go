@@ -0,0 +1,300 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeconfig
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/utils"
+	pb "github.com/nitrictech/nitric/pkg/api/nitric/v1"
+)
+
+// handlerCacheFile holds what each handler declared when it was last gathered, so the handlers whose
+// sources have not changed since are not run again.
+func handlerCacheFile(dir string) string {
+	return filepath.Join(utils.NitricLogDir(dir), "handlers.json")
+}
+
+// declaration is a resource or worker declared by a handler, as the message the handler sent.
+type declaration struct {
+	Kind    string          `json:"kind"`
+	Name    string          `json:"name,omitempty"`
+	Message json.RawMessage `json:"message"`
+}
+
+type cachedHandler struct {
+	// Hash is of the sources the handler was gathered from
+	Hash         string        `json:"hash"`
+	Declarations []declaration `json:"declarations"`
+}
+
+func newDeclaration(kind, name string, m proto.Message) (declaration, error) {
+	b, err := protojson.Marshal(m)
+	return declaration{Kind: kind, Name: name, Message: b}, err
+}
+
+// declarations returns what the function declared, in the order they can be replayed.
+func (a *FunctionDependencies) declarations() ([]declaration, error) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	decls := []declaration{}
+	add := func(kind, name string, m proto.Message) error {
+		d, err := newDeclaration(kind, name, m)
+		if err != nil {
+			return errors.WithMessage(err, kind+" "+name)
+		}
+		decls = append(decls, d)
+		return nil
+	}
+
+	errs := utils.NewErrorList()
+	for _, api := range sortedKeys(a.apis) {
+		for _, w := range a.apis[api].workers {
+			errs.Add(add("api", api, w))
+		}
+	}
+	for _, k := range sortedKeys(a.subscriptions) {
+		errs.Add(add("subscription", k, a.subscriptions[k]))
+	}
+	for _, k := range sortedKeys(a.schedules) {
+		errs.Add(add("schedule", k, a.schedules[k]))
+	}
+	for _, k := range sortedKeys(a.buckets) {
+		errs.Add(add("bucket", k, a.buckets[k]))
+	}
+	for _, k := range sortedKeys(a.topics) {
+		errs.Add(add("topic", k, a.topics[k]))
+	}
+	for _, k := range sortedKeys(a.collections) {
+		errs.Add(add("collection", k, a.collections[k]))
+	}
+	for _, k := range sortedKeys(a.queues) {
+		errs.Add(add("queue", k, a.queues[k]))
+	}
+	for _, k := range sortedKeys(a.secrets) {
+		errs.Add(add("secret", k, a.secrets[k]))
+	}
+	for _, p := range a.policies {
+		errs.Add(add("policy", "", p))
+	}
+	return decls, errs.Aggregate()
+}
+
+// replayFunction returns the function that made the declarations.
+func replayFunction(name string, decls []declaration) (*FunctionDependencies, error) {
+	fun := NewFunction(name)
+	for _, d := range decls {
+		var err error
+		switch d.Kind {
+		case "api":
+			w := &pb.ApiWorker{}
+			if err = protojson.Unmarshal(d.Message, w); err == nil {
+				err = fun.AddApiHandler(w)
+			}
+		case "subscription":
+			w := &pb.SubscriptionWorker{}
+			if err = protojson.Unmarshal(d.Message, w); err == nil {
+				err = fun.AddSubscriptionHandler(w)
+			}
+		case "schedule":
+			w := &pb.ScheduleWorker{}
+			if err = protojson.Unmarshal(d.Message, w); err == nil {
+				err = fun.AddScheduleHandler(w)
+			}
+		case "bucket":
+			r := &pb.BucketResource{}
+			if err = protojson.Unmarshal(d.Message, r); err == nil {
+				fun.AddBucket(d.Name, r)
+			}
+		case "topic":
+			r := &pb.TopicResource{}
+			if err = protojson.Unmarshal(d.Message, r); err == nil {
+				fun.AddTopic(d.Name, r)
+			}
+		case "collection":
+			r := &pb.CollectionResource{}
+			if err = protojson.Unmarshal(d.Message, r); err == nil {
+				fun.AddCollection(d.Name, r)
+			}
+		case "queue":
+			r := &pb.QueueResource{}
+			if err = protojson.Unmarshal(d.Message, r); err == nil {
+				fun.AddQueue(d.Name, r)
+			}
+		case "secret":
+			r := &pb.SecretResource{}
+			if err = protojson.Unmarshal(d.Message, r); err == nil {
+				fun.AddSecret(d.Name, r)
+			}
+		case "policy":
+			r := &pb.PolicyResource{}
+			if err = protojson.Unmarshal(d.Message, r); err == nil {
+				fun.AddPolicy(r)
+			}
+		default:
+			err = fmt.Errorf("unknown declaration %s", d.Kind)
+		}
+		if err != nil {
+			return nil, errors.WithMessage(err, d.Kind+" "+d.Name)
+		}
+	}
+	return fun, nil
+}
+
+// handlerHashes returns a hash of the sources each function is gathered from, by function name. A
+// function's hash covers its handler and every other file of the project, as handlers can import any
+// of them, so changing a handler only changes its own hash.
+func handlerHashes(p *project.Project, envMap map[string]string) (map[string]string, error) {
+	handlers := map[string]bool{}
+	for _, f := range p.Functions {
+		rel, err := f.RelativeHandlerPath(p)
+		if err != nil {
+			return nil, err
+		}
+		handlers[filepath.Join(p.Dir, rel)] = true
+	}
+
+	shared := sha256.New()
+	fmt.Fprintln(shared, utils.Version)
+	for _, k := range sortedKeys(envMap) {
+		fmt.Fprintf(shared, "%s=%s\n", k, envMap[k])
+	}
+	err := filepath.Walk(p.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != p.Dir && project.SkipDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if handlers[path] || !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(p.Dir, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(shared, rel)
+		return hashFile(shared, path)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sharedSum := shared.Sum(nil)
+
+	hashes := map[string]string{}
+	for _, f := range p.Functions {
+		rel, err := f.RelativeHandlerPath(p)
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		h.Write(sharedSum)
+		fmt.Fprintln(h, rel)
+		if err := hashFile(h, filepath.Join(p.Dir, rel)); err != nil {
+			return nil, err
+		}
+		hashes[f.Name] = hex.EncodeToString(h.Sum(nil))
+	}
+	return hashes, nil
+}
+
+// sortedKeys returns the keys of a map with string keys, in order.
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func hashFile(w io.Writer, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// loadCachedHandlers returns the functions whose handlers have not changed since they were last
+// gathered, replayed from what they declared then.
+func loadCachedHandlers(dir string, hashes map[string]string) map[string]*FunctionDependencies {
+	functions := map[string]*FunctionDependencies{}
+
+	b, err := ioutil.ReadFile(handlerCacheFile(dir))
+	if err != nil {
+		return functions
+	}
+	cached := map[string]cachedHandler{}
+	if err := json.Unmarshal(b, &cached); err != nil {
+		return functions
+	}
+
+	for name, h := range cached {
+		if hashes[name] == "" || hashes[name] != h.Hash {
+			continue
+		}
+		fun, err := replayFunction(name, h.Declarations)
+		if err != nil {
+			continue
+		}
+		functions[name] = fun
+	}
+	return functions
+}
+
+// saveCachedHandlers records what each gathered function declared, with the hash of its sources.
+func saveCachedHandlers(dir string, hashes map[string]string, functions map[string]*FunctionDependencies) error {
+	cached := map[string]cachedHandler{}
+	for name, fun := range functions {
+		decls, err := fun.declarations()
+		if err != nil {
+			return err
+		}
+		cached[name] = cachedHandler{Hash: hashes[name], Declarations: decls}
+	}
+
+	b, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(utils.NitricLogDir(dir), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(handlerCacheFile(dir), b, 0644)
+}
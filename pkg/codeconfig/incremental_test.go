@@ -0,0 +1,127 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/nitrictech/cli/pkg/project"
+	pb "github.com/nitrictech/nitric/pkg/api/nitric/v1"
+)
+
+func writeSource(t *testing.T, dir, file, content string) {
+	path := filepath.Join(dir, file)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHandlerHashes(t *testing.T) {
+	dir := t.TempDir()
+	writeSource(t, dir, "functions/orders.ts", "orders")
+	writeSource(t, dir, "functions/users.ts", "users")
+	writeSource(t, dir, "common/resources.ts", "resources")
+	writeSource(t, dir, "node_modules/dep/index.js", "dep")
+
+	p := &project.Project{
+		Dir: dir,
+		Functions: map[string]project.Function{
+			"orders": {Handler: "functions/orders.ts", ComputeUnit: project.ComputeUnit{Name: "orders"}},
+			"users":  {Handler: "functions/users.ts", ComputeUnit: project.ComputeUnit{Name: "users"}},
+		},
+	}
+	hashes := func() map[string]string {
+		h, err := handlerHashes(p, map[string]string{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	before := hashes()
+	if before["orders"] == "" || before["orders"] == before["users"] {
+		t.Fatalf("handlerHashes() = %v, want a different hash for each handler", before)
+	}
+
+	writeSource(t, dir, "node_modules/dep/index.js", "dep v2")
+	if after := hashes(); after["orders"] != before["orders"] || after["users"] != before["users"] {
+		t.Error("changing a dependency changed the hashes")
+	}
+
+	writeSource(t, dir, "functions/orders.ts", "orders v2")
+	after := hashes()
+	if after["orders"] == before["orders"] || after["users"] != before["users"] {
+		t.Error("changing the orders handler should only change its hash")
+	}
+
+	writeSource(t, dir, "common/resources.ts", "resources v2")
+	if shared := hashes(); shared["orders"] == after["orders"] || shared["users"] == after["users"] {
+		t.Error("changing a shared file should change every hash")
+	}
+}
+
+func TestCachedHandlers(t *testing.T) {
+	dir := t.TempDir()
+
+	orders := NewFunction("orders")
+	if err := orders.AddApiHandler(&pb.ApiWorker{Api: "main", Path: "/orders/:id", Methods: []string{"GET"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := orders.AddSubscriptionHandler(&pb.SubscriptionWorker{Topic: "created"}); err != nil {
+		t.Fatal(err)
+	}
+	orders.AddBucket("receipts", &pb.BucketResource{})
+	orders.AddPolicy(&pb.PolicyResource{
+		Principals: []*pb.Resource{{Type: pb.ResourceType_Function}},
+		Actions:    []pb.Action{pb.Action_BucketFilePut},
+		Resources:  []*pb.Resource{{Type: pb.ResourceType_Bucket, Name: "receipts"}},
+	})
+	users := NewFunction("users")
+	users.AddTopic("created", &pb.TopicResource{})
+
+	err := saveCachedHandlers(dir, map[string]string{"orders": "a", "users": "b"}, map[string]*FunctionDependencies{"orders": orders, "users": users})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cached := loadCachedHandlers(dir, map[string]string{"orders": "a", "users": "changed"})
+	if len(cached) != 1 || cached["orders"] == nil {
+		t.Fatalf("loadCachedHandlers() = %v, want only the unchanged orders handler", cached)
+	}
+
+	got := cached["orders"]
+	if got.WorkerCount() != 2 {
+		t.Errorf("WorkerCount() = %d, want 2", got.WorkerCount())
+	}
+	if !proto.Equal(got.apis["main"].workers[0], orders.apis["main"].workers[0]) {
+		t.Errorf("api worker = %v, want %v", got.apis["main"].workers[0], orders.apis["main"].workers[0])
+	}
+	if got.subscriptions["created"] == nil || got.buckets["receipts"] == nil {
+		t.Errorf("replayed function is missing its subscription or bucket: %+v", got)
+	}
+	if len(got.policies) != 1 || !proto.Equal(got.policies[0], orders.policies[0]) {
+		t.Errorf("policies = %v, want %v", got.policies, orders.policies)
+	}
+}
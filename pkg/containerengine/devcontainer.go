@@ -0,0 +1,176 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package containerengine
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// DevContainer describes the container (e.g. a VS Code dev container or a codespace) the CLI is running in.
+type DevContainer struct {
+	// Sibling is true when the engine's socket is mounted from the host, so the containers the CLI
+	// starts run next to the dev container rather than inside it (docker-in-docker)
+	Sibling bool
+	// Address the sibling containers reach the dev container on
+	Address string
+	// Workspace is the workspace in the dev container and LocalWorkspace the same folder on the host
+	Workspace      string
+	LocalWorkspace string
+	// Codespace is the name of the GitHub codespace, and PortDomain the domain its ports are forwarded on
+	Codespace  string
+	PortDomain string
+}
+
+var (
+	devContainer     *DevContainer
+	devContainerOnce sync.Once
+)
+
+// Detect returns the dev container the CLI is running in, it is nil when the CLI runs on the host.
+func Detect() *DevContainer {
+	devContainerOnce.Do(func() {
+		if !inContainer() {
+			return
+		}
+		devContainer = newDevContainer(os.Getenv)
+
+		ce, err := Discover()
+		if err != nil {
+			return
+		}
+		info, err := ce.Info()
+		if err != nil {
+			return
+		}
+		hostname, _ := os.Hostname()
+		// a daemon running in this container shares its host name
+		if info.Name != "" && !strings.EqualFold(info.Name, hostname) && RemoteHost() == "" {
+			devContainer.Sibling = true
+			devContainer.Address = containerAddress()
+		}
+	})
+	return devContainer
+}
+
+func inContainer() bool {
+	for _, f := range []string{"/.dockerenv", "/run/.containerenv"} {
+		if _, err := os.Stat(f); err == nil {
+			return true
+		}
+	}
+	return os.Getenv("REMOTE_CONTAINERS") == "true" || os.Getenv("CODESPACES") == "true"
+}
+
+func newDevContainer(getenv func(string) string) *DevContainer {
+	d := &DevContainer{
+		LocalWorkspace: getenv("LOCAL_WORKSPACE_FOLDER"),
+		Workspace:      getenv("CONTAINER_WORKSPACE_FOLDER"),
+		Codespace:      getenv("CODESPACE_NAME"),
+		PortDomain:     getenv("GITHUB_CODESPACES_PORT_FORWARDING_DOMAIN"),
+	}
+	if d.Workspace == "" && d.LocalWorkspace != "" {
+		// dev containers mount the workspace at /workspaces/<folder> by default
+		d.Workspace = "/workspaces/" + path.Base(strings.ReplaceAll(d.LocalWorkspace, "\\", "/"))
+	}
+	if d.Codespace != "" && d.PortDomain == "" {
+		d.PortDomain = "preview.app.github.dev"
+	}
+	return d
+}
+
+// containerAddress is the first IPv4 address of this container, other than the loopback.
+func containerAddress() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return ""
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String()
+		}
+	}
+	return ""
+}
+
+// CLIHost is the host the containers started by the CLI reach its servers on.
+func CLIHost() string {
+	if d := Detect(); d != nil && d.Sibling && d.Address != "" {
+		return d.Address
+	}
+	return "host.docker.internal"
+}
+
+// PublishedHost is the host the CLI reaches the ports published by the container engine on.
+func PublishedHost() string {
+	d := Detect()
+	if d == nil || !d.Sibling {
+		return "localhost"
+	}
+	if _, err := net.LookupHost("host.docker.internal"); err == nil {
+		return "host.docker.internal"
+	}
+	// the default bridge gateway
+	return "172.17.0.1"
+}
+
+// HostPath is the path on the engine's machine of a path in the dev container.
+func (d *DevContainer) HostPath(p string) string {
+	if d == nil || !d.Sibling || d.LocalWorkspace == "" {
+		return p
+	}
+	rel, err := filepath.Rel(d.Workspace, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return p
+	}
+	if rel == "." {
+		return d.LocalWorkspace
+	}
+	return strings.TrimSuffix(d.LocalWorkspace, "/") + "/" + filepath.ToSlash(rel)
+}
+
+// HostMounts returns the mounts with their sources on the engine's machine.
+func HostMounts(mounts []mount.Mount) []mount.Mount {
+	d := Detect()
+	if d == nil {
+		return mounts
+	}
+	hm := make([]mount.Mount, 0, len(mounts))
+	for _, m := range mounts {
+		if m.Type == mount.TypeBind {
+			m.Source = d.HostPath(m.Source)
+		}
+		hm = append(hm, m)
+	}
+	return hm
+}
+
+// ForwardedURL is the URL a port of the dev container is forwarded to, it is empty when the
+// port is forwarded to localhost.
+func (d *DevContainer) ForwardedURL(port string) string {
+	if d == nil || d.Codespace == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://%s-%s.%s", d.Codespace, port, d.PortDomain)
+}
@@ -0,0 +1,74 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package containerengine
+
+import (
+	"testing"
+)
+
+func TestDevContainerHostPath(t *testing.T) {
+	env := map[string]string{"LOCAL_WORKSPACE_FOLDER": "/home/jo/src/shop"}
+	d := newDevContainer(func(k string) string { return env[k] })
+	d.Sibling = true
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "workspace",
+			path: "/workspaces/shop",
+			want: "/home/jo/src/shop",
+		},
+		{
+			name: "in the workspace",
+			path: "/workspaces/shop/functions",
+			want: "/home/jo/src/shop/functions",
+		},
+		{
+			name: "outside the workspace",
+			path: "/home/vscode/.nitric/run",
+			want: "/home/vscode/.nitric/run",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.HostPath(tt.path); got != tt.want {
+				t.Errorf("HostPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	d.Sibling = false
+	if got := d.HostPath("/workspaces/shop"); got != "/workspaces/shop" {
+		t.Errorf("HostPath() = %v, want the path unchanged with docker-in-docker", got)
+	}
+}
+
+func TestDevContainerForwardedURL(t *testing.T) {
+	env := map[string]string{"CODESPACE_NAME": "jo-shop-x7", "GITHUB_CODESPACES_PORT_FORWARDING_DOMAIN": "app.github.dev"}
+	d := newDevContainer(func(k string) string { return env[k] })
+	if got, want := d.ForwardedURL("9001"), "https://jo-shop-x7-9001.app.github.dev"; got != want {
+		t.Errorf("ForwardedURL() = %v, want %v", got, want)
+	}
+
+	d = newDevContainer(func(string) string { return "" })
+	if got := d.ForwardedURL("9001"); got != "" {
+		t.Errorf("ForwardedURL() = %v, want none outside a codespace", got)
+	}
+}
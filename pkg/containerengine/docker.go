@@ -18,14 +18,17 @@ package containerengine
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -65,7 +68,7 @@ func newDocker() (ContainerEngine, error) {
 		return nil, err
 	}
 
-	cli, err := client.NewClientWithOpts(client.FromEnv)
+	cli, err := newEngineClient()
 	if err != nil {
 		return nil, err
 	}
@@ -83,7 +86,8 @@ func tarContextDir(relDockerfile, contextDir string, extraExcludes []string) (io
 		return nil, err
 	}
 
-	excludes = append(excludes, utils.NitricLogDir(contextDir))
+	// excludes are relative to the context
+	excludes = append(excludes, utils.NitricLogDir("."))
 	excludes = append(excludes, extraExcludes...)
 
 	if err := build.ValidateContextDirectory(contextDir, excludes); err != nil {
@@ -124,8 +128,9 @@ func imageNameFromBuildContext(dockerfile, srcPath, imageTag string, excludes []
 	return strings.ToLower(imageName + ":" + hex.EncodeToString(hash.Sum(nil))), nil
 }
 
-func (d *docker) Build(dockerfile, srcPath, imageTag string, buildArgs map[string]string, excludes []string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), buildTimeout())
+func (d *docker) Build(dockerfile, srcPath, imageTag string, buildArgs map[string]string, excludes []string, buildOpts *BuildOpts) error {
+	timeout := buildTimeout(buildOpts)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	imageTagWithHash, err := imageNameFromBuildContext(dockerfile, srcPath, imageTag, excludes)
@@ -146,21 +151,118 @@ func (d *docker) Build(dockerfile, srcPath, imageTag string, buildArgs map[strin
 		return nil
 	}
 
+	// the RUN steps don't inherit the proxy of the environment
+	args := utils.ProxyEnv()
+	for k, v := range buildArgs {
+		args[k] = v
+	}
+	buildArgs = args
+
+	if !buildOpts.empty() {
+		// ssh and secret mounts need a BuildKit session, which the docker cli provides.
+		err := buildWithCli(ctx, dockerfile, srcPath, []string{strings.ToLower(imageTag), imageTagWithHash}, buildArgs, excludes, buildOpts)
+		return offlineBuildErr(timeoutBuildErr(ctx, imageTag, timeout, err))
+	}
+
+	sdkBuildArgs := map[string]*string{}
+	for k := range buildArgs {
+		v := buildArgs[k]
+		sdkBuildArgs[k] = &v
+	}
+
 	opts := types.ImageBuildOptions{
 		SuppressOutput: false,
 		Dockerfile:     dockerfile,
 		Tags:           []string{strings.ToLower(imageTag), imageTagWithHash},
 		Remove:         true,
 		ForceRemove:    true,
-		PullParent:     true,
+		PullParent:     (buildOpts == nil || !buildOpts.Pinned) && !utils.Offline,
+		BuildArgs:      sdkBuildArgs,
+	}
+	if buildOpts != nil {
+		opts.Platform = buildOpts.Platform
+		opts.Labels = buildOpts.Labels
 	}
 	res, err := d.cli.ImageBuild(ctx, buildContext, opts)
 	if err != nil {
-		return err
+		return timeoutBuildErr(ctx, imageTag, timeout, err)
 	}
 	defer res.Body.Close()
 
-	return print(res.Body)
+	return offlineBuildErr(timeoutBuildErr(ctx, imageTag, timeout, print(res.Body)))
+}
+
+// timeoutBuildErr explains build failures caused by the build running longer than its timeout.
+func timeoutBuildErr(ctx context.Context, imageTag string, timeout time.Duration, err error) error {
+	if err == nil || ctx.Err() != context.DeadlineExceeded {
+		return err
+	}
+	return fmt.Errorf("building %s took longer than %s, raise the build_timeout setting or the buildTimeout of the function in nitric.yaml", imageTag, timeout)
+}
+
+// offlineBuildErr explains build failures while offline, when the base images and membrane can't be downloaded.
+func offlineBuildErr(err error) error {
+	if err == nil || !utils.Offline {
+		return err
+	}
+	return errors.WithMessage(err, "building offline uses the base images pulled by earlier builds, and needs LOCAL_MEMBRANE to serve the nitric membrane")
+}
+
+func buildWithCli(ctx context.Context, dockerfile, srcPath string, tags []string, buildArgs map[string]string, excludes []string, opts *BuildOpts) error {
+	if !filepath.IsAbs(dockerfile) {
+		dockerfile = filepath.Join(srcPath, dockerfile)
+	}
+
+	// BuildKit reads <Dockerfile>.dockerignore in preference to the context's .dockerignore
+	ignore, err := build.ReadDockerignore(srcPath)
+	if err != nil {
+		return err
+	}
+	ignore = append(ignore, utils.NitricLogDir("."))
+	ignore = append(ignore, excludes...)
+	ignoreFile := dockerfile + ".dockerignore"
+	if _, err := os.Stat(ignoreFile); os.IsNotExist(err) {
+		err = ioutil.WriteFile(ignoreFile, []byte(strings.Join(ignore, "\n")), 0600)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(ignoreFile)
+	}
+
+	args := []string{"build", "--progress", "plain", "-f", dockerfile}
+	if !opts.Pinned && !utils.Offline {
+		args = append(args, "--pull")
+	}
+	if opts.Platform != "" {
+		args = append(args, "--platform", opts.Platform)
+	}
+	for _, t := range tags {
+		args = append(args, "-t", t)
+	}
+	for k, v := range buildArgs {
+		args = append(args, "--build-arg", k+"="+v)
+	}
+	for _, s := range opts.SSH {
+		args = append(args, "--ssh", s)
+	}
+	for id, src := range opts.Secrets {
+		args = append(args, "--secret", fmt.Sprintf("id=%s,src=%s", id, src))
+	}
+	for k, v := range opts.Labels {
+		args = append(args, "--label", k+"="+v)
+	}
+	args = append(args, srcPath)
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Env = append(os.Environ(), "DOCKER_BUILDKIT=1")
+	// keep the output for the error, as it is only printed when the output includes the build
+	out := &bytes.Buffer{}
+	cmd.Stdout = io.MultiWriter(output.VerboseWriter(output.VerboseDiagnostics), out)
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Run(); err != nil {
+		return errors.WithMessage(err, "docker build\n"+out.String())
+	}
+	return nil
 }
 
 type ErrorLine struct {
@@ -189,12 +291,11 @@ func print(rd io.Reader) error {
 			return err
 		}
 		if len(strings.TrimSpace(line.Stream)) > 0 {
+			// the intermediate layers are only interesting when debugging the build
 			if strings.Contains(line.Stream, "--->") {
-				if output.VerboseLevel >= 3 {
-					log.Default().Print(line.Stream)
-				}
+				_, _ = io.WriteString(output.VerboseWriter(output.VerboseDebug), line.Stream)
 			} else {
-				log.Default().Print(line.Stream)
+				_, _ = io.WriteString(output.VerboseWriter(output.VerboseDiagnostics), line.Stream)
 			}
 		}
 	}
@@ -234,6 +335,16 @@ func (d *docker) ListImages(stackName, containerName string) ([]Image, error) {
 }
 
 func (d *docker) ImagePull(rawImage string, opts types.ImagePullOptions) error {
+	if utils.Offline {
+		// use the image pulled before
+		listOpts := types.ImageListOptions{Filters: filters.NewArgs()}
+		listOpts.Filters.Add("reference", rawImage)
+		if imgs, err := d.cli.ImageList(context.Background(), listOpts); err == nil && len(imgs) > 0 {
+			return nil
+		}
+		return utils.RequireNetwork("pulling the image " + rawImage)
+	}
+
 	resp, err := d.cli.ImagePull(context.Background(), rawImage, opts)
 	if err != nil {
 		return errors.WithMessage(err, "Pull")
@@ -242,6 +353,29 @@ func (d *docker) ImagePull(rawImage string, opts types.ImagePullOptions) error {
 	return print(resp)
 }
 
+func (d *docker) RepoDigest(image string) (string, error) {
+	ii, _, err := d.cli.ImageInspectWithRaw(context.Background(), image)
+	if err != nil {
+		return "", errors.WithMessage(err, "ImageInspect")
+	}
+	if len(ii.RepoDigests) == 0 {
+		return "", fmt.Errorf("image %s has no repository digest, it has not been pulled from a registry", image)
+	}
+	return ii.RepoDigests[0], nil
+}
+
+func (d *docker) ImageSize(image string) (int64, error) {
+	ii, _, err := d.cli.ImageInspectWithRaw(context.Background(), image)
+	if err != nil {
+		return 0, errors.WithMessage(err, "ImageInspect")
+	}
+	return ii.Size, nil
+}
+
+func (d *docker) Tag(source, target string) error {
+	return d.cli.ImageTag(context.Background(), source, target)
+}
+
 func (d *docker) ContainerCreate(config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, name string) (string, error) {
 	resp, err := d.cli.ContainerCreate(context.Background(), config, hostConfig, networkingConfig, nil, name)
 	if err != nil {
@@ -302,3 +436,22 @@ func (d *docker) Version() string {
 	b, _ := yaml.Marshal(sv)
 	return string(b)
 }
+
+func (d *docker) Info() (*EngineInfo, error) {
+	ctx := context.Background()
+	info, err := d.cli.Info(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Info")
+	}
+	sv, err := d.cli.ServerVersion(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "ServerVersion")
+	}
+	return &EngineInfo{
+		OSType:       info.OSType,
+		Architecture: info.Architecture,
+		APIVersion:   sv.APIVersion,
+		RootDir:      info.DockerRootDir,
+		Name:         info.Name,
+	}, nil
+}
@@ -0,0 +1,113 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package containerengine
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/docker/cli/cli/connhelper"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+)
+
+// engineHost returns the daemon address to use, it is empty when the local default should be used.
+// DOCKER_HOST takes precedence, then the DOCKER_CONTEXT or the current docker context.
+func engineHost() (string, error) {
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		return host, nil
+	}
+
+	args := []string{"context", "inspect", "--format", "{{.Endpoints.docker.Host}}"}
+	if ctxName := os.Getenv("DOCKER_CONTEXT"); ctxName != "" {
+		args = append(args, ctxName)
+	}
+
+	out := &bytes.Buffer{}
+	cmd := exec.Command("docker", args...)
+	cmd.Stdout = out
+	if err := cmd.Run(); err != nil {
+		if os.Getenv("DOCKER_CONTEXT") != "" {
+			return "", errors.WithMessage(err, "docker context "+os.Getenv("DOCKER_CONTEXT"))
+		}
+		// podman-docker and older docker clients don't support contexts.
+		return "", nil
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// isRemoteHost is true when the engine is not running on this machine.
+func isRemoteHost(host string) bool {
+	u, err := url.Parse(host)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "", "unix", "npipe":
+		return false
+	case "tcp", "http", "https":
+		h := u.Hostname()
+		return h != "localhost" && h != "127.0.0.1" && h != "::1"
+	default:
+		return true
+	}
+}
+
+// RemoteHost returns the address of the container engine when it is on another machine (e.g. ssh://user@buildbox).
+func RemoteHost() string {
+	host, err := engineHost()
+	if err != nil || !isRemoteHost(host) {
+		return ""
+	}
+	return host
+}
+
+func newEngineClient() (*client.Client, error) {
+	host, err := engineHost()
+	if err != nil {
+		return nil, err
+	}
+	return newEngineClientForHost(host)
+}
+
+func newEngineClientForHost(host string) (*client.Client, error) {
+	if !strings.HasPrefix(host, "ssh://") {
+		opts := []client.Opt{client.FromEnv}
+		if host != "" {
+			opts = append(opts, client.WithHost(host))
+		}
+		return client.NewClientWithOpts(opts...)
+	}
+
+	// ssh hosts are reached by tunnelling through "docker system dial-stdio" on the remote machine.
+	helper, err := connhelper.GetConnectionHelper(host)
+	if err != nil {
+		return nil, errors.WithMessage(err, "connecting to "+host)
+	}
+
+	return client.NewClientWithOpts(
+		client.WithHTTPClient(&http.Client{
+			Transport: &http.Transport{DialContext: helper.Dialer},
+		}),
+		client.WithHost(helper.Host),
+		client.WithDialContext(helper.Dialer),
+		client.WithAPIVersionNegotiation(),
+	)
+}
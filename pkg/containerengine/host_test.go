@@ -0,0 +1,40 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package containerengine
+
+import "testing"
+
+func TestIsRemoteHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{host: "", want: false},
+		{host: "unix:///var/run/docker.sock", want: false},
+		{host: "npipe:////./pipe/docker_engine", want: false},
+		{host: "tcp://localhost:2375", want: false},
+		{host: "tcp://127.0.0.1:2375", want: false},
+		{host: "tcp://10.0.0.4:2376", want: true},
+		{host: "ssh://me@buildbox", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := isRemoteHost(tt.host); got != tt.want {
+				t.Errorf("isRemoteHost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -21,19 +21,22 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	goruntime "runtime"
 	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
-	"github.com/docker/docker/client"
 	"github.com/pkg/errors"
 
 	"github.com/nitrictech/cli/pkg/utils"
 )
 
+const podmanMachinePipe = "npipe:////./pipe/podman-machine-default"
+
 // use docker client to podman socket.
 type podman struct {
 	*docker
@@ -48,6 +51,19 @@ func newPodman() (ContainerEngine, error) {
 		return nil, err
 	}
 
+	if goruntime.GOOS == "windows" && os.Getenv("DOCKER_HOST") == "" {
+		// podman-docker is not available on windows, the podman machine is reached through its named pipe.
+		cli, err := newEngineClientForHost(podmanMachinePipe)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = cli.ContainerList(context.Background(), types.ContainerListOptions{}); err != nil {
+			fmt.Println("podman machine not running, please execute 'podman machine start'")
+			return nil, err
+		}
+		return &podman{docker: &docker{cli: cli}}, nil
+	}
+
 	// make sure that the podman-docker package has been installed.
 	out := &bytes.Buffer{}
 	cmd = exec.Command("docker", "--version")
@@ -62,7 +78,7 @@ func newPodman() (ContainerEngine, error) {
 	}
 
 	//export DOCKER_HOST=unix:///run/user/1000/podman/podman.sock
-	cli, err := client.NewClientWithOpts(client.FromEnv)
+	cli, err := newEngineClient()
 	if err != nil {
 		return nil, err
 	}
@@ -85,8 +101,8 @@ func (p *podman) Version() string {
 	return p.docker.Version()
 }
 
-func (p *podman) Build(dockerfile, path, imageTag string, buildArgs map[string]string, excludes []string) error {
-	return p.docker.Build(dockerfile, path, imageTag, buildArgs, excludes)
+func (p *podman) Build(dockerfile, path, imageTag string, buildArgs map[string]string, excludes []string, opts *BuildOpts) error {
+	return p.docker.Build(dockerfile, path, imageTag, buildArgs, excludes, opts)
 }
 
 func (p *podman) ListImages(stackName, containerName string) ([]Image, error) {
@@ -97,6 +113,18 @@ func (p *podman) ImagePull(rawImage string, opts types.ImagePullOptions) error {
 	return p.docker.ImagePull(rawImage, opts)
 }
 
+func (p *podman) RepoDigest(image string) (string, error) {
+	return p.docker.RepoDigest(image)
+}
+
+func (p *podman) ImageSize(image string) (int64, error) {
+	return p.docker.ImageSize(image)
+}
+
+func (p *podman) Tag(source, target string) error {
+	return p.docker.Tag(source, target)
+}
+
 func (p *podman) ContainerCreate(config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, name string) (string, error) {
 	return p.docker.ContainerCreate(config, hostConfig, networkingConfig, name)
 }
@@ -141,3 +169,7 @@ func (j *jsonfile) Config() *container.LogConfig {
 
 func (j *jsonfile) Stop() error  { return nil }
 func (j *jsonfile) Start() error { return nil }
+
+func (j *jsonfile) Follow(handler LogHandler) error {
+	return utils.NewNotSupportedErr("following logs is not supported with podman, see " + j.logPath)
+}
@@ -0,0 +1,100 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package containerengine
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/docker/docker/api/types/versions"
+	"github.com/pkg/errors"
+	"github.com/pterm/pterm"
+)
+
+// minFreeSpace is the free space the daemon needs to build, the base images and layers of a project
+// quickly add up to several GiB.
+const minFreeSpace = 5 << 30
+
+// minBuildKitAPI is the API version of docker 18.09, the first release with BuildKit.
+const minBuildKitAPI = "1.39"
+
+// daemonArchitectures maps the architectures the daemon reports to those of platforms.
+var daemonArchitectures = map[string]string{
+	"x86_64":  "amd64",
+	"aarch64": "arm64",
+}
+
+// Preflight checks the engine can build images for the platform, reporting every problem together before the
+// slow steps start rather than as an error from deep inside the build.
+func Preflight(platform string, opts *BuildOpts) error {
+	ce, err := Discover()
+	if err != nil {
+		return errors.WithMessage(err, "docker or podman must be installed and running to build the images")
+	}
+
+	info, err := ce.Info()
+	if err != nil {
+		return errors.WithMessage(err, "the container engine is not responding, please check it is running")
+	}
+
+	if arch := strings.TrimPrefix(platform, "linux/"); arch != platformArchitecture(info.Architecture) {
+		pterm.Warning.Printf("Building %s images on a %s daemon uses emulation and will be slower\n", platform, info.Architecture)
+	}
+
+	problems := checkEngine(info, !opts.empty(), exec.LookPath, freeSpace)
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("the %s daemon can't build the images:\n  - %s", ce.Type(), strings.Join(problems, "\n  - "))
+}
+
+// platformArchitecture returns the platform architecture of a daemon architecture.
+func platformArchitecture(arch string) string {
+	if a, ok := daemonArchitectures[arch]; ok {
+		return a
+	}
+	return arch
+}
+
+// checkEngine returns the problems that would stop the daemon building the images. The free space is only
+// checked when the daemon's root directory is on this machine.
+func checkEngine(info *EngineInfo, buildKit bool, lookPath func(string) (string, error), free func(string) (uint64, error)) []string {
+	problems := []string{}
+
+	if info.OSType != "" && info.OSType != "linux" {
+		problems = append(problems, fmt.Sprintf("it runs %s containers, switch it to linux containers", info.OSType))
+	}
+
+	if buildKit {
+		if info.APIVersion != "" && versions.LessThan(info.APIVersion, minBuildKitAPI) {
+			problems = append(problems, fmt.Sprintf("ssh and secret build mounts need BuildKit, which needs docker 18.09 (API %s) or later, the daemon has API %s", minBuildKitAPI, info.APIVersion))
+		}
+		if _, err := lookPath("docker"); err != nil {
+			problems = append(problems, "ssh and secret build mounts are built with the docker cli, which is not on the PATH")
+		}
+	}
+
+	if _, err := os.Stat(info.RootDir); info.RootDir != "" && err == nil {
+		if bytes, err := free(info.RootDir); err == nil && bytes < minFreeSpace {
+			problems = append(problems, fmt.Sprintf("%s has %.1f GiB free, at least %d GiB is needed, try 'docker system prune'", info.RootDir, float64(bytes)/(1<<30), minFreeSpace>>30))
+		}
+	}
+
+	return problems
+}
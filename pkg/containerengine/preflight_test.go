@@ -0,0 +1,103 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package containerengine
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCheckEngine(t *testing.T) {
+	dir := t.TempDir()
+	found := func(string) (string, error) { return "/usr/bin/docker", nil }
+	missing := func(string) (string, error) { return "", errors.New("not found") }
+	plenty := func(string) (uint64, error) { return 100 << 30, nil }
+	full := func(string) (uint64, error) { return 1 << 30, nil }
+
+	tests := []struct {
+		name     string
+		info     *EngineInfo
+		buildKit bool
+		lookPath func(string) (string, error)
+		free     func(string) (uint64, error)
+		want     []string
+	}{
+		{
+			name:     "ready",
+			info:     &EngineInfo{OSType: "linux", APIVersion: "1.41", RootDir: dir},
+			buildKit: true,
+			lookPath: found,
+			free:     plenty,
+			want:     []string{},
+		},
+		{
+			name:     "windows containers",
+			info:     &EngineInfo{OSType: "windows", APIVersion: "1.41"},
+			lookPath: found,
+			free:     plenty,
+			want:     []string{"it runs windows containers, switch it to linux containers"},
+		},
+		{
+			name:     "no buildkit",
+			info:     &EngineInfo{OSType: "linux", APIVersion: "1.38"},
+			buildKit: true,
+			lookPath: missing,
+			free:     plenty,
+			want: []string{
+				"ssh and secret build mounts need BuildKit, which needs docker 18.09 (API 1.39) or later, the daemon has API 1.38",
+				"ssh and secret build mounts are built with the docker cli, which is not on the PATH",
+			},
+		},
+		{
+			name:     "buildkit not needed",
+			info:     &EngineInfo{OSType: "linux", APIVersion: "1.38"},
+			lookPath: missing,
+			free:     plenty,
+			want:     []string{},
+		},
+		{
+			name:     "disk full",
+			info:     &EngineInfo{OSType: "linux", APIVersion: "1.41", RootDir: dir},
+			lookPath: found,
+			free:     full,
+			want:     []string{dir + " has 1.0 GiB free, at least 5 GiB is needed, try 'docker system prune'"},
+		},
+		{
+			name:     "remote daemon",
+			info:     &EngineInfo{OSType: "linux", APIVersion: "1.41", RootDir: "/var/lib/docker-does-not-exist"},
+			lookPath: found,
+			free:     full,
+			want:     []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkEngine(tt.info, tt.buildKit, tt.lookPath, tt.free); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkEngine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlatformArchitecture(t *testing.T) {
+	for arch, want := range map[string]string{"x86_64": "amd64", "aarch64": "arm64", "arm64": "arm64"} {
+		if got := platformArchitecture(arch); got != want {
+			t.Errorf("platformArchitecture(%s) = %s, want %s", arch, got, want)
+		}
+	}
+}
@@ -38,6 +38,7 @@ type localSyslog struct {
 	file    *os.File
 	port    int
 	server  *syslog.Server
+	handler LogHandler
 }
 
 func newSyslog(logPath string) ContainerLogger {
@@ -54,6 +55,11 @@ func (s *localSyslog) Stop() error {
 	return errList.Aggregate()
 }
 
+func (s *localSyslog) Follow(handler LogHandler) error {
+	s.handler = handler
+	return nil
+}
+
 func (s *localSyslog) Config() *container.LogConfig {
 	return &container.LogConfig{
 		Type: "syslog",
@@ -100,6 +106,12 @@ func (s *localSyslog) Start() error {
 	go func(channel syslog.LogPartsChannel) {
 		for logParts := range channel {
 			fmt.Fprintf(s.file, "%s %s %s\n", logParts["timestamp"], logParts["tag"], logParts["content"])
+
+			if s.handler != nil {
+				tag, _ := logParts["tag"].(string)
+				content, _ := logParts["content"].(string)
+				s.handler(tagContainer(tag), content)
+			}
 		}
 	}(channel)
 
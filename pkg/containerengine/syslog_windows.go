@@ -20,6 +20,8 @@ package containerengine
 
 import (
 	"github.com/docker/docker/api/types/container"
+
+	"github.com/nitrictech/cli/pkg/utils"
 )
 
 type nullLogger struct {
@@ -35,3 +37,7 @@ func (n *nullLogger) Config() *container.LogConfig {
 
 func (n *nullLogger) Stop() error  { return nil }
 func (n *nullLogger) Start() error { return nil }
+
+func (n *nullLogger) Follow(handler LogHandler) error {
+	return utils.NewNotSupportedErr("following logs is not supported on windows")
+}
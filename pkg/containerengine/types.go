@@ -19,12 +19,15 @@ package containerengine
 import (
 	"errors"
 	"io"
+	goruntime "runtime"
 	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
+
+	"github.com/nitrictech/cli/pkg/settings"
 )
 
 var DiscoveredEngine ContainerEngine
@@ -36,17 +39,78 @@ type Image struct {
 	CreatedAt  string `yaml:"createdAt,omitempty"`
 }
 
+// BuildOpts forward credentials to BuildKit for RUN --mount=type=ssh|secret.
+type BuildOpts struct {
+	SSH     []string
+	Secrets map[string]string
+	// Pinned builds from the local base images as they are, rather than pulling newer ones
+	Pinned bool
+	// Platform the image is built for, e.g. linux/amd64. Defaults to the engine's platform.
+	Platform string
+	// Timeout limits how long the build can take, defaults to the build_timeout setting.
+	Timeout time.Duration
+	// Labels are set on the image, e.g. org.opencontainers.image.revision
+	Labels map[string]string
+}
+
+// DeployPlatform is the platform of the images deployed to the cloud providers.
+const DeployPlatform = "linux/amd64"
+
+// HostPlatform is the platform images run on without emulation on this machine.
+func HostPlatform() string {
+	return "linux/" + goruntime.GOARCH
+}
+
+func (o *BuildOpts) empty() bool {
+	return o == nil || (len(o.SSH) == 0 && len(o.Secrets) == 0)
+}
+
+// LogHandler is called with each line logged, container is the name of the container that logged it
+// or empty for lines logged by the cli itself.
+type LogHandler func(container, line string)
+
 type ContainerLogger interface {
 	Start() error
 	Stop() error
 	Config() *container.LogConfig
+	// Follow sets a handler to call with each line logged, it must be called before Start.
+	Follow(handler LogHandler) error
+}
+
+// tagContainer returns the container name from a "{{.ImageName}}/{{.Name}}/{{.ID}}" log tag,
+// or an empty string for other tags.
+func tagContainer(tag string) string {
+	parts := strings.Split(tag, "/")
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[len(parts)-2]
+}
+
+// EngineInfo describes the daemon of a container engine.
+type EngineInfo struct {
+	// OSType of the containers the daemon runs, linux or windows
+	OSType string
+	// Architecture of the daemon's machine, e.g. x86_64 or aarch64
+	Architecture string
+	// APIVersion of the daemon, e.g. 1.41
+	APIVersion string
+	// RootDir is where the daemon keeps its images, on the daemon's machine
+	RootDir string
+	// Name is the host name of the daemon's machine
+	Name string
 }
 
 type ContainerEngine interface {
 	Type() string
-	Build(dockerfile, path, imageTag string, buildArgs map[string]string, excludes []string) error
+	Build(dockerfile, path, imageTag string, buildArgs map[string]string, excludes []string, opts *BuildOpts) error
 	ListImages(stackName, containerName string) ([]Image, error)
 	ImagePull(rawImage string, opts types.ImagePullOptions) error
+	// RepoDigest returns the repository digest (name@sha256:...) of a local image
+	RepoDigest(image string) (string, error)
+	// ImageSize returns the size of a local image in bytes
+	ImageSize(image string) (int64, error)
+	Tag(source, target string) error
 	ContainerCreate(config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, name string) (string, error)
 	Start(nameOrID string) error
 	Stop(nameOrID string, timeout *time.Duration) error
@@ -55,26 +119,38 @@ type ContainerEngine interface {
 	ContainerLogs(containerID string, opts types.ContainerLogsOptions) (io.ReadCloser, error)
 	Logger(stackPath string) ContainerLogger
 	Version() string
+	// Info describes the daemon, for checking it can build the images
+	Info() (*EngineInfo, error)
+}
+
+// engines are tried in order by Discover, the first one available is used.
+// Each engine connects to DOCKER_HOST (including ssh:// hosts) or the selected docker context.
+var engines = []func() (ContainerEngine, error){
+	newPodman,
+	newDocker,
 }
 
 func Discover() (ContainerEngine, error) {
 	if DiscoveredEngine != nil {
 		return DiscoveredEngine, nil
 	}
-	pm, err := newPodman()
-	if err == nil {
-		DiscoveredEngine = pm
-		return pm, nil
-	}
-	dk, err := newDocker()
-	if err == nil {
-		DiscoveredEngine = dk
-		return dk, nil
+	for _, newEngine := range engines {
+		ce, err := newEngine()
+		if err == nil {
+			DiscoveredEngine = ce
+			return ce, nil
+		}
 	}
 	return nil, errors.New("neither podman nor docker found")
 }
 
-func buildTimeout() time.Duration {
+func buildTimeout(opts *BuildOpts) time.Duration {
+	if opts != nil && opts.Timeout > 0 {
+		return opts.Timeout
+	}
+	if t := settings.Get().BuildTimeout; t > 0 {
+		return t
+	}
 	return 15 * time.Minute
 }
 
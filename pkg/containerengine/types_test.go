@@ -0,0 +1,47 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package containerengine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTagContainer(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{tag: "nitric-ts-dev/shop-run-orders/0a1b2c3d", want: "shop-run-orders"},
+		{tag: "minio/minio/minio-shop/4e5f6a7b", want: "minio-shop"},
+		{tag: "nitric-run", want: ""},
+		{tag: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := tagContainer(tt.tag); got != tt.want {
+				t.Errorf("tagContainer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTimeoutOverride(t *testing.T) {
+	if got := buildTimeout(&BuildOpts{Timeout: 45 * time.Minute}); got != 45*time.Minute {
+		t.Errorf("buildTimeout() = %v, want %v", got, 45*time.Minute)
+	}
+}
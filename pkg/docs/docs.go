@@ -0,0 +1,214 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docs
+
+import (
+	"embed"
+	htmltemplate "html/template"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+// Formats are the formats the report is rendered in.
+var Formats = []string{"markdown", "html"}
+
+//go:embed templates
+var templates embed.FS
+
+// Environment is a stack of the project, with the endpoints it is deployed to.
+type Environment struct {
+	Stack string
+	// Target is where the stack is deployed, e.g. aws/us-east-1
+	Target    string
+	Endpoints []Endpoint
+	// Error is why the endpoints couldn't be read, e.g. the stack isn't deployed
+	Error string
+}
+
+// Endpoint is where an api, cdn or site of the project is served by a stack.
+type Endpoint struct {
+	// Kind is api, cdn or site
+	Kind string
+	Name string
+	URL  string
+}
+
+// NewEnvironment returns the endpoints of the deployment of a stack, sorted by kind and name.
+func NewEnvironment(stackName, target string, d *types.Deployment) Environment {
+	env := Environment{Stack: stackName, Target: target, Endpoints: []Endpoint{}}
+	for kind, endpoints := range map[string]map[string]string{"api": d.ApiEndpoints, "cdn": d.CdnEndpoints, "site": d.SiteEndpoints} {
+		for name, url := range endpoints {
+			env.Endpoints = append(env.Endpoints, Endpoint{Kind: kind, Name: name, URL: url})
+		}
+	}
+	sort.Slice(env.Endpoints, func(i, j int) bool {
+		if env.Endpoints[i].Kind != env.Endpoints[j].Kind {
+			return env.Endpoints[i].Kind < env.Endpoints[j].Kind
+		}
+		return env.Endpoints[i].Name < env.Endpoints[j].Name
+	})
+	return env
+}
+
+type Function struct {
+	Name   string
+	Kind   string
+	Source string
+	// Triggers are what invokes the function, e.g. topic:orders or api:main GET /orders
+	Triggers []string
+}
+
+type Api struct {
+	Name   string
+	Routes []project.RouteSpec
+}
+
+type Topic struct {
+	Name        string
+	Schema      string
+	Subscribers []string
+}
+
+type Bucket struct {
+	Name      string
+	Listeners []string
+}
+
+type Schedule struct {
+	Name string
+	project.ScheduleSpec
+}
+
+// Report is the project's resources, routes and triggers with the endpoints of each of its stacks, sorted by name.
+type Report struct {
+	Project      string
+	Functions    []Function
+	Apis         []Api
+	Topics       []Topic
+	Buckets      []Bucket
+	Queues       []string
+	Collections  []string
+	Secrets      []string
+	Schedules    []Schedule
+	Policies     []project.PolicySpec
+	Environments []Environment
+}
+
+// NewReport returns the report of the spec and the environments.
+func NewReport(spec *project.Spec, envs []Environment) *Report {
+	r := &Report{
+		Project:      spec.Project,
+		Queues:       spec.Queues,
+		Collections:  spec.Collections,
+		Secrets:      spec.Secrets,
+		Policies:     spec.Policies,
+		Environments: envs,
+	}
+
+	triggers := map[string][]string{}
+	for _, name := range sortedKeys(spec.Apis) {
+		r.Apis = append(r.Apis, Api{Name: name, Routes: spec.Apis[name]})
+		for _, route := range spec.Apis[name] {
+			triggers[route.Function] = append(triggers[route.Function], "api:"+name+" "+route.Method+" "+route.Path)
+		}
+	}
+	for _, name := range sortedKeys(spec.Topics) {
+		t := spec.Topics[name]
+		r.Topics = append(r.Topics, Topic{Name: name, Schema: t.Schema, Subscribers: t.Subscribers})
+		for _, f := range t.Subscribers {
+			triggers[f] = append(triggers[f], "topic:"+name)
+		}
+	}
+	for _, name := range sortedKeys(spec.Buckets) {
+		b := spec.Buckets[name]
+		r.Buckets = append(r.Buckets, Bucket{Name: name, Listeners: b.Listeners})
+		for _, f := range b.Listeners {
+			triggers[f] = append(triggers[f], "bucket:"+name)
+		}
+	}
+	for _, name := range sortedKeys(spec.Schedules) {
+		r.Schedules = append(r.Schedules, Schedule{Name: name, ScheduleSpec: spec.Schedules[name]})
+	}
+	for _, name := range sortedKeys(spec.Functions) {
+		f := spec.Functions[name]
+		r.Functions = append(r.Functions, Function{Name: name, Kind: f.Kind, Source: f.Source, Triggers: triggers[name]})
+	}
+	return r
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	switch t := m.(type) {
+	case map[string][]project.RouteSpec:
+		for k := range t {
+			keys = append(keys, k)
+		}
+	case map[string]project.TopicSpec:
+		for k := range t {
+			keys = append(keys, k)
+		}
+	case map[string]project.BucketSpec:
+		for k := range t {
+			keys = append(keys, k)
+		}
+	case map[string]project.ScheduleSpec:
+		for k := range t {
+			keys = append(keys, k)
+		}
+	case map[string]project.FunctionSpec:
+		for k := range t {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var funcs = map[string]interface{}{
+	"join": strings.Join,
+}
+
+// Markdown writes the report as markdown.
+func (r *Report) Markdown(w io.Writer) error {
+	tmpl, err := template.New("resources.md.tmpl").Funcs(funcs).ParseFS(templates, "templates/resources.md.tmpl")
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, r)
+}
+
+// HTML writes the report as a standalone HTML page.
+func (r *Report) HTML(w io.Writer) error {
+	tmpl, err := htmltemplate.New("resources.html.tmpl").Funcs(funcs).ParseFS(templates, "templates/resources.html.tmpl")
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, r)
+}
+
+// Render writes the report in the format, markdown or html.
+func (r *Report) Render(format string, w io.Writer) error {
+	if format == "html" {
+		return r.HTML(w)
+	}
+	return r.Markdown(w)
+}
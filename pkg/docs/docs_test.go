@@ -0,0 +1,93 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docs
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+func testReport() *Report {
+	spec := &project.Spec{
+		Project: "shop",
+		Functions: map[string]project.FunctionSpec{
+			"orders": {Kind: "function", Source: "functions/orders.ts"},
+			"thumbs": {Kind: "container", Source: "thumbs.dockerfile"},
+		},
+		Apis: map[string][]project.RouteSpec{
+			"main": {{Method: "GET", Path: "/orders", Function: "orders"}},
+		},
+		Topics:    map[string]project.TopicSpec{"sales": {Subscribers: []string{"orders"}}},
+		Buckets:   map[string]project.BucketSpec{"images": {Listeners: []string{"thumbs"}}},
+		Schedules: map[string]project.ScheduleSpec{"daily": {Expression: "@daily", Topic: "sales"}},
+		Secrets:   []string{"api-key"},
+	}
+	envs := []Environment{
+		NewEnvironment("prod", "aws/us-east-1", &types.Deployment{
+			ApiEndpoints:  map[string]string{"main": "https://api.example.com/?a=1&b=<2>"},
+			SiteEndpoints: map[string]string{"web": "https://example.com"},
+		}),
+		{Stack: "dev", Target: "gcp", Error: "not deployed"},
+	}
+	return NewReport(spec, envs)
+}
+
+func TestNewReport(t *testing.T) {
+	r := testReport()
+
+	want := []Function{
+		{Name: "orders", Kind: "function", Source: "functions/orders.ts", Triggers: []string{"api:main GET /orders", "topic:sales"}},
+		{Name: "thumbs", Kind: "container", Source: "thumbs.dockerfile", Triggers: []string{"bucket:images"}},
+	}
+	if !reflect.DeepEqual(r.Functions, want) {
+		t.Errorf("Functions = %+v, want %+v", r.Functions, want)
+	}
+
+	endpoints := r.Environments[0].Endpoints
+	if len(endpoints) != 2 || endpoints[0].Kind != "api" || endpoints[1].Kind != "site" {
+		t.Errorf("Endpoints = %+v, want the api then the site", endpoints)
+	}
+}
+
+func TestRender(t *testing.T) {
+	r := testReport()
+
+	md := &bytes.Buffer{}
+	if err := r.Render("markdown", md); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"# shop", "### prod (aws/us-east-1)", "| api | main | https://api.example.com/?a=1&b=<2> |", "not deployed", "| GET | /orders | orders |", "| daily | @daily | sales |", "- api-key"} {
+		if !strings.Contains(md.String(), s) {
+			t.Errorf("markdown is missing %q:\n%s", s, md)
+		}
+	}
+
+	html := &bytes.Buffer{}
+	if err := r.Render("html", html); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"<h1>shop</h1>", "https://api.example.com/?a=1&amp;b=&lt;2&gt;", "<td>api:main GET /orders<br>topic:sales</td>"} {
+		if !strings.Contains(html.String(), s) {
+			t.Errorf("html is missing %q:\n%s", s, html)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+// en is the catalog messages fall back to when the locale's catalogs don't have them. Its ids
+// are the ones translated by the catalogs in the locales directories.
+var en = Catalog{
+	"gather.start": "Gathering configuration from code..",
+	"gather.stop":  "Configuration gathered",
+	"build.start":  "Building Images",
+	"build.stop":   "Images built",
+	"deploy.start": "Deploying..",
+	"deploy.stop":  "Stack",
+	"delete.start": "Deleting..",
+	"delete.stop":  "Stack",
+	"verify.start": "Verifying functions",
+	"verify.stop":  "Functions serving",
+	"smoke.start":  "Calling the apis and publishing to the topics",
+	"smoke.stop":   "Apis and topics checked",
+	"sign.start":   "Signing Images",
+	"sign.stop":    "Images signed",
+	"replay.start": "Replaying %s",
+	"replay.stop":  "Replayed %s",
+
+	"project.name.prompt": "What is the name of the project?",
+	"stack.name.prompt":   "What do you want to call your new stack?",
+	"stack.cloud.prompt":  "Which Cloud do you wish to deploy to?",
+	"stack.down.confirm":  "Warning - This operation will destroy your stack, all deployed resources will be removed. Are you sure you want to proceed?",
+	"stack.gc.confirm":    "Delete these %d resources?",
+}
@@ -0,0 +1,147 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v2"
+
+	"github.com/nitrictech/cli/pkg/settings"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// Catalog maps message ids to the messages printed for them, which are fmt formats when the
+// message has arguments.
+type Catalog map[string]string
+
+var (
+	current Catalog
+	once    sync.Once
+)
+
+// Locale is the locale the CLI's messages are printed in, from NITRIC_LOCALE, then the locale
+// setting, then the environment of the shell.
+func Locale() string {
+	if l := os.Getenv("NITRIC_LOCALE"); l != "" {
+		return l
+	}
+	if l := settings.Get().Locale; l != "" {
+		return l
+	}
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if l := os.Getenv(env); l != "" {
+			return l
+		}
+	}
+	return "en"
+}
+
+// catalogNames are the catalog files of locale, the most specific first, e.g. de_DE.UTF-8 is
+// read from de_DE.yaml then de.yaml.
+func catalogNames(locale string) []string {
+	locale = strings.SplitN(locale, ".", 2)[0]
+	locale = strings.SplitN(locale, "@", 2)[0]
+	locale = strings.ReplaceAll(locale, "-", "_")
+
+	if locale == "" || locale == "C" || locale == "POSIX" {
+		return nil
+	}
+
+	names := []string{locale + ".yaml"}
+	if lang := strings.SplitN(locale, "_", 2)[0]; lang != locale {
+		names = append(names, lang+".yaml")
+	}
+	return names
+}
+
+// Load reads the catalogs of locale from the user's config directory and the project in dir. The
+// project's messages override the user's, and messages missing from both are in English.
+func Load(dir, locale string) (Catalog, error) {
+	c := Catalog{}
+	for k, v := range en {
+		c[k] = v
+	}
+
+	names := catalogNames(locale)
+	dirs := []string{
+		filepath.Join(utils.NitricConfigDir(), "locales"),
+		filepath.Join(dir, ".nitric", "locales"),
+	}
+	for _, d := range dirs {
+		// the less specific catalog is read first so the specific one overrides it
+		for i := len(names) - 1; i >= 0; i-- {
+			file := filepath.Join(d, names[i])
+			b, err := ioutil.ReadFile(file)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			messages := map[string]string{}
+			if err := yaml.Unmarshal(b, &messages); err != nil {
+				return nil, errors.WithMessage(err, file)
+			}
+			for k, v := range messages {
+				if v != "" {
+					c[k] = v
+				}
+			}
+		}
+	}
+	return c, nil
+}
+
+// T returns the message id in the catalog, formatted with args. Ids missing from the catalog are
+// returned as they are, so a typo shows up rather than an empty message.
+func (c Catalog) T(id string, args ...interface{}) string {
+	msg, ok := c[id]
+	if !ok {
+		msg = id
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// T returns the message id in the catalog of the CLI's locale, formatted with args.
+func T(id string, args ...interface{}) string {
+	once.Do(func() {
+		current = en
+
+		wd, err := os.Getwd()
+		if err != nil {
+			return
+		}
+		c, err := Load(wd, Locale())
+		if err != nil {
+			pterm.Warning.Println("Ignoring the message catalogs:", err)
+			return
+		}
+		current = c
+	})
+	return current.T(id, args...)
+}
@@ -0,0 +1,105 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFile(t *testing.T, file, content string) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCatalogNames(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   []string
+	}{
+		{locale: "de", want: []string{"de.yaml"}},
+		{locale: "de_DE.UTF-8", want: []string{"de_DE.yaml", "de.yaml"}},
+		{locale: "pt-BR", want: []string{"pt_BR.yaml", "pt.yaml"}},
+		{locale: "sr_RS@latin", want: []string{"sr_RS.yaml", "sr.yaml"}},
+		{locale: "C.UTF-8"},
+		{locale: "POSIX"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			if got := catalogNames(tt.locale); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("catalogNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	userDir := t.TempDir()
+	os.Setenv("XDG_CONFIG_HOME", userDir)
+	projectDir := t.TempDir()
+
+	writeFile(t, filepath.Join(userDir, "nitric", "locales", "de.yaml"), `
+gather.start: Konfiguration wird gelesen..
+gather.stop: Konfiguration gelesen
+build.start: Images werden gebaut
+`)
+	writeFile(t, filepath.Join(userDir, "nitric", "locales", "de_AT.yaml"), `
+build.start: Images werden erstellt
+`)
+	writeFile(t, filepath.Join(projectDir, ".nitric", "locales", "de.yaml"), `
+gather.stop: Konfiguration vom Code gelesen
+stack.gc.confirm: Diese %d Ressourcen löschen?
+`)
+
+	c, err := Load(projectDir, "de_AT.UTF-8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id   string
+		args []interface{}
+		want string
+	}{
+		{id: "gather.start", want: "Konfiguration wird gelesen.."},
+		{id: "gather.stop", want: "Konfiguration vom Code gelesen"},
+		{id: "build.start", want: "Images werden erstellt"},
+		{id: "build.stop", want: "Images built"},
+		{id: "stack.gc.confirm", args: []interface{}{3}, want: "Diese 3 Ressourcen löschen?"},
+		{id: "replay.start", args: []interface{}{"orders.json"}, want: "Replaying orders.json"},
+		{id: "missing.id", want: "missing.id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := c.T(tt.id, tt.args...); got != tt.want {
+				t.Errorf("T() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	writeFile(t, filepath.Join(projectDir, ".nitric", "locales", "fr.yaml"), "gather.start: [")
+	if _, err := Load(projectDir, "fr"); err == nil {
+		t.Error("Load() expected an error for an invalid catalog")
+	}
+}
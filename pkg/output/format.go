@@ -22,7 +22,6 @@ import (
 	"io"
 	"os"
 	"reflect"
-	"sort"
 	"strings"
 
 	"github.com/davecgh/go-spew/spew"
@@ -33,54 +32,136 @@ import (
 )
 
 var (
-	allowedFormats = []string{"json", "yaml", "table"}
 	defaultFormat  = "table"
 	outputFormat   string
-	OutputTypeFlag = pflagext.NewStringEnumVar(&outputFormat, allowedFormats, defaultFormat)
+	OutputTypeFlag = pflagext.NewStringEnumVar(&outputFormat, []string{}, defaultFormat)
+	// Out is where Print writes, stdout unless the cli is embedded
+	Out io.Writer = os.Stdout
 )
 
 func Print(object interface{}) {
-	switch outputFormat {
-	case "json":
-		printJson(object)
-	case "yaml":
-		printYaml(object)
-	default:
-		printTable(object)
+	object = applySortAndFilter(object)
+
+	if Quiet {
+		printQuiet(object, Out)
+		return
+	}
+
+	err := render(object, outputFormat, Out)
+	if err != nil {
+		panic(err)
 	}
 }
 
-func printJson(object interface{}) {
+// render writes the object with the renderer registered for format, falling back to a table.
+func render(object interface{}, format string, out io.Writer) error {
+	r, ok := renderers[format]
+	if !ok {
+		r = renderers[defaultFormat]
+	}
+	return r.Render(object, out)
+}
+
+func printJson(object interface{}, out io.Writer) error {
 	b, err := json.MarshalIndent(object, "", "  ")
 	if err != nil {
-		panic(err)
+		return err
 	}
-	fmt.Print(string(b))
+	_, err = fmt.Fprint(out, string(b))
+	return err
 }
 
-func printYaml(object interface{}) {
+func printYaml(object interface{}, out io.Writer) error {
 	b, err := yaml.Marshal(object)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	fmt.Print(string(b))
+	_, err = fmt.Fprint(out, string(b))
+	return err
 }
 
-func printTable(object interface{}) {
-	ro := reflect.TypeOf(object)
+func printTable(object interface{}, out io.Writer) error {
+	tab := newTable(object)
+	if tab == nil {
+		spew.Fdump(out, object)
+		return nil
+	}
+	tab.SetOutputMirror(out)
+	tab.Render()
+	return nil
+}
 
-	switch ro.Kind() {
+// printMarkdown prints the table as GitHub flavored markdown, e.g. for pull request comments.
+func printMarkdown(object interface{}, out io.Writer) error {
+	tab := newTable(object)
+	if tab == nil {
+		_, err := fmt.Fprintf(out, "```\n%s```\n", spew.Sdump(object))
+		return err
+	}
+	if reflect.TypeOf(object).Kind() == reflect.Struct {
+		// markdown tables can't be rendered without a header
+		tab.AppendHeader(table.Row{"field", "value"})
+	}
+	tab.SetOutputMirror(out)
+	tab.RenderMarkdown()
+	return nil
+}
+
+// newTable returns the table of a map, list or struct, nil for other kinds.
+func newTable(object interface{}) table.Writer {
+	if object == nil {
+		return nil
+	}
+
+	switch reflect.TypeOf(object).Kind() {
 	case reflect.Map:
-		printMap(object, os.Stdout)
+		return mapTable(object)
 	case reflect.Array, reflect.Slice:
-		printList(object, os.Stdout)
+		return listTable(object)
 	case reflect.Struct:
-		printStruct(object, os.Stdout)
+		return structTable(object)
+	default:
+		return nil
+	}
+}
+
+// printQuiet prints just the primary identifier of each item, one per line. This is the key of
+// a map entry, otherwise the first column of a struct.
+func printQuiet(object interface{}, out io.Writer) {
+	if object == nil {
+		return
+	}
+
+	v := reflect.ValueOf(object)
+	switch v.Kind() {
+	case reflect.Map:
+		keys := v.MapKeys()
+		sortKeys(v, keys, SortBy)
+		for _, k := range keys {
+			fmt.Fprintln(out, k)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fmt.Fprintln(out, primaryIdentifier(v.Index(i)))
+		}
 	default:
-		spew.Dump(object)
+		fmt.Fprintln(out, primaryIdentifier(v))
 	}
 }
 
+func primaryIdentifier(v reflect.Value) interface{} {
+	v = reflect.Indirect(v)
+	if v.Kind() != reflect.Struct {
+		return v
+	}
+	for i := 0; i < v.NumField(); i++ {
+		if nameFromField(v.Type().Field(i)) != "" {
+			return reflect.Indirect(v.Field(i))
+		}
+	}
+	return v
+}
+
 func tags(f reflect.StructField) []string {
 	if f.Tag != "" {
 		for _, tName := range []string{"yaml", "json"} {
@@ -121,7 +202,7 @@ func namesFrom(t reflect.Type) table.Row {
 	return names
 }
 
-// printList will print something like the following:
+// listTable returns a table like the following:
 // +--------------+-----------------+--------+--------------------------------+
 // | ID           | REPOSITORY      | TAG    | CREATEDAT                      |
 // +--------------+-----------------+--------+--------------------------------+
@@ -129,9 +210,8 @@ func namesFrom(t reflect.Type) table.Row {
 // | 49e64c2fd5c1 | go-read-local   | latest | 2022-01-07 15:19:18 +1000 AEST |
 // | ea9f8d14df25 | go-list-local   | latest | 2022-01-07 15:18:44 +1000 AEST |
 // +--------------+-----------------+--------+--------------------------------+
-func printList(object interface{}, out io.Writer) {
+func listTable(object interface{}) table.Writer {
 	tab := table.NewWriter()
-	tab.SetOutputMirror(out)
 
 	t := reflect.TypeOf(object)
 	names := namesFrom(t.Elem())
@@ -163,19 +243,18 @@ func printList(object interface{}, out io.Writer) {
 		}
 	}
 	tab.AppendRows(rows)
-	tab.Render()
+	return tab
 }
 
-// printMap will print something like the following:
+// mapTable returns a table like the following:
 // +----------+-------------+----------+--------+
 // | KEY      | NAME        | PROVIDER | REGION |
 // +----------+-------------+----------+--------+
 // | local    | default     | local    |        |
 // | test-app | super-duper | aws      | eastus |
 // +----------+-------------+----------+--------+
-func printMap(object interface{}, out io.Writer) {
+func mapTable(object interface{}) table.Writer {
 	tab := table.NewWriter()
-	tab.SetOutputMirror(out)
 
 	names := namesFrom(reflect.TypeOf(object).Elem())
 	tab.AppendHeader(append(table.Row{"key"}, names...))
@@ -189,9 +268,7 @@ func printMap(object interface{}, out io.Writer) {
 	for iter.Next() {
 		keyList = append(keyList, iter.Key())
 	}
-	sort.SliceStable(keyList, func(i, j int) bool {
-		return keyList[i].String() < keyList[j].String()
-	})
+	sortKeys(value, keyList, SortBy)
 	for _, k := range keyList {
 		v := value.MapIndex(k)
 
@@ -212,19 +289,18 @@ func printMap(object interface{}, out io.Writer) {
 		}
 	}
 	tab.AppendRows(rows)
-	tab.Render()
+	return tab
 }
 
-// printStruct will print something like the following:
+// structTable returns a table like the following:
 //+------------+--------------------------------+
 //| ID         | 6e83378b322a                   |
 //| REPOSITORY | go-create-local                |
 //| TAG        | latest                         |
 //| CREATEDAT  | 2022-01-07 15:19:01 +1000 AEST |
 //+------------+--------------------------------+
-func printStruct(object interface{}, out io.Writer) {
+func structTable(object interface{}) table.Writer {
 	tab := table.NewWriter()
-	tab.SetOutputMirror(out)
 
 	rows := []table.Row{}
 	v := reflect.ValueOf(object)
@@ -237,5 +313,5 @@ func printStruct(object interface{}, out io.Writer) {
 	}
 
 	tab.AppendRows(rows)
-	tab.Render()
+	return tab
 }
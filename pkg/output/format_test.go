@@ -18,6 +18,8 @@ package output
 
 import (
 	"bytes"
+	"fmt"
+	"io"
 	"sort"
 	"strings"
 	"testing"
@@ -47,7 +49,7 @@ func Test_printStruct(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			buf := &bytes.Buffer{}
-			printStruct(tt.object, buf)
+			_ = printTable(tt.object, buf)
 			if !cmp.Equal(tt.expect, buf.String()) {
 				t.Error(cmp.Diff(tt.expect, buf.String()))
 			}
@@ -82,7 +84,7 @@ func Test_printList(t *testing.T) {
 			sort.SliceStable(tt.object, func(i, j int) bool {
 				return strings.Compare(tt.object[i].Provider, tt.object[j].Provider) < 0
 			})
-			printList(tt.object, buf)
+			_ = printTable(tt.object, buf)
 			if !cmp.Equal(tt.expect, buf.String()) {
 				t.Error(cmp.Diff(tt.expect, buf.String()))
 			}
@@ -114,10 +116,163 @@ func Test_printMap(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			out := &bytes.Buffer{}
-			printMap(tt.object, out)
+			_ = printTable(tt.object, out)
 			if !cmp.Equal(tt.wantOut, out.String()) {
 				t.Error(cmp.Diff(tt.wantOut, out.String()))
 			}
 		})
 	}
 }
+
+func Test_printMarkdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		object interface{}
+		lines  []string
+	}{
+		{
+			name: "list",
+			object: []stack.Config{
+				{Name: "a", Provider: "azure", Region: "somewhere"},
+				{Name: "b", Provider: "aws", Region: "x|z"},
+			},
+			lines: []string{"| --- | --- | --- |", "| a | azure | somewhere |", "| b | aws | x\\|z |"},
+		},
+		{
+			name:   "struct",
+			object: stack.Config{Name: "prod", Provider: "azure"},
+			lines:  []string{"| --- | --- |", "| NAME | prod |", "| PROVIDER | azure |"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := printMarkdown(tt.object, buf); err != nil {
+				t.Fatal(err)
+			}
+			got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			for _, l := range tt.lines {
+				found := false
+				for _, g := range got {
+					found = found || g == l
+				}
+				if !found {
+					t.Errorf("printMarkdown() = %s, missing the line %s", buf.String(), l)
+				}
+			}
+		})
+	}
+}
+
+func Test_sortAndFilter(t *testing.T) {
+	list := []stack.Config{
+		{Name: "b", Provider: "aws", Region: "xyz"},
+		{Name: "a", Provider: "azure", Region: "somewhere"},
+		{Name: "c", Provider: "aws", Region: "abc"},
+	}
+
+	tests := []struct {
+		name    string
+		sortBy  string
+		filters []string
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name:   "sort",
+			sortBy: "name",
+			want: []stack.Config{
+				{Name: "a", Provider: "azure", Region: "somewhere"},
+				{Name: "b", Provider: "aws", Region: "xyz"},
+				{Name: "c", Provider: "aws", Region: "abc"},
+			},
+		},
+		{
+			name:    "filter and sort",
+			sortBy:  "region",
+			filters: []string{"provider=aws"},
+			want: []stack.Config{
+				{Name: "c", Provider: "aws", Region: "abc"},
+				{Name: "b", Provider: "aws", Region: "xyz"},
+			},
+		},
+		{
+			name:    "unknown column",
+			filters: []string{"colour=red"},
+			want:    list,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sortAndFilter(list, tt.sortBy, tt.filters)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("sortAndFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !cmp.Equal(tt.want, got) {
+				t.Error(cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
+
+func Test_printQuiet(t *testing.T) {
+	tests := []struct {
+		name   string
+		object interface{}
+		expect string
+	}{
+		{
+			name: "list",
+			object: []stack.Config{
+				{Name: "a", Provider: "azure"},
+				{Name: "b", Provider: "aws"},
+			},
+			expect: "a\nb\n",
+		},
+		{
+			name: "map",
+			object: map[string]stack.Config{
+				"t3": {Provider: "aws"},
+				"t1": {Provider: "azure"},
+			},
+			expect: "t1\nt3\n",
+		},
+		{
+			name:   "strings",
+			object: []string{"x", "y"},
+			expect: "x\ny\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			printQuiet(tt.object, buf)
+			if !cmp.Equal(tt.expect, buf.String()) {
+				t.Error(cmp.Diff(tt.expect, buf.String()))
+			}
+		})
+	}
+}
+
+func Test_render(t *testing.T) {
+	Register("names", RendererFunc(func(object interface{}, out io.Writer) error {
+		for _, c := range object.([]stack.Config) {
+			fmt.Fprintf(out, "<%s>", c.Name)
+		}
+		return nil
+	}))
+
+	if err := OutputTypeFlag.Set("names"); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	err := render([]stack.Config{{Name: "a"}, {Name: "b"}}, "names", buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal("<a><b>", buf.String()) {
+		t.Error(cmp.Diff("<a><b>", buf.String()))
+	}
+}
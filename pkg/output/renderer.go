@@ -0,0 +1,56 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Renderer writes an object to out in a single output format.
+type Renderer interface {
+	Render(object interface{}, out io.Writer) error
+}
+
+// RendererFunc adapts a function to a Renderer.
+type RendererFunc func(object interface{}, out io.Writer) error
+
+func (f RendererFunc) Render(object interface{}, out io.Writer) error {
+	return f(object, out)
+}
+
+var renderers = map[string]Renderer{}
+
+// Register makes a Renderer available as an --output format. It is intended to be called
+// from an init function and panics if the format is already registered.
+func Register(format string, r Renderer) {
+	if _, ok := renderers[format]; ok {
+		panic(fmt.Sprintf("output format %s is already registered", format))
+	}
+	renderers[format] = r
+
+	OutputTypeFlag.Allowed = append(OutputTypeFlag.Allowed, format)
+	sort.Strings(OutputTypeFlag.Allowed)
+}
+
+func init() {
+	Register("json", RendererFunc(printJson))
+	Register("yaml", RendererFunc(printYaml))
+	Register("table", RendererFunc(printTable))
+	Register("markdown", RendererFunc(printMarkdown))
+}
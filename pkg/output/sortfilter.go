@@ -0,0 +1,176 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pterm/pterm"
+)
+
+var (
+	// SortBy orders lists and maps by this column (the yaml/json tag of a field, "key" or "value")
+	SortBy string
+	// Filters only keeps the rows where column=value
+	Filters []string
+)
+
+// column returns the value of the named column in a row, matching the field's yaml/json tag.
+func column(row reflect.Value, key reflect.Value, name string) (reflect.Value, bool) {
+	if strings.EqualFold(name, "key") && key.IsValid() {
+		return key, true
+	}
+
+	row = reflect.Indirect(row)
+	if row.Kind() != reflect.Struct {
+		return row, strings.EqualFold(name, "value")
+	}
+
+	for i := 0; i < row.NumField(); i++ {
+		if strings.EqualFold(nameFromField(row.Type().Field(i)), name) {
+			return reflect.Indirect(row.Field(i)), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func lessValue(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if b.Kind() == a.Kind() {
+			return a.Int() < b.Int()
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if b.Kind() == a.Kind() {
+			return a.Uint() < b.Uint()
+		}
+	case reflect.Float32, reflect.Float64:
+		if b.Kind() == a.Kind() {
+			return a.Float() < b.Float()
+		}
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+type filter struct {
+	column string
+	value  string
+}
+
+func parseFilters(filters []string) ([]filter, error) {
+	parsed := []filter{}
+	for _, f := range filters {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("filter %s must be of the form column=value", f)
+		}
+		parsed = append(parsed, filter{column: kv[0], value: kv[1]})
+	}
+	return parsed, nil
+}
+
+func matches(row reflect.Value, key reflect.Value, filters []filter) (bool, error) {
+	for _, f := range filters {
+		v, ok := column(row, key, f.column)
+		if !ok {
+			return false, fmt.Errorf("unknown column %s", f.column)
+		}
+		if fmt.Sprint(v) != f.value {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// sortAndFilter applies the --filter and --sort-by flags to lists and maps, other objects are returned as is.
+func sortAndFilter(object interface{}, sortBy string, filters []string) (interface{}, error) {
+	if object == nil || (sortBy == "" && len(filters) == 0) {
+		return object, nil
+	}
+
+	parsed, err := parseFilters(filters)
+	if err != nil {
+		return object, err
+	}
+
+	v := reflect.ValueOf(object)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		out := reflect.MakeSlice(reflect.SliceOf(v.Type().Elem()), 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			ok, err := matches(v.Index(i), reflect.Value{}, parsed)
+			if err != nil {
+				return object, err
+			}
+			if ok {
+				out = reflect.Append(out, v.Index(i))
+			}
+		}
+
+		if sortBy != "" && out.Len() > 0 {
+			if _, ok := column(out.Index(0), reflect.Value{}, sortBy); !ok {
+				return object, fmt.Errorf("unknown column %s", sortBy)
+			}
+			sort.SliceStable(out.Interface(), func(i, j int) bool {
+				a, _ := column(out.Index(i), reflect.Value{}, sortBy)
+				b, _ := column(out.Index(j), reflect.Value{}, sortBy)
+				return lessValue(a, b)
+			})
+		}
+		return out.Interface(), nil
+	case reflect.Map:
+		// maps are sorted when printed as a table
+		out := reflect.MakeMap(v.Type())
+		iter := v.MapRange()
+		for iter.Next() {
+			ok, err := matches(iter.Value(), iter.Key(), parsed)
+			if err != nil {
+				return object, err
+			}
+			if ok {
+				out.SetMapIndex(iter.Key(), iter.Value())
+			}
+		}
+		return out.Interface(), nil
+	}
+	return object, nil
+}
+
+// sortKeys orders the keys of a map by the --sort-by column, or by key when it is not set.
+func sortKeys(m reflect.Value, keys []reflect.Value, sortBy string) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		if sortBy != "" {
+			a, aok := column(m.MapIndex(keys[i]), keys[i], sortBy)
+			b, bok := column(m.MapIndex(keys[j]), keys[j], sortBy)
+			if aok && bok {
+				return lessValue(a, b)
+			}
+		}
+		return keys[i].String() < keys[j].String()
+	})
+}
+
+func applySortAndFilter(object interface{}) interface{} {
+	out, err := sortAndFilter(object, SortBy, Filters)
+	if err != nil {
+		pterm.Warning.Println(err)
+	}
+	return out
+}
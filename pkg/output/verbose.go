@@ -17,33 +17,56 @@
 package output
 
 import (
+	"bufio"
 	"io"
+	"strings"
 
 	"github.com/pterm/pterm"
 )
 
+// The verbosity tiers, each includes the output of the tiers below it.
+const (
+	// VerboseNormal is the progress and results of commands
+	VerboseNormal = 1
+	// VerboseDiagnostics adds the diagnostics of the pulumi resources and the docker build output
+	VerboseDiagnostics = 2
+	// VerboseDebug adds the debug logs of pulumi and the providers, including their API calls
+	VerboseDebug = 3
+)
+
 var (
 	VerboseLevel int
 	CI           bool
+	// Quiet prints only the primary identifiers of the output, one per line
+	Quiet bool
 )
 
 type Progress interface {
 	Debugf(format string, a ...interface{})
 	Busyf(format string, a ...interface{})
+	// Progressf is Busyf for determinate work, done of total units (e.g. resources) are complete
+	Progressf(done, total int, format string, a ...interface{})
 	Successf(format string, a ...interface{})
 	Failf(format string, a ...interface{})
+	// SubTask returns a Progress whose messages are nested under name
+	SubTask(name string) Progress
 }
 
+// Verbose is true when the output includes the verbosity tier.
+func Verbose(level int) bool {
+	return VerboseLevel >= level
+}
+
+// StdoutToPtermDebug prints each line read from b as a debug message, whole lines are printed so they
+// are not split around the spinner.
 func StdoutToPtermDebug(b io.ReadCloser, p Progress, prefix string) {
 	defer b.Close()
-	buf := make([]byte, 1024)
-	for {
-		n, err := b.Read(buf)
-		if err != nil {
-			break
-		}
-		p.Debugf("%s %v", prefix, string(buf[:n]))
+	scanner := bufio.NewScanner(b)
+	for scanner.Scan() {
+		p.Debugf("%s %v", prefix, scanner.Text())
 	}
+	// keep draining, so the writer doesn't block on lines that are too long to scan
+	_, _ = io.Copy(io.Discard, b)
 }
 
 type pTermWriter struct {
@@ -51,7 +74,11 @@ type pTermWriter struct {
 }
 
 func (p *pTermWriter) Write(b []byte) (n int, err error) {
-	p.prefix.Println(string(b))
+	for _, line := range strings.Split(strings.TrimRight(string(b), "\n"), "\n") {
+		if strings.TrimSpace(line) != "" {
+			p.prefix.Println(line)
+		}
+	}
 
 	return len(b), nil
 }
@@ -59,3 +86,11 @@ func (p *pTermWriter) Write(b []byte) (n int, err error) {
 func NewPtermWriter(prefix pterm.PrefixPrinter) *pTermWriter {
 	return &pTermWriter{prefix: prefix}
 }
+
+// VerboseWriter prints what is written to it as debug messages when the output includes the verbosity tier.
+func VerboseWriter(level int) io.Writer {
+	if !Verbose(level) {
+		return io.Discard
+	}
+	return NewPtermWriter(pterm.Debug)
+}
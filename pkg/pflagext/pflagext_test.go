@@ -0,0 +1,141 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pflagext
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestStringEnum(t *testing.T) {
+	var format string
+	cmd := &cobra.Command{Use: "test"}
+	if err := AddStringEnumVarP(cmd, &format, "output", "o", []string{"json", "yaml"}, "json", "output format"); err != nil {
+		t.Fatal(err)
+	}
+
+	if format != "json" {
+		t.Errorf("default = %s, want json", format)
+	}
+	if err := cmd.Flags().Set("output", "yaml"); err != nil || format != "yaml" {
+		t.Errorf("Set(yaml) error = %v, value = %s", err, format)
+	}
+	if err := cmd.Flags().Set("output", "xml"); err == nil {
+		t.Error("Set(xml) expected an error")
+	}
+
+	cmd.Flags().Lookup("output").Value.(*stringEnum).Reset()
+	if format != "json" {
+		t.Errorf("after Reset() = %s, want json", format)
+	}
+}
+
+func TestStringMap(t *testing.T) {
+	noSecrets := func(key, value string) error {
+		if strings.HasPrefix(key, "SECRET") {
+			return fmt.Errorf("%s can't be set on the command line", key)
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "repeated",
+			args: []string{"A=1", "B=x,y", "C="},
+			want: map[string]string{"A": "1", "B": "x,y", "C": ""},
+		},
+		{
+			name: "value with equals",
+			args: []string{"QUERY=a=b"},
+			want: map[string]string{"QUERY": "a=b"},
+		},
+		{
+			name:    "missing value",
+			args:    []string{"A"},
+			wantErr: "A must be of the form key=value",
+		},
+		{
+			name:    "duplicate key",
+			args:    []string{"A=1", "A=2"},
+			wantErr: "A is given more than once",
+		},
+		{
+			name:    "invalid",
+			args:    []string{"SECRET_KEY=1"},
+			wantErr: "SECRET_KEY can't be set on the command line",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var env map[string]string
+			m := NewStringMapVar(&env, noSecrets)
+
+			var err error
+			for _, a := range tt.args {
+				if err = m.Set(a); err != nil {
+					break
+				}
+			}
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Set() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(env, tt.want) {
+				t.Errorf("value = %v, want %v", env, tt.want)
+			}
+
+			m.Reset()
+			if len(env) != 0 {
+				t.Errorf("after Reset() = %v, want no pairs", env)
+			}
+		})
+	}
+}
+
+func TestStringMapCompletion(t *testing.T) {
+	var params map[string]string
+	cmd := &cobra.Command{Use: "test", Run: func(cmd *cobra.Command, args []string) {}}
+	if err := AddStringMapVarP(cmd, &params, "param", "", nil, func() []string { return []string{"region", "tenant"} }, "parameters"); err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{cobra.ShellCompRequestCmd, "--param", ""})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "region=\ntenant=\n:") {
+		t.Errorf("completions = %q, want region= and tenant=", out.String())
+	}
+}
@@ -19,11 +19,14 @@ package pflagext
 import (
 	"fmt"
 	"strings"
+
+	"github.com/spf13/cobra"
 )
 
 type stringEnum struct {
 	Allowed []string
 	ValueP  *string
+	Default string
 }
 
 // NewStringEnumVar give a list of allowed flag parameters, where the second argument is the default
@@ -32,6 +35,7 @@ func NewStringEnumVar(value *string, allowed []string, d string) *stringEnum {
 	return &stringEnum{
 		Allowed: allowed,
 		ValueP:  value,
+		Default: d,
 	}
 }
 
@@ -55,6 +59,23 @@ func (e *stringEnum) Set(p string) error {
 	return nil
 }
 
+// Reset sets the flag back to its default, which need not be one of the allowed values.
+func (e *stringEnum) Reset() {
+	*e.ValueP = e.Default
+}
+
 func (e *stringEnum) Type() string {
 	return "stringEnumVar"
 }
+
+// Complete is the flag's shell completion, its allowed values.
+func (e *stringEnum) Complete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return e.Allowed, cobra.ShellCompDirectiveNoFileComp
+}
+
+// AddStringEnumVarP adds an enum flag to the command, that completes to its allowed values.
+func AddStringEnumVarP(cmd *cobra.Command, value *string, name, shorthand string, allowed []string, d, usage string) error {
+	e := NewStringEnumVar(value, allowed, d)
+	cmd.Flags().VarP(e, name, shorthand, usage)
+	return cmd.RegisterFlagCompletionFunc(name, e.Complete)
+}
@@ -0,0 +1,91 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pflagext
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type stringMap struct {
+	ValueP   *map[string]string
+	Validate func(key, value string) error
+}
+
+// NewStringMapVar is a repeated key=value flag, e.g. --env A=1 --env B=2. Each key can be given once,
+// and validate, when set, checks each pair as it is given.
+func NewStringMapVar(value *map[string]string, validate func(key, value string) error) *stringMap {
+	*value = map[string]string{}
+	return &stringMap{
+		ValueP:   value,
+		Validate: validate,
+	}
+}
+
+func (m *stringMap) String() string {
+	pairs := []string{}
+	for k, v := range *m.ValueP {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (m *stringMap) Set(p string) error {
+	kv := strings.SplitN(p, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return fmt.Errorf("%s must be of the form key=value", p)
+	}
+	if _, ok := (*m.ValueP)[kv[0]]; ok {
+		return fmt.Errorf("%s is given more than once", kv[0])
+	}
+	if m.Validate != nil {
+		if err := m.Validate(kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	(*m.ValueP)[kv[0]] = kv[1]
+	return nil
+}
+
+// Reset removes the pairs that were given.
+func (m *stringMap) Reset() {
+	*m.ValueP = map[string]string{}
+}
+
+func (m *stringMap) Type() string {
+	return "key=value"
+}
+
+// AddStringMapVarP adds a repeated key=value flag to the command, that completes to the keys returned by
+// keys when it is set.
+func AddStringMapVarP(cmd *cobra.Command, value *map[string]string, name, shorthand string, validate func(key, value string) error, keys func() []string, usage string) error {
+	cmd.Flags().VarP(NewStringMapVar(value, validate), name, shorthand, usage)
+	if keys == nil {
+		return nil
+	}
+	return cmd.RegisterFlagCompletionFunc(name, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		completions := []string{}
+		for _, k := range keys() {
+			completions = append(completions, k+"=")
+		}
+		return completions, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CacheEnvPrefix is the prefix of the env vars that hold the connection details of a cache,
+// e.g. CACHE_SESSIONS for the sessions cache.
+func CacheEnvPrefix(name string) string {
+	return "CACHE_" + nonEnvChars.ReplaceAllString(strings.ToUpper(name), "_")
+}
+
+// CachesFor returns the names of the caches the compute unit connects to.
+func (s *Project) CachesFor(name string) []string {
+	caches := []string{}
+	for k, c := range s.Caches {
+		for _, f := range c.Functions {
+			if f == name {
+				caches = append(caches, k)
+				break
+			}
+		}
+	}
+	sort.Strings(caches)
+	return caches
+}
+
+// ValidateCaches checks the caches are used by functions that exist.
+func (s *Project) ValidateCaches() error {
+	names := map[string]bool{}
+	for _, c := range s.Computes() {
+		names[c.Unit().Name] = true
+	}
+
+	for k, c := range s.Caches {
+		for _, f := range c.Functions {
+			if !names[f] {
+				return fmt.Errorf("cache %s is used by function %s, but the function does not exist", k, f)
+			}
+		}
+	}
+	return nil
+}
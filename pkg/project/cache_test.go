@@ -0,0 +1,50 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCaches(t *testing.T) {
+	s := &Project{
+		Functions: map[string]Function{
+			"orders": {ComputeUnit: ComputeUnit{Name: "orders"}},
+		},
+		Caches: map[string]Cache{
+			"sessions": {Functions: []string{"orders"}},
+			"unused":   {},
+		},
+	}
+
+	if got := CacheEnvPrefix("user-sessions"); got != "CACHE_USER_SESSIONS" {
+		t.Errorf("CacheEnvPrefix() = %s, want CACHE_USER_SESSIONS", got)
+	}
+	if err := s.ValidateCaches(); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.CachesFor("orders"); !cmp.Equal(got, []string{"sessions"}) {
+		t.Error(cmp.Diff(got, []string{"sessions"}))
+	}
+
+	s.Caches["missing"] = Cache{Functions: []string{"billing"}}
+	if err := s.ValidateCaches(); err == nil {
+		t.Error("expected an error for a missing function")
+	}
+}
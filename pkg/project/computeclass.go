@@ -0,0 +1,83 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ComputeClass is a size of compute instance, it sets the CPU and memory together.
+type ComputeClass struct {
+	// The number of vCPUs
+	Cpu float64
+
+	// The memory in MB
+	Memory int
+}
+
+// ComputeClasses are the classes a compute unit can request, providers error when they can't
+// provide the class.
+var ComputeClasses = map[string]ComputeClass{
+	"small":  {Cpu: 0.25, Memory: 512},
+	"medium": {Cpu: 1, Memory: 2048},
+	"large":  {Cpu: 2, Memory: 4096},
+	"xlarge": {Cpu: 4, Memory: 8192},
+}
+
+// ComputeClass returns the class requested by the compute unit, or nil when it uses the provider's default.
+func (c *ComputeUnit) ComputeClass() *ComputeClass {
+	class, ok := ComputeClasses[c.Class]
+	if !ok {
+		return nil
+	}
+	return &class
+}
+
+// MemoryOrDefault returns the memory of the compute unit's class or its memory, or def when neither is set.
+func (c *ComputeUnit) MemoryOrDefault(def int) int {
+	if class := c.ComputeClass(); class != nil {
+		return class.Memory
+	}
+	if c.Memory != 0 {
+		return c.Memory
+	}
+	return def
+}
+
+// ValidateComputeClasses checks the compute units request a known class, without also setting their memory.
+func (s *Project) ValidateComputeClasses() error {
+	for _, c := range s.Computes() {
+		u := c.Unit()
+		if u.Class == "" {
+			continue
+		}
+		if _, ok := ComputeClasses[u.Class]; !ok {
+			classes := []string{}
+			for k := range ComputeClasses {
+				classes = append(classes, k)
+			}
+			sort.Strings(classes)
+			return fmt.Errorf("%s has an unknown compute class %s, it must be one of %s", u.Name, u.Class, strings.Join(classes, ", "))
+		}
+		if u.Memory != 0 {
+			return fmt.Errorf("%s sets both a compute class and memory, the class sets the memory", u.Name)
+		}
+	}
+	return nil
+}
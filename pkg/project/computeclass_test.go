@@ -0,0 +1,54 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import "testing"
+
+func TestComputeClasses(t *testing.T) {
+	s := &Project{
+		Functions: map[string]Function{
+			"resize": {ComputeUnit: ComputeUnit{Name: "resize", Class: "large"}},
+			"orders": {ComputeUnit: ComputeUnit{Name: "orders", Memory: 256}},
+		},
+	}
+
+	if err := s.ValidateComputeClasses(); err != nil {
+		t.Fatal(err)
+	}
+
+	resize := s.Functions["resize"]
+	if got := resize.MemoryOrDefault(128); got != 4096 {
+		t.Errorf("MemoryOrDefault() = %d, want 4096", got)
+	}
+	orders := s.Functions["orders"]
+	if got := orders.MemoryOrDefault(128); got != 256 {
+		t.Errorf("MemoryOrDefault() = %d, want 256", got)
+	}
+	if orders.ComputeClass() != nil {
+		t.Error("expected no compute class")
+	}
+
+	s.Functions["huge"] = Function{ComputeUnit: ComputeUnit{Name: "huge", Class: "huge"}}
+	if err := s.ValidateComputeClasses(); err == nil {
+		t.Error("expected an error for an unknown class")
+	}
+
+	s.Functions["huge"] = Function{ComputeUnit: ComputeUnit{Name: "huge", Class: "xlarge", Memory: 1024}}
+	if err := s.ValidateComputeClasses(); err == nil {
+		t.Error("expected an error for a class with memory")
+	}
+}
@@ -20,15 +20,68 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
+
+	"github.com/nitrictech/cli/pkg/audit"
+	"github.com/nitrictech/cli/pkg/settings"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
 )
 
 type Config struct {
-	Name     string   `yaml:"name"`
-	Dir      string   `yaml:"-"`
-	Handlers []string `yaml:"handlers"`
+	Name       string                    `yaml:"name"`
+	Dir        string                    `yaml:"-"`
+	Handlers   []string                  `yaml:"handlers,omitempty"`
+	Functions  map[string]FunctionConfig `yaml:"functions,omitempty"`
+	Containers map[string]Container      `yaml:"containers,omitempty"`
+	Services   map[string]Container      `yaml:"services,omitempty"`
+	Jobs       map[string]JobConfig      `yaml:"jobs,omitempty"`
+	Sites      map[string]Site           `yaml:"sites,omitempty"`
+	Workflows  map[string]Workflow       `yaml:"workflows,omitempty"`
+	Databases  map[string]Database       `yaml:"databases,omitempty"`
+	Caches     map[string]Cache          `yaml:"caches,omitempty"`
+	Emails     map[string]Email          `yaml:"emails,omitempty"`
+	Topics     map[string]Topic          `yaml:"topics,omitempty"`
+	Mocks      map[string]string         `yaml:"mocks,omitempty"`
+	Build      Build                     `yaml:"build,omitempty"`
+	Audit      *audit.Config             `yaml:"audit,omitempty"`
+}
+
+// AutoInit creates the nitric.yaml of a project that has none, rather than failing.
+var AutoInit bool
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9-]+`)
+
+// NameFromDir returns a valid project name based on the name of the directory.
+func NameFromDir(dir string) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(filepath.Base(dir), "-"), "-")
+	if name == "" {
+		return "project"
+	}
+	return strings.ToLower(name)
+}
+
+// NewConfig returns the config of the project in dir, named after the directory and with the handlers
+// that use the nitric SDK, or the handler_globs setting when it is set.
+func NewConfig(dir string) (*Config, error) {
+	handlers := settings.Get().HandlerGlobs
+	if len(handlers) == 0 {
+		var err error
+		handlers, err = DiscoverHandlers(dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Config{
+		Name:     NameFromDir(dir),
+		Dir:      dir,
+		Handlers: handlers,
+	}, nil
 }
 
 func (p *Config) ToFile() error {
@@ -44,8 +97,23 @@ func (p *Config) ToFile() error {
 	return ioutil.WriteFile(filepath.Join(p.Dir, "nitric.yaml"), b, 0644)
 }
 
-// Assume the project is in the currentDirectory
-func ConfigFromFile() (*Config, error) {
+// ConfigFromFile reads the project in the current directory, interpolating ${env:VAR} and, when
+// a stack is given, ${stack:name}, ${target:region} and ${param:key} references.
+func ConfigFromFile(s *stack.Config) (*Config, error) {
+	vars := map[string]string{}
+	if s != nil {
+		vars = s.Vars()
+	}
+	return configFromFile(vars)
+}
+
+// RawConfigFromFile reads the project in the current directory without interpolation, for when
+// the config will be written back.
+func RawConfigFromFile() (*Config, error) {
+	return configFromFile(nil)
+}
+
+func configFromFile(vars map[string]string) (*Config, error) {
 	wd, err := os.Getwd()
 	if err != nil {
 		return nil, err
@@ -60,8 +128,18 @@ func ConfigFromFile() (*Config, error) {
 		Dir: absDir,
 	}
 	yamlFile, err := ioutil.ReadFile(filepath.Join(wd, "nitric.yaml"))
+	if os.IsNotExist(err) && AutoInit {
+		yamlFile, err = autoInit(absDir)
+	}
 	if err != nil {
-		return nil, errors.WithMessage(err, "No nitric project found (unable to find nitric.yaml). If you haven't created a project yet, run `nitric new` to get started")
+		return nil, errors.WithMessage(err, "No nitric project found (unable to find nitric.yaml). To start a new project run `nitric new`, or run `nitric init` to add nitric.yaml to existing code")
+	}
+
+	if vars != nil {
+		yamlFile, err = utils.Interpolate(yamlFile, vars)
+		if err != nil {
+			return nil, errors.WithMessage(err, "nitric.yaml")
+		}
 	}
 
 	err = yaml.Unmarshal(yamlFile, p)
@@ -70,3 +148,15 @@ func ConfigFromFile() (*Config, error) {
 	}
 	return p, nil
 }
+
+// autoInit writes a nitric.yaml for the project in dir, returning its contents.
+func autoInit(dir string) ([]byte, error) {
+	c, err := NewConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.ToFile(); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(filepath.Join(dir, "nitric.yaml"))
+}
@@ -0,0 +1,57 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestNameFromDir(t *testing.T) {
+	tests := map[string]string{
+		"/home/me/my-app":     "my-app",
+		"/home/me/My App_2":   "my-app-2",
+		"/home/me/.hidden":    "hidden",
+		"/home/me/___":        "project",
+		"relative/ShopFront/": "shopfront",
+	}
+	for dir, want := range tests {
+		if got := NameFromDir(dir); got != want {
+			t.Errorf("NameFromDir(%q) = %q, want %q", dir, got, want)
+		}
+	}
+}
+
+func TestNewConfig(t *testing.T) {
+	// ignore the user's handler_globs setting
+	os.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	want := &Config{
+		Name:     "discover",
+		Dir:      "testdata/discover",
+		Handlers: []string{"cmd/hello/main.go", "functions/hello.ts", "lib/tasks.py"},
+	}
+
+	got, err := NewConfig("testdata/discover")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewConfig() = %+v, want %+v", got, want)
+	}
+}
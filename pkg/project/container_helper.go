@@ -18,6 +18,12 @@ package project
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+
+	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
+
+	"github.com/nitrictech/cli/pkg/cron"
 )
 
 var _ Compute = &Container{}
@@ -40,3 +46,109 @@ func (c *Container) Workers() int {
 	// Default to expecting a minimum of 1 worker for containers
 	return 1
 }
+
+// permissionActions maps the permissions of each resource type to the actions they allow,
+// matching the actions the SDKs request for the same permissions.
+var permissionActions = map[v1.ResourceType]map[string][]v1.Action{
+	v1.ResourceType_Bucket: {
+		"reading":  {v1.Action_BucketFileGet, v1.Action_BucketFileList},
+		"writing":  {v1.Action_BucketFilePut},
+		"deleting": {v1.Action_BucketFileDelete},
+	},
+	v1.ResourceType_Topic: {
+		"publishing": {v1.Action_TopicDetail, v1.Action_TopicEventPublish, v1.Action_TopicList},
+	},
+	v1.ResourceType_Queue: {
+		"sending":   {v1.Action_QueueDetail, v1.Action_QueueList, v1.Action_QueueSend},
+		"receiving": {v1.Action_QueueDetail, v1.Action_QueueList, v1.Action_QueueReceive},
+	},
+	v1.ResourceType_Collection: {
+		"reading":  {v1.Action_CollectionDocumentRead, v1.Action_CollectionList, v1.Action_CollectionQuery},
+		"writing":  {v1.Action_CollectionDocumentWrite, v1.Action_CollectionList},
+		"deleting": {v1.Action_CollectionDocumentDelete, v1.Action_CollectionList},
+	},
+	v1.ResourceType_Secret: {
+		"accessing": {v1.Action_SecretAccess},
+		"putting":   {v1.Action_SecretPut},
+	},
+}
+
+func (p ContainerPermissions) byType() map[v1.ResourceType]map[string][]string {
+	return map[v1.ResourceType]map[string][]string{
+		v1.ResourceType_Bucket:     p.Buckets,
+		v1.ResourceType_Topic:      p.Topics,
+		v1.ResourceType_Queue:      p.Queues,
+		v1.ResourceType_Collection: p.Collections,
+		v1.ResourceType_Secret:     p.Secrets,
+	}
+}
+
+// Policies returns a least privilege policy for each resource the container has permissions on.
+func (c *Container) Policies() ([]*v1.PolicyResource, error) {
+	policies := []*v1.PolicyResource{}
+	for rt, resources := range c.Permissions.byType() {
+		names := []string{}
+		for name := range resources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			actions := []v1.Action{}
+			seen := map[v1.Action]bool{}
+			for _, perm := range resources[name] {
+				permActions, ok := permissionActions[rt][perm]
+				if !ok {
+					return nil, fmt.Errorf("container %s has unknown permission %s on %s %s", c.Name, perm, strings.ToLower(rt.String()), name)
+				}
+				for _, a := range permActions {
+					if !seen[a] {
+						seen[a] = true
+						actions = append(actions, a)
+					}
+				}
+			}
+			policies = append(policies, &v1.PolicyResource{
+				Principals: []*v1.Resource{{Name: c.Name, Type: v1.ResourceType_Function}},
+				Actions:    actions,
+				Resources:  []*v1.Resource{{Name: name, Type: rt}},
+			})
+		}
+	}
+	return policies, nil
+}
+
+// ScheduleTopic returns the topic the events of a schedule are delivered to, spaces in the
+// schedule name are replaced with hyphens.
+func ScheduleTopic(schedule string) string {
+	return strings.ToLower(strings.ReplaceAll(schedule, " ", "-"))
+}
+
+// ProjectSchedules returns the container's schedules as project schedules keyed by name,
+// rates such as "5 minutes" are converted to cron expressions.
+func (c *Container) ProjectSchedules() (map[string]Schedule, error) {
+	schedules := map[string]Schedule{}
+	for k, exp := range c.Schedules {
+		if len(strings.Fields(exp)) == 2 {
+			e, err := cron.RateToCron(exp)
+			if err != nil {
+				return nil, fmt.Errorf("container %s schedule %s; %w", c.Name, k, err)
+			}
+			exp = e
+		}
+		schedules[k] = Schedule{
+			Expression: exp,
+			Target: ScheduleTarget{
+				Type: "topic",
+				Name: ScheduleTopic(k),
+			},
+			Event: ScheduleEvent{
+				PayloadType: "io.nitric.schedule",
+				Payload: map[string]interface{}{
+					"schedule": k,
+				},
+			},
+		}
+	}
+	return schedules, nil
+}
@@ -0,0 +1,95 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
+)
+
+func TestContainerPolicies(t *testing.T) {
+	c := &Container{
+		ComputeUnit: ComputeUnit{Name: "worker"},
+		Permissions: ContainerPermissions{
+			Buckets: map[string][]string{"images": {"reading", "writing"}},
+			Queues:  map[string][]string{"jobs": {"sending", "receiving"}},
+		},
+	}
+
+	got, err := c.Policies()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	principals := []*v1.Resource{{Name: "worker", Type: v1.ResourceType_Function}}
+	want := map[string]*v1.PolicyResource{
+		"images": {
+			Principals: principals,
+			Actions:    []v1.Action{v1.Action_BucketFileGet, v1.Action_BucketFileList, v1.Action_BucketFilePut},
+			Resources:  []*v1.Resource{{Name: "images", Type: v1.ResourceType_Bucket}},
+		},
+		"jobs": {
+			Principals: principals,
+			Actions:    []v1.Action{v1.Action_QueueDetail, v1.Action_QueueList, v1.Action_QueueSend, v1.Action_QueueReceive},
+			Resources:  []*v1.Resource{{Name: "jobs", Type: v1.ResourceType_Queue}},
+		},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d policies, got %d", len(want), len(got))
+	}
+	for _, p := range got {
+		if diff := cmp.Diff(want[p.Resources[0].Name], p, protocmp.Transform()); diff != "" {
+			t.Error(diff)
+		}
+	}
+
+	c.Permissions.Topics = map[string][]string{"orders": {"subscribing"}}
+	if _, err := c.Policies(); err == nil {
+		t.Error("expected an error for an unknown permission")
+	}
+}
+
+func TestContainerProjectSchedules(t *testing.T) {
+	c := &Container{
+		ComputeUnit: ComputeUnit{Name: "worker"},
+		Schedules: map[string]string{
+			"Nightly Report": "0 2 * * *",
+			"poll":           "5 minutes",
+		},
+	}
+
+	got, err := c.ProjectSchedules()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got["Nightly Report"].Expression != "0 2 * * *" || got["Nightly Report"].Target.Name != "nightly-report" {
+		t.Errorf("unexpected schedule %v", got["Nightly Report"])
+	}
+	if got["poll"].Expression != "*/5 * * * *" || got["poll"].Target.Name != "poll" {
+		t.Errorf("unexpected schedule %v", got["poll"])
+	}
+
+	c.Schedules = map[string]string{"bad": "5 weeks"}
+	if _, err := c.ProjectSchedules(); err == nil {
+		t.Error("expected an error for an invalid rate")
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const DatabaseEnginePostgres = "postgres"
+
+var (
+	nonEnvChars  = regexp.MustCompile(`[^A-Z0-9]+`)
+	nonNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// DatabaseName is the name of the database created on the server, lowercase alphanumerics are
+// accepted by all the providers.
+func DatabaseName(name string) string {
+	return nonNameChars.ReplaceAllString(strings.ToLower(name), "")
+}
+
+// DatabaseEnvPrefix is the prefix of the env vars that hold the connection details of a database,
+// e.g. DATABASE_ORDERS for the orders database.
+func DatabaseEnvPrefix(name string) string {
+	return "DATABASE_" + nonEnvChars.ReplaceAllString(strings.ToUpper(name), "_")
+}
+
+// DatabasesFor returns the names of the databases the compute unit connects to.
+func (s *Project) DatabasesFor(name string) []string {
+	dbs := []string{}
+	for k, db := range s.Databases {
+		for _, f := range db.Functions {
+			if f == name {
+				dbs = append(dbs, k)
+				break
+			}
+		}
+	}
+	sort.Strings(dbs)
+	return dbs
+}
+
+// ValidateDatabases checks the databases use a supported engine and are used by functions that exist.
+func (s *Project) ValidateDatabases() error {
+	names := map[string]bool{}
+	for _, c := range s.Computes() {
+		names[c.Unit().Name] = true
+	}
+
+	for k, db := range s.Databases {
+		if db.Engine != "" && db.Engine != DatabaseEnginePostgres {
+			return fmt.Errorf("database %s has engine %s, only %s is currently supported", k, db.Engine, DatabaseEnginePostgres)
+		}
+		for _, f := range db.Functions {
+			if !names[f] {
+				return fmt.Errorf("database %s is used by function %s, but the function does not exist", k, f)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDatabaseNames(t *testing.T) {
+	if got := DatabaseEnvPrefix("order-db"); got != "DATABASE_ORDER_DB" {
+		t.Errorf("DatabaseEnvPrefix() = %s, want DATABASE_ORDER_DB", got)
+	}
+	if got := DatabaseName("Order_DB"); got != "orderdb" {
+		t.Errorf("DatabaseName() = %s, want orderdb", got)
+	}
+}
+
+func TestDatabases(t *testing.T) {
+	s := &Project{
+		Functions: map[string]Function{
+			"orders":  {ComputeUnit: ComputeUnit{Name: "orders"}},
+			"reports": {ComputeUnit: ComputeUnit{Name: "reports"}},
+		},
+		Databases: map[string]Database{
+			"main":      {Functions: []string{"orders", "reports"}},
+			"analytics": {Engine: "postgres", Functions: []string{"reports"}},
+		},
+	}
+
+	if err := s.ValidateDatabases(); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.DatabasesFor("reports"); !cmp.Equal(got, []string{"analytics", "main"}) {
+		t.Error(cmp.Diff(got, []string{"analytics", "main"}))
+	}
+	if got := s.DatabasesFor("orders"); !cmp.Equal(got, []string{"main"}) {
+		t.Error(cmp.Diff(got, []string{"main"}))
+	}
+
+	s.Databases["legacy"] = Database{Engine: "mysql"}
+	if err := s.ValidateDatabases(); err == nil {
+		t.Error("expected an error for an unsupported engine")
+	}
+
+	delete(s.Databases, "legacy")
+	s.Databases["missing"] = Database{Functions: []string{"billing"}}
+	if err := s.ValidateDatabases(); err == nil {
+		t.Error("expected an error for a missing function")
+	}
+}
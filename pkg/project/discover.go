@@ -0,0 +1,119 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// directories that never contain handlers
+var discoverSkipDirs = map[string]bool{
+	".git":         true,
+	".nitric":      true,
+	".venv":        true,
+	"__pycache__":  true,
+	"node_modules": true,
+	"vendor":       true,
+	"venv":         true,
+}
+
+// SkipDir reports whether a directory of the project holds dependencies or tooling rather than its own sources.
+func SkipDir(name string) bool {
+	return discoverSkipDirs[name]
+}
+
+var sdkImports = map[string]*regexp.Regexp{
+	".ts": regexp.MustCompile(`(from\s+|require\()\s*['"]@nitric/sdk['"]`),
+	".js": regexp.MustCompile(`(from\s+|require\()\s*['"]@nitric/sdk['"]`),
+	".py": regexp.MustCompile(`^\s*(from|import)\s+nitric(\.|\s)`),
+	".go": regexp.MustCompile(`"github.com/nitrictech/go-sdk/`),
+}
+
+var goMainPackage = regexp.MustCompile(`^package\s+main\b`)
+
+// isHandler reports whether the file uses the nitric SDK, go files must also be a main package.
+func isHandler(file string) (bool, error) {
+	re, ok := sdkImports[filepath.Ext(file)]
+	if !ok {
+		return false, nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	usesSdk := false
+	isMain := filepath.Ext(file) != ".go"
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if re.MatchString(line) {
+			usesSdk = true
+		}
+		if goMainPackage.MatchString(line) {
+			isMain = true
+		}
+		if usesSdk && isMain {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// DiscoverHandlers scans the project for source files that use the nitric SDK, returning their paths
+// relative to the project. Type declaration and test files are ignored.
+func DiscoverHandlers(dir string) ([]string, error) {
+	handlers := []string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if discoverSkipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name := info.Name()
+		if strings.HasSuffix(name, ".d.ts") || strings.HasSuffix(name, "_test.go") || strings.Contains(name, ".test.") || strings.Contains(name, ".spec.") {
+			return nil
+		}
+
+		ok, err := isHandler(path)
+		if err != nil || !ok {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		handlers = append(handlers, filepath.ToSlash(rel))
+		return nil
+	})
+	sort.Strings(handlers)
+
+	return handlers, err
+}
@@ -0,0 +1,34 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiscoverHandlers(t *testing.T) {
+	want := []string{"cmd/hello/main.go", "functions/hello.ts", "lib/tasks.py"}
+
+	got, err := DiscoverHandlers("testdata/discover")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiscoverHandlers() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EmailEnvPrefix is the prefix of the env vars that hold the settings of an email sender,
+// e.g. EMAIL_RECEIPTS for the receipts email.
+func EmailEnvPrefix(name string) string {
+	return "EMAIL_" + nonEnvChars.ReplaceAllString(strings.ToUpper(name), "_")
+}
+
+// EmailsFor returns the names of the emails the compute unit sends.
+func (s *Project) EmailsFor(name string) []string {
+	emails := []string{}
+	for k, e := range s.Emails {
+		for _, f := range e.Functions {
+			if f == name {
+				emails = append(emails, k)
+				break
+			}
+		}
+	}
+	sort.Strings(emails)
+	return emails
+}
+
+// ValidateEmails checks the emails have a sender within their domain and are used by functions that exist.
+func (s *Project) ValidateEmails() error {
+	names := map[string]bool{}
+	for _, c := range s.Computes() {
+		names[c.Unit().Name] = true
+	}
+
+	for k, e := range s.Emails {
+		if !strings.Contains(e.Sender, "@") {
+			return fmt.Errorf("email %s requires a sender address", k)
+		}
+		if e.Domain != "" && !strings.HasSuffix(e.Sender, "@"+e.Domain) {
+			return fmt.Errorf("email %s sender %s is not in the domain %s", k, e.Sender, e.Domain)
+		}
+		for _, f := range e.Functions {
+			if !names[f] {
+				return fmt.Errorf("email %s is used by function %s, but the function does not exist", k, f)
+			}
+		}
+	}
+	return nil
+}
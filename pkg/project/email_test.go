@@ -0,0 +1,56 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEmails(t *testing.T) {
+	s := &Project{
+		Functions: map[string]Function{
+			"orders": {ComputeUnit: ComputeUnit{Name: "orders"}},
+		},
+		Emails: map[string]Email{
+			"receipts": {Sender: "receipts@example.com", Domain: "example.com", Functions: []string{"orders"}},
+			"alerts":   {Sender: "alerts@example.com"},
+		},
+	}
+
+	if got := EmailEnvPrefix("order-receipts"); got != "EMAIL_ORDER_RECEIPTS" {
+		t.Errorf("EmailEnvPrefix() = %s, want EMAIL_ORDER_RECEIPTS", got)
+	}
+	if err := s.ValidateEmails(); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.EmailsFor("orders"); !cmp.Equal(got, []string{"receipts"}) {
+		t.Error(cmp.Diff(got, []string{"receipts"}))
+	}
+
+	s.Emails["other"] = Email{Sender: "noreply@other.com", Domain: "example.com"}
+	if err := s.ValidateEmails(); err == nil {
+		t.Error("expected an error for a sender outside the domain")
+	}
+
+	delete(s.Emails, "other")
+	s.Emails["missing"] = Email{Sender: "noreply@example.com", Functions: []string{"billing"}}
+	if err := s.ValidateEmails(); err == nil {
+		t.Error("expected an error for a missing function")
+	}
+}
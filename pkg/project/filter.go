@@ -0,0 +1,114 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+	"sort"
+
+	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+// FilterFunctions removes the functions that are not deployed to the stack, along with their
+// api routes and policies. Excluding a function that is not in the project is not an error,
+// so that removing a function doesn't break the stacks that excluded it.
+func (s *Project) FilterFunctions(sc *stack.Config) error {
+	if sc.Functions == nil {
+		return nil
+	}
+
+	for _, name := range sc.Functions.Include {
+		if _, ok := s.Functions[name]; !ok {
+			return fmt.Errorf("stack %s includes function %s which is not in the project", sc.Name, name)
+		}
+	}
+
+	excluded := map[string]bool{}
+	for name := range s.Functions {
+		if !sc.DeploysFunction(name) {
+			excluded[name] = true
+			delete(s.Functions, name)
+		}
+	}
+	if len(excluded) == 0 {
+		return nil
+	}
+
+	for api, doc := range s.ApiDocs {
+		for path, pathItem := range doc.Paths {
+			for m, op := range pathItem.Operations() {
+				if excluded[operationTarget(op.Extensions)] {
+					pathItem.SetOperation(m, nil)
+				}
+			}
+			if len(pathItem.Operations()) == 0 {
+				delete(doc.Paths, path)
+			}
+		}
+		// an api served only by excluded functions is not deployed
+		if len(doc.Paths) == 0 {
+			delete(s.ApiDocs, api)
+			delete(s.Apis, api)
+		}
+	}
+
+	policies := make([]*v1.PolicyResource, 0, len(s.Policies))
+	for _, p := range s.Policies {
+		principals := make([]*v1.Resource, 0, len(p.Principals))
+		for _, pr := range p.Principals {
+			if pr.Type == v1.ResourceType_Function && excluded[pr.Name] {
+				continue
+			}
+			principals = append(principals, pr)
+		}
+		if len(principals) > 0 {
+			p.Principals = principals
+			policies = append(policies, p)
+		}
+	}
+	s.Policies = policies
+
+	return nil
+}
+
+func operationTarget(ext map[string]interface{}) string {
+	target, ok := ext["x-nitric-target"].(map[string]string)
+	if !ok {
+		return ""
+	}
+	return target["name"]
+}
+
+// ApisFor returns the names of the apis with routes to the function.
+func (s *Project) ApisFor(name string) []string {
+	apis := []string{}
+	for api, doc := range s.ApiDocs {
+	routes:
+		for _, pathItem := range doc.Paths {
+			for _, op := range pathItem.Operations() {
+				if operationTarget(op.Extensions) == name {
+					apis = append(apis, api)
+					break routes
+				}
+			}
+		}
+	}
+	sort.Strings(apis)
+	return apis
+}
@@ -0,0 +1,81 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func targetOp(name string) *openapi3.Operation {
+	return &openapi3.Operation{
+		ExtensionProps: openapi3.ExtensionProps{
+			Extensions: map[string]interface{}{
+				"x-nitric-target": map[string]string{"type": "function", "name": name},
+			},
+		},
+	}
+}
+
+func TestFilterFunctions(t *testing.T) {
+	p := New(&Config{Name: "shop"})
+	p.Functions = map[string]Function{
+		"orders": {ComputeUnit: ComputeUnit{Name: "orders"}},
+		"admin":  {ComputeUnit: ComputeUnit{Name: "admin"}},
+	}
+	p.ApiDocs = map[string]*openapi3.T{
+		"public": {Paths: openapi3.Paths{"/orders": &openapi3.PathItem{Get: targetOp("orders"), Delete: targetOp("admin")}}},
+		"admin":  {Paths: openapi3.Paths{"/users": &openapi3.PathItem{Get: targetOp("admin")}}},
+	}
+	p.Apis = map[string]string{"public": "public.yaml", "admin": "admin.yaml"}
+	p.Policies = []*v1.PolicyResource{
+		{Principals: []*v1.Resource{{Name: "admin", Type: v1.ResourceType_Function}}},
+		{Principals: []*v1.Resource{{Name: "admin", Type: v1.ResourceType_Function}, {Name: "orders", Type: v1.ResourceType_Function}}},
+	}
+
+	err := p.FilterFunctions(&stack.Config{Name: "prod", Functions: &stack.FunctionFilter{Exclude: []string{"admin", "removed"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := p.Functions["admin"]; ok || len(p.Functions) != 1 {
+		t.Errorf("expected only orders to remain, got %v", p.Functions)
+	}
+	if _, ok := p.ApiDocs["admin"]; ok {
+		t.Error("expected the admin api to be removed")
+	}
+	if _, ok := p.Apis["admin"]; ok {
+		t.Error("expected the admin api file to be removed")
+	}
+	orders := p.ApiDocs["public"].Paths["/orders"]
+	if orders.Get == nil || orders.Delete != nil {
+		t.Errorf("expected only GET /orders to remain, got %v", orders.Operations())
+	}
+	if len(p.Policies) != 1 || len(p.Policies[0].Principals) != 1 || p.Policies[0].Principals[0].Name != "orders" {
+		t.Errorf("expected one policy for orders, got %v", p.Policies)
+	}
+
+	err = p.FilterFunctions(&stack.Config{Name: "prod", Functions: &stack.FunctionFilter{Include: []string{"missing"}}})
+	if err == nil {
+		t.Error("expected an error including a function that is not in the project")
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"sort"
+	"strings"
+)
+
+// FunctionSummary is how a function is triggered and the resources it uses, gathered from its code.
+type FunctionSummary struct {
+	Name string `json:"name" yaml:"name"`
+	// Kind is function or container
+	Kind string `json:"kind" yaml:"kind"`
+	// Source is the handler of a function or the Dockerfile of a container
+	Source string `json:"source" yaml:"source"`
+	// Routes are the api routes the function serves, e.g. main GET /orders
+	Routes []string `json:"routes,omitempty" yaml:"routes,omitempty"`
+	// Subscriptions are the topics the function is subscribed to, other than those of its schedules
+	Subscriptions []string `json:"subscriptions,omitempty" yaml:"subscriptions,omitempty"`
+	// Schedules trigger the function, e.g. nightly (0 2 * * *)
+	Schedules []string `json:"schedules,omitempty" yaml:"schedules,omitempty"`
+	// Buckets notify the function of changes to their files
+	Buckets []string `json:"buckets,omitempty" yaml:"buckets,omitempty"`
+	// Resources the function's policies allow it to use, e.g. bucket:images
+	Resources []string `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+// FunctionSummaries returns each function of the spec with its triggers and resources, sorted by name.
+func (s *Spec) FunctionSummaries() []FunctionSummary {
+	summaries := map[string]*FunctionSummary{}
+	names := []string{}
+	for name, f := range s.Functions {
+		summaries[name] = &FunctionSummary{Name: name, Kind: f.Kind, Source: f.Source}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for api, routes := range s.Apis {
+		for _, r := range routes {
+			if fs, ok := summaries[r.Function]; ok {
+				fs.Routes = append(fs.Routes, api+" "+r.Method+" "+r.Path)
+			}
+		}
+	}
+
+	scheduleTopics := map[string]string{}
+	for name, sched := range s.Schedules {
+		scheduleTopics[sched.Topic] = name + " (" + sched.Expression + ")"
+	}
+	for topic, t := range s.Topics {
+		for _, sub := range t.Subscribers {
+			fs, ok := summaries[sub]
+			if !ok {
+				continue
+			}
+			if sched, ok := scheduleTopics[topic]; ok {
+				fs.Schedules = append(fs.Schedules, sched)
+			} else {
+				fs.Subscriptions = append(fs.Subscriptions, topic)
+			}
+		}
+	}
+
+	for bucket, b := range s.Buckets {
+		for _, l := range b.Listeners {
+			if fs, ok := summaries[l]; ok {
+				fs.Buckets = append(fs.Buckets, bucket)
+			}
+		}
+	}
+
+	for _, p := range s.Policies {
+		for _, principal := range p.Principals {
+			if !strings.HasPrefix(principal, "function:") {
+				continue
+			}
+			fs, ok := summaries[strings.TrimPrefix(principal, "function:")]
+			if !ok {
+				continue
+			}
+			for _, r := range p.Resources {
+				fs.Resources = appendUnique(fs.Resources, r)
+			}
+		}
+	}
+
+	result := []FunctionSummary{}
+	for _, name := range names {
+		fs := summaries[name]
+		sort.Strings(fs.Routes)
+		sort.Strings(fs.Subscriptions)
+		sort.Strings(fs.Schedules)
+		sort.Strings(fs.Buckets)
+		sort.Strings(fs.Resources)
+		result = append(result, *fs)
+	}
+	return result
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,74 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFunctionSummaries(t *testing.T) {
+	spec := &Spec{
+		Functions: map[string]FunctionSpec{
+			"checkout": {Kind: "function", Source: "functions/checkout.ts"},
+			"thumbs":   {Kind: "container", Source: "thumbs/Dockerfile"},
+		},
+		Apis: map[string][]RouteSpec{
+			"main": {
+				{Method: "POST", Path: "/orders", Function: "checkout"},
+				{Method: "GET", Path: "/orders", Function: "checkout"},
+			},
+		},
+		Topics: map[string]TopicSpec{
+			"orders":  {Subscribers: []string{"checkout"}},
+			"nightly": {Subscribers: []string{"thumbs"}},
+		},
+		Buckets: map[string]BucketSpec{
+			"images": {Listeners: []string{"thumbs"}},
+		},
+		Schedules: map[string]ScheduleSpec{
+			"nightly": {Expression: "0 2 * * *", Topic: "nightly"},
+		},
+		Policies: []PolicySpec{
+			{Principals: []string{"function:checkout"}, Actions: []string{"BucketFileGet"}, Resources: []string{"bucket:images"}},
+			{Principals: []string{"function:checkout", "function:thumbs"}, Actions: []string{"BucketFilePut"}, Resources: []string{"bucket:images", "collection:orders"}},
+		},
+	}
+
+	want := []FunctionSummary{
+		{
+			Name:          "checkout",
+			Kind:          "function",
+			Source:        "functions/checkout.ts",
+			Routes:        []string{"main GET /orders", "main POST /orders"},
+			Subscriptions: []string{"orders"},
+			Resources:     []string{"bucket:images", "collection:orders"},
+		},
+		{
+			Name:      "thumbs",
+			Kind:      "container",
+			Source:    "thumbs/Dockerfile",
+			Schedules: []string{"nightly (0 2 * * *)"},
+			Buckets:   []string{"images"},
+			Resources: []string{"bucket:images", "collection:orders"},
+		},
+	}
+	if diff := cmp.Diff(want, spec.FunctionSummaries()); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package project
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+const LockFile = "nitric.lock"
+
+// Lock records what each target was last deployed with, so deploys can be reproduced on other machines.
+type Lock struct {
+	Targets map[string]*TargetLock `yaml:"targets"`
+}
+
+type TargetLock struct {
+	CliVersion string `yaml:"cliVersion"`
+
+	// Pulumi plugin versions, keyed by plugin name
+	Plugins map[string]string `yaml:"plugins,omitempty"`
+
+	// Base image repository digests, keyed by image name
+	Images map[string]string `yaml:"images,omitempty"`
+}
+
+// LockFromFile reads the project's lock file, a missing file results in an empty lock.
+func LockFromFile(dir string) (*Lock, error) {
+	l := &Lock{Targets: map[string]*TargetLock{}}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, LockFile))
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(b, l); err != nil {
+		return nil, err
+	}
+	if l.Targets == nil {
+		l.Targets = map[string]*TargetLock{}
+	}
+	return l, nil
+}
+
+func (l *Lock) ToFile(dir string) error {
+	b, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, LockFile), b, 0644)
+}
@@ -0,0 +1,57 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package project
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLockRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-nitric-lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l, err := LockFromFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Targets) != 0 {
+		t.Errorf("expected an empty lock, got %v", l.Targets)
+	}
+
+	l.Targets["aws"] = &TargetLock{
+		CliVersion: "v1.0.0",
+		Plugins:    map[string]string{"aws": "v4.37.5"},
+		Images:     map[string]string{"node:alpine": "node@sha256:1234"},
+	}
+	if err := l.ToFile(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LockFromFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(l, got) {
+		t.Error(cmp.Diff(l, got))
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// MockResponse is the response an api operation is mocked with.
+type MockResponse struct {
+	Status      int
+	ContentType string
+	// Example is false when the response has no example, the status is returned with an empty body.
+	Example bool
+}
+
+// MockFromConfig returns a project with only the apis mocked in the config, and no functions, so the
+// apis can be deployed before their handlers exist.
+func MockFromConfig(config *Config) (*Project, error) {
+	if len(config.Mocks) == 0 {
+		return nil, fmt.Errorf("project %s has no mocks, add the openapi files of the apis to mock to nitric.yaml", config.Name)
+	}
+
+	p := New(config)
+	p.Mock = true
+	for name, file := range config.Mocks {
+		doc, err := openapi3.NewLoader().LoadFromFile(filepath.Join(p.Dir, file))
+		if err != nil {
+			return nil, fmt.Errorf("mock %s: %w", name, err)
+		}
+		p.Apis[name] = file
+		p.ApiDocs[name] = doc
+	}
+	return p, nil
+}
+
+// hasExample returns true when the media type has an example, or named examples.
+func hasExample(m *openapi3.MediaType) bool {
+	return m != nil && (m.Example != nil || len(m.Examples) > 0)
+}
+
+// OperationMockResponse returns the response the operation is mocked with, the lowest 2xx response
+// preferring those with an example, and 200 when the operation has no 2xx responses.
+func OperationMockResponse(op *openapi3.Operation) MockResponse {
+	statuses := []int{}
+	for k := range op.Responses {
+		status, err := strconv.Atoi(k)
+		if err == nil && status >= 200 && status < 300 {
+			statuses = append(statuses, status)
+		}
+	}
+	sort.Ints(statuses)
+
+	mock := MockResponse{Status: 200, ContentType: "application/json"}
+	for i, status := range statuses {
+		r := op.Responses[strconv.Itoa(status)]
+		if r == nil || r.Value == nil {
+			continue
+		}
+
+		contentTypes := []string{}
+		for ct := range r.Value.Content {
+			contentTypes = append(contentTypes, ct)
+		}
+		sort.Strings(contentTypes)
+
+		for _, ct := range contentTypes {
+			if hasExample(r.Value.Content[ct]) {
+				return MockResponse{Status: status, ContentType: ct, Example: true}
+			}
+		}
+		if i == 0 {
+			mock.Status = status
+			if len(contentTypes) > 0 {
+				mock.ContentType = contentTypes[0]
+			}
+		}
+	}
+	return mock
+}
@@ -0,0 +1,114 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const mockSpec = `openapi: 3.0.1
+info:
+  title: orders
+  version: v1
+paths:
+  /orders:
+    get:
+      operationId: orders-get
+      responses:
+        "200":
+          description: the orders
+          content:
+            application/json:
+              example: [{"id": "1"}]
+`
+
+func TestMockFromConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "orders.yaml"), []byte(mockSpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := MockFromConfig(&Config{Name: "shop", Dir: dir, Mocks: map[string]string{"orders": "orders.yaml"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Mock || len(p.Functions) != 0 {
+		t.Errorf("expected a mocked project without functions, got mock %v and %d functions", p.Mock, len(p.Functions))
+	}
+	if doc, ok := p.ApiDocs["orders"]; !ok || doc.Paths["/orders"] == nil {
+		t.Errorf("expected the orders api to be loaded, got %v", p.ApiDocs)
+	}
+
+	if _, err := MockFromConfig(&Config{Name: "shop", Dir: dir}); err == nil {
+		t.Error("expected an error for a project without mocks")
+	}
+}
+
+func response(content openapi3.Content) *openapi3.ResponseRef {
+	return &openapi3.ResponseRef{Value: &openapi3.Response{Content: content}}
+}
+
+func TestOperationMockResponse(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses openapi3.Responses
+		want      MockResponse
+	}{
+		{
+			name: "no responses",
+			want: MockResponse{Status: 200, ContentType: "application/json"},
+		},
+		{
+			name: "lowest 2xx without an example",
+			responses: openapi3.Responses{
+				"204": response(nil),
+				"201": response(openapi3.Content{"text/plain": &openapi3.MediaType{}}),
+				"404": response(openapi3.Content{"application/json": &openapi3.MediaType{Example: "missing"}}),
+			},
+			want: MockResponse{Status: 201, ContentType: "text/plain"},
+		},
+		{
+			name: "prefers an example",
+			responses: openapi3.Responses{
+				"200": response(nil),
+				"202": response(openapi3.Content{
+					"application/xml":  &openapi3.MediaType{},
+					"application/json": &openapi3.MediaType{Examples: openapi3.Examples{"queued": &openapi3.ExampleRef{}}},
+				}),
+			},
+			want: MockResponse{Status: 202, ContentType: "application/json", Example: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := OperationMockResponse(&openapi3.Operation{Responses: tt.responses})
+			if got != tt.want {
+				t.Errorf("OperationMockResponse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -20,8 +20,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/pterm/pterm"
 
 	"github.com/nitrictech/cli/pkg/runtime"
@@ -58,13 +61,189 @@ func FromConfig(p *Config) (*Project, error) {
 		}
 	}
 
-	if len(s.Functions) == 0 {
+	// explicitly named functions take precedence over those found from handlers
+	for name, fc := range p.Functions {
+		if fc.Handler == "" {
+			return nil, fmt.Errorf("function %s has no handler", name)
+		}
+		pterm.Debug.Println("Using function " + name + " from " + fc.Handler)
+		if _, err := runtime.NewRunTimeFromHandler(fc.Handler); err != nil {
+			return nil, err
+		}
+		if fc.BuildTimeout < 0 {
+			return nil, fmt.Errorf("function %s has a negative buildTimeout", name)
+		}
+		s.Functions[name] = Function{
+			Handler: fc.Handler,
+			ComputeUnit: ComputeUnit{
+				Name:         name,
+				Memory:       fc.Memory,
+				Class:        fc.Class,
+				MinScale:     fc.MinScale,
+				MaxScale:     fc.MaxScale,
+				Triggers:     fc.Triggers,
+				BuildTimeout: fc.BuildTimeout,
+			},
+		}
+	}
+
+	for name, c := range p.Containers {
+		c.Name = name
+		if err := s.addContainer(c); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, c := range p.Services {
+		if _, ok := s.Containers[name]; ok {
+			return nil, fmt.Errorf("service %s has the same name as a container", name)
+		}
+		c.Name = name
+		if err := s.addService(c); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, j := range p.Jobs {
+		if _, ok := s.Containers[name]; ok {
+			return nil, fmt.Errorf("job %s has the same name as a container or service", name)
+		}
+		j.Name = name
+		if err := s.addJob(j); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(s.Functions) == 0 && len(s.Containers) == 0 {
+		if len(p.Handlers) == 0 {
+			return nil, errors.New("no functions, handlers or containers are declared in nitric.yaml")
+		}
 		return nil, fmt.Errorf("no functions were found with the glob '%s', try a new pattern", strings.Join(p.Handlers, ","))
 	}
 
 	return s, nil
 }
 
+// addContainer adds a container along with the topics, schedules and resources it uses.
+// FunctionNames are the sorted names of the functions of the project in the current directory,
+// found from nitric.yaml and its handler globs without gathering config from code.
+func FunctionNames() ([]string, error) {
+	config, err := ConfigFromFile(nil)
+	if err != nil {
+		return nil, err
+	}
+	s, err := FromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for name := range s.Functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *Project) addContainer(c Container) error {
+	if c.Dockerfile == "" {
+		return fmt.Errorf("container %s has no dockerfile", c.Name)
+	}
+	if c.BuildTimeout < 0 {
+		return fmt.Errorf("container %s has a negative buildTimeout", c.Name)
+	}
+	if err := validateProtocol(c.ComputeUnit); err != nil {
+		return err
+	}
+
+	topics := append([]string{}, c.Triggers.Topics...)
+	// the schedules of jobs start runs directly rather than publishing to a topic
+	if c.Job == nil {
+		schedules, err := c.ProjectSchedules()
+		if err != nil {
+			return err
+		}
+		for k, sched := range schedules {
+			s.Schedules[k] = sched
+			topics = append(topics, sched.Target.Name)
+		}
+	}
+	for _, t := range topics {
+		s.AddTopic(t)
+	}
+	c.Triggers.Topics = topics
+
+	policies, err := c.Policies()
+	if err != nil {
+		return err
+	}
+	s.Policies = append(s.Policies, policies...)
+
+	for k := range c.Permissions.Buckets {
+		s.Buckets[k] = Bucket{}
+	}
+	for k := range c.Permissions.Topics {
+		s.AddTopic(k)
+	}
+	for k := range c.Permissions.Queues {
+		s.Queues[k] = Queue{}
+	}
+	for k := range c.Permissions.Collections {
+		s.Collections[k] = Collection{}
+	}
+	for k := range c.Permissions.Secrets {
+		s.Secrets[k] = Secret{}
+	}
+
+	s.Containers[c.Name] = c
+	return nil
+}
+
+// addService adds a container that is always running, with at least one instance. Services do the
+// work they pull themselves (e.g. websocket servers or queue consumers), so they have no triggers.
+func (s *Project) addService(c Container) error {
+	if len(c.Triggers.Topics) > 0 || len(c.Triggers.Buckets) > 0 || len(c.Schedules) > 0 {
+		return fmt.Errorf("service %s can't have triggers or schedules, services are always running", c.Name)
+	}
+	if c.MinScale < 0 {
+		return fmt.Errorf("service %s must have a minScale of at least 1", c.Name)
+	}
+
+	c.AlwaysOn = true
+	if c.MinScale == 0 {
+		c.MinScale = 1
+	}
+	return s.addContainer(c)
+}
+
+// addJob adds a container that runs to completion, started by its schedules or by messages waiting on its queue.
+func (s *Project) addJob(j JobConfig) error {
+	c := j.Container
+	if len(c.Triggers.Topics) > 0 || len(c.Triggers.Buckets) > 0 || c.Port != 0 {
+		return fmt.Errorf("job %s can't have triggers or a port, jobs are started by their schedules or queue", c.Name)
+	}
+	if len(c.Schedules) == 0 && j.Queue == "" {
+		return fmt.Errorf("job %s needs schedules or a queue to start its runs", c.Name)
+	}
+	if _, err := c.ProjectSchedules(); err != nil {
+		return err
+	}
+	if j.Timeout != "" {
+		if d, err := time.ParseDuration(j.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("job %s has an invalid timeout %s, use a duration such as 30m", c.Name, j.Timeout)
+		}
+	}
+	if j.Retries < 0 {
+		return fmt.Errorf("job %s can't have negative retries", c.Name)
+	}
+
+	if j.Queue != "" {
+		s.Queues[j.Queue] = Queue{}
+	}
+	job := j.Job
+	c.Job = &job
+	return s.addContainer(c)
+}
+
 func FunctionFromHandler(h, stackDir string) (Function, error) {
 	pterm.Debug.Println("Using function from " + h)
 	rt, err := runtime.NewRunTimeFromHandler(h)
@@ -19,6 +19,7 @@ package project
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/imdario/mergo"
@@ -67,6 +68,126 @@ func TestFromConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "explicit functions sharing a handler",
+			proj: &Config{
+				Name: "pkg",
+				Dir:  "../../pkg",
+				Functions: map[string]FunctionConfig{
+					"reader": {Handler: "stack/types.go"},
+					"writer": {Handler: "stack/types.go", Memory: 512, MaxScale: 5, BuildTimeout: 45 * time.Minute},
+				},
+			},
+			want: &Project{
+				Dir:  "../../pkg",
+				Name: "pkg",
+				Functions: map[string]Function{
+					"reader": {
+						Handler:     "stack/types.go",
+						ComputeUnit: ComputeUnit{Name: "reader"},
+					},
+					"writer": {
+						Handler:     "stack/types.go",
+						ComputeUnit: ComputeUnit{Name: "writer", Memory: 512, MaxScale: 5, BuildTimeout: 45 * time.Minute},
+					},
+				},
+			},
+		},
+		{
+			name: "negative build timeout",
+			proj: &Config{
+				Name: "pkg",
+				Dir:  "../../pkg",
+				Functions: map[string]FunctionConfig{
+					"writer": {Handler: "stack/types.go", BuildTimeout: -time.Minute},
+				},
+			},
+			want:    &Project{},
+			wantErr: true,
+		},
+		{
+			name: "services",
+			proj: &Config{
+				Name: "pkg",
+				Dir:  "../../pkg",
+				Services: map[string]Container{
+					"socket": {Dockerfile: "Dockerfile", ComputeUnit: ComputeUnit{Port: 8080}},
+				},
+			},
+			want: &Project{
+				Dir:  "../../pkg",
+				Name: "pkg",
+				Containers: map[string]Container{
+					"socket": {
+						Dockerfile:  "Dockerfile",
+						ComputeUnit: ComputeUnit{Name: "socket", Port: 8080, MinScale: 1, AlwaysOn: true, Triggers: Triggers{Topics: []string{}}},
+					},
+				},
+			},
+		},
+		{
+			name: "service with triggers",
+			proj: &Config{
+				Name: "pkg",
+				Dir:  "../../pkg",
+				Services: map[string]Container{
+					"socket": {Dockerfile: "Dockerfile", ComputeUnit: ComputeUnit{Triggers: Triggers{Topics: []string{"orders"}}}},
+				},
+			},
+			want:    &Project{},
+			wantErr: true,
+		},
+		{
+			name: "jobs",
+			proj: &Config{
+				Name: "pkg",
+				Dir:  "../../pkg",
+				Jobs: map[string]JobConfig{
+					"report": {
+						Container: Container{Dockerfile: "Dockerfile", Schedules: map[string]string{"nightly": "0 2 * * *"}},
+						Job:       Job{Timeout: "1h", Retries: 2},
+					},
+					"import": {
+						Container: Container{Dockerfile: "Dockerfile"},
+						Job:       Job{Queue: "imports"},
+					},
+				},
+			},
+			want: &Project{
+				Dir:    "../../pkg",
+				Name:   "pkg",
+				Queues: map[string]Queue{"imports": {}},
+				Containers: map[string]Container{
+					"report": {
+						Dockerfile:  "Dockerfile",
+						Schedules:   map[string]string{"nightly": "0 2 * * *"},
+						ComputeUnit: ComputeUnit{Name: "report", Job: &Job{Timeout: "1h", Retries: 2}, Triggers: Triggers{Topics: []string{}}},
+					},
+					"import": {
+						Dockerfile:  "Dockerfile",
+						ComputeUnit: ComputeUnit{Name: "import", Job: &Job{Queue: "imports"}, Triggers: Triggers{Topics: []string{}}},
+					},
+				},
+			},
+		},
+		{
+			name: "job without schedules or queue",
+			proj: &Config{
+				Name: "pkg",
+				Dir:  "../../pkg",
+				Jobs: map[string]JobConfig{
+					"report": {Container: Container{Dockerfile: "Dockerfile"}, Job: Job{Timeout: "1h"}},
+				},
+			},
+			want:    &Project{},
+			wantErr: true,
+		},
+		{
+			name:    "no handlers or functions",
+			proj:    &Config{Name: "pkg", Dir: "../../pkg"},
+			want:    &Project{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
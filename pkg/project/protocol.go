@@ -0,0 +1,59 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// ProtocolHttp serves HTTP/1.1 requests, the default
+	ProtocolHttp = "http"
+	// ProtocolGrpc serves gRPC, which needs HTTP/2 from the ingress to the container
+	ProtocolGrpc = "grpc"
+	// ProtocolH2c serves HTTP/2 without TLS, the ingress terminates TLS
+	ProtocolH2c = "h2c"
+)
+
+// Protocols are the protocols a compute unit can serve on its port.
+var Protocols = []string{ProtocolHttp, ProtocolGrpc, ProtocolH2c}
+
+// Http2 returns true when the compute unit's port serves HTTP/2, so the ingress must forward requests to it
+// over HTTP/2.
+func (c *ComputeUnit) Http2() bool {
+	return c.Protocol == ProtocolGrpc || c.Protocol == ProtocolH2c
+}
+
+// validateProtocol checks the protocol is known, the membrane serves the default port over HTTP/1.1 so
+// other protocols need a port of their own.
+func validateProtocol(c ComputeUnit) error {
+	if c.Protocol == "" {
+		return nil
+	}
+	found := false
+	for _, p := range Protocols {
+		found = found || p == c.Protocol
+	}
+	if !found {
+		return fmt.Errorf("%s has an unknown protocol %s, it must be one of %s", c.Name, c.Protocol, strings.Join(Protocols, ", "))
+	}
+	if c.Http2() && c.Port == 0 {
+		return fmt.Errorf("%s serves %s, which needs the port the container listens on", c.Name, c.Protocol)
+	}
+	return nil
+}
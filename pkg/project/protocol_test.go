@@ -0,0 +1,63 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateProtocol(t *testing.T) {
+	tests := []struct {
+		name    string
+		unit    ComputeUnit
+		wantErr string
+	}{
+		{
+			name: "default",
+			unit: ComputeUnit{Name: "orders"},
+		},
+		{
+			name: "grpc",
+			unit: ComputeUnit{Name: "orders", Port: 50051, Protocol: ProtocolGrpc},
+		},
+		{
+			name:    "grpc without a port",
+			unit:    ComputeUnit{Name: "orders", Protocol: ProtocolGrpc},
+			wantErr: "orders serves grpc, which needs the port the container listens on",
+		},
+		{
+			name:    "unknown",
+			unit:    ComputeUnit{Name: "orders", Port: 8080, Protocol: "websocket"},
+			wantErr: "orders has an unknown protocol websocket, it must be one of http, grpc, h2c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateProtocol(tt.unit)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateProtocol() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateProtocol() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
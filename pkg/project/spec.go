@@ -0,0 +1,182 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"sort"
+	"strings"
+
+	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
+)
+
+// SpecVersion is the version of the spec document, it changes when fields are removed or change meaning.
+const SpecVersion = "v1"
+
+// Spec is the resource model of a project gathered from its code, a document for audits, diffing between
+// branches and external tooling. Names are sorted so the same project always gives the same document.
+type Spec struct {
+	Version     string                  `json:"version" yaml:"version"`
+	Project     string                  `json:"project" yaml:"project"`
+	Functions   map[string]FunctionSpec `json:"functions,omitempty" yaml:"functions,omitempty"`
+	Apis        map[string][]RouteSpec  `json:"apis,omitempty" yaml:"apis,omitempty"`
+	Topics      map[string]TopicSpec    `json:"topics,omitempty" yaml:"topics,omitempty"`
+	Queues      []string                `json:"queues,omitempty" yaml:"queues,omitempty"`
+	Buckets     map[string]BucketSpec   `json:"buckets,omitempty" yaml:"buckets,omitempty"`
+	Collections []string                `json:"collections,omitempty" yaml:"collections,omitempty"`
+	Secrets     []string                `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+	Schedules   map[string]ScheduleSpec `json:"schedules,omitempty" yaml:"schedules,omitempty"`
+	Policies    []PolicySpec            `json:"policies,omitempty" yaml:"policies,omitempty"`
+}
+
+type FunctionSpec struct {
+	// Kind is function or container
+	Kind string `json:"kind" yaml:"kind"`
+	// Source is the handler of a function or the Dockerfile of a container
+	Source string `json:"source" yaml:"source"`
+}
+
+type RouteSpec struct {
+	Method   string `json:"method" yaml:"method"`
+	Path     string `json:"path" yaml:"path"`
+	Function string `json:"function" yaml:"function"`
+}
+
+type TopicSpec struct {
+	// The functions subscribed to the topic
+	Subscribers []string `json:"subscribers,omitempty" yaml:"subscribers,omitempty"`
+	Schema      string   `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+type BucketSpec struct {
+	// The functions notified of changes to the bucket's files
+	Listeners []string `json:"listeners,omitempty" yaml:"listeners,omitempty"`
+}
+
+type ScheduleSpec struct {
+	Expression string `json:"expression" yaml:"expression"`
+	Topic      string `json:"topic" yaml:"topic"`
+}
+
+// PolicySpec allows the principals the actions on the resources, which are written as kind:name, e.g. bucket:images.
+type PolicySpec struct {
+	Principals []string `json:"principals" yaml:"principals"`
+	Actions    []string `json:"actions" yaml:"actions"`
+	Resources  []string `json:"resources" yaml:"resources"`
+}
+
+func specResources(resources []*v1.Resource) []string {
+	names := []string{}
+	for _, r := range resources {
+		names = append(names, strings.ToLower(r.Type.String())+":"+r.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Spec returns the project's resource model.
+func (s *Project) Spec() *Spec {
+	spec := &Spec{
+		Version:   SpecVersion,
+		Project:   s.Name,
+		Functions: map[string]FunctionSpec{},
+		Apis:      map[string][]RouteSpec{},
+		Topics:    map[string]TopicSpec{},
+		Buckets:   map[string]BucketSpec{},
+		Schedules: map[string]ScheduleSpec{},
+		Policies:  []PolicySpec{},
+	}
+
+	for name, f := range s.Functions {
+		spec.Functions[name] = FunctionSpec{Kind: "function", Source: f.Handler}
+	}
+	for name, c := range s.Containers {
+		spec.Functions[name] = FunctionSpec{Kind: "container", Source: c.Dockerfile}
+	}
+
+	for name, t := range s.Topics {
+		spec.Topics[name] = TopicSpec{Schema: t.Schema}
+	}
+	for name := range s.Buckets {
+		spec.Buckets[name] = BucketSpec{}
+	}
+	for _, c := range s.Computes() {
+		for _, t := range c.Unit().Triggers.Topics {
+			ts := spec.Topics[t]
+			ts.Subscribers = append(ts.Subscribers, c.Unit().Name)
+			sort.Strings(ts.Subscribers)
+			spec.Topics[t] = ts
+		}
+		for _, b := range c.Unit().Triggers.Buckets {
+			bs := spec.Buckets[b.Bucket]
+			bs.Listeners = append(bs.Listeners, c.Unit().Name)
+			sort.Strings(bs.Listeners)
+			spec.Buckets[b.Bucket] = bs
+		}
+	}
+
+	for api, doc := range s.ApiDocs {
+		routes := []RouteSpec{}
+		for path, pathItem := range doc.Paths {
+			for m, op := range pathItem.Operations() {
+				routes = append(routes, RouteSpec{Method: m, Path: path, Function: operationTarget(op.Extensions)})
+			}
+		}
+		sort.Slice(routes, func(i, j int) bool {
+			if routes[i].Path != routes[j].Path {
+				return routes[i].Path < routes[j].Path
+			}
+			return routes[i].Method < routes[j].Method
+		})
+		spec.Apis[api] = routes
+	}
+
+	for name := range s.Queues {
+		spec.Queues = append(spec.Queues, name)
+	}
+	sort.Strings(spec.Queues)
+	for name := range s.Collections {
+		spec.Collections = append(spec.Collections, name)
+	}
+	sort.Strings(spec.Collections)
+	for name := range s.Secrets {
+		spec.Secrets = append(spec.Secrets, name)
+	}
+	sort.Strings(spec.Secrets)
+
+	for name, sched := range s.Schedules {
+		spec.Schedules[name] = ScheduleSpec{Expression: sched.Expression, Topic: sched.Target.Name}
+	}
+
+	for _, p := range s.Policies {
+		actions := []string{}
+		for _, a := range p.Actions {
+			actions = append(actions, a.String())
+		}
+		sort.Strings(actions)
+		spec.Policies = append(spec.Policies, PolicySpec{
+			Principals: specResources(p.Principals),
+			Actions:    actions,
+			Resources:  specResources(p.Resources),
+		})
+	}
+	sort.Slice(spec.Policies, func(i, j int) bool {
+		return strings.Join(spec.Policies[i].Principals, ",")+strings.Join(spec.Policies[i].Resources, ",") <
+			strings.Join(spec.Policies[j].Principals, ",")+strings.Join(spec.Policies[j].Resources, ",")
+	})
+
+	return spec
+}
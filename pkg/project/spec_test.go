@@ -0,0 +1,77 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/go-cmp/cmp"
+
+	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
+)
+
+func TestSpec(t *testing.T) {
+	p := New(&Config{Name: "shop"})
+	p.Functions["checkout"] = Function{
+		Handler:     "functions/checkout.ts",
+		ComputeUnit: ComputeUnit{Name: "checkout", Triggers: Triggers{Topics: []string{"orders"}}},
+	}
+	p.Containers["thumbs"] = Container{
+		Dockerfile:  "thumbs/Dockerfile",
+		ComputeUnit: ComputeUnit{Name: "thumbs", Triggers: Triggers{Buckets: []BucketTrigger{{Bucket: "images"}}}},
+	}
+	p.Topics["orders"] = Topic{}
+	p.Buckets["images"] = Bucket{}
+	p.Queues["emails"] = Queue{}
+	p.Schedules["nightly"] = Schedule{Expression: "0 1 * * *", Target: ScheduleTarget{Type: "topic", Name: "orders"}}
+	p.ApiDocs["main"] = &openapi3.T{Paths: openapi3.Paths{
+		"/orders": &openapi3.PathItem{Get: targetOp("checkout"), Post: targetOp("checkout")},
+	}}
+	p.Policies = []*v1.PolicyResource{{
+		Principals: []*v1.Resource{{Name: "thumbs", Type: v1.ResourceType_Function}},
+		Actions:    []v1.Action{v1.Action_BucketFileGet, v1.Action_BucketFilePut},
+		Resources:  []*v1.Resource{{Name: "images", Type: v1.ResourceType_Bucket}},
+	}}
+
+	want := &Spec{
+		Version: SpecVersion,
+		Project: "shop",
+		Functions: map[string]FunctionSpec{
+			"checkout": {Kind: "function", Source: "functions/checkout.ts"},
+			"thumbs":   {Kind: "container", Source: "thumbs/Dockerfile"},
+		},
+		Apis: map[string][]RouteSpec{
+			"main": {
+				{Method: "GET", Path: "/orders", Function: "checkout"},
+				{Method: "POST", Path: "/orders", Function: "checkout"},
+			},
+		},
+		Topics:    map[string]TopicSpec{"orders": {Subscribers: []string{"checkout"}}},
+		Queues:    []string{"emails"},
+		Buckets:   map[string]BucketSpec{"images": {Listeners: []string{"thumbs"}}},
+		Schedules: map[string]ScheduleSpec{"nightly": {Expression: "0 1 * * *", Topic: "orders"}},
+		Policies: []PolicySpec{{
+			Principals: []string{"function:thumbs"},
+			Actions:    []string{"BucketFileGet", "BucketFilePut"},
+			Resources:  []string{"bucket:images"},
+		}},
+	}
+	if got := p.Spec(); !cmp.Equal(got, want) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
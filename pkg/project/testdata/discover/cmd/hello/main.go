@@ -0,0 +1,7 @@
+package main
+
+import "github.com/nitrictech/go-sdk/resources"
+
+func main() {
+	_ = resources.Run()
+}
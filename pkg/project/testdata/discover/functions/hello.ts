@@ -0,0 +1,3 @@
+import { api } from '@nitric/sdk';
+
+api('main').get('/hello', async (ctx) => ctx);
@@ -0,0 +1,5 @@
+package lib
+
+import "github.com/nitrictech/go-sdk/resources"
+
+var Run = resources.Run
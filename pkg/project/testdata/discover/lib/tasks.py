@@ -0,0 +1 @@
+from nitric.resources import topic
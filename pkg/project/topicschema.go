@@ -0,0 +1,110 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/pkg/errors"
+)
+
+// TopicSchema returns the schema of the topic's message payloads, nil when the topic has none.
+// Schemas are the subset of JSON schema supported by OpenAPI 3.
+func (s *Project) TopicSchema(topic string) (*openapi3.Schema, error) {
+	t, ok := s.Topics[topic]
+	if !ok || t.Schema == "" {
+		return nil, nil
+	}
+
+	file := t.Schema
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(s.Dir, file)
+	}
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "schema of topic %s", topic)
+	}
+
+	schema := openapi3.NewSchema()
+	if err := json.Unmarshal(b, schema); err != nil {
+		return nil, errors.WithMessagef(err, "schema of topic %s is not a JSON schema", topic)
+	}
+	return schema, nil
+}
+
+// TopicSchemas returns the schemas of the topics that have one, keyed by topic.
+func (s *Project) TopicSchemas() (map[string]*openapi3.Schema, error) {
+	schemas := map[string]*openapi3.Schema{}
+	for k := range s.Topics {
+		schema, err := s.TopicSchema(k)
+		if err != nil {
+			return nil, err
+		}
+		if schema != nil {
+			schemas[k] = schema
+		}
+	}
+	return schemas, nil
+}
+
+// ValidateTopicSchemas checks the schemas of the topics can be read.
+func (s *Project) ValidateTopicSchemas() error {
+	_, err := s.TopicSchemas()
+	return err
+}
+
+// ValidateMessage checks the payload matches the schema of the topic, when it has one.
+func (s *Project) ValidateMessage(topic string, payload map[string]interface{}) error {
+	schema, err := s.TopicSchema(topic)
+	if err != nil || schema == nil {
+		return err
+	}
+	return ValidatePayload(topic, schema, payload)
+}
+
+// ValidatePayload checks the payload of a message to the topic matches its schema.
+func ValidatePayload(topic string, schema *openapi3.Schema, payload map[string]interface{}) error {
+	// round trip the payload so its values have the types decoded from JSON
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	if err := json.Unmarshal(b, &value); err != nil {
+		return err
+	}
+
+	if err := schema.VisitJSON(value); err != nil {
+		return fmt.Errorf("the message does not match the schema of topic %s: %w", topic, err)
+	}
+	return nil
+}
+
+// RequiredFields are the top level fields the topic's schema requires, sorted.
+func RequiredFields(schema *openapi3.Schema) []string {
+	if schema == nil {
+		return nil
+	}
+	fields := append([]string{}, schema.Required...)
+	sort.Strings(fields)
+	return fields
+}
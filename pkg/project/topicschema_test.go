@@ -0,0 +1,105 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateMessage(t *testing.T) {
+	dir := t.TempDir()
+	schema := `{
+  "$schema": "http://json-schema.org/draft-04/schema#",
+  "type": "object",
+  "required": ["orderId", "total"],
+  "properties": {
+    "orderId": {"type": "string"},
+    "total": {"type": "number", "minimum": 0}
+  }
+}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "order.json"), []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(&Config{
+		Name: "atest",
+		Dir:  dir,
+		Topics: map[string]Topic{
+			"orders": {Schema: "order.json"},
+		},
+	})
+	s.AddTopic("orders")
+	s.AddTopic("sales")
+
+	if err := s.ValidateTopicSchemas(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		topic   string
+		payload map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:    "matches",
+			topic:   "orders",
+			payload: map[string]interface{}{"orderId": "42", "total": 10},
+		},
+		{
+			name:    "missing field",
+			topic:   "orders",
+			payload: map[string]interface{}{"orderId": "42"},
+			wantErr: true,
+		},
+		{
+			name:    "wrong type",
+			topic:   "orders",
+			payload: map[string]interface{}{"orderId": 42, "total": 10},
+			wantErr: true,
+		},
+		{
+			name:    "no schema",
+			topic:   "sales",
+			payload: map[string]interface{}{"anything": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.ValidateMessage(tt.topic, tt.payload); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	schemas, err := s.TopicSchemas()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := RequiredFields(schemas["orders"]); len(got) != 2 || got[0] != "orderId" || got[1] != "total" {
+		t.Errorf("RequiredFields() = %v", got)
+	}
+}
+
+func TestValidateTopicSchemasMissingFile(t *testing.T) {
+	s := New(&Config{Name: "atest", Dir: t.TempDir(), Topics: map[string]Topic{"orders": {Schema: "missing.json"}}})
+	if err := s.ValidateTopicSchemas(); err == nil {
+		t.Error("expected an error for a missing schema file")
+	}
+}
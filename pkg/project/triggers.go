@@ -0,0 +1,99 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// MissingTopicError is returned when a function subscribes to a topic that is not declared.
+type MissingTopicError struct {
+	Function string
+	Topic    string
+}
+
+func (e *MissingTopicError) Error() string {
+	return fmt.Sprintf("function %s subscribes to topic %s, but the topic is not declared", e.Function, e.Topic)
+}
+
+// missingTopics returns the triggers of each compute unit that are not declared topics.
+func (s *Project) missingTopics() []*MissingTopicError {
+	missing := []*MissingTopicError{}
+	for _, c := range s.Computes() {
+		for _, t := range c.Unit().Triggers.Topics {
+			if _, ok := s.Topics[t]; !ok {
+				missing = append(missing, &MissingTopicError{Function: c.Unit().Name, Topic: t})
+			}
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		if missing[i].Function == missing[j].Function {
+			return missing[i].Topic < missing[j].Topic
+		}
+		return missing[i].Function < missing[j].Function
+	})
+	return missing
+}
+
+// EventTypes returns the events that trigger the compute unit.
+func (t BucketTrigger) EventTypes() []BucketEvent {
+	if len(t.Events) == 0 {
+		return []BucketEvent{BucketEventCreated, BucketEventDeleted}
+	}
+	return t.Events
+}
+
+// ValidateTriggers returns a MissingTopicError for each subscription to a topic that is not declared,
+// and an error for each bucket trigger on a bucket that is not declared or with an unknown event.
+func (s *Project) ValidateTriggers() error {
+	errList := utils.NewErrorList()
+	for _, e := range s.missingTopics() {
+		errList.Add(e)
+	}
+
+	for _, c := range s.Computes() {
+		for _, t := range c.Unit().Triggers.Buckets {
+			if _, ok := s.Buckets[t.Bucket]; !ok {
+				errList.Add(fmt.Errorf("function %s is triggered by bucket %s, but the bucket is not declared", c.Unit().Name, t.Bucket))
+			}
+			for _, e := range t.Events {
+				if e != BucketEventCreated && e != BucketEventDeleted {
+					errList.Add(fmt.Errorf("function %s is triggered by unknown bucket event %q, use %q or %q", c.Unit().Name, e, BucketEventCreated, BucketEventDeleted))
+				}
+			}
+		}
+	}
+	return errList.Aggregate()
+}
+
+// CreateMissingTopics declares the topics that functions subscribe to but are not declared,
+// returning their names.
+func (s *Project) CreateMissingTopics() []string {
+	created := []string{}
+	for _, e := range s.missingTopics() {
+		if _, ok := s.Topics[e.Topic]; ok {
+			continue
+		}
+		s.Topics[e.Topic] = Topic{}
+		created = append(created, e.Topic)
+	}
+	sort.Strings(created)
+	return created
+}
@@ -0,0 +1,81 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+func TestValidateTriggers(t *testing.T) {
+	p := New(&Config{Name: "shop"})
+	p.Topics = map[string]Topic{"orders": {}}
+	p.Functions = map[string]Function{
+		"fulfil": {ComputeUnit: ComputeUnit{Name: "fulfil", Triggers: Triggers{Topics: []string{"orders", "refunds"}}}},
+		"notify": {ComputeUnit: ComputeUnit{Name: "notify", Triggers: Triggers{Topics: []string{"refunds", "shipped"}}}},
+	}
+
+	err := p.ValidateTriggers()
+	errList, ok := err.(*utils.ErrorList)
+	if !ok {
+		t.Fatalf("expected an error list, got %v", err)
+	}
+	want := []error{
+		&MissingTopicError{Function: "fulfil", Topic: "refunds"},
+		&MissingTopicError{Function: "notify", Topic: "refunds"},
+		&MissingTopicError{Function: "notify", Topic: "shipped"},
+	}
+	if !reflect.DeepEqual(errList.Errors(), want) {
+		t.Errorf("ValidateTriggers() = %v, want %v", errList.Errors(), want)
+	}
+
+	created := p.CreateMissingTopics()
+	if !reflect.DeepEqual(created, []string{"refunds", "shipped"}) {
+		t.Errorf("CreateMissingTopics() = %v", created)
+	}
+	if err := p.ValidateTriggers(); err != nil {
+		t.Errorf("expected no errors after creating the topics, got %v", err)
+	}
+}
+
+func TestValidateBucketTriggers(t *testing.T) {
+	p := New(&Config{Name: "shop"})
+	p.Buckets = map[string]Bucket{"uploads": {}}
+	p.Functions = map[string]Function{
+		"resize": {ComputeUnit: ComputeUnit{Name: "resize", Triggers: Triggers{Buckets: []BucketTrigger{
+			{Bucket: "uploads", Events: []BucketEvent{BucketEventCreated}, Prefix: "images/"},
+			{Bucket: "archive"},
+			{Bucket: "uploads", Events: []BucketEvent{"modified"}},
+		}}}},
+	}
+
+	err := p.ValidateTriggers()
+	errList, ok := err.(*utils.ErrorList)
+	if !ok {
+		t.Fatalf("expected an error list, got %v", err)
+	}
+	if len(errList.Errors()) != 2 {
+		t.Errorf("expected 2 errors, got %v", errList.Errors())
+	}
+
+	want := []BucketEvent{BucketEventCreated, BucketEventDeleted}
+	if got := (BucketTrigger{Bucket: "uploads"}).EventTypes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("EventTypes() = %v, want %v", got, want)
+	}
+}
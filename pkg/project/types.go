@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"path"
 	"path/filepath"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"gopkg.in/yaml.v2"
@@ -31,6 +32,27 @@ import (
 
 type Triggers struct {
 	Topics []string `yaml:"topics,omitempty"`
+
+	// Buckets invoke the compute unit when files in a bucket are created or deleted
+	Buckets []BucketTrigger `yaml:"buckets,omitempty"`
+}
+
+// BucketEvent is a change to the files in a bucket.
+type BucketEvent string
+
+const (
+	BucketEventCreated BucketEvent = "created"
+	BucketEventDeleted BucketEvent = "deleted"
+)
+
+type BucketTrigger struct {
+	Bucket string `yaml:"bucket"`
+
+	// The events that trigger the compute unit, defaults to all
+	Events []BucketEvent `yaml:"events,omitempty"`
+
+	// Only files whose names start with the prefix trigger the compute unit
+	Prefix string `yaml:"prefix,omitempty"`
 }
 
 type ComputeUnit struct {
@@ -42,11 +64,35 @@ type ComputeUnit struct {
 	// The memory of the compute instance in MB
 	Memory int `yaml:"memory,omitempty"`
 
+	// The size of the compute instance, e.g. small or large, see ComputeClasses
+	Class string `yaml:"class,omitempty"`
+
 	// The minimum number of instances to keep alive
 	MinScale int `yaml:"minScale,omitempty"`
 
 	// The maximum number of instances to scale to
 	MaxScale int `yaml:"maxScale,omitempty"`
+
+	// The port requests are sent to, defaults to 9001 where the membrane listens
+	Port int `yaml:"port,omitempty"`
+
+	// The protocol served on the port, http (the default), grpc or h2c
+	Protocol string `yaml:"protocol,omitempty"`
+
+	// Environment variables set on the compute unit, values from the env files take precedence
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// The size of the writable /tmp storage in MB, for workloads that process large files
+	TmpSize int `yaml:"tmpSize,omitempty"`
+
+	// How long building the image can take, e.g. 45m, overrides the build_timeout setting
+	BuildTimeout time.Duration `yaml:"buildTimeout,omitempty"`
+
+	// Keep the instances running rather than scaling with requests, set for services
+	AlwaysOn bool `yaml:"-"`
+
+	// Run to completion when started by a schedule or queue rather than serving requests, set for jobs
+	Job *Job `yaml:"-"`
 }
 
 type Function struct {
@@ -64,6 +110,74 @@ type Container struct {
 	Args       []string `yaml:"args,omitempty"`
 
 	ComputeUnit `yaml:",inline"`
+
+	// Schedules that trigger the container keyed by name, as a cron expression or a rate such as "5 minutes"
+	Schedules map[string]string `yaml:"schedules,omitempty"`
+
+	// The resources the container can access, the same permissions the SDKs request for functions
+	Permissions ContainerPermissions `yaml:"permissions,omitempty"`
+
+	// Shared file systems mounted into the container keyed by name, containers that mount the same name share the files
+	Volumes map[string]VolumeMount `yaml:"volumes,omitempty"`
+}
+
+// VolumeMount is where a volume, a persistent file system shared by every instance, is mounted in a container.
+type VolumeMount struct {
+	// The absolute path the volume is mounted at
+	Path string `yaml:"path"`
+
+	// Mount the volume read only
+	ReadOnly bool `yaml:"readOnly,omitempty"`
+}
+
+// DefaultJobTimeout is the longest a job run may take when the job has no timeout.
+const DefaultJobTimeout = 10 * time.Minute
+
+// Job are the settings of the runs of a container that runs to completion, each run is started by
+// one of the container's schedules or when messages are waiting on the queue.
+type Job struct {
+	// The queue whose waiting messages start a run, the job receives the messages itself
+	Queue string `yaml:"queue,omitempty"`
+
+	// The longest a run may take, e.g. "30m", defaults to 10 minutes
+	Timeout string `yaml:"timeout,omitempty"`
+
+	// The number of times a failed or timed out run is retried
+	Retries int `yaml:"retries,omitempty"`
+}
+
+// TimeoutOrDefault returns the job's timeout, or the default when it has none.
+func (j *Job) TimeoutOrDefault() time.Duration {
+	d, err := time.ParseDuration(j.Timeout)
+	if err != nil || d <= 0 {
+		return DefaultJobTimeout
+	}
+	return d
+}
+
+// JobConfig declares a job, the container that is run along with the settings of its runs.
+type JobConfig struct {
+	Container `yaml:",inline"`
+	Job       `yaml:",inline"`
+}
+
+// ContainerPermissions lists the permissions of a container for each resource by name,
+// e.g. buckets: {images: [reading, writing]}
+type ContainerPermissions struct {
+	// reading, writing or deleting
+	Buckets map[string][]string `yaml:"buckets,omitempty"`
+
+	// publishing
+	Topics map[string][]string `yaml:"topics,omitempty"`
+
+	// sending or receiving
+	Queues map[string][]string `yaml:"queues,omitempty"`
+
+	// reading, writing or deleting
+	Collections map[string][]string `yaml:"collections,omitempty"`
+
+	// accessing or putting
+	Secrets map[string][]string `yaml:"secrets,omitempty"`
 }
 
 type Compute interface {
@@ -97,12 +211,106 @@ type Collection struct{}
 
 type Bucket struct{}
 
-type Topic struct{}
+type Topic struct {
+	// A JSON schema file the payloads of the topic's messages must match, relative to the project
+	Schema string `yaml:"schema,omitempty"`
+}
 
 type Queue struct{}
 
 type Secret struct{}
 
+// FunctionConfig declares a function explicitly by name, rather than deriving its name from the handler file.
+type FunctionConfig struct {
+	// The handler source file, relative to the project
+	Handler string `yaml:"handler"`
+
+	// The memory of the compute instance in MB
+	Memory int `yaml:"memory,omitempty"`
+
+	// The size of the compute instance, e.g. small or large, see ComputeClasses
+	Class string `yaml:"class,omitempty"`
+
+	// The minimum number of instances to keep alive
+	MinScale int `yaml:"minScale,omitempty"`
+
+	// The maximum number of instances to scale to
+	MaxScale int `yaml:"maxScale,omitempty"`
+
+	// Triggers in addition to the topic subscriptions declared in code
+	Triggers Triggers `yaml:"triggers,omitempty"`
+
+	// How long building the image can take, e.g. 45m for functions with large dependencies,
+	// overrides the build_timeout setting
+	BuildTimeout time.Duration `yaml:"buildTimeout,omitempty"`
+}
+
+type Site struct {
+	// The directory containing the built assets, relative to the project
+	Path string `yaml:"path"`
+
+	// The document served for the root of the site, defaults to index.html
+	IndexDocument string `yaml:"indexDocument,omitempty"`
+
+	// The document served when an asset is not found
+	ErrorDocument string `yaml:"errorDocument,omitempty"`
+}
+
+type WorkflowStep struct {
+	// The name of the function run by this step
+	Function string `yaml:"function"`
+
+	// The topic the function subscribes to, used to deliver the step's event.
+	// Defaults to the function's only topic trigger.
+	Topic string `yaml:"topic,omitempty"`
+}
+
+// Workflow runs its steps one after another, passing the execution input to each step as a topic event.
+type Workflow struct {
+	Steps []WorkflowStep `yaml:"steps"`
+}
+
+// Database is a relational database, its connection details are injected into the functions that use it.
+type Database struct {
+	// The database engine, only postgres is currently supported
+	Engine string `yaml:"engine,omitempty"`
+
+	// The functions that connect to the database
+	Functions []string `yaml:"functions,omitempty"`
+}
+
+// Cache is a Redis cache, its connection details are injected into the functions that use it.
+type Cache struct {
+	// The functions that connect to the cache
+	Functions []string `yaml:"functions,omitempty"`
+}
+
+// Email sends transactional email from a verified sender, the functions that use it are granted
+// permission to send.
+type Email struct {
+	// The address email is sent from, e.g. noreply@example.com
+	Sender string `yaml:"sender"`
+
+	// Verify the whole domain rather than only the sender address
+	Domain string `yaml:"domain,omitempty"`
+
+	// The Secret Manager secret holding a SendGrid API key, used on GCP which has no email service
+	ApiKeySecret string `yaml:"apiKeySecret,omitempty"`
+
+	// The functions that send email
+	Functions []string `yaml:"functions,omitempty"`
+}
+
+// Build makes credentials available to image builds (e.g. for private git dependencies)
+// using BuildKit mounts, so they are never stored in the image layers.
+type Build struct {
+	// SSH agent sockets or keys to forward, e.g. "default" (RUN --mount=type=ssh)
+	SSH []string `yaml:"ssh,omitempty"`
+
+	// Secret ids mapped to the file they are read from, relative to the project (RUN --mount=type=secret,id=...)
+	Secrets map[string]string `yaml:"secrets,omitempty"`
+}
+
 type Project struct {
 	Dir         string                 `yaml:"-"`
 	Name        string                 `yaml:"name"`
@@ -120,12 +328,20 @@ type Project struct {
 	// but re-using the contract here provides us a serializable entity with no
 	// repetition/redefinition
 	// NOTE: if we want to use the proto definition here we would need support for yaml parsing to use customisable tags
-	Policies []*v1.PolicyResource `yaml:"-"`
-	Secrets  map[string]Secret    `yaml:"secrets,omitempty"`
+	Policies  []*v1.PolicyResource `yaml:"-"`
+	Secrets   map[string]Secret    `yaml:"secrets,omitempty"`
+	Sites     map[string]Site      `yaml:"sites,omitempty"`
+	Workflows map[string]Workflow  `yaml:"workflows,omitempty"`
+	Databases map[string]Database  `yaml:"databases,omitempty"`
+	Caches    map[string]Cache     `yaml:"caches,omitempty"`
+	Emails    map[string]Email     `yaml:"emails,omitempty"`
+	Build     Build                `yaml:"build,omitempty"`
+	// Mock is set when the apis respond with the examples of their openapi files, the project has no compute.
+	Mock bool `yaml:"-"`
 }
 
 func New(config *Config) *Project {
-	return &Project{
+	p := &Project{
 		Name:        config.Name,
 		Dir:         config.Dir,
 		Containers:  map[string]Container{},
@@ -139,7 +355,46 @@ func New(config *Config) *Project {
 		ApiDocs:     map[string]*openapi3.T{},
 		Policies:    make([]*v1.PolicyResource, 0),
 		Secrets:     map[string]Secret{},
+		Sites:       config.Sites,
+		Workflows:   config.Workflows,
+		Databases:   config.Databases,
+		Caches:      config.Caches,
+		Emails:      config.Emails,
+		Build:       config.Build,
+	}
+	for k, t := range config.Topics {
+		p.Topics[k] = t
+	}
+	return p
+}
+
+// AddTopic declares the topic, keeping its settings when it is already declared.
+func (s *Project) AddTopic(name string) {
+	if _, ok := s.Topics[name]; !ok {
+		s.Topics[name] = Topic{}
+	}
+}
+
+// WorkflowStepTopic returns the topic used to deliver events for the step.
+func (s *Project) WorkflowStepTopic(step WorkflowStep) (string, error) {
+	f, ok := s.Functions[step.Function]
+	if !ok {
+		return "", fmt.Errorf("function %s does not exist", step.Function)
 	}
+
+	if step.Topic == "" {
+		if len(f.Triggers.Topics) != 1 {
+			return "", fmt.Errorf("function %s must subscribe to exactly one topic, or the step must set the topic", step.Function)
+		}
+		return f.Triggers.Topics[0], nil
+	}
+
+	for _, t := range f.Triggers.Topics {
+		if t == step.Topic {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("function %s does not subscribe to topic %s", step.Function, step.Topic)
 }
 
 func (s *Project) Computes() []Compute {
@@ -168,6 +423,14 @@ func calculateDefaultPolicies(s *Project) []*v1.PolicyResource {
 		})
 	}
 
+	// containers are deployed as functions
+	for name := range s.Containers {
+		principals = append(principals, &v1.Resource{
+			Name: name,
+			Type: v1.ResourceType_Function,
+		})
+	}
+
 	topicResources := make([]*v1.Resource, 0, len(s.Topics))
 	for name := range s.Topics {
 		topicResources = append(topicResources, &v1.Resource{
@@ -0,0 +1,54 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+	"path"
+	"sort"
+)
+
+// Volumes returns the names of the volumes mounted by the project's containers, with the containers that mount each.
+func (s *Project) Volumes() map[string][]string {
+	volumes := map[string][]string{}
+	for _, c := range s.Containers {
+		for v := range c.Volumes {
+			volumes[v] = append(volumes[v], c.Name)
+		}
+	}
+	for _, containers := range volumes {
+		sort.Strings(containers)
+	}
+	return volumes
+}
+
+// ValidateVolumes checks the volumes are mounted at distinct absolute paths in each container.
+func (s *Project) ValidateVolumes() error {
+	for _, c := range s.Containers {
+		paths := map[string]string{}
+		for v, m := range c.Volumes {
+			if !path.IsAbs(m.Path) || path.Clean(m.Path) == "/" {
+				return fmt.Errorf("volume %s of %s must be mounted at an absolute path other than /", v, c.Name)
+			}
+			if other, ok := paths[path.Clean(m.Path)]; ok {
+				return fmt.Errorf("volumes %s and %s of %s are both mounted at %s", other, v, c.Name, m.Path)
+			}
+			paths[path.Clean(m.Path)] = v
+		}
+	}
+	return nil
+}
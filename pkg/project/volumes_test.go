@@ -0,0 +1,63 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVolumes(t *testing.T) {
+	s := &Project{
+		Containers: map[string]Container{
+			"render": {
+				ComputeUnit: ComputeUnit{Name: "render"},
+				Volumes:     map[string]VolumeMount{"assets": {Path: "/mnt/assets"}, "cache": {Path: "/var/cache/render"}},
+			},
+			"publish": {
+				ComputeUnit: ComputeUnit{Name: "publish"},
+				Volumes:     map[string]VolumeMount{"assets": {Path: "/assets", ReadOnly: true}},
+			},
+			"api": {ComputeUnit: ComputeUnit{Name: "api"}},
+		},
+	}
+
+	if err := s.ValidateVolumes(); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{"assets": {"publish", "render"}, "cache": {"render"}}
+	if got := s.Volumes(); !cmp.Equal(got, want) {
+		t.Error(cmp.Diff(got, want))
+	}
+
+	s.Containers["api"] = Container{
+		ComputeUnit: ComputeUnit{Name: "api"},
+		Volumes:     map[string]VolumeMount{"assets": {Path: "assets"}},
+	}
+	if err := s.ValidateVolumes(); err == nil {
+		t.Error("expected an error for a relative path")
+	}
+
+	s.Containers["api"] = Container{
+		ComputeUnit: ComputeUnit{Name: "api"},
+		Volumes:     map[string]VolumeMount{"assets": {Path: "/data"}, "cache": {Path: "/data/"}},
+	}
+	if err := s.ValidateVolumes(); err == nil {
+		t.Error("expected an error for two volumes at the same path")
+	}
+}
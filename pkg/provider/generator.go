@@ -22,15 +22,24 @@ import (
 	"github.com/nitrictech/cli/pkg/project"
 	"github.com/nitrictech/cli/pkg/provider/pulumi"
 	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/settings"
 	"github.com/nitrictech/cli/pkg/stack"
 	"github.com/nitrictech/cli/pkg/utils"
 )
 
 func NewProvider(p *project.Project, s *stack.Config, envMap map[string]string) (types.Provider, error) {
+	var prov types.Provider
+	var err error
 	switch s.Provider {
 	case stack.Aws, stack.Azure, stack.Digitalocean, stack.Gcp:
-		return pulumi.New(p, s, envMap)
+		prov, err = pulumi.New(p, s, envMap)
 	default:
 		return nil, utils.NewNotSupportedErr(fmt.Sprintf("provider %s is not supported", s.Provider))
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	prov.SetReadOnly(settings.Get().ReadOnly)
+	return prov, nil
 }
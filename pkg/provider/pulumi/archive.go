@@ -0,0 +1,214 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pterm/pterm"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/sig"
+
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+const (
+	snapshotPre  = "pre"
+	snapshotPost = "post"
+)
+
+// snapshot is archived before and after each update and delete of a stack with an archive.
+type snapshot struct {
+	Project         string            `json:"project"`
+	Stack           string            `json:"stack"`
+	Target          string            `json:"target"`
+	Operation       string            `json:"operation"`
+	Phase           string            `json:"phase"`
+	Time            time.Time         `json:"time"`
+	User            string            `json:"user,omitempty"`
+	GitCommit       string            `json:"gitCommit,omitempty"`
+	Result          string            `json:"result,omitempty"`
+	Error           string            `json:"error,omitempty"`
+	ResourceChanges map[string]int    `json:"resourceChanges,omitempty"`
+	Outputs         map[string]string `json:"outputs"`
+	State           interface{}       `json:"state"`
+}
+
+// archiver writes the snapshots of an operation to the stack's archive.
+type archiver struct {
+	p         *pulumiDeployment
+	operation string
+	started   time.Time
+}
+
+// archiver returns the archiver of the operation, or nil if the stack is not archived.
+func (p *pulumiDeployment) archiver(operation string) *archiver {
+	if p.sc.Archive == nil {
+		return nil
+	}
+	return &archiver{p: p, operation: operation, started: time.Now().UTC()}
+}
+
+// validateArchive returns an error when the stack has an archive without a bucket, or its provider can't write to one.
+func (p *pulumiDeployment) validateArchive() error {
+	if p.sc.Archive == nil {
+		return nil
+	}
+	if p.sc.Archive.Bucket == "" {
+		return fmt.Errorf("the archive of stack %s needs a bucket", p.sc.Name)
+	}
+	if _, ok := p.prov.(common.StateArchiver); !ok {
+		return utils.NewNotSupportedErr("archiving stacks is not supported on " + p.sc.Provider)
+	}
+	return nil
+}
+
+// pre archives the stack before the operation changes it.
+func (a *archiver) pre(ctx context.Context, s *auto.Stack, log output.Progress) {
+	if a == nil {
+		return
+	}
+	a.write(ctx, s, &snapshot{Phase: snapshotPre}, log)
+}
+
+// post archives the stack after the operation, with the resources it changed and its error.
+func (a *archiver) post(ctx context.Context, s *auto.Stack, changes *map[string]int, opErr error, log output.Progress) {
+	if a == nil {
+		return
+	}
+	snap := &snapshot{Phase: snapshotPost, Result: "success"}
+	if changes != nil {
+		snap.ResourceChanges = *changes
+	}
+	if opErr != nil {
+		snap.Result = "failure"
+		snap.Error = opErr.Error()
+	}
+	a.write(ctx, s, snap, log)
+}
+
+// write completes the snapshot with the stack and writes it, the operation isn't failed when it can't be.
+func (a *archiver) write(ctx context.Context, s *auto.Stack, snap *snapshot, log output.Progress) {
+	log.Busyf("Archiving the %s-%s snapshot of the stack", snap.Phase, a.operation)
+	key, err := a.archive(ctx, s, snap)
+	if err != nil {
+		pterm.Warning.Printf("unable to archive the %s-%s snapshot of the stack: %v\n", snap.Phase, a.operation, err)
+		return
+	}
+	log.Debugf("archived the stack to %s", key)
+}
+
+func (a *archiver) archive(ctx context.Context, s *auto.Stack, snap *snapshot) (string, error) {
+	archive := a.p.sc.Archive
+	w, ok := a.p.prov.(common.StateArchiver)
+	if !ok {
+		return "", utils.NewNotSupportedErr("archiving stacks is not supported on " + a.p.sc.Provider)
+	}
+
+	dep, _, err := exportState(ctx, s)
+	if err != nil {
+		return "", err
+	}
+	var state interface{}
+	if len(dep.Deployment) > 0 {
+		if err := json.Unmarshal(dep.Deployment, &state); err != nil {
+			return "", errors.WithMessage(err, "decoding stack state")
+		}
+	}
+
+	outputs, err := s.Outputs(ctx)
+	if err != nil {
+		return "", errors.WithMessage(err, "Outputs")
+	}
+
+	snap.Project = a.p.proj.Name
+	snap.Stack = a.p.sc.Name
+	snap.Target = a.p.sc.Provider
+	snap.Operation = a.operation
+	snap.Time = time.Now().UTC()
+	snap.User = utils.GitUser(a.p.proj.Dir)
+	snap.GitCommit = utils.GitCommit(a.p.proj.Dir)
+	snap.Outputs = redactOutputs(outputs)
+	snap.State = redactSecrets(state)
+
+	b, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	retainUntil := time.Time{}
+	if archive.RetentionDays > 0 {
+		retainUntil = snap.Time.AddDate(0, 0, archive.RetentionDays)
+	}
+
+	key := snapshotKey(archive.PrefixOrDefault(), a.p.proj.Name, a.p.sc.Name, a.operation, snap.Phase, a.started)
+	return key, w.ArchiveState(archive.Bucket, key, b, retainUntil)
+}
+
+// destroyErr returns the error of a destroy, or an error counting the resources that failed to delete.
+func destroyErr(failed map[string]string, err error) error {
+	if err == nil && len(failed) > 0 {
+		return fmt.Errorf("%d resources failed to delete", len(failed))
+	}
+	return err
+}
+
+// snapshotKey returns the key of a snapshot, the snapshots of an operation share the time it started.
+func snapshotKey(prefix, project, stack, operation, phase string, started time.Time) string {
+	return fmt.Sprintf("%s%s/%s/%s-%s/%s.json", prefix, project, stack, started.Format("20060102T150405Z"), operation, phase)
+}
+
+// redactOutputs returns the stack outputs as strings, masking those marked as secret.
+func redactOutputs(outputs auto.OutputMap) map[string]string {
+	redacted := map[string]string{}
+	for k, v := range outputs {
+		redacted[k] = fmt.Sprint(v.Value)
+		if v.Secret {
+			redacted[k] = secretMask
+		}
+	}
+	return redacted
+}
+
+// redactSecrets returns the decoded state with each secret value replaced by the mask.
+func redactSecrets(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if t[sig.Key] == sig.Secret {
+			return secretMask
+		}
+		redacted := map[string]interface{}{}
+		for k, e := range t {
+			redacted[k] = redactSecrets(e)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(t))
+		for i, e := range t {
+			redacted[i] = redactSecrets(e)
+		}
+		return redacted
+	default:
+		return v
+	}
+}
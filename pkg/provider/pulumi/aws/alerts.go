@@ -0,0 +1,124 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/cloudwatch"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/route53"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/sns"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/sqs"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+type AlertsArgs struct {
+	Region string
+	Alerts *stack.Alerts
+	Funcs  map[string]*Lambda
+	Queues map[string]*sqs.Queue
+	Apis   map[string]*ApiGateway
+}
+
+type Alerts struct {
+	pulumi.ResourceState
+
+	Name         string
+	Topic        *sns.Topic
+	Alarms       map[string]*cloudwatch.MetricAlarm
+	HealthChecks map[string]*route53.HealthCheck
+}
+
+// newAlerts creates a CloudWatch alarm for each rule and the uptime checks, notifying the email address through
+// an SNS topic.
+func newAlerts(ctx *pulumi.Context, name string, args *AlertsArgs, opts ...pulumi.ResourceOption) (*Alerts, error) {
+	res := &Alerts{Name: name, Alarms: map[string]*cloudwatch.MetricAlarm{}, HealthChecks: map[string]*route53.HealthCheck{}}
+	err := ctx.RegisterComponentResource("nitric:alerts:CloudWatch", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	res.Topic, err = sns.NewTopic(ctx, name+"Topic", &sns.TopicArgs{
+		Tags: common.Tags(ctx, name+"Topic"),
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "alerts topic")
+	}
+
+	_, err = sns.NewTopicSubscription(ctx, name+"Email", &sns.TopicSubscriptionArgs{
+		Topic:    res.Topic.Arn,
+		Protocol: pulumi.String("email"),
+		Endpoint: pulumi.String(args.Alerts.Email),
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "alerts email subscription")
+	}
+
+	for k, r := range args.Alerts.Rules {
+		alarm := &cloudwatch.MetricAlarmArgs{
+			AlarmDescription:   pulumi.String(fmt.Sprintf("%s %s above %v", r.Function+r.Queue, r.Metric, r.Threshold)),
+			ComparisonOperator: pulumi.String("GreaterThanThreshold"),
+			EvaluationPeriods:  pulumi.Int(r.MinutesOrDefault()),
+			Period:             pulumi.Int(60),
+			Threshold:          pulumi.Float64(r.Threshold),
+			TreatMissingData:   pulumi.String("notBreaching"),
+			AlarmActions:       pulumi.Array{res.Topic.Arn},
+			OkActions:          pulumi.Array{res.Topic.Arn},
+			Tags:               common.Tags(ctx, k),
+		}
+
+		switch r.Metric {
+		case stack.AlertErrorRate:
+			alarm.Namespace = pulumi.String("AWS/Lambda")
+			alarm.MetricName = pulumi.String("Errors")
+			alarm.Statistic = pulumi.String("Sum")
+			alarm.Dimensions = pulumi.StringMap{"FunctionName": args.Funcs[r.Function].Function.Name}
+		case stack.AlertLatency:
+			alarm.Namespace = pulumi.String("AWS/Lambda")
+			alarm.MetricName = pulumi.String("Duration")
+			alarm.ExtendedStatistic = pulumi.String("p95")
+			alarm.Dimensions = pulumi.StringMap{"FunctionName": args.Funcs[r.Function].Function.Name}
+		case stack.AlertQueueDepth:
+			alarm.Namespace = pulumi.String("AWS/SQS")
+			alarm.MetricName = pulumi.String("ApproximateNumberOfMessagesVisible")
+			alarm.Statistic = pulumi.String("Maximum")
+			alarm.Dimensions = pulumi.StringMap{"QueueName": args.Queues[r.Queue].Name}
+		}
+
+		res.Alarms[k], err = cloudwatch.NewMetricAlarm(ctx, k+"Alarm", alarm, opts...)
+		if err != nil {
+			return nil, errors.WithMessage(err, "alarm "+k)
+		}
+	}
+
+	if args.Alerts.Uptime != nil {
+		if err := res.newUptimeChecks(ctx, args, opts...); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":  pulumi.String(res.Name),
+		"topic": res.Topic.Arn,
+	})
+}
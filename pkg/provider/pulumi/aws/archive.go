@@ -0,0 +1,58 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+var _ common.StateArchiver = &awsProvider{}
+
+// ArchiveState writes the snapshot to the S3 bucket, locking it in compliance mode until retainUntil.
+func (a *awsProvider) ArchiveState(bucket, key string, body []byte, retainUntil time.Time) error {
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(a.sc.Region)})
+	if err != nil {
+		return errors.WithMessage(err, "aws session")
+	}
+
+	// object lock requires the checksum of the content
+	sum := md5.Sum(body)
+	in := &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("application/json"),
+		ContentMD5:  aws.String(base64.StdEncoding.EncodeToString(sum[:])),
+	}
+	if !retainUntil.IsZero() {
+		in.ObjectLockMode = aws.String(s3.ObjectLockModeCompliance)
+		in.ObjectLockRetainUntilDate = aws.Time(retainUntil)
+	}
+
+	_, err = s3.New(sess).PutObject(in)
+	return errors.WithMessage(err, "PutObject")
+}
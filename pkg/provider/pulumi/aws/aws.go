@@ -31,6 +31,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/dynamodb"
 	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/ecr"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/ecs"
 	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/iam"
 	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/resourcegroups"
 	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/s3"
@@ -52,10 +53,15 @@ type awsProvider struct {
 	sc     *stack.Config
 	envMap map[string]string
 	tmpDir string
+	// names are the names of the deployed resources when the stack uses stable naming
+	names *common.StableNames
+	// deployedImages are the images pushed by the last update, keyed by source image, when they are reused
+	deployedImages map[string]string
 
 	// created resources (mostly here for testing)
 	rg          *resourcegroups.Group
 	topics      map[string]*sns.Topic
+	bus         *EventBus
 	buckets     map[string]*s3.Bucket
 	queues      map[string]*sqs.Queue
 	collections map[string]*dynamodb.Table
@@ -63,11 +69,19 @@ type awsProvider struct {
 	images      map[string]*common.Image
 	funcs       map[string]*Lambda
 	schedules   map[string]*Schedule
+	databases   map[string]*AuroraDatabase
+	emails      map[string]*SesIdentity
+	services    map[string]*FargateService
+	jobs        map[string]*FargateJob
+	volumes     map[string]*EfsVolume
 }
 
 //go:embed pulumi-aws-version.txt
 var awsPluginVersion string
 
+//go:embed pulumi-random-version.txt
+var randomPluginVersion string
+
 func New(s *project.Project, t *stack.Config, envMap map[string]string) common.PulumiProvider {
 	return &awsProvider{
 		proj:        s,
@@ -81,6 +95,11 @@ func New(s *project.Project, t *stack.Config, envMap map[string]string) common.P
 		images:      map[string]*common.Image{},
 		funcs:       map[string]*Lambda{},
 		schedules:   map[string]*Schedule{},
+		databases:   map[string]*AuroraDatabase{},
+		emails:      map[string]*SesIdentity{},
+		services:    map[string]*FargateService{},
+		jobs:        map[string]*FargateJob{},
+		volumes:     map[string]*EfsVolume{},
 	}
 }
 
@@ -99,6 +118,10 @@ func (a *awsProvider) Plugins() []common.Plugin {
 			Name:    "aws",
 			Version: strings.TrimSpace(awsPluginVersion),
 		},
+		{
+			Name:    "random",
+			Version: strings.TrimSpace(randomPluginVersion),
+		},
 	}
 }
 
@@ -119,6 +142,41 @@ func (a *awsProvider) SupportedRegions() []string {
 	}
 }
 
+func (a *awsProvider) SetStableNames(names *common.StableNames) {
+	a.names = names
+}
+
+var _ common.ImageReuser = &awsProvider{}
+
+func (a *awsProvider) ReuseImages(images map[string]string) {
+	a.deployedImages = images
+}
+
+func (a *awsProvider) Capabilities() common.Capabilities {
+	return common.CapabilitiesExcept(
+		// ElastiCache is only reachable from inside a VPC and the lambdas are not deployed into one.
+		common.CapabilityCaches,
+		// lambdas are invoked through the runtime API, not over a port
+		common.CapabilityContainerPorts,
+		common.CapabilityDapr,
+		common.CapabilityCosmos,
+		common.CapabilityBucketEvents,
+		// HTTP APIs throttle routes by stage, usage plans and their quotas are only for REST APIs
+		common.CapabilityQuotas,
+		common.CapabilitySchemeLimits,
+		// JWT authorizers are only added to the api gateways on gcp so far
+		common.CapabilityJwt,
+		// HTTP APIs accept bodies up to their 10MB payload limit
+		common.CapabilityApiBodyLimits,
+		// existing topics are only read from event grid so far
+		common.CapabilityExistingTopics,
+		// HTTP APIs forward requests over HTTP/1.1 and services have no load balancer to serve HTTP/2
+		common.CapabilityHttp2,
+		// HTTP APIs have no mock integrations, every route needs a backend
+		common.CapabilityApiMocks,
+	)
+}
+
 func (a *awsProvider) Validate() error {
 	found := false
 	for _, r := range a.SupportedRegions() {
@@ -130,7 +188,54 @@ func (a *awsProvider) Validate() error {
 	if !found {
 		return utils.NewNotSupportedErr(fmt.Sprintf("region %s not supported on provider %s", a.sc.Region, a.sc.Provider))
 	}
-	return nil
+	if err := a.validateLayers(); err != nil {
+		return err
+	}
+	if err := a.validateTmpSizes(); err != nil {
+		return err
+	}
+	if err := a.validateVolumes(); err != nil {
+		return err
+	}
+	if err := a.validateApiLogs(); err != nil {
+		return err
+	}
+	// HTTP API integrations time out after 30 seconds at most
+	if err := common.ValidateApiRequestLimits(a.sc.Provider, a.sc.ApiRequests, 30, 0); err != nil {
+		return err
+	}
+	if err := a.validateWaf(); err != nil {
+		return err
+	}
+	if err := common.ValidateTls(a.sc.Tls); err != nil {
+		return err
+	}
+	if err := common.ValidateObservability(a.sc.Observability); err != nil {
+		return err
+	}
+	if err := common.ValidateAlerts(a.sc.Alerts, a.proj); err != nil {
+		return err
+	}
+	if err := common.ValidateBudget(a.sc.Budget); err != nil {
+		return err
+	}
+	if err := a.validateEventing(); err != nil {
+		return err
+	}
+	if err := a.validateSubscriptions(); err != nil {
+		return err
+	}
+	if err := common.ValidateSleep(a.sc.Sleep); err != nil {
+		return err
+	}
+	if err := common.ValidateKeepWarm(a.sc.KeepWarm, a.proj, 0); err != nil {
+		return err
+	}
+	if a.sc.Sleep != nil && a.sc.Sleep.TimeZoneOrDefault() != "UTC" {
+		// the scheduled scaling actions run in UTC
+		return fmt.Errorf("sleep schedules on %s are in UTC, remove the time zone %s", a.sc.Provider, a.sc.Sleep.TimeZone)
+	}
+	return common.ValidateEnv(a.sc.Provider, lambdaReservedEnv, a.envMap)
 }
 
 func (a *awsProvider) Configure(ctx context.Context, autoStack *auto.Stack) error {
@@ -189,13 +294,36 @@ func (a *awsProvider) Deploy(ctx *pulumi.Context) error {
 		return errors.WithMessage(err, "resource group create")
 	}
 
+	if err := a.validateEventFilters(); err != nil {
+		return err
+	}
+
+	if err := common.ValidateSubscriptions(a.sc.Subscriptions, a.proj); err != nil {
+		return err
+	}
+
+	if a.eventBridge() && len(a.proj.Topics) > 0 {
+		topicSchemas, err := a.proj.TopicSchemas()
+		if err != nil {
+			return err
+		}
+		// the topics are the detail-types of the events on the bus
+		a.bus, err = newEventBus(ctx, ctx.Stack(), &EventBusArgs{EventBridge: a.sc.EventBridge, Schemas: topicSchemas})
+		if err != nil {
+			return errors.WithMessage(err, "event bus")
+		}
+	}
+
 	for k := range a.proj.Topics {
+		if a.bus != nil {
+			continue
+		}
 		a.topics[k], err = sns.NewTopic(ctx, k, &sns.TopicArgs{
 			// FIXME: Autonaming of topics disabled until improvements to
 			// nitric topic name discovery is made for SNS topics.
 			Name: pulumi.StringPtr(k),
 			Tags: common.Tags(ctx, k),
-		})
+		}, pulumi.Protect(true))
 		if err != nil {
 			return errors.WithMessage(err, "sns topic "+k)
 		}
@@ -203,15 +331,29 @@ func (a *awsProvider) Deploy(ctx *pulumi.Context) error {
 
 	for k := range a.proj.Buckets {
 		a.buckets[k], err = s3.NewBucket(ctx, k, &s3.BucketArgs{
-			Tags: common.Tags(ctx, k),
-		})
+			Bucket: a.names.Name(ctx, "aws:s3/bucket:Bucket", k, stableName(ctx, k, 63)),
+			Tags:   common.Tags(ctx, k),
+		}, pulumi.Protect(true))
 		if err != nil {
 			return errors.WithMessage(err, "s3 bucket "+k)
 		}
+		ctx.Export("bucket:"+k, a.buckets[k].Bucket)
+
+		// the policy of a bucket served from a cdn also lets the cdn read it, so is created with the cdn
+		served := false
+		if a.sc.Cdn != nil {
+			_, served = a.sc.Cdn.Buckets[k]
+		}
+		if !served {
+			if err := newBucketTlsPolicy(ctx, k+"TlsPolicy", a.buckets[k], a.sc.TlsOrDefault()); err != nil {
+				return errors.WithMessage(err, "s3 bucket policy "+k)
+			}
+		}
 	}
 
 	for k := range a.proj.Queues {
 		a.queues[k], err = sqs.NewQueue(ctx, k, &sqs.QueueArgs{
+			Name: a.names.Name(ctx, "aws:sqs/queue:Queue", k, stableName(ctx, k, 80)),
 			Tags: common.Tags(ctx, k),
 		})
 		if err != nil {
@@ -221,6 +363,7 @@ func (a *awsProvider) Deploy(ctx *pulumi.Context) error {
 
 	for k := range a.proj.Collections {
 		a.collections[k], err = dynamodb.NewTable(ctx, k, &dynamodb.TableArgs{
+			Name: a.names.Name(ctx, "aws:dynamodb/table:Table", k, stableName(ctx, k, 255)),
 			Attributes: dynamodb.TableAttributeArray{
 				&dynamodb.TableAttributeArgs{
 					Name: pulumi.String("_pk"),
@@ -234,13 +377,26 @@ func (a *awsProvider) Deploy(ctx *pulumi.Context) error {
 			HashKey:     pulumi.String("_pk"),
 			RangeKey:    pulumi.String("_sk"),
 			BillingMode: pulumi.String("PAY_PER_REQUEST"),
-			Tags:        common.Tags(ctx, k),
-		})
+			PointInTimeRecovery: dynamodb.TablePointInTimeRecoveryArgs{
+				Enabled: pulumi.Bool(a.sc.Backups != nil),
+			},
+			Tags: common.Tags(ctx, k),
+		}, pulumi.Protect(true))
 		if err != nil {
 			return errors.WithMessage(err, "dynamodb table "+k)
 		}
 	}
 
+	if a.sc.Backups != nil && len(a.collections) > 0 {
+		_, err = newBackupPlan(ctx, "collections", &BackupPlanArgs{
+			Backups: a.sc.Backups,
+			Tables:  a.collections,
+		})
+		if err != nil {
+			return errors.WithMessage(err, "backup plan")
+		}
+	}
+
 	secrets := map[string]*secretsmanager.Secret{}
 	for k := range a.proj.Secrets {
 		secrets[k], err = secretsmanager.NewSecret(ctx, k, &secretsmanager.SecretArgs{
@@ -269,6 +425,27 @@ func (a *awsProvider) Deploy(ctx *pulumi.Context) error {
 		}
 	}
 
+	for k, db := range a.proj.Databases {
+		a.databases[k], err = newAuroraDatabase(ctx, k, &AuroraDatabaseArgs{Database: db})
+		if err != nil {
+			return errors.WithMessage(err, "database "+k)
+		}
+	}
+
+	for k, e := range a.proj.Emails {
+		a.emails[k], err = newSesIdentity(ctx, k, &SesIdentityArgs{Email: e})
+		if err != nil {
+			return errors.WithMessage(err, "email "+k)
+		}
+	}
+
+	for k, containers := range a.proj.Volumes() {
+		a.volumes[k], err = newEfsVolume(ctx, k, &EfsVolumeArgs{Containers: containers})
+		if err != nil {
+			return errors.WithMessage(err, "volume "+k)
+		}
+	}
+
 	authToken, err := ecr.GetAuthorizationToken(ctx, &ecr.GetAuthorizationTokenArgs{})
 	if err != nil {
 		return err
@@ -277,6 +454,8 @@ func (a *awsProvider) Deploy(ctx *pulumi.Context) error {
 	principalMap := make(map[v1.ResourceType]map[string]*iam.Role)
 	principalMap[v1.ResourceType_Function] = make(map[string]*iam.Role)
 
+	// services and jobs share a cluster, it is created with the first one
+	var cluster *ecs.Cluster
 	for _, c := range a.proj.Computes() {
 		localImageName := c.ImageTagName(a.proj, "")
 
@@ -289,6 +468,11 @@ func (a *awsProvider) Deploy(ctx *pulumi.Context) error {
 
 		image, ok := a.images[c.Unit().Name]
 		if !ok {
+			instructions, err := a.layerInstructions(c.Unit().Name, a.tmpDir)
+			if err != nil {
+				return errors.WithMessage(err, "function layers "+c.Unit().Name)
+			}
+
 			image, err = common.NewImage(ctx, c.Unit().Name, &common.ImageArgs{
 				LocalImageName:  localImageName,
 				SourceImageName: c.ImageTagName(a.proj, a.sc.Provider),
@@ -296,7 +480,9 @@ func (a *awsProvider) Deploy(ctx *pulumi.Context) error {
 				Server:          pulumi.String(authToken.ProxyEndpoint),
 				Username:        pulumi.String(authToken.UserName),
 				Password:        pulumi.String(authToken.Password),
-				TempDir:         a.tmpDir})
+				TempDir:         a.tmpDir,
+				Instructions:    instructions,
+				Deployed:        a.deployedImages[c.ImageTagName(a.proj, a.sc.Provider)]})
 
 			if err != nil {
 				return errors.WithMessage(err, "function image tag "+c.Unit().Name)
@@ -304,12 +490,85 @@ func (a *awsProvider) Deploy(ctx *pulumi.Context) error {
 			a.images[c.Unit().Name] = image
 		}
 
+		if c.Unit().AlwaysOn || c.Unit().Job != nil {
+			if cluster == nil {
+				cluster, err = ecs.NewCluster(ctx, "services", &ecs.ClusterArgs{
+					Tags: common.Tags(ctx, "services"),
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			serviceArgs := FargateServiceArgs{
+				StackName:   ctx.Stack(),
+				Cluster:     cluster,
+				Region:      a.sc.Region,
+				DockerImage: image.DockerImage,
+				Compute:     c,
+				EnvMap:      a.envMap,
+				Sleep:       a.sc.Sleep,
+				EventBus:    a.bus,
+				Volumes:     a.proj.Containers[c.Unit().Name].Volumes,
+				EfsVolumes:  a.volumes,
+			}
+
+			if c.Unit().Job != nil {
+				ctr := a.proj.Containers[c.Unit().Name]
+				schedules, err := ctr.ProjectSchedules()
+				if err != nil {
+					return err
+				}
+				a.jobs[c.Unit().Name], err = newFargateJob(ctx, c.Unit().Name, &FargateJobArgs{
+					FargateServiceArgs: serviceArgs,
+					Schedules:          schedules,
+					Queue:              a.queues[c.Unit().Job.Queue],
+				})
+				if err != nil {
+					return errors.WithMessage(err, "fargate job "+c.Unit().Name)
+				}
+
+				principalMap[v1.ResourceType_Function][c.Unit().Name] = a.jobs[c.Unit().Name].Role
+				continue
+			}
+
+			a.services[c.Unit().Name], err = newFargateService(ctx, c.Unit().Name, &serviceArgs)
+			if err != nil {
+				return errors.WithMessage(err, "fargate service "+c.Unit().Name)
+			}
+
+			principalMap[v1.ResourceType_Function][c.Unit().Name] = a.services[c.Unit().Name].Role
+			continue
+		}
+
+		databases := map[string]*AuroraDatabase{}
+		for _, k := range a.proj.DatabasesFor(c.Unit().Name) {
+			databases[k] = a.databases[k]
+		}
+		emails := map[string]*SesIdentity{}
+		for _, k := range a.proj.EmailsFor(c.Unit().Name) {
+			emails[k] = a.emails[k]
+		}
+
+		eventFilters, err := a.eventFilters(c.Unit().Name, c.Unit().Triggers.Topics)
+		if err != nil {
+			return err
+		}
+
 		a.funcs[c.Unit().Name], err = newLambda(ctx, c.Unit().Name, &LambdaArgs{
-			Topics:      a.topics,
-			DockerImage: image.DockerImage,
-			Compute:     c,
-			StackName:   ctx.Stack(),
-			EnvMap:      a.envMap,
+			Topics:        a.topics,
+			EventBus:      a.bus,
+			EventFilters:  eventFilters,
+			Databases:     databases,
+			Emails:        emails,
+			DockerImage:   image.DockerImage,
+			Compute:       c,
+			StackName:     ctx.Stack(),
+			EnvMap:        a.envMap,
+			Observability: a.sc.Observability,
+			Subscriptions: a.sc.Subscriptions[c.Unit().Name],
+			// event source mappings can't limit their concurrency, so the function's is reserved
+			ReservedConcurrency: a.sc.MaxConcurrency(c.Unit().Name),
 		})
 		if err != nil {
 			return errors.WithMessage(err, "lambda container "+c.Unit().Name)
@@ -318,15 +577,147 @@ func (a *awsProvider) Deploy(ctx *pulumi.Context) error {
 		principalMap[v1.ResourceType_Function][c.Unit().Name] = a.funcs[c.Unit().Name].Role
 	}
 
+	if a.bus != nil {
+		for k, s := range a.proj.Schedules {
+			if s.Target.Type != "topic" || s.Target.Name == "" {
+				continue
+			}
+			if _, ok := a.proj.Topics[s.Target.Name]; !ok {
+				return fmt.Errorf("schedule %s does not have a topic %s", k, s.Target.Name)
+			}
+			a.schedules[k], err = a.newBusSchedule(ctx, k, BusScheduleArgs{
+				Expression:  s.Expression,
+				Topic:       s.Target.Name,
+				Subscribers: a.subscribers(s.Target.Name),
+			})
+			if err != nil {
+				return errors.WithMessage(err, "schedule "+k)
+			}
+		}
+	}
+
+	for k := range a.sc.ApiLogs {
+		if _, ok := a.proj.ApiDocs[k]; !ok {
+			return fmt.Errorf("access logs configured for api %s, but the api does not exist", k)
+		}
+	}
+
+	apis := map[string]*ApiGateway{}
 	for k, v := range a.proj.ApiDocs {
-		_, err = newApiGateway(ctx, k, &ApiGatewayArgs{
+		limits, err := common.ApiRateLimits(v, common.StackThrottling(a.sc, k))
+		if err != nil {
+			return errors.WithMessage(err, "gateway "+k)
+		}
+		apis[k], err = newApiGateway(ctx, k, &ApiGatewayArgs{
 			OpenAPISpec:     v,
-			LambdaFunctions: a.funcs})
+			LambdaFunctions: a.funcs,
+			Limits:          limits,
+			Logs:            apiLogs(a.sc, k),
+			Timeout:         a.sc.ApiRequests[k].Timeout,
+		})
 		if err != nil {
 			return errors.WithMessage(err, "gateway "+k)
 		}
 	}
 
+	if len(a.proj.Sites) > 0 {
+		endpoints := map[string]pulumi.StringInput{}
+		for k, api := range apis {
+			endpoints[k] = api.Api.ApiEndpoint
+		}
+		for k, site := range a.proj.Sites {
+			_, err = newSite(ctx, k, &SiteArgs{
+				ProjectDir: a.proj.Dir,
+				Site:       site,
+				Cdn:        a.sc.SiteCdnTarget(k),
+				Tls:        a.sc.TlsOrDefault(),
+				Env:        common.SiteEnv(endpoints),
+			})
+			if err != nil {
+				return errors.WithMessage(err, "site "+k)
+			}
+		}
+	}
+
+	for k, w := range a.proj.Workflows {
+		stepTopics := []string{}
+		for _, step := range w.Steps {
+			topic, err := a.proj.WorkflowStepTopic(step)
+			if err != nil {
+				return errors.WithMessage(err, "workflow "+k)
+			}
+			stepTopics = append(stepTopics, topic)
+		}
+		_, err = newWorkflow(ctx, k, &WorkflowArgs{
+			Workflow:   w,
+			StepTopics: stepTopics,
+			Topics:     a.topics,
+			EventBus:   a.bus,
+			Funcs:      a.funcs,
+		})
+		if err != nil {
+			return errors.WithMessage(err, "workflow "+k)
+		}
+	}
+
+	for k, minutes := range a.sc.KeepWarm {
+		fn, ok := a.funcs[k]
+		if !ok {
+			return fmt.Errorf("keepWarm configured for function %s, but the function does not exist", k)
+		}
+		if _, err = newKeepWarm(ctx, k+"KeepWarm", &KeepWarmArgs{Minutes: minutes, Lambda: fn}); err != nil {
+			return errors.WithMessage(err, "keepWarm "+k)
+		}
+	}
+
+	if a.sc.Alerts != nil && (len(a.sc.Alerts.Rules) > 0 || a.sc.Alerts.Uptime != nil) {
+		_, err = newAlerts(ctx, "alerts", &AlertsArgs{
+			Region: a.sc.Region,
+			Alerts: a.sc.Alerts,
+			Funcs:  a.funcs,
+			Queues: a.queues,
+			Apis:   apis,
+		})
+		if err != nil {
+			return errors.WithMessage(err, "alerts")
+		}
+	}
+
+	if a.sc.Budget != nil {
+		if _, err = newBudget(ctx, "budget", &BudgetArgs{Budget: a.sc.Budget}); err != nil {
+			return errors.WithMessage(err, "budget")
+		}
+	}
+
+	if a.sc.Cdn != nil {
+		var webAclArn pulumi.StringPtrInput
+		if a.sc.Waf != nil {
+			waf, err := newWaf(ctx, "waf", &WafArgs{Waf: a.sc.Waf})
+			if err != nil {
+				return errors.WithMessage(err, "waf")
+			}
+			webAclArn = waf.WebAcl.Arn
+		}
+		for k, target := range a.sc.Cdn.Apis {
+			api, ok := apis[k]
+			if !ok {
+				return fmt.Errorf("cdn configured for api %s, but the api does not exist", k)
+			}
+			if _, err = newCdn(ctx, k+"-api-cdn", &CdnArgs{Target: target, Api: api, Tls: a.sc.TlsOrDefault(), WebAclArn: webAclArn}); err != nil {
+				return errors.WithMessage(err, "cdn "+k)
+			}
+		}
+		for k, target := range a.sc.Cdn.Buckets {
+			bucket, ok := a.buckets[k]
+			if !ok {
+				return fmt.Errorf("cdn configured for bucket %s, but the bucket does not exist", k)
+			}
+			if _, err = newCdn(ctx, k+"-bucket-cdn", &CdnArgs{Target: target, Bucket: bucket, Tls: a.sc.TlsOrDefault()}); err != nil {
+				return errors.WithMessage(err, "cdn "+k)
+			}
+		}
+	}
+
 	for _, p := range a.proj.Policies {
 		if len(p.Actions) == 0 {
 			// note Topic receiving does not require an action.
@@ -342,6 +733,7 @@ func (a *awsProvider) Deploy(ctx *pulumi.Context) error {
 			Policy: p,
 			Resources: &StackResources{
 				Topics:      a.topics,
+				EventBus:    a.bus,
 				Queues:      a.queues,
 				Buckets:     a.buckets,
 				Collections: a.collections,
@@ -356,8 +748,32 @@ func (a *awsProvider) Deploy(ctx *pulumi.Context) error {
 	return nil
 }
 
+// subscribers are the lambdas triggered by the topic.
+func (a *awsProvider) subscribers(topic string) map[string]*Lambda {
+	subs := map[string]*Lambda{}
+	for _, c := range a.proj.Computes() {
+		fn, ok := a.funcs[c.Unit().Name]
+		if !ok {
+			continue
+		}
+		for _, t := range c.Unit().Triggers.Topics {
+			if t == topic {
+				subs[c.Unit().Name] = fn
+			}
+		}
+	}
+	return subs
+}
+
 func (a *awsProvider) CleanUp() {
 	if a.tmpDir != "" {
-		os.Remove(a.tmpDir)
+		os.RemoveAll(a.tmpDir)
 	}
 }
+
+// stableName is the name of a resource when the stack uses stable naming, the logical name with a suffix
+// that is the same each deployment.
+func stableName(ctx *pulumi.Context, logical string, maxLen int) string {
+	suffix := common.StableSuffix(ctx, logical)
+	return strings.ToLower(utils.StringTrunc(logical, maxLen-len(suffix)-1)) + "-" + suffix
+}
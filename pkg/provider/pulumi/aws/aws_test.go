@@ -17,11 +17,13 @@
 package aws
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"sync"
 	"testing"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/dynamodb"
 	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/s3"
 	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/sns"
@@ -261,6 +263,50 @@ func TestValidate(t *testing.T) {
 			t:       &stack.Config{Provider: stack.Aws, Region: "pole-north-right-next-to-santa"},
 			wantErr: true,
 		},
+		{
+			name: "api logs",
+			t: &stack.Config{Provider: stack.Aws, Region: "us-west-1", ApiLogs: map[string]stack.ApiLogs{
+				"main": {Format: `{"id":"$context.requestId","status":"$context.status"}`, RetentionDays: 14, DetailedMetrics: true},
+			}},
+		},
+		{
+			name: "waf",
+			t: &stack.Config{
+				Provider: stack.Aws, Region: "us-west-1",
+				Cdn: &stack.Cdn{Apis: map[string]stack.CdnTarget{"main": {}}},
+				Waf: &stack.Waf{ManagedRules: []string{"AWSManagedRulesCommonRuleSet"}, RateLimit: 2000},
+			},
+		},
+		{
+			name: "waf rate limit too low",
+			t: &stack.Config{
+				Provider: stack.Aws, Region: "us-west-1",
+				Cdn: &stack.Cdn{Apis: map[string]stack.CdnTarget{"main": {}}},
+				Waf: &stack.Waf{RateLimit: 50},
+			},
+			wantErr: true,
+		},
+		{
+			name: "api logs not json",
+			t: &stack.Config{Provider: stack.Aws, Region: "us-west-1", ApiLogs: map[string]stack.ApiLogs{
+				"main": {Format: "$context.requestId $context.status"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "api logs without request id",
+			t: &stack.Config{Provider: stack.Aws, Region: "us-west-1", ApiLogs: map[string]stack.ApiLogs{
+				"main": {Format: `{"status":"$context.status"}`},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "api logs retention",
+			t: &stack.Config{Provider: stack.Aws, Region: "us-west-1", ApiLogs: map[string]stack.ApiLogs{
+				"main": {RetentionDays: 10},
+			}},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -275,9 +321,146 @@ func TestValidate(t *testing.T) {
 func Test_awsProvider_Plugins(t *testing.T) {
 	want := []common.Plugin{
 		{Name: "aws", Version: "v4.37.5"},
+		{Name: "random", Version: "v4.4.2"},
 	}
 	got := (&awsProvider{}).Plugins()
 	if !reflect.DeepEqual(got, want) {
 		t.Errorf("awsProvider.Plugins() = %v, want %v", got, want)
 	}
 }
+
+func Test_workflowDefinition(t *testing.T) {
+	w := project.Workflow{Steps: []project.WorkflowStep{{Function: "a"}, {Function: "b"}}}
+	arns := map[string]string{"fn:a": "a-arn", "fn:b": "b-arn", "topic:ta": "ta-arn", "topic:tb": "tb-arn"}
+
+	got, err := workflowDefinition(w, []string{"ta", "tb"}, arns, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	def := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(got), &def))
+	assert.Equal(t, "1-a", def["StartAt"])
+
+	states := def["States"].(map[string]interface{})
+	first := states["1-a"].(map[string]interface{})
+	assert.Equal(t, "2-b", first["Next"])
+	assert.Equal(t, "a-arn", first["Parameters"].(map[string]interface{})["FunctionName"])
+
+	last := states["2-b"].(map[string]interface{})
+	assert.Equal(t, true, last["End"])
+	assert.Nil(t, last["ResultPath"])
+}
+
+func Test_workflowDefinitionEventBridge(t *testing.T) {
+	w := project.Workflow{Steps: []project.WorkflowStep{{Function: "a"}}}
+	arns := map[string]string{"fn:a": "a-arn"}
+
+	got, err := workflowDefinition(w, []string{"ta"}, arns, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	def := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(got), &def))
+
+	first := def["States"].(map[string]interface{})["1-a"].(map[string]interface{})
+	payload := first["Parameters"].(map[string]interface{})["Payload"].(map[string]interface{})
+	assert.Equal(t, eventSource, payload["source"])
+	assert.Equal(t, "ta", payload["detail-type"])
+	assert.Nil(t, payload["Records"])
+}
+
+func Test_topicPattern(t *testing.T) {
+	got, err := topicPattern("orders", map[string]interface{}{
+		"status": []interface{}{"paid"},
+		"total":  map[interface{}]interface{}{"numeric": []interface{}{">", 100}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"detail":{"payload":{"status":["paid"],"total":{"numeric":["\u003e",100]}}},"detail-type":["orders"],"source":["nitric"]}`
+	assert.Equal(t, want, got)
+}
+
+func Test_schemaFilter(t *testing.T) {
+	schema := openapi3.NewObjectSchema()
+	schema.Required = []string{"orderId", "status"}
+
+	got := schemaFilter(schema, map[string]interface{}{"status": []interface{}{"paid"}})
+	want := map[string]interface{}{
+		"orderId": []interface{}{map[string]interface{}{"exists": true}},
+		"status":  []interface{}{"paid"},
+	}
+	assert.Equal(t, want, got)
+}
+
+func Test_fargateStorage(t *testing.T) {
+	tests := []struct {
+		tmpSize int
+		want    int
+	}{
+		{tmpSize: 0, want: 0},
+		{tmpSize: 10240, want: 0},
+		{tmpSize: 20480, want: 0},
+		{tmpSize: 20481, want: 21},
+		{tmpSize: 51200, want: 50},
+	}
+	for _, tt := range tests {
+		if got := fargateStorage(tt.tmpSize); got != tt.want {
+			t.Errorf("fargateStorage(%d) = %d, want %d", tt.tmpSize, got, tt.want)
+		}
+	}
+}
+
+func Test_jobDefinition(t *testing.T) {
+	arns := map[string]string{"cluster": "cluster-arn", "task": "task-arn"}
+
+	got, err := jobDefinition(&project.Job{Timeout: "1h", Retries: 2}, arns, []string{"subnet-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	def := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(got), &def))
+	assert.Equal(t, "Run", def["StartAt"])
+
+	run := def["States"].(map[string]interface{})["Run"].(map[string]interface{})
+	assert.Equal(t, "arn:aws:states:::ecs:runTask.sync", run["Resource"])
+	assert.Equal(t, float64(3600), run["TimeoutSeconds"])
+	assert.Equal(t, "task-arn", run["Parameters"].(map[string]interface{})["TaskDefinition"])
+	assert.Equal(t, float64(2), run["Retry"].([]interface{})[0].(map[string]interface{})["MaxAttempts"])
+}
+
+func Test_jobDefinitionQueue(t *testing.T) {
+	arns := map[string]string{"cluster": "cluster-arn", "task": "task-arn", "queueUrl": "queue-url"}
+
+	got, err := jobDefinition(&project.Job{Queue: "imports"}, arns, []string{"subnet-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	def := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(got), &def))
+	assert.Equal(t, "CheckQueue", def["StartAt"])
+
+	states := def["States"].(map[string]interface{})
+	check := states["CheckQueue"].(map[string]interface{})
+	assert.Equal(t, "queue-url", check["Parameters"].(map[string]interface{})["QueueUrl"])
+
+	run := states["Run"].(map[string]interface{})
+	assert.Equal(t, project.DefaultJobTimeout.Seconds(), run["TimeoutSeconds"])
+	assert.Nil(t, run["Retry"])
+}
+
+func Test_tlsStatements(t *testing.T) {
+	got := tlsStatements("arn:aws:s3:::images", stack.Tls{MinVersion: "1.2"})
+	if len(got) != 2 || got[0]["Sid"] != "DenyHttp" || got[1]["Sid"] != "DenyOldTls" {
+		t.Errorf("tlsStatements() = %v, want DenyHttp and DenyOldTls", got)
+	}
+
+	got = tlsStatements("arn:aws:s3:::images", stack.Tls{MinVersion: "1.0", AllowHttp: true})
+	if len(got) != 1 || got[0]["Sid"] != "DenyOldTls" {
+		t.Errorf("tlsStatements() = %v, want only DenyOldTls", got)
+	}
+}
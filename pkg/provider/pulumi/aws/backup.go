@@ -0,0 +1,138 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"encoding/json"
+
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/backup"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/dynamodb"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/iam"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/cron"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+type BackupPlanArgs struct {
+	Backups *stack.Backups
+	Tables  map[string]*dynamodb.Table
+}
+
+type BackupPlan struct {
+	pulumi.ResourceState
+
+	Name      string
+	Vault     *backup.Vault
+	Plan      *backup.Plan
+	Role      *iam.Role
+	Selection *backup.Selection
+}
+
+// newBackupPlan takes scheduled AWS Backup snapshots of the tables, in addition to the point in time recovery
+// enabled on each table.
+func newBackupPlan(ctx *pulumi.Context, name string, args *BackupPlanArgs, opts ...pulumi.ResourceOption) (*BackupPlan, error) {
+	res := &BackupPlan{Name: name}
+	err := ctx.RegisterComponentResource("nitric:backup:AwsBackupPlan", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	schedule, err := cron.ConvertToAWS(args.Backups.ScheduleOrDefault())
+	if err != nil {
+		return nil, err
+	}
+
+	res.Vault, err = backup.NewVault(ctx, name+"Vault", &backup.VaultArgs{
+		Tags: common.Tags(ctx, name+"Vault"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Plan, err = backup.NewPlan(ctx, name+"Plan", &backup.PlanArgs{
+		Rules: backup.PlanRuleArray{
+			backup.PlanRuleArgs{
+				RuleName:        pulumi.String(name + "-scheduled"),
+				TargetVaultName: res.Vault.Name,
+				Schedule:        pulumi.String(schedule),
+				Lifecycle: backup.PlanRuleLifecycleArgs{
+					DeleteAfter: pulumi.Int(args.Backups.RetentionDaysOrDefault()),
+				},
+			},
+		},
+		Tags: common.Tags(ctx, name+"Plan"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpJSON, err := json.Marshal(map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{
+			{
+				"Sid":    "",
+				"Effect": "Allow",
+				"Principal": map[string]interface{}{
+					"Service": "backup.amazonaws.com",
+				},
+				"Action": "sts:AssumeRole",
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res.Role, err = iam.NewRole(ctx, name+"BackupRole", &iam.RoleArgs{
+		AssumeRolePolicy: pulumi.String(tmpJSON),
+		Tags:             common.Tags(ctx, name+"BackupRole"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = iam.NewRolePolicyAttachment(ctx, name+"BackupService", &iam.RolePolicyAttachmentArgs{
+		PolicyArn: pulumi.String("arn:aws:iam::aws:policy/service-role/AWSBackupServiceRolePolicyForBackup"),
+		Role:      res.Role.ID(),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := pulumi.StringArray{}
+	for _, t := range args.Tables {
+		tables = append(tables, t.Arn)
+	}
+
+	res.Selection, err = backup.NewSelection(ctx, name+"Selection", &backup.SelectionArgs{
+		IamRoleArn: res.Role.Arn,
+		PlanId:     res.Plan.ID(),
+		Resources:  tables,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name": pulumi.String(res.Name),
+		"plan": res.Plan,
+	})
+}
@@ -0,0 +1,139 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/budgets"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/iam"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/sns"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+type BudgetArgs struct {
+	Budget *stack.Budget
+}
+
+type Budget struct {
+	pulumi.ResourceState
+
+	Name   string
+	Budget *budgets.Budget
+	Topic  *sns.Topic
+}
+
+// newBudget creates a monthly cost budget of the resources tagged with the stack, notifying the emails directly
+// and the webhook through an SNS topic. The x-nitric-stack tag has to be activated as a cost allocation tag
+// in the billing console for the costs to be found.
+func newBudget(ctx *pulumi.Context, name string, args *BudgetArgs, opts ...pulumi.ResourceOption) (*Budget, error) {
+	res := &Budget{Name: name}
+	err := ctx.RegisterComponentResource("nitric:budget:AwsBudget", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	topics := pulumi.StringArray{}
+	if args.Budget.Webhook != "" {
+		res.Topic, err = sns.NewTopic(ctx, name+"Topic", &sns.TopicArgs{
+			Tags: common.Tags(ctx, name+"Topic"),
+		}, opts...)
+		if err != nil {
+			return nil, errors.WithMessage(err, "budget topic")
+		}
+
+		policy := res.Topic.Arn.ApplyT(func(arn string) (string, error) {
+			pdoc, err := iam.GetPolicyDocument(ctx, &iam.GetPolicyDocumentArgs{
+				Statements: []iam.GetPolicyDocumentStatement{
+					{
+						Effect:  to.StringPtr("Allow"),
+						Actions: []string{"SNS:Publish"},
+						Principals: []iam.GetPolicyDocumentStatementPrincipal{
+							{Type: "Service", Identifiers: []string{"budgets.amazonaws.com"}},
+						},
+						Resources: []string{arn},
+					},
+				},
+			})
+			if err != nil {
+				return "", err
+			}
+			return pdoc.Json, nil
+		}).(pulumi.StringOutput)
+
+		_, err = sns.NewTopicPolicy(ctx, name+"TopicPolicy", &sns.TopicPolicyArgs{
+			Arn:    res.Topic.Arn,
+			Policy: policy,
+		}, opts...)
+		if err != nil {
+			return nil, errors.WithMessage(err, "budget topic policy")
+		}
+
+		_, err = sns.NewTopicSubscription(ctx, name+"Webhook", &sns.TopicSubscriptionArgs{
+			Topic:    res.Topic.Arn,
+			Protocol: pulumi.String("https"),
+			Endpoint: pulumi.String(args.Budget.Webhook),
+		}, opts...)
+		if err != nil {
+			return nil, errors.WithMessage(err, "budget webhook subscription")
+		}
+
+		topics = append(topics, res.Topic.Arn)
+	}
+
+	notifications := budgets.BudgetNotificationArray{}
+	for _, t := range args.Budget.ThresholdsOrDefault() {
+		notifications = append(notifications, budgets.BudgetNotificationArgs{
+			ComparisonOperator:       pulumi.String("GREATER_THAN"),
+			NotificationType:         pulumi.String("ACTUAL"),
+			Threshold:                pulumi.Float64(t),
+			ThresholdType:            pulumi.String("PERCENTAGE"),
+			SubscriberEmailAddresses: pulumi.ToStringArray(args.Budget.Emails),
+			SubscriberSnsTopicArns:   topics,
+		})
+	}
+
+	res.Budget, err = budgets.NewBudget(ctx, name, &budgets.BudgetArgs{
+		Name:        pulumi.Sprintf("%s-%s", ctx.Project(), ctx.Stack()),
+		BudgetType:  pulumi.String("COST"),
+		LimitAmount: pulumi.String(fmt.Sprint(args.Budget.Amount)),
+		LimitUnit:   pulumi.String("USD"),
+		TimeUnit:    pulumi.String("MONTHLY"),
+		CostFilters: budgets.BudgetCostFilterArray{
+			budgets.BudgetCostFilterArgs{
+				Name:   pulumi.String("TagKeyValue"),
+				Values: pulumi.StringArray{pulumi.Sprintf("user:x-nitric-stack$%s", ctx.Stack())},
+			},
+		},
+		Notifications: notifications,
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "budget")
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":   pulumi.String(res.Name),
+		"budget": res.Budget.Name,
+	})
+}
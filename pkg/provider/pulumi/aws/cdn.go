@@ -0,0 +1,183 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/cloudfront"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/s3"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+type CdnArgs struct {
+	Target stack.CdnTarget
+
+	// Only one of Api or Bucket should be set
+	Api    *ApiGateway
+	Bucket *s3.Bucket
+
+	// The object to return for requests to the root URL, e.g. index.html
+	DefaultRootObject string
+
+	// Tls denies HTTP viewers and bucket requests that don't meet the stack's settings
+	Tls stack.Tls
+
+	// The ARN of the WAF web ACL to protect the distribution with, nil for none
+	WebAclArn pulumi.StringPtrInput
+}
+
+type Cdn struct {
+	pulumi.ResourceState
+
+	Name         string
+	Distribution *cloudfront.Distribution
+}
+
+func newCdn(ctx *pulumi.Context, name string, args *CdnArgs, opts ...pulumi.ResourceOption) (*Cdn, error) {
+	res := &Cdn{Name: name}
+	err := ctx.RegisterComponentResource("nitric:cdn:AwsCloudFront", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	origin := cloudfront.DistributionOriginArgs{
+		OriginId: pulumi.String(name),
+	}
+
+	if args.Bucket != nil {
+		oai, err := cloudfront.NewOriginAccessIdentity(ctx, name+"Oai", &cloudfront.OriginAccessIdentityArgs{
+			Comment: pulumi.String("nitric cdn " + name),
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		policy := pulumi.All(args.Bucket.Arn, oai.IamArn).ApplyT(func(all []interface{}) (string, error) {
+			statements := []map[string]interface{}{
+				{
+					"Effect":    "Allow",
+					"Principal": map[string]interface{}{"AWS": all[1].(string)},
+					"Action":    "s3:GetObject",
+					"Resource":  all[0].(string) + "/*",
+				},
+			}
+			b, err := json.Marshal(map[string]interface{}{
+				"Version":   "2012-10-17",
+				"Statement": append(statements, tlsStatements(all[0].(string), args.Tls)...),
+			})
+			return string(b), err
+		}).(pulumi.StringOutput)
+
+		_, err = s3.NewBucketPolicy(ctx, name+"OaiPolicy", &s3.BucketPolicyArgs{
+			Bucket: args.Bucket.ID(),
+			Policy: policy,
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		origin.DomainName = args.Bucket.BucketRegionalDomainName
+		origin.S3OriginConfig = &cloudfront.DistributionOriginS3OriginConfigArgs{
+			OriginAccessIdentity: oai.CloudfrontAccessIdentityPath,
+		}
+	} else {
+		origin.DomainName = args.Api.Api.ApiEndpoint.ApplyT(func(ep string) string {
+			return strings.TrimPrefix(ep, "https://")
+		}).(pulumi.StringOutput)
+		origin.CustomOriginConfig = &cloudfront.DistributionOriginCustomOriginConfigArgs{
+			HttpPort:             pulumi.Int(80),
+			HttpsPort:            pulumi.Int(443),
+			OriginProtocolPolicy: pulumi.String("https-only"),
+			OriginSslProtocols:   pulumi.ToStringArray([]string{"TLSv1.2"}),
+		}
+	}
+
+	allowedMethods := []string{"GET", "HEAD", "OPTIONS"}
+	if args.Api != nil {
+		allowedMethods = []string{"GET", "HEAD", "OPTIONS", "PUT", "POST", "PATCH", "DELETE"}
+	}
+
+	viewerProtocolPolicy := "redirect-to-https"
+	if args.Tls.AllowHttp {
+		viewerProtocolPolicy = "allow-all"
+	}
+
+	viewerCert := cloudfront.DistributionViewerCertificateArgs{
+		CloudfrontDefaultCertificate: pulumi.Bool(true),
+	}
+	if args.Target.Certificate != "" {
+		viewerCert = cloudfront.DistributionViewerCertificateArgs{
+			AcmCertificateArn:      pulumi.String(args.Target.Certificate),
+			SslSupportMethod:       pulumi.String("sni-only"),
+			MinimumProtocolVersion: pulumi.String("TLSv1.2_2021"),
+		}
+	}
+
+	res.Distribution, err = cloudfront.NewDistribution(ctx, name, &cloudfront.DistributionArgs{
+		Enabled:           pulumi.Bool(true),
+		DefaultRootObject: pulumi.StringPtr(args.DefaultRootObject),
+		Aliases:           pulumi.ToStringArray(args.Target.Domains),
+		Origins:           cloudfront.DistributionOriginArray{origin},
+		DefaultCacheBehavior: cloudfront.DistributionDefaultCacheBehaviorArgs{
+			TargetOriginId:       pulumi.String(name),
+			AllowedMethods:       pulumi.ToStringArray(allowedMethods),
+			CachedMethods:        pulumi.ToStringArray([]string{"GET", "HEAD"}),
+			ViewerProtocolPolicy: pulumi.String(viewerProtocolPolicy),
+			MinTtl:               pulumi.Int(0),
+			DefaultTtl:           pulumi.Int(args.Target.DefaultTTL),
+			MaxTtl:               pulumi.Int(args.Target.DefaultTTL),
+			ForwardedValues: &cloudfront.DistributionDefaultCacheBehaviorForwardedValuesArgs{
+				QueryString: pulumi.Bool(args.Api != nil),
+				Cookies: &cloudfront.DistributionDefaultCacheBehaviorForwardedValuesCookiesArgs{
+					Forward: pulumi.String("none"),
+				},
+			},
+		},
+		Restrictions: cloudfront.DistributionRestrictionsArgs{
+			GeoRestriction: cloudfront.DistributionRestrictionsGeoRestrictionArgs{
+				RestrictionType: pulumi.String("none"),
+			},
+		},
+		ViewerCertificate: viewerCert,
+		WebAclId:          args.WebAclArn,
+		Tags:              common.Tags(ctx, name),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Export("cdn:"+name, pulumi.Sprintf("https://%s", res.Distribution.DomainName))
+
+	if !args.Target.ManualDns {
+		err = newDomainRecords(ctx, name, args.Target.Domains, args.Target.Zone, res.Distribution, opts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":         pulumi.String(res.Name),
+		"distribution": res.Distribution,
+	})
+}
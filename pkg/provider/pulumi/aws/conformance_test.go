@@ -0,0 +1,75 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi-docker/sdk/v3/go/docker"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/test"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func TestConformance(t *testing.T) {
+	a := New(test.Project(), &stack.Config{Name: "deploy", Provider: stack.Aws, Region: "mock"}, map[string]string{}).(*awsProvider)
+	a.images[test.Function] = &common.Image{
+		DockerImage: &docker.Image{
+			ImageName: pulumi.Sprintf("docker.io/nitrictech/%s:latest", test.Function),
+		},
+	}
+	defer a.CleanUp()
+
+	test.Run(t, test.Conformance{
+		Deploy: a.Deploy,
+		Mocks: &test.Mocks{
+			Outputs: func(typ, name string) map[string]interface{} {
+				switch typ {
+				case "aws:sns/topic:Topic":
+					return map[string]interface{}{"arn": "test-arn"}
+				case "aws:s3/bucket:Bucket":
+					return map[string]interface{}{"bucket": name}
+				}
+				return nil
+			},
+		},
+		Types: map[string]string{
+			"topic":      "aws:sns/topic:Topic",
+			"bucket":     "aws:s3/bucket:Bucket",
+			"queue":      "aws:sqs/queue:Queue",
+			"collection": "aws:dynamodb/table:Table",
+			"secret":     "aws:secretsmanager/secret:Secret",
+		},
+		Compute: "aws:lambda/function:Function",
+		Env: func(r test.Resource) map[string]interface{} {
+			environment, _ := r.Inputs["environment"].(map[string]interface{})
+			env, _ := environment["variables"].(map[string]interface{})
+			return env
+		},
+		Grants: func(r test.Resource, function, bucket string) bool {
+			if r.Type != "aws:iam/rolePolicy:RolePolicy" || !strings.HasPrefix(r.Name, function+"-") {
+				return false
+			}
+			// the policy is unknown when it refers to the bucket's arn
+			policy, ok := r.Inputs["policy"].(string)
+			return !ok || strings.Contains(policy, "s3:GetObject")
+		},
+	})
+}
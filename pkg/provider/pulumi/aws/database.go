@@ -0,0 +1,153 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"encoding/json"
+
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/iam"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/rds"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/secretsmanager"
+	"github.com/pulumi/pulumi-random/sdk/v4/go/random"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+const databaseUser = "nitric"
+
+type AuroraDatabaseArgs struct {
+	Database project.Database
+}
+
+type AuroraDatabase struct {
+	pulumi.ResourceState
+
+	Name    string
+	Cluster *rds.Cluster
+	Secret  *secretsmanager.Secret
+}
+
+// newAuroraDatabase creates an Aurora Serverless postgres cluster with the Data API enabled, so
+// functions can query it without being placed in the cluster's VPC.
+func newAuroraDatabase(ctx *pulumi.Context, name string, args *AuroraDatabaseArgs, opts ...pulumi.ResourceOption) (*AuroraDatabase, error) {
+	res := &AuroraDatabase{Name: name}
+	err := ctx.RegisterComponentResource("nitric:database:AuroraServerless", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	password, err := random.NewRandomPassword(ctx, name+"-password", &random.RandomPasswordArgs{
+		Length:  pulumi.Int(32),
+		Special: pulumi.Bool(false),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Cluster, err = rds.NewCluster(ctx, project.DatabaseName(name), &rds.ClusterArgs{
+		Engine:             pulumi.String("aurora-postgresql"),
+		EngineMode:         pulumi.String("serverless"),
+		DatabaseName:       pulumi.String(project.DatabaseName(name)),
+		MasterUsername:     pulumi.String(databaseUser),
+		MasterPassword:     password.Result,
+		EnableHttpEndpoint: pulumi.Bool(true),
+		SkipFinalSnapshot:  pulumi.Bool(true),
+		ScalingConfiguration: rds.ClusterScalingConfigurationArgs{
+			AutoPause:             pulumi.Bool(true),
+			MinCapacity:           pulumi.Int(2),
+			MaxCapacity:           pulumi.Int(16),
+			SecondsUntilAutoPause: pulumi.Int(300),
+		},
+		Tags: common.Tags(ctx, name),
+	}, append(opts, pulumi.Protect(true))...)
+	if err != nil {
+		return nil, err
+	}
+
+	// the Data API authenticates with a secret holding the credentials
+	res.Secret, err = secretsmanager.NewSecret(ctx, name+"-credentials", &secretsmanager.SecretArgs{
+		Tags: common.Tags(ctx, name+"-credentials"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = secretsmanager.NewSecretVersion(ctx, name+"-credentials", &secretsmanager.SecretVersionArgs{
+		SecretId: res.Secret.ID(),
+		SecretString: password.Result.ApplyT(func(pw string) (string, error) {
+			b, err := json.Marshal(map[string]string{"username": databaseUser, "password": pw})
+			return string(b), err
+		}).(pulumi.StringOutput),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":    pulumi.String(res.Name),
+		"cluster": res.Cluster.Arn,
+	})
+}
+
+// databaseEnv are the env vars used to connect to a database with the Data API.
+func databaseEnv(name string) []types.EnvVar {
+	prefix := project.DatabaseEnvPrefix(name)
+	return []types.EnvVar{
+		{Name: prefix + "_CLUSTER_ARN", Value: types.DeployTimeValue, Source: types.EnvSourceProvider},
+		{Name: prefix + "_SECRET_ARN", Value: types.DeployTimeValue, Source: types.EnvSourceProvider},
+		{Name: prefix + "_NAME", Value: project.DatabaseName(name), Source: types.EnvSourceProvider},
+	}
+}
+
+// databaseAccess allows a function to query the database with the Data API.
+func databaseAccess(ctx *pulumi.Context, name string, role *iam.Role, db *AuroraDatabase, opts ...pulumi.ResourceOption) error {
+	policy := pulumi.All(db.Cluster.Arn, db.Secret.Arn).ApplyT(func(args []interface{}) (string, error) {
+		b, err := json.Marshal(map[string]interface{}{
+			"Version": "2012-10-17",
+			"Statement": []map[string]interface{}{
+				{
+					"Action": []string{
+						"rds-data:BatchExecuteStatement",
+						"rds-data:BeginTransaction",
+						"rds-data:CommitTransaction",
+						"rds-data:ExecuteStatement",
+						"rds-data:RollbackTransaction",
+					},
+					"Effect":   "Allow",
+					"Resource": args[0],
+				},
+				{
+					"Action":   []string{"secretsmanager:GetSecretValue"},
+					"Effect":   "Allow",
+					"Resource": args[1],
+				},
+			},
+		})
+		return string(b), err
+	}).(pulumi.StringOutput)
+
+	_, err := iam.NewRolePolicy(ctx, name, &iam.RolePolicyArgs{
+		Role:   role.ID(),
+		Policy: policy,
+	}, opts...)
+	return err
+}
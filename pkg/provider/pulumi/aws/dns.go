@@ -0,0 +1,80 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/cloudfront"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/route53"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// candidateZones are the names of the hosted zones that could hold the domain, most specific first.
+func candidateZones(domain string) []string {
+	labels := strings.Split(strings.TrimSuffix(domain, "."), ".")
+	zones := []string{}
+	for i := 0; i < len(labels)-1; i++ {
+		zones = append(zones, strings.Join(labels[i:], "."))
+	}
+	return zones
+}
+
+// hostedZone returns the id of the hosted zone of the domain, the configured zone if set or else the most
+// specific zone in the account named after the domain or one of its parents, "" when there is none.
+func hostedZone(ctx *pulumi.Context, domain, zone string) string {
+	if zone != "" {
+		return zone
+	}
+	for _, name := range candidateZones(domain) {
+		n := name
+		z, err := route53.LookupZone(ctx, &route53.LookupZoneArgs{Name: &n})
+		if err == nil && z.ZoneId != "" {
+			return z.ZoneId
+		}
+	}
+	return ""
+}
+
+// newDomainRecords aliases the distribution's domains in their hosted zones, warning about the domains
+// whose records have to be created by hand.
+func newDomainRecords(ctx *pulumi.Context, name string, domains []string, zone string, dist *cloudfront.Distribution, opts ...pulumi.ResourceOption) error {
+	for i, domain := range domains {
+		zoneId := hostedZone(ctx, domain, zone)
+		if zoneId == "" {
+			_ = ctx.Log.Warn(fmt.Sprintf("no hosted zone found for %s, create its records by hand (see nitric stack outputs)", domain), nil)
+			continue
+		}
+		_, err := route53.NewRecord(ctx, fmt.Sprintf("%s-domain%d", name, i), &route53.RecordArgs{
+			ZoneId: pulumi.String(zoneId),
+			Name:   pulumi.String(domain),
+			Type:   pulumi.String("A"),
+			Aliases: route53.RecordAliasArray{
+				route53.RecordAliasArgs{
+					Name:                 dist.DomainName,
+					ZoneId:               dist.HostedZoneId,
+					EvaluateTargetHealth: pulumi.Bool(false),
+				},
+			},
+		}, opts...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
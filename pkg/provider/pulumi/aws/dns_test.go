@@ -0,0 +1,40 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_candidateZones(t *testing.T) {
+	tests := []struct {
+		domain string
+		want   []string
+	}{
+		{domain: "api.example.com", want: []string{"api.example.com", "example.com"}},
+		{domain: "v1.api.example.com.", want: []string{"v1.api.example.com", "api.example.com", "example.com"}},
+		{domain: "example.com", want: []string{"example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			if got := candidateZones(tt.domain); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("candidateZones() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+// rootSortKey is the sort key the membrane gives documents of top level collections.
+const rootSortKey = "#"
+
+type dynamoDocuments struct {
+	stackName string
+	sess      *session.Session
+	client    *dynamodb.DynamoDB
+}
+
+var (
+	_ common.DocumentStorer = &awsProvider{}
+	_ types.DocumentStore   = &dynamoDocuments{}
+)
+
+func (a *awsProvider) Documents(stackName string) (types.DocumentStore, error) {
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(a.sc.Region)})
+	if err != nil {
+		return nil, errors.WithMessage(err, "aws session")
+	}
+	return &dynamoDocuments{stackName: stackName, sess: sess, client: dynamodb.New(sess)}, nil
+}
+
+// table finds the name of the collection's table, table names are generated when deployed.
+func (d *dynamoDocuments) table(collection string) (*string, error) {
+	arn, err := resourceArn(d.sess, d.stackName, "dynamodb:table", collection)
+	if err != nil {
+		return nil, err
+	}
+	// arn:aws:dynamodb:<region>:<account>:table/<name>
+	return aws.String(arn[strings.LastIndex(arn, "/")+1:]), nil
+}
+
+func documentKey(id string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"_pk": {S: aws.String(id)},
+		"_sk": {S: aws.String(rootSortKey)},
+	}
+}
+
+func toDocument(item map[string]*dynamodb.AttributeValue) (*types.Document, error) {
+	content := map[string]interface{}{}
+	if err := dynamodbattribute.UnmarshalMap(item, &content); err != nil {
+		return nil, err
+	}
+	id, _ := content["_pk"].(string)
+	delete(content, "_pk")
+	delete(content, "_sk")
+	return &types.Document{Id: id, Content: content}, nil
+}
+
+func (d *dynamoDocuments) Get(collection, id string) (*types.Document, error) {
+	table, err := d.table(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := d.client.GetItem(&dynamodb.GetItemInput{TableName: table, Key: documentKey(id)})
+	if err != nil {
+		return nil, err
+	}
+	if len(out.Item) == 0 {
+		return nil, fmt.Errorf("document %s not found in collection %s", id, collection)
+	}
+	return toDocument(out.Item)
+}
+
+func (d *dynamoDocuments) Put(collection, id string, content map[string]interface{}) error {
+	table, err := d.table(collection)
+	if err != nil {
+		return err
+	}
+
+	item, err := dynamodbattribute.MarshalMap(content)
+	if err != nil {
+		return err
+	}
+	for k, v := range documentKey(id) {
+		item[k] = v
+	}
+
+	_, err = d.client.PutItem(&dynamodb.PutItemInput{TableName: table, Item: item})
+	return err
+}
+
+func (d *dynamoDocuments) Delete(collection, id string) error {
+	table, err := d.table(collection)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.DeleteItem(&dynamodb.DeleteItemInput{TableName: table, Key: documentKey(id)})
+	return err
+}
+
+func (d *dynamoDocuments) Query(collection string, where map[string]interface{}, limit int) ([]*types.Document, error) {
+	table, err := d.table(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := []string{"#sk = :sk"}
+	names := map[string]*string{"#sk": aws.String("_sk")}
+	values := map[string]*dynamodb.AttributeValue{":sk": {S: aws.String(rootSortKey)}}
+	i := 0
+	for k, v := range where {
+		av, err := dynamodbattribute.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, fmt.Sprintf("#f%d = :v%d", i, i))
+		names[fmt.Sprintf("#f%d", i)] = aws.String(k)
+		values[fmt.Sprintf(":v%d", i)] = av
+		i++
+	}
+
+	docs := []*types.Document{}
+	var docErr error
+	err = d.client.ScanPages(&dynamodb.ScanInput{
+		TableName:                 table,
+		FilterExpression:          aws.String(strings.Join(filters, " AND ")),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}, func(out *dynamodb.ScanOutput, lastPage bool) bool {
+		for _, item := range out.Items {
+			doc, err := toDocument(item)
+			if err != nil {
+				docErr = err
+				return false
+			}
+			docs = append(docs, doc)
+			if limit > 0 && len(docs) >= limit {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return docs, docErr
+}
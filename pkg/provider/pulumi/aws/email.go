@@ -0,0 +1,114 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"encoding/json"
+
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/iam"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/ses"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+type SesIdentityArgs struct {
+	Email project.Email
+}
+
+type SesIdentity struct {
+	pulumi.ResourceState
+
+	Name   string
+	Sender string
+	Arn    pulumi.StringOutput
+}
+
+// newSesIdentity verifies the sender address, or its whole domain when one is set. SES sends a
+// verification email to the address, a domain is verified by adding the DKIM records to its DNS.
+func newSesIdentity(ctx *pulumi.Context, name string, args *SesIdentityArgs, opts ...pulumi.ResourceOption) (*SesIdentity, error) {
+	res := &SesIdentity{Name: name, Sender: args.Email.Sender}
+	err := ctx.RegisterComponentResource("nitric:email:SesIdentity", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	outputs := pulumi.Map{"name": pulumi.String(res.Name)}
+	if args.Email.Domain != "" {
+		domain, err := ses.NewDomainIdentity(ctx, name, &ses.DomainIdentityArgs{
+			Domain: pulumi.String(args.Email.Domain),
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		dkim, err := ses.NewDomainDkim(ctx, name, &ses.DomainDkimArgs{
+			Domain: domain.Domain,
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Arn = domain.Arn
+		outputs["dkimTokens"] = dkim.DkimTokens
+	} else {
+		identity, err := ses.NewEmailIdentity(ctx, name, &ses.EmailIdentityArgs{
+			Email: pulumi.String(args.Email.Sender),
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Arn = identity.Arn
+	}
+	outputs["identity"] = res.Arn
+
+	return res, ctx.RegisterResourceOutputs(res, outputs)
+}
+
+// emailEnv are the env vars used to send email.
+func emailEnv(name string, e project.Email) []types.EnvVar {
+	return []types.EnvVar{
+		{Name: project.EmailEnvPrefix(name) + "_SENDER", Value: e.Sender, Source: types.EnvSourceProvider},
+	}
+}
+
+// emailAccess allows a function to send email from the identity.
+func emailAccess(ctx *pulumi.Context, name string, role *iam.Role, identity *SesIdentity, opts ...pulumi.ResourceOption) error {
+	policy := identity.Arn.ApplyT(func(arn string) (string, error) {
+		b, err := json.Marshal(map[string]interface{}{
+			"Version": "2012-10-17",
+			"Statement": []map[string]interface{}{
+				{
+					"Action":   []string{"ses:SendEmail", "ses:SendRawEmail"},
+					"Effect":   "Allow",
+					"Resource": arn,
+				},
+			},
+		})
+		return string(b), err
+	}).(pulumi.StringOutput)
+
+	_, err := iam.NewRolePolicy(ctx, name, &iam.RolePolicyArgs{
+		Role:   role.ID(),
+		Policy: policy,
+	}, opts...)
+	return err
+}
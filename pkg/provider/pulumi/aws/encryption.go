@@ -0,0 +1,77 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"encoding/base64"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+var _ common.Encrypter = &awsProvider{}
+
+func (a *awsProvider) kmsClient() (*kms.KMS, error) {
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(a.sc.Region)})
+	if err != nil {
+		return nil, errors.WithMessage(err, "aws session")
+	}
+	return kms.New(sess), nil
+}
+
+// Encrypt encrypts the value with the KMS key of the stack's encryptionKey, a key id, ARN or alias.
+func (a *awsProvider) Encrypt(plaintext string) (string, error) {
+	client, err := a.kmsClient()
+	if err != nil {
+		return "", err
+	}
+
+	out, err := client.Encrypt(&kms.EncryptInput{
+		KeyId:     aws.String(a.sc.EncryptionKey),
+		Plaintext: []byte(plaintext),
+	})
+	if err != nil {
+		return "", errors.WithMessage(err, "kms encrypt")
+	}
+	return base64.StdEncoding.EncodeToString(out.CiphertextBlob), nil
+}
+
+// Decrypt decrypts a value encrypted by Encrypt, the ciphertext records the key it was encrypted with.
+func (a *awsProvider) Decrypt(ciphertext string) (string, error) {
+	blob, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", errors.WithMessage(err, "encrypted value")
+	}
+
+	client, err := a.kmsClient()
+	if err != nil {
+		return "", err
+	}
+
+	out, err := client.Decrypt(&kms.DecryptInput{
+		KeyId:          aws.String(a.sc.EncryptionKey),
+		CiphertextBlob: blob,
+	})
+	if err != nil {
+		return "", errors.WithMessage(err, "kms decrypt")
+	}
+	return string(out.Plaintext), nil
+}
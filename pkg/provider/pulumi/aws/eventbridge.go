@@ -0,0 +1,381 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/cloudwatch"
+	awslambda "github.com/pulumi/pulumi-aws/sdk/v4/go/aws/lambda"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/schemas"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/cron"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+// eventSource is the source of the events published to the stack's event bus, the topic is the detail-type.
+const eventSource = "nitric"
+
+type EventBusArgs struct {
+	EventBridge *stack.EventBridge
+	// The schemas of the topics' payloads, keyed by topic
+	Schemas map[string]*openapi3.Schema
+}
+
+type EventBus struct {
+	pulumi.ResourceState
+
+	Name     string
+	Bus      *cloudwatch.EventBus
+	Archive  *cloudwatch.EventArchive
+	Registry *schemas.Registry
+}
+
+// newEventBus creates the bus that carries the events of every topic when eventing is eventbridge.
+// It is named after the stack, so the runtime and the cli can find it.
+func newEventBus(ctx *pulumi.Context, name string, args *EventBusArgs, opts ...pulumi.ResourceOption) (*EventBus, error) {
+	res := &EventBus{Name: name}
+	err := ctx.RegisterComponentResource("nitric:topics:AwsEventBus", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	res.Bus, err = cloudwatch.NewEventBus(ctx, name, &cloudwatch.EventBusArgs{
+		Name: pulumi.StringPtr(name),
+		Tags: common.Tags(ctx, name),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.EventBridge != nil && args.EventBridge.Archive {
+		archiveArgs := &cloudwatch.EventArchiveArgs{
+			EventSourceArn: res.Bus.Arn,
+			Description:    pulumi.StringPtr("events of the topics of " + name),
+		}
+		if args.EventBridge.ArchiveRetentionDays > 0 {
+			archiveArgs.RetentionDays = pulumi.IntPtr(args.EventBridge.ArchiveRetentionDays)
+		}
+		res.Archive, err = cloudwatch.NewEventArchive(ctx, name+"Archive", archiveArgs, opts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(args.Schemas) > 0 {
+		res.Registry, err = schemas.NewRegistry(ctx, name+"Schemas", &schemas.RegistryArgs{
+			Name:        pulumi.StringPtr(name),
+			Description: pulumi.StringPtr("schemas of the topics of " + name),
+			Tags:        common.Tags(ctx, name+"Schemas"),
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		for topic, schema := range args.Schemas {
+			content, err := eventSchema(topic, schema)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "schema of topic %s", topic)
+			}
+			_, err = schemas.NewSchema(ctx, name+topic+"Schema", &schemas.SchemaArgs{
+				Name:         pulumi.StringPtr(eventSource + "@" + topic),
+				RegistryName: res.Registry.Name,
+				Type:         pulumi.String("JSONSchemaDraft4"),
+				Content:      pulumi.String(content),
+				Description:  pulumi.StringPtr("events of topic " + topic),
+				Tags:         common.Tags(ctx, name+topic+"Schema"),
+			}, opts...)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name": pulumi.String(res.Name),
+		"bus":  res.Bus,
+	})
+}
+
+// eventSchema is the JSON schema of the topic's events on the bus, the payload schema wrapped in the event envelope.
+func eventSchema(topic string, payload *openapi3.Schema) (string, error) {
+	b, err := json.Marshal(map[string]interface{}{
+		"$schema":  "http://json-schema.org/draft-04/schema#",
+		"type":     "object",
+		"required": []string{"source", "detail-type", "detail"},
+		"properties": map[string]interface{}{
+			"source":      map[string]interface{}{"type": "string", "enum": []string{eventSource}},
+			"detail-type": map[string]interface{}{"type": "string", "enum": []string{topic}},
+			"detail": map[string]interface{}{
+				"type":       "object",
+				"required":   []string{"payload"},
+				"properties": map[string]interface{}{"payload": payload},
+			},
+		},
+	})
+	return string(b), err
+}
+
+// topicPattern matches the events published to the topic, that also match the content filter.
+func topicPattern(topic string, filter map[string]interface{}) (string, error) {
+	pattern := map[string]interface{}{
+		"source":      []string{eventSource},
+		"detail-type": []string{topic},
+	}
+	if len(filter) > 0 {
+		pattern["detail"] = map[string]interface{}{"payload": jsonValue(filter)}
+	}
+	b, err := json.Marshal(pattern)
+	return string(b), err
+}
+
+// jsonValue converts the maps decoded from yaml, which have interface{} keys, so they can be encoded as JSON.
+func jsonValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, child := range t {
+			m[fmt.Sprint(k)] = jsonValue(child)
+		}
+		return m
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for k, child := range t {
+			m[k] = jsonValue(child)
+		}
+		return m
+	case []interface{}:
+		l := []interface{}{}
+		for _, child := range t {
+			l = append(l, jsonValue(child))
+		}
+		return l
+	default:
+		return v
+	}
+}
+
+// topicEvent is a nitric event in the shape of the events published to the bus.
+func topicEvent(topic string) (string, error) {
+	b, err := json.Marshal(map[string]interface{}{
+		"source":      eventSource,
+		"detail-type": topic,
+		"detail": map[string]interface{}{
+			"payload": map[string]interface{}{},
+		},
+	})
+	return string(b), err
+}
+
+// newTopicRule subscribes the function to the topic's events on the bus.
+func newTopicRule(ctx *pulumi.Context, name string, bus *EventBus, topic string, filter map[string]interface{}, fn *awslambda.Function, opts ...pulumi.ResourceOption) error {
+	pattern, err := topicPattern(topic, filter)
+	if err != nil {
+		return err
+	}
+
+	rule, err := cloudwatch.NewEventRule(ctx, name+"Rule", &cloudwatch.EventRuleArgs{
+		EventBusName: bus.Bus.Name,
+		EventPattern: pulumi.String(pattern),
+		Tags:         common.Tags(ctx, name+"Rule"),
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	_, err = awslambda.NewPermission(ctx, name+"Permission", &awslambda.PermissionArgs{
+		SourceArn: rule.Arn,
+		Function:  fn.Name,
+		Principal: pulumi.String("events.amazonaws.com"),
+		Action:    pulumi.String("lambda:InvokeFunction"),
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	_, err = cloudwatch.NewEventTarget(ctx, name+"Target", &cloudwatch.EventTargetArgs{
+		EventBusName: bus.Bus.Name,
+		Rule:         rule.Name,
+		Arn:          fn.Arn,
+	}, opts...)
+	return err
+}
+
+type BusScheduleArgs struct {
+	Expression string
+	Topic      string
+	// The functions subscribed to the topic
+	Subscribers map[string]*Lambda
+}
+
+// newBusSchedule invokes the topic's subscribers on the schedule. Scheduled rules only run on the
+// default bus, so the subscribers are invoked with the event the bus would have delivered.
+func (a *awsProvider) newBusSchedule(ctx *pulumi.Context, name string, args BusScheduleArgs, opts ...pulumi.ResourceOption) (*Schedule, error) {
+	res := &Schedule{Name: name}
+	err := ctx.RegisterComponentResource("nitric:schedule:AwsSchedule", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	awsCronValue, err := cron.ConvertToAWS(args.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := topicEvent(args.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	res.EventRule, err = cloudwatch.NewEventRule(ctx, name+"Schedule", &cloudwatch.EventRuleArgs{
+		ScheduleExpression: pulumi.String(awsCronValue),
+		Tags:               common.Tags(ctx, name+"Schedule"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for fnName, fn := range args.Subscribers {
+		_, err = awslambda.NewPermission(ctx, name+fnName+"Permission", &awslambda.PermissionArgs{
+			SourceArn: res.EventRule.Arn,
+			Function:  fn.Function.Name,
+			Principal: pulumi.String("events.amazonaws.com"),
+			Action:    pulumi.String("lambda:InvokeFunction"),
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = cloudwatch.NewEventTarget(ctx, name+fnName+"Target", &cloudwatch.EventTargetArgs{
+			Rule:  res.EventRule.Name,
+			Arn:   fn.Function.Arn,
+			Input: pulumi.StringPtr(event),
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// eventBusEnv tells the runtime to publish to the stack's event bus instead of sns topics.
+func eventBusEnv(bus *EventBus) []types.EnvVar {
+	if bus == nil {
+		return nil
+	}
+	return []types.EnvVar{{Name: "NITRIC_EVENT_BUS", Value: bus.Name, Source: types.EnvSourceProvider}}
+}
+
+// eventBridge is true when the stack's topics are carried by an event bus.
+func (a *awsProvider) eventBridge() bool {
+	return a.sc.Eventing == stack.EventingEventBridge
+}
+
+func (a *awsProvider) validateEventing() error {
+	switch a.sc.Eventing {
+	case "", stack.EventingSns, stack.EventingEventBridge:
+	default:
+		return fmt.Errorf("eventing %s is not supported on %s, use %s or %s", a.sc.Eventing, a.sc.Provider, stack.EventingSns, stack.EventingEventBridge)
+	}
+	if a.sc.EventBridge == nil {
+		return nil
+	}
+	if !a.eventBridge() {
+		return fmt.Errorf("eventBridge is configured, but eventing is not %s", stack.EventingEventBridge)
+	}
+	if a.sc.EventBridge.ArchiveRetentionDays < 0 {
+		return fmt.Errorf("eventBridge archiveRetentionDays must be 0 or more")
+	}
+	return nil
+}
+
+// validateEventFilters checks each filter is on a topic the function subscribes to, the subscriptions
+// are gathered from code so this is checked when deploying.
+func (a *awsProvider) validateEventFilters() error {
+	if a.sc.EventBridge == nil {
+		return nil
+	}
+	for fn, topics := range a.sc.EventBridge.Filters {
+		subscribed := map[string]bool{}
+		found := false
+		for _, c := range a.proj.Computes() {
+			if c.Unit().Name == fn {
+				found = true
+				for _, t := range c.Unit().Triggers.Topics {
+					subscribed[t] = true
+				}
+			}
+		}
+		if !found {
+			return fmt.Errorf("eventBridge filter configured for function %s, but the function does not exist", fn)
+		}
+		for t := range topics {
+			if !subscribed[t] {
+				return fmt.Errorf("eventBridge filter configured for function %s on topic %s, but the function does not subscribe to it", fn, t)
+			}
+		}
+	}
+	return nil
+}
+
+// eventFilters are the content filters of the function's subscriptions, keyed by topic.
+// When schemas are enforced the fields required by the topic's schema must exist, unless they are filtered on.
+func (a *awsProvider) eventFilters(fn string, topics []string) (map[string]map[string]interface{}, error) {
+	if a.sc.EventBridge == nil {
+		return nil, nil
+	}
+	if !a.sc.EventBridge.EnforceSchemas {
+		return a.sc.EventBridge.Filters[fn], nil
+	}
+
+	filters := map[string]map[string]interface{}{}
+	for _, topic := range topics {
+		schema, err := a.proj.TopicSchema(topic)
+		if err != nil {
+			return nil, err
+		}
+		filter := schemaFilter(schema, a.sc.EventBridge.Filters[fn][topic])
+		if len(filter) > 0 {
+			filters[topic] = filter
+		}
+	}
+	return filters, nil
+}
+
+// schemaFilter adds an exists filter for each field the schema requires to the configured filter.
+func schemaFilter(schema *openapi3.Schema, filter map[string]interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+	for _, field := range project.RequiredFields(schema) {
+		res[field] = []interface{}{map[string]interface{}{"exists": true}}
+	}
+	for k, v := range filter {
+		res[k] = v
+	}
+	return res
+}
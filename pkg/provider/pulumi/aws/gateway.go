@@ -17,21 +17,45 @@
 package aws
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/apigatewayv2"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/cloudwatch"
 	awslambda "github.com/pulumi/pulumi-aws/sdk/v4/go/aws/lambda"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
 	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
 )
 
 type ApiGatewayArgs struct {
 	OpenAPISpec     *openapi3.T
 	LambdaFunctions map[string]*Lambda
+	// Limits throttle the routes of the default stage
+	Limits *common.ApiLimits
+	// Logs write the access logs of the default stage, nil when it has none
+	Logs *stack.ApiLogs
+	// Timeout is the seconds the integrations wait for the functions, the gateway's default when 0
+	Timeout int
 }
 
+// defaultAccessLogFormat logs each request with its status, latencies and the errors of the gateway and
+// the function, HTTP APIs have no execution logs to find them in.
+const defaultAccessLogFormat = `{"requestId":"$context.requestId","ip":"$context.identity.sourceIp",` +
+	`"requestTime":"$context.requestTime","httpMethod":"$context.httpMethod","routeKey":"$context.routeKey",` +
+	`"status":"$context.status","protocol":"$context.protocol","responseLength":"$context.responseLength",` +
+	`"responseLatency":"$context.responseLatency","integrationStatus":"$context.integrationStatus",` +
+	`"integrationLatency":"$context.integrationLatency","error":"$context.error.message",` +
+	`"integrationError":"$context.integrationErrorMessage"}`
+
+// logRetentionDays are the retentions a CloudWatch log group can have.
+var logRetentionDays = []int{1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1827, 3653}
+
 type ApiGateway struct {
 	pulumi.ResourceState
 
@@ -79,12 +103,12 @@ func newApiGateway(ctx *pulumi.Context, name string, args *ApiGatewayArgs, opts
 		}
 
 		for k, p := range args.OpenAPISpec.Paths {
-			p.Get = awsOperation(p.Get, naps)
-			p.Post = awsOperation(p.Post, naps)
-			p.Patch = awsOperation(p.Patch, naps)
-			p.Put = awsOperation(p.Put, naps)
-			p.Delete = awsOperation(p.Delete, naps)
-			p.Options = awsOperation(p.Options, naps)
+			p.Get = awsOperation(p.Get, naps, args.Timeout)
+			p.Post = awsOperation(p.Post, naps, args.Timeout)
+			p.Patch = awsOperation(p.Patch, naps, args.Timeout)
+			p.Put = awsOperation(p.Put, naps, args.Timeout)
+			p.Delete = awsOperation(p.Delete, naps, args.Timeout)
+			p.Options = awsOperation(p.Options, naps, args.Timeout)
 			args.OpenAPISpec.Paths[k] = p
 		}
 
@@ -109,12 +133,58 @@ func newApiGateway(ctx *pulumi.Context, name string, args *ApiGatewayArgs, opts
 		return nil, err
 	}
 
-	_, err = apigatewayv2.NewStage(ctx, name+"DefaultStage", &apigatewayv2.StageArgs{
+	stageArgs := &apigatewayv2.StageArgs{
 		AutoDeploy: pulumi.BoolPtr(true),
 		Name:       pulumi.String("$default"),
 		ApiId:      res.Api.ID(),
 		Tags:       common.Tags(ctx, name+"DefaultStage"),
-	}, opts...)
+	}
+	defaultRoute := apigatewayv2.StageDefaultRouteSettingsArgs{}
+	hasDefaultRoute := false
+	if args.Logs != nil {
+		logGroup, err := cloudwatch.NewLogGroup(ctx, name+"AccessLogs", &cloudwatch.LogGroupArgs{
+			RetentionInDays: pulumi.Int(args.Logs.RetentionDaysOrDefault()),
+			Tags:            common.Tags(ctx, name+"AccessLogs"),
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+		stageArgs.AccessLogSettings = apigatewayv2.StageAccessLogSettingsArgs{
+			DestinationArn: logGroup.Arn,
+			Format:         pulumi.String(accessLogFormat(args.Logs)),
+		}
+		if args.Logs.DetailedMetrics {
+			defaultRoute.DetailedMetricsEnabled = pulumi.Bool(true)
+			hasDefaultRoute = true
+		}
+	}
+	if args.Limits != nil {
+		if l := args.Limits.Default; l != nil && l.Rate > 0 {
+			defaultRoute.ThrottlingBurstLimit = pulumi.Int(burstLimit(*l))
+			defaultRoute.ThrottlingRateLimit = pulumi.Float64(l.Rate)
+			hasDefaultRoute = true
+		}
+		routes := apigatewayv2.StageRouteSettingArray{}
+		for _, k := range args.Limits.RouteKeys() {
+			l := args.Limits.Routes[k]
+			if l.Rate == 0 {
+				continue
+			}
+			routes = append(routes, apigatewayv2.StageRouteSettingArgs{
+				RouteKey:             pulumi.String(k),
+				ThrottlingBurstLimit: pulumi.Int(burstLimit(l)),
+				ThrottlingRateLimit:  pulumi.Float64(l.Rate),
+			})
+		}
+		if len(routes) > 0 {
+			stageArgs.RouteSettings = routes
+		}
+	}
+	if hasDefaultRoute {
+		stageArgs.DefaultRouteSettings = defaultRoute
+	}
+
+	_, err = apigatewayv2.NewStage(ctx, name+"DefaultStage", stageArgs, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -141,7 +211,7 @@ func newApiGateway(ctx *pulumi.Context, name string, args *ApiGatewayArgs, opts
 	return res, nil
 }
 
-func awsOperation(op *openapi3.Operation, funcs map[string]string) *openapi3.Operation {
+func awsOperation(op *openapi3.Operation, funcs map[string]string, timeout int) *openapi3.Operation {
 	if op == nil {
 		return nil
 	}
@@ -160,7 +230,7 @@ func awsOperation(op *openapi3.Operation, funcs map[string]string) *openapi3.Ope
 	}
 
 	arn := funcs[name]
-	op.Extensions["x-amazon-apigateway-integration"] = map[string]string{
+	integration := map[string]interface{}{
 		"type":                 "aws_proxy",
 		"httpMethod":           "POST",
 		"payloadFormatVersion": "2.0",
@@ -168,5 +238,69 @@ func awsOperation(op *openapi3.Operation, funcs map[string]string) *openapi3.Ope
 		// Need to determine if the body of the..
 		"uri": arn,
 	}
+	if timeout > 0 {
+		integration["timeoutInMillis"] = timeout * 1000
+	}
+	op.Extensions["x-amazon-apigateway-integration"] = integration
 	return op
 }
+
+// burstLimit is the burst of the limit, defaulting to its rate so requests at the rate are not throttled.
+func burstLimit(l stack.RateLimit) int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return int(math.Ceil(l.Rate))
+}
+
+// accessLogFormat returns the format of the access logs, the default when the stack has none.
+func accessLogFormat(l *stack.ApiLogs) string {
+	if l.Format == "" {
+		return defaultAccessLogFormat
+	}
+	return l.Format
+}
+
+// validateApiLogs checks the access log formats are JSON that identify each request, and that the
+// retentions are ones CloudWatch supports.
+func (a *awsProvider) validateApiLogs() error {
+	apis := make([]string, 0, len(a.sc.ApiLogs))
+	for api := range a.sc.ApiLogs {
+		apis = append(apis, api)
+	}
+	sort.Strings(apis)
+
+	for _, api := range apis {
+		l := a.sc.ApiLogs[api]
+		if l.Format != "" {
+			if !json.Valid([]byte(l.Format)) {
+				return fmt.Errorf("the access log format of api %s is not valid JSON", api)
+			}
+			if !strings.Contains(l.Format, "$context.requestId") && !strings.Contains(l.Format, "$context.extendedRequestId") {
+				return fmt.Errorf("the access log format of api %s must include $context.requestId", api)
+			}
+		}
+		if l.RetentionDays != 0 && !validLogRetention(l.RetentionDays) {
+			return fmt.Errorf("the access logs of api %s can't be kept %d days, it should be one of %v", api, l.RetentionDays, logRetentionDays)
+		}
+	}
+	return nil
+}
+
+func validLogRetention(days int) bool {
+	for _, d := range logRetentionDays {
+		if d == days {
+			return true
+		}
+	}
+	return false
+}
+
+// apiLogs returns the stack's access logs of the api, nil when it has none.
+func apiLogs(sc *stack.Config, api string) *stack.ApiLogs {
+	l, ok := sc.ApiLogs[api]
+	if !ok {
+		return nil
+	}
+	return &l
+}
@@ -0,0 +1,62 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+// healthCheckPayload is answered by the membrane without calling the function's handlers.
+const healthCheckPayload = `{"x-nitric-healthcheck": true}`
+
+var _ common.HealthChecker = &awsProvider{}
+
+// Health invokes each lambda with a health check, which fails until the membrane is serving.
+func (a *awsProvider) Health(ids map[string]string) ([]types.FunctionHealth, error) {
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(a.sc.Region)})
+	if err != nil {
+		return nil, errors.WithMessage(err, "aws session")
+	}
+	client := lambda.New(sess)
+
+	return common.CheckHealth(ids, func(name, id string) types.FunctionHealth {
+		out, err := client.Invoke(&lambda.InvokeInput{
+			FunctionName: aws.String(id),
+			Payload:      []byte(healthCheckPayload),
+		})
+		if err != nil {
+			return types.FunctionHealth{Function: name, Reason: err.Error()}
+		}
+		return lambdaHealth(name, out)
+	}), nil
+}
+
+// lambdaHealth is healthy when the invocation returned without a function error.
+func lambdaHealth(name string, out *lambda.InvokeOutput) types.FunctionHealth {
+	if out.FunctionError != nil {
+		return types.FunctionHealth{Function: name, Reason: fmt.Sprintf("%s: %s", aws.StringValue(out.FunctionError), out.Payload)}
+	}
+	return types.FunctionHealth{Function: name, Healthy: true}
+}
@@ -0,0 +1,173 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+var _ common.Bootstrapper = &awsProvider{}
+
+// deployerActions are the actions used to create, update and destroy the resources of a stack.
+var deployerActions = []string{
+	"acm:DescribeCertificate",
+	"apigateway:*",
+	"application-autoscaling:*",
+	"backup:*",
+	"backup-storage:MountCapsule",
+	"cloudfront:*",
+	"cloudwatch:*",
+	"dynamodb:*",
+	"ec2:Describe*",
+	"ec2:CreateSecurityGroup",
+	"ec2:DeleteSecurityGroup",
+	"ec2:AuthorizeSecurityGroupIngress",
+	"ec2:AuthorizeSecurityGroupEgress",
+	"ec2:RevokeSecurityGroupIngress",
+	"ec2:RevokeSecurityGroupEgress",
+	"ec2:CreateTags",
+	"ec2:DeleteTags",
+	"ecr:*",
+	"ecs:*",
+	"elasticache:*",
+	"events:*",
+	"iam:CreateRole",
+	"iam:DeleteRole",
+	"iam:GetRole",
+	"iam:TagRole",
+	"iam:UntagRole",
+	"iam:UpdateAssumeRolePolicy",
+	"iam:ListRolePolicies",
+	"iam:ListAttachedRolePolicies",
+	"iam:ListInstanceProfilesForRole",
+	"iam:PutRolePolicy",
+	"iam:GetRolePolicy",
+	"iam:DeleteRolePolicy",
+	"iam:AttachRolePolicy",
+	"iam:DetachRolePolicy",
+	"iam:PassRole",
+	"iam:CreateServiceLinkedRole",
+	"kms:CreateGrant",
+	"kms:Decrypt",
+	"kms:DescribeKey",
+	"lambda:*",
+	"logs:*",
+	"rds:*",
+	"resource-groups:*",
+	"schemas:*",
+	"secretsmanager:*",
+	"ses:*",
+	"sns:*",
+	"sqs:*",
+	"states:*",
+	"s3:*",
+	"sts:GetCallerIdentity",
+	"tag:GetResources",
+}
+
+// DeployerRole returns an IAM role whose inline policy allows the actions that deploy a stack.
+func (a *awsProvider) DeployerRole(name string) (*types.DeployerRole, error) {
+	policy, err := json.MarshalIndent(map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{
+			{
+				"Effect":   "Allow",
+				"Action":   deployerActions,
+				"Resource": "*",
+			},
+		},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.DeployerRole{
+		Name:   name,
+		Policy: string(policy),
+		Instructions: []string{
+			"save the policy as policy.json and a trust policy for the accounts or users that deploy as trust.json",
+			fmt.Sprintf("aws iam create-role --role-name %s --assume-role-policy-document file://trust.json", name),
+			fmt.Sprintf("aws iam put-role-policy --role-name %s --policy-name %s --policy-document file://policy.json", name, name),
+		},
+	}, nil
+}
+
+// CreateDeployerRole creates the role, trusted by the account of the current credentials.
+func (a *awsProvider) CreateDeployerRole(role *types.DeployerRole) error {
+	// IAM is global, the region only chooses the STS endpoint
+	region := a.sc.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(region)})
+	if err != nil {
+		return errors.WithMessage(err, "aws session")
+	}
+
+	identity, err := sts.New(sess).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return errors.WithMessage(err, "get caller identity")
+	}
+
+	trust, err := json.Marshal(map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{
+			{
+				"Effect": "Allow",
+				"Principal": map[string]interface{}{
+					"AWS": "arn:aws:iam::" + aws.StringValue(identity.Account) + ":root",
+				},
+				"Action": "sts:AssumeRole",
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	client := awsiam.New(sess)
+	out, err := client.CreateRole(&awsiam.CreateRoleInput{
+		RoleName:                 aws.String(role.Name),
+		AssumeRolePolicyDocument: aws.String(string(trust)),
+		Description:              aws.String("deploys the stacks of " + a.proj.Name),
+	})
+	if err != nil {
+		return errors.WithMessage(err, "create role "+role.Name)
+	}
+
+	_, err = client.PutRolePolicy(&awsiam.PutRolePolicyInput{
+		RoleName:       aws.String(role.Name),
+		PolicyName:     aws.String(role.Name),
+		PolicyDocument: aws.String(role.Policy),
+	})
+	if err != nil {
+		return errors.WithMessage(err, "put role policy "+role.Name)
+	}
+
+	role.ID = aws.StringValue(out.Role.Arn)
+	return nil
+}
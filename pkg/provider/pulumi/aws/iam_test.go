@@ -0,0 +1,79 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func TestDeployerRole(t *testing.T) {
+	a := New(&project.Project{Name: "shop"}, &stack.Config{Name: "deployer", Provider: stack.Aws}, map[string]string{}).(*awsProvider)
+
+	role, err := a.DeployerRole("shop-deployer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy := struct {
+		Statement []struct {
+			Effect   string
+			Action   []string
+			Resource string
+		}
+	}{}
+	if err := json.Unmarshal([]byte(role.Policy), &policy); err != nil {
+		t.Fatalf("policy is not json: %v", err)
+	}
+	if len(policy.Statement) != 1 || policy.Statement[0].Effect != "Allow" || policy.Statement[0].Resource != "*" {
+		t.Fatalf("policy = %s, want one statement allowing the deployer actions", role.Policy)
+	}
+
+	actions := map[string]bool{}
+	for _, action := range policy.Statement[0].Action {
+		actions[action] = true
+	}
+	tests := []struct {
+		action string
+		want   bool
+	}{
+		{action: "lambda:*", want: true},
+		{action: "iam:PassRole", want: true},
+		{action: "kms:Decrypt", want: true},
+		{action: "sts:GetCallerIdentity", want: true},
+		{action: "*", want: false},
+		{action: "iam:*", want: false},
+		{action: "iam:CreateUser", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			if actions[tt.action] != tt.want {
+				t.Errorf("policy allows %s = %v, want %v", tt.action, actions[tt.action], tt.want)
+			}
+		})
+	}
+
+	for _, i := range role.Instructions[1:] {
+		if !strings.Contains(i, "--role-name shop-deployer") {
+			t.Errorf("instruction %q does not name the role", i)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/cloudwatch"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/ec2"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/iam"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/sfn"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/sqs"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/cron"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+type FargateJobArgs struct {
+	FargateServiceArgs
+	// Schedules start a run, keyed by name
+	Schedules map[string]project.Schedule
+	// Queue starts a run when it has messages waiting
+	Queue *sqs.Queue
+}
+
+type FargateJob struct {
+	pulumi.ResourceState
+
+	Name         string
+	Role         *iam.Role
+	StateMachine *sfn.StateMachine
+}
+
+// jobQueueRate is how often the queue of a job is checked for waiting messages.
+const jobQueueRate = "rate(1 minute)"
+
+// newFargateJob runs a job's container to completion on Fargate with a state machine, which stops the task when it
+// times out and retries failed runs. EventBridge rules start the state machine on the job's schedules and to check its queue.
+func newFargateJob(ctx *pulumi.Context, name string, args *FargateJobArgs, opts ...pulumi.ResourceOption) (*FargateJob, error) {
+	res := &FargateJob{Name: name}
+	err := ctx.RegisterComponentResource("nitric:job:AWSFargate", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	task, err := newFargateTask(ctx, name, &args.FargateServiceArgs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	res.Role = task.Role
+
+	isDefault := true
+	vpc, err := ec2.LookupVpc(ctx, &ec2.LookupVpcArgs{Default: &isDefault})
+	if err != nil {
+		return nil, err
+	}
+	subnets, err := ec2.GetSubnetIds(ctx, &ec2.GetSubnetIdsArgs{VpcId: vpc.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	arns := pulumi.StringMap{
+		"cluster":       args.Cluster.Arn,
+		"task":          task.Definition.Arn,
+		"taskRole":      task.Role.Arn,
+		"executionRole": task.ExecutionRole.Arn,
+	}
+	if args.Queue != nil {
+		arns["queue"] = args.Queue.Arn
+		arns["queueUrl"] = args.Queue.Url
+	}
+
+	stateMachineRole, err := iam.NewRole(ctx, name+"JobRole", &iam.RoleArgs{
+		AssumeRolePolicy: pulumi.String(assumeRolePolicy("states.amazonaws.com")),
+		Tags:             common.Tags(ctx, name+"JobRole"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = iam.NewRolePolicy(ctx, name+"RunTask", &iam.RolePolicyArgs{
+		Role: stateMachineRole.ID(),
+		Policy: arns.ToStringMapOutput().ApplyT(func(arns map[string]string) (string, error) {
+			statements := []map[string]interface{}{
+				{
+					"Action":   []string{"ecs:RunTask"},
+					"Effect":   "Allow",
+					"Resource": arns["task"],
+				},
+				{
+					"Action":   []string{"ecs:StopTask", "ecs:DescribeTasks"},
+					"Effect":   "Allow",
+					"Resource": "*",
+				},
+				{
+					"Action":   []string{"iam:PassRole"},
+					"Effect":   "Allow",
+					"Resource": []string{arns["taskRole"], arns["executionRole"]},
+				},
+				{
+					// runTask.sync waits for the task to stop with a managed rule
+					"Action":   []string{"events:PutTargets", "events:PutRule", "events:DescribeRule"},
+					"Effect":   "Allow",
+					"Resource": fmt.Sprintf("arn:aws:events:%s:*:rule/StepFunctionsGetEventsForECSTaskRule", args.Region),
+				},
+			}
+			if arns["queue"] != "" {
+				statements = append(statements, map[string]interface{}{
+					"Action":   []string{"sqs:GetQueueAttributes"},
+					"Effect":   "Allow",
+					"Resource": arns["queue"],
+				})
+			}
+			b, err := json.Marshal(map[string]interface{}{
+				"Version":   "2012-10-17",
+				"Statement": statements,
+			})
+			return string(b), err
+		}).(pulumi.StringOutput),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res.StateMachine, err = sfn.NewStateMachine(ctx, name, &sfn.StateMachineArgs{
+		RoleArn: stateMachineRole.Arn,
+		Definition: arns.ToStringMapOutput().ApplyT(func(arns map[string]string) (string, error) {
+			return jobDefinition(args.Compute.Unit().Job, arns, subnets.Ids)
+		}).(pulumi.StringOutput),
+		Tags: common.Tags(ctx, name),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	eventsRole, err := iam.NewRole(ctx, name+"StartRole", &iam.RoleArgs{
+		AssumeRolePolicy: pulumi.String(assumeRolePolicy("events.amazonaws.com")),
+		Tags:             common.Tags(ctx, name+"StartRole"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = iam.NewRolePolicy(ctx, name+"StartExecution", &iam.RolePolicyArgs{
+		Role: eventsRole.ID(),
+		Policy: res.StateMachine.Arn.ApplyT(func(arn string) (string, error) {
+			b, err := json.Marshal(map[string]interface{}{
+				"Version": "2012-10-17",
+				"Statement": []map[string]interface{}{
+					{
+						"Action":   []string{"states:StartExecution"},
+						"Effect":   "Allow",
+						"Resource": arn,
+					},
+				},
+			})
+			return string(b), err
+		}).(pulumi.StringOutput),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	starts := map[string]string{}
+	for k, sched := range args.Schedules {
+		expression, err := cron.ConvertToAWS(sched.Expression)
+		if err != nil {
+			return nil, err
+		}
+		starts[project.ScheduleTopic(k)] = expression
+	}
+	if args.Queue != nil {
+		starts["queue"] = jobQueueRate
+	}
+
+	keys := []string{}
+	for k := range starts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		rule, err := cloudwatch.NewEventRule(ctx, name+"-"+k, &cloudwatch.EventRuleArgs{
+			ScheduleExpression: pulumi.String(starts[k]),
+			Tags:               common.Tags(ctx, name+"-"+k),
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = cloudwatch.NewEventTarget(ctx, name+"-"+k+"Target", &cloudwatch.EventTargetArgs{
+			Rule:    rule.Name,
+			Arn:     res.StateMachine.Arn,
+			RoleArn: eventsRole.Arn,
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":         pulumi.String(res.Name),
+		"stateMachine": res.StateMachine,
+	})
+}
+
+// assumeRolePolicy allows the service to assume a role.
+func assumeRolePolicy(service string) string {
+	b, _ := json.Marshal(map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{
+			{
+				"Effect": "Allow",
+				"Principal": map[string]interface{}{
+					"Service": service,
+				},
+				"Action": "sts:AssumeRole",
+			},
+		},
+	})
+	return string(b)
+}
+
+// jobDefinition renders a state machine that runs the job's task until it stops, failing the run when the task
+// exits with an error or the timeout passes. Jobs started by a queue first check there are messages waiting.
+func jobDefinition(job *project.Job, arns map[string]string, subnets []string) (string, error) {
+	run := map[string]interface{}{
+		"Type":     "Task",
+		"Resource": "arn:aws:states:::ecs:runTask.sync",
+		"Parameters": map[string]interface{}{
+			"LaunchType":     "FARGATE",
+			"Cluster":        arns["cluster"],
+			"TaskDefinition": arns["task"],
+			"NetworkConfiguration": map[string]interface{}{
+				"AwsvpcConfiguration": map[string]interface{}{
+					"Subnets": subnets,
+					// the default VPC has no NAT gateway, the public IP is used to pull the image
+					"AssignPublicIp": "ENABLED",
+				},
+			},
+		},
+		"TimeoutSeconds": int(job.TimeoutOrDefault().Seconds()),
+		"End":            true,
+	}
+	if job.Retries > 0 {
+		run["Retry"] = []map[string]interface{}{
+			{
+				"ErrorEquals":     []string{"States.ALL"},
+				"MaxAttempts":     job.Retries,
+				"IntervalSeconds": 30,
+				"BackoffRate":     2,
+			},
+		}
+	}
+
+	states := map[string]interface{}{"Run": run}
+	startAt := "Run"
+	if arns["queueUrl"] != "" {
+		startAt = "CheckQueue"
+		states["CheckQueue"] = map[string]interface{}{
+			"Type":     "Task",
+			"Resource": "arn:aws:states:::aws-sdk:sqs:getQueueAttributes",
+			"Parameters": map[string]interface{}{
+				"QueueUrl":       arns["queueUrl"],
+				"AttributeNames": []string{"ApproximateNumberOfMessages"},
+			},
+			"ResultSelector": map[string]interface{}{
+				"messages.$": "States.StringToJson($.Attributes.ApproximateNumberOfMessages)",
+			},
+			"Next": "HasMessages",
+		}
+		states["HasMessages"] = map[string]interface{}{
+			"Type": "Choice",
+			"Choices": []map[string]interface{}{
+				{"Variable": "$.messages", "NumericGreaterThan": 0, "Next": "Run"},
+			},
+			"Default": "NoMessages",
+		}
+		states["NoMessages"] = map[string]interface{}{"Type": "Succeed"}
+	}
+
+	b, err := json.Marshal(map[string]interface{}{
+		"StartAt": startAt,
+		"States":  states,
+	})
+	return string(b), err
+}
@@ -0,0 +1,87 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/cloudwatch"
+	awslambda "github.com/pulumi/pulumi-aws/sdk/v4/go/aws/lambda"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+type KeepWarmArgs struct {
+	Minutes int
+	Lambda  *Lambda
+}
+
+type KeepWarm struct {
+	pulumi.ResourceState
+
+	Name        string
+	EventRule   *cloudwatch.EventRule
+	EventTarget *cloudwatch.EventTarget
+}
+
+// newKeepWarm invokes the lambda on a schedule so an instance is kept warm.
+func newKeepWarm(ctx *pulumi.Context, name string, args *KeepWarmArgs, opts ...pulumi.ResourceOption) (*KeepWarm, error) {
+	res := &KeepWarm{Name: name}
+	err := ctx.RegisterComponentResource("nitric:func:AwsKeepWarm", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	rate := fmt.Sprintf("rate(%d minutes)", args.Minutes)
+	if args.Minutes == 1 {
+		rate = "rate(1 minute)"
+	}
+
+	res.EventRule, err = cloudwatch.NewEventRule(ctx, name+"Rule", &cloudwatch.EventRuleArgs{
+		ScheduleExpression: pulumi.String(rate),
+		Tags:               common.Tags(ctx, name+"Rule"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = awslambda.NewPermission(ctx, name+"Permission", &awslambda.PermissionArgs{
+		Action:    pulumi.String("lambda:InvokeFunction"),
+		Function:  args.Lambda.Function.Name,
+		Principal: pulumi.String("events.amazonaws.com"),
+		SourceArn: res.EventRule.Arn,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res.EventTarget, err = cloudwatch.NewEventTarget(ctx, name+"Target", &cloudwatch.EventTargetArgs{
+		Rule:  res.EventRule.Name,
+		Arn:   args.Lambda.Function.Arn,
+		Input: pulumi.String(`{"x-nitric-keep-warm":true}`),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name": pulumi.String(res.Name),
+	})
+}
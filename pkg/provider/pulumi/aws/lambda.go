@@ -28,14 +28,54 @@ import (
 
 	"github.com/nitrictech/cli/pkg/project"
 	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/stack"
 )
 
 type LambdaArgs struct {
-	StackName   string
-	Topics      map[string]*sns.Topic
-	DockerImage *docker.Image
-	Compute     project.Compute
-	EnvMap      map[string]string
+	StackName string
+	Topics    map[string]*sns.Topic
+	// EventBus carries the topics instead of Topics when eventing is eventbridge
+	EventBus *EventBus
+	// EventFilters are the content filters of the subscriptions on the bus, keyed by topic
+	EventFilters map[string]map[string]interface{}
+	Databases    map[string]*AuroraDatabase
+	Emails       map[string]*SesIdentity
+	DockerImage  *docker.Image
+	Compute      project.Compute
+	EnvMap       map[string]string
+	// Observability runs the collector layer in the image, see layerInstructions
+	Observability *stack.Observability
+	// Subscriptions are the delivery settings of the topics, keyed by topic
+	Subscriptions map[string]stack.Subscription
+	// ReservedConcurrency limits the instances of the function, it is not limited when 0
+	ReservedConcurrency int
+}
+
+// The ephemeral storage of a lambda is between 512MB, the default, and 10GB.
+const (
+	minLambdaTmpSize = 512
+	maxLambdaTmpSize = 10240
+)
+
+// validateTmpSizes checks the tmp size of each compute unit fits in the lambda or fargate task it is deployed to.
+func (a *awsProvider) validateTmpSizes() error {
+	for _, c := range a.proj.Computes() {
+		u := c.Unit()
+		if u.TmpSize == 0 {
+			continue
+		}
+		if u.AlwaysOn || u.Job != nil {
+			if u.TmpSize > maxFargateStorage*1024 {
+				return fmt.Errorf("the tmpSize of %s is more than the %dGiB a fargate task can have", u.Name, maxFargateStorage)
+			}
+			continue
+		}
+		if u.TmpSize < minLambdaTmpSize || u.TmpSize > maxLambdaTmpSize {
+			return fmt.Errorf("the tmpSize of %s must be between %d and %d MB on lambda", u.Name, minLambdaTmpSize, maxLambdaTmpSize)
+		}
+	}
+	return nil
 }
 
 type Lambda struct {
@@ -122,51 +162,93 @@ func newLambda(ctx *pulumi.Context, name string, args *LambdaArgs, opts ...pulum
 		return nil, err
 	}
 
-	envVars := pulumi.StringMap{
-		"NITRIC_STACK": pulumi.String(args.StackName),
-		"MIN_WORKERS":  pulumi.String(fmt.Sprint(args.Compute.Workers())),
+	envVars := pulumi.StringMap{}
+	env := append(lambdaEnv(args.StackName, args.Compute), observabilityEnv(args.Observability, name, args.StackName)...)
+	env = append(env, eventBusEnv(args.EventBus)...)
+	env = append(env, common.UnitEnv(args.Compute)...)
+	for _, e := range common.MergeEnv(env, args.EnvMap) {
+		envVars[e.Name] = pulumi.String(e.Value)
+	}
+
+	for k, db := range args.Databases {
+		prefix := project.DatabaseEnvPrefix(k)
+		envVars[prefix+"_CLUSTER_ARN"] = db.Cluster.Arn
+		envVars[prefix+"_SECRET_ARN"] = db.Secret.Arn
+		envVars[prefix+"_NAME"] = pulumi.String(project.DatabaseName(k))
+
+		if err := databaseAccess(ctx, name+k+"DatabaseAccess", res.Role, db, opts...); err != nil {
+			return nil, err
+		}
 	}
-	for k, v := range args.EnvMap {
-		envVars[k] = pulumi.String(v)
+
+	for k, e := range args.Emails {
+		envVars[project.EmailEnvPrefix(k)+"_SENDER"] = pulumi.String(e.Sender)
+		if err := emailAccess(ctx, name+k+"EmailAccess", res.Role, e, opts...); err != nil {
+			return nil, err
+		}
 	}
 
-	memory := common.IntValueOrDefault(args.Compute.Unit().Memory, 128)
-	res.Function, err = awslambda.NewFunction(ctx, name, &awslambda.FunctionArgs{
+	memory := args.Compute.Unit().MemoryOrDefault(128)
+	functionArgs := &awslambda.FunctionArgs{
 		ImageUri:    args.DockerImage.ImageName,
 		MemorySize:  pulumi.IntPtr(memory),
-		Timeout:     pulumi.IntPtr(15),
+		Timeout:     pulumi.IntPtr(lambdaTimeout),
 		PackageType: pulumi.String("Image"),
 		Role:        res.Role.Arn,
 		Tags:        common.Tags(ctx, name),
 		Environment: awslambda.FunctionEnvironmentArgs{Variables: envVars},
-	}, opts...)
+	}
+	if args.ReservedConcurrency > 0 {
+		functionArgs.ReservedConcurrentExecutions = pulumi.IntPtr(args.ReservedConcurrency)
+	}
+	if tmpSize := args.Compute.Unit().TmpSize; tmpSize > 0 {
+		functionArgs.EphemeralStorage = awslambda.FunctionEphemeralStorageArgs{Size: pulumi.Int(tmpSize)}
+	}
+	res.Function, err = awslambda.NewFunction(ctx, name, functionArgs, opts...)
 	if err != nil {
 		return nil, err
 	}
+	// nitric stack update --verify invokes the function with its name
+	ctx.Export("function:"+name, res.Function.Name)
 
+	// the triggers are checked by ValidateTriggers before deploying
 	for _, t := range args.Compute.Unit().Triggers.Topics {
-		topic, ok := args.Topics[t]
-		if ok {
-			_, err = awslambda.NewPermission(ctx, name+t+"Permission", &awslambda.PermissionArgs{
-				SourceArn: topic.Arn,
-				Function:  res.Function.Name,
-				Principal: pulumi.String("sns.amazonaws.com"),
-				Action:    pulumi.String("lambda:InvokeFunction"),
-			}, opts...)
-			if err != nil {
+		if args.EventBus != nil {
+			if err := newTopicRule(ctx, name+t, args.EventBus, t, args.EventFilters[t], res.Function, opts...); err != nil {
 				return nil, err
 			}
+			continue
+		}
 
-			_, err = sns.NewTopicSubscription(ctx, name+t+"Subscription", &sns.TopicSubscriptionArgs{
-				Endpoint: res.Function.Arn,
-				Protocol: pulumi.String("lambda"),
-				Topic:    topic.ID(), // TODO check (was topic.sns)
-			}, opts...)
-			if err != nil {
+		topic, ok := args.Topics[t]
+		if !ok {
+			continue
+		}
+
+		if queued(args.Subscriptions[t]) {
+			if err := newTopicQueue(ctx, name+t, topic, args.Subscriptions[t], res, opts...); err != nil {
 				return nil, err
 			}
-		} else {
-			fmt.Printf("WARNING: Function %s has a Trigger %s, but the topic is missing", name, t)
+			continue
+		}
+
+		_, err = awslambda.NewPermission(ctx, name+t+"Permission", &awslambda.PermissionArgs{
+			SourceArn: topic.Arn,
+			Function:  res.Function.Name,
+			Principal: pulumi.String("sns.amazonaws.com"),
+			Action:    pulumi.String("lambda:InvokeFunction"),
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = sns.NewTopicSubscription(ctx, name+t+"Subscription", &sns.TopicSubscriptionArgs{
+			Endpoint: res.Function.Arn,
+			Protocol: pulumi.String("lambda"),
+			Topic:    topic.ID(), // TODO check (was topic.sns)
+		}, opts...)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -175,3 +257,38 @@ func newLambda(ctx *pulumi.Context, name string, args *LambdaArgs, opts ...pulum
 		"lambda": res.Function,
 	})
 }
+
+func (a *awsProvider) Env(c project.Compute) []types.EnvVar {
+	stackName := a.proj.Name + "-" + a.sc.Name
+	env := append(lambdaEnv(stackName, c), observabilityEnv(a.sc.Observability, c.Unit().Name, stackName)...)
+	if a.eventBridge() && len(a.proj.Topics) > 0 {
+		env = append(env, eventBusEnv(&EventBus{Name: stackName})...)
+	}
+	for _, k := range a.proj.DatabasesFor(c.Unit().Name) {
+		env = append(env, databaseEnv(k)...)
+	}
+	for _, k := range a.proj.EmailsFor(c.Unit().Name) {
+		env = append(env, emailEnv(k, a.proj.Emails[k])...)
+	}
+	env = append(env, common.UnitEnv(c)...)
+	return common.MergeEnv(env, a.envMap)
+}
+
+// observabilityEnv adds the location of the collector layer's configuration to the OpenTelemetry env.
+func observabilityEnv(o *stack.Observability, function, stackName string) []types.EnvVar {
+	env := common.ObservabilityEnv(o, function, stackName)
+	if o != nil {
+		env = append(env, types.EnvVar{Name: "OPENTELEMETRY_COLLECTOR_CONFIG_FILE", Value: collectorConfigFile, Source: types.EnvSourceProvider})
+	}
+	return env
+}
+
+// lambdaReservedEnv are set by the lambda runtime or lambdaEnv.
+var lambdaReservedEnv = []string{"AWS_", "LAMBDA_", "_HANDLER", "_X_AMZN_TRACE_ID", "TZ"}
+
+// lambdaEnv is the environment injected into every lambda, before the user's env files are applied.
+func lambdaEnv(stackName string, c project.Compute) []types.EnvVar {
+	return append(common.DeploymentEnv(stackName, c),
+		types.EnvVar{Name: "MIN_WORKERS", Value: fmt.Sprint(c.Workers()), Source: types.EnvSourceProvider},
+	)
+}
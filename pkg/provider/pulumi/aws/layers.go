@@ -0,0 +1,218 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// maxLayers is the number of layers Lambda allows a function to use.
+const maxLayers = 5
+
+var layerArn = regexp.MustCompile(`^arn:aws[a-z-]*:lambda:([a-z0-9-]+):\d{12}:layer:[A-Za-z0-9_-]+:\d+$`)
+
+// resolveLayerArn replaces {region} with the stack's region, layers can only be used in the region they are published to.
+func resolveLayerArn(arn, region string) (string, error) {
+	arn = strings.ReplaceAll(arn, "{region}", region)
+
+	m := layerArn.FindStringSubmatch(arn)
+	if m == nil {
+		return "", fmt.Errorf("%s is not a layer version ARN", arn)
+	}
+	if m[1] != region {
+		return "", fmt.Errorf("layer %s is in region %s, but the stack is in %s, use {region} in the ARN to follow the stack", arn, m[1], region)
+	}
+	return arn, nil
+}
+
+// validateLayers checks the layers are for existing functions and resolve in the stack's region.
+func (a *awsProvider) validateLayers() error {
+	for fn := range a.sc.Layers {
+		if _, ok := a.proj.Functions[fn]; !ok {
+			return fmt.Errorf("layers configured for function %s, but the function does not exist", fn)
+		}
+	}
+
+	for _, c := range a.proj.Computes() {
+		fn := c.Unit().Name
+		layers := a.functionLayers(fn)
+		if len(layers) > maxLayers {
+			return fmt.Errorf("function %s has %d layers, Lambda allows at most %d", fn, len(layers), maxLayers)
+		}
+		for _, arn := range layers {
+			if _, err := resolveLayerArn(arn, a.sc.Region); err != nil {
+				return errors.WithMessage(err, "function "+fn)
+			}
+		}
+	}
+	return nil
+}
+
+// collectorLayer is the AWS Distro for OpenTelemetry collector, used when observability is configured.
+const collectorLayer = "arn:aws:lambda:{region}:901920570463:layer:aws-otel-collector-amd64-ver-0-62-1:1"
+
+// collectorConfigFile is where the collector configuration is copied to in the image.
+const collectorConfigFile = "/opt/collector.yaml"
+
+// functionLayers are the layer ARNs of the function, including the collector when observability is configured.
+func (a *awsProvider) functionLayers(fn string) []string {
+	layers := append([]string{}, a.sc.Layers[fn].Layers...)
+	if o := a.sc.Observability; o != nil {
+		if o.CollectorLayer != "" {
+			layers = append(layers, o.CollectorLayer)
+		} else {
+			layers = append(layers, collectorLayer)
+		}
+	}
+	return layers
+}
+
+// layerInstructions returns the Dockerfile instructions that add the function's layers and
+// extensions to its image, the layers are downloaded into the build context dir.
+func (a *awsProvider) layerInstructions(fn string, dir string) ([]string, error) {
+	instructions := []string{}
+
+	layers := a.functionLayers(fn)
+	if len(layers) > 0 {
+		sess, err := session.NewSession(&aws.Config{Region: aws.String(a.sc.Region)})
+		if err != nil {
+			return nil, errors.WithMessage(err, "aws session")
+		}
+		client := lambda.New(sess)
+
+		for i, arn := range layers {
+			arn, err = resolveLayerArn(arn, a.sc.Region)
+			if err != nil {
+				return nil, err
+			}
+
+			out, err := client.GetLayerVersionByArn(&lambda.GetLayerVersionByArnInput{Arn: aws.String(arn)})
+			if err != nil {
+				return nil, errors.WithMessage(err, "layer "+arn)
+			}
+
+			layerDir := filepath.Join("layers", fn, fmt.Sprint(i))
+			if err := downloadLayer(aws.StringValue(out.Content.Location), filepath.Join(dir, layerDir)); err != nil {
+				return nil, errors.WithMessage(err, "layer "+arn)
+			}
+			instructions = append(instructions, fmt.Sprintf("COPY %s/ /opt/", filepath.ToSlash(layerDir)))
+		}
+	}
+
+	for _, image := range a.sc.Layers[fn].Extensions {
+		instructions = append(instructions, fmt.Sprintf("COPY --from=%s /opt/. /opt/", image))
+	}
+
+	if a.sc.Observability != nil {
+		config, err := common.CollectorConfig(a.sc.Observability, a.envMap)
+		if err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "collector.yaml"), []byte(config), 0644); err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, "COPY collector.yaml "+collectorConfigFile)
+	}
+
+	return instructions, nil
+}
+
+// downloadLayer extracts the layer archive at url into dir.
+func downloadLayer(url string, dir string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return utils.NetworkErr(err, "downloading the layer "+url)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading layer failed with status %s", resp.Status)
+	}
+
+	f, err := ioutil.TempFile("", "layer-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	size, err := io.Copy(f, resp.Body)
+	if err != nil {
+		return err
+	}
+
+	zr, err := zip.NewReader(f, size)
+	if err != nil {
+		return err
+	}
+
+	for _, zf := range zr.File {
+		target := filepath.Join(dir, zf.Name)
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("layer file %s is outside of the layer", zf.Name)
+		}
+
+		if zf.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := extractFile(zf, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractFile(zf *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	r, err := zf.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, zf.Mode())
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	_, err = io.Copy(w, r)
+	return err
+}
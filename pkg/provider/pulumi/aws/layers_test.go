@@ -0,0 +1,60 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import "testing"
+
+func TestResolveLayerArn(t *testing.T) {
+	tests := []struct {
+		name    string
+		arn     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "region placeholder",
+			arn:  "arn:aws:lambda:{region}:464622532012:layer:Datadog-Node16-x:85",
+			want: "arn:aws:lambda:us-east-1:464622532012:layer:Datadog-Node16-x:85",
+		},
+		{
+			name: "same region",
+			arn:  "arn:aws:lambda:us-east-1:123456789012:layer:shared:3",
+			want: "arn:aws:lambda:us-east-1:123456789012:layer:shared:3",
+		},
+		{
+			name:    "other region",
+			arn:     "arn:aws:lambda:eu-west-1:123456789012:layer:shared:3",
+			wantErr: true,
+		},
+		{
+			name:    "missing version",
+			arn:     "arn:aws:lambda:us-east-1:123456789012:layer:shared",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveLayerArn(tt.arn, "us-east-1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveLayerArn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveLayerArn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
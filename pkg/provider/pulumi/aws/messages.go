@@ -0,0 +1,148 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+var _ common.Messenger = &awsProvider{}
+
+// resourceArn finds the arn of a stack's resource from the tags it was deployed with.
+func resourceArn(sess *session.Session, stackName, resourceType, name string) (string, error) {
+	out, err := resourcegroupstaggingapi.New(sess).GetResources(&resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: []*string{aws.String(resourceType)},
+		TagFilters: []*resourcegroupstaggingapi.TagFilter{
+			{Key: aws.String("x-nitric-stack"), Values: []*string{aws.String(stackName)}},
+			{Key: aws.String("x-nitric-name"), Values: []*string{aws.String(name)}},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(out.ResourceTagMappingList) == 0 {
+		return "", fmt.Errorf("%s %s not found in stack %s, has it been deployed?", resourceType, name, stackName)
+	}
+	return aws.StringValue(out.ResourceTagMappingList[0].ResourceARN), nil
+}
+
+func (a *awsProvider) Publish(stackName, topic string, payload map[string]interface{}) (string, error) {
+	body, err := common.MessageBody(payload)
+	if err != nil {
+		return "", err
+	}
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(a.sc.Region)})
+	if err != nil {
+		return "", errors.WithMessage(err, "aws session")
+	}
+
+	if a.eventBridge() {
+		return publishEvent(sess, stackName, topic, string(body))
+	}
+
+	arn, err := resourceArn(sess, stackName, "sns", topic)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := sns.New(sess).Publish(&sns.PublishInput{
+		TopicArn: aws.String(arn),
+		Message:  aws.String(string(body)),
+	})
+	if err != nil {
+		return "", errors.WithMessage(err, "publish to "+topic)
+	}
+	return aws.StringValue(out.MessageId), nil
+}
+
+// publishEvent puts the topic's event on the stack's event bus, which is named after the stack.
+func publishEvent(sess *session.Session, stackName, topic, body string) (string, error) {
+	out, err := eventbridge.New(sess).PutEvents(&eventbridge.PutEventsInput{
+		Entries: []*eventbridge.PutEventsRequestEntry{
+			{
+				EventBusName: aws.String(stackName),
+				Source:       aws.String(eventSource),
+				DetailType:   aws.String(topic),
+				Detail:       aws.String(body),
+			},
+		},
+	})
+	if err != nil {
+		return "", errors.WithMessage(err, "publish to "+topic)
+	}
+	if aws.Int64Value(out.FailedEntryCount) > 0 || len(out.Entries) == 0 {
+		msg := "unknown error"
+		if len(out.Entries) > 0 {
+			msg = aws.StringValue(out.Entries[0].ErrorMessage)
+		}
+		return "", fmt.Errorf("publish to %s failed: %s", topic, msg)
+	}
+	return aws.StringValue(out.Entries[0].EventId), nil
+}
+
+func (a *awsProvider) Send(stackName, queue string, payload map[string]interface{}) (string, error) {
+	body, err := common.MessageBody(payload)
+	if err != nil {
+		return "", err
+	}
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(a.sc.Region)})
+	if err != nil {
+		return "", errors.WithMessage(err, "aws session")
+	}
+
+	arn, err := resourceArn(sess, stackName, "sqs", queue)
+	if err != nil {
+		return "", err
+	}
+
+	// arn:aws:sqs:<region>:<account>:<name>
+	parts := strings.Split(arn, ":")
+	if len(parts) != 6 {
+		return "", fmt.Errorf("unexpected queue arn %s", arn)
+	}
+
+	client := sqs.New(sess)
+	url, err := client.GetQueueUrl(&sqs.GetQueueUrlInput{
+		QueueName:              aws.String(parts[5]),
+		QueueOwnerAWSAccountId: aws.String(parts[4]),
+	})
+	if err != nil {
+		return "", errors.WithMessage(err, "queue url of "+queue)
+	}
+
+	out, err := client.SendMessage(&sqs.SendMessageInput{
+		QueueUrl:    url.QueueUrl,
+		MessageBody: aws.String(string(body)),
+	})
+	if err != nil {
+		return "", errors.WithMessage(err, "send to "+queue)
+	}
+	return aws.StringValue(out.MessageId), nil
+}
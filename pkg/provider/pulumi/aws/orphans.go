@@ -0,0 +1,137 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var _ common.Collector = &awsProvider{}
+
+// orphanType is the service and kind of resource of an ARN, e.g. lambda:function, or only the service
+// when its resources have no kind, e.g. sqs.
+func orphanType(a arn.ARN) string {
+	// api gateway resources start with a /, e.g. arn:aws:apigateway:<region>::/apis/<id>
+	r := strings.TrimPrefix(a.Resource, "/")
+	if i := strings.IndexAny(r, ":/"); i > 0 {
+		return a.Service + ":" + r[:i]
+	}
+	return a.Service
+}
+
+// arnResourceName is the name of the resource in an ARN, after its kind.
+func arnResourceName(a arn.ARN) string {
+	r := strings.TrimPrefix(a.Resource, "/")
+	if i := strings.IndexAny(r, ":/"); i > 0 {
+		return strings.TrimSuffix(r[i+1:], ":*")
+	}
+	return r
+}
+
+// TaggedResources finds the resources of the stack's region with its x-nitric-stack tag.
+func (a *awsProvider) TaggedResources(stackName string) ([]types.Orphan, error) {
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(a.sc.Region)})
+	if err != nil {
+		return nil, errors.WithMessage(err, "aws session")
+	}
+
+	res := []types.Orphan{}
+	err = resourcegroupstaggingapi.New(sess).GetResourcesPages(&resourcegroupstaggingapi.GetResourcesInput{
+		TagFilters: []*resourcegroupstaggingapi.TagFilter{
+			{Key: aws.String("x-nitric-stack"), Values: []*string{aws.String(stackName)}},
+		},
+	}, func(out *resourcegroupstaggingapi.GetResourcesOutput, _ bool) bool {
+		for _, m := range out.ResourceTagMappingList {
+			a, err := arn.Parse(aws.StringValue(m.ResourceARN))
+			if err != nil {
+				continue
+			}
+			res = append(res, types.Orphan{Type: orphanType(a), ID: a.String()})
+		}
+		return true
+	})
+	return res, err
+}
+
+// DeleteOrphan deletes the kinds of resources the stack deploys that have no dependents, the others have to be
+// deleted by hand.
+func (a *awsProvider) DeleteOrphan(r types.Orphan) error {
+	resArn, err := arn.Parse(r.ID)
+	if err != nil {
+		return err
+	}
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(a.sc.Region)})
+	if err != nil {
+		return errors.WithMessage(err, "aws session")
+	}
+
+	name := arnResourceName(resArn)
+	switch r.Type {
+	case "s3":
+		_, err = s3.New(sess).DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String(resArn.Resource)})
+	case "sns":
+		_, err = sns.New(sess).DeleteTopic(&sns.DeleteTopicInput{TopicArn: aws.String(r.ID)})
+	case "sqs":
+		client := sqs.New(sess)
+		out, err := client.GetQueueUrl(&sqs.GetQueueUrlInput{
+			QueueName:              aws.String(resArn.Resource),
+			QueueOwnerAWSAccountId: aws.String(resArn.AccountID),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = client.DeleteQueue(&sqs.DeleteQueueInput{QueueUrl: out.QueueUrl})
+		return err
+	case "lambda:function":
+		_, err = lambda.New(sess).DeleteFunction(&lambda.DeleteFunctionInput{FunctionName: aws.String(r.ID)})
+	case "dynamodb:table":
+		_, err = dynamodb.New(sess).DeleteTable(&dynamodb.DeleteTableInput{TableName: aws.String(name)})
+	case "secretsmanager:secret":
+		// the secret can be restored until its recovery window ends
+		_, err = secretsmanager.New(sess).DeleteSecret(&secretsmanager.DeleteSecretInput{SecretId: aws.String(r.ID)})
+	case "logs:log-group":
+		_, err = cloudwatchlogs.New(sess).DeleteLogGroup(&cloudwatchlogs.DeleteLogGroupInput{LogGroupName: aws.String(name)})
+	case "cloudwatch:alarm":
+		_, err = cloudwatch.New(sess).DeleteAlarms(&cloudwatch.DeleteAlarmsInput{AlarmNames: []*string{aws.String(name)}})
+	case "apigateway:apis":
+		_, err = apigatewayv2.New(sess).DeleteApi(&apigatewayv2.DeleteApiInput{ApiId: aws.String(name)})
+	default:
+		return utils.NewNotSupportedErr(fmt.Sprintf("%s resources can not be deleted by nitric, delete it in the AWS console", r.Type))
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+)
+
+func Test_orphanType(t *testing.T) {
+	tests := []struct {
+		arn      string
+		wantType string
+		wantName string
+	}{
+		{arn: "arn:aws:sqs:us-east-1:123456789012:orders", wantType: "sqs", wantName: "orders"},
+		{arn: "arn:aws:s3:::images-1234", wantType: "s3", wantName: "images-1234"},
+		{arn: "arn:aws:lambda:us-east-1:123456789012:function:checkout", wantType: "lambda:function", wantName: "checkout"},
+		{arn: "arn:aws:dynamodb:us-east-1:123456789012:table/orders", wantType: "dynamodb:table", wantName: "orders"},
+		{arn: "arn:aws:logs:us-east-1:123456789012:log-group:/aws/lambda/checkout:*", wantType: "logs:log-group", wantName: "/aws/lambda/checkout"},
+		{arn: "arn:aws:apigateway:us-east-1::/apis/a1b2c3", wantType: "apigateway:apis", wantName: "a1b2c3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arn, func(t *testing.T) {
+			a, err := arn.Parse(tt.arn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := orphanType(a); got != tt.wantType {
+				t.Errorf("orphanType() = %v, want %v", got, tt.wantType)
+			}
+			if got := arnResourceName(a); got != tt.wantName {
+				t.Errorf("arnResourceName() = %v, want %v", got, tt.wantName)
+			}
+		})
+	}
+}
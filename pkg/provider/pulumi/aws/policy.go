@@ -28,6 +28,7 @@ import (
 	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/sqs"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
 	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
 )
 
@@ -40,6 +41,7 @@ type Policy struct {
 
 type StackResources struct {
 	Topics      map[string]*sns.Topic
+	EventBus    *EventBus
 	Queues      map[string]*sqs.Queue
 	Buckets     map[string]*s3.Bucket
 	Collections map[string]*dynamodb.Table
@@ -58,7 +60,8 @@ type PolicyArgs struct {
 
 var awsActionsMap map[v1.Action][]string = map[v1.Action][]string{
 	v1.Action_BucketFileList: {
-		"s3:ListObjectsV2",
+		// listing objects is allowed by s3:ListBucket, ListObjectsV2 is only the name of the API call
+		"s3:ListBucket",
 	},
 	v1.Action_BucketFileGet: {
 		"s3:GetObject",
@@ -78,6 +81,8 @@ var awsActionsMap map[v1.Action][]string = map[v1.Action][]string{
 	},
 	v1.Action_TopicEventPublish: {
 		"sns:Publish",
+		// when eventing is eventbridge
+		"events:PutEvents",
 	},
 	v1.Action_QueueSend: {
 		"sqs:SendMessage",
@@ -130,6 +135,14 @@ func actionsToAwsActions(actions []v1.Action) []string {
 	return awsActions
 }
 
+var _ common.PermissionMapper = &awsProvider{}
+
+// Grants returns the IAM actions allowed on the resource's ARNs, a function's role policy allows every
+// action of a policy on each of its resources.
+func (a *awsProvider) Grants(action v1.Action, resource *v1.Resource) []string {
+	return awsActionsMap[action]
+}
+
 // discover the arn of a deployed resource
 func arnForResource(resource *v1.Resource, resources *StackResources) ([]interface{}, error) {
 	switch resource.Type {
@@ -141,6 +154,10 @@ func arnForResource(resource *v1.Resource, resources *StackResources) ([]interfa
 		if t, ok := resources.Topics[resource.Name]; ok {
 			return []interface{}{t.Arn}, nil
 		}
+		if resources.EventBus != nil {
+			// the topics share the stack's event bus
+			return []interface{}{resources.EventBus.Bus.Arn}, nil
+		}
 	case v1.ResourceType_Queue:
 		if q, ok := resources.Queues[resource.Name]; ok {
 			return []interface{}{q.Arn}, nil
@@ -0,0 +1,70 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+// minUnreservedConcurrency is the concurrency lambda keeps for the functions without reserved concurrency.
+const minUnreservedConcurrency = 100
+
+var _ common.QuotaChecker = &awsProvider{}
+
+// Quotas reads the lambda concurrency limit of the account, new accounts start with a limit too low to
+// reserve concurrency for any function.
+func (a *awsProvider) Quotas() ([]types.Quota, error) {
+	reserved := 0
+	lambdas := 0
+	for _, c := range a.proj.Computes() {
+		if c.Unit().AlwaysOn || c.Unit().Job != nil {
+			continue
+		}
+		lambdas++
+		reserved += a.sc.MaxConcurrency(c.Unit().Name)
+	}
+	if lambdas == 0 {
+		return []types.Quota{}, nil
+	}
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(a.sc.Region)})
+	if err != nil {
+		return nil, errors.WithMessage(err, "aws session")
+	}
+	settings, err := lambda.New(sess).GetAccountSettings(&lambda.GetAccountSettingsInput{})
+	if err != nil {
+		return nil, errors.WithMessage(err, "lambda account settings")
+	}
+
+	return []types.Quota{lambdaConcurrencyQuota(aws.Int64Value(settings.AccountLimit.ConcurrentExecutions), reserved, a.sc.Region)}, nil
+}
+
+// lambdaConcurrencyQuota needs the stack's reserved concurrency on top of what lambda keeps unreserved.
+func lambdaConcurrencyQuota(limit int64, reserved int, region string) types.Quota {
+	return types.Quota{
+		Name:        "Lambda concurrent executions",
+		Limit:       float64(limit),
+		Needed:      float64(reserved + minUnreservedConcurrency),
+		IncreaseUrl: "https://" + region + ".console.aws.amazon.com/servicequotas/home/services/lambda/quotas/L-B99A9384",
+	}
+}
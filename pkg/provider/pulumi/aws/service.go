@@ -0,0 +1,330 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/appautoscaling"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/cloudwatch"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/ec2"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/ecs"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/iam"
+	"github.com/pulumi/pulumi-docker/sdk/v3/go/docker"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/cron"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+type FargateServiceArgs struct {
+	StackName   string
+	Cluster     *ecs.Cluster
+	Region      string
+	DockerImage *docker.Image
+	Compute     project.Compute
+	EnvMap      map[string]string
+	// Sleep scales the service to zero outside the awake hours
+	Sleep *stack.Sleep
+	// EventBus carries the topics when eventing is eventbridge
+	EventBus *EventBus
+	// Volumes are mounted from the EFS volumes of the same name
+	Volumes    map[string]project.VolumeMount
+	EfsVolumes map[string]*EfsVolume
+}
+
+type FargateService struct {
+	pulumi.ResourceState
+
+	Name    string
+	Service *ecs.Service
+	Role    *iam.Role
+}
+
+// Fargate tasks have 20GiB of ephemeral storage, shared by the image and the files written, it can be raised to 200GiB.
+const (
+	defaultFargateStorage = 20
+	maxFargateStorage     = 200
+)
+
+// fargateStorage returns the ephemeral storage in GiB to hold the tmp size in MB, or 0 when the default is enough.
+func fargateStorage(tmpSize int) int {
+	storage := (tmpSize + 1023) / 1024
+	if storage <= defaultFargateStorage {
+		return 0
+	}
+	return storage
+}
+
+// fargateCpu is the smallest task CPU, in units of 1/1024 vCPU, that Fargate allows with the memory.
+func fargateCpu(memory int) int {
+	switch {
+	case memory <= 2048:
+		return 256
+	case memory <= 4096:
+		return 512
+	case memory <= 8192:
+		return 1024
+	case memory <= 16384:
+		return 2048
+	default:
+		return 4096
+	}
+}
+
+// newFargateService runs a service's container on Fargate in the default VPC, keeping
+// MinScale tasks running.
+func newFargateService(ctx *pulumi.Context, name string, args *FargateServiceArgs, opts ...pulumi.ResourceOption) (*FargateService, error) {
+	res := &FargateService{Name: name}
+	err := ctx.RegisterComponentResource("nitric:service:AWSFargate", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	task, err := newFargateTask(ctx, name, args, opts...)
+	if err != nil {
+		return nil, err
+	}
+	res.Role = task.Role
+
+	isDefault := true
+	vpc, err := ec2.LookupVpc(ctx, &ec2.LookupVpcArgs{Default: &isDefault})
+	if err != nil {
+		return nil, err
+	}
+	subnets, err := ec2.GetSubnetIds(ctx, &ec2.GetSubnetIdsArgs{VpcId: vpc.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	res.Service, err = ecs.NewService(ctx, name, &ecs.ServiceArgs{
+		Cluster:        args.Cluster.Arn,
+		TaskDefinition: task.Definition.Arn,
+		LaunchType:     pulumi.String("FARGATE"),
+		DesiredCount:   pulumi.Int(common.IntValueOrDefault(args.Compute.Unit().MinScale, 1)),
+		NetworkConfiguration: ecs.ServiceNetworkConfigurationArgs{
+			Subnets: pulumi.ToStringArray(subnets.Ids),
+			// the default VPC has no NAT gateway, the public IP is used to pull the image
+			AssignPublicIp: pulumi.Bool(true),
+		},
+		Tags: common.Tags(ctx, name),
+	}, append(opts, pulumi.DependsOn(task.MountTargets))...)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Sleep != nil {
+		if err := sleepSchedule(ctx, name, args.Cluster, res.Service, args.Compute, args.Sleep, opts...); err != nil {
+			return nil, err
+		}
+	}
+
+	// nitric stack sleep and wake find the service with its ARN
+	ctx.Export("service:"+name, res.Service.ID())
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":    pulumi.String(res.Name),
+		"service": res.Service.Name,
+	})
+}
+
+// fargateTask is the task definition run by a service or job, along with the roles of its tasks.
+type fargateTask struct {
+	Definition    *ecs.TaskDefinition
+	Role          *iam.Role
+	ExecutionRole *iam.Role
+	// MountTargets of the volumes, tasks fail to start until the file systems can be mounted in their subnet
+	MountTargets []pulumi.Resource
+}
+
+// newFargateTask creates the task definition of the compute unit's container, the task role is used
+// by the container, the execution role pulls the image and writes the logs.
+func newFargateTask(ctx *pulumi.Context, name string, args *FargateServiceArgs, opts ...pulumi.ResourceOption) (*fargateTask, error) {
+	task := &fargateTask{}
+
+	var err error
+	task.Role, err = iam.NewRole(ctx, name+"TaskRole", &iam.RoleArgs{
+		AssumeRolePolicy: pulumi.String(assumeRolePolicy("ecs-tasks.amazonaws.com")),
+		Tags:             common.Tags(ctx, name+"TaskRole"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	task.ExecutionRole, err = iam.NewRole(ctx, name+"ExecutionRole", &iam.RoleArgs{
+		AssumeRolePolicy: pulumi.String(assumeRolePolicy("ecs-tasks.amazonaws.com")),
+		Tags:             common.Tags(ctx, name+"ExecutionRole"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = iam.NewRolePolicyAttachment(ctx, name+"TaskExecution", &iam.RolePolicyAttachmentArgs{
+		PolicyArn: pulumi.String("arn:aws:iam::aws:policy/service-role/AmazonECSTaskExecutionRolePolicy"),
+		Role:      task.ExecutionRole.ID(),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	logGroup, err := cloudwatch.NewLogGroup(ctx, name+"Logs", &cloudwatch.LogGroupArgs{
+		RetentionInDays: pulumi.Int(30),
+		Tags:            common.Tags(ctx, name+"Logs"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	env := []map[string]string{}
+	for _, e := range common.MergeEnv(append(append(lambdaEnv(args.StackName, args.Compute), eventBusEnv(args.EventBus)...), common.UnitEnv(args.Compute)...), args.EnvMap) {
+		env = append(env, map[string]string{"name": e.Name, "value": e.Value})
+	}
+
+	mountPoints := []map[string]interface{}{}
+	volumes := ecs.TaskDefinitionVolumeArray{}
+	names := []string{}
+	for k := range args.Volumes {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		v := args.EfsVolumes[k]
+		mountPoints = append(mountPoints, map[string]interface{}{
+			"sourceVolume":  k,
+			"containerPath": args.Volumes[k].Path,
+			"readOnly":      args.Volumes[k].ReadOnly,
+		})
+		volumes = append(volumes, ecs.TaskDefinitionVolumeArgs{
+			Name: pulumi.String(k),
+			EfsVolumeConfiguration: ecs.TaskDefinitionVolumeEfsVolumeConfigurationArgs{
+				FileSystemId:      v.FileSystem.ID(),
+				TransitEncryption: pulumi.String("ENABLED"),
+			},
+		})
+		// tasks fail to start until the file system can be mounted in their subnet
+		for _, mt := range v.MountTargets {
+			task.MountTargets = append(task.MountTargets, mt)
+		}
+	}
+
+	port := common.IntValueOrDefault(args.Compute.Unit().Port, 9001)
+	containerDefinitions := pulumi.All(args.DockerImage.ImageName, logGroup.Name).ApplyT(func(all []interface{}) (string, error) {
+		b, err := json.Marshal([]map[string]interface{}{
+			{
+				"name":         name,
+				"image":        all[0],
+				"essential":    true,
+				"environment":  env,
+				"portMappings": []map[string]interface{}{{"containerPort": port}},
+				"mountPoints":  mountPoints,
+				"logConfiguration": map[string]interface{}{
+					"logDriver": "awslogs",
+					"options": map[string]string{
+						"awslogs-group":         all[1].(string),
+						"awslogs-region":        args.Region,
+						"awslogs-stream-prefix": name,
+					},
+				},
+			},
+		})
+		return string(b), err
+	}).(pulumi.StringOutput)
+
+	memory := args.Compute.Unit().MemoryOrDefault(512)
+	cpu := fargateCpu(memory)
+	if class := args.Compute.Unit().ComputeClass(); class != nil {
+		cpu = int(class.Cpu * 1024)
+	}
+	taskArgs := &ecs.TaskDefinitionArgs{
+		Family:                  pulumi.String(args.StackName + "-" + name),
+		RequiresCompatibilities: pulumi.StringArray{pulumi.String("FARGATE")},
+		NetworkMode:             pulumi.String("awsvpc"),
+		Cpu:                     pulumi.String(fmt.Sprint(cpu)),
+		Memory:                  pulumi.String(fmt.Sprint(memory)),
+		TaskRoleArn:             task.Role.Arn,
+		ExecutionRoleArn:        task.ExecutionRole.Arn,
+		ContainerDefinitions:    containerDefinitions,
+		Volumes:                 volumes,
+		Tags:                    common.Tags(ctx, name),
+	}
+	if storage := fargateStorage(args.Compute.Unit().TmpSize); storage > 0 {
+		taskArgs.EphemeralStorage = ecs.TaskDefinitionEphemeralStorageArgs{SizeInGib: pulumi.Int(storage)}
+	}
+	task.Definition, err = ecs.NewTaskDefinition(ctx, name, taskArgs, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// sleepSchedule scales the service's tasks to zero when the stack goes to sleep, and back to
+// minScale when it wakes up.
+func sleepSchedule(ctx *pulumi.Context, name string, cluster *ecs.Cluster, service *ecs.Service, c project.Compute, sleep *stack.Sleep, opts ...pulumi.ResourceOption) error {
+	minScale := common.IntValueOrDefault(c.Unit().MinScale, 1)
+	maxScale := common.IntValueOrDefault(c.Unit().MaxScale, minScale)
+	if maxScale < minScale {
+		maxScale = minScale
+	}
+
+	target, err := appautoscaling.NewTarget(ctx, name+"Scaling", &appautoscaling.TargetArgs{
+		ResourceId:        pulumi.Sprintf("service/%s/%s", cluster.Name, service.Name),
+		ScalableDimension: pulumi.String("ecs:service:DesiredCount"),
+		ServiceNamespace:  pulumi.String("ecs"),
+		MinCapacity:       pulumi.Int(minScale),
+		MaxCapacity:       pulumi.Int(maxScale),
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	actions := []struct {
+		name     string
+		schedule string
+		min, max int
+	}{
+		{name: "Wake", schedule: sleep.Wake, min: minScale, max: maxScale},
+		{name: "Sleep", schedule: sleep.Sleep, min: 0, max: 0},
+	}
+	for _, a := range actions {
+		schedule, err := cron.ConvertToAWS(a.schedule)
+		if err != nil {
+			return err
+		}
+
+		_, err = appautoscaling.NewScheduledAction(ctx, name+a.name, &appautoscaling.ScheduledActionArgs{
+			ResourceId:        target.ResourceId,
+			ScalableDimension: target.ScalableDimension,
+			ServiceNamespace:  target.ServiceNamespace,
+			Schedule:          pulumi.String(schedule),
+			ScalableTargetAction: appautoscaling.ScheduledActionScalableTargetActionArgs{
+				MinCapacity: pulumi.Int(a.min),
+				MaxCapacity: pulumi.Int(a.max),
+			},
+		}, opts...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package aws
+
+import (
+	"path/filepath"
+
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/s3"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+type SiteArgs struct {
+	ProjectDir string
+	Site       project.Site
+	Cdn        stack.CdnTarget
+	Tls        stack.Tls
+	Env        pulumi.StringOutput
+}
+
+type Site struct {
+	pulumi.ResourceState
+
+	Name   string
+	Bucket *s3.Bucket
+	Cdn    *Cdn
+}
+
+func newSite(ctx *pulumi.Context, name string, args *SiteArgs, opts ...pulumi.ResourceOption) (*Site, error) {
+	res := &Site{Name: name}
+	err := ctx.RegisterComponentResource("nitric:site:AwsSite", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	index := args.Site.IndexDocument
+	if index == "" {
+		index = "index.html"
+	}
+
+	website := &s3.BucketWebsiteArgs{
+		IndexDocument: pulumi.String(index),
+	}
+	if args.Site.ErrorDocument != "" {
+		website.ErrorDocument = pulumi.String(args.Site.ErrorDocument)
+	}
+
+	res.Bucket, err = s3.NewBucket(ctx, name+"-site", &s3.BucketArgs{
+		Website: website,
+		Tags:    common.Tags(ctx, name),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := common.SiteFiles(filepath.Join(args.ProjectDir, args.Site.Path))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		_, err = s3.NewBucketObject(ctx, name+"-"+f.Key, &s3.BucketObjectArgs{
+			Bucket:      res.Bucket.ID(),
+			Key:         pulumi.String(f.Key),
+			Source:      pulumi.NewFileAsset(f.Path),
+			ContentType: pulumi.String(f.ContentType),
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = s3.NewBucketObject(ctx, name+"-"+common.SiteEnvFile, &s3.BucketObjectArgs{
+		Bucket:      res.Bucket.ID(),
+		Key:         pulumi.String(common.SiteEnvFile),
+		Content:     args.Env,
+		ContentType: pulumi.String("application/json"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Cdn, err = newCdn(ctx, name+"-site-cdn", &CdnArgs{
+		Target:            args.Cdn,
+		Bucket:            res.Bucket,
+		DefaultRootObject: index,
+		Tls:               args.Tls,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Export("site:"+name, pulumi.Sprintf("https://%s", res.Cdn.Distribution.DomainName))
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":   pulumi.String(res.Name),
+		"bucket": res.Bucket,
+	})
+}
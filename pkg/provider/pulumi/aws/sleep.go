@@ -0,0 +1,66 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+var _ common.Sleeper = &awsProvider{}
+
+// Sleep sets the desired count of the fargate services to zero, or back to their minScale.
+func (a *awsProvider) Sleep(ids map[string]string, sleeping bool) error {
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(a.sc.Region)})
+	if err != nil {
+		return errors.WithMessage(err, "aws session")
+	}
+	client := ecs.New(sess)
+
+	for _, c := range a.proj.Computes() {
+		arn, ok := ids[c.Unit().Name]
+		if !ok {
+			continue
+		}
+		// arn:aws:ecs:<region>:<account>:service/<cluster>/<service>
+		parts := strings.Split(arn, "/")
+		if len(parts) != 3 {
+			return fmt.Errorf("service %s has an unexpected ARN %s", c.Unit().Name, arn)
+		}
+
+		count := int64(common.IntValueOrDefault(c.Unit().MinScale, 1))
+		if sleeping {
+			count = 0
+		}
+		_, err := client.UpdateService(&ecs.UpdateServiceInput{
+			Cluster:      aws.String(parts[1]),
+			Service:      aws.String(parts[2]),
+			DesiredCount: aws.Int64(count),
+		})
+		if err != nil {
+			return errors.WithMessage(err, "update service "+c.Unit().Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/iam"
+	awslambda "github.com/pulumi/pulumi-aws/sdk/v4/go/aws/lambda"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/sns"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/sqs"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+const (
+	// lambdaTimeout is the seconds a function invocation can run for
+	lambdaTimeout = 15
+	// maxBatchSize is the most messages an sqs event source mapping delivers at once
+	maxBatchSize = 10000
+	// maxVisibilityTimeout is the longest an sqs message can be hidden for, 12 hours
+	maxVisibilityTimeout = 43200
+)
+
+// queued is true when the subscription's messages are delivered through a queue, which batches them.
+func queued(s stack.Subscription) bool {
+	return s.BatchSize > 0 || s.VisibilityTimeout > 0
+}
+
+// validateSubscriptions checks the delivery settings can be met by a queue between the topic and the function.
+func (a *awsProvider) validateSubscriptions() error {
+	for _, s := range common.SortedSubscriptions(a.sc.Subscriptions) {
+		if !queued(s.Subscription) {
+			continue
+		}
+		if a.eventBridge() {
+			return utils.NewNotSupportedErr(fmt.Sprintf("the batchSize and visibilityTimeout of subscription %s:%s need %s eventing", s.Function, s.Topic, stack.EventingSns))
+		}
+		if s.BatchSize > maxBatchSize {
+			return fmt.Errorf("the batchSize of subscription %s:%s is more than %d", s.Function, s.Topic, maxBatchSize)
+		}
+		if s.VisibilityTimeout != 0 && (s.VisibilityTimeout < lambdaTimeout || s.VisibilityTimeout > maxVisibilityTimeout) {
+			return fmt.Errorf("the visibilityTimeout of subscription %s:%s must be between the function timeout of %d and %d seconds", s.Function, s.Topic, lambdaTimeout, maxVisibilityTimeout)
+		}
+	}
+	return nil
+}
+
+// newTopicQueue delivers the topic's messages to the function through a queue, so they are batched
+// and retried after the visibility timeout.
+func newTopicQueue(ctx *pulumi.Context, name string, topic *sns.Topic, sub stack.Subscription, fn *Lambda, opts ...pulumi.ResourceOption) error {
+	queue, err := sqs.NewQueue(ctx, name+"Queue", &sqs.QueueArgs{
+		VisibilityTimeoutSeconds: pulumi.IntPtr(common.IntValueOrDefault(sub.VisibilityTimeout, 30)),
+		Tags:                     common.Tags(ctx, name+"Queue"),
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	queuePolicy := pulumi.All(queue.Arn, topic.Arn).ApplyT(func(args []interface{}) (string, error) {
+		b, err := json.Marshal(map[string]interface{}{
+			"Version": "2012-10-17",
+			"Statement": []map[string]interface{}{
+				{
+					"Action":    "sqs:SendMessage",
+					"Effect":    "Allow",
+					"Principal": map[string]interface{}{"Service": "sns.amazonaws.com"},
+					"Resource":  args[0],
+					"Condition": map[string]interface{}{
+						"ArnEquals": map[string]interface{}{"aws:SourceArn": args[1]},
+					},
+				},
+			},
+		})
+		return string(b), err
+	}).(pulumi.StringOutput)
+
+	_, err = sqs.NewQueuePolicy(ctx, name+"QueuePolicy", &sqs.QueuePolicyArgs{
+		QueueUrl: queue.Url,
+		Policy:   queuePolicy,
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	_, err = sns.NewTopicSubscription(ctx, name+"Subscription", &sns.TopicSubscriptionArgs{
+		Endpoint: queue.Arn,
+		Protocol: pulumi.String("sqs"),
+		Topic:    topic.ID(),
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	accessPolicy := queue.Arn.ApplyT(func(arn string) (string, error) {
+		b, err := json.Marshal(map[string]interface{}{
+			"Version": "2012-10-17",
+			"Statement": []map[string]interface{}{
+				{
+					"Action": []string{
+						"sqs:ReceiveMessage",
+						"sqs:DeleteMessage",
+						"sqs:GetQueueAttributes",
+					},
+					"Effect":   "Allow",
+					"Resource": arn,
+				},
+			},
+		})
+		return string(b), err
+	}).(pulumi.StringOutput)
+
+	access, err := iam.NewRolePolicy(ctx, name+"QueueAccess", &iam.RolePolicyArgs{
+		Role:   fn.Role.ID(),
+		Policy: accessPolicy,
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	mappingArgs := &awslambda.EventSourceMappingArgs{
+		EventSourceArn: queue.Arn,
+		FunctionName:   fn.Function.Arn,
+		BatchSize:      pulumi.IntPtr(common.IntValueOrDefault(sub.BatchSize, 1)),
+	}
+	if sub.BatchSize > 10 {
+		// sqs only delivers more than 10 messages at once when it can wait for them
+		mappingArgs.MaximumBatchingWindowInSeconds = pulumi.IntPtr(1)
+	}
+	_, err = awslambda.NewEventSourceMapping(ctx, name+"Mapping", mappingArgs, append(opts, pulumi.DependsOn([]pulumi.Resource{access}))...)
+	return err
+}
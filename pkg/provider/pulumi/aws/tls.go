@@ -0,0 +1,72 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"encoding/json"
+
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/s3"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+// tlsStatements deny requests to the bucket that don't use HTTPS, unless the stack allows HTTP, and those
+// using a version of TLS older than the stack's minimum.
+func tlsStatements(bucketArn string, t stack.Tls) []map[string]interface{} {
+	resources := []string{bucketArn, bucketArn + "/*"}
+	statements := []map[string]interface{}{}
+	if !t.AllowHttp {
+		statements = append(statements, map[string]interface{}{
+			"Sid":       "DenyHttp",
+			"Effect":    "Deny",
+			"Principal": "*",
+			"Action":    "s3:*",
+			"Resource":  resources,
+			"Condition": map[string]interface{}{
+				"Bool": map[string]interface{}{"aws:SecureTransport": "false"},
+			},
+		})
+	}
+	return append(statements, map[string]interface{}{
+		"Sid":       "DenyOldTls",
+		"Effect":    "Deny",
+		"Principal": "*",
+		"Action":    "s3:*",
+		"Resource":  resources,
+		"Condition": map[string]interface{}{
+			"NumericLessThan": map[string]interface{}{"s3:TlsVersion": t.MinVersion},
+		},
+	})
+}
+
+// newBucketTlsPolicy gives a bucket that isn't served from a cdn a policy with only the tls statements.
+func newBucketTlsPolicy(ctx *pulumi.Context, name string, bucket *s3.Bucket, t stack.Tls, opts ...pulumi.ResourceOption) error {
+	policy := bucket.Arn.ApplyT(func(arn string) (string, error) {
+		b, err := json.Marshal(map[string]interface{}{
+			"Version":   "2012-10-17",
+			"Statement": tlsStatements(arn, t),
+		})
+		return string(b), err
+	}).(pulumi.StringOutput)
+
+	_, err := s3.NewBucketPolicy(ctx, name, &s3.BucketPolicyArgs{
+		Bucket: bucket.ID(),
+		Policy: policy,
+	}, opts...)
+	return err
+}
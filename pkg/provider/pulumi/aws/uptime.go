@@ -0,0 +1,108 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/cloudwatch"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/route53"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/sns"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+// newUptimeChecks creates a Route 53 health check of each checked api, with an alarm when it fails.
+func (res *Alerts) newUptimeChecks(ctx *pulumi.Context, args *AlertsArgs, opts ...pulumi.ResourceOption) error {
+	// the health check metrics are only in us-east-1, so the alarms and the topic they notify are too
+	alarmOpts := opts
+	topic := res.Topic
+	if args.Region != "us-east-1" {
+		usEast1, err := aws.NewProvider(ctx, res.Name+"UsEast1", &aws.ProviderArgs{
+			Region: pulumi.String("us-east-1"),
+		}, opts...)
+		if err != nil {
+			return err
+		}
+		alarmOpts = append(opts, pulumi.Provider(usEast1))
+
+		topic, err = sns.NewTopic(ctx, res.Name+"UptimeTopic", &sns.TopicArgs{
+			Tags: common.Tags(ctx, res.Name+"UptimeTopic"),
+		}, alarmOpts...)
+		if err != nil {
+			return errors.WithMessage(err, "uptime topic")
+		}
+
+		_, err = sns.NewTopicSubscription(ctx, res.Name+"UptimeEmail", &sns.TopicSubscriptionArgs{
+			Topic:    topic.Arn,
+			Protocol: pulumi.String("email"),
+			Endpoint: pulumi.String(args.Alerts.Email),
+		}, alarmOpts...)
+		if err != nil {
+			return errors.WithMessage(err, "uptime email subscription")
+		}
+	}
+
+	uptime := args.Alerts.Uptime
+	for k, api := range args.Apis {
+		if !uptime.Checks(k) {
+			continue
+		}
+
+		host := api.Api.ApiEndpoint.ApplyT(func(ep string) string {
+			return strings.TrimPrefix(ep, "https://")
+		}).(pulumi.StringOutput)
+
+		var err error
+		res.HealthChecks[k], err = route53.NewHealthCheck(ctx, k+"HealthCheck", &route53.HealthCheckArgs{
+			Type:             pulumi.String("HTTPS"),
+			Fqdn:             host,
+			Port:             pulumi.Int(443),
+			ResourcePath:     pulumi.String(uptime.PathOrDefault()),
+			RequestInterval:  pulumi.Int(30),
+			FailureThreshold: pulumi.Int(3),
+			Tags:             common.Tags(ctx, k+"HealthCheck"),
+		}, opts...)
+		if err != nil {
+			return errors.WithMessage(err, "health check "+k)
+		}
+
+		res.Alarms[k+"Uptime"], err = cloudwatch.NewMetricAlarm(ctx, k+"UptimeAlarm", &cloudwatch.MetricAlarmArgs{
+			AlarmDescription:   pulumi.String(fmt.Sprintf("api %s is not responding", k)),
+			Namespace:          pulumi.String("AWS/Route53"),
+			MetricName:         pulumi.String("HealthCheckStatus"),
+			Statistic:          pulumi.String("Minimum"),
+			Dimensions:         pulumi.StringMap{"HealthCheckId": res.HealthChecks[k].ID().ToStringOutput()},
+			ComparisonOperator: pulumi.String("LessThanThreshold"),
+			Threshold:          pulumi.Float64(1),
+			EvaluationPeriods:  pulumi.Int(1),
+			Period:             pulumi.Int(60),
+			TreatMissingData:   pulumi.String("breaching"),
+			AlarmActions:       pulumi.Array{topic.Arn},
+			OkActions:          pulumi.Array{topic.Arn},
+			Tags:               common.Tags(ctx, k+"Uptime"),
+		}, alarmOpts...)
+		if err != nil {
+			return errors.WithMessage(err, "uptime alarm "+k)
+		}
+	}
+	return nil
+}
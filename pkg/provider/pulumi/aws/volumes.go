@@ -0,0 +1,115 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/ec2"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/efs"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+type EfsVolumeArgs struct {
+	// Containers are the names of the services that mount the volume
+	Containers []string
+}
+
+type EfsVolume struct {
+	pulumi.ResourceState
+
+	Name         string
+	FileSystem   *efs.FileSystem
+	MountTargets []*efs.MountTarget
+}
+
+// validateVolumes checks volumes are only mounted by services and jobs, lambdas can't mount EFS outside a VPC.
+func (a *awsProvider) validateVolumes() error {
+	for _, c := range a.proj.Containers {
+		if len(c.Volumes) > 0 && !c.AlwaysOn && c.Job == nil {
+			return fmt.Errorf("container %s mounts volumes on %s, which is only supported for services and jobs", c.Name, a.sc.Provider)
+		}
+	}
+	return nil
+}
+
+// newEfsVolume creates an encrypted EFS file system for the volume with a mount target in each
+// subnet of the default VPC, reachable over NFS from the services in the VPC.
+func newEfsVolume(ctx *pulumi.Context, name string, args *EfsVolumeArgs, opts ...pulumi.ResourceOption) (*EfsVolume, error) {
+	res := &EfsVolume{Name: name}
+	err := ctx.RegisterComponentResource("nitric:volume:AWSEfs", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	res.FileSystem, err = efs.NewFileSystem(ctx, name, &efs.FileSystemArgs{
+		Encrypted: pulumi.Bool(true),
+		Tags:      common.Tags(ctx, name),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	isDefault := true
+	vpc, err := ec2.LookupVpc(ctx, &ec2.LookupVpcArgs{Default: &isDefault})
+	if err != nil {
+		return nil, err
+	}
+	subnets, err := ec2.GetSubnetIds(ctx, &ec2.GetSubnetIdsArgs{VpcId: vpc.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	sg, err := ec2.NewSecurityGroup(ctx, name+"Nfs", &ec2.SecurityGroupArgs{
+		VpcId:       pulumi.String(vpc.Id),
+		Description: pulumi.Sprintf("NFS access to volume %s", name),
+		Ingress: ec2.SecurityGroupIngressArray{
+			ec2.SecurityGroupIngressArgs{
+				Protocol:   pulumi.String("tcp"),
+				FromPort:   pulumi.Int(2049),
+				ToPort:     pulumi.Int(2049),
+				CidrBlocks: pulumi.StringArray{pulumi.String(vpc.CidrBlock)},
+			},
+		},
+		Tags: common.Tags(ctx, name+"Nfs"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range subnets.Ids {
+		mt, err := efs.NewMountTarget(ctx, name+id, &efs.MountTargetArgs{
+			FileSystemId:   res.FileSystem.ID(),
+			SubnetId:       pulumi.String(id),
+			SecurityGroups: pulumi.StringArray{sg.ID()},
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+		res.MountTargets = append(res.MountTargets, mt)
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":       pulumi.String(res.Name),
+		"fileSystem": res.FileSystem.ID(),
+		"containers": pulumi.ToStringArray(args.Containers),
+	})
+}
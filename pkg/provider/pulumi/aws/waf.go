@@ -0,0 +1,142 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/wafv2"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+// minWafRateLimit is the fewest requests in 5 minutes a WAF rate based rule can allow.
+const minWafRateLimit = 100
+
+type WafArgs struct {
+	Waf *stack.Waf
+}
+
+type Waf struct {
+	pulumi.ResourceState
+
+	Name   string
+	WebAcl *wafv2.WebAcl
+}
+
+// validateWaf checks the waf's rate limit is one a rate based rule can have.
+func (a *awsProvider) validateWaf() error {
+	if err := common.ValidateWaf(a.sc); err != nil {
+		return err
+	}
+	if a.sc.Waf != nil && a.sc.Waf.RateLimit > 0 && a.sc.Waf.RateLimit < minWafRateLimit {
+		return fmt.Errorf("the waf rate limit must be at least %d requests on %s", minWafRateLimit, a.sc.Provider)
+	}
+	return nil
+}
+
+// newWaf creates a WAFv2 web ACL for the CloudFront distributions serving the apis, with a rule for each
+// managed rule group and the rate limit.
+func newWaf(ctx *pulumi.Context, name string, args *WafArgs, opts ...pulumi.ResourceOption) (*Waf, error) {
+	res := &Waf{Name: name}
+	err := ctx.RegisterComponentResource("nitric:waf:AwsWafV2", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	// the web ACLs of CloudFront distributions must be created in us-east-1
+	usEast1, err := aws.NewProvider(ctx, name+"UsEast1", &aws.ProviderArgs{
+		Region: pulumi.String("us-east-1"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := wafv2.WebAclRuleArray{}
+	for i, group := range args.Waf.ManagedRules {
+		override := &wafv2.WebAclRuleOverrideActionArgs{None: &wafv2.WebAclRuleOverrideActionNoneArgs{}}
+		if args.Waf.Monitor {
+			override = &wafv2.WebAclRuleOverrideActionArgs{Count: &wafv2.WebAclRuleOverrideActionCountArgs{}}
+		}
+		rules = append(rules, wafv2.WebAclRuleArgs{
+			Name:           pulumi.String(group),
+			Priority:       pulumi.Int(i),
+			OverrideAction: override,
+			Statement: wafv2.WebAclRuleStatementArgs{
+				ManagedRuleGroupStatement: &wafv2.WebAclRuleStatementManagedRuleGroupStatementArgs{
+					Name:       pulumi.String(group),
+					VendorName: pulumi.String("AWS"),
+				},
+			},
+			VisibilityConfig: wafv2.WebAclRuleVisibilityConfigArgs{
+				CloudwatchMetricsEnabled: pulumi.Bool(true),
+				MetricName:               pulumi.String(name + "-" + group),
+				SampledRequestsEnabled:   pulumi.Bool(true),
+			},
+		})
+	}
+
+	if args.Waf.RateLimit > 0 {
+		action := &wafv2.WebAclRuleActionArgs{Block: &wafv2.WebAclRuleActionBlockArgs{}}
+		if args.Waf.Monitor {
+			action = &wafv2.WebAclRuleActionArgs{Count: &wafv2.WebAclRuleActionCountArgs{}}
+		}
+		rules = append(rules, wafv2.WebAclRuleArgs{
+			Name:     pulumi.String("RateLimit"),
+			Priority: pulumi.Int(len(args.Waf.ManagedRules)),
+			Action:   action,
+			Statement: wafv2.WebAclRuleStatementArgs{
+				RateBasedStatement: &wafv2.WebAclRuleStatementRateBasedStatementArgs{
+					Limit:            pulumi.Int(args.Waf.RateLimit),
+					AggregateKeyType: pulumi.String("IP"),
+				},
+			},
+			VisibilityConfig: wafv2.WebAclRuleVisibilityConfigArgs{
+				CloudwatchMetricsEnabled: pulumi.Bool(true),
+				MetricName:               pulumi.String(name + "-RateLimit"),
+				SampledRequestsEnabled:   pulumi.Bool(true),
+			},
+		})
+	}
+
+	res.WebAcl, err = wafv2.NewWebAcl(ctx, name, &wafv2.WebAclArgs{
+		Scope: pulumi.String("CLOUDFRONT"),
+		DefaultAction: wafv2.WebAclDefaultActionArgs{
+			Allow: &wafv2.WebAclDefaultActionAllowArgs{},
+		},
+		Rules: rules,
+		VisibilityConfig: wafv2.WebAclVisibilityConfigArgs{
+			CloudwatchMetricsEnabled: pulumi.Bool(true),
+			MetricName:               pulumi.String(name),
+			SampledRequestsEnabled:   pulumi.Bool(true),
+		},
+		Tags: common.Tags(ctx, name),
+	}, append(opts, pulumi.Provider(usEast1))...)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":   pulumi.String(res.Name),
+		"webAcl": res.WebAcl,
+	})
+}
@@ -0,0 +1,210 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/iam"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/sfn"
+	"github.com/pulumi/pulumi-aws/sdk/v4/go/aws/sns"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+type WorkflowArgs struct {
+	Workflow project.Workflow
+	// The topic used to deliver the event for each step
+	StepTopics []string
+	Topics     map[string]*sns.Topic
+	// EventBus carries the topics instead of Topics when eventing is eventbridge
+	EventBus *EventBus
+	Funcs    map[string]*Lambda
+}
+
+type Workflow struct {
+	pulumi.ResourceState
+
+	Name         string
+	Role         *iam.Role
+	StateMachine *sfn.StateMachine
+}
+
+// stepMessage wraps the execution input in a nitric event, as the function is invoked as a topic subscriber.
+const stepMessage = `States.Format('\{"id":"{}","payload":{}\}', $$.Execution.Name, States.JsonToString($))`
+
+func newWorkflow(ctx *pulumi.Context, name string, args *WorkflowArgs, opts ...pulumi.ResourceOption) (*Workflow, error) {
+	res := &Workflow{Name: name}
+	err := ctx.RegisterComponentResource("nitric:workflow:AwsStepFunction", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	if len(args.Workflow.Steps) == 0 {
+		return nil, fmt.Errorf("workflow %s has no steps", name)
+	}
+
+	arns := pulumi.StringMap{}
+	for i, step := range args.Workflow.Steps {
+		fn, ok := args.Funcs[step.Function]
+		if !ok {
+			return nil, fmt.Errorf("workflow %s references function %s, but the function does not exist", name, step.Function)
+		}
+		arns["fn:"+step.Function] = fn.Function.Arn
+		if args.EventBus != nil {
+			continue
+		}
+		topic, ok := args.Topics[args.StepTopics[i]]
+		if !ok {
+			return nil, fmt.Errorf("workflow %s references topic %s, but the topic does not exist", name, args.StepTopics[i])
+		}
+		arns["topic:"+args.StepTopics[i]] = topic.Arn
+	}
+
+	tmpJSON, err := json.Marshal(map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{
+			{
+				"Sid":    "",
+				"Effect": "Allow",
+				"Principal": map[string]interface{}{
+					"Service": "states.amazonaws.com",
+				},
+				"Action": "sts:AssumeRole",
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res.Role, err = iam.NewRole(ctx, name+"WorkflowRole", &iam.RoleArgs{
+		AssumeRolePolicy: pulumi.String(tmpJSON),
+		Tags:             common.Tags(ctx, name+"WorkflowRole"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = iam.NewRolePolicy(ctx, name+"InvokeAccess", &iam.RolePolicyArgs{
+		Role: res.Role.ID(),
+		Policy: arns.ToStringMapOutput().ApplyT(func(arns map[string]string) (string, error) {
+			functions := []string{}
+			for k, arn := range arns {
+				if strings.HasPrefix(k, "fn:") {
+					functions = append(functions, arn)
+				}
+			}
+			sort.Strings(functions)
+			b, err := json.Marshal(map[string]interface{}{
+				"Version": "2012-10-17",
+				"Statement": []map[string]interface{}{
+					{
+						"Action":   []string{"lambda:InvokeFunction"},
+						"Effect":   "Allow",
+						"Resource": functions,
+					},
+				},
+			})
+			return string(b), err
+		}).(pulumi.StringOutput),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res.StateMachine, err = sfn.NewStateMachine(ctx, name, &sfn.StateMachineArgs{
+		RoleArn: res.Role.Arn,
+		Definition: arns.ToStringMapOutput().ApplyT(func(arns map[string]string) (string, error) {
+			return workflowDefinition(args.Workflow, args.StepTopics, arns, args.EventBus != nil)
+		}).(pulumi.StringOutput),
+		Tags: common.Tags(ctx, name),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":         pulumi.String(res.Name),
+		"stateMachine": res.StateMachine,
+	})
+}
+
+// workflowDefinition renders the workflow as a sequential Amazon States Language state machine.
+// Each step receives the execution input unchanged, the function results are discarded.
+// The functions are invoked with the event their topic delivers, an event bus event when eventBridge is set.
+func workflowDefinition(w project.Workflow, stepTopics []string, arns map[string]string, eventBridge bool) (string, error) {
+	stateName := func(i int) string {
+		return fmt.Sprintf("%d-%s", i+1, w.Steps[i].Function)
+	}
+
+	states := map[string]interface{}{}
+	for i, step := range w.Steps {
+		state := map[string]interface{}{
+			"Type":     "Task",
+			"Resource": "arn:aws:states:::lambda:invoke",
+			"Parameters": map[string]interface{}{
+				"FunctionName": arns["fn:"+step.Function],
+				"Payload":      stepPayload(stepTopics[i], arns, eventBridge),
+			},
+			"ResultPath": nil,
+		}
+		if i == len(w.Steps)-1 {
+			state["End"] = true
+		} else {
+			state["Next"] = stateName(i + 1)
+		}
+		states[stateName(i)] = state
+	}
+
+	b, err := json.Marshal(map[string]interface{}{
+		"StartAt": stateName(0),
+		"States":  states,
+	})
+	return string(b), err
+}
+
+// stepPayload is the event the step's function receives from the topic.
+func stepPayload(topic string, arns map[string]string, eventBridge bool) map[string]interface{} {
+	if eventBridge {
+		return map[string]interface{}{
+			"source":      eventSource,
+			"detail-type": topic,
+			"id.$":        "$$.Execution.Name",
+			"detail.$":    "States.StringToJson(" + stepMessage + ")",
+		}
+	}
+	return map[string]interface{}{
+		"Records": []map[string]interface{}{
+			{
+				"EventSource": "aws:sns",
+				"Sns": map[string]interface{}{
+					"TopicArn":    arns["topic:"+topic],
+					"MessageId.$": "$$.Execution.Name",
+					"Message.$":   stepMessage,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/insights"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+type AlertsArgs struct {
+	ResourceGroupName pulumi.StringInput
+	Location          pulumi.StringInput
+	Alerts            *stack.Alerts
+	Apps              map[string]*ContainerApp
+	Apis              map[string]*AzureApiManagement
+}
+
+type Alerts struct {
+	pulumi.ResourceState
+
+	Name        string
+	ActionGroup *insights.ActionGroup
+	Alerts      map[string]*insights.MetricAlert
+	Insights    *insights.Component
+	WebTests    map[string]*insights.WebTest
+}
+
+// newAlerts creates a metric alert for each error-rate rule and the uptime checks, emailing the address through
+// an action group.
+func newAlerts(ctx *pulumi.Context, name string, args *AlertsArgs, opts ...pulumi.ResourceOption) (*Alerts, error) {
+	res := &Alerts{Name: name, Alerts: map[string]*insights.MetricAlert{}, WebTests: map[string]*insights.WebTest{}}
+	err := ctx.RegisterComponentResource("nitric:alerts:AzureMonitor", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	shortName := ctx.Stack()
+	if len(shortName) > 12 {
+		shortName = shortName[:12]
+	}
+
+	res.ActionGroup, err = insights.NewActionGroup(ctx, resourceName(ctx, name, ActionGroupRT), &insights.ActionGroupArgs{
+		ResourceGroupName: args.ResourceGroupName,
+		Location:          pulumi.String("Global"),
+		GroupShortName:    pulumi.String(shortName),
+		Enabled:           pulumi.Bool(true),
+		EmailReceivers: insights.EmailReceiverArray{
+			insights.EmailReceiverArgs{
+				Name:                 pulumi.String("email"),
+				EmailAddress:         pulumi.String(args.Alerts.Email),
+				UseCommonAlertSchema: pulumi.Bool(true),
+			},
+		},
+		Tags: common.Tags(ctx, name),
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "action group")
+	}
+
+	for k, r := range args.Alerts.Rules {
+		if r.Metric != stack.AlertErrorRate {
+			_ = ctx.Log.Warn(fmt.Sprintf("Alert %s: %s alerts are not currently supported for Azure deployments", k, r.Metric), &pulumi.LogArgs{})
+			continue
+		}
+
+		minutes := r.MinutesOrDefault()
+
+		res.Alerts[k], err = insights.NewMetricAlert(ctx, resourceName(ctx, k, MetricAlertRT), &insights.MetricAlertArgs{
+			ResourceGroupName:   args.ResourceGroupName,
+			Location:            pulumi.String("global"),
+			Description:         pulumi.String(fmt.Sprintf("%s %s above %v", r.Function, r.Metric, r.Threshold)),
+			Severity:            pulumi.Int(2),
+			Enabled:             pulumi.Bool(true),
+			Scopes:              pulumi.StringArray{args.Apps[r.Function].App.ID()},
+			EvaluationFrequency: pulumi.String("PT1M"),
+			WindowSize:          pulumi.String(fmt.Sprintf("PT%dM", minutes)),
+			Criteria: insights.MetricAlertSingleResourceMultipleMetricCriteriaArgs{
+				OdataType: pulumi.String("Microsoft.Azure.Monitor.SingleResourceMultipleMetricCriteria"),
+				AllOf: insights.MetricCriteriaArray{
+					insights.MetricCriteriaArgs{
+						Name:            pulumi.String("errors"),
+						CriterionType:   pulumi.String("StaticThresholdCriterion"),
+						MetricName:      pulumi.String("Requests"),
+						Operator:        pulumi.String("GreaterThan"),
+						TimeAggregation: pulumi.String("Total"),
+						// the threshold is per minute, the total is over the whole window
+						Threshold: pulumi.Float64(r.Threshold * float64(minutes)),
+						Dimensions: insights.MetricDimensionArray{
+							insights.MetricDimensionArgs{
+								Name:     pulumi.String("statusCodeCategory"),
+								Operator: pulumi.String("Include"),
+								Values:   pulumi.StringArray{pulumi.String("5xx")},
+							},
+						},
+					},
+				},
+			},
+			Actions: insights.MetricAlertActionArray{
+				insights.MetricAlertActionArgs{ActionGroupId: res.ActionGroup.ID()},
+			},
+			Tags: common.Tags(ctx, k),
+		}, opts...)
+		if err != nil {
+			return nil, errors.WithMessage(err, "metric alert "+k)
+		}
+	}
+
+	if args.Alerts.Uptime != nil {
+		if err := res.newUptimeChecks(ctx, args, opts...); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":        pulumi.String(res.Name),
+		"actionGroup": res.ActionGroup,
+	})
+}
@@ -17,12 +17,22 @@
 package azure
 
 import (
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/pkg/errors"
 	apimanagement "github.com/pulumi/pulumi-azure-native/sdk/go/azure/apimanagement/v20201201"
 
 	//"github.com/pulumi/pulumi-azure-native/sdk/go/azure/apimanagement"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
 )
 
 type AzureApiManagementArgs struct {
@@ -31,6 +41,14 @@ type AzureApiManagementArgs struct {
 	AdminEmail        pulumi.StringInput
 	OpenAPISpec       *openapi3.T
 	Apps              map[string]*ContainerApp
+	// Limits are applied by rate-limit-by-key and quota-by-key policies
+	Limits *common.ApiLimits
+	// Tls sets the gateway's protocols and the oldest TLS version it accepts
+	Tls stack.Tls
+	// Requests bound the timeout and body size of the operations
+	Requests stack.ApiRequests
+	// Mock responds to every operation with the example of its response, rather than forwarding to the apps
+	Mock bool
 }
 
 type AzureApiManagement struct {
@@ -41,7 +59,80 @@ type AzureApiManagement struct {
 	Service *apimanagement.ApiManagementService
 }
 
-const policyTemplate = `<policies><inbound><base /><set-backend-service base-url="https://%s" /></inbound><backend><base /></backend><outbound><base /></outbound><on-error><base /></on-error></policies>`
+const policyTemplate = `<policies><inbound><base />%s<set-backend-service base-url="https://%s" /></inbound><backend>%s</backend><outbound><base /></outbound><on-error><base /></on-error></policies>`
+
+const mockPolicyTemplate = `<policies><inbound><base />%s<mock-response status-code="%d" content-type="%s" /></inbound><backend><base /></backend><outbound><base /></outbound><on-error><base /></on-error></policies>`
+
+const apiPolicyTemplate = `<policies><inbound><base />%s</inbound><backend><base /></backend><outbound><base /></outbound><on-error><base /></on-error></policies>`
+
+// quotaRenewal is the renewal period, in seconds, of each quota period.
+var quotaRenewal = map[string]int{
+	"day":   24 * 60 * 60,
+	"week":  7 * 24 * 60 * 60,
+	"month": 30 * 24 * 60 * 60,
+}
+
+// limitPolicies returns the policies that enforce the limit, counted by the key. The key and condition
+// are policy expressions or literal values.
+func limitPolicies(l *stack.RateLimit, key, condition string) string {
+	if l == nil {
+		return ""
+	}
+	attrs := fmt.Sprintf(`counter-key="%s"`, html.EscapeString(key))
+	if condition != "" {
+		attrs += fmt.Sprintf(` increment-condition="%s"`, html.EscapeString(condition))
+	}
+
+	policies := ""
+	if l.Rate > 0 {
+		// the rate is per second, APIM counts calls per renewal period
+		policies += fmt.Sprintf(`<rate-limit-by-key calls="%d" renewal-period="60" %s />`, int(math.Ceil(l.Rate*60)), attrs)
+	}
+	if l.Quota > 0 {
+		policies += fmt.Sprintf(`<quota-by-key calls="%d" renewal-period="%d" %s />`, l.Quota, quotaRenewal[l.Period], attrs)
+	}
+	return policies
+}
+
+// requestPolicies returns the inbound policy that rejects bodies over the limit, and the backend policy
+// that forwards the requests with the timeout.
+func requestPolicies(r stack.ApiRequests) (string, string) {
+	inbound := ""
+	if r.MaxBodyKB > 0 {
+		tooLarge := fmt.Sprintf(`@(long.Parse(context.Request.Headers.GetValueOrDefault("Content-Length","0")) > %d)`, r.MaxBodyKB*1024)
+		inbound = fmt.Sprintf(`<choose><when condition="%s"><return-response><set-status code="413" reason="Payload Too Large" /></return-response></when></choose>`, html.EscapeString(tooLarge))
+	}
+	backend := "<base />"
+	if r.Timeout > 0 {
+		backend = fmt.Sprintf(`<forward-request timeout="%d" />`, r.Timeout)
+	}
+	return inbound, backend
+}
+
+// mockPolicy returns the policy that responds to the operation with the example of its response, after
+// the inbound policies. APIM returns the example imported from the openapi spec.
+func mockPolicy(inbound string, op *openapi3.Operation) string {
+	r := project.OperationMockResponse(op)
+	return fmt.Sprintf(mockPolicyTemplate, inbound, r.Status, html.EscapeString(r.ContentType))
+}
+
+// schemeCredential returns the policy expression of the credential a security scheme is called with,
+// false when it can't be read by a policy.
+func schemeCredential(s *openapi3.SecurityScheme) (string, bool) {
+	switch s.Type {
+	case "apiKey":
+		switch s.In {
+		case "header":
+			return fmt.Sprintf(`context.Request.Headers.GetValueOrDefault("%s","")`, s.Name), true
+		case "query":
+			return fmt.Sprintf(`context.Request.Url.Query.GetValueOrDefault("%s","")`, s.Name), true
+		}
+		return "", false
+	case "http", "oauth2", "openIdConnect":
+		return `context.Request.Headers.GetValueOrDefault("Authorization","")`, true
+	}
+	return "", false
+}
 
 func newAzureApiManagement(ctx *pulumi.Context, name string, args *AzureApiManagementArgs, opts ...pulumi.ResourceOption) (*AzureApiManagement, error) {
 	res := &AzureApiManagement{Name: name}
@@ -50,14 +141,25 @@ func newAzureApiManagement(ctx *pulumi.Context, name string, args *AzureApiManag
 		return nil, err
 	}
 
+	sku := apimanagement.ApiManagementServiceSkuPropertiesArgs{
+		Name:     pulumi.String("Consumption"),
+		Capacity: pulumi.Int(0),
+	}
+	limited := args.Limits != nil && (args.Limits.RateLimited() || args.Limits.Quotas() || len(args.Limits.SecuritySchemes) > 0)
+	if limited {
+		// the consumption tier has no rate-limit-by-key or quota-by-key policies
+		sku = apimanagement.ApiManagementServiceSkuPropertiesArgs{
+			Name:     pulumi.String("Basic"),
+			Capacity: pulumi.Int(1),
+		}
+	}
+
 	res.Service, err = apimanagement.NewApiManagementService(ctx, resourceName(ctx, name, ApiManagementRT), &apimanagement.ApiManagementServiceArgs{
 		ResourceGroupName: args.ResourceGroupName,
 		PublisherEmail:    args.AdminEmail,
 		PublisherName:     args.OrgName,
-		Sku: apimanagement.ApiManagementServiceSkuPropertiesArgs{
-			Name:     pulumi.String("Consumption"),
-			Capacity: pulumi.Int(0),
-		},
+		Sku:               sku,
+		CustomProperties:  pulumi.ToStringMap(gatewayTlsProperties(args.Tls)),
 	})
 	if err != nil {
 		return nil, err
@@ -74,7 +176,7 @@ func newAzureApiManagement(ctx *pulumi.Context, name string, args *AzureApiManag
 
 	res.Api, err = apimanagement.NewApi(ctx, resourceName(ctx, name, ApiRT), &apimanagement.ApiArgs{
 		DisplayName:          pulumi.String(displayName),
-		Protocols:            apimanagement.ProtocolArray{"https"},
+		Protocols:            apiProtocols(args.Tls),
 		ApiId:                pulumi.String(name),
 		Format:               pulumi.String("openapi+json"),
 		Path:                 pulumi.String("/"),
@@ -92,8 +194,76 @@ func newAzureApiManagement(ctx *pulumi.Context, name string, args *AzureApiManag
 
 	ctx.Export("api:"+name, res.Api.ServiceUrl)
 
-	for _, pathItem := range args.OpenAPISpec.Paths {
-		for _, op := range pathItem.Operations() {
+	// this.api.id returns a URL path, which is the incorrect value here.
+	//   We instead need the value passed to apiId in the api creation above.
+	// However, we want to maintain the pulumi dependency, so we need to keep the 'apply' call.
+	apiId := res.Api.ID().ToStringOutput().ApplyT(func(id string) string {
+		return name
+	}).(pulumi.StringOutput)
+
+	if limited && len(args.Limits.SecuritySchemes) > 0 {
+		schemes := []string{}
+		for k := range args.Limits.SecuritySchemes {
+			schemes = append(schemes, k)
+		}
+		sort.Strings(schemes)
+
+		policies := []string{}
+		for _, k := range schemes {
+			l := args.Limits.SecuritySchemes[k]
+			s := args.OpenAPISpec.Components.SecuritySchemes[k]
+			if s == nil || s.Value == nil {
+				continue
+			}
+			credential, ok := schemeCredential(s.Value)
+			if !ok {
+				_ = ctx.Log.Warn(fmt.Sprintf("security scheme %s: the credential of %s %s schemes can't be limited", k, s.Value.In, s.Value.Type), &pulumi.LogArgs{})
+				continue
+			}
+			policies = append(policies, limitPolicies(&l, "@("+credential+")", "@("+credential+` != "")`))
+		}
+
+		_, err = apimanagement.NewApiPolicy(ctx, resourceName(ctx, name, ApiPolicyRT), &apimanagement.ApiPolicyArgs{
+			ResourceGroupName: args.ResourceGroupName,
+			ApiId:             apiId,
+			ServiceName:       res.Service.Name,
+			PolicyId:          pulumi.String("policy"),
+			Format:            pulumi.String("xml"),
+			Value:             pulumi.Sprintf(apiPolicyTemplate, strings.Join(policies, "")),
+		})
+		if err != nil {
+			return nil, errors.WithMessage(err, "NewApiPolicy "+name)
+		}
+	}
+
+	for path, pathItem := range args.OpenAPISpec.Paths {
+		for method, op := range pathItem.Operations() {
+			if args.Mock {
+				if op.OperationID == "" {
+					_ = ctx.Log.Warn(fmt.Sprintf("%s %s has no operationId, it can't be mocked", method, path), &pulumi.LogArgs{})
+					continue
+				}
+				limits := ""
+				if limited {
+					limits = limitPolicies(args.Limits.Route(method, path), name+"-"+op.OperationID, "")
+				}
+				bodyLimit, _ := requestPolicies(args.Requests)
+
+				_, err = apimanagement.NewApiOperationPolicy(ctx, resourceName(ctx, name+"-"+op.OperationID, ApiOperationPolicyRT), &apimanagement.ApiOperationPolicyArgs{
+					ResourceGroupName: args.ResourceGroupName,
+					ApiId:             apiId,
+					ServiceName:       res.Service.Name,
+					OperationId:       pulumi.String(op.OperationID),
+					PolicyId:          pulumi.String("policy"),
+					Format:            pulumi.String("xml"),
+					Value:             pulumi.String(mockPolicy(bodyLimit+limits, op)),
+				})
+				if err != nil {
+					return nil, errors.WithMessage(err, "NewApiOperationPolicy "+op.OperationID)
+				}
+				continue
+			}
+
 			if v, ok := op.Extensions["x-nitric-target"]; ok {
 				target := ""
 				targetMap, isMap := v.(map[string]string)
@@ -106,12 +276,11 @@ func newAzureApiManagement(ctx *pulumi.Context, name string, args *AzureApiManag
 					continue
 				}
 
-				// this.api.id returns a URL path, which is the incorrect value here.
-				//   We instead need the value passed to apiId in the api creation above.
-				// However, we want to maintain the pulumi dependency, so we need to keep the 'apply' call.
-				apiId := res.Api.ID().ToStringOutput().ApplyT(func(id string) string {
-					return name
-				}).(pulumi.StringOutput)
+				limits := ""
+				if limited {
+					limits = limitPolicies(args.Limits.Route(method, path), name+"-"+op.OperationID, "")
+				}
+				bodyLimit, backend := requestPolicies(args.Requests)
 
 				_ = ctx.Log.Info("op policy "+op.OperationID+" , name "+name, &pulumi.LogArgs{Ephemeral: true})
 
@@ -122,7 +291,7 @@ func newAzureApiManagement(ctx *pulumi.Context, name string, args *AzureApiManag
 					OperationId:       pulumi.String(op.OperationID),
 					PolicyId:          pulumi.String("policy"),
 					Format:            pulumi.String("xml"),
-					Value:             pulumi.Sprintf(policyTemplate, app.App.LatestRevisionFqdn),
+					Value:             pulumi.Sprintf(policyTemplate, bodyLimit+limits, app.App.LatestRevisionFqdn, backend),
 				})
 				if err != nil {
 					return nil, errors.WithMessage(err, "NewApiOperationPolicy "+op.OperationID)
@@ -0,0 +1,74 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func Test_requestPolicies(t *testing.T) {
+	tests := []struct {
+		name        string
+		requests    stack.ApiRequests
+		wantInbound string
+		wantBackend string
+	}{
+		{
+			name:        "defaults",
+			wantBackend: "<base />",
+		},
+		{
+			name:        "timeout",
+			requests:    stack.ApiRequests{Timeout: 120},
+			wantBackend: `<forward-request timeout="120" />`,
+		},
+		{
+			name:        "body limit",
+			requests:    stack.ApiRequests{MaxBodyKB: 1},
+			wantInbound: `<choose><when condition="@(long.Parse(context.Request.Headers.GetValueOrDefault(&#34;Content-Length&#34;,&#34;0&#34;)) &gt; 1024)"><return-response><set-status code="413" reason="Payload Too Large" /></return-response></when></choose>`,
+			wantBackend: "<base />",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inbound, backend := requestPolicies(tt.requests)
+			if inbound != tt.wantInbound {
+				t.Errorf("requestPolicies() inbound = %v, want %v", inbound, tt.wantInbound)
+			}
+			if backend != tt.wantBackend {
+				t.Errorf("requestPolicies() backend = %v, want %v", backend, tt.wantBackend)
+			}
+		})
+	}
+}
+
+func Test_mockPolicy(t *testing.T) {
+	op := &openapi3.Operation{Responses: openapi3.Responses{
+		"201": &openapi3.ResponseRef{Value: &openapi3.Response{Content: openapi3.Content{
+			"application/json": &openapi3.MediaType{Example: map[string]string{"id": "1"}},
+		}}},
+	}}
+
+	want := `<policies><inbound><base /><rate-limit /><mock-response status-code="201" content-type="application/json" /></inbound><backend><base /></backend><outbound><base /></outbound><on-error><base /></on-error></policies>`
+	if got := mockPolicy("<rate-limit />", op); got != want {
+		t.Errorf("mockPolicy() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+// blobAPIVersion is the first version that sets the immutability policy of a blob as it is written
+const blobAPIVersion = "2020-06-12"
+
+var _ common.StateArchiver = &azureProvider{}
+
+// ArchiveState writes the snapshot to the container of the bucket, account/container, with a locked
+// immutability policy until retainUntil.
+func (a *azureProvider) ArchiveState(bucket, key string, body []byte, retainUntil time.Time) error {
+	parts := strings.SplitN(bucket, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("the archive bucket %s must be a storage account and container, e.g. audits/snapshots", bucket)
+	}
+
+	token, err := azToken("https://storage.azure.com")
+	if err != nil {
+		return errors.WithMessage(err, "storage token")
+	}
+
+	u := fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", parts[0], parts[1], key)
+	req, err := http.NewRequest(http.MethodPut, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-ms-version", blobAPIVersion)
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	if !retainUntil.IsZero() {
+		req.Header.Set("x-ms-immutability-policy-until-date", retainUntil.UTC().Format(http.TimeFormat))
+		req.Header.Set("x-ms-immutability-policy-mode", "Locked")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("writing %s failed with %s: %s", u, resp.Status, b)
+	}
+	return nil
+}
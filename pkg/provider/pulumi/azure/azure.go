@@ -29,8 +29,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/authorization"
 	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/eventgrid"
-	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/keyvault"
 	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/resources"
+	pulumiEventgrid "github.com/pulumi/pulumi-azure/sdk/v4/go/azure/eventgrid"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
@@ -47,6 +47,10 @@ type azureProvider struct {
 	tmpDir     string
 	org        string
 	adminEmail string
+	// names are the names of the deployed resources when the stack uses stable naming
+	names *common.StableNames
+	// deployedImages are the images pushed by the last update, keyed by source image, when they are reused
+	deployedImages map[string]string
 }
 
 var (
@@ -56,6 +60,8 @@ var (
 	azureADPluginVersion string
 	//go:embed pulumi-azure-native-version.txt
 	azureNativePluginVersion string
+	//go:embed pulumi-random-version.txt
+	randomPluginVersion string
 )
 
 func New(s *project.Project, t *stack.Config, envMap map[string]string) common.PulumiProvider {
@@ -80,6 +86,10 @@ func (a *azureProvider) Plugins() []common.Plugin {
 			Name:    "azuread",
 			Version: strings.TrimSpace(azureADPluginVersion),
 		},
+		{
+			Name:    "random",
+			Version: strings.TrimSpace(randomPluginVersion),
+		},
 	}
 }
 
@@ -134,6 +144,44 @@ func (a *azureProvider) SupportedRegions() []string {
 	}
 }
 
+func (a *azureProvider) SetStableNames(names *common.StableNames) {
+	a.names = names
+}
+
+var _ common.ImageReuser = &azureProvider{}
+
+func (a *azureProvider) ReuseImages(images map[string]string) {
+	a.deployedImages = images
+}
+
+func (a *azureProvider) Capabilities() common.Capabilities {
+	return common.CapabilitiesExcept(
+		// NOTE: Currently CRONTAB support is required, we either need to revisit the design of
+		// our scheduled expressions or implement a workaround or request a feature.
+		common.CapabilitySchedules,
+		common.CapabilityWorkflows,
+		common.CapabilityLayers,
+		// azure files storage is only mounted by environments from a newer container apps API than we use
+		common.CapabilityVolumes,
+		// container apps jobs are only in newer API versions than the container apps API we use
+		common.CapabilityJobs,
+		// topics are always event grid topics
+		common.CapabilityEventBridge,
+		// validate-jwt policies are only added to the api gateways on gcp so far
+		common.CapabilityJwt,
+	)
+}
+
+// hasBucketEvents is true when any function is triggered by bucket events.
+func (a *azureProvider) hasBucketEvents() bool {
+	for _, c := range a.proj.Computes() {
+		if len(c.Unit().Triggers.Buckets) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *azureProvider) Validate() error {
 	errList := utils.NewErrorList()
 
@@ -155,6 +203,24 @@ func (a *azureProvider) Validate() error {
 		a.adminEmail = a.sc.Extra["adminemail"].(string)
 	}
 
+	errList.Add(common.ValidateEnv(a.sc.Provider, containerAppReservedEnv, a.envMap))
+	errList.Add(common.ValidateSleep(a.sc.Sleep))
+	errList.Add(common.ValidateKeepWarm(a.sc.KeepWarm, a.proj, 0))
+	errList.Add(validateComputeClasses(a.proj))
+	errList.Add(common.ValidateObservability(a.sc.Observability))
+	errList.Add(common.ValidateAlerts(a.sc.Alerts, a.proj))
+	errList.Add(common.ValidateBudget(a.sc.Budget))
+	errList.Add(validateTmpSizes(a.proj))
+	errList.Add(validateSubscriptions(a.sc.Subscriptions))
+	errList.Add(validateKeyVault(a.sc.KeyVault))
+	errList.Add(validateRevisions(a.proj, a.sc.Revisions))
+	errList.Add(validateExistingTopics(a.proj, a.sc.ExistingTopics))
+	errList.Add(validateWaf(a.sc))
+	errList.Add(common.ValidateTls(a.sc.Tls))
+	errList.Add(validateDnsZones(a.sc.Cdn))
+	// API Management forwards requests for 240 seconds at most
+	errList.Add(common.ValidateApiRequestLimits(a.sc.Provider, a.sc.ApiRequests, 240, 0))
+
 	return errList.Aggregate()
 }
 
@@ -189,6 +255,10 @@ func (a *azureProvider) Deploy(ctx *pulumi.Context) error {
 		return err
 	}
 
+	if err := common.ValidateSubscriptions(a.sc.Subscriptions, a.proj); err != nil {
+		return err
+	}
+
 	clientConfig, err := authorization.GetClientConfig(ctx)
 	if err != nil {
 		return err
@@ -213,42 +283,25 @@ func (a *azureProvider) Deploy(ctx *pulumi.Context) error {
 
 	// Create a stack level keyvault if secrets are enabled
 	// At the moment secrets have no config level setting
-	kvName := resourceName(ctx, "", KeyVaultRT)
-	kv, err := keyvault.NewVault(ctx, kvName, &keyvault.VaultArgs{
-		Location:          rg.Location,
-		ResourceGroupName: rg.Name,
-		Properties: &keyvault.VaultPropertiesArgs{
-			EnableSoftDelete:        pulumi.Bool(false),
-			EnableRbacAuthorization: pulumi.Bool(true),
-			Sku: &keyvault.SkuArgs{
-				Family: pulumi.String("A"),
-				Name:   keyvault.SkuNameStandard,
-			},
-			TenantId: pulumi.String(clientConfig.TenantId),
-		},
-		Tags: common.Tags(ctx, kvName),
-	})
-
+	kv, err := a.newKeyVault(ctx, rg, clientConfig)
 	if err != nil {
-		return err
+		return errors.WithMessage(err, "key vault create")
 	}
 	contAppsArgs.KVaultName = kv.Name
 
+	var sr *Storage
 	if len(a.proj.Buckets) > 0 || len(a.proj.Queues) > 0 {
-		sr, err := a.newStorageResources(ctx, "storage", &StorageArgs{ResourceGroupName: rg.Name})
+		sr, err = a.newStorageResources(ctx, "storage", &StorageArgs{ResourceGroupName: rg.Name, Tls: a.sc.TlsOrDefault()})
 		if err != nil {
 			return errors.WithMessage(err, "storage create")
 		}
 		contAppsArgs.StorageAccountBlobEndpoint = sr.Account.PrimaryEndpoints.Blob()
 		contAppsArgs.StorageAccountQueueEndpoint = sr.Account.PrimaryEndpoints.Queue()
+		contAppsArgs.Containers = sr.Containers
 	}
 
 	for k := range a.proj.Topics {
-		contAppsArgs.Topics[k], err = eventgrid.NewTopic(ctx, resourceName(ctx, k, EventGridRT), &eventgrid.TopicArgs{
-			ResourceGroupName: rg.Name,
-			Location:          rg.Location,
-			Tags:              common.Tags(ctx, k),
-		})
+		contAppsArgs.Topics[k], err = a.newTopic(ctx, rg, k)
 		if err != nil {
 			return errors.WithMessage(err, "eventgrid topic "+k)
 		}
@@ -265,6 +318,47 @@ func (a *azureProvider) Deploy(ctx *pulumi.Context) error {
 		contAppsArgs.MongoDatabaseConnectionString = mc.ConnectionString
 	}
 
+	if len(a.proj.Databases) > 0 {
+		contAppsArgs.Databases = map[string]*PostgresDatabase{}
+		for k, db := range a.proj.Databases {
+			contAppsArgs.Databases[k], err = newPostgresDatabase(ctx, k, &PostgresDatabaseArgs{
+				ResourceGroupName: rg.Name,
+				Location:          rg.Location,
+				Database:          db,
+			})
+			if err != nil {
+				return errors.WithMessage(err, "database "+k)
+			}
+		}
+	}
+
+	if len(a.proj.Caches) > 0 {
+		contAppsArgs.Caches = map[string]*RedisCache{}
+		for k, c := range a.proj.Caches {
+			contAppsArgs.Caches[k], err = newRedisCache(ctx, k, &RedisCacheArgs{
+				ResourceGroupName: rg.Name,
+				Location:          rg.Location,
+				Cache:             c,
+			})
+			if err != nil {
+				return errors.WithMessage(err, "cache "+k)
+			}
+		}
+	}
+
+	if len(a.proj.Emails) > 0 {
+		contAppsArgs.Emails = map[string]*CommunicationService{}
+		for k, e := range a.proj.Emails {
+			contAppsArgs.Emails[k], err = newCommunicationService(ctx, k, &CommunicationServiceArgs{
+				ResourceGroupName: rg.Name,
+				Email:             e,
+			})
+			if err != nil {
+				return errors.WithMessage(err, "email "+k)
+			}
+		}
+	}
+
 	var apps *ContainerApps
 	if len(a.proj.Functions) > 0 || len(a.proj.Containers) > 0 {
 		apps, err = a.newContainerApps(ctx, "containerApps", contAppsArgs)
@@ -273,34 +367,126 @@ func (a *azureProvider) Deploy(ctx *pulumi.Context) error {
 		}
 	}
 
-	_, err = newSubscriptions(ctx, "subscriptions", &SubscriptionsArgs{
+	subArgs := &SubscriptionsArgs{
 		ResourceGroupName: rg.Name,
 		Apps:              apps.Apps,
-	})
-	if err != nil {
-		return errors.WithMessage(err, "subscripitons")
+		Subscriptions:     a.sc.Subscriptions,
+	}
+	if sr != nil && a.hasBucketEvents() {
+		// storage events are published to a system topic of the storage account
+		subArgs.Containers = sr.Containers
+		subArgs.StorageTopic, err = pulumiEventgrid.NewSystemTopic(ctx, resourceName(ctx, "storage", SystemTopicRT), &pulumiEventgrid.SystemTopicArgs{
+			ResourceGroupName:   rg.Name,
+			Location:            rg.Location,
+			SourceArmResourceId: sr.Account.ID().ToStringOutput(),
+			TopicType:           pulumi.String("Microsoft.Storage.StorageAccounts"),
+			Tags:                common.Tags(ctx, "storage"),
+		})
+		if err != nil {
+			return errors.WithMessage(err, "storage system topic")
+		}
 	}
 
-	// TODO: Add schedule support
-	// NOTE: Currently CRONTAB support is required, we either need to revisit the design of
-	// our scheduled expressions or implement a workaround or request a feature.
-	if len(a.proj.Schedules) > 0 {
-		_ = ctx.Log.Warn("Schedules are not currently supported for Azure deployments", &pulumi.LogArgs{})
+	_, err = newSubscriptions(ctx, "subscriptions", subArgs)
+	if err != nil {
+		return errors.WithMessage(err, "subscripitons")
 	}
 
+	apis := map[string]*AzureApiManagement{}
 	for k, v := range a.proj.ApiDocs {
-		_, err = newAzureApiManagement(ctx, k, &AzureApiManagementArgs{
+		limits, err := common.ApiRateLimits(v, common.StackThrottling(a.sc, k))
+		if err != nil {
+			return errors.WithMessage(err, "gateway "+k)
+		}
+		apis[k], err = newAzureApiManagement(ctx, k, &AzureApiManagementArgs{
 			ResourceGroupName: rg.Name,
 			OrgName:           pulumi.String(a.org),
 			AdminEmail:        pulumi.String(a.adminEmail),
 			OpenAPISpec:       v,
 			Apps:              apps.Apps,
+			Limits:            limits,
+			Tls:               a.sc.TlsOrDefault(),
+			Requests:          a.sc.ApiRequests[k],
+			Mock:              a.proj.Mock,
 		})
 		if err != nil {
 			return errors.WithMessage(err, "gateway "+k)
 		}
 	}
 
+	sites := map[string]*Site{}
+	if len(a.proj.Sites) > 0 {
+		endpoints := map[string]pulumi.StringInput{}
+		for k, api := range apis {
+			endpoints[k] = api.Api.ServiceUrl.Elem()
+		}
+		for k, site := range a.proj.Sites {
+			sites[k], err = newSite(ctx, k, &SiteArgs{
+				ResourceGroupName: rg.Name,
+				ProjectDir:        a.proj.Dir,
+				Site:              site,
+				Env:               common.SiteEnv(endpoints),
+				Tls:               a.sc.TlsOrDefault(),
+			})
+			if err != nil {
+				return errors.WithMessage(err, "site "+k)
+			}
+		}
+	}
+
+	for k, minutes := range a.sc.KeepWarm {
+		app, ok := apps.Apps[k]
+		if !ok {
+			return fmt.Errorf("keepWarm configured for function %s, but the function does not exist", k)
+		}
+		_, err = newKeepWarm(ctx, k+"-keepwarm", &KeepWarmArgs{
+			ResourceGroupName: rg.Name,
+			Location:          rg.Location,
+			Minutes:           minutes,
+			App:               app,
+		})
+		if err != nil {
+			return errors.WithMessage(err, "keepWarm "+k)
+		}
+	}
+
+	if a.sc.Alerts != nil && (len(a.sc.Alerts.Rules) > 0 || a.sc.Alerts.Uptime != nil) {
+		_, err = newAlerts(ctx, "alerts", &AlertsArgs{
+			ResourceGroupName: rg.Name,
+			Location:          rg.Location,
+			Alerts:            a.sc.Alerts,
+			Apps:              apps.Apps,
+			Apis:              apis,
+		})
+		if err != nil {
+			return errors.WithMessage(err, "alerts")
+		}
+	}
+
+	if a.sc.Budget != nil {
+		_, err = newBudget(ctx, "budget", &BudgetArgs{
+			ResourceGroup: rg,
+			Budget:        a.sc.Budget,
+		})
+		if err != nil {
+			return errors.WithMessage(err, "budget")
+		}
+	}
+
+	if a.sc.Cdn != nil {
+		_, err = newFrontDoor(ctx, "cdn", &FrontDoorArgs{
+			ResourceGroupName: rg.Name,
+			Cdn:               a.sc.Cdn,
+			Apis:              apis,
+			Storage:           sr,
+			Sites:             sites,
+			Waf:               a.sc.Waf,
+		})
+		if err != nil {
+			return errors.WithMessage(err, "front door")
+		}
+	}
+
 	return nil
 }
 
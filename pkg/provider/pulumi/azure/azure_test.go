@@ -28,6 +28,7 @@ func Test_azureProvider_Plugins(t *testing.T) {
 		{Name: "azure-native", Version: "v1.60.0"},
 		{Name: "azure", Version: "v4.39.0"},
 		{Name: "azuread", Version: "v5.17.0"},
+		{Name: "random", Version: "v4.4.2"},
 	}
 	got := (&azureProvider{}).Plugins()
 	if !reflect.DeepEqual(got, want) {
@@ -0,0 +1,122 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/authorization"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
+)
+
+const blobDataAction = "Microsoft.Storage/storageAccounts/blobServices/containers/blobs/"
+
+// blobDataActions are the data actions of the custom role that allow each bucket action. Listing and
+// reading files are both allowed by blobs/read, blob storage has no data action for listing alone.
+var blobDataActions = map[v1.Action][]string{
+	v1.Action_BucketFileList:   {blobDataAction + "read"},
+	v1.Action_BucketFileGet:    {blobDataAction + "read"},
+	v1.Action_BucketFilePut:    {blobDataAction + "write", blobDataAction + "add/action"},
+	v1.Action_BucketFileDelete: {blobDataAction + "delete"},
+}
+
+// bucketDataActions returns the data actions the policies allow the compute on each bucket, keyed by
+// bucket name.
+func bucketDataActions(policies []*v1.PolicyResource, compute string) map[string][]string {
+	allowed := map[string]map[string]bool{}
+	for _, p := range policies {
+		principal := false
+		for _, pr := range p.Principals {
+			if pr.Type == v1.ResourceType_Function && pr.Name == compute {
+				principal = true
+				break
+			}
+		}
+		if !principal {
+			continue
+		}
+
+		for _, r := range p.Resources {
+			if r.Type != v1.ResourceType_Bucket {
+				continue
+			}
+			for _, a := range p.Actions {
+				for _, da := range blobDataActions[a] {
+					if allowed[r.Name] == nil {
+						allowed[r.Name] = map[string]bool{}
+					}
+					allowed[r.Name][da] = true
+				}
+			}
+		}
+	}
+
+	actions := map[string][]string{}
+	for bucket, das := range allowed {
+		for da := range das {
+			actions[bucket] = append(actions[bucket], da)
+		}
+		sort.Strings(actions[bucket])
+	}
+	return actions
+}
+
+// assignBucketRoles assigns the app a custom role on each bucket's container with only the data actions
+// its policies allow there.
+func assignBucketRoles(ctx *pulumi.Context, res *ContainerApp, args *ContainerAppArgs) error {
+	buckets := []string{}
+	for b := range args.BucketActions {
+		buckets = append(buckets, b)
+	}
+	sort.Strings(buckets)
+
+	for _, b := range buckets {
+		container, ok := args.Containers[b]
+		if !ok {
+			continue
+		}
+
+		role, err := authorization.NewRoleDefinition(ctx, resourceName(ctx, res.Name+b, RoleDefinitionRT), &authorization.RoleDefinitionArgs{
+			RoleName:         pulumi.Sprintf("%s-%s-%s-%s", ctx.Project(), ctx.Stack(), res.Name, b),
+			Description:      pulumi.String(fmt.Sprintf("the access of %s to the %s bucket", res.Name, b)),
+			Scope:            container.ID(),
+			AssignableScopes: pulumi.StringArray{container.ID()},
+			Permissions: authorization.PermissionArray{
+				authorization.PermissionArgs{
+					DataActions: pulumi.ToStringArray(args.BucketActions[b]),
+				},
+			},
+		}, pulumi.Parent(res))
+		if err != nil {
+			return err
+		}
+
+		_, err = authorization.NewRoleAssignment(ctx, resourceName(ctx, res.Name+b, AssignmentRT), &authorization.RoleAssignmentArgs{
+			PrincipalId:      res.Sp.ServicePrincipalId,
+			PrincipalType:    pulumi.StringPtr("ServicePrincipal"),
+			RoleDefinitionId: pulumi.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", args.SubscriptionID, role.Name),
+			Scope:            container.ID(),
+		}, pulumi.Parent(res))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
+)
+
+func Test_bucketDataActions(t *testing.T) {
+	fn := func(name string) *v1.Resource {
+		return &v1.Resource{Name: name, Type: v1.ResourceType_Function}
+	}
+	bucket := func(name string) *v1.Resource {
+		return &v1.Resource{Name: name, Type: v1.ResourceType_Bucket}
+	}
+	policies := []*v1.PolicyResource{
+		{
+			Principals: []*v1.Resource{fn("gallery")},
+			Actions:    []v1.Action{v1.Action_BucketFileGet, v1.Action_BucketFileList},
+			Resources:  []*v1.Resource{bucket("images")},
+		},
+		{
+			Principals: []*v1.Resource{fn("gallery"), fn("uploads")},
+			Actions:    []v1.Action{v1.Action_BucketFilePut, v1.Action_TopicEventPublish},
+			Resources:  []*v1.Resource{bucket("thumbnails"), {Name: "resized", Type: v1.ResourceType_Topic}},
+		},
+		{
+			Principals: []*v1.Resource{fn("uploads")},
+			Actions:    []v1.Action{v1.Action_BucketFileDelete},
+			Resources:  []*v1.Resource{bucket("images")},
+		},
+	}
+
+	want := map[string][]string{
+		"images":     {blobDataAction + "read"},
+		"thumbnails": {blobDataAction + "add/action", blobDataAction + "write"},
+	}
+	if got := bucketDataActions(policies, "gallery"); !reflect.DeepEqual(got, want) {
+		t.Errorf("bucketDataActions() = %v, want %v", got, want)
+	}
+
+	if got := bucketDataActions(policies, "unknown"); len(got) != 0 {
+		t.Errorf("bucketDataActions() = %v, want no buckets", got)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/consumption"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/insights"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/resources"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+type BudgetArgs struct {
+	ResourceGroup *resources.ResourceGroup
+	Budget        *stack.Budget
+}
+
+type Budget struct {
+	pulumi.ResourceState
+
+	Name        string
+	ActionGroup *insights.ActionGroup
+	Budget      *consumption.Budget
+}
+
+// newBudget creates a monthly cost budget of the stack's resource group, emailing the addresses directly and
+// posting to the webhook through an action group.
+func newBudget(ctx *pulumi.Context, name string, args *BudgetArgs, opts ...pulumi.ResourceOption) (*Budget, error) {
+	res := &Budget{Name: name}
+	err := ctx.RegisterComponentResource("nitric:budget:AzureBudget", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	groups := pulumi.StringArray{}
+	if args.Budget.Webhook != "" {
+		shortName := ctx.Stack()
+		if len(shortName) > 12 {
+			shortName = shortName[:12]
+		}
+
+		res.ActionGroup, err = insights.NewActionGroup(ctx, resourceName(ctx, name, ActionGroupRT), &insights.ActionGroupArgs{
+			ResourceGroupName: args.ResourceGroup.Name,
+			Location:          pulumi.String("Global"),
+			GroupShortName:    pulumi.String(shortName),
+			Enabled:           pulumi.Bool(true),
+			WebhookReceivers: insights.WebhookReceiverArray{
+				insights.WebhookReceiverArgs{
+					Name:                 pulumi.String("webhook"),
+					ServiceUri:           pulumi.String(args.Budget.Webhook),
+					UseCommonAlertSchema: pulumi.Bool(true),
+				},
+			},
+			Tags: common.Tags(ctx, name),
+		}, opts...)
+		if err != nil {
+			return nil, errors.WithMessage(err, "action group")
+		}
+		groups = append(groups, res.ActionGroup.ID())
+	}
+
+	notifications := consumption.NotificationMap{}
+	for _, t := range args.Budget.ThresholdsOrDefault() {
+		notifications[fmt.Sprintf("actual_GreaterThanOrEqualTo_%v_Percent", t)] = consumption.NotificationArgs{
+			Enabled:       pulumi.Bool(true),
+			Operator:      pulumi.String("GreaterThanOrEqualTo"),
+			Threshold:     pulumi.Float64(t),
+			ContactEmails: pulumi.ToStringArray(args.Budget.Emails),
+			ContactGroups: groups,
+		}
+	}
+
+	// budgets start on the first of a month, the start is kept as it is rejected when it is changed
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	res.Budget, err = consumption.NewBudget(ctx, resourceName(ctx, name, BudgetRT), &consumption.BudgetArgs{
+		Scope:     args.ResourceGroup.ID(),
+		Amount:    pulumi.Float64(args.Budget.Amount),
+		Category:  pulumi.String("Cost"),
+		TimeGrain: pulumi.String("Monthly"),
+		TimePeriod: consumption.BudgetTimePeriodArgs{
+			StartDate: pulumi.String(start.Format(time.RFC3339)),
+		},
+		Notifications: notifications,
+	}, append(opts, pulumi.IgnoreChanges([]string{"timePeriod"}))...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "budget")
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":   pulumi.String(res.Name),
+		"budget": res.Budget.Name,
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/cache"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+type RedisCacheArgs struct {
+	ResourceGroupName pulumi.StringInput
+	Location          pulumi.StringInput
+	Cache             project.Cache
+}
+
+type RedisCache struct {
+	pulumi.ResourceState
+
+	Name  string
+	Redis *cache.Redis
+	URL   pulumi.StringOutput
+}
+
+// newRedisCache creates an Azure Cache for Redis that only accepts TLS connections.
+func newRedisCache(ctx *pulumi.Context, name string, args *RedisCacheArgs, opts ...pulumi.ResourceOption) (*RedisCache, error) {
+	res := &RedisCache{Name: name}
+	err := ctx.RegisterComponentResource("nitric:cache:AzureRedis", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	res.Redis, err = cache.NewRedis(ctx, resourceName(ctx, name, RedisCacheRT), &cache.RedisArgs{
+		ResourceGroupName: args.ResourceGroupName,
+		Location:          args.Location,
+		EnableNonSslPort:  pulumi.Bool(false),
+		MinimumTlsVersion: pulumi.String("1.2"),
+		Sku: &cache.SkuArgs{
+			Name:     pulumi.String("Basic"),
+			Family:   pulumi.String("C"),
+			Capacity: pulumi.Int(0),
+		},
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "redis cache")
+	}
+
+	res.URL = pulumi.ToSecret(pulumi.All(args.ResourceGroupName, res.Redis.Name, res.Redis.HostName, res.Redis.SslPort).ApplyT(func(args []interface{}) (string, error) {
+		rgName := args[0].(string)
+		redisName := args[1].(string)
+		hostName := args[2].(string)
+		sslPort := args[3].(int)
+
+		keys, err := cache.ListRedisKeys(ctx, &cache.ListRedisKeysArgs{
+			ResourceGroupName: rgName,
+			Name:              redisName,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("rediss://:%s@%s:%d", keys.PrimaryKey, hostName, sslPort), nil
+	})).(pulumi.StringOutput)
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":  pulumi.String(res.Name),
+		"redis": res.Redis.Name,
+	})
+}
+
+// cacheSecretName is the container app secret holding the cache URL.
+func cacheSecretName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(project.CacheEnvPrefix(name)), "_", "-") + "-url"
+}
+
+// cacheEnv are the env vars used to connect to a cache.
+func cacheEnv(name string) []types.EnvVar {
+	return []types.EnvVar{
+		{Name: project.CacheEnvPrefix(name) + "_URL", Value: cacheSecretName(name), Source: types.EnvSourceSecret},
+	}
+}
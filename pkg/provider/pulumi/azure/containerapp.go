@@ -24,11 +24,16 @@ import (
 	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/containerregistry"
 	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/eventgrid"
 	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/operationalinsights"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/storage"
 	web "github.com/pulumi/pulumi-azure-native/sdk/go/azure/web/v20210301"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
 	"github.com/nitrictech/cli/pkg/project"
 	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
 )
 
 type ContainerAppsArgs struct {
@@ -44,6 +49,12 @@ type ContainerAppsArgs struct {
 	StorageAccountQueueEndpoint   pulumi.StringInput
 	MongoDatabaseName             pulumi.StringInput
 	MongoDatabaseConnectionString pulumi.StringInput
+
+	Databases map[string]*PostgresDatabase
+	Caches    map[string]*RedisCache
+	Emails    map[string]*CommunicationService
+	// Containers are the blob containers of the buckets, keyed by bucket name
+	Containers map[string]*storage.BlobContainer
 }
 
 type ContainerApps struct {
@@ -177,6 +188,19 @@ func (a *azureProvider) newContainerApps(ctx *pulumi.Context, name string, args
 	}).(pulumi.StringPtrOutput)
 
 	for _, c := range a.proj.Computes() {
+		databases := map[string]*PostgresDatabase{}
+		for _, k := range a.proj.DatabasesFor(c.Unit().Name) {
+			databases[k] = args.Databases[k]
+		}
+		caches := map[string]*RedisCache{}
+		for _, k := range a.proj.CachesFor(c.Unit().Name) {
+			caches[k] = args.Caches[k]
+		}
+		emails := map[string]*CommunicationService{}
+		for _, k := range a.proj.EmailsFor(c.Unit().Name) {
+			emails[k] = args.Emails[k]
+		}
+
 		localImageName := c.ImageTagName(a.proj, "")
 		repositoryUrl := pulumi.Sprintf("%s/%s", res.Registry.LoginServer, c.ImageTagName(a.proj, a.sc.Provider))
 
@@ -187,7 +211,8 @@ func (a *azureProvider) newContainerApps(ctx *pulumi.Context, name string, args
 			Username:        adminUser.Elem(),
 			Password:        adminPass.Elem(),
 			Server:          res.Registry.LoginServer,
-			TempDir:         a.tmpDir}, pulumi.Parent(res))
+			TempDir:         a.tmpDir,
+			Deployed:        a.deployedImages[c.ImageTagName(a.proj, a.sc.Provider)]}, pulumi.Parent(res))
 		if err != nil {
 			return nil, errors.WithMessage(err, "function image tag "+c.Unit().Name)
 		}
@@ -197,13 +222,22 @@ func (a *azureProvider) newContainerApps(ctx *pulumi.Context, name string, args
 			Location:          args.Location,
 			SubscriptionID:    args.SubscriptionID,
 			Registry:          res.Registry,
-			RegistryUser:      adminUser,
-			RegistryPass:      adminPass,
 			KubeEnv:           kube,
 			ImageUri:          image.DockerImage.ImageName,
 			Env:               env,
 			Topics:            args.Topics,
 			Compute:           c,
+			Dapr:              a.sc.Dapr,
+			Sleep:             a.sc.Sleep,
+			Databases:         databases,
+			Caches:            caches,
+			Emails:            emails,
+			MaxConcurrency:    a.sc.MaxConcurrency(c.Unit().Name),
+			Tls:               a.sc.TlsOrDefault(),
+			Revisions:         a.sc.Revisions[c.Unit().Name],
+			ExistingTopics:    a.sc.ExistingTopics,
+			Containers:        args.Containers,
+			BucketActions:     bucketDataActions(a.proj.Policies, c.Unit().Name),
 		}, pulumi.Parent(res))
 		if err != nil {
 			return nil, err
@@ -218,13 +252,28 @@ type ContainerAppArgs struct {
 	Location          pulumi.StringInput
 	SubscriptionID    pulumi.StringInput
 	Registry          *containerregistry.Registry
-	RegistryUser      pulumi.StringPtrInput
-	RegistryPass      pulumi.StringPtrInput
 	KubeEnv           *web.KubeEnvironment
 	ImageUri          pulumi.StringInput
 	Env               web.EnvironmentVarArray
 	Compute           project.Compute
 	Topics            map[string]*eventgrid.Topic
+	Dapr              *stack.Dapr
+	Sleep             *stack.Sleep
+	Databases         map[string]*PostgresDatabase
+	Caches            map[string]*RedisCache
+	Emails            map[string]*CommunicationService
+	// MaxConcurrency limits the replicas of the app, it is not limited when 0
+	MaxConcurrency int
+	// Tls allows HTTP requests to the app's ingress when the stack does
+	Tls stack.Tls
+	// Revisions sets the app's revision mode, the name of the new revision and how the traffic is split
+	Revisions stack.Revisions
+	// ExistingTopics are the resource ids of the topics owned by others, keyed by the project's topic name
+	ExistingTopics map[string]string
+	// Containers are the blob containers of the buckets, keyed by bucket name
+	Containers map[string]*storage.BlobContainer
+	// BucketActions are the data actions the app's policies allow on each bucket, keyed by bucket name
+	BucketActions map[string][]string
 }
 
 type ContainerApp struct {
@@ -234,6 +283,7 @@ type ContainerApp struct {
 	Sp            *SevicePrinciple
 	App           *web.ContainerApp
 	Subscriptions map[string]*eventgrid.Topic
+	BucketEvents  []project.BucketTrigger
 }
 
 // Built in role definitions for Azure
@@ -241,13 +291,47 @@ type ContainerApp struct {
 // https://docs.microsoft.com/en-us/azure/role-based-access-control/built-in-roles
 var RoleDefinitions = map[string]string{
 	"KVSecretsOfficer":    "b86a8fe4-44ce-4948-aee5-eccb2c155cd7",
-	"BlobDataContrib":     "ba92f5b4-2d11-453d-a403-e96b0029c9fe",
 	"QueueDataContrib":    "974c5e8b-45b9-4653-ba55-5f855dd0fb88",
 	"EventGridDataSender": "d5a91429-5739-47e2-a06b-3470a27159e7",
 	// Access for locating resources
 	"TagContributor": "4a9ae827-6dc8-4573-8ac7-8239d42aa03f",
 }
 
+// resourceRoles are the roles of RoleDefinitions that allow the use of each type of resource, buckets
+// are allowed by a custom role on each container instead.
+var resourceRoles = map[v1.ResourceType]string{
+	v1.ResourceType_Queue:  "QueueDataContrib",
+	v1.ResourceType_Topic:  "EventGridDataSender",
+	v1.ResourceType_Secret: "KVSecretsOfficer",
+}
+
+var _ common.PermissionMapper = &azureProvider{}
+
+// Grants returns the role that allows the action, every app is assigned all of RoleDefinitions on the
+// stack's resource group whatever its policies, and a custom role with the data actions of its policies
+// on each bucket's container.
+func (a *azureProvider) Grants(action v1.Action, resource *v1.Resource) []string {
+	if resource.Type == v1.ResourceType_Collection {
+		// collections are reached with the connection string of the mongo account
+		return []string{"mongo connection string"}
+	}
+	if resource.Type == v1.ResourceType_Bucket {
+		grants := []string{}
+		for _, da := range blobDataActions[action] {
+			grants = append(grants, da+" (container)")
+		}
+		return grants
+	}
+	role, ok := resourceRoles[resource.Type]
+	if !ok {
+		return []string{}
+	}
+	return []string{role + " (resource group)"}
+}
+
+// acrPullRoleDefinition allows the app's service principal to pull its image from the registry
+const acrPullRoleDefinition = "7f951dfc-4ca0-4f4c-8ed7-54d7f5b79e3b"
+
 func (a *azureProvider) newContainerApp(ctx *pulumi.Context, name string, args *ContainerAppArgs, opts ...pulumi.ResourceOption) (*ContainerApp, error) {
 	res := &ContainerApp{
 		Name:          name,
@@ -280,6 +364,26 @@ func (a *azureProvider) newContainerApp(ctx *pulumi.Context, name string, args *
 		}
 	}
 
+	// Pull the image using the app's own identity rather than the registry admin credentials,
+	// so the registry password is never stored in the container app.
+	acrPull, err := authorization.NewRoleAssignment(ctx, resourceName(ctx, name+"AcrPull", AssignmentRT), &authorization.RoleAssignmentArgs{
+		PrincipalId:      res.Sp.ServicePrincipalId,
+		PrincipalType:    pulumi.StringPtr("ServicePrincipal"),
+		RoleDefinitionId: pulumi.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", args.SubscriptionID, acrPullRoleDefinition),
+		Scope:            args.Registry.ID(),
+	}, pulumi.Parent(res))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := assignExistingTopicRoles(ctx, res, args); err != nil {
+		return nil, err
+	}
+
+	if err := assignBucketRoles(ctx, res, args); err != nil {
+		return nil, err
+	}
+
 	env := web.EnvironmentVarArray{
 		web.EnvironmentVarArgs{
 			Name:  pulumi.String("MIN_WORKERS"),
@@ -311,57 +415,150 @@ func (a *azureProvider) newContainerApp(ctx *pulumi.Context, name string, args *
 		},
 	}
 
-	//memory := common.IntValueOrDefault(args.Compute.Unit().Memory, 128)
-	// we can't define memory without defining the cpu..
+	secrets := web.SecretArray{
+		web.SecretArgs{
+			Name:  pulumi.String("client-id"),
+			Value: res.Sp.ClientID,
+		},
+		web.SecretArgs{
+			Name:  pulumi.String("tenant-id"),
+			Value: res.Sp.TenantID,
+		},
+		web.SecretArgs{
+			Name:  pulumi.String("client-secret"),
+			Value: res.Sp.ClientSecret,
+		},
+	}
+
+	for k, db := range args.Databases {
+		secrets = append(secrets, web.SecretArgs{
+			Name:  pulumi.String(databaseSecretName(k)),
+			Value: db.URL,
+		})
+		env = append(env,
+			web.EnvironmentVarArgs{
+				Name:      pulumi.String(project.DatabaseEnvPrefix(k) + "_URL"),
+				SecretRef: pulumi.String(databaseSecretName(k)),
+			},
+			web.EnvironmentVarArgs{
+				Name:  pulumi.String(project.DatabaseEnvPrefix(k) + "_NAME"),
+				Value: pulumi.String(project.DatabaseName(k)),
+			})
+	}
+
+	for k, c := range args.Caches {
+		secrets = append(secrets, web.SecretArgs{
+			Name:  pulumi.String(cacheSecretName(k)),
+			Value: c.URL,
+		})
+		env = append(env, web.EnvironmentVarArgs{
+			Name:      pulumi.String(project.CacheEnvPrefix(k) + "_URL"),
+			SecretRef: pulumi.String(cacheSecretName(k)),
+		})
+	}
+
+	for k, e := range args.Emails {
+		secrets = append(secrets, web.SecretArgs{
+			Name:  pulumi.String(emailSecretName(k)),
+			Value: e.ConnectionString,
+		})
+		env = append(env,
+			web.EnvironmentVarArgs{
+				Name:  pulumi.String(project.EmailEnvPrefix(k) + "_SENDER"),
+				Value: pulumi.String(e.Sender),
+			},
+			web.EnvironmentVarArgs{
+				Name:      pulumi.String(project.EmailEnvPrefix(k) + "_CONNECTION_STRING"),
+				SecretRef: pulumi.String(emailSecretName(k)),
+			})
+	}
+
+	for _, e := range append(common.DeploymentEnv(ctx.Stack(), args.Compute), common.ObservabilityEnv(a.sc.Observability, name, a.sc.Name)...) {
+		env = append(env, web.EnvironmentVarArgs{
+			Name:  pulumi.String(e.Name),
+			Value: pulumi.String(e.Value),
+		})
+	}
+
+	for _, e := range common.UnitEnv(args.Compute) {
+		// the env files are in args.Env and take precedence
+		if _, ok := a.envMap[e.Name]; ok {
+			continue
+		}
+		env = append(env, web.EnvironmentVarArgs{
+			Name:  pulumi.String(e.Name),
+			Value: pulumi.String(e.Value),
+		})
+	}
+
+	containers := web.ContainerArray{
+		web.ContainerArgs{
+			Name:      pulumi.String("myapp"),
+			Image:     args.ImageUri,
+			Env:       append(env, args.Env...),
+			Resources: resourcesArgs(args.Compute),
+		},
+	}
+	if a.sc.Observability != nil {
+		config, err := common.CollectorConfig(a.sc.Observability, a.envMap)
+		if err != nil {
+			return nil, errors.WithMessage(err, "collector config")
+		}
+		// the config holds the exporter headers, such as API keys, so it is kept in a secret
+		secrets = append(secrets, web.SecretArgs{
+			Name:  pulumi.String("collector-config"),
+			Value: pulumi.String(config),
+		})
+		// the collector exports what the function sends to localhost
+		containers = append(containers, web.ContainerArgs{
+			Name:  pulumi.String("collector"),
+			Image: pulumi.String(common.CollectorImage),
+			Args:  pulumi.StringArray{pulumi.String("--config=env:" + common.CollectorConfigEnv)},
+			Env: web.EnvironmentVarArray{
+				web.EnvironmentVarArgs{
+					Name:      pulumi.String(common.CollectorConfigEnv),
+					SecretRef: pulumi.String("collector-config"),
+				},
+			},
+		})
+	}
+
+	// we can't define memory without defining the cpu, so it is only set by a compute class
 	res.App, err = web.NewContainerApp(ctx, resourceName(ctx, name, ContainerAppRT), &web.ContainerAppArgs{
 		ResourceGroupName: args.ResourceGroupName,
 		Location:          args.Location,
 		KubeEnvironmentId: args.KubeEnv.ID(),
 		Configuration: web.ConfigurationArgs{
+			ActiveRevisionsMode: activeRevisionsMode(args.Revisions),
 			Ingress: web.IngressArgs{
-				External:   pulumi.BoolPtr(true),
-				TargetPort: pulumi.Int(9001),
+				External:      pulumi.BoolPtr(true),
+				TargetPort:    pulumi.Int(common.IntValueOrDefault(args.Compute.Unit().Port, 9001)),
+				Transport:     ingressTransport(args.Compute),
+				AllowInsecure: pulumi.BoolPtr(args.Tls.AllowHttp),
+				Traffic:       trafficArgs(args.Revisions),
 			},
 			Registries: web.RegistryCredentialsArray{
 				web.RegistryCredentialsArgs{
 					Server:            args.Registry.LoginServer,
-					Username:          args.RegistryUser,
-					PasswordSecretRef: pulumi.String("pwd"),
-				},
-			},
-			Secrets: web.SecretArray{
-				web.SecretArgs{
-					Name:  pulumi.String("pwd"),
-					Value: args.RegistryPass,
-				},
-				web.SecretArgs{
-					Name:  pulumi.String("client-id"),
-					Value: res.Sp.ClientID,
-				},
-				web.SecretArgs{
-					Name:  pulumi.String("tenant-id"),
-					Value: res.Sp.TenantID,
-				},
-				web.SecretArgs{
-					Name:  pulumi.String("client-secret"),
-					Value: res.Sp.ClientSecret,
+					Username:          res.Sp.ClientID,
+					PasswordSecretRef: pulumi.String("client-secret"),
 				},
 			},
+			Secrets: secrets,
 		},
 		Tags: common.Tags(ctx, name),
 		Template: web.TemplateArgs{
-			Containers: web.ContainerArray{
-				web.ContainerArgs{
-					Name:  pulumi.String("myapp"),
-					Image: args.ImageUri,
-					Env:   append(env, args.Env...),
-				},
-			},
+			Containers:     containers,
+			Dapr:           daprArgs(name, args.Dapr),
+			Scale:          scaleArgs(args.Compute, args.Sleep, args.MaxConcurrency),
+			RevisionSuffix: revisionSuffix(args.Revisions),
 		},
-	}, pulumi.Parent(res))
+	}, pulumi.Parent(res), pulumi.DependsOn([]pulumi.Resource{acrPull}))
 	if err != nil {
 		return nil, err
 	}
+	// nitric stack update --verify reads the health of the app's latest revision with its id
+	ctx.Export("function:"+name, res.App.ID())
 
 	// Determine required subscriptions so they can be setup once the container starts
 	for _, t := range args.Compute.Unit().Triggers.Topics {
@@ -370,6 +567,7 @@ func (a *azureProvider) newContainerApp(ctx *pulumi.Context, name string, args *
 			res.Subscriptions[t] = topic
 		}
 	}
+	res.BucketEvents = args.Compute.Unit().Triggers.Buckets
 
 	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
 		"name":         pulumi.StringPtr(res.Name),
@@ -377,3 +575,207 @@ func (a *azureProvider) newContainerApp(ctx *pulumi.Context, name string, args *
 		//"subscriptions": res.Subscriptions,
 	})
 }
+
+// ingressTransport forwards requests to apps serving grpc or h2c over HTTP/2, the ingress detects the
+// protocol otherwise.
+func ingressTransport(c project.Compute) pulumi.StringPtrInput {
+	if !c.Unit().Http2() {
+		return nil
+	}
+	return pulumi.StringPtr("http2")
+}
+
+// maxContainerAppCpu is the most vCPUs a container app can have.
+const maxContainerAppCpu = 2
+
+// resourcesArgs sets the CPU and memory of the compute class, the memory can't be set without the CPU.
+func resourcesArgs(c project.Compute) web.ContainerResourcesPtrInput {
+	class := c.Unit().ComputeClass()
+	if class == nil {
+		return nil
+	}
+	return web.ContainerResourcesArgs{
+		Cpu:    pulumi.Float64Ptr(class.Cpu),
+		Memory: pulumi.StringPtr(fmt.Sprintf("%gGi", float64(class.Memory)/1024)),
+	}
+}
+
+// validateComputeClasses checks the compute classes fit in a container app.
+func validateComputeClasses(proj *project.Project) error {
+	for _, c := range proj.Computes() {
+		class := c.Unit().ComputeClass()
+		if class != nil && class.Cpu > maxContainerAppCpu {
+			return utils.NewNotSupportedErr(fmt.Sprintf("compute class %s of %s needs %g vCPUs, container apps have at most %d", c.Unit().Class, c.Unit().Name, class.Cpu, maxContainerAppCpu))
+		}
+	}
+	return nil
+}
+
+// A container app has 4GiB of ephemeral storage, that /tmp is written to, for each vCPU. The storage can't be
+// sized separately, the CPU of the compute class sets it.
+const (
+	containerAppStoragePerCpu = 4096
+	defaultContainerAppCpu    = 0.5
+)
+
+// validateTmpSizes checks the tmp size of each compute unit fits in the ephemeral storage of its container app.
+func validateTmpSizes(proj *project.Project) error {
+	for _, c := range proj.Computes() {
+		u := c.Unit()
+		cpu := defaultContainerAppCpu
+		if class := u.ComputeClass(); class != nil {
+			cpu = class.Cpu
+		}
+		if storage := int(cpu * containerAppStoragePerCpu); u.TmpSize > storage {
+			return fmt.Errorf("the tmpSize of %s is more than the %dMB of storage a container app with %g vCPUs has, use a larger compute class", u.Name, storage, cpu)
+		}
+	}
+	return nil
+}
+
+// scaleArgs keeps the replicas of a service running, other apps use the default scale rules.
+// When the stack sleeps, the replicas are only kept running by a cron rule between the wake and sleep schedules.
+// The replicas are limited to the max concurrency of the app's subscriptions.
+func scaleArgs(c project.Compute, sleep *stack.Sleep, maxConcurrency int) web.ScalePtrInput {
+	maxReplicas := common.IntValueOrDefault(c.Unit().MaxScale, 10)
+	if maxConcurrency > 0 && maxConcurrency < maxReplicas {
+		maxReplicas = maxConcurrency
+	}
+	minScale := c.Unit().MinScale
+	if c.Unit().AlwaysOn {
+		minScale = common.IntValueOrDefault(minScale, 1)
+	}
+	if minScale == 0 || (sleep == nil && !c.Unit().AlwaysOn) {
+		if maxConcurrency == 0 {
+			return nil
+		}
+		return web.ScaleArgs{
+			MaxReplicas: pulumi.IntPtr(maxReplicas),
+		}
+	}
+	if sleep == nil {
+		return web.ScaleArgs{
+			MinReplicas: pulumi.IntPtr(minScale),
+			MaxReplicas: pulumi.IntPtr(maxReplicas),
+		}
+	}
+	return web.ScaleArgs{
+		MinReplicas: pulumi.IntPtr(0),
+		MaxReplicas: pulumi.IntPtr(maxReplicas),
+		Rules: web.ScaleRuleArray{
+			web.ScaleRuleArgs{
+				Name: pulumi.StringPtr("awake"),
+				Custom: web.CustomScaleRuleArgs{
+					Type: pulumi.StringPtr("cron"),
+					Metadata: pulumi.StringMap{
+						"timezone":        pulumi.String(sleep.TimeZoneOrDefault()),
+						"start":           pulumi.String(sleep.Wake),
+						"end":             pulumi.String(sleep.Sleep),
+						"desiredReplicas": pulumi.String(fmt.Sprint(minScale)),
+					},
+				},
+			},
+		},
+	}
+}
+
+// daprArgs enables the Dapr sidecar when it is configured for the function.
+func daprArgs(name string, dapr *stack.Dapr) web.DaprPtrInput {
+	if dapr == nil {
+		return nil
+	}
+	app, ok := dapr.Functions[name]
+	if !ok {
+		return nil
+	}
+
+	appID := app.AppID
+	if appID == "" {
+		appID = name
+	}
+
+	components := web.DaprComponentArray{}
+	for _, c := range dapr.Components {
+		metadata := web.DaprMetadataArray{}
+		for k, v := range c.Metadata {
+			metadata = append(metadata, web.DaprMetadataArgs{
+				Name:  pulumi.String(k),
+				Value: pulumi.String(v),
+			})
+		}
+		components = append(components, web.DaprComponentArgs{
+			Name:     pulumi.String(c.Name),
+			Type:     pulumi.String(c.Type),
+			Version:  pulumi.String(c.Version),
+			Metadata: metadata,
+		})
+	}
+
+	return web.DaprArgs{
+		Enabled:    pulumi.Bool(true),
+		AppId:      pulumi.String(appID),
+		AppPort:    pulumi.Int(common.IntValueOrDefault(app.AppPort, 9001)),
+		Components: components,
+	}
+}
+
+// containerAppReservedEnv are injected by newContainerApps and newContainerApp, including the
+// service principal secrets.
+var containerAppReservedEnv = []string{
+	"AZURE_STORAGE_ACCOUNT_BLOB_ENDPOINT",
+	"AZURE_STORAGE_ACCOUNT_QUEUE_ENDPOINT",
+	"MONGODB_CONNECTION_STRING",
+	"MONGODB_DATABASE",
+	"KVAULT_NAME",
+	"AZURE_SUBSCRIPTION_ID",
+	"AZURE_RESOURCE_GROUP",
+	"AZURE_CLIENT_ID",
+	"AZURE_TENANT_ID",
+	"AZURE_CLIENT_SECRET",
+	"TOLERATE_MISSING_SERVICES",
+}
+
+// Env mirrors the environment built by newContainerApps and newContainerApp. Most of the
+// values are outputs of other resources so they are only named here.
+func (a *azureProvider) Env(c project.Compute) []types.EnvVar {
+	env := []types.EnvVar{}
+	if len(a.proj.Buckets) > 0 || len(a.proj.Queues) > 0 {
+		env = append(env,
+			types.EnvVar{Name: "AZURE_STORAGE_ACCOUNT_BLOB_ENDPOINT", Value: types.DeployTimeValue, Source: types.EnvSourceProvider},
+			types.EnvVar{Name: "AZURE_STORAGE_ACCOUNT_QUEUE_ENDPOINT", Value: types.DeployTimeValue, Source: types.EnvSourceProvider},
+		)
+	}
+	if len(a.proj.Collections) > 0 {
+		env = append(env,
+			types.EnvVar{Name: "MONGODB_CONNECTION_STRING", Value: types.DeployTimeValue, Source: types.EnvSourceProvider},
+			types.EnvVar{Name: "MONGODB_DATABASE", Value: types.DeployTimeValue, Source: types.EnvSourceProvider},
+		)
+	}
+
+	for _, k := range a.proj.DatabasesFor(c.Unit().Name) {
+		env = append(env, databaseEnv(k)...)
+	}
+
+	for _, k := range a.proj.CachesFor(c.Unit().Name) {
+		env = append(env, cacheEnv(k)...)
+	}
+
+	for _, k := range a.proj.EmailsFor(c.Unit().Name) {
+		env = append(env, emailEnv(k, a.proj.Emails[k])...)
+	}
+
+	env = append(env, common.DeploymentEnv(a.proj.Name+"-"+a.sc.Name, c)...)
+	env = append(env, common.ObservabilityEnv(a.sc.Observability, c.Unit().Name, a.sc.Name)...)
+	env = append(env, common.UnitEnv(c)...)
+
+	return common.MergeEnv(append(env,
+		types.EnvVar{Name: "KVAULT_NAME", Value: types.DeployTimeValue, Source: types.EnvSourceProvider},
+		types.EnvVar{Name: "MIN_WORKERS", Value: fmt.Sprint(c.Workers()), Source: types.EnvSourceProvider},
+		types.EnvVar{Name: "AZURE_SUBSCRIPTION_ID", Value: types.DeployTimeValue, Source: types.EnvSourceProvider},
+		types.EnvVar{Name: "AZURE_RESOURCE_GROUP", Value: types.DeployTimeValue, Source: types.EnvSourceProvider},
+		types.EnvVar{Name: "AZURE_CLIENT_ID", Value: "client-id", Source: types.EnvSourceSecret},
+		types.EnvVar{Name: "AZURE_TENANT_ID", Value: "tenant-id", Source: types.EnvSourceSecret},
+		types.EnvVar{Name: "AZURE_CLIENT_SECRET", Value: "client-secret", Source: types.EnvSourceSecret},
+		types.EnvVar{Name: "TOLERATE_MISSING_SERVICES", Value: "true", Source: types.EnvSourceProvider},
+	), a.envMap)
+}
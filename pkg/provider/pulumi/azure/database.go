@@ -0,0 +1,121 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"github.com/pkg/errors"
+	postgresql "github.com/pulumi/pulumi-azure-native/sdk/go/azure/dbforpostgresql/v20210601"
+	"github.com/pulumi/pulumi-random/sdk/v4/go/random"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+const databaseUser = "nitric"
+
+type PostgresDatabaseArgs struct {
+	ResourceGroupName pulumi.StringInput
+	Location          pulumi.StringInput
+	Database          project.Database
+}
+
+type PostgresDatabase struct {
+	pulumi.ResourceState
+
+	Name   string
+	Server *postgresql.Server
+	URL    pulumi.StringOutput
+}
+
+// newPostgresDatabase creates a Flexible Server for the database, accepting connections from within Azure.
+func newPostgresDatabase(ctx *pulumi.Context, name string, args *PostgresDatabaseArgs, opts ...pulumi.ResourceOption) (*PostgresDatabase, error) {
+	res := &PostgresDatabase{Name: name}
+	err := ctx.RegisterComponentResource("nitric:database:AzurePostgres", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	password, err := random.NewRandomPassword(ctx, name+"-password", &random.RandomPasswordArgs{
+		Length:  pulumi.Int(32),
+		Special: pulumi.Bool(false),
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "database password")
+	}
+
+	res.Server, err = postgresql.NewServer(ctx, resourceName(ctx, name, PostgresServerRT), &postgresql.ServerArgs{
+		ResourceGroupName:          args.ResourceGroupName,
+		Location:                   args.Location,
+		AdministratorLogin:         pulumi.String(databaseUser),
+		AdministratorLoginPassword: password.Result,
+		Version:                    pulumi.String("13"),
+		Sku: &postgresql.SkuArgs{
+			Name: pulumi.String("Standard_B1ms"),
+			Tier: pulumi.String("Burstable"),
+		},
+		Storage: &postgresql.StorageArgs{
+			StorageSizeGB: pulumi.Int(32),
+		},
+	}, append(opts, pulumi.Protect(true))...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "postgres server")
+	}
+
+	// 0.0.0.0 allows connections from within Azure, such as the container apps
+	_, err = postgresql.NewFirewallRule(ctx, name+"-allow-azure", &postgresql.FirewallRuleArgs{
+		ResourceGroupName: args.ResourceGroupName,
+		ServerName:        res.Server.Name,
+		StartIpAddress:    pulumi.String(azureDatacenters),
+		EndIpAddress:      pulumi.String(azureDatacenters),
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "postgres firewall rule")
+	}
+
+	db, err := postgresql.NewDatabase(ctx, name, &postgresql.DatabaseArgs{
+		ResourceGroupName: args.ResourceGroupName,
+		ServerName:        res.Server.Name,
+		DatabaseName:      pulumi.String(project.DatabaseName(name)),
+	}, append(opts, pulumi.Protect(true))...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "postgres database")
+	}
+
+	res.URL = pulumi.Sprintf("postgres://%s:%s@%s:5432/%s?sslmode=require", databaseUser, password.Result, res.Server.FullyQualifiedDomainName, db.Name)
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":   pulumi.String(res.Name),
+		"server": res.Server.Name,
+	})
+}
+
+// databaseSecretName is the container app secret holding the database URL.
+func databaseSecretName(name string) string {
+	return "db-" + project.DatabaseName(name) + "-url"
+}
+
+// databaseEnv are the env vars used to connect to a database.
+func databaseEnv(name string) []types.EnvVar {
+	prefix := project.DatabaseEnvPrefix(name)
+	return []types.EnvVar{
+		{Name: prefix + "_URL", Value: databaseSecretName(name), Source: types.EnvSourceSecret},
+		{Name: prefix + "_NAME", Value: project.DatabaseName(name), Source: types.EnvSourceProvider},
+	}
+}
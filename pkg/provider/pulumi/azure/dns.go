@@ -0,0 +1,135 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/cdn"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/network"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+type domainRecordsArgs struct {
+	// Zone is the resource id of the Azure DNS zone holding the domain
+	Zone     string
+	Domain   string
+	Endpoint *cdn.AFDEndpoint
+	// Token proves the domain is owned, so Front Door can issue its certificate
+	Token pulumi.StringInput
+}
+
+// dnsZone is an Azure DNS zone, from its resource id.
+type dnsZone struct {
+	ResourceGroup string
+	Name          string
+}
+
+// parseDnsZone parses /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.Network/dnszones/<zone>
+func parseDnsZone(id string) (*dnsZone, error) {
+	parts := strings.Split(strings.Trim(id, "/"), "/")
+	if len(parts) != 8 || !strings.EqualFold(parts[2], "resourceGroups") || !strings.EqualFold(parts[6], "dnszones") {
+		return nil, fmt.Errorf("%s is not the resource id of an Azure DNS zone", id)
+	}
+	return &dnsZone{ResourceGroup: parts[3], Name: parts[7]}, nil
+}
+
+// relativeName is the name of the domain's records in the zone, @ for the zone's apex.
+func (z *dnsZone) relativeName(domain string) (string, error) {
+	if strings.EqualFold(domain, z.Name) {
+		return "@", nil
+	}
+	suffix := "." + strings.ToLower(z.Name)
+	if !strings.HasSuffix(strings.ToLower(domain), suffix) {
+		return "", fmt.Errorf("the domain %s is not in the zone %s", domain, z.Name)
+	}
+	return domain[:len(domain)-len(suffix)], nil
+}
+
+// validateDnsZones checks the zones of the cdn targets are DNS zones that hold their domains.
+func validateDnsZones(c *stack.Cdn) error {
+	if c == nil {
+		return nil
+	}
+	for _, targets := range []map[string]stack.CdnTarget{c.Apis, c.Buckets, c.Sites} {
+		for _, t := range targets {
+			if t.Zone == "" || t.ManualDns {
+				continue
+			}
+			z, err := parseDnsZone(t.Zone)
+			if err != nil {
+				return err
+			}
+			for _, d := range t.Domains {
+				if _, err := z.relativeName(d); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// newDomainRecords creates the TXT record that validates the domain and the record that sends its requests
+// to the endpoint, an alias at the zone's apex as it can't have a CNAME.
+func newDomainRecords(ctx *pulumi.Context, name string, args *domainRecordsArgs, opts ...pulumi.ResourceOption) error {
+	z, err := parseDnsZone(args.Zone)
+	if err != nil {
+		return err
+	}
+	relative, err := z.relativeName(args.Domain)
+	if err != nil {
+		return err
+	}
+
+	validation := "_dnsauth"
+	if relative != "@" {
+		validation += "." + relative
+	}
+	_, err = network.NewRecordSet(ctx, name+"-validation", &network.RecordSetArgs{
+		ResourceGroupName:     pulumi.String(z.ResourceGroup),
+		ZoneName:              pulumi.String(z.Name),
+		RecordType:            pulumi.String("TXT"),
+		RelativeRecordSetName: pulumi.String(validation),
+		TTL:                   pulumi.Float64(3600),
+		TxtRecords: network.TxtRecordArray{
+			network.TxtRecordArgs{Value: pulumi.StringArray{args.Token}},
+		},
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	route := &network.RecordSetArgs{
+		ResourceGroupName:     pulumi.String(z.ResourceGroup),
+		ZoneName:              pulumi.String(z.Name),
+		RelativeRecordSetName: pulumi.String(relative),
+		TTL:                   pulumi.Float64(3600),
+	}
+	if relative == "@" {
+		route.RecordType = pulumi.String("A")
+		route.TargetResource = &network.SubResourceArgs{Id: args.Endpoint.ID()}
+	} else {
+		route.RecordType = pulumi.String("CNAME")
+		route.CnameRecord = &network.CnameRecordArgs{Cname: args.Endpoint.HostName}
+	}
+	_, err = network.NewRecordSet(ctx, name+"-route", route, opts...)
+	return err
+}
@@ -0,0 +1,79 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func Test_dnsZone_relativeName(t *testing.T) {
+	z, err := parseDnsZone("/subscriptions/0000/resourceGroups/dns/providers/Microsoft.Network/dnszones/example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if z.ResourceGroup != "dns" || z.Name != "example.com" {
+		t.Errorf("parseDnsZone() = %+v, want the dns group and example.com", z)
+	}
+
+	tests := []struct {
+		domain  string
+		want    string
+		wantErr bool
+	}{
+		{domain: "api.example.com", want: "api"},
+		{domain: "v1.api.example.com", want: "v1.api"},
+		{domain: "example.com", want: "@"},
+		{domain: "example.org", wantErr: true},
+		{domain: "badexample.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			got, err := z.relativeName(tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("relativeName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("relativeName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validateDnsZones(t *testing.T) {
+	zone := "/subscriptions/0000/resourceGroups/dns/providers/Microsoft.Network/dnszones/example.com"
+	tests := []struct {
+		name    string
+		target  stack.CdnTarget
+		wantErr bool
+	}{
+		{name: "no zone", target: stack.CdnTarget{Domains: []string{"api.example.org"}}},
+		{name: "in zone", target: stack.CdnTarget{Domains: []string{"api.example.com"}, Zone: zone}},
+		{name: "manual", target: stack.CdnTarget{Domains: []string{"api.example.org"}, Zone: "example.com", ManualDns: true}},
+		{name: "not in zone", target: stack.CdnTarget{Domains: []string{"api.example.org"}, Zone: zone}, wantErr: true},
+		{name: "not a zone id", target: stack.CdnTarget{Domains: []string{"api.example.com"}, Zone: "example.com"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &stack.Cdn{Apis: map[string]stack.CdnTarget{"main": tt.target}}
+			if err := validateDnsZones(c); (err != nil) != tt.wantErr {
+				t.Errorf("validateDnsZones() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
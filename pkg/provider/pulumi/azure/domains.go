@@ -0,0 +1,96 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+// frontDoorAPIVersion is the version of the Front Door management API the custom domains are read with.
+const frontDoorAPIVersion = "2021-06-01"
+
+var _ common.DomainValidator = &azureProvider{}
+
+// afdCustomDomain is the part of a Front Door custom domain that describes its validation.
+type afdCustomDomain struct {
+	Properties struct {
+		DomainValidationState string `json:"domainValidationState"`
+		ValidationProperties  struct {
+			ValidationToken string `json:"validationToken"`
+		} `json:"validationProperties"`
+	} `json:"properties"`
+}
+
+// Domains reads the validation state of the stack's Front Door custom domains, which are exported with
+// their ids and the endpoint hosts they are served from.
+func (a *azureProvider) Domains(outputs map[string]string) ([]types.DomainStatus, error) {
+	names := []string{}
+	for k := range outputs {
+		if strings.HasPrefix(k, "domain:") {
+			names = append(names, strings.TrimPrefix(k, "domain:"))
+		}
+	}
+	sort.Strings(names)
+
+	statuses := []types.DomainStatus{}
+	for _, name := range names {
+		d, err := getCustomDomain(outputs["domain:"+name])
+		if err != nil {
+			return nil, errors.WithMessage(err, "custom domain "+name)
+		}
+		statuses = append(statuses, domainStatus(name, outputs["domainTarget:"+name], d))
+	}
+	return statuses, nil
+}
+
+// domainStatus returns the records a custom domain needs until it is approved, the TXT record that
+// proves it is owned and the CNAME that sends its requests to the endpoint.
+func domainStatus(name, target string, d *afdCustomDomain) types.DomainStatus {
+	s := types.DomainStatus{
+		Domain: name,
+		State:  d.Properties.DomainValidationState,
+		Ready:  d.Properties.DomainValidationState == "Approved",
+	}
+	if s.Ready {
+		return s
+	}
+	if token := d.Properties.ValidationProperties.ValidationToken; token != "" {
+		s.Records = append(s.Records, types.DnsRecord{Type: "TXT", Name: "_dnsauth." + name, Value: token})
+	}
+	if target != "" {
+		s.Records = append(s.Records, types.DnsRecord{Type: "CNAME", Name: name, Value: target})
+	}
+	return s
+}
+
+func getCustomDomain(id string) (*afdCustomDomain, error) {
+	d := &afdCustomDomain{}
+	return d, armGet(id, frontDoorAPIVersion, d)
+}
+
+// armGet reads the resource with the id from the Azure Resource Manager API into v.
+func armGet(id, apiVersion string, v interface{}) error {
+	return armRequest(http.MethodGet, fmt.Sprintf("https://management.azure.com%s?api-version=%s", id, apiVersion), v)
+}
@@ -0,0 +1,62 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+func Test_domainStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want types.DomainStatus
+	}{
+		{
+			name: "pending",
+			body: `{"properties":{"domainValidationState":"Pending","validationProperties":{"validationToken":"abc123"}}}`,
+			want: types.DomainStatus{
+				Domain: "api.example.com",
+				State:  "Pending",
+				Records: []types.DnsRecord{
+					{Type: "TXT", Name: "_dnsauth.api.example.com", Value: "abc123"},
+					{Type: "CNAME", Name: "api.example.com", Value: "main-api.z01.azurefd.net"},
+				},
+			},
+		},
+		{
+			name: "approved",
+			body: `{"properties":{"domainValidationState":"Approved","validationProperties":{"validationToken":"abc123"}}}`,
+			want: types.DomainStatus{Domain: "api.example.com", State: "Approved", Ready: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &afdCustomDomain{}
+			if err := json.Unmarshal([]byte(tt.body), d); err != nil {
+				t.Fatal(err)
+			}
+			if got := domainStatus("api.example.com", "main-api.z01.azurefd.net", d); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("domainStatus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
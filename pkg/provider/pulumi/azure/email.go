@@ -0,0 +1,100 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/communication"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+// communicationDataLocation is where Communication Services stores its data at rest.
+const communicationDataLocation = "United States"
+
+type CommunicationServiceArgs struct {
+	ResourceGroupName pulumi.StringInput
+	Email             project.Email
+}
+
+type CommunicationService struct {
+	pulumi.ResourceState
+
+	Name             string
+	Sender           string
+	Service          *communication.CommunicationService
+	ConnectionString pulumi.StringOutput
+}
+
+// newCommunicationService creates an Azure Communication Services resource to send email with. The
+// sender's domain is connected to the resource once it has been verified in the portal.
+func newCommunicationService(ctx *pulumi.Context, name string, args *CommunicationServiceArgs, opts ...pulumi.ResourceOption) (*CommunicationService, error) {
+	res := &CommunicationService{Name: name, Sender: args.Email.Sender}
+	err := ctx.RegisterComponentResource("nitric:email:AzureCommunicationService", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	res.Service, err = communication.NewCommunicationService(ctx, resourceName(ctx, name, CommunicationServiceRT), &communication.CommunicationServiceArgs{
+		ResourceGroupName: args.ResourceGroupName,
+		Location:          pulumi.String("global"),
+		DataLocation:      pulumi.String(communicationDataLocation),
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "communication service")
+	}
+
+	res.ConnectionString = pulumi.ToSecret(pulumi.All(args.ResourceGroupName, res.Service.Name).ApplyT(func(args []interface{}) (string, error) {
+		keys, err := communication.ListCommunicationServiceKeys(ctx, &communication.ListCommunicationServiceKeysArgs{
+			ResourceGroupName:        args[0].(string),
+			CommunicationServiceName: args[1].(string),
+		})
+		if err != nil {
+			return "", err
+		}
+		if keys.PrimaryConnectionString == nil {
+			return "", errors.New("communication service has no connection string")
+		}
+
+		return *keys.PrimaryConnectionString, nil
+	})).(pulumi.StringOutput)
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":    pulumi.String(res.Name),
+		"service": res.Service.Name,
+	})
+}
+
+// emailSecretName is the container app secret holding the communication service connection string.
+func emailSecretName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(project.EmailEnvPrefix(name)), "_", "-") + "-connection-string"
+}
+
+// emailEnv are the env vars used to send email.
+func emailEnv(name string, e project.Email) []types.EnvVar {
+	prefix := project.EmailEnvPrefix(name)
+	return []types.EnvVar{
+		{Name: prefix + "_SENDER", Value: e.Sender, Source: types.EnvSourceProvider},
+		{Name: prefix + "_CONNECTION_STRING", Value: emailSecretName(name), Source: types.EnvSourceSecret},
+	}
+}
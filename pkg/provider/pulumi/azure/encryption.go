@@ -0,0 +1,101 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+var _ common.Encrypter = &azureProvider{}
+
+// keyVaultToken returns a token for Key Vault.
+func keyVaultToken() (string, error) {
+	return azToken("https://vault.azure.net")
+}
+
+// azToken returns a token for the resource from the az cli, which the azure plugins also authenticate with.
+func azToken(resource string) (string, error) {
+	out, err := exec.Command("az", "account", "get-access-token", "--resource", resource, "--query", "accessToken", "--output", "tsv").Output()
+	if ee, ok := err.(*exec.ExitError); ok {
+		return "", errors.WithMessage(err, strings.TrimSpace(string(ee.Stderr)))
+	}
+	return strings.TrimSpace(string(out)), err
+}
+
+// keyOperation calls the encrypt or decrypt operation of the stack's encryptionKey, a Key Vault key id
+// https://<vault>.vault.azure.net/keys/<key>/<version>, returning the resulting value.
+func (a *azureProvider) keyOperation(op, value string) (string, error) {
+	token, err := keyVaultToken()
+	if err != nil {
+		return "", errors.WithMessage(err, "key vault token")
+	}
+
+	b, err := json.Marshal(map[string]string{"alg": "RSA-OAEP-256", "value": value})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(a.sc.EncryptionKey, "/")+"/"+op+"?api-version=7.3", bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return "", fmt.Errorf("key vault %s failed with %s: %s", op, resp.Status, msg)
+	}
+
+	result := struct {
+		Value string `json:"value"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	return result.Value, nil
+}
+
+func (a *azureProvider) Encrypt(plaintext string) (string, error) {
+	return a.keyOperation("encrypt", base64.RawURLEncoding.EncodeToString([]byte(plaintext)))
+}
+
+func (a *azureProvider) Decrypt(ciphertext string) (string, error) {
+	value, err := a.keyOperation("decrypt", ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := base64.RawURLEncoding.DecodeString(value)
+	return string(plaintext), err
+}
@@ -0,0 +1,325 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/cdn"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+type FrontDoorArgs struct {
+	ResourceGroupName pulumi.StringInput
+	Cdn               *stack.Cdn
+	Apis              map[string]*AzureApiManagement
+	Storage           *Storage
+	Sites             map[string]*Site
+	// Waf protects the api endpoints, nil for none
+	Waf *stack.Waf
+}
+
+type FrontDoor struct {
+	pulumi.ResourceState
+
+	Name      string
+	Profile   *cdn.Profile
+	Endpoints map[string]*cdn.AFDEndpoint
+	// Domains are the custom domains of each endpoint
+	Domains map[string][]*cdn.AFDCustomDomain
+}
+
+type frontDoorRouteArgs struct {
+	ResourceGroupName pulumi.StringInput
+	Profile           *cdn.Profile
+	HostName          pulumi.StringInput
+	OriginPath        pulumi.StringPtrInput
+	Target            stack.CdnTarget
+}
+
+func newFrontDoor(ctx *pulumi.Context, name string, args *FrontDoorArgs, opts ...pulumi.ResourceOption) (*FrontDoor, error) {
+	res := &FrontDoor{
+		Name:      name,
+		Endpoints: map[string]*cdn.AFDEndpoint{},
+		Domains:   map[string][]*cdn.AFDCustomDomain{},
+	}
+	err := ctx.RegisterComponentResource("nitric:cdn:AzureFrontDoor", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Profile, err = cdn.NewProfile(ctx, resourceName(ctx, name, FrontDoorProfileRT), &cdn.ProfileArgs{
+		ResourceGroupName: args.ResourceGroupName,
+		Location:          pulumi.String("Global"),
+		Sku: cdn.SkuArgs{
+			Name: pulumi.String(frontDoorSku(args.Waf)),
+		},
+		Tags: common.Tags(ctx, name),
+	}, pulumi.Parent(res))
+	if err != nil {
+		return nil, errors.WithMessage(err, "front door profile")
+	}
+
+	for k, target := range args.Cdn.Apis {
+		api, ok := args.Apis[k]
+		if !ok {
+			return nil, fmt.Errorf("cdn configured for api %s, but the api does not exist", k)
+		}
+
+		host := api.Service.GatewayUrl.ApplyT(func(url string) string {
+			return strings.TrimPrefix(url, "https://")
+		}).(pulumi.StringOutput)
+
+		res.Endpoints[k+"-api"], res.Domains[k+"-api"], err = newFrontDoorRoute(ctx, k+"-api", &frontDoorRouteArgs{
+			ResourceGroupName: args.ResourceGroupName,
+			Profile:           res.Profile,
+			HostName:          host,
+			Target:            target,
+		}, pulumi.Parent(res))
+		if err != nil {
+			return nil, errors.WithMessage(err, "front door api "+k)
+		}
+	}
+
+	for k, target := range args.Cdn.Buckets {
+		if args.Storage == nil {
+			return nil, fmt.Errorf("cdn configured for bucket %s, but the bucket does not exist", k)
+		}
+		container, ok := args.Storage.Containers[k]
+		if !ok {
+			return nil, fmt.Errorf("cdn configured for bucket %s, but the bucket does not exist", k)
+		}
+
+		host := args.Storage.Account.PrimaryEndpoints.Blob().ApplyT(func(url string) string {
+			return strings.TrimSuffix(strings.TrimPrefix(url, "https://"), "/")
+		}).(pulumi.StringOutput)
+
+		res.Endpoints[k+"-bucket"], res.Domains[k+"-bucket"], err = newFrontDoorRoute(ctx, k+"-bucket", &frontDoorRouteArgs{
+			ResourceGroupName: args.ResourceGroupName,
+			Profile:           res.Profile,
+			HostName:          host,
+			OriginPath:        pulumi.Sprintf("/%s", container.Name),
+			Target:            target,
+		}, pulumi.Parent(res))
+		if err != nil {
+			return nil, errors.WithMessage(err, "front door bucket "+k)
+		}
+	}
+
+	for k, target := range args.Cdn.Sites {
+		site, ok := args.Sites[k]
+		if !ok {
+			return nil, fmt.Errorf("cdn configured for site %s, but the site does not exist", k)
+		}
+
+		host := site.Account.PrimaryEndpoints.Web().ApplyT(func(url string) string {
+			return strings.TrimSuffix(strings.TrimPrefix(url, "https://"), "/")
+		}).(pulumi.StringOutput)
+
+		res.Endpoints[k+"-site"], res.Domains[k+"-site"], err = newFrontDoorRoute(ctx, k+"-site", &frontDoorRouteArgs{
+			ResourceGroupName: args.ResourceGroupName,
+			Profile:           res.Profile,
+			HostName:          host,
+			Target:            target,
+		}, pulumi.Parent(res))
+		if err != nil {
+			return nil, errors.WithMessage(err, "front door site "+k)
+		}
+	}
+
+	if args.Waf != nil {
+		apis := make([]string, 0, len(args.Cdn.Apis))
+		for k := range args.Cdn.Apis {
+			apis = append(apis, k)
+		}
+		sort.Strings(apis)
+
+		domains := cdn.ActivatedResourceReferenceArray{}
+		for _, k := range apis {
+			domains = append(domains, cdn.ActivatedResourceReferenceArgs{Id: res.Endpoints[k+"-api"].ID()})
+			for _, d := range res.Domains[k+"-api"] {
+				domains = append(domains, cdn.ActivatedResourceReferenceArgs{Id: d.ID()})
+			}
+		}
+		err = newFrontDoorWaf(ctx, name+"-waf", &frontDoorWafArgs{
+			ResourceGroupName: args.ResourceGroupName,
+			Profile:           res.Profile,
+			Waf:               args.Waf,
+			Domains:           domains,
+		}, pulumi.Parent(res))
+		if err != nil {
+			return nil, errors.WithMessage(err, "front door waf")
+		}
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":    pulumi.String(name),
+		"profile": res.Profile,
+	})
+}
+
+func newFrontDoorRoute(ctx *pulumi.Context, name string, args *frontDoorRouteArgs, opts ...pulumi.ResourceOption) (*cdn.AFDEndpoint, []*cdn.AFDCustomDomain, error) {
+	ep, err := cdn.NewAFDEndpoint(ctx, resourceName(ctx, name, FrontDoorEndpointRT), &cdn.AFDEndpointArgs{
+		ResourceGroupName: args.ResourceGroupName,
+		ProfileName:       args.Profile.Name,
+		Location:          pulumi.String("Global"),
+		EnabledState:      pulumi.String("Enabled"),
+		Tags:              common.Tags(ctx, name),
+	}, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	og, err := cdn.NewAFDOriginGroup(ctx, resourceName(ctx, name+"-group", FrontDoorOriginRT), &cdn.AFDOriginGroupArgs{
+		ResourceGroupName: args.ResourceGroupName,
+		ProfileName:       args.Profile.Name,
+		LoadBalancingSettings: cdn.LoadBalancingSettingsParametersArgs{
+			SampleSize:                pulumi.Int(4),
+			SuccessfulSamplesRequired: pulumi.Int(3),
+		},
+	}, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	origin, err := cdn.NewAFDOrigin(ctx, resourceName(ctx, name, FrontDoorOriginRT), &cdn.AFDOriginArgs{
+		ResourceGroupName: args.ResourceGroupName,
+		ProfileName:       args.Profile.Name,
+		OriginGroupName:   og.Name,
+		HostName:          args.HostName,
+		OriginHostHeader:  args.HostName,
+		HttpsPort:         pulumi.Int(443),
+		Priority:          pulumi.Int(1),
+		Weight:            pulumi.Int(1000),
+	}, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	domains := []*cdn.AFDCustomDomain{}
+	customDomains := cdn.ResourceReferenceArray{}
+	var dnsZone cdn.ResourceReferencePtrInput
+	if args.Target.Zone != "" {
+		dnsZone = &cdn.ResourceReferenceArgs{Id: pulumi.String(args.Target.Zone)}
+	}
+	for i, domain := range args.Target.Domains {
+		cd, err := cdn.NewAFDCustomDomain(ctx, resourceName(ctx, fmt.Sprintf("%s-domain%d", name, i), FrontDoorOriginRT), &cdn.AFDCustomDomainArgs{
+			ResourceGroupName: args.ResourceGroupName,
+			ProfileName:       args.Profile.Name,
+			HostName:          pulumi.String(domain),
+			AzureDnsZone:      dnsZone,
+			TlsSettings: &cdn.AFDDomainHttpsParametersArgs{
+				CertificateType:   pulumi.String("ManagedCertificate"),
+				MinimumTlsVersion: cdn.AfdMinimumTlsVersionTLS12,
+			},
+		}, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		domains = append(domains, cd)
+		customDomains = append(customDomains, cdn.ResourceReferenceArgs{Id: cd.ID()})
+
+		// the domain is validated, and its certificate issued, once its DNS records are created
+		ctx.Export("domain:"+domain, cd.ID())
+		ctx.Export("domainTarget:"+domain, ep.HostName)
+
+		if args.Target.Zone != "" && !args.Target.ManualDns {
+			err = newDomainRecords(ctx, fmt.Sprintf("%s-domain%d", name, i), &domainRecordsArgs{
+				Zone:     args.Target.Zone,
+				Domain:   domain,
+				Endpoint: ep,
+				Token:    cd.ValidationProperties.ValidationToken(),
+			}, opts...)
+			if err != nil {
+				return nil, nil, errors.WithMessage(err, "dns records of "+domain)
+			}
+		}
+	}
+
+	ruleSets := cdn.ResourceReferenceArray{}
+	if args.Target.DefaultTTL > 0 {
+		rs, err := cdn.NewRuleSet(ctx, resourceName(ctx, name, FrontDoorRuleSetRT), &cdn.RuleSetArgs{
+			ResourceGroupName: args.ResourceGroupName,
+			ProfileName:       args.Profile.Name,
+		}, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		_, err = cdn.NewRule(ctx, resourceName(ctx, name+"cache", FrontDoorRuleSetRT), &cdn.RuleArgs{
+			ResourceGroupName: args.ResourceGroupName,
+			ProfileName:       args.Profile.Name,
+			RuleSetName:       rs.Name,
+			Order:             pulumi.Int(1),
+			Actions: pulumi.Array{
+				cdn.DeliveryRuleCacheExpirationActionArgs{
+					Name: pulumi.String("CacheExpiration"),
+					Parameters: cdn.CacheExpirationActionParametersArgs{
+						OdataType:     pulumi.String("#Microsoft.Azure.Cdn.Models.DeliveryRuleCacheExpirationActionParameters"),
+						CacheBehavior: pulumi.String("Override"),
+						CacheType:     pulumi.String("All"),
+						CacheDuration: pulumi.String(cacheDuration(args.Target.DefaultTTL)),
+					},
+				},
+			},
+		}, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		ruleSets = append(ruleSets, cdn.ResourceReferenceArgs{Id: rs.ID()})
+	}
+
+	_, err = cdn.NewRoute(ctx, resourceName(ctx, name, FrontDoorEndpointRT), &cdn.RouteArgs{
+		ResourceGroupName:   args.ResourceGroupName,
+		ProfileName:         args.Profile.Name,
+		EndpointName:        ep.Name,
+		OriginGroup:         cdn.ResourceReferenceArgs{Id: og.ID()},
+		OriginPath:          args.OriginPath,
+		PatternsToMatch:     pulumi.ToStringArray([]string{"/*"}),
+		SupportedProtocols:  pulumi.ToStringArray([]string{"Https"}),
+		ForwardingProtocol:  pulumi.String("HttpsOnly"),
+		HttpsRedirect:       pulumi.String("Enabled"),
+		LinkToDefaultDomain: pulumi.String("Enabled"),
+		CustomDomains:       customDomains,
+		RuleSets:            ruleSets,
+	}, append(opts, pulumi.DependsOn([]pulumi.Resource{origin}))...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx.Export("cdn:"+name, pulumi.Sprintf("https://%s", ep.HostName))
+
+	return ep, domains, nil
+}
+
+// cacheDuration formats seconds in the [d.]hh:mm:ss format expected by Front Door
+func cacheDuration(seconds int) string {
+	d := time.Duration(seconds) * time.Second
+	days := int(d.Hours()) / 24
+	hms := fmt.Sprintf("%02d:%02d:%02d", int(d.Hours())%24, int(d.Minutes())%60, int(d.Seconds())%60)
+	if days > 0 {
+		return fmt.Sprintf("%d.%s", days, hms)
+	}
+	return hms
+}
@@ -0,0 +1,38 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import "testing"
+
+func Test_cacheDuration(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    string
+	}{
+		{seconds: 30, want: "00:00:30"},
+		{seconds: 3600, want: "01:00:00"},
+		{seconds: 5400, want: "01:30:00"},
+		{seconds: 90000, want: "1.01:00:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := cacheDuration(tt.seconds); got != tt.want {
+				t.Errorf("cacheDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
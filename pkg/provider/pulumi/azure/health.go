@@ -0,0 +1,79 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+// containerAppsAPIVersion is the version of the container apps management API the apps are deployed with.
+const containerAppsAPIVersion = "2021-03-01"
+
+var _ common.HealthChecker = &azureProvider{}
+
+type containerAppStatus struct {
+	Properties struct {
+		LatestRevisionName string `json:"latestRevisionName"`
+	} `json:"properties"`
+}
+
+// revisionStatus is the part of a container app revision that describes whether it is serving.
+type revisionStatus struct {
+	Properties struct {
+		HealthState       string `json:"healthState"`
+		ProvisioningState string `json:"provisioningState"`
+		ProvisioningError string `json:"provisioningError"`
+	} `json:"properties"`
+}
+
+// Health reads the health of the latest revision of each container app.
+func (a *azureProvider) Health(ids map[string]string) ([]types.FunctionHealth, error) {
+	return common.CheckHealth(ids, func(name, id string) types.FunctionHealth {
+		app := &containerAppStatus{}
+		if err := armGet(id, containerAppsAPIVersion, app); err != nil {
+			return types.FunctionHealth{Function: name, Reason: err.Error()}
+		}
+		if app.Properties.LatestRevisionName == "" {
+			return types.FunctionHealth{Function: name, Reason: "the app has no revision yet"}
+		}
+
+		rev := &revisionStatus{}
+		if err := armGet(id+"/revisions/"+app.Properties.LatestRevisionName, containerAppsAPIVersion, rev); err != nil {
+			return types.FunctionHealth{Function: name, Reason: err.Error()}
+		}
+		return revisionHealth(name, app.Properties.LatestRevisionName, rev)
+	}), nil
+}
+
+// revisionHealth is healthy once the revision is provisioned and its replicas pass their health probes.
+func revisionHealth(name, revision string, rev *revisionStatus) types.FunctionHealth {
+	p := rev.Properties
+	switch {
+	case p.ProvisioningState == "Failed":
+		reason := "revision " + revision + " failed to provision"
+		if p.ProvisioningError != "" {
+			reason += ": " + p.ProvisioningError
+		}
+		return types.FunctionHealth{Function: name, Reason: reason}
+	case p.ProvisioningState != "Provisioned":
+		return types.FunctionHealth{Function: name, Reason: "revision " + revision + " is " + p.ProvisioningState}
+	case p.HealthState != "Healthy":
+		return types.FunctionHealth{Function: name, Reason: "revision " + revision + " is " + p.HealthState}
+	}
+	return types.FunctionHealth{Function: name, Healthy: true}
+}
@@ -0,0 +1,65 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+func Test_revisionHealth(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want types.FunctionHealth
+	}{
+		{
+			name: "healthy",
+			body: `{"properties":{"healthState":"Healthy","provisioningState":"Provisioned"}}`,
+			want: types.FunctionHealth{Function: "orders", Healthy: true},
+		},
+		{
+			name: "provisioning",
+			body: `{"properties":{"healthState":"None","provisioningState":"Provisioning"}}`,
+			want: types.FunctionHealth{Function: "orders", Reason: "revision orders--abc12 is Provisioning"},
+		},
+		{
+			name: "unhealthy",
+			body: `{"properties":{"healthState":"Unhealthy","provisioningState":"Provisioned"}}`,
+			want: types.FunctionHealth{Function: "orders", Reason: "revision orders--abc12 is Unhealthy"},
+		},
+		{
+			name: "failed",
+			body: `{"properties":{"healthState":"None","provisioningState":"Failed","provisioningError":"image pull failed"}}`,
+			want: types.FunctionHealth{Function: "orders", Reason: "revision orders--abc12 failed to provision: image pull failed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rev := &revisionStatus{}
+			if err := json.Unmarshal([]byte(tt.body), rev); err != nil {
+				t.Fatal(err)
+			}
+			if got := revisionHealth("orders", "orders--abc12", rev); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("revisionHealth() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
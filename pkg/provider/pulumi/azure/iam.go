@@ -0,0 +1,85 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var _ common.Bootstrapper = &azureProvider{}
+
+// deployerActions are the actions used to create, update and destroy the resources of a stack.
+var deployerActions = []string{
+	"Microsoft.Resources/subscriptions/resourceGroups/*",
+	"Microsoft.Resources/deployments/*",
+	"Microsoft.App/*",
+	"Microsoft.Web/*",
+	"Microsoft.ContainerRegistry/*",
+	"Microsoft.OperationalInsights/*",
+	"Microsoft.Storage/*",
+	"Microsoft.DocumentDB/*",
+	"Microsoft.EventGrid/*",
+	"Microsoft.ApiManagement/*",
+	"Microsoft.KeyVault/*",
+	"Microsoft.Cdn/*",
+	"Microsoft.Logic/*",
+	"Microsoft.DBforPostgreSQL/*",
+	"Microsoft.Cache/*",
+	"Microsoft.Communication/*",
+	"Microsoft.Insights/*",
+	"Microsoft.Network/privateEndpoints/*",
+	"Microsoft.Authorization/roleAssignments/*",
+	// each app is given a custom role on the containers of its buckets
+	"Microsoft.Authorization/roleDefinitions/*",
+}
+
+// DeployerRole returns a custom role definition with the actions that deploy a stack, it is
+// assigned to a service principal created with the az cli.
+func (a *azureProvider) DeployerRole(name string) (*types.DeployerRole, error) {
+	role, err := json.MarshalIndent(map[string]interface{}{
+		"Name":             name,
+		"IsCustom":         true,
+		"Description":      "deploys the stacks of " + a.proj.Name,
+		"Actions":          deployerActions,
+		"NotActions":       []string{},
+		"DataActions":      []string{"Microsoft.KeyVault/vaults/keys/decrypt/action"},
+		"AssignableScopes": []string{"/subscriptions/<subscription-id>"},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.DeployerRole{
+		Name:   name,
+		Policy: string(role),
+		Instructions: []string{
+			"save the role definition as role.json, replacing <subscription-id>",
+			"az role definition create --role-definition @role.json",
+			fmt.Sprintf("az ad sp create-for-rbac --name %s --role %s --scopes /subscriptions/<subscription-id>", name, name),
+			"grant the service principal the Application.ReadWrite.OwnedBy Microsoft Graph permission, stacks create an application per function",
+		},
+	}, nil
+}
+
+func (a *azureProvider) CreateDeployerRole(role *types.DeployerRole) error {
+	return utils.NewNotSupportedErr("azure deployer roles are created with the az cli, run 'nitric iam bootstrap -t azure' without --create")
+}
@@ -0,0 +1,77 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+func TestDeployerRole(t *testing.T) {
+	a := New(&project.Project{Name: "shop"}, &stack.Config{Name: "deployer", Provider: stack.Azure}, map[string]string{}).(*azureProvider)
+
+	role, err := a.DeployerRole("shop-deployer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	definition := struct {
+		Name        string
+		IsCustom    bool
+		Description string
+		Actions     []string
+		DataActions []string
+	}{}
+	if err := json.Unmarshal([]byte(role.Policy), &definition); err != nil {
+		t.Fatalf("role definition is not json: %v", err)
+	}
+	if definition.Name != "shop-deployer" || !definition.IsCustom || definition.Description != "deploys the stacks of shop" {
+		t.Errorf("role definition = %s, want the custom role shop-deployer of shop", role.Policy)
+	}
+	actions := map[string]bool{}
+	for _, action := range definition.Actions {
+		actions[action] = true
+	}
+	tests := []struct {
+		action string
+		want   bool
+	}{
+		{action: "Microsoft.App/*", want: true},
+		{action: "Microsoft.Authorization/roleAssignments/*", want: true},
+		{action: "Microsoft.Authorization/roleDefinitions/*", want: true},
+		{action: "*", want: false},
+		{action: "Microsoft.Authorization/*", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			if actions[tt.action] != tt.want {
+				t.Errorf("role definition allows %s = %v, want %v", tt.action, actions[tt.action], tt.want)
+			}
+		})
+	}
+	if len(definition.DataActions) != 1 || definition.DataActions[0] != "Microsoft.KeyVault/vaults/keys/decrypt/action" {
+		t.Errorf("data actions = %v, want only key vault decryption", definition.DataActions)
+	}
+
+	if _, ok := a.CreateDeployerRole(role).(*utils.NotSupportedError); !ok {
+		t.Error("CreateDeployerRole() expected a not supported error")
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/logic"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+type KeepWarmArgs struct {
+	ResourceGroupName pulumi.StringInput
+	Location          pulumi.StringInput
+	Minutes           int
+	App               *ContainerApp
+}
+
+type KeepWarm struct {
+	pulumi.ResourceState
+
+	Name     string
+	Workflow *logic.Workflow
+}
+
+// newKeepWarm pings the container app from a Logic App on a recurrence so a replica is kept warm.
+func newKeepWarm(ctx *pulumi.Context, name string, args *KeepWarmArgs, opts ...pulumi.ResourceOption) (*KeepWarm, error) {
+	res := &KeepWarm{Name: name}
+	err := ctx.RegisterComponentResource("nitric:func:AzureKeepWarm", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	definition := args.App.App.LatestRevisionFqdn.ApplyT(func(fqdn string) map[string]interface{} {
+		return keepWarmDefinition(fqdn, args.Minutes)
+	})
+
+	res.Workflow, err = logic.NewWorkflow(ctx, resourceName(ctx, name, LogicAppRT), &logic.WorkflowArgs{
+		ResourceGroupName: args.ResourceGroupName,
+		Location:          args.Location,
+		Definition:        definition,
+		State:             pulumi.String("Enabled"),
+		Tags:              common.Tags(ctx, name),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":     pulumi.String(res.Name),
+		"workflow": res.Workflow,
+	})
+}
+
+// keepWarmDefinition is a Logic App workflow that GETs the app every interval of minutes.
+func keepWarmDefinition(fqdn string, minutes int) map[string]interface{} {
+	return map[string]interface{}{
+		"$schema":        "https://schema.management.azure.com/providers/Microsoft.Logic/schemas/2016-06-01/workflowdefinition.json#",
+		"contentVersion": "1.0.0.0",
+		"triggers": map[string]interface{}{
+			"Recurrence": map[string]interface{}{
+				"type": "Recurrence",
+				"recurrence": map[string]interface{}{
+					"frequency": "Minute",
+					"interval":  minutes,
+				},
+			},
+		},
+		"actions": map[string]interface{}{
+			"Ping": map[string]interface{}{
+				"type": "Http",
+				"inputs": map[string]interface{}{
+					"method": "GET",
+					"uri":    "https://" + fqdn,
+				},
+			},
+		},
+	}
+}
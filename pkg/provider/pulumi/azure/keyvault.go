@@ -0,0 +1,211 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/authorization"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/keyvault"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/resources"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+var _ common.Purger = &azureProvider{}
+
+const (
+	keyVaultAPIVersion = "2019-09-01"
+	// purgeTimeout is how long a purge of a deleted vault is waited for
+	purgeTimeout = 10 * time.Minute
+)
+
+// validateKeyVault checks the soft delete settings of the stack's vault.
+func validateKeyVault(kv *stack.KeyVault) error {
+	if kv == nil {
+		return nil
+	}
+	if kv.SoftDeleteRetentionDays != 0 && (kv.SoftDeleteRetentionDays < 7 || kv.SoftDeleteRetentionDays > 90) {
+		return fmt.Errorf("keyVault softDeleteRetentionDays must be between 7 and 90, not %d", kv.SoftDeleteRetentionDays)
+	}
+	switch kv.Deleted {
+	case "", stack.KeyVaultRecover, stack.KeyVaultPurge:
+	default:
+		return fmt.Errorf("keyVault deleted must be %s or %s, not %s", stack.KeyVaultRecover, stack.KeyVaultPurge, kv.Deleted)
+	}
+	if kv.SoftDeleteRetentionDays == 0 && (kv.PurgeProtection || kv.Deleted != "") {
+		return errors.New("keyVault purgeProtection and deleted require softDeleteRetentionDays")
+	}
+	if kv.PurgeProtection && kv.Deleted == stack.KeyVaultPurge {
+		return fmt.Errorf("keyVault deleted can't be %s with purgeProtection, which stops deleted vaults being purged", stack.KeyVaultPurge)
+	}
+	return nil
+}
+
+// softDeletes is true when the stack's vault is kept for a time once deleted.
+func (a *azureProvider) softDeletes() bool {
+	return a.sc.KeyVault != nil && a.sc.KeyVault.SoftDeleteRetentionDays > 0
+}
+
+// newKeyVault creates the stack's vault for secrets. A soft deleted vault keeps its name until it is purged,
+// so with soft delete on the vault is named the same each time and one left by stack down is recovered or purged.
+func (a *azureProvider) newKeyVault(ctx *pulumi.Context, rg *resources.ResourceGroup, clientConfig *authorization.GetClientConfigResult) (*keyvault.Vault, error) {
+	kvName := resourceName(ctx, "", KeyVaultRT)
+	props := &keyvault.VaultPropertiesArgs{
+		EnableSoftDelete:        pulumi.Bool(a.softDeletes()),
+		EnableRbacAuthorization: pulumi.Bool(true),
+		Sku: &keyvault.SkuArgs{
+			Family: pulumi.String("A"),
+			Name:   keyvault.SkuNameStandard,
+		},
+		TenantId: pulumi.String(clientConfig.TenantId),
+	}
+
+	var vaultName pulumi.StringPtrInput
+	if a.softDeletes() {
+		kv := a.sc.KeyVault
+		props.SoftDeleteRetentionInDays = pulumi.Int(kv.SoftDeleteRetentionDays)
+		if kv.PurgeProtection {
+			// the api rejects turning it off, so it is only set when on
+			props.EnablePurgeProtection = pulumi.Bool(true)
+		}
+
+		name := stableName(ctx, kvName)
+		vaultName = pulumi.String(name)
+
+		deleted, err := deletedVaultExists(clientConfig.SubscriptionId, a.sc.Region, name)
+		if err != nil {
+			return nil, errors.WithMessage(err, "deleted key vault")
+		}
+		switch {
+		case deleted && kv.Deleted == stack.KeyVaultPurge:
+			_ = ctx.Log.Info("purging the deleted key vault "+name, nil)
+			if !ctx.DryRun() {
+				if err := purgeDeletedVault(clientConfig.SubscriptionId, a.sc.Region, name); err != nil {
+					return nil, errors.WithMessage(err, "purging the deleted key vault "+name)
+				}
+			}
+		case deleted:
+			_ = ctx.Log.Info("recovering the deleted key vault "+name+" with its secrets", nil)
+			props.CreateMode = keyvault.CreateModeRecover
+		}
+	}
+
+	kv, err := keyvault.NewVault(ctx, kvName, &keyvault.VaultArgs{
+		VaultName:         vaultName,
+		Location:          rg.Location,
+		ResourceGroupName: rg.Name,
+		Properties:        props,
+		Tags:              common.Tags(ctx, kvName),
+	}, pulumi.Protect(len(a.proj.Secrets) > 0))
+	if err != nil {
+		return nil, err
+	}
+
+	// stack down purges the vault by its id
+	ctx.Export("keyvault:id", kv.ID())
+	return kv, nil
+}
+
+// PurgeDeleted purges the soft deleted vault of the stack when it is configured to be purged.
+func (a *azureProvider) PurgeDeleted(outputs map[string]string, log output.Progress) error {
+	if !a.softDeletes() || a.sc.KeyVault.Deleted != stack.KeyVaultPurge || outputs["keyvault:id"] == "" {
+		return nil
+	}
+
+	subscriptionID, name, err := parseVaultID(outputs["keyvault:id"])
+	if err != nil {
+		return err
+	}
+
+	log.Busyf("Purging the deleted key vault %s", name)
+	if err := purgeDeletedVault(subscriptionID, a.sc.Region, name); err != nil {
+		return errors.WithMessage(err, "purging the deleted key vault "+name)
+	}
+	log.Successf("Purged the deleted key vault %s\n", name)
+	return nil
+}
+
+// parseVaultID returns the subscription and name of the vault from its resource id,
+// /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.KeyVault/vaults/<name>.
+func parseVaultID(id string) (string, string, error) {
+	parts := strings.Split(strings.Trim(id, "/"), "/")
+	if len(parts) != 8 || parts[0] != "subscriptions" || parts[6] != "vaults" {
+		return "", "", fmt.Errorf("%s is not a key vault id", id)
+	}
+	return parts[1], parts[7], nil
+}
+
+// deletedVault calls the operation (empty for the vault itself) of a soft deleted vault, returning the status code.
+// Not found is not an error, as the vault has not been deleted or has been purged.
+func deletedVault(method, subscriptionID, location, name, operation string) (int, error) {
+	token, err := azToken("https://management.azure.com")
+	if err != nil {
+		return 0, errors.WithMessage(err, "management token")
+	}
+
+	url := fmt.Sprintf("https://management.azure.com/subscriptions/%s/providers/Microsoft.KeyVault/locations/%s/deletedVaults/%s%s?api-version=%s",
+		subscriptionID, location, name, operation, keyVaultAPIVersion)
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusNotFound {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return resp.StatusCode, fmt.Errorf("%s of deleted key vault %s failed with %s: %s", method, name, resp.Status, msg)
+	}
+	return resp.StatusCode, nil
+}
+
+func deletedVaultExists(subscriptionID, location, name string) (bool, error) {
+	code, err := deletedVault(http.MethodGet, subscriptionID, location, name, "")
+	return code == http.StatusOK, err
+}
+
+// purgeDeletedVault purges the vault and waits for it to be gone, so its name can be used again.
+func purgeDeletedVault(subscriptionID, location, name string) error {
+	if _, err := deletedVault(http.MethodPost, subscriptionID, location, name, "/purge"); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(purgeTimeout)
+	for {
+		exists, err := deletedVaultExists(subscriptionID, location, name)
+		if err != nil || !exists {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("the vault was not purged within %v", purgeTimeout)
+		}
+		time.Sleep(10 * time.Second)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func Test_validateKeyVault(t *testing.T) {
+	tests := []struct {
+		name    string
+		kv      *stack.KeyVault
+		wantErr bool
+	}{
+		{
+			name: "not configured",
+		},
+		{
+			name: "recover",
+			kv:   &stack.KeyVault{SoftDeleteRetentionDays: 7, PurgeProtection: true, Deleted: stack.KeyVaultRecover},
+		},
+		{
+			name: "purge",
+			kv:   &stack.KeyVault{SoftDeleteRetentionDays: 90, Deleted: stack.KeyVaultPurge},
+		},
+		{
+			name:    "retention too short",
+			kv:      &stack.KeyVault{SoftDeleteRetentionDays: 3},
+			wantErr: true,
+		},
+		{
+			name:    "unknown deleted",
+			kv:      &stack.KeyVault{SoftDeleteRetentionDays: 7, Deleted: "keep"},
+			wantErr: true,
+		},
+		{
+			name:    "purge protection without soft delete",
+			kv:      &stack.KeyVault{PurgeProtection: true},
+			wantErr: true,
+		},
+		{
+			name:    "purge with purge protection",
+			kv:      &stack.KeyVault{SoftDeleteRetentionDays: 7, PurgeProtection: true, Deleted: stack.KeyVaultPurge},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateKeyVault(tt.kv); (err != nil) != tt.wantErr {
+				t.Errorf("validateKeyVault() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_parseVaultID(t *testing.T) {
+	sub, name, err := parseVaultID("/subscriptions/1234/resourceGroups/app-rg/providers/Microsoft.KeyVault/vaults/appprodkv1a2b3c4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub != "1234" || name != "appprodkv1a2b3c4" {
+		t.Errorf("parseVaultID() = %s, %s, want 1234, appprodkv1a2b3c4", sub, name)
+	}
+
+	if _, _, err := parseVaultID("/subscriptions/1234/resourceGroups/app-rg"); err == nil {
+		t.Errorf("parseVaultID() error = nil, want an error for a resource group id")
+	}
+}
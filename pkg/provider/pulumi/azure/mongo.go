@@ -21,10 +21,16 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/documentdb"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/network"
 	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/resources"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/stack"
 )
 
+// azureDatacenters is the Cosmos DB IP rule that accepts connections from within public Azure datacenters.
+const azureDatacenters = "0.0.0.0"
+
 type MongoCollectionsArgs struct {
 	ResourceGroup *resources.ResourceGroup
 }
@@ -63,7 +69,20 @@ func (a *azureProvider) newMongoCollections(ctx *pulumi.Context, name string, ar
 		secondaryGeo.LocationName = pulumi.String("northeurope")
 	}
 
-	res.Account, err = documentdb.NewDatabaseAccount(ctx, resourceName(ctx, name, CosmosDBAccountRT), &documentdb.DatabaseAccountArgs{
+	// Continuous backup keeps 30 days of point in time restores, the schedule and retention are managed by Azure.
+	var backupPolicy pulumi.Input
+	if a.sc.Backups != nil {
+		if a.sc.Backups.Schedule != "" || a.sc.Backups.RetentionDays != 0 {
+			_ = ctx.Log.Warn("Cosmos DB continuous backup does not support a custom schedule or retention, they will be ignored", &pulumi.LogArgs{})
+		}
+		backupPolicy = documentdb.ContinuousModeBackupPolicyArgs{
+			Type: pulumi.String("Continuous"),
+		}
+	}
+
+	accName := resourceName(ctx, name, CosmosDBAccountRT)
+	res.Account, err = documentdb.NewDatabaseAccount(ctx, accName, &documentdb.DatabaseAccountArgs{
+		AccountName:       a.names.Name(ctx, "azure-native:documentdb:DatabaseAccount", accName, stableName(ctx, accName)),
 		ResourceGroupName: args.ResourceGroup.Name,
 		Kind:              pulumi.String("MongoDB"),
 
@@ -81,11 +100,36 @@ func (a *azureProvider) newMongoCollections(ctx *pulumi.Context, name string, ar
 			IsZoneRedundant:  pulumi.BoolPtr(false),
 			LocationName:     pulumi.String("eastus"),
 		}},
-	}, pulumi.Parent(res))
+		BackupPolicy:                  backupPolicy,
+		IpRules:                       cosmosIpRules(a.sc.Cosmos),
+		IsVirtualNetworkFilterEnabled: pulumi.Bool(len(cosmosNetwork(a.sc.Cosmos).Subnets) > 0),
+		VirtualNetworkRules:           cosmosVirtualNetworkRules(a.sc.Cosmos),
+		PublicNetworkAccess:           cosmosPublicNetworkAccess(a.sc.Cosmos),
+	}, pulumi.Parent(res), pulumi.Protect(true))
 	if err != nil {
 		return nil, errors.WithMessage(err, "cosmosdb account")
 	}
 
+	for i, subnet := range cosmosNetwork(a.sc.Cosmos).PrivateEndpointSubnets {
+		_, err = network.NewPrivateEndpoint(ctx, resourceName(ctx, fmt.Sprintf("%s-pe%d", name, i), PrivateEndpointRT), &network.PrivateEndpointArgs{
+			ResourceGroupName: args.ResourceGroup.Name,
+			Location:          args.ResourceGroup.Location,
+			Subnet: &network.SubnetTypeArgs{
+				Id: pulumi.String(subnet),
+			},
+			PrivateLinkServiceConnections: network.PrivateLinkServiceConnectionArray{
+				network.PrivateLinkServiceConnectionArgs{
+					Name:                 pulumi.Sprintf("%s-pe%d", name, i),
+					PrivateLinkServiceId: res.Account.ID(),
+					GroupIds:             pulumi.StringArray{pulumi.String("MongoDB")},
+				},
+			},
+		}, pulumi.Parent(res))
+		if err != nil {
+			return nil, errors.WithMessage(err, "cosmosdb private endpoint")
+		}
+	}
+
 	res.MongoDB, err = documentdb.NewMongoDBResourceMongoDBDatabase(ctx, resourceName(ctx, name, MongoDBRT), &documentdb.MongoDBResourceMongoDBDatabaseArgs{
 		ResourceGroupName: args.ResourceGroup.Name,
 		AccountName:       res.Account.Name,
@@ -94,7 +138,7 @@ func (a *azureProvider) newMongoCollections(ctx *pulumi.Context, name string, ar
 		Resource: documentdb.MongoDBDatabaseResourceArgs{
 			Id: pulumi.String(name),
 		},
-	}, pulumi.Parent(res))
+	}, pulumi.Parent(res), pulumi.Protect(true))
 	if err != nil {
 		return nil, errors.WithMessage(err, "mongo db")
 	}
@@ -110,13 +154,14 @@ func (a *azureProvider) newMongoCollections(ctx *pulumi.Context, name string, ar
 			Resource: documentdb.MongoDBCollectionResourceArgs{
 				Id: pulumi.String(k),
 			},
-		}, pulumi.Parent(res))
+		}, pulumi.Parent(res), pulumi.Protect(true))
 		if err != nil {
 			return nil, errors.WithMessage(err, "mongo collection")
 		}
 	}
 
-	connectionString := pulumi.All(args.ResourceGroup.Name, res.Account.Name).ApplyT(func(args []interface{}) (string, error) {
+	// the connection string holds the account key, as a secret it is encrypted in the state and masked in the output
+	connectionString := pulumi.ToSecret(pulumi.All(args.ResourceGroup.Name, res.Account.Name).ApplyT(func(args []interface{}) (string, error) {
 		rgName := args[0].(string)
 		acctName := args[1].(string)
 		connStr, err := documentdb.ListDatabaseAccountConnectionStrings(ctx, &documentdb.ListDatabaseAccountConnectionStringsArgs{
@@ -133,7 +178,7 @@ func (a *azureProvider) newMongoCollections(ctx *pulumi.Context, name string, ar
 		}
 
 		return connStr.ConnectionStrings[0].ConnectionString, nil
-	}).(pulumi.StringOutput)
+	})).(pulumi.StringOutput)
 
 	res.ConnectionString = connectionString
 
@@ -143,3 +188,36 @@ func (a *azureProvider) newMongoCollections(ctx *pulumi.Context, name string, ar
 		"connectionString":  connectionString,
 	})
 }
+
+func cosmosNetwork(n *stack.CosmosNetwork) *stack.CosmosNetwork {
+	if n == nil {
+		return &stack.CosmosNetwork{}
+	}
+	return n
+}
+
+// cosmosIpRules allows the configured addresses, along with Azure so the container apps can connect.
+func cosmosIpRules(n *stack.CosmosNetwork) documentdb.IpAddressOrRangeArray {
+	rules := documentdb.IpAddressOrRangeArray{
+		documentdb.IpAddressOrRangeArgs{IpAddressOrRange: pulumi.String(azureDatacenters)},
+	}
+	for _, ip := range cosmosNetwork(n).AllowedIPs {
+		rules = append(rules, documentdb.IpAddressOrRangeArgs{IpAddressOrRange: pulumi.String(ip)})
+	}
+	return rules
+}
+
+func cosmosVirtualNetworkRules(n *stack.CosmosNetwork) documentdb.VirtualNetworkRuleArray {
+	rules := documentdb.VirtualNetworkRuleArray{}
+	for _, subnet := range cosmosNetwork(n).Subnets {
+		rules = append(rules, documentdb.VirtualNetworkRuleArgs{Id: pulumi.String(subnet)})
+	}
+	return rules
+}
+
+func cosmosPublicNetworkAccess(n *stack.CosmosNetwork) pulumi.StringPtrInput {
+	if cosmosNetwork(n).DisablePublicAccess {
+		return pulumi.String("Disabled")
+	}
+	return pulumi.String("Enabled")
+}
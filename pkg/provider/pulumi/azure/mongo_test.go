@@ -0,0 +1,57 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/documentdb"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func Test_cosmosIpRules(t *testing.T) {
+	tests := []struct {
+		name string
+		n    *stack.CosmosNetwork
+		want documentdb.IpAddressOrRangeArray
+	}{
+		{
+			name: "default",
+			want: documentdb.IpAddressOrRangeArray{
+				documentdb.IpAddressOrRangeArgs{IpAddressOrRange: pulumi.String("0.0.0.0")},
+			},
+		},
+		{
+			name: "allowed ips",
+			n:    &stack.CosmosNetwork{AllowedIPs: []string{"203.0.113.0/24"}},
+			want: documentdb.IpAddressOrRangeArray{
+				documentdb.IpAddressOrRangeArgs{IpAddressOrRange: pulumi.String("0.0.0.0")},
+				documentdb.IpAddressOrRangeArgs{IpAddressOrRange: pulumi.String("203.0.113.0/24")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cosmosIpRules(tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cosmosIpRules() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
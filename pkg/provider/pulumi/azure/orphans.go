@@ -0,0 +1,162 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+// resourcesAPIVersion is the version of the resource manager API the tagged resources are found with.
+const resourcesAPIVersion = "2021-04-01"
+
+var _ common.Collector = &azureProvider{}
+
+type armResources struct {
+	Value []struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	} `json:"value"`
+	NextLink string `json:"nextLink"`
+}
+
+type armProvider struct {
+	ResourceTypes []struct {
+		ResourceType string   `json:"resourceType"`
+		ApiVersions  []string `json:"apiVersions"`
+	} `json:"resourceTypes"`
+}
+
+// armRequest calls the resource manager API, decoding the response into out when it is not nil.
+func armRequest(method, u string, out interface{}) error {
+	token, err := azToken("https://management.azure.com")
+	if err != nil {
+		return errors.WithMessage(err, "management token")
+	}
+
+	req, err := http.NewRequest(method, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s failed with %s: %s", method, resp.Status, body)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
+
+func azSubscription() (string, error) {
+	out, err := exec.Command("az", "account", "show", "--query", "id", "--output", "tsv").Output()
+	if ee, ok := err.(*exec.ExitError); ok {
+		return "", errors.WithMessage(err, strings.TrimSpace(string(ee.Stderr)))
+	}
+	return strings.TrimSpace(string(out)), err
+}
+
+// TaggedResources finds the resources of the current subscription with the stack's x-nitric-stack tag.
+func (a *azureProvider) TaggedResources(stackName string) ([]types.Orphan, error) {
+	sub, err := azSubscription()
+	if err != nil {
+		return nil, errors.WithMessage(err, "subscription")
+	}
+
+	filter := url.QueryEscape(fmt.Sprintf("tagName eq 'x-nitric-stack' and tagValue eq '%s'", stackName))
+	next := fmt.Sprintf("https://management.azure.com/subscriptions/%s/resources?$filter=%s&api-version=%s", sub, filter, resourcesAPIVersion)
+
+	res := []types.Orphan{}
+	for next != "" {
+		page := &armResources{}
+		if err := armRequest(http.MethodGet, next, page); err != nil {
+			return nil, err
+		}
+		for _, r := range page.Value {
+			res = append(res, types.Orphan{Type: r.Type, ID: r.ID})
+		}
+		next = page.NextLink
+	}
+	return res, nil
+}
+
+// stableAPIVersion returns the newest version of a resource type's API that is not a preview, the versions are
+// listed newest first.
+func stableAPIVersion(versions []string) string {
+	for _, v := range versions {
+		if !strings.Contains(v, "preview") {
+			return v
+		}
+	}
+	if len(versions) > 0 {
+		return versions[0]
+	}
+	return ""
+}
+
+// DeleteOrphan deletes the resource with the newest API version of its type.
+func (a *azureProvider) DeleteOrphan(r types.Orphan) error {
+	sub, err := azSubscription()
+	if err != nil {
+		return errors.WithMessage(err, "subscription")
+	}
+
+	// e.g. Microsoft.Storage/storageAccounts, types can be nested as Microsoft.Sql/servers/databases
+	parts := strings.SplitN(r.Type, "/", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("resource type %s has no provider namespace", r.Type)
+	}
+
+	p := &armProvider{}
+	err = armRequest(http.MethodGet, fmt.Sprintf("https://management.azure.com/subscriptions/%s/providers/%s?api-version=%s", sub, parts[0], resourcesAPIVersion), p)
+	if err != nil {
+		return errors.WithMessage(err, "resource provider "+parts[0])
+	}
+
+	version := ""
+	for _, t := range p.ResourceTypes {
+		if strings.EqualFold(t.ResourceType, parts[1]) {
+			version = stableAPIVersion(t.ApiVersions)
+		}
+	}
+	if version == "" {
+		return fmt.Errorf("no API version found for %s", r.Type)
+	}
+
+	return armRequest(http.MethodDelete, fmt.Sprintf("https://management.azure.com%s?api-version=%s", r.ID, version), nil)
+}
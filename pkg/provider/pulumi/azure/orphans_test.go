@@ -0,0 +1,39 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import "testing"
+
+func Test_stableAPIVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		want     string
+	}{
+		{name: "stable first", versions: []string{"2021-09-01", "2021-08-01"}, want: "2021-09-01"},
+		{name: "preview first", versions: []string{"2022-05-01-preview", "2021-09-01"}, want: "2021-09-01"},
+		{name: "only previews", versions: []string{"2022-05-01-preview"}, want: "2022-05-01-preview"},
+		{name: "none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stableAPIVersion(tt.versions); got != tt.want {
+				t.Errorf("stableAPIVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
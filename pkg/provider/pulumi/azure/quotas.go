@@ -0,0 +1,97 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+const (
+	// eventGridAPIVersion is the version of the Event Grid management API the topics are listed with.
+	eventGridAPIVersion = "2021-12-01"
+	// maxEventGridTopics is the number of custom topics a subscription can have in each region.
+	maxEventGridTopics = 100
+)
+
+var _ common.QuotaChecker = &azureProvider{}
+
+type eventGridTopics struct {
+	Value []struct {
+		Location string            `json:"location"`
+		Tags     map[string]string `json:"tags"`
+	} `json:"value"`
+	NextLink string `json:"nextLink"`
+}
+
+// Quotas counts the Event Grid topics of the subscription in the stack's region, each of the project's
+// topics is an Event Grid topic, created by the stack unless it is an existing topic.
+func (a *azureProvider) Quotas() ([]types.Quota, error) {
+	created := 0
+	for name := range a.proj.Topics {
+		if _, ok := a.sc.ExistingTopics[name]; !ok {
+			created++
+		}
+	}
+	if created == 0 {
+		return []types.Quota{}, nil
+	}
+
+	sub, err := azSubscription()
+	if err != nil {
+		return nil, errors.WithMessage(err, "subscription")
+	}
+
+	others := 0
+	next := fmt.Sprintf("https://management.azure.com/subscriptions/%s/providers/Microsoft.EventGrid/topics?api-version=%s", sub, eventGridAPIVersion)
+	for next != "" {
+		page := &eventGridTopics{}
+		if err := armRequest(http.MethodGet, next, page); err != nil {
+			return nil, errors.WithMessage(err, "event grid topics")
+		}
+		others += otherStackTopics(page, a.sc.Region, a.proj.Name+"-"+a.sc.Name)
+		next = page.NextLink
+	}
+
+	return []types.Quota{{
+		Name:        "Event Grid topics in " + a.sc.Region,
+		Limit:       maxEventGridTopics,
+		Needed:      float64(others + created),
+		IncreaseUrl: "https://portal.azure.com/#blade/Microsoft_Azure_Support/HelpAndSupportBlade/newsupportrequest",
+	}}, nil
+}
+
+// otherStackTopics counts the topics in the region that are not the stack's own, which it replaces when updated.
+func otherStackTopics(page *eventGridTopics, region, stackName string) int {
+	n := 0
+	for _, t := range page.Value {
+		if !strings.EqualFold(strings.ReplaceAll(t.Location, " ", ""), region) {
+			continue
+		}
+		if t.Tags["x-nitric-stack"] == stackName {
+			continue
+		}
+		n++
+	}
+	return n
+}
@@ -0,0 +1,38 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_otherStackTopics(t *testing.T) {
+	body := `{"value":[
+		{"location":"eastus","tags":{"x-nitric-stack":"shop-prod"}},
+		{"location":"eastus","tags":{"x-nitric-stack":"shop-dev"}},
+		{"location":"East US"},
+		{"location":"westeurope"}
+	]}`
+	page := &eventGridTopics{}
+	if err := json.Unmarshal([]byte(body), page); err != nil {
+		t.Fatal(err)
+	}
+	if got := otherStackTopics(page, "eastus", "shop-prod"); got != 2 {
+		t.Errorf("otherStackTopics() = %d, want 2", got)
+	}
+}
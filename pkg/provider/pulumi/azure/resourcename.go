@@ -22,6 +22,7 @@ import (
 
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
 	"github.com/nitrictech/cli/pkg/utils"
 )
 
@@ -52,6 +53,7 @@ var (
 	// Alphanumerics and hyphens. Start and end with alphanumeric.
 	AnalyticsWorkspaceRT = ResouceType{Abbreviation: "log", MaxLen: 24, AllowHyphen: true}
 	AssignmentRT         = ResouceType{Abbreviation: "assign", MaxLen: 64, UseName: true}
+	RoleDefinitionRT     = ResouceType{Abbreviation: "role", MaxLen: 64, UseName: true}
 	// TODO find docs on this..
 	KubeRT = ResouceType{Abbreviation: "kube", MaxLen: 64, AllowUpperCase: true}
 	// lowercase letters, numbers, and the '-' character, and must be between 3 and 50 characters.
@@ -65,6 +67,8 @@ var (
 	ADServicePrincipalPasswordRT = ResouceType{Abbreviation: "aad-spp", MaxLen: 64, UseName: true}
 	// Lowercase letters and numbers.
 	StorageAccountRT = ResouceType{Abbreviation: "st", MaxLen: 24}
+	// Lowercase letters and numbers.
+	SiteStorageAccountRT = ResouceType{Abbreviation: "site", MaxLen: 24, UseName: true}
 	// 	Lowercase letters, numbers, and hyphens.
 	// Start with lowercase letter or number. Can't use consecutive hyphens.
 	StorageContainerRT = ResouceType{MaxLen: 63, AllowHyphen: true, UseName: true}
@@ -78,6 +82,9 @@ var (
 	//Alphanumerics and hyphens.
 	EventGridRT = ResouceType{Abbreviation: "evgt", MaxLen: 24, AllowUpperCase: true, AllowHyphen: true, UseName: true}
 
+	//Alphanumerics and hyphens.
+	SystemTopicRT = ResouceType{Abbreviation: "evgst", MaxLen: 128, AllowUpperCase: true, AllowHyphen: true}
+
 	//Alphanumerics and hyphens.
 	EventSubscriptionRT = ResouceType{Abbreviation: "sub", MaxLen: 24, AllowUpperCase: true, AllowHyphen: true, UseName: true}
 
@@ -89,8 +96,60 @@ var (
 
 	// Alphanumerics and hyphens, Start with letter and end with alphanumeric.
 	ApiOperationPolicyRT = ResouceType{Abbreviation: "api-op-pol", MaxLen: 80, AllowUpperCase: true, AllowHyphen: true, UseName: true}
+
+	// Alphanumerics and hyphens, Start with letter and end with alphanumeric.
+	ApiPolicyRT = ResouceType{Abbreviation: "api-pol", MaxLen: 80, AllowUpperCase: true, AllowHyphen: true, UseName: true}
+
+	// Alphanumerics and hyphens. Start and end with alphanumeric.
+	FrontDoorProfileRT = ResouceType{Abbreviation: "afd", MaxLen: 90, AllowUpperCase: true, AllowHyphen: true}
+	// Alphanumerics and hyphens. Start and end with alphanumeric.
+	FrontDoorEndpointRT = ResouceType{Abbreviation: "fde", MaxLen: 46, AllowUpperCase: true, AllowHyphen: true, UseName: true}
+	// Alphanumerics and hyphens. Start and end with alphanumeric.
+	FrontDoorOriginRT = ResouceType{Abbreviation: "fdo", MaxLen: 90, AllowUpperCase: true, AllowHyphen: true, UseName: true}
+	// Alphanumerics. Start with a letter.
+	FrontDoorRuleSetRT = ResouceType{Abbreviation: "fdrs", MaxLen: 60, AllowUpperCase: true, UseName: true}
+	// Alphanumerics. Start with a letter.
+	FrontDoorWafPolicyRT = ResouceType{Abbreviation: "fdwaf", MaxLen: 128, AllowUpperCase: true}
+	// Alphanumerics and hyphens. Start and end with alphanumeric.
+	FrontDoorSecurityPolicyRT = ResouceType{Abbreviation: "fdsp", MaxLen: 260, AllowUpperCase: true, AllowHyphen: true}
+
+	// Alphanumerics, hyphens, underscores, periods, and parenthesis.
+	LogicAppRT = ResouceType{Abbreviation: "logic", MaxLen: 80, AllowUpperCase: true, AllowHyphen: true, UseName: true}
+
+	// Alphanumerics, underscores, periods, and hyphens. Start with alphanumeric.
+	PrivateEndpointRT = ResouceType{Abbreviation: "pe", MaxLen: 80, AllowUpperCase: true, AllowHyphen: true, UseName: true}
+
+	// Lowercase letters, numbers, and hyphens. Can't start or end with hyphen.
+	PostgresServerRT = ResouceType{Abbreviation: "psql", MaxLen: 63, AllowHyphen: true, UseName: true}
+
+	// Alphanumerics and hyphens. Start and end with alphanumeric.
+	RedisCacheRT = ResouceType{Abbreviation: "redis", MaxLen: 63, AllowHyphen: true, UseName: true}
+
+	// Alphanumerics and hyphens. Start and end with alphanumeric.
+	CommunicationServiceRT = ResouceType{Abbreviation: "acs", MaxLen: 63, AllowHyphen: true, UseName: true}
+
+	// Alphanumerics and hyphens.
+	ActionGroupRT = ResouceType{Abbreviation: "ag", MaxLen: 260, AllowUpperCase: true, AllowHyphen: true}
+
+	// Alphanumerics, hyphens, underscores and periods.
+	MetricAlertRT = ResouceType{Abbreviation: "alert", MaxLen: 260, AllowUpperCase: true, AllowHyphen: true, UseName: true}
+
+	// Alphanumerics, hyphens, underscores, periods and parentheses.
+	AppInsightsRT = ResouceType{Abbreviation: "appi", MaxLen: 260, AllowUpperCase: true, AllowHyphen: true}
+
+	// Alphanumerics, hyphens, underscores and periods.
+	WebTestRT = ResouceType{Abbreviation: "webt", MaxLen: 260, AllowUpperCase: true, AllowHyphen: true, UseName: true}
+
+	// Alphanumerics, hyphens and underscores.
+	BudgetRT = ResouceType{Abbreviation: "budget", MaxLen: 63, AllowUpperCase: true, AllowHyphen: true}
 )
 
+// stableName is the name of a resource when the stack uses stable naming, the suffix fits in the space
+// resourceName leaves for pulumi's random suffix.
+func stableName(ctx *pulumi.Context, name string) string {
+	return name + common.StableSuffix(ctx, name)
+}
+
 func cleanPart(p string, rt ResouceType) string {
 	r := alphanumeric.ReplaceAllString(p, "")
 	if !rt.AllowHyphen {
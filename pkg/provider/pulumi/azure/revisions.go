@@ -0,0 +1,139 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	web "github.com/pulumi/pulumi-azure-native/sdk/go/azure/web/v20210301"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var _ common.RevisionLister = &azureProvider{}
+
+// validateRevisions checks the revisions are set for the project's functions and containers.
+func validateRevisions(proj *project.Project, revisions map[string]stack.Revisions) error {
+	units := map[string]bool{}
+	for _, c := range proj.Computes() {
+		units[c.Unit().Name] = true
+	}
+
+	errList := utils.NewErrorList()
+	for name, r := range revisions {
+		if !units[name] {
+			errList.Add(fmt.Errorf("revisions are set for %s, which is not a function or container of the project", name))
+			continue
+		}
+		errList.Add(errors.WithMessage(r.Validate(), name))
+	}
+	return errList.Aggregate()
+}
+
+// activeRevisionsMode is the app's revisions mode, the default single mode when it is not set.
+func activeRevisionsMode(r stack.Revisions) pulumi.StringPtrInput {
+	if r.Mode == "" {
+		return nil
+	}
+	return pulumi.StringPtr(r.Mode)
+}
+
+// revisionSuffix names the revision deployed by the update, Azure generates a name when it is not set.
+func revisionSuffix(r stack.Revisions) pulumi.StringPtrInput {
+	if r.Suffix == "" {
+		return nil
+	}
+	return pulumi.StringPtr(r.Suffix)
+}
+
+// trafficArgs splits the app's requests between its revisions, all go to the latest when it is not set.
+func trafficArgs(r stack.Revisions) web.TrafficWeightArrayInput {
+	if len(r.Traffic) == 0 {
+		return nil
+	}
+
+	names := []string{}
+	for name := range r.Traffic {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	traffic := web.TrafficWeightArray{}
+	for _, name := range names {
+		w := web.TrafficWeightArgs{Weight: pulumi.IntPtr(r.Traffic[name])}
+		if name == stack.LatestRevision {
+			w.LatestRevision = pulumi.BoolPtr(true)
+		} else {
+			w.RevisionName = pulumi.StringPtr(name)
+		}
+		traffic = append(traffic, w)
+	}
+	return traffic
+}
+
+type revisionList struct {
+	Value []struct {
+		Name       string `json:"name"`
+		Properties struct {
+			CreatedTime       string `json:"createdTime"`
+			Active            bool   `json:"active"`
+			TrafficWeight     int    `json:"trafficWeight"`
+			Replicas          int    `json:"replicas"`
+			HealthState       string `json:"healthState"`
+			ProvisioningState string `json:"provisioningState"`
+		} `json:"properties"`
+	} `json:"value"`
+}
+
+// Revisions lists the revisions of the container app, the newest first.
+func (a *azureProvider) Revisions(id string) ([]types.Revision, error) {
+	list := &revisionList{}
+	if err := armGet(id+"/revisions", containerAppsAPIVersion, list); err != nil {
+		return nil, err
+	}
+	return revisions(list), nil
+}
+
+func revisions(list *revisionList) []types.Revision {
+	revs := []types.Revision{}
+	for _, v := range list.Value {
+		state := v.Properties.ProvisioningState
+		if state == "Provisioned" && v.Properties.HealthState != "" {
+			state = v.Properties.HealthState
+		}
+		revs = append(revs, types.Revision{
+			Name:     v.Name,
+			Created:  v.Properties.CreatedTime,
+			Active:   v.Properties.Active,
+			Traffic:  v.Properties.TrafficWeight,
+			Replicas: v.Properties.Replicas,
+			State:    state,
+		})
+	}
+	// the times are RFC 3339, so they sort as strings
+	sort.SliceStable(revs, func(i, j int) bool {
+		return revs[i].Created > revs[j].Created
+	})
+	return revs
+}
@@ -0,0 +1,92 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func Test_revisions(t *testing.T) {
+	body := `{"value":[
+		{"name":"orders--v1","properties":{"createdTime":"2022-03-01T10:00:00Z","active":true,"trafficWeight":90,"replicas":2,"healthState":"Healthy","provisioningState":"Provisioned"}},
+		{"name":"orders--v2","properties":{"createdTime":"2022-03-02T10:00:00Z","active":true,"trafficWeight":10,"replicas":1,"healthState":"Unhealthy","provisioningState":"Provisioned"}},
+		{"name":"orders--v0","properties":{"createdTime":"2022-02-01T10:00:00Z","active":false,"provisioningState":"Deprovisioned"}}
+	]}`
+	list := &revisionList{}
+	if err := json.Unmarshal([]byte(body), list); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []types.Revision{
+		{Name: "orders--v2", Created: "2022-03-02T10:00:00Z", Active: true, Traffic: 10, Replicas: 1, State: "Unhealthy"},
+		{Name: "orders--v1", Created: "2022-03-01T10:00:00Z", Active: true, Traffic: 90, Replicas: 2, State: "Healthy"},
+		{Name: "orders--v0", Created: "2022-02-01T10:00:00Z", State: "Deprovisioned"},
+	}
+	if got := revisions(list); !reflect.DeepEqual(got, want) {
+		t.Errorf("revisions() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_validateRevisions(t *testing.T) {
+	proj := &project.Project{
+		Functions: map[string]project.Function{
+			"orders": {ComputeUnit: project.ComputeUnit{Name: "orders"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		revisions map[string]stack.Revisions
+		wantErr   string
+	}{
+		{
+			name:      "split",
+			revisions: map[string]stack.Revisions{"orders": {Mode: stack.RevisionsMultiple, Traffic: map[string]int{"latest": 20, "orders--v1": 80}}},
+		},
+		{
+			name:      "unknown function",
+			revisions: map[string]stack.Revisions{"users": {Mode: stack.RevisionsMultiple}},
+			wantErr:   "revisions are set for users, which is not a function or container of the project",
+		},
+		{
+			name:      "invalid",
+			revisions: map[string]stack.Revisions{"orders": {Traffic: map[string]int{"latest": 50}}},
+			wantErr:   "orders: the traffic weights of the revisions add up to 50, not 100",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRevisions(proj, tt.revisions)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateRevisions() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateRevisions() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package azure
+
+import (
+	"path/filepath"
+
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/storage"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+type SiteArgs struct {
+	ResourceGroupName pulumi.StringInput
+	ProjectDir        string
+	Site              project.Site
+	Env               pulumi.StringOutput
+	Tls               stack.Tls
+}
+
+type Site struct {
+	pulumi.ResourceState
+
+	Name    string
+	Account *storage.StorageAccount
+}
+
+func newSite(ctx *pulumi.Context, name string, args *SiteArgs, opts ...pulumi.ResourceOption) (*Site, error) {
+	res := &Site{Name: name}
+	err := ctx.RegisterComponentResource("nitric:site:AzureSite", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// static website hosting is configured per storage account, so each site gets its own.
+	accName := resourceName(ctx, name, SiteStorageAccountRT)
+	res.Account, err = storage.NewStorageAccount(ctx, accName, &storage.StorageAccountArgs{
+		AccessTier:        storage.AccessTierHot,
+		ResourceGroupName: args.ResourceGroupName,
+		Kind:              pulumi.String("StorageV2"),
+		Sku: storage.SkuArgs{
+			Name: pulumi.String(storage.SkuName_Standard_LRS),
+		},
+		MinimumTlsVersion:      pulumi.String(storageTlsVersion(args.Tls)),
+		EnableHttpsTrafficOnly: pulumi.Bool(!args.Tls.AllowHttp),
+		Tags:                   common.Tags(ctx, accName),
+	}, pulumi.Parent(res))
+	if err != nil {
+		return nil, err
+	}
+
+	index := args.Site.IndexDocument
+	if index == "" {
+		index = "index.html"
+	}
+
+	websiteArgs := &storage.StorageAccountStaticWebsiteArgs{
+		ResourceGroupName: args.ResourceGroupName,
+		AccountName:       res.Account.Name,
+		IndexDocument:     pulumi.String(index),
+	}
+	if args.Site.ErrorDocument != "" {
+		websiteArgs.Error404Document = pulumi.String(args.Site.ErrorDocument)
+	}
+
+	website, err := storage.NewStorageAccountStaticWebsite(ctx, name+"-website", websiteArgs, pulumi.Parent(res))
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := common.SiteFiles(filepath.Join(args.ProjectDir, args.Site.Path))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		_, err = storage.NewBlob(ctx, name+"-"+f.Key, &storage.BlobArgs{
+			ResourceGroupName: args.ResourceGroupName,
+			AccountName:       res.Account.Name,
+			ContainerName:     website.ContainerName,
+			BlobName:          pulumi.String(f.Key),
+			Source:            pulumi.NewFileAsset(f.Path),
+			ContentType:       pulumi.String(f.ContentType),
+			Type:              storage.BlobTypeBlock,
+		}, pulumi.Parent(res))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = storage.NewBlob(ctx, name+"-"+common.SiteEnvFile, &storage.BlobArgs{
+		ResourceGroupName: args.ResourceGroupName,
+		AccountName:       res.Account.Name,
+		ContainerName:     website.ContainerName,
+		BlobName:          pulumi.String(common.SiteEnvFile),
+		Source:            args.Env.ApplyT(func(env string) pulumi.Asset { return pulumi.NewStringAsset(env) }).(pulumi.AssetOutput),
+		ContentType:       pulumi.String("application/json"),
+		Type:              storage.BlobTypeBlock,
+	}, pulumi.Parent(res))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Export("site:"+name, res.Account.PrimaryEndpoints.Web())
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":    pulumi.String(res.Name),
+		"account": res.Account,
+	})
+}
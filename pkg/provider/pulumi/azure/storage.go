@@ -22,10 +22,12 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
 	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
 )
 
 type StorageArgs struct {
 	ResourceGroupName pulumi.StringInput
+	Tls               stack.Tls
 }
 
 type Storage struct {
@@ -50,23 +52,29 @@ func (a *azureProvider) newStorageResources(ctx *pulumi.Context, name string, ar
 
 	accName := resourceName(ctx, name, StorageAccountRT)
 	res.Account, err = storage.NewStorageAccount(ctx, accName, &storage.StorageAccountArgs{
+		AccountName:       a.names.Name(ctx, "azure-native:storage:StorageAccount", accName, stableName(ctx, accName)),
 		AccessTier:        storage.AccessTierHot,
 		ResourceGroupName: args.ResourceGroupName,
 		Kind:              pulumi.String("StorageV2"),
 		Sku: storage.SkuArgs{
 			Name: pulumi.String(storage.SkuName_Standard_LRS),
 		},
-		Tags: common.Tags(ctx, accName),
-	}, pulumi.Parent(res))
+		MinimumTlsVersion:      pulumi.String(storageTlsVersion(args.Tls)),
+		EnableHttpsTrafficOnly: pulumi.Bool(!args.Tls.AllowHttp),
+		Tags:                   common.Tags(ctx, accName),
+	}, pulumi.Parent(res), pulumi.Protect(len(a.proj.Buckets) > 0))
 	if err != nil {
 		return nil, errors.WithMessage(err, "account create")
 	}
 
 	for bName := range a.proj.Buckets {
-		res.Containers[bName], err = storage.NewBlobContainer(ctx, resourceName(ctx, bName, StorageContainerRT), &storage.BlobContainerArgs{
+		containerName := resourceName(ctx, bName, StorageContainerRT)
+		res.Containers[bName], err = storage.NewBlobContainer(ctx, containerName, &storage.BlobContainerArgs{
+			// containers are named within the account, so they don't need a suffix
+			ContainerName:     a.names.Name(ctx, "azure-native:storage:BlobContainer", containerName, containerName),
 			ResourceGroupName: args.ResourceGroupName,
 			AccountName:       res.Account.Name,
-		}, pulumi.Parent(res))
+		}, pulumi.Parent(res), pulumi.Protect(true))
 		if err != nil {
 			return nil, errors.WithMessage(err, "container create")
 		}
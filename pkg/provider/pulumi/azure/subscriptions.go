@@ -19,17 +19,37 @@ package azure
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/eventgrid/2018-01-01/eventgrid"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/storage"
 	pulumiEventgrid "github.com/pulumi/pulumi-azure/sdk/v4/go/azure/eventgrid"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
 )
 
 type SubscriptionsArgs struct {
 	ResourceGroupName pulumi.StringInput
 	Apps              map[string]*ContainerApp
+
+	// StorageTopic publishes the events of the Containers, when functions are triggered by them
+	StorageTopic *pulumiEventgrid.SystemTopic
+	Containers   map[string]*storage.BlobContainer
+
+	// The delivery settings of the apps' subscriptions, keyed by app then topic
+	Subscriptions stack.Subscriptions
+}
+
+// blobEventTypes are the event grid event types of each bucket event.
+var blobEventTypes = map[project.BucketEvent]string{
+	project.BucketEventCreated: "Microsoft.Storage.BlobCreated",
+	project.BucketEventDeleted: "Microsoft.Storage.BlobDeleted",
 }
 
 type Subscriptions struct {
@@ -46,7 +66,7 @@ func newSubscriptions(ctx *pulumi.Context, name string, args *SubscriptionsArgs,
 	}
 
 	for _, app := range args.Apps {
-		if len(app.Subscriptions) == 0 {
+		if len(app.Subscriptions) == 0 && len(app.BucketEvents) == 0 {
 			continue
 		}
 
@@ -106,7 +126,7 @@ func newSubscriptions(ctx *pulumi.Context, name string, args *SubscriptionsArgs,
 				WebhookEndpoint: pulumiEventgrid.EventSubscriptionWebhookEndpointArgs{
 					Url: hostUrl,
 					// TODO: Reduce event chattiness here and handle internally in the Azure AppService HTTP Gateway?
-					MaxEventsPerBatch: pulumi.Int(1),
+					MaxEventsPerBatch: pulumi.Int(common.IntValueOrDefault(args.Subscriptions[app.Name][subName].BatchSize, 1)),
 				},
 				RetryPolicy: pulumiEventgrid.EventSubscriptionRetryPolicyArgs{
 					MaxDeliveryAttempts: pulumi.Int(30),
@@ -117,7 +137,56 @@ func newSubscriptions(ctx *pulumi.Context, name string, args *SubscriptionsArgs,
 				return nil, err
 			}
 		}
+
+		for i, t := range app.BucketEvents {
+			container, ok := args.Containers[t.Bucket]
+			if !ok || args.StorageTopic == nil {
+				return nil, fmt.Errorf("bucket %s triggering %s does not exist", t.Bucket, app.Name)
+			}
+
+			eventTypes := pulumi.StringArray{}
+			for _, e := range t.EventTypes() {
+				eventTypes = append(eventTypes, pulumi.String(blobEventTypes[e]))
+			}
+
+			_, err = pulumiEventgrid.NewSystemTopicEventSubscription(ctx, resourceName(ctx, fmt.Sprintf("%s-%s-%d", app.Name, t.Bucket, i), EventSubscriptionRT), &pulumiEventgrid.SystemTopicEventSubscriptionArgs{
+				SystemTopic:        args.StorageTopic.Name,
+				ResourceGroupName:  args.ResourceGroupName,
+				IncludedEventTypes: eventTypes,
+				SubjectFilter: pulumiEventgrid.SystemTopicEventSubscriptionSubjectFilterArgs{
+					SubjectBeginsWith: pulumi.Sprintf("/blobServices/default/containers/%s/blobs/%s", container.Name, t.Prefix),
+				},
+				WebhookEndpoint: pulumiEventgrid.SystemTopicEventSubscriptionWebhookEndpointArgs{
+					Url:               hostUrl,
+					MaxEventsPerBatch: pulumi.Int(1),
+				},
+				RetryPolicy: pulumiEventgrid.SystemTopicEventSubscriptionRetryPolicyArgs{
+					MaxDeliveryAttempts: pulumi.Int(30),
+					EventTimeToLive:     pulumi.Int(5),
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	return res, nil
 }
+
+// maxEventsPerBatch is the most events event grid delivers to a webhook at once
+const maxEventsPerBatch = 5000
+
+// validateSubscriptions checks the delivery settings are supported by event grid, which does not hide
+// delivered events from other replicas.
+func validateSubscriptions(subs stack.Subscriptions) error {
+	for _, s := range common.SortedSubscriptions(subs) {
+		if s.VisibilityTimeout > 0 {
+			return utils.NewNotSupportedErr(fmt.Sprintf("the visibilityTimeout of subscription %s:%s is not supported on azure", s.Function, s.Topic))
+		}
+		if s.BatchSize > maxEventsPerBatch {
+			return fmt.Errorf("the batchSize of subscription %s:%s is more than %d", s.Function, s.Topic, maxEventsPerBatch)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"strings"
+
+	apimanagement "github.com/pulumi/pulumi-azure-native/sdk/go/azure/apimanagement/v20201201"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+// gatewayProtocolProperty is the custom property of an API Management service that enables a TLS version.
+const gatewayProtocolProperty = "Microsoft.WindowsAzure.ApiManagement.Gateway.Security.Protocols."
+
+// storageTlsVersion is the minimum TLS version of a storage account, e.g. TLS1_2
+func storageTlsVersion(t stack.Tls) string {
+	return "TLS" + strings.Replace(t.MinVersion, ".", "_", 1)
+}
+
+// gatewayTlsProperties enable the TLS versions older than 1.2 the gateway accepts.
+func gatewayTlsProperties(t stack.Tls) map[string]string {
+	enabled := func(on bool) string {
+		if on {
+			return "True"
+		}
+		return "False"
+	}
+	return map[string]string{
+		gatewayProtocolProperty + "Tls10": enabled(t.MinVersion == "1.0"),
+		gatewayProtocolProperty + "Tls11": enabled(t.MinVersion == "1.0" || t.MinVersion == "1.1"),
+	}
+}
+
+// apiProtocols are the protocols the api accepts, HTTPS and, when the stack allows it, HTTP.
+func apiProtocols(t stack.Tls) apimanagement.ProtocolArray {
+	if t.AllowHttp {
+		return apimanagement.ProtocolArray{"https", "http"}
+	}
+	return apimanagement.ProtocolArray{"https"}
+}
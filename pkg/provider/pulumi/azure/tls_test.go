@@ -0,0 +1,48 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func Test_storageTlsVersion(t *testing.T) {
+	if got := storageTlsVersion(stack.Tls{MinVersion: "1.2"}); got != "TLS1_2" {
+		t.Errorf("storageTlsVersion() = %v, want TLS1_2", got)
+	}
+}
+
+func Test_gatewayTlsProperties(t *testing.T) {
+	tests := []struct {
+		version string
+		want    map[string]string
+	}{
+		{version: "1.2", want: map[string]string{gatewayProtocolProperty + "Tls10": "False", gatewayProtocolProperty + "Tls11": "False"}},
+		{version: "1.1", want: map[string]string{gatewayProtocolProperty + "Tls10": "False", gatewayProtocolProperty + "Tls11": "True"}},
+		{version: "1.0", want: map[string]string{gatewayProtocolProperty + "Tls10": "True", gatewayProtocolProperty + "Tls11": "True"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := gatewayTlsProperties(stack.Tls{MinVersion: tt.version}); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("gatewayTlsProperties() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/authorization"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/eventgrid"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/resources"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var eventGridTopicID = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.EventGrid/topics/[^/]+$`)
+
+// validateExistingTopics checks each existing topic is a topic of the project with an Event Grid topic resource id.
+func validateExistingTopics(proj *project.Project, existing map[string]string) error {
+	errList := utils.NewErrorList()
+	for name, id := range existing {
+		if _, ok := proj.Topics[name]; !ok {
+			errList.Add(fmt.Errorf("existing topic %s is not a topic of the project", name))
+			continue
+		}
+		if !eventGridTopicID.MatchString(id) {
+			errList.Add(fmt.Errorf("existing topic %s must be an Event Grid topic resource id, e.g. /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.EventGrid/topics/<name>, not %q", name, id))
+		}
+	}
+	return errList.Aggregate()
+}
+
+// newTopic creates the project's topic, or reads the existing topic it is mapped to, which is owned by
+// someone else and so is neither changed nor deleted with the stack.
+func (a *azureProvider) newTopic(ctx *pulumi.Context, rg *resources.ResourceGroup, name string) (*eventgrid.Topic, error) {
+	if id, ok := a.sc.ExistingTopics[name]; ok {
+		return eventgrid.GetTopic(ctx, resourceName(ctx, name, EventGridRT), pulumi.ID(id), nil)
+	}
+	return eventgrid.NewTopic(ctx, resourceName(ctx, name, EventGridRT), &eventgrid.TopicArgs{
+		ResourceGroupName: rg.Name,
+		Location:          rg.Location,
+		Tags:              common.Tags(ctx, name),
+	})
+}
+
+// assignExistingTopicRoles lets the app publish to the existing topics, which are outside the stack's resource
+// group its other roles are assigned on. Only the sender role is assigned, the topics' owners keep control of them.
+func assignExistingTopicRoles(ctx *pulumi.Context, app *ContainerApp, args *ContainerAppArgs) error {
+	names := []string{}
+	for name := range args.ExistingTopics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		_, err := authorization.NewRoleAssignment(ctx, resourceName(ctx, app.Name+name+"Sender", AssignmentRT), &authorization.RoleAssignmentArgs{
+			PrincipalId:      app.Sp.ServicePrincipalId,
+			PrincipalType:    pulumi.StringPtr("ServicePrincipal"),
+			RoleDefinitionId: pulumi.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", args.SubscriptionID, RoleDefinitions["EventGridDataSender"]),
+			Scope:            pulumi.String(args.ExistingTopics[name]),
+		}, pulumi.Parent(app))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/project"
+)
+
+func Test_validateExistingTopics(t *testing.T) {
+	proj := &project.Project{Topics: map[string]project.Topic{"orders": {}}}
+	id := "/subscriptions/0000/resourceGroups/payments/providers/Microsoft.EventGrid/topics/orders"
+
+	tests := []struct {
+		name     string
+		existing map[string]string
+		wantErr  string
+	}{
+		{
+			name:     "valid",
+			existing: map[string]string{"orders": id},
+		},
+		{
+			name:     "unknown topic",
+			existing: map[string]string{"refunds": id},
+			wantErr:  "existing topic refunds is not a topic of the project",
+		},
+		{
+			name:     "not an event grid topic",
+			existing: map[string]string{"orders": "arn:aws:sns:us-east-1:123456789012:orders"},
+			wantErr:  "existing topic orders must be an Event Grid topic resource id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateExistingTopics(proj, tt.existing)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateExistingTopics() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateExistingTopics() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
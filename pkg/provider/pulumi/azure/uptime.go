@@ -0,0 +1,135 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"html"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/insights"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+// uptimeLocations are the availability test locations the apis are requested from.
+var uptimeLocations = []string{"us-va-ash-azr", "us-ca-sjc-azr", "emea-nl-ams-azr", "emea-gb-db3-azr", "apac-sg-sin-azr"}
+
+// uptimeFailedLocations is the number of locations that fail before the alert fires.
+const uptimeFailedLocations = 2
+
+// newUptimeChecks creates an availability test of each checked api in an Application Insights component,
+// with an alert when it fails from several locations.
+func (res *Alerts) newUptimeChecks(ctx *pulumi.Context, args *AlertsArgs, opts ...pulumi.ResourceOption) error {
+	var err error
+	res.Insights, err = insights.NewComponent(ctx, resourceName(ctx, res.Name, AppInsightsRT), &insights.ComponentArgs{
+		ResourceGroupName: args.ResourceGroupName,
+		Location:          args.Location,
+		Kind:              pulumi.String("web"),
+		ApplicationType:   pulumi.String("web"),
+		Tags:              common.Tags(ctx, res.Name),
+	}, opts...)
+	if err != nil {
+		return errors.WithMessage(err, "application insights")
+	}
+
+	// availability tests are linked to their component by a hidden-link tag
+	tags := res.Insights.ID().ToStringOutput().ApplyT(func(id string) map[string]string {
+		return map[string]string{
+			"hidden-link:" + id: "Resource",
+			"x-nitric-project":  ctx.Project(),
+			"x-nitric-stack":    ctx.Stack(),
+		}
+	}).(pulumi.StringMapOutput)
+
+	locations := insights.WebTestGeolocationArray{}
+	for _, l := range uptimeLocations {
+		locations = append(locations, insights.WebTestGeolocationArgs{Location: pulumi.String(l)})
+	}
+
+	uptime := args.Alerts.Uptime
+	for k, api := range args.Apis {
+		if !uptime.Checks(k) {
+			continue
+		}
+
+		testName := ctx.Stack() + "-" + k + "-uptime"
+		config := api.Api.ServiceUrl.Elem().ApplyT(func(url string) string {
+			return pingTestXML(testName, url+uptime.PathOrDefault())
+		}).(pulumi.StringOutput)
+
+		res.WebTests[k], err = insights.NewWebTest(ctx, resourceName(ctx, k, WebTestRT), &insights.WebTestArgs{
+			ResourceGroupName:  args.ResourceGroupName,
+			Location:           args.Location,
+			Kind:               pulumi.String("ping"),
+			WebTestKind:        pulumi.String("ping"),
+			Name:               pulumi.String(testName),
+			SyntheticMonitorId: pulumi.String(testName),
+			Enabled:            pulumi.Bool(true),
+			Frequency:          pulumi.Int(uptime.MinutesOrDefault() * 60),
+			Timeout:            pulumi.Int(30),
+			RetryEnabled:       pulumi.Bool(true),
+			Locations:          locations,
+			Configuration:      insights.WebTestPropertiesConfigurationArgs{WebTest: config},
+			Tags:               tags,
+		}, opts...)
+		if err != nil {
+			return errors.WithMessage(err, "availability test "+k)
+		}
+
+		res.Alerts[k+"-uptime"], err = insights.NewMetricAlert(ctx, resourceName(ctx, k+"-uptime", MetricAlertRT), &insights.MetricAlertArgs{
+			ResourceGroupName:   args.ResourceGroupName,
+			Location:            pulumi.String("global"),
+			Description:         pulumi.String(fmt.Sprintf("api %s is not responding from %d locations", k, uptimeFailedLocations)),
+			Severity:            pulumi.Int(1),
+			Enabled:             pulumi.Bool(true),
+			Scopes:              pulumi.StringArray{res.WebTests[k].ID(), res.Insights.ID()},
+			EvaluationFrequency: pulumi.String("PT1M"),
+			WindowSize:          pulumi.String("PT5M"),
+			Criteria: insights.WebtestLocationAvailabilityCriteriaArgs{
+				OdataType:           pulumi.String("Microsoft.Azure.Monitor.WebtestLocationAvailabilityCriteria"),
+				WebTestId:           res.WebTests[k].ID(),
+				ComponentId:         res.Insights.ID(),
+				FailedLocationCount: pulumi.Float64(uptimeFailedLocations),
+			},
+			Actions: insights.MetricAlertActionArray{
+				insights.MetricAlertActionArgs{ActionGroupId: res.ActionGroup.ID()},
+			},
+			Tags: common.Tags(ctx, k+"-uptime"),
+		}, opts...)
+		if err != nil {
+			return errors.WithMessage(err, "availability alert "+k)
+		}
+	}
+	return nil
+}
+
+// pingTestXML returns the configuration of an availability test requesting the url, which passes when it
+// responds with 200.
+func pingTestXML(name, url string) string {
+	return fmt.Sprintf(`<WebTest Name="%[1]s" Id="%[2]s" Enabled="True" Timeout="30" xmlns="http://microsoft.com/schemas/VisualStudio/TeamTest/2010" PreAuthenticate="True" Proxy="default" StopOnError="False">`+
+		`<Items><Request Method="GET" Guid="%[3]s" Version="1.1" Url="%[4]s" ThinkTime="0" Timeout="30" ParseDependentRequests="False" FollowRedirects="True" RecordResult="True" Cache="False" ResponseTimeGoal="0" Encoding="utf-8" ExpectedHttpStatusCode="200" IgnoreHttpStatusCode="False" /></Items>`+
+		`</WebTest>`, html.EscapeString(name), testGuid(name), testGuid(url), html.EscapeString(url))
+}
+
+// testGuid returns a GUID derived from the value, so the test configuration is the same on each update.
+func testGuid(value string) string {
+	h := sha1.Sum([]byte(value))
+	return fmt.Sprintf("%x-%x-%x-%x-%x", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16])
+}
@@ -0,0 +1,55 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func Test_pingTestXML(t *testing.T) {
+	got := pingTestXML("prod-main-uptime", "https://shop.azure-api.net/health?full=1&v=2")
+
+	if !strings.Contains(got, `Name="prod-main-uptime"`) {
+		t.Errorf("pingTestXML() is missing the name: %s", got)
+	}
+	if !strings.Contains(got, `Url="https://shop.azure-api.net/health?full=1&amp;v=2"`) {
+		t.Errorf("pingTestXML() did not escape the url: %s", got)
+	}
+	if !strings.Contains(got, `ExpectedHttpStatusCode="200"`) {
+		t.Errorf("pingTestXML() is missing the expected status: %s", got)
+	}
+	if again := pingTestXML("prod-main-uptime", "https://shop.azure-api.net/health?full=1&v=2"); again != got {
+		t.Error("pingTestXML() is not the same for the same test")
+	}
+}
+
+func Test_testGuid(t *testing.T) {
+	guid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+	got := testGuid("prod-main-uptime")
+	if !guid.MatchString(got) {
+		t.Errorf("testGuid() = %s, want a GUID", got)
+	}
+	if testGuid("prod-main-uptime") != got {
+		t.Error("testGuid() is not the same for the same value")
+	}
+	if testGuid("prod-admin-uptime") == got {
+		t.Error("testGuid() is the same for different values")
+	}
+}
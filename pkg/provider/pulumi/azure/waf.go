@@ -0,0 +1,150 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/cdn"
+	"github.com/pulumi/pulumi-azure-native/sdk/go/azure/network"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+type frontDoorWafArgs struct {
+	ResourceGroupName pulumi.StringInput
+	Profile           *cdn.Profile
+	Waf               *stack.Waf
+	// Domains are the endpoints and custom domains the policy protects
+	Domains cdn.ActivatedResourceReferenceArray
+}
+
+// validateWaf checks the managed rule sets are named by their type and version.
+func validateWaf(sc *stack.Config) error {
+	if err := common.ValidateWaf(sc); err != nil {
+		return err
+	}
+	if sc.Waf == nil {
+		return nil
+	}
+	for _, r := range sc.Waf.ManagedRules {
+		if _, _, err := managedRuleSet(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// managedRuleSet splits a managed rule set into its type and version, e.g. Microsoft_DefaultRuleSet:2.0
+func managedRuleSet(r string) (string, string, error) {
+	parts := strings.Split(r, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("the waf managed rule set %s should be type:version, e.g. Microsoft_DefaultRuleSet:2.0", r)
+	}
+	return parts[0], parts[1], nil
+}
+
+// frontDoorSku is the tier of the Front Door profile, managed rule sets are only available on premium.
+func frontDoorSku(waf *stack.Waf) string {
+	if waf != nil && len(waf.ManagedRules) > 0 {
+		return "Premium_AzureFrontDoor"
+	}
+	return "Standard_AzureFrontDoor"
+}
+
+// newFrontDoorWaf creates a WAF policy with the managed rule sets and the rate limit, and applies it to
+// the domains through a security policy of the profile.
+func newFrontDoorWaf(ctx *pulumi.Context, name string, args *frontDoorWafArgs, opts ...pulumi.ResourceOption) error {
+	mode := "Prevention"
+	if args.Waf.Monitor {
+		mode = "Detection"
+	}
+
+	ruleSets := network.ManagedRuleSetArray{}
+	for _, r := range args.Waf.ManagedRules {
+		ruleSetType, version, err := managedRuleSet(r)
+		if err != nil {
+			return err
+		}
+		ruleSets = append(ruleSets, network.ManagedRuleSetArgs{
+			RuleSetType:    pulumi.String(ruleSetType),
+			RuleSetVersion: pulumi.String(version),
+		})
+	}
+
+	customRules := network.CustomRuleArray{}
+	if args.Waf.RateLimit > 0 {
+		customRules = append(customRules, network.CustomRuleArgs{
+			Name:                       pulumi.String("RateLimit"),
+			Priority:                   pulumi.Int(1),
+			EnabledState:               pulumi.String("Enabled"),
+			RuleType:                   pulumi.String("RateLimitRule"),
+			RateLimitDurationInMinutes: pulumi.Int(5),
+			RateLimitThreshold:         pulumi.Int(args.Waf.RateLimit),
+			Action:                     pulumi.String("Block"),
+			// every address, each is counted on its own
+			MatchConditions: network.FrontDoorMatchConditionArray{
+				network.FrontDoorMatchConditionArgs{
+					MatchVariable: pulumi.String("RemoteAddr"),
+					Operator:      pulumi.String("IPMatch"),
+					MatchValue:    pulumi.ToStringArray([]string{"0.0.0.0/0", "::/0"}),
+				},
+			},
+		})
+	}
+
+	policy, err := network.NewPolicy(ctx, resourceName(ctx, name, FrontDoorWafPolicyRT), &network.PolicyArgs{
+		ResourceGroupName: args.ResourceGroupName,
+		Location:          pulumi.String("Global"),
+		Sku: network.SkuArgs{
+			Name: pulumi.String(frontDoorSku(args.Waf)),
+		},
+		PolicySettings: network.FrontDoorPolicySettingsArgs{
+			EnabledState: pulumi.String("Enabled"),
+			Mode:         pulumi.String(mode),
+		},
+		ManagedRules: network.ManagedRuleSetListArgs{
+			ManagedRuleSets: ruleSets,
+		},
+		CustomRules: network.CustomRuleListArgs{
+			Rules: customRules,
+		},
+		Tags: common.Tags(ctx, name),
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	_, err = cdn.NewSecurityPolicy(ctx, resourceName(ctx, name, FrontDoorSecurityPolicyRT), &cdn.SecurityPolicyArgs{
+		ResourceGroupName: args.ResourceGroupName,
+		ProfileName:       args.Profile.Name,
+		Parameters: cdn.SecurityPolicyWebApplicationFirewallParametersArgs{
+			Type:      pulumi.String("WebApplicationFirewall"),
+			WafPolicy: &cdn.ResourceReferenceArgs{Id: policy.ID()},
+			Associations: cdn.SecurityPolicyWebApplicationFirewallAssociationArray{
+				cdn.SecurityPolicyWebApplicationFirewallAssociationArgs{
+					Domains:         args.Domains,
+					PatternsToMatch: pulumi.ToStringArray([]string{"/*"}),
+				},
+			},
+		},
+	}, opts...)
+	return err
+}
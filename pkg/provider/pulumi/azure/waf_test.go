@@ -0,0 +1,59 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func Test_validateWaf(t *testing.T) {
+	apis := &stack.Cdn{Apis: map[string]stack.CdnTarget{"main": {}}}
+	tests := []struct {
+		name    string
+		waf     *stack.Waf
+		wantErr bool
+	}{
+		{name: "not configured"},
+		{
+			name: "managed rules and rate limit",
+			waf:  &stack.Waf{ManagedRules: []string{"Microsoft_DefaultRuleSet:2.0", "Microsoft_BotManagerRuleSet:1.0"}, RateLimit: 1000},
+		},
+		{
+			name:    "managed rules without a version",
+			waf:     &stack.Waf{ManagedRules: []string{"Microsoft_DefaultRuleSet"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateWaf(&stack.Config{Cdn: apis, Waf: tt.waf}); (err != nil) != tt.wantErr {
+				t.Errorf("validateWaf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_frontDoorSku(t *testing.T) {
+	if got := frontDoorSku(&stack.Waf{RateLimit: 1000}); got != "Standard_AzureFrontDoor" {
+		t.Errorf("frontDoorSku() = %v, want Standard_AzureFrontDoor", got)
+	}
+	if got := frontDoorSku(&stack.Waf{ManagedRules: []string{"Microsoft_DefaultRuleSet:2.0"}}); got != "Premium_AzureFrontDoor" {
+		t.Errorf("frontDoorSku() = %v, want Premium_AzureFrontDoor", got)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// ValidateAlerts checks each rule watches a function or queue of the project that its metric applies to,
+// and the uptime checks request APIs of the project.
+func ValidateAlerts(alerts *stack.Alerts, proj *project.Project) error {
+	if alerts == nil {
+		return nil
+	}
+
+	errList := utils.NewErrorList()
+	if alerts.Email == "" && (len(alerts.Rules) > 0 || alerts.Uptime != nil) {
+		errList.Add(errors.New("alerts require the email address to notify"))
+	}
+
+	functions := map[string]bool{}
+	for _, c := range proj.Computes() {
+		functions[c.Unit().Name] = true
+	}
+
+	names := []string{}
+	for k := range alerts.Rules {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, k := range names {
+		r := alerts.Rules[k]
+		switch r.Metric {
+		case stack.AlertErrorRate, stack.AlertLatency:
+			if !functions[r.Function] {
+				errList.Add(fmt.Errorf("alert %s watches function %q, but the function does not exist", k, r.Function))
+			}
+		case stack.AlertQueueDepth:
+			if _, ok := proj.Queues[r.Queue]; !ok {
+				errList.Add(fmt.Errorf("alert %s watches queue %q, but the queue does not exist", k, r.Queue))
+			}
+		default:
+			errList.Add(fmt.Errorf("alert %s has unknown metric %q, use %s, %s or %s", k, r.Metric, stack.AlertErrorRate, stack.AlertLatency, stack.AlertQueueDepth))
+		}
+		if r.Threshold <= 0 {
+			errList.Add(fmt.Errorf("alert %s requires a threshold greater than 0", k))
+		}
+	}
+
+	if u := alerts.Uptime; u != nil {
+		for _, api := range u.Apis {
+			if _, ok := proj.ApiDocs[api]; !ok {
+				errList.Add(fmt.Errorf("uptime checks api %q, but the api does not exist", api))
+			}
+		}
+		if !strings.HasPrefix(u.PathOrDefault(), "/") {
+			errList.Add(fmt.Errorf("the uptime path %q must start with /", u.Path))
+		}
+		switch u.MinutesOrDefault() {
+		case 5, 10, 15:
+		default:
+			errList.Add(fmt.Errorf("uptime checks every %d minutes, use 5, 10 or 15", u.Minutes))
+		}
+	}
+
+	return errList.Aggregate()
+}
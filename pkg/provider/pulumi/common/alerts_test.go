@@ -0,0 +1,104 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func TestValidateAlerts(t *testing.T) {
+	p := project.New(&project.Config{Name: "atest", Dir: "."})
+	p.Queues = map[string]project.Queue{"checkout": {}}
+	p.ApiDocs = map[string]*openapi3.T{"main": {}}
+	p.Functions = map[string]project.Function{
+		"orders": {
+			Handler:     "functions/orders.ts",
+			ComputeUnit: project.ComputeUnit{Name: "orders"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		alerts  *stack.Alerts
+		wantErr bool
+	}{
+		{name: "not configured"},
+		{
+			name: "valid",
+			alerts: &stack.Alerts{Email: "ops@example.com", Rules: map[string]stack.AlertRule{
+				"errors":  {Function: "orders", Metric: stack.AlertErrorRate, Threshold: 5},
+				"backlog": {Queue: "checkout", Metric: stack.AlertQueueDepth, Threshold: 100, Minutes: 10},
+			}},
+		},
+		{
+			name:    "missing email",
+			alerts:  &stack.Alerts{Rules: map[string]stack.AlertRule{"errors": {Function: "orders", Metric: stack.AlertErrorRate, Threshold: 5}}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown function",
+			alerts:  &stack.Alerts{Email: "ops@example.com", Rules: map[string]stack.AlertRule{"slow": {Function: "payments", Metric: stack.AlertLatency, Threshold: 500}}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown metric",
+			alerts:  &stack.Alerts{Email: "ops@example.com", Rules: map[string]stack.AlertRule{"cpu": {Function: "orders", Metric: "cpu", Threshold: 80}}},
+			wantErr: true,
+		},
+		{
+			name:   "uptime",
+			alerts: &stack.Alerts{Email: "ops@example.com", Uptime: &stack.Uptime{Apis: []string{"main"}, Path: "/health", Minutes: 10}},
+		},
+		{
+			name:    "uptime without email",
+			alerts:  &stack.Alerts{Uptime: &stack.Uptime{}},
+			wantErr: true,
+		},
+		{
+			name:    "uptime unknown api",
+			alerts:  &stack.Alerts{Email: "ops@example.com", Uptime: &stack.Uptime{Apis: []string{"admin"}}},
+			wantErr: true,
+		},
+		{
+			name:    "uptime minutes",
+			alerts:  &stack.Alerts{Email: "ops@example.com", Uptime: &stack.Uptime{Minutes: 1}},
+			wantErr: true,
+		},
+		{
+			name:    "uptime relative path",
+			alerts:  &stack.Alerts{Email: "ops@example.com", Uptime: &stack.Uptime{Path: "health"}},
+			wantErr: true,
+		},
+		{
+			name:    "zero threshold",
+			alerts:  &stack.Alerts{Email: "ops@example.com", Rules: map[string]stack.AlertRule{"backlog": {Queue: "checkout", Metric: stack.AlertQueueDepth}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateAlerts(tt.alerts, p); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAlerts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
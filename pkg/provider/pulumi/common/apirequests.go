@@ -0,0 +1,67 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// ValidateApiRequests checks the request settings are of the project's apis, the apis are gathered
+// from code so this is checked when deploying.
+func ValidateApiRequests(proj *project.Project, sc *stack.Config) error {
+	for _, api := range names(sc.ApiRequests) {
+		if _, ok := proj.ApiDocs[api]; !ok {
+			return fmt.Errorf("apiRequests configured for api %s, but the api does not exist", api)
+		}
+	}
+	return nil
+}
+
+// ValidateApiRequestLimits checks the timeouts and body sizes are within the provider's maximums,
+// a maximum of 0 has no limit.
+func ValidateApiRequestLimits(provider string, requests map[string]stack.ApiRequests, maxTimeout, maxBodyKB int) error {
+	errList := utils.NewErrorList()
+	for _, api := range names(requests) {
+		r := requests[api]
+		if maxTimeout > 0 && (r.Timeout < 0 || r.Timeout > maxTimeout) {
+			errList.Add(fmt.Errorf("the timeout of api %s must be between 1 and %d seconds on %s", api, maxTimeout, provider))
+		} else if r.Timeout < 0 {
+			errList.Add(fmt.Errorf("the timeout of api %s must be greater than 0", api))
+		}
+		if maxBodyKB > 0 && (r.MaxBodyKB < 0 || r.MaxBodyKB > maxBodyKB) {
+			errList.Add(fmt.Errorf("the maxBodyKb of api %s must be between 1 and %d on %s", api, maxBodyKB, provider))
+		} else if r.MaxBodyKB < 0 {
+			errList.Add(fmt.Errorf("the maxBodyKb of api %s must be greater than 0", api))
+		}
+	}
+	return errList.Aggregate()
+}
+
+// ApiTimeout returns the longest timeout of the apis with routes to the function, 0 when none is set.
+func ApiTimeout(proj *project.Project, sc *stack.Config, function string) int {
+	timeout := 0
+	for _, api := range proj.ApisFor(function) {
+		if t := sc.ApiRequests[api].Timeout; t > timeout {
+			timeout = t
+		}
+	}
+	return timeout
+}
@@ -0,0 +1,87 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func TestValidateApiRequestLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests map[string]stack.ApiRequests
+		wantErr  bool
+	}{
+		{name: "none"},
+		{
+			name:     "within the maximums",
+			requests: map[string]stack.ApiRequests{"orders": {Timeout: 30, MaxBodyKB: 1024}},
+		},
+		{
+			name:     "timeout too long",
+			requests: map[string]stack.ApiRequests{"orders": {Timeout: 31}},
+			wantErr:  true,
+		},
+		{
+			name:     "negative timeout",
+			requests: map[string]stack.ApiRequests{"orders": {Timeout: -1}},
+			wantErr:  true,
+		},
+		{
+			name:     "body too large",
+			requests: map[string]stack.ApiRequests{"orders": {MaxBodyKB: 10241}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateApiRequestLimits("aws", tt.requests, 30, 10240)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateApiRequestLimits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApiTimeout(t *testing.T) {
+	p := project.New(&project.Config{Name: "atest", Dir: "."})
+	routed := func(function string) *openapi3.T {
+		doc := &openapi3.T{Paths: openapi3.Paths{}}
+		op := openapi3.NewOperation()
+		op.Extensions = map[string]interface{}{"x-nitric-target": map[string]string{"name": function, "type": "function"}}
+		doc.AddOperation("/"+function, "GET", op)
+		return doc
+	}
+	p.ApiDocs = map[string]*openapi3.T{"orders": routed("orders"), "payments": routed("orders")}
+	sc := &stack.Config{ApiRequests: map[string]stack.ApiRequests{
+		"orders":   {Timeout: 60},
+		"payments": {Timeout: 600},
+		"refunds":  {Timeout: 900},
+	}}
+
+	if got := ApiTimeout(p, sc, "orders"); got != 600 {
+		t.Errorf("ApiTimeout() = %d, want 600", got)
+	}
+	if got := ApiTimeout(p, sc, "payments"); got != 0 {
+		t.Errorf("ApiTimeout() = %d, want 0 for a function without routes", got)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// ValidateApis checks the settings the stack has for each api are of the project's apis, and that each
+// custom domain is served by one api, bucket or site. Every api is deployed to its own gateway, so apis
+// can't share a domain under different base paths.
+func ValidateApis(proj *project.Project, sc *stack.Config) error {
+	errList := utils.NewErrorList()
+
+	settings := map[string][]string{
+		"jwt":         names(sc.Jwt),
+		"access logs": names(sc.ApiLogs),
+	}
+	if sc.Cdn != nil {
+		settings["cdn"] = names(sc.Cdn.Apis)
+	}
+	for _, setting := range names(settings) {
+		for _, api := range settings[setting] {
+			if _, ok := proj.ApiDocs[api]; !ok {
+				errList.Add(fmt.Errorf("%s configured for api %s, but the api does not exist", setting, api))
+			}
+		}
+	}
+
+	if sc.Cdn == nil {
+		return errList.Aggregate()
+	}
+	servedBy := map[string]string{}
+	targets := map[string]map[string]stack.CdnTarget{
+		"api":    sc.Cdn.Apis,
+		"bucket": sc.Cdn.Buckets,
+		"site":   sc.Cdn.Sites,
+	}
+	for _, kind := range names(targets) {
+		for _, name := range names(targets[kind]) {
+			for _, d := range targets[kind][name].Domains {
+				domain := strings.ToLower(strings.TrimSuffix(d, "."))
+				if other, ok := servedBy[domain]; ok {
+					errList.Add(fmt.Errorf("domain %s is served by both %s and %s %s, each needs its own domain", d, other, kind, name))
+					continue
+				}
+				servedBy[domain] = kind + " " + name
+			}
+		}
+	}
+	return errList.Aggregate()
+}
@@ -0,0 +1,70 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func TestValidateApis(t *testing.T) {
+	proj := &project.Project{ApiDocs: map[string]*openapi3.T{"main": {}, "admin": {}}}
+	tests := []struct {
+		name    string
+		sc      *stack.Config
+		wantErr string
+	}{
+		{
+			name: "apis with their own domains",
+			sc: &stack.Config{
+				Jwt: map[string]stack.JwtAuth{"admin": {Issuer: "https://example.auth0.com/"}},
+				Cdn: &stack.Cdn{Apis: map[string]stack.CdnTarget{
+					"main":  {Domains: []string{"api.example.com"}},
+					"admin": {Domains: []string{"admin.example.com"}},
+				}},
+			},
+		},
+		{
+			name:    "jwt for a missing api",
+			sc:      &stack.Config{Jwt: map[string]stack.JwtAuth{"billing": {}}},
+			wantErr: "jwt configured for api billing, but the api does not exist",
+		},
+		{
+			name: "apis sharing a domain",
+			sc: &stack.Config{Cdn: &stack.Cdn{
+				Apis:  map[string]stack.CdnTarget{"main": {Domains: []string{"api.example.com"}}, "admin": {Domains: []string{"API.example.com."}}},
+				Sites: map[string]stack.CdnTarget{"web": {Domains: []string{"www.example.com"}}},
+			}},
+			wantErr: "domain api.example.com is served by both api admin and api main, each needs its own domain",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateApis(proj, tt.sc)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("ValidateApis() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("ValidateApis() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
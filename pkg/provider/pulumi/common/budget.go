@@ -0,0 +1,53 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// ValidateBudget checks the budget has an amount, thresholds above 0 and someone to notify.
+func ValidateBudget(b *stack.Budget) error {
+	if b == nil {
+		return nil
+	}
+
+	errList := utils.NewErrorList()
+	if b.Amount <= 0 {
+		errList.Add(errors.New("the budget requires an amount greater than 0"))
+	}
+	for _, t := range b.Thresholds {
+		if t <= 0 {
+			errList.Add(fmt.Errorf("the budget threshold %v%% should be greater than 0", t))
+		}
+	}
+	if len(b.Emails) == 0 && b.Webhook == "" {
+		errList.Add(errors.New("the budget requires emails or a webhook to notify"))
+	}
+	if b.Webhook != "" {
+		if u, err := url.Parse(b.Webhook); err != nil || u.Scheme != "https" || u.Host == "" {
+			errList.Add(fmt.Errorf("the budget webhook %q should be an https URL", b.Webhook))
+		}
+	}
+
+	return errList.Aggregate()
+}
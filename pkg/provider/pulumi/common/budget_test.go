@@ -0,0 +1,46 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func TestValidateBudget(t *testing.T) {
+	tests := []struct {
+		name    string
+		budget  *stack.Budget
+		wantErr bool
+	}{
+		{name: "not configured"},
+		{name: "email", budget: &stack.Budget{Amount: 100, Emails: []string{"finance@example.com"}}},
+		{name: "webhook", budget: &stack.Budget{Amount: 100, Thresholds: []float64{50, 120}, Webhook: "https://example.com/budget"}},
+		{name: "no amount", budget: &stack.Budget{Emails: []string{"finance@example.com"}}, wantErr: true},
+		{name: "no one notified", budget: &stack.Budget{Amount: 100}, wantErr: true},
+		{name: "zero threshold", budget: &stack.Budget{Amount: 100, Thresholds: []float64{0}, Emails: []string{"finance@example.com"}}, wantErr: true},
+		{name: "http webhook", budget: &stack.Budget{Amount: 100, Webhook: "http://example.com/budget"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateBudget(tt.budget); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBudget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
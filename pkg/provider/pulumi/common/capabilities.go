@@ -0,0 +1,345 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// Capability is a feature of a project or stack that a provider may not support.
+type Capability string
+
+const (
+	CapabilityApis           Capability = "apis"
+	CapabilitySchedules      Capability = "schedules"
+	CapabilityTopics         Capability = "topics"
+	CapabilityQueues         Capability = "queues"
+	CapabilityBuckets        Capability = "buckets"
+	CapabilityBucketEvents   Capability = "bucket events"
+	CapabilityCollections    Capability = "collections"
+	CapabilitySecrets        Capability = "secrets"
+	CapabilitySites          Capability = "sites"
+	CapabilityWorkflows      Capability = "workflows"
+	CapabilityDatabases      Capability = "databases"
+	CapabilityCaches         Capability = "caches"
+	CapabilityContainers     Capability = "containers"
+	CapabilityContainerPorts Capability = "container ports"
+	CapabilityServices       Capability = "services"
+	CapabilityVolumes        Capability = "volumes"
+	CapabilityJobs           Capability = "jobs"
+	CapabilityCdn            Capability = "cdn"
+	CapabilityWaf            Capability = "waf"
+	CapabilityTls            Capability = "tls settings"
+	CapabilityDapr           Capability = "dapr"
+	CapabilityBackups        Capability = "backups"
+	CapabilityKeepWarm       Capability = "keepWarm"
+	CapabilityCosmos         Capability = "cosmos"
+	CapabilityLayers         Capability = "layers"
+	CapabilityObservability  Capability = "observability"
+	CapabilityAlerts         Capability = "alerts"
+	CapabilityBudget         Capability = "budget"
+	CapabilitySleep          Capability = "sleep"
+	CapabilityEventBridge    Capability = "eventbridge"
+	CapabilityRateLimits     Capability = "route rate limits"
+	CapabilityQuotas         Capability = "route quotas"
+	CapabilitySchemeLimits   Capability = "security scheme limits"
+	CapabilityJwt            Capability = "jwt auth"
+	CapabilityApiBodyLimits  Capability = "api body limits"
+	CapabilityExistingTopics Capability = "existing topics"
+	CapabilityHttp2          Capability = "grpc and h2c"
+	CapabilityApiMocks       Capability = "api mocks"
+)
+
+// AllCapabilities is every capability, in the order they are reported.
+var AllCapabilities = []Capability{
+	CapabilityApis,
+	CapabilitySchedules,
+	CapabilityTopics,
+	CapabilityQueues,
+	CapabilityBuckets,
+	CapabilityBucketEvents,
+	CapabilityCollections,
+	CapabilitySecrets,
+	CapabilitySites,
+	CapabilityWorkflows,
+	CapabilityDatabases,
+	CapabilityCaches,
+	CapabilityContainers,
+	CapabilityContainerPorts,
+	CapabilityServices,
+	CapabilityVolumes,
+	CapabilityJobs,
+	CapabilityCdn,
+	CapabilityWaf,
+	CapabilityTls,
+	CapabilityDapr,
+	CapabilityBackups,
+	CapabilityKeepWarm,
+	CapabilityCosmos,
+	CapabilityLayers,
+	CapabilityObservability,
+	CapabilityAlerts,
+	CapabilityBudget,
+	CapabilitySleep,
+	CapabilityEventBridge,
+	CapabilityRateLimits,
+	CapabilityQuotas,
+	CapabilitySchemeLimits,
+	CapabilityJwt,
+	CapabilityApiBodyLimits,
+	CapabilityExistingTopics,
+	CapabilityHttp2,
+	CapabilityApiMocks,
+}
+
+// OptionalCapabilities are skipped with a warning, rather than failing the deployment, when a provider
+// doesn't support them.
+var OptionalCapabilities = map[Capability]bool{
+	CapabilityRateLimits:   true,
+	CapabilityQuotas:       true,
+	CapabilitySchemeLimits: true,
+	// the gateways of the other providers still limit the body to their own maximum
+	CapabilityApiBodyLimits: true,
+}
+
+// Capabilities are the features a provider supports.
+type Capabilities map[Capability]bool
+
+// CapabilitiesExcept returns all capabilities apart from those given.
+func CapabilitiesExcept(unsupported ...Capability) Capabilities {
+	c := Capabilities{}
+	for _, k := range AllCapabilities {
+		c[k] = true
+	}
+	for _, k := range unsupported {
+		delete(c, k)
+	}
+	return c
+}
+
+// names returns the sorted keys of a map keyed by name.
+func names(m interface{}) []string {
+	names := []string{}
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		names = append(names, k.String())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UsedCapabilities returns the capabilities used by the project and stack, along with the names
+// of what uses them.
+func UsedCapabilities(proj *project.Project, sc *stack.Config) map[Capability][]string {
+	used := map[Capability][]string{}
+	add := func(c Capability, names []string) {
+		if len(names) > 0 {
+			used[c] = names
+		}
+	}
+
+	add(CapabilityApis, names(proj.ApiDocs))
+	if proj.Mock {
+		add(CapabilityApiMocks, names(proj.ApiDocs))
+	}
+	add(CapabilitySchedules, names(proj.Schedules))
+	add(CapabilityTopics, names(proj.Topics))
+	add(CapabilityQueues, names(proj.Queues))
+	add(CapabilityBuckets, names(proj.Buckets))
+	add(CapabilityCollections, names(proj.Collections))
+	add(CapabilitySecrets, names(proj.Secrets))
+	add(CapabilitySites, names(proj.Sites))
+	add(CapabilityWorkflows, names(proj.Workflows))
+	add(CapabilityDatabases, names(proj.Databases))
+	add(CapabilityCaches, names(proj.Caches))
+	add(CapabilityContainers, names(proj.Containers))
+
+	// services listen on their port on every provider
+	ports := []string{}
+	for _, c := range proj.Computes() {
+		if c.Unit().Port != 0 && !c.Unit().AlwaysOn {
+			ports = append(ports, c.Unit().Name)
+		}
+	}
+	sort.Strings(ports)
+	add(CapabilityContainerPorts, ports)
+
+	http2 := []string{}
+	for _, c := range proj.Computes() {
+		if c.Unit().Http2() {
+			http2 = append(http2, c.Unit().Name)
+		}
+	}
+	sort.Strings(http2)
+	add(CapabilityHttp2, http2)
+
+	services := []string{}
+	for _, c := range proj.Computes() {
+		if c.Unit().AlwaysOn {
+			services = append(services, c.Unit().Name)
+		}
+	}
+	sort.Strings(services)
+	add(CapabilityServices, services)
+
+	volumes := []string{}
+	for _, c := range proj.Containers {
+		if len(c.Volumes) > 0 {
+			volumes = append(volumes, c.Name)
+		}
+	}
+	sort.Strings(volumes)
+	add(CapabilityVolumes, volumes)
+
+	jobs := []string{}
+	for _, c := range proj.Containers {
+		if c.Job != nil {
+			jobs = append(jobs, c.Name)
+		}
+	}
+	sort.Strings(jobs)
+	add(CapabilityJobs, jobs)
+
+	bucketEvents := []string{}
+	for _, c := range proj.Computes() {
+		if len(c.Unit().Triggers.Buckets) > 0 {
+			bucketEvents = append(bucketEvents, c.Unit().Name)
+		}
+	}
+	sort.Strings(bucketEvents)
+	add(CapabilityBucketEvents, bucketEvents)
+
+	// stack settings are reported by their name in the stack file
+	if sc.Cdn != nil {
+		used[CapabilityCdn] = []string{}
+	}
+	if sc.Waf != nil {
+		used[CapabilityWaf] = []string{}
+	}
+	if sc.Tls != nil {
+		used[CapabilityTls] = []string{}
+	}
+	if sc.Dapr != nil {
+		used[CapabilityDapr] = names(sc.Dapr.Functions)
+	}
+	if sc.Backups != nil {
+		used[CapabilityBackups] = []string{}
+	}
+	add(CapabilityKeepWarm, names(sc.KeepWarm))
+	if sc.Cosmos != nil {
+		used[CapabilityCosmos] = []string{}
+	}
+	add(CapabilityLayers, names(sc.Layers))
+	if sc.Observability != nil {
+		used[CapabilityObservability] = []string{}
+	}
+	if sc.Alerts != nil {
+		add(CapabilityAlerts, names(sc.Alerts.Rules))
+	}
+	if sc.Budget != nil {
+		used[CapabilityBudget] = []string{}
+	}
+	if sc.Sleep != nil {
+		used[CapabilitySleep] = []string{}
+	}
+	if sc.Eventing == stack.EventingEventBridge || sc.EventBridge != nil {
+		used[CapabilityEventBridge] = []string{}
+	}
+	add(CapabilityJwt, names(sc.Jwt))
+	add(CapabilityExistingTopics, names(sc.ExistingTopics))
+
+	bodyLimits := []string{}
+	for _, api := range names(sc.ApiRequests) {
+		if sc.ApiRequests[api].MaxBodyKB > 0 {
+			bodyLimits = append(bodyLimits, api)
+		}
+	}
+	add(CapabilityApiBodyLimits, bodyLimits)
+
+	// limits that don't parse are reported by ValidateThrottling
+	rateLimited, quotas, schemeLimits := []string{}, []string{}, []string{}
+	for _, api := range names(proj.ApiDocs) {
+		l, err := ApiRateLimits(proj.ApiDocs[api], StackThrottling(sc, api))
+		if err != nil {
+			continue
+		}
+		if l.RateLimited() {
+			rateLimited = append(rateLimited, api)
+		}
+		if l.Quotas() {
+			quotas = append(quotas, api)
+		}
+		if len(l.SecuritySchemes) > 0 {
+			schemeLimits = append(schemeLimits, api)
+		}
+	}
+	add(CapabilityRateLimits, rateLimited)
+	add(CapabilityQuotas, quotas)
+	add(CapabilitySchemeLimits, schemeLimits)
+
+	return used
+}
+
+// unsupported returns the capabilities used by the project or stack that the provider doesn't support,
+// along with what uses them, when they are optional or not.
+func unsupported(supported Capabilities, proj *project.Project, sc *stack.Config, optional bool) []string {
+	used := UsedCapabilities(proj, sc)
+
+	lines := []string{}
+	for _, c := range AllCapabilities {
+		users, ok := used[c]
+		if !ok || supported[c] || OptionalCapabilities[c] != optional {
+			continue
+		}
+		line := string(c)
+		if len(users) > 0 {
+			line += " (" + strings.Join(users, ", ") + ")"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// SkippedCapabilities returns a warning for each optional capability used by the project or stack that
+// the provider doesn't support, so it isn't deployed.
+func SkippedCapabilities(provider string, supported Capabilities, proj *project.Project, sc *stack.Config) []string {
+	warnings := []string{}
+	for _, line := range unsupported(supported, proj, sc, true) {
+		warnings = append(warnings, fmt.Sprintf("%s are not supported on %s and will not be deployed", line, provider))
+	}
+	return warnings
+}
+
+// CheckCapabilities returns a single error listing every capability used by the project or stack
+// that the provider doesn't support.
+func CheckCapabilities(provider string, supported Capabilities, proj *project.Project, sc *stack.Config) error {
+	lines := unsupported(supported, proj, sc, false)
+	if len(lines) == 0 {
+		return nil
+	}
+	for i := range lines {
+		lines[i] = "  " + lines[i]
+	}
+
+	return utils.NewNotSupportedErr(fmt.Sprintf("these features are not supported on %s:\n%s", provider, strings.Join(lines, "\n")))
+}
@@ -0,0 +1,50 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+func TestCheckCapabilities(t *testing.T) {
+	p := project.New(&project.Config{Name: "atest", Dir: "."})
+	p.Schedules = map[string]project.Schedule{"nightly": {}, "hourly": {}}
+	p.Workflows = map[string]project.Workflow{"checkout": {}}
+	p.Buckets = map[string]project.Bucket{"images": {}}
+	sc := &stack.Config{Name: "prod", Provider: stack.Azure, Cosmos: &stack.CosmosNetwork{}}
+
+	if err := CheckCapabilities("azure", CapabilitiesExcept(), p, sc); err != nil {
+		t.Errorf("expected all capabilities to be supported, got %v", err)
+	}
+
+	err := CheckCapabilities("azure", CapabilitiesExcept(CapabilitySchedules, CapabilityWorkflows, CapabilityCosmos, CapabilityCaches), p, sc)
+	if err == nil {
+		t.Fatal("expected an error for unsupported capabilities")
+	}
+	if _, ok := err.(*utils.NotSupportedError); !ok {
+		t.Errorf("expected a not supported error, got %T", err)
+	}
+
+	want := "these features are not supported on azure:\n  schedules (hourly, nightly)\n  workflows (checkout)\n  cosmos"
+	if err.Error() != want {
+		t.Errorf("CheckCapabilities() = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import "github.com/nitrictech/cli/pkg/provider/types"
+
+// DomainValidator is implemented by providers whose custom domains are validated after the stack is deployed,
+// e.g. Front Door managed certificates. outputs are the deployed stack's outputs.
+type DomainValidator interface {
+	Domains(outputs map[string]string) ([]types.DomainStatus, error)
+}
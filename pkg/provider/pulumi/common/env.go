@@ -0,0 +1,98 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// reservedEnv are used by the nitric membrane on every provider.
+var reservedEnv = []string{"NITRIC_", "MIN_WORKERS"}
+
+// MergeEnv overlays the user's env files on top of the provider injected values,
+// matching the order in which the providers apply them.
+func MergeEnv(provided []types.EnvVar, envMap map[string]string) []types.EnvVar {
+	merged := map[string]types.EnvVar{}
+	for _, e := range provided {
+		merged[e.Name] = e
+	}
+	for k, v := range envMap {
+		merged[k] = types.EnvVar{Name: k, Value: v, Source: types.EnvSourceEnvFile}
+	}
+
+	env := []types.EnvVar{}
+	for _, e := range merged {
+		env = append(env, e)
+	}
+	sort.Slice(env, func(i, j int) bool {
+		return env[i].Name < env[j].Name
+	})
+	return env
+}
+
+// DeploymentEnv names the stack and the compute unit in every instance, so their logs and traces can be
+// correlated with the deployment.
+func DeploymentEnv(stackName string, c project.Compute) []types.EnvVar {
+	return []types.EnvVar{
+		{Name: "NITRIC_STACK", Value: stackName, Source: types.EnvSourceProvider},
+		{Name: "NITRIC_FUNCTION", Value: c.Unit().Name, Source: types.EnvSourceProvider},
+	}
+}
+
+// UnitEnv returns the env vars set on the compute unit in nitric.yaml, the user's env files take precedence.
+func UnitEnv(c project.Compute) []types.EnvVar {
+	env := []types.EnvVar{}
+	for k, v := range c.Unit().Env {
+		env = append(env, types.EnvVar{Name: k, Value: v, Source: types.EnvSourceProject})
+	}
+	sort.Slice(env, func(i, j int) bool {
+		return env[i].Name < env[j].Name
+	})
+	return env
+}
+
+// ValidateEnv returns an error for each of the user's env vars that would replace a value the
+// provider injects or the platform reserves. Reserved names ending in _ reserve the whole prefix.
+func ValidateEnv(provider string, reserved []string, envMap map[string]string) error {
+	errList := utils.NewErrorList()
+
+	names := []string{}
+	for k := range envMap {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, k := range names {
+		for _, r := range append(reservedEnv, reserved...) {
+			if k == r {
+				errList.Add(fmt.Errorf("env var %s is set by %s and can't be set in env files", k, provider))
+				break
+			}
+			if strings.HasSuffix(r, "_") && strings.HasPrefix(k, r) {
+				errList.Add(fmt.Errorf("env var %s is reserved on %s, names starting with %s can't be set in env files", k, provider, r))
+				break
+			}
+		}
+	}
+
+	return errList.Aggregate()
+}
@@ -0,0 +1,80 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package common
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+func TestMergeEnv(t *testing.T) {
+	got := MergeEnv([]types.EnvVar{
+		{Name: "MIN_WORKERS", Value: "1", Source: types.EnvSourceProvider},
+		{Name: "NITRIC_STACK", Value: "proj-aws", Source: types.EnvSourceProvider},
+	}, map[string]string{
+		"MIN_WORKERS": "4",
+		"API_KEY":     "xyz",
+	})
+
+	want := []types.EnvVar{
+		{Name: "API_KEY", Value: "xyz", Source: types.EnvSourceEnvFile},
+		{Name: "MIN_WORKERS", Value: "4", Source: types.EnvSourceEnvFile},
+		{Name: "NITRIC_STACK", Value: "proj-aws", Source: types.EnvSourceProvider},
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestValidateEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envMap  map[string]string
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			envMap: map[string]string{"API_KEY": "xyz", "BUCKET_SUFFIX": "x"},
+		},
+		{
+			name:    "injected",
+			envMap:  map[string]string{"MIN_WORKERS": "4"},
+			wantErr: "env var MIN_WORKERS is set by aws and can't be set in env files",
+		},
+		{
+			name:    "prefix",
+			envMap:  map[string]string{"NITRIC_STACK": "x", "AWS_REGION": "us-east-1"},
+			wantErr: "env var AWS_REGION is reserved on aws, names starting with AWS_ can't be set in env files\nenv var NITRIC_STACK is reserved on aws, names starting with NITRIC_ can't be set in env files",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEnv("aws", []string{"AWS_", "TZ"}, tt.envMap)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
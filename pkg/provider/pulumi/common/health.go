@@ -0,0 +1,44 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"sort"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+// HealthChecker is implemented by providers that can check the deployed functions are serving requests,
+// ids maps the names of the functions to the ids they were deployed with.
+type HealthChecker interface {
+	Health(ids map[string]string) ([]types.FunctionHealth, error)
+}
+
+// CheckHealth checks each deployed function in name order.
+func CheckHealth(ids map[string]string, check func(name, id string) types.FunctionHealth) []types.FunctionHealth {
+	names := []string{}
+	for name := range ids {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hs := []types.FunctionHealth{}
+	for _, name := range names {
+		hs = append(hs, check(name, ids[name]))
+	}
+	return hs
+}
@@ -23,6 +23,19 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 )
 
+// CredentialRefresher is implemented by providers that fetch the registry credentials once, before the deployment,
+// they are refreshed when pushing an image fails because they expired.
+type CredentialRefresher interface {
+	RefreshCredentials() error
+}
+
+// ImageReuser is implemented by providers that can deploy the functions with the images pushed by the last
+// update, rather than pushing them again, so updates that only change their configuration are fast.
+type ImageReuser interface {
+	// ReuseImages sets the pushed images, keyed by the name of their source image
+	ReuseImages(images map[string]string)
+}
+
 type ImageArgs struct {
 	LocalImageName  string
 	SourceImageName string
@@ -31,6 +44,10 @@ type ImageArgs struct {
 	Server          pulumi.StringInput
 	Username        pulumi.StringInput
 	Password        pulumi.StringInput
+	// Instructions are added to the Dockerfile after FROM, with TempDir as the build context
+	Instructions []string
+	// Deployed is the image pushed by the last update, when it is set the image is used as is
+	Deployed string
 }
 
 type Image struct {
@@ -47,13 +64,41 @@ func NewImage(ctx *pulumi.Context, name string, args *ImageArgs, opts ...pulumi.
 		return nil, err
 	}
 
+	if args.Deployed != "" {
+		res.DockerImage = &docker.Image{
+			ImageName:      pulumi.String(args.Deployed).ToStringOutput(),
+			BaseImageName:  pulumi.String(args.Deployed).ToStringOutput(),
+			RegistryServer: args.Server.ToStringOutput(),
+		}
+	} else if err := buildImage(ctx, res, args); err != nil {
+		return nil, err
+	}
+
+	// exported for signing the pushed image, see build.Sign
+	ctx.Export("image:"+args.SourceImageName, res.DockerImage.ImageName)
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":          pulumi.String(res.Name),
+		"imageUri":      res.DockerImage.ImageName,
+		"baseImageName": res.DockerImage.BaseImageName,
+	})
+}
+
+// buildImage builds the image from its source image and pushes it to the repository.
+func buildImage(ctx *pulumi.Context, res *Image, args *ImageArgs) error {
 	dummyDockerFilePath, err := ioutil.TempFile(args.TempDir, "*.dockerfile")
 	if err != nil {
-		return nil, err
+		return err
 	}
 	_, err = dummyDockerFilePath.WriteString("FROM " + args.SourceImageName + "\n")
 	if err != nil {
-		return nil, err
+		return err
+	}
+	for _, i := range args.Instructions {
+		_, err = dummyDockerFilePath.WriteString(i + "\n")
+		if err != nil {
+			return err
+		}
 	}
 
 	imageArgs := &docker.ImageArgs{
@@ -70,14 +115,9 @@ func NewImage(ctx *pulumi.Context, name string, args *ImageArgs, opts ...pulumi.
 			Password: args.Password,
 		},
 	}
-	res.DockerImage, err = docker.NewImage(ctx, name+"-image", imageArgs, pulumi.Parent(res))
-	if err != nil {
-		return nil, err
+	if len(args.Instructions) > 0 {
+		imageArgs.Build.Context = pulumi.String(args.TempDir)
 	}
-
-	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
-		"name":          pulumi.String(res.Name),
-		"imageUri":      res.DockerImage.ImageName,
-		"baseImageName": res.DockerImage.BaseImageName,
-	})
+	res.DockerImage, err = docker.NewImage(ctx, res.Name+"-image", imageArgs, pulumi.Parent(res))
+	return err
 }
@@ -0,0 +1,58 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// ValidateKeepWarm checks each keepWarm setting pings a compute unit of the project every 1 to maxMinutes
+// minutes, a maxMinutes of 0 has no upper limit.
+func ValidateKeepWarm(keepWarm map[string]int, proj *project.Project, maxMinutes int) error {
+	if len(keepWarm) == 0 {
+		return nil
+	}
+
+	computes := map[string]bool{}
+	for _, c := range proj.Computes() {
+		computes[c.Unit().Name] = true
+	}
+
+	names := []string{}
+	for k := range keepWarm {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	errList := utils.NewErrorList()
+	for _, k := range names {
+		minutes := keepWarm[k]
+		if !computes[k] {
+			errList.Add(fmt.Errorf("keepWarm configured for function %s, but the function does not exist", k))
+		}
+		if maxMinutes > 0 && (minutes < 1 || minutes > maxMinutes) {
+			errList.Add(fmt.Errorf("keepWarm for function %s must be between 1 and %d minutes", k, maxMinutes))
+		} else if minutes < 1 {
+			errList.Add(fmt.Errorf("keepWarm for function %s must be at least 1 minute", k))
+		}
+	}
+	return errList.Aggregate()
+}
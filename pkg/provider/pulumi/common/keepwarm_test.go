@@ -0,0 +1,56 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/project"
+)
+
+func TestValidateKeepWarm(t *testing.T) {
+	p := project.New(&project.Config{Name: "atest", Dir: "."})
+	p.Functions = map[string]project.Function{
+		"orders": {
+			Handler:     "functions/orders.ts",
+			ComputeUnit: project.ComputeUnit{Name: "orders"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		keepWarm   map[string]int
+		maxMinutes int
+		wantErr    bool
+	}{
+		{name: "not configured"},
+		{name: "valid", keepWarm: map[string]int{"orders": 5}},
+		{name: "no upper limit", keepWarm: map[string]int{"orders": 120}},
+		{name: "within the limit", keepWarm: map[string]int{"orders": 59}, maxMinutes: 59},
+		{name: "over the limit", keepWarm: map[string]int{"orders": 60}, maxMinutes: 59, wantErr: true},
+		{name: "zero minutes", keepWarm: map[string]int{"orders": 0}, wantErr: true},
+		{name: "negative minutes with a limit", keepWarm: map[string]int{"orders": -1}, maxMinutes: 59, wantErr: true},
+		{name: "unknown function", keepWarm: map[string]int{"payments": 5}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateKeepWarm(tt.keepWarm, p, tt.maxMinutes); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateKeepWarm() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nitrictech/nitric/pkg/plugins/events"
+)
+
+// Messenger is implemented by providers that can publish to the topics and send to the queues of a deployed stack.
+type Messenger interface {
+	Publish(stackName, topic string, payload map[string]interface{}) (string, error)
+	Send(stackName, queue string, payload map[string]interface{}) (string, error)
+}
+
+// MessageBody wraps the payload in the event the membrane unwraps from topics and queues.
+func MessageBody(payload map[string]interface{}) ([]byte, error) {
+	return json.Marshal(&events.NitricEvent{
+		ID:          fmt.Sprintf("cli-%d", time.Now().UnixNano()),
+		PayloadType: "cli",
+		Payload:     payload,
+	})
+}
@@ -0,0 +1,69 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// stableSuffixLength is the length of the suffix of generated names, the same as pulumi's random suffix.
+const stableSuffixLength = 7
+
+// StableNamer is implemented by providers that can give stateful resources the names they were first deployed with.
+type StableNamer interface {
+	SetStableNames(names *StableNames)
+}
+
+// StableNames are the physical names of the deployed resources, keyed by StableNameKey. The names are
+// exported with the stack, so each deployment names the resources the same way as the one before.
+type StableNames struct {
+	names map[string]string
+}
+
+func NewStableNames(names map[string]string) *StableNames {
+	return &StableNames{names: names}
+}
+
+// StableNameKey identifies a resource by its pulumi type and logical name.
+func StableNameKey(typ, logical string) string {
+	return typ + "::" + logical
+}
+
+// Name returns the physical name of the resource, the name it was deployed with or generated when it is new.
+// When stable naming is off it is nil, so pulumi auto-names the resource.
+func (n *StableNames) Name(ctx *pulumi.Context, typ, logical, generated string) pulumi.StringPtrInput {
+	if n == nil {
+		return nil
+	}
+
+	key := StableNameKey(typ, logical)
+	name, ok := n.names[key]
+	if !ok {
+		name = generated
+	}
+	ctx.Export("name:"+key, pulumi.String(name))
+	return pulumi.StringPtr(name)
+}
+
+// StableSuffix is derived from the stack and the logical name, so it is the same each time the resource is named.
+func StableSuffix(ctx *pulumi.Context, logical string) string {
+	h := sha256.Sum256([]byte(ctx.Project() + "/" + ctx.Stack() + "/" + logical))
+	return hex.EncodeToString(h[:])[:stableSuffixLength]
+}
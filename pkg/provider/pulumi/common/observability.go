@@ -0,0 +1,107 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+// CollectorImage is the OpenTelemetry collector run as a sidecar on container platforms.
+const CollectorImage = "otel/opentelemetry-collector-contrib:0.62.1"
+
+// CollectorConfigEnv holds the collector configuration of the sidecar, it is read with --config=env:
+const CollectorConfigEnv = "OTEL_COLLECTOR_CONFIG"
+
+// ValidateObservability checks the exporter settings.
+func ValidateObservability(o *stack.Observability) error {
+	if o == nil {
+		return nil
+	}
+	if o.Endpoint == "" {
+		return errors.New("observability requires the endpoint of the OTLP exporter")
+	}
+	switch o.Protocol {
+	case "", "grpc", "http/protobuf":
+	default:
+		return fmt.Errorf("observability protocol %s is not supported, use grpc or http/protobuf", o.Protocol)
+	}
+	return nil
+}
+
+// ObservabilityEnv points the function's OpenTelemetry SDK at the collector running next to it.
+func ObservabilityEnv(o *stack.Observability, function, stackName string) []types.EnvVar {
+	if o == nil {
+		return nil
+	}
+	return []types.EnvVar{
+		{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://localhost:4318", Source: types.EnvSourceProvider},
+		{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf", Source: types.EnvSourceProvider},
+		{Name: "OTEL_SERVICE_NAME", Value: function, Source: types.EnvSourceProvider},
+		{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: "deployment.environment=" + stackName, Source: types.EnvSourceProvider},
+	}
+}
+
+// CollectorConfig is the collector configuration receiving OTLP from the function on localhost and
+// exporting it to the configured endpoint. Header values are expanded from envMap.
+func CollectorConfig(o *stack.Observability, envMap map[string]string) (string, error) {
+	exporter := "otlphttp"
+	if o.Protocol == "grpc" {
+		exporter = "otlp"
+	}
+
+	headers := map[string]string{}
+	for k, v := range o.Headers {
+		headers[k] = os.Expand(v, func(name string) string { return envMap[name] })
+	}
+
+	pipeline := map[string][]string{
+		"receivers": {"otlp"},
+		"exporters": {exporter},
+	}
+	config := map[string]interface{}{
+		"receivers": map[string]interface{}{
+			"otlp": map[string]interface{}{
+				"protocols": map[string]interface{}{
+					"grpc": map[string]string{"endpoint": "localhost:4317"},
+					"http": map[string]string{"endpoint": "localhost:4318"},
+				},
+			},
+		},
+		"exporters": map[string]interface{}{
+			exporter: map[string]interface{}{
+				"endpoint": o.Endpoint,
+				"headers":  headers,
+			},
+		},
+		"service": map[string]interface{}{
+			"pipelines": map[string]interface{}{
+				"traces":  pipeline,
+				"metrics": pipeline,
+			},
+		},
+	}
+
+	b, err := yaml.Marshal(config)
+	return string(b), err
+}
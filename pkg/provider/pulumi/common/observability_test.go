@@ -0,0 +1,62 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func TestCollectorConfig(t *testing.T) {
+	o := &stack.Observability{
+		Endpoint: "https://api.honeycomb.io",
+		Headers:  map[string]string{"x-honeycomb-team": "${HONEYCOMB_KEY}"},
+	}
+
+	got, err := CollectorConfig(o, map[string]string{"HONEYCOMB_KEY": "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"otlphttp:", "endpoint: https://api.honeycomb.io", "x-honeycomb-team: secret", "endpoint: localhost:4318"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("CollectorConfig() = %s, missing %s", got, want)
+		}
+	}
+}
+
+func TestValidateObservability(t *testing.T) {
+	tests := []struct {
+		name    string
+		o       *stack.Observability
+		wantErr bool
+	}{
+		{name: "not configured"},
+		{name: "valid", o: &stack.Observability{Endpoint: "https://otlp.example.com", Protocol: "grpc"}},
+		{name: "missing endpoint", o: &stack.Observability{}, wantErr: true},
+		{name: "unknown protocol", o: &stack.Observability{Endpoint: "https://otlp.example.com", Protocol: "zipkin"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateObservability(tt.o); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateObservability() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"strings"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+// Collector is implemented by providers that can find the resources tagged with a stack, so the resources
+// missing from the stack's state can be deleted. stackName is the pulumi stack, the value of x-nitric-stack.
+type Collector interface {
+	TaggedResources(stackName string) ([]types.Orphan, error)
+	DeleteOrphan(r types.Orphan) error
+}
+
+// normalId compares ids case insensitively, as Azure resource ids are, and without the :* log group ARNs can end with.
+func normalId(id string) string {
+	return strings.TrimSuffix(strings.ToLower(id), ":*")
+}
+
+// Orphans returns the tagged resources whose ids are not among the ids of the resources in the state.
+func Orphans(tagged []types.Orphan, stateIds []string) []types.Orphan {
+	inState := map[string]bool{}
+	for _, id := range stateIds {
+		inState[normalId(id)] = true
+	}
+
+	orphans := []types.Orphan{}
+	for _, r := range tagged {
+		if !inState[normalId(r.ID)] {
+			orphans = append(orphans, r)
+		}
+	}
+	return orphans
+}
@@ -0,0 +1,43 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+func TestOrphans(t *testing.T) {
+	tagged := []types.Orphan{
+		{Type: "sqs", ID: "arn:aws:sqs:us-east-1:123456789012:orders"},
+		{Type: "sqs", ID: "arn:aws:sqs:us-east-1:123456789012:orders-old"},
+		{Type: "logs:log-group", ID: "arn:aws:logs:us-east-1:123456789012:log-group:api"},
+		{Type: "Microsoft.Storage/storageAccounts", ID: "/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/st1"},
+	}
+	stateIds := []string{
+		"arn:aws:sqs:us-east-1:123456789012:orders",
+		"arn:aws:logs:us-east-1:123456789012:log-group:api:*",
+		"/subscriptions/0000/resourcegroups/rg/providers/Microsoft.Storage/storageAccounts/st1",
+	}
+
+	want := []types.Orphan{{Type: "sqs", ID: "arn:aws:sqs:us-east-1:123456789012:orders-old"}}
+	if got := Orphans(tagged, stateIds); !reflect.DeepEqual(got, want) {
+		t.Errorf("Orphans() = %v, want %v", got, want)
+	}
+}
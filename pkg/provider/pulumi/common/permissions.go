@@ -0,0 +1,94 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"sort"
+	"strings"
+
+	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+// PermissionMapper is implemented by providers that can describe the grants the actions of a policy
+// become, so they can be reviewed before they are deployed.
+type PermissionMapper interface {
+	// Grants returns the provider's permissions or roles that allow the action on the resource
+	Grants(action v1.Action, resource *v1.Resource) []string
+}
+
+// resourceKey is how a resource is shown, e.g. bucket:images
+func resourceKey(r *v1.Resource) string {
+	return strings.ToLower(r.Type.String()) + ":" + r.Name
+}
+
+// appendMissing appends the values not already in list.
+func appendMissing(list []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, l := range list {
+			if l == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// Permissions returns the permissions the policies grant each function, merging the policies of the same
+// function and resource, sorted by function and resource.
+func Permissions(policies []*v1.PolicyResource, m PermissionMapper) []types.Permission {
+	byKey := map[string]*types.Permission{}
+	for _, p := range policies {
+		for _, principal := range p.Principals {
+			if principal.Type != v1.ResourceType_Function {
+				continue
+			}
+			for _, r := range p.Resources {
+				key := principal.Name + "/" + resourceKey(r)
+				perm, ok := byKey[key]
+				if !ok {
+					perm = &types.Permission{Function: principal.Name, Resource: resourceKey(r), Actions: []string{}, Grants: []string{}}
+					byKey[key] = perm
+				}
+				for _, a := range p.Actions {
+					perm.Actions = appendMissing(perm.Actions, a.String())
+					perm.Grants = appendMissing(perm.Grants, m.Grants(a, r)...)
+				}
+			}
+		}
+	}
+
+	perms := make([]types.Permission, 0, len(byKey))
+	for _, perm := range byKey {
+		sort.Strings(perm.Actions)
+		sort.Strings(perm.Grants)
+		perms = append(perms, *perm)
+	}
+	sort.Slice(perms, func(i, j int) bool {
+		if perms[i].Function != perms[j].Function {
+			return perms[i].Function < perms[j].Function
+		}
+		return perms[i].Resource < perms[j].Resource
+	})
+	return perms
+}
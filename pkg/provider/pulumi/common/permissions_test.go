@@ -0,0 +1,67 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+type fakeMapper map[v1.Action][]string
+
+func (m fakeMapper) Grants(action v1.Action, resource *v1.Resource) []string {
+	return m[action]
+}
+
+func TestPermissions(t *testing.T) {
+	m := fakeMapper{
+		v1.Action_BucketFileGet:     {"s3:GetObject"},
+		v1.Action_BucketFileList:    {"s3:ListObjectsV2"},
+		v1.Action_TopicEventPublish: {"sns:Publish"},
+	}
+	images := &v1.Resource{Type: v1.ResourceType_Bucket, Name: "images"}
+	policies := []*v1.PolicyResource{
+		{
+			Principals: []*v1.Resource{{Type: v1.ResourceType_Function, Name: "thumbs"}, {Type: v1.ResourceType_Function, Name: "gallery"}},
+			Actions:    []v1.Action{v1.Action_BucketFileGet},
+			Resources:  []*v1.Resource{images},
+		},
+		{
+			Principals: []*v1.Resource{{Type: v1.ResourceType_Function, Name: "gallery"}},
+			Actions:    []v1.Action{v1.Action_BucketFileList, v1.Action_BucketFileGet},
+			Resources:  []*v1.Resource{images},
+		},
+		{
+			Principals: []*v1.Resource{{Type: v1.ResourceType_Function, Name: "thumbs"}},
+			Actions:    []v1.Action{v1.Action_TopicEventPublish},
+			Resources:  []*v1.Resource{{Type: v1.ResourceType_Topic, Name: "resized"}},
+		},
+	}
+
+	want := []types.Permission{
+		{Function: "gallery", Resource: "bucket:images", Actions: []string{"BucketFileGet", "BucketFileList"}, Grants: []string{"s3:GetObject", "s3:ListObjectsV2"}},
+		{Function: "thumbs", Resource: "bucket:images", Actions: []string{"BucketFileGet"}, Grants: []string{"s3:GetObject"}},
+		{Function: "thumbs", Resource: "topic:resized", Actions: []string{"TopicEventPublish"}, Grants: []string{"sns:Publish"}},
+	}
+	if got := Permissions(policies, m); !reflect.DeepEqual(got, want) {
+		t.Errorf("Permissions() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import "github.com/nitrictech/cli/pkg/provider/types"
+
+// QuotaChecker is implemented by providers that can read the account quotas that commonly block the
+// first deploy of a stack, e.g. a new account's low concurrency limits.
+type QuotaChecker interface {
+	Quotas() ([]types.Quota, error)
+}
@@ -0,0 +1,92 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// SiteEnvFile is published with every site so the frontend can discover the stack's API endpoints.
+const SiteEnvFile = "nitric.json"
+
+type SiteFile struct {
+	// Key is the slash separated path of the file relative to the site root
+	Key         string
+	Path        string
+	ContentType string
+}
+
+// SiteFiles lists all files under dir that should be uploaded for a site.
+func SiteFiles(dir string) ([]SiteFile, error) {
+	files := []SiteFile{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, SiteFile{
+			Key:         filepath.ToSlash(rel),
+			Path:        path,
+			ContentType: contentType(path),
+		})
+		return nil
+	})
+	return files, err
+}
+
+func contentType(path string) string {
+	ct := mime.TypeByExtension(filepath.Ext(path))
+	if ct == "" {
+		return "application/octet-stream"
+	}
+	return ct
+}
+
+// SiteEnv renders the content of SiteEnvFile from the deployed API endpoints.
+func SiteEnv(apiEndpoints map[string]pulumi.StringInput) pulumi.StringOutput {
+	names := []string{}
+	for k := range apiEndpoints {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	endpoints := []interface{}{}
+	for _, k := range names {
+		endpoints = append(endpoints, apiEndpoints[k])
+	}
+
+	return pulumi.All(endpoints...).ApplyT(func(all []interface{}) (string, error) {
+		apis := map[string]string{}
+		for i, k := range names {
+			apis[k] = all[i].(string)
+		}
+		b, err := json.Marshal(map[string]interface{}{"apis": apis})
+		return string(b), err
+	}).(pulumi.StringOutput)
+}
@@ -0,0 +1,61 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSiteFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nitric-site")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, f := range []string{"index.html", "css/app.css", "data/export.nosuchext"} {
+		path := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := SiteFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SiteFile{
+		{Key: "css/app.css", Path: filepath.Join(dir, "css", "app.css"), ContentType: "text/css; charset=utf-8"},
+		{Key: "data/export.nosuchext", Path: filepath.Join(dir, "data", "export.nosuchext"), ContentType: "application/octet-stream"},
+		{Key: "index.html", Path: filepath.Join(dir, "index.html"), ContentType: "text/html; charset=utf-8"},
+	}
+	if !cmp.Equal(got, want) {
+		t.Error(cmp.Diff(want, got))
+	}
+
+	if _, err := SiteFiles(filepath.Join(dir, "missing")); err == nil {
+		t.Error("SiteFiles() of a missing directory expected an error")
+	}
+}
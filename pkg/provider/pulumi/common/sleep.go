@@ -0,0 +1,52 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+// Sleeper is implemented by providers that can put the always running compute of a deployed stack to sleep,
+// ids maps the names of the compute units to the ids they were deployed with.
+type Sleeper interface {
+	Sleep(ids map[string]string, sleeping bool) error
+}
+
+// ValidateSleep checks the sleep and wake schedules.
+func ValidateSleep(s *stack.Sleep) error {
+	if s == nil {
+		return nil
+	}
+	if s.Wake == "" || s.Sleep == "" {
+		return errors.New("sleep requires both the wake and sleep schedules")
+	}
+	for _, exp := range []string{s.Wake, s.Sleep} {
+		if _, err := cron.ParseStandard(exp); err != nil {
+			return fmt.Errorf("sleep schedule %s is not a valid cron expression: %w", exp, err)
+		}
+	}
+	if _, err := time.LoadLocation(s.TimeZoneOrDefault()); err != nil {
+		return fmt.Errorf("sleep time zone %s is not valid: %w", s.TimeZone, err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func TestValidateSleep(t *testing.T) {
+	tests := []struct {
+		name    string
+		sleep   *stack.Sleep
+		wantErr bool
+	}{
+		{name: "not configured"},
+		{
+			name:  "valid",
+			sleep: &stack.Sleep{Wake: "0 8 * * 1-5", Sleep: "0 19 * * 1-5", TimeZone: "Australia/Sydney"},
+		},
+		{
+			name:    "missing wake",
+			sleep:   &stack.Sleep{Sleep: "0 19 * * 1-5"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid schedule",
+			sleep:   &stack.Sleep{Wake: "at 8am", Sleep: "0 19 * * 1-5"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid time zone",
+			sleep:   &stack.Sleep{Wake: "0 8 * * 1-5", Sleep: "0 19 * * 1-5", TimeZone: "Mars/Olympus"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSleep(tt.sleep); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSleep() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
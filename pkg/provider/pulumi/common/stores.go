@@ -0,0 +1,40 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"time"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+// DocumentStorer is implemented by providers that can access the collections of a deployed stack.
+type DocumentStorer interface {
+	Documents(stackName string) (types.DocumentStore, error)
+}
+
+// BucketStorer is implemented by providers that can access the buckets of a deployed stack,
+// names maps the project's bucket names to the names they were deployed with.
+type BucketStorer interface {
+	Buckets(names map[string]string) (types.BucketStore, error)
+}
+
+// StateArchiver is implemented by providers that can write snapshots of a stack to a bucket outside of it,
+// the snapshot can't be deleted or overwritten until retainUntil unless it is zero.
+type StateArchiver interface {
+	ArchiveState(bucket, key string, body []byte, retainUntil time.Time) error
+}
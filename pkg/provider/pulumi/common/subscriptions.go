@@ -0,0 +1,77 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+// FunctionSubscription is the delivery configuration of a function's subscription to a topic.
+type FunctionSubscription struct {
+	stack.Subscription
+
+	Function string
+	Topic    string
+}
+
+// SortedSubscriptions flattens the configured subscriptions, sorted by function then topic.
+func SortedSubscriptions(subs stack.Subscriptions) []FunctionSubscription {
+	res := []FunctionSubscription{}
+	for fn, topics := range subs {
+		for t, s := range topics {
+			res = append(res, FunctionSubscription{Subscription: s, Function: fn, Topic: t})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Function != res[j].Function {
+			return res[i].Function < res[j].Function
+		}
+		return res[i].Topic < res[j].Topic
+	})
+	return res
+}
+
+// ValidateSubscriptions checks each subscription is of a function to a topic it subscribes to, the
+// subscriptions are gathered from code so this is checked when deploying.
+func ValidateSubscriptions(subs stack.Subscriptions, proj *project.Project) error {
+	triggers := map[string][]string{}
+	for _, c := range proj.Computes() {
+		triggers[c.Unit().Name] = c.Unit().Triggers.Topics
+	}
+
+	for _, s := range SortedSubscriptions(subs) {
+		topics, ok := triggers[s.Function]
+		if !ok {
+			return fmt.Errorf("subscription configured for function %s, but the function does not exist", s.Function)
+		}
+		subscribed := false
+		for _, t := range topics {
+			subscribed = subscribed || t == s.Topic
+		}
+		if !subscribed {
+			return fmt.Errorf("subscription configured for function %s on topic %s, but the function does not subscribe to it", s.Function, s.Topic)
+		}
+		if s.BatchSize < 0 || s.MaxConcurrency < 0 || s.VisibilityTimeout < 0 {
+			return fmt.Errorf("subscription of function %s to topic %s has a negative setting", s.Function, s.Topic)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func TestValidateSubscriptions(t *testing.T) {
+	p := project.New(&project.Config{Name: "atest", Dir: "."})
+	p.Functions = map[string]project.Function{
+		"orders": {
+			Handler: "functions/orders.ts",
+			ComputeUnit: project.ComputeUnit{
+				Name:     "orders",
+				Triggers: project.Triggers{Topics: []string{"paid"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		subs    stack.Subscriptions
+		wantErr bool
+	}{
+		{name: "not configured"},
+		{
+			name: "valid",
+			subs: stack.Subscriptions{"orders": {"paid": {BatchSize: 10, MaxConcurrency: 2, VisibilityTimeout: 60}}},
+		},
+		{
+			name:    "unknown function",
+			subs:    stack.Subscriptions{"payments": {"paid": {BatchSize: 10}}},
+			wantErr: true,
+		},
+		{
+			name:    "not subscribed",
+			subs:    stack.Subscriptions{"orders": {"refunded": {BatchSize: 10}}},
+			wantErr: true,
+		},
+		{
+			name:    "negative",
+			subs:    stack.Subscriptions{"orders": {"paid": {MaxConcurrency: -1}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSubscriptions(tt.subs, p); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSubscriptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	sc := &stack.Config{Subscriptions: stack.Subscriptions{
+		"orders": {"paid": {MaxConcurrency: 5}, "refunded": {MaxConcurrency: 2}, "shipped": {BatchSize: 10}},
+	}}
+	if got := sc.MaxConcurrency("orders"); got != 2 {
+		t.Errorf("MaxConcurrency() = %d, want 2", got)
+	}
+	if got := sc.MaxConcurrency("payments"); got != 0 {
+		t.Errorf("MaxConcurrency() = %d, want 0", got)
+	}
+}
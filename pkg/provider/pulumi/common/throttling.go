@@ -0,0 +1,241 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+// ThrottlingExtension declares a rate limit on an api document (its default), an operation or a security scheme.
+const ThrottlingExtension = "x-nitric-throttling"
+
+// ApiLimits are the rate limits of an api.
+type ApiLimits struct {
+	Default *stack.RateLimit
+	// Routes are keyed by RouteKey
+	Routes          map[string]stack.RateLimit
+	SecuritySchemes map[string]stack.RateLimit
+}
+
+// RouteKey is the key of a route, its method and path, e.g. GET /orders/{id}
+func RouteKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// Route returns the limit of the route, or the api's default, nil when the route is not limited.
+func (l *ApiLimits) Route(method, path string) *stack.RateLimit {
+	if r, ok := l.Routes[RouteKey(method, path)]; ok {
+		return &r
+	}
+	return l.Default
+}
+
+// RouteKeys returns the keys of the limited routes, sorted.
+func (l *ApiLimits) RouteKeys() []string {
+	return names(l.Routes)
+}
+
+// routeLimits returns the default and route limits.
+func (l *ApiLimits) routeLimits() []stack.RateLimit {
+	limits := []stack.RateLimit{}
+	if l.Default != nil {
+		limits = append(limits, *l.Default)
+	}
+	for _, r := range l.Routes {
+		limits = append(limits, r)
+	}
+	return limits
+}
+
+// RateLimited is true when any route has a rate.
+func (l *ApiLimits) RateLimited() bool {
+	for _, r := range l.routeLimits() {
+		if r.Rate > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Quotas is true when any route has a quota.
+func (l *ApiLimits) Quotas() bool {
+	for _, r := range l.routeLimits() {
+		if r.Quota > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeExtension decodes the throttling extension, nil when there is none. Extensions of
+// documents loaded from files are json, those built from code are values.
+func decodeExtension(ext map[string]interface{}) (*stack.RateLimit, error) {
+	v, ok := ext[ThrottlingExtension]
+	if !ok {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	r := &stack.RateLimit{}
+	return r, json.Unmarshal(b, r)
+}
+
+// ApiRateLimits returns the limits declared by the api document's extensions, replaced by those in
+// the stack's throttling when it is not nil.
+func ApiRateLimits(doc *openapi3.T, t *stack.Throttling) (*ApiLimits, error) {
+	l := &ApiLimits{
+		Routes:          map[string]stack.RateLimit{},
+		SecuritySchemes: map[string]stack.RateLimit{},
+	}
+
+	var err error
+	l.Default, err = decodeExtension(doc.Extensions)
+	if err != nil {
+		return nil, errors.WithMessage(err, "default "+ThrottlingExtension)
+	}
+	for path, pi := range doc.Paths {
+		for method, op := range pi.Operations() {
+			r, err := decodeExtension(op.Extensions)
+			if err != nil {
+				return nil, errors.WithMessage(err, RouteKey(method, path)+" "+ThrottlingExtension)
+			}
+			if r != nil {
+				l.Routes[RouteKey(method, path)] = *r
+			}
+		}
+	}
+	for name, s := range doc.Components.SecuritySchemes {
+		if s.Value == nil {
+			continue
+		}
+		r, err := decodeExtension(s.Value.Extensions)
+		if err != nil {
+			return nil, errors.WithMessage(err, "security scheme "+name+" "+ThrottlingExtension)
+		}
+		if r != nil {
+			l.SecuritySchemes[name] = *r
+		}
+	}
+
+	if t == nil {
+		return l, nil
+	}
+	if t.Default != nil {
+		l.Default = t.Default
+	}
+	for k, r := range t.Routes {
+		parts := strings.SplitN(k, " ", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("route %s should be a method and path, e.g. GET /orders", k)
+		}
+		l.Routes[RouteKey(parts[0], strings.TrimSpace(parts[1]))] = r
+	}
+	for k, r := range t.SecuritySchemes {
+		l.SecuritySchemes[k] = r
+	}
+	return l, nil
+}
+
+// validateRateLimit checks the limit sets a rate or a quota, and quotas have a period.
+func validateRateLimit(r stack.RateLimit) error {
+	if r.Rate < 0 || r.Burst < 0 || r.Quota < 0 {
+		return errors.New("has a negative setting")
+	}
+	if r.Rate == 0 && r.Quota == 0 {
+		return errors.New("sets neither a rate nor a quota")
+	}
+	if r.Quota == 0 {
+		if r.Period != "" {
+			return errors.New("has a period but no quota")
+		}
+		return nil
+	}
+	for _, p := range stack.QuotaPeriods {
+		if r.Period == p {
+			return nil
+		}
+	}
+	return fmt.Errorf("has a quota period of %q, it should be one of %s", r.Period, strings.Join(stack.QuotaPeriods, ", "))
+}
+
+// ValidateThrottling checks the rate limits are of routes and security schemes of the project's apis,
+// the apis are gathered from code so this is checked when deploying.
+func ValidateThrottling(proj *project.Project, sc *stack.Config) error {
+	for api := range sc.Throttling {
+		if _, ok := proj.ApiDocs[api]; !ok {
+			return fmt.Errorf("throttling configured for api %s, but the api does not exist", api)
+		}
+	}
+
+	for _, api := range names(proj.ApiDocs) {
+		doc := proj.ApiDocs[api]
+		l, err := ApiRateLimits(doc, StackThrottling(sc, api))
+		if err != nil {
+			return errors.WithMessage(err, "api "+api)
+		}
+
+		if l.Default != nil {
+			if err := validateRateLimit(*l.Default); err != nil {
+				return fmt.Errorf("the default limit of api %s %v", api, err)
+			}
+		}
+
+		routes := map[string]bool{}
+		for path, pi := range doc.Paths {
+			for method := range pi.Operations() {
+				routes[RouteKey(method, path)] = true
+			}
+		}
+		for _, k := range names(l.Routes) {
+			if !routes[k] {
+				return fmt.Errorf("limit configured for route %s of api %s, but the route does not exist", k, api)
+			}
+			if err := validateRateLimit(l.Routes[k]); err != nil {
+				return fmt.Errorf("the limit of route %s of api %s %v", k, api, err)
+			}
+		}
+
+		for _, k := range names(l.SecuritySchemes) {
+			if _, ok := doc.Components.SecuritySchemes[k]; !ok {
+				return fmt.Errorf("limit configured for security scheme %s of api %s, but the scheme does not exist", k, api)
+			}
+			if err := validateRateLimit(l.SecuritySchemes[k]); err != nil {
+				return fmt.Errorf("the limit of security scheme %s of api %s %v", k, api, err)
+			}
+		}
+	}
+	return nil
+}
+
+// StackThrottling returns the stack's throttling of the api, nil when it has none.
+func StackThrottling(sc *stack.Config, api string) *stack.Throttling {
+	t, ok := sc.Throttling[api]
+	if !ok {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,148 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+// throttledDoc is an api whose GET /orders route is limited by its document.
+func throttledDoc() *openapi3.T {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.1",
+		Info:    &openapi3.Info{Title: "orders", Version: "v1"},
+		Paths:   openapi3.Paths{},
+		Components: openapi3.Components{
+			SecuritySchemes: openapi3.SecuritySchemes{
+				"key": &openapi3.SecuritySchemeRef{Value: openapi3.NewSecurityScheme()},
+			},
+		},
+	}
+	get := openapi3.NewOperation()
+	get.Extensions = map[string]interface{}{ThrottlingExtension: json.RawMessage(`{"rate": 10}`)}
+	doc.AddOperation("/orders", "GET", get)
+	doc.AddOperation("/orders", "POST", openapi3.NewOperation())
+	return doc
+}
+
+func TestApiRateLimits(t *testing.T) {
+	l, err := ApiRateLimits(throttledDoc(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]stack.RateLimit{"GET /orders": {Rate: 10}}; !reflect.DeepEqual(l.Routes, want) {
+		t.Errorf("Routes = %v, want %v", l.Routes, want)
+	}
+	if r := l.Route("POST", "/orders"); r != nil {
+		t.Errorf("expected POST /orders to not be limited, got %v", r)
+	}
+
+	l, err = ApiRateLimits(throttledDoc(), &stack.Throttling{
+		Default: &stack.RateLimit{Quota: 1000, Period: "day"},
+		Routes:  map[string]stack.RateLimit{"get /orders": {Rate: 5, Burst: 20}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := l.Route("GET", "/orders"); r == nil || r.Rate != 5 || r.Burst != 20 {
+		t.Errorf("expected the stack to replace the limit of GET /orders, got %v", r)
+	}
+	if r := l.Route("POST", "/orders"); r == nil || r.Quota != 1000 {
+		t.Errorf("expected POST /orders to have the default limit, got %v", r)
+	}
+	if !l.RateLimited() || !l.Quotas() {
+		t.Errorf("expected rate limits and quotas, got %v", l)
+	}
+}
+
+func TestValidateThrottling(t *testing.T) {
+	p := project.New(&project.Config{Name: "atest", Dir: "."})
+	p.ApiDocs = map[string]*openapi3.T{"orders": throttledDoc()}
+
+	tests := []struct {
+		name       string
+		throttling map[string]stack.Throttling
+		wantErr    bool
+	}{
+		{name: "document only"},
+		{
+			name: "valid",
+			throttling: map[string]stack.Throttling{"orders": {
+				Routes:          map[string]stack.RateLimit{"POST /orders": {Rate: 1, Quota: 100, Period: "month"}},
+				SecuritySchemes: map[string]stack.RateLimit{"key": {Rate: 2}},
+			}},
+		},
+		{
+			name:       "unknown api",
+			throttling: map[string]stack.Throttling{"payments": {Default: &stack.RateLimit{Rate: 1}}},
+			wantErr:    true,
+		},
+		{
+			name:       "unknown route",
+			throttling: map[string]stack.Throttling{"orders": {Routes: map[string]stack.RateLimit{"DELETE /orders": {Rate: 1}}}},
+			wantErr:    true,
+		},
+		{
+			name:       "unknown scheme",
+			throttling: map[string]stack.Throttling{"orders": {SecuritySchemes: map[string]stack.RateLimit{"jwt": {Rate: 1}}}},
+			wantErr:    true,
+		},
+		{
+			name:       "no rate or quota",
+			throttling: map[string]stack.Throttling{"orders": {Default: &stack.RateLimit{Burst: 10}}},
+			wantErr:    true,
+		},
+		{
+			name:       "quota without period",
+			throttling: map[string]stack.Throttling{"orders": {Default: &stack.RateLimit{Quota: 10}}},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateThrottling(p, &stack.Config{Throttling: tt.throttling})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateThrottling() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSkippedCapabilities(t *testing.T) {
+	p := project.New(&project.Config{Name: "atest", Dir: "."})
+	p.ApiDocs = map[string]*openapi3.T{"orders": throttledDoc()}
+	sc := &stack.Config{Throttling: map[string]stack.Throttling{"orders": {
+		SecuritySchemes: map[string]stack.RateLimit{"key": {Rate: 2}},
+	}}}
+
+	supported := CapabilitiesExcept(CapabilityQuotas, CapabilitySchemeLimits)
+	if err := CheckCapabilities("aws", supported, p, sc); err != nil {
+		t.Errorf("expected unsupported limits to not fail the deployment, got %v", err)
+	}
+
+	want := []string{"security scheme limits (orders) are not supported on aws and will not be deployed"}
+	if got := SkippedCapabilities("aws", supported, p, sc); !reflect.DeepEqual(got, want) {
+		t.Errorf("SkippedCapabilities() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+// ValidateTls checks the minimum TLS version is one the stack can have.
+func ValidateTls(t *stack.Tls) error {
+	if t == nil || t.MinVersion == "" {
+		return nil
+	}
+	for _, v := range stack.TlsVersions {
+		if t.MinVersion == v {
+			return nil
+		}
+	}
+	return fmt.Errorf("tls has a minVersion of %q, it should be one of %s", t.MinVersion, strings.Join(stack.TlsVersions, ", "))
+}
@@ -0,0 +1,43 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func TestValidateTls(t *testing.T) {
+	tests := []struct {
+		name    string
+		tls     *stack.Tls
+		wantErr bool
+	}{
+		{name: "not configured"},
+		{name: "allow http", tls: &stack.Tls{AllowHttp: true}},
+		{name: "valid", tls: &stack.Tls{MinVersion: "1.1"}},
+		{name: "invalid", tls: &stack.Tls{MinVersion: "TLS1_2"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateTls(tt.tls); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTls() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -22,6 +22,8 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/auto"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
 	"github.com/nitrictech/cli/pkg/stack"
 )
 
@@ -42,6 +44,8 @@ type PulumiProvider interface {
 	CleanUp()
 	Ask() (*stack.Config, error)
 	TryPullImages() error
+	Env(c project.Compute) []types.EnvVar
+	Capabilities() Capabilities
 }
 
 func Tags(ctx *pulumi.Context, name string) pulumi.StringMap {
@@ -0,0 +1,41 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+// ValidateWaf checks the waf has apis to protect, it is attached to the cdn serving them as the api
+// gateways can't have one of their own.
+func ValidateWaf(sc *stack.Config) error {
+	if sc.Waf == nil {
+		return nil
+	}
+	if sc.Cdn == nil || len(sc.Cdn.Apis) == 0 {
+		return errors.New("the waf protects the apis served from the cdn, add them to cdn.apis")
+	}
+	if sc.Waf.RateLimit < 0 {
+		return errors.New("the waf rate limit can't be negative")
+	}
+	if len(sc.Waf.ManagedRules) == 0 && sc.Waf.RateLimit == 0 {
+		return errors.New("the waf has no rules, add managedRules or a rateLimit")
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func TestValidateWaf(t *testing.T) {
+	apis := &stack.Cdn{Apis: map[string]stack.CdnTarget{"main": {}}}
+	tests := []struct {
+		name    string
+		sc      *stack.Config
+		wantErr bool
+	}{
+		{name: "not configured", sc: &stack.Config{}},
+		{
+			name: "valid",
+			sc:   &stack.Config{Cdn: apis, Waf: &stack.Waf{ManagedRules: []string{"AWSManagedRulesCommonRuleSet"}, RateLimit: 2000}},
+		},
+		{
+			name:    "no cdn",
+			sc:      &stack.Config{Waf: &stack.Waf{RateLimit: 2000}},
+			wantErr: true,
+		},
+		{
+			name:    "cdn without apis",
+			sc:      &stack.Config{Cdn: &stack.Cdn{Buckets: map[string]stack.CdnTarget{"images": {}}}, Waf: &stack.Waf{RateLimit: 2000}},
+			wantErr: true,
+		},
+		{
+			name:    "no rules",
+			sc:      &stack.Config{Cdn: apis, Waf: &stack.Waf{Monitor: true}},
+			wantErr: true,
+		},
+		{
+			name:    "negative rate limit",
+			sc:      &stack.Config{Cdn: apis, Waf: &stack.Waf{RateLimit: -1}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateWaf(tt.sc); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateWaf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optpreview"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// functionConfig match the properties of the function resources that only configure them, keyed by resource type.
+var functionConfig = map[string]*regexp.Regexp{
+	"aws:lambda/function:Function":            regexp.MustCompile(`^(environment|memorySize|timeout|reservedConcurrentExecutions|tags)(\.|\[|$)`),
+	"gcp:cloudrun/service:Service":            regexp.MustCompile(`^template\.spec\.containers\[\d+\]\.(envs|resources)(\.|\[|$)`),
+	"azure-native:web/v20210301:ContainerApp": regexp.MustCompile(`^template\.containers\[\d+\]\.(env|resources)(\.|\[|$)`),
+}
+
+// reusedImageType is the type of the image resources that are no longer declared when the deployed images are reused,
+// deleting them is skipped as they are not targeted.
+const reusedImageType = "docker:image:Image"
+
+// isConfigChange returns true if the change only updates the configuration of a function.
+func isConfigChange(c types.Change) bool {
+	re, ok := functionConfig[c.Type]
+	if !ok || c.Op != string(apitype.OpUpdate) || len(c.Diffs) == 0 {
+		return false
+	}
+	for _, d := range c.Diffs {
+		if !re.MatchString(d) {
+			return false
+		}
+	}
+	return true
+}
+
+// reuseImages gives the provider the images pushed by the last update, so the functions are not built and pushed again.
+func (p *pulumiDeployment) reuseImages(ctx context.Context, s *auto.Stack) error {
+	reuser, ok := p.prov.(common.ImageReuser)
+	if !ok {
+		return utils.NewNotSupportedErr("updating only the configuration is not supported on " + p.sc.Provider)
+	}
+
+	outputs, err := s.Outputs(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "Outputs")
+	}
+
+	images := map[string]string{}
+	for k, v := range outputs {
+		if strings.HasPrefix(k, "image:") {
+			images[strings.TrimPrefix(k, "image:")] = fmt.Sprint(v.Value)
+		}
+	}
+
+	missing := []string{}
+	for _, c := range p.proj.Computes() {
+		if _, ok := images[c.ImageTagName(p.proj, p.sc.Provider)]; !ok {
+			missing = append(missing, c.Unit().Name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("%s have not been deployed, update the stack without --config-only first", strings.Join(missing, ", "))
+	}
+
+	reuser.ReuseImages(images)
+	return nil
+}
+
+// configChanges returns the function configuration changes an update of the loaded stack would make and the
+// URNs of the resources to update, it returns an error if the update would make any other change.
+func (p *pulumiDeployment) configChanges(s *auto.Stack) ([]string, []types.Change, error) {
+	previewEvents := make(chan events.EngineEvent)
+	done := make(chan []apitype.StepEventMetadata)
+	go collectSteps(previewEvents, done)
+
+	_, err := s.Preview(context.Background(), optpreview.EventStreams(previewEvents))
+	steps := <-done
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "Preview")
+	}
+
+	targets := []string{}
+	changes := []types.Change{}
+	others := []string{}
+	for _, md := range steps {
+		c := configChange(md)
+		switch {
+		case isConfigChange(c):
+			targets = append(targets, md.URN)
+			changes = append(changes, c)
+		case c.Type == reusedImageType && c.Op == string(apitype.OpDelete):
+			// the image is reused, not deleted
+		default:
+			others = append(others, c.String())
+		}
+	}
+	if len(others) > 0 {
+		return nil, nil, fmt.Errorf("the update changes more than the configuration of the functions, update the stack without --config-only:\n  %s", strings.Join(others, "\n  "))
+	}
+	return targets, changes, nil
+}
+
+// configChange returns the change of the step, with the paths of the properties it changes as the diffs.
+func configChange(md apitype.StepEventMetadata) types.Change {
+	diffs := []string{}
+	for path := range md.DetailedDiff {
+		diffs = append(diffs, path)
+	}
+	if len(diffs) == 0 {
+		diffs = append(diffs, md.Diffs...)
+	}
+	sort.Strings(diffs)
+
+	urnSplit := strings.Split(md.URN, "::")
+	return types.Change{
+		Op:       string(md.Op),
+		Type:     md.Type,
+		Name:     urnSplit[len(urnSplit)-1],
+		Diffs:    diffs,
+		Security: isSecurityType(md.Type),
+	}
+}
+
+// collectSteps sends the steps that the preview would change a resource with to done once the channel is closed.
+func collectSteps(eventChannel <-chan events.EngineEvent, done chan<- []apitype.StepEventMetadata) {
+	steps := []apitype.StepEventMetadata{}
+	for event := range eventChannel {
+		if event.ResourcePreEvent == nil {
+			continue
+		}
+		md := event.ResourcePreEvent.Metadata
+		if !previewOps[md.Op] || strings.HasPrefix(md.Type, "pulumi:") {
+			continue
+		}
+		steps = append(steps, md)
+	}
+	done <- steps
+}
@@ -0,0 +1,111 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+func Test_isConfigChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		change types.Change
+		want   bool
+	}{
+		{
+			name:   "lambda environment",
+			change: types.Change{Op: "update", Type: "aws:lambda/function:Function", Diffs: []string{"environment.variables.GREETING"}},
+			want:   true,
+		},
+		{
+			name:   "lambda memory and timeout",
+			change: types.Change{Op: "update", Type: "aws:lambda/function:Function", Diffs: []string{"memorySize", "timeout"}},
+			want:   true,
+		},
+		{
+			name:   "lambda image",
+			change: types.Change{Op: "update", Type: "aws:lambda/function:Function", Diffs: []string{"environment", "imageUri"}},
+			want:   false,
+		},
+		{
+			name:   "cloud run env",
+			change: types.Change{Op: "update", Type: "gcp:cloudrun/service:Service", Diffs: []string{"template.spec.containers[0].envs[2].value"}},
+			want:   true,
+		},
+		{
+			name:   "container app memory",
+			change: types.Change{Op: "update", Type: "azure-native:web/v20210301:ContainerApp", Diffs: []string{"template.containers[0].resources.memory"}},
+			want:   true,
+		},
+		{
+			name:   "container app scale",
+			change: types.Change{Op: "update", Type: "azure-native:web/v20210301:ContainerApp", Diffs: []string{"template.scale.maxReplicas"}},
+			want:   false,
+		},
+		{
+			name:   "replaced function",
+			change: types.Change{Op: "replace", Type: "aws:lambda/function:Function", Diffs: []string{"environment"}},
+			want:   false,
+		},
+		{
+			name:   "other resource",
+			change: types.Change{Op: "update", Type: "aws:sns/topic:Topic", Diffs: []string{"tags"}},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isConfigChange(tt.change); got != tt.want {
+				t.Errorf("isConfigChange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_configChange(t *testing.T) {
+	md := apitype.StepEventMetadata{
+		Op:    apitype.OpUpdate,
+		URN:   "urn:pulumi:dev::app::nitric:func:AWSLambda$aws:lambda/function:Function::hello",
+		Type:  "aws:lambda/function:Function",
+		Diffs: []string{"environment", "memorySize"},
+		DetailedDiff: map[string]apitype.PropertyDiff{
+			"memorySize":                     {Kind: apitype.DiffUpdate},
+			"environment.variables.GREETING": {Kind: apitype.DiffUpdate},
+		},
+	}
+
+	want := types.Change{
+		Op:    "update",
+		Type:  "aws:lambda/function:Function",
+		Name:  "hello",
+		Diffs: []string{"environment.variables.GREETING", "memorySize"},
+	}
+	if got := configChange(md); !reflect.DeepEqual(got, want) {
+		t.Errorf("configChange() = %v, want %v", got, want)
+	}
+
+	md.DetailedDiff = nil
+	want.Diffs = []string{"environment", "memorySize"}
+	if got := configChange(md); !reflect.DeepEqual(got, want) {
+		t.Errorf("configChange() without a detailed diff = %v, want %v", got, want)
+	}
+}
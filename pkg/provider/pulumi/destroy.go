@@ -0,0 +1,267 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optdestroy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+// maxDestroyPasses is the number of times a destroy is repeated, skipping the resources that failed
+// to delete and those they depend on, so that as much of the stack as possible is deleted.
+const maxDestroyPasses = 5
+
+// destroy deletes the resources of the stack, returning the URNs of those that could not be
+// deleted and the reason why.
+func (a *pulumiDeployment) destroy(ctx context.Context, s *auto.Stack, log output.Progress) (map[string]string, error) {
+	failed := map[string]string{}
+	targets := []string{}
+
+	for pass := 1; pass <= maxDestroyPasses; pass++ {
+		operation := "down"
+		if pass > 1 {
+			operation = fmt.Sprintf("down-%d", pass)
+		}
+
+		failures := make(chan events.EngineEvent)
+		done := make(chan map[string]string)
+		go collectFailures(failures, done)
+
+		opts := destroyLoggingOpts(log, a.listener, a.history(operation, log), failures)
+		if len(targets) > 0 {
+			opts = append(opts, optdestroy.Target(targets))
+		}
+
+		res, err := s.Destroy(ctx, opts...)
+		passFailed := <-done
+		for urn, reason := range passFailed {
+			failed[urn] = reason
+		}
+		if err == nil {
+			return failed, nil
+		}
+		if len(passFailed) == 0 {
+			// the destroy failed for a reason other than a resource
+			return nil, errors.WithMessage(err, res.Summary.Message)
+		}
+
+		_, state, err := exportState(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		targets = remainingTargets(state, failed)
+		if len(targets) == 0 {
+			break
+		}
+		log.Busyf("Deleting the rest of the stack, %d resources failed to delete", len(failed))
+	}
+	return failed, nil
+}
+
+// collectFailures sends the URNs of the resources that failed and the error reported for them
+// to done once the channel is closed.
+func collectFailures(eventChannel <-chan events.EngineEvent, done chan<- map[string]string) {
+	reasons := map[string]string{}
+	failed := map[string]string{}
+	for event := range eventChannel {
+		if event.DiagnosticEvent != nil && event.DiagnosticEvent.Severity == "error" && event.DiagnosticEvent.URN != "" {
+			reasons[event.DiagnosticEvent.URN] = strings.TrimSpace(event.DiagnosticEvent.Message)
+		}
+		if event.ResOpFailedEvent != nil {
+			failed[event.ResOpFailedEvent.Metadata.URN] = ""
+		}
+	}
+
+	for urn := range failed {
+		failed[urn] = reasons[urn]
+		if failed[urn] == "" {
+			failed[urn] = "failed to delete"
+		}
+	}
+	done <- failed
+}
+
+// isDeletable returns false for resources that pulumi deletes along with the stack.
+func isDeletable(r apitype.ResourceV3) bool {
+	return string(r.Type) != "pulumi:pulumi:Stack" && !strings.HasPrefix(string(r.Type), "pulumi:providers:")
+}
+
+// remainingTargets returns the resources in the state that can still be deleted, skipping the
+// failed resources, their parents and the resources they depend on.
+func remainingTargets(state *apitype.DeploymentV3, failed map[string]string) []string {
+	byURN := map[resource.URN]apitype.ResourceV3{}
+	for _, r := range state.Resources {
+		byURN[r.URN] = r
+	}
+
+	blocked := map[resource.URN]bool{}
+	var block func(urn resource.URN)
+	block = func(urn resource.URN) {
+		if urn == "" || blocked[urn] {
+			return
+		}
+		blocked[urn] = true
+		r, ok := byURN[urn]
+		if !ok {
+			return
+		}
+		block(r.Parent)
+		for _, d := range r.Dependencies {
+			block(d)
+		}
+	}
+	for urn := range failed {
+		block(resource.URN(urn))
+	}
+
+	targets := []string{}
+	for _, r := range state.Resources {
+		if !blocked[r.URN] && isDeletable(r) {
+			targets = append(targets, string(r.URN))
+		}
+	}
+	return targets
+}
+
+// emptyDeployedBuckets deletes the files of the buckets chosen to be emptied, as the providers
+// refuse to delete buckets that hold files. Failures are logged, the bucket is then reported
+// as left over when it fails to delete.
+func (a *pulumiDeployment) emptyDeployedBuckets(log output.Progress) {
+	if !a.emptyBuckets && len(a.sc.EmptyBuckets) == 0 {
+		return
+	}
+
+	b, ok := a.prov.(common.BucketStorer)
+	if !ok {
+		log.Debugf("buckets are not emptied on %s, they are deleted along with their files\n", a.sc.Provider)
+		return
+	}
+
+	names, err := a.outputs("bucket:")
+	if err != nil {
+		log.Failf("Unable to find the deployed buckets: %v\n", err)
+		return
+	}
+	store, err := b.Buckets(names)
+	if err != nil {
+		log.Failf("Unable to access the deployed buckets: %v\n", err)
+		return
+	}
+
+	buckets := []string{}
+	for k := range names {
+		if a.emptyBuckets || a.sc.EmptiesBucket(k) {
+			buckets = append(buckets, k)
+		}
+	}
+	sort.Strings(buckets)
+
+	for _, k := range buckets {
+		log.Busyf("Emptying bucket %s", k)
+		n, err := store.Empty(k)
+		if err != nil {
+			log.Failf("Emptying bucket %s: %v\n", k, err)
+			continue
+		}
+		log.Successf("Emptied bucket %s (%d files)\n", k, n)
+	}
+}
+
+// clearPendingOperations removes the operations that were in progress when an update was
+// interrupted. The URNs of the resources that were being created are returned, as they may
+// exist without being in the state.
+func clearPendingOperations(ctx context.Context, s *auto.Stack) ([]string, error) {
+	dep, state, err := exportState(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	if len(state.PendingOperations) == 0 {
+		return nil, nil
+	}
+
+	creating := []string{}
+	for _, op := range state.PendingOperations {
+		if op.Type == apitype.OperationTypeCreating {
+			creating = append(creating, string(op.Resource.URN))
+		}
+	}
+	state.PendingOperations = nil
+
+	dep.Deployment, err = json.Marshal(state)
+	if err != nil {
+		return nil, errors.WithMessage(err, "encoding stack state")
+	}
+	return creating, errors.WithMessage(s.Import(ctx, *dep), "importing stack state")
+}
+
+// urnName returns the type and name of a resource from its URN, e.g. Bucket/images.
+func urnName(urn string) string {
+	parts := strings.Split(urn, "::")
+	if len(parts) < 2 {
+		return urn
+	}
+	typeParts := strings.Split(parts[len(parts)-2], "$")
+	rType := typeParts[len(typeParts)-1]
+	rType = rType[strings.LastIndex(rType, ":")+1:]
+	return rType + "/" + parts[len(parts)-1]
+}
+
+// leftoversError reports the resources that could not be deleted and why, along with the
+// resources that may exist because their creation was interrupted.
+func leftoversError(ctx context.Context, s *auto.Stack, failed map[string]string, interrupted []string) error {
+	if len(failed) == 0 && len(interrupted) == 0 {
+		return nil
+	}
+
+	lines := []string{}
+	for urn, reason := range failed {
+		lines = append(lines, fmt.Sprintf("  %s: %s", urnName(urn), reason))
+	}
+	for _, urn := range interrupted {
+		lines = append(lines, fmt.Sprintf("  %s: its creation was interrupted, check whether it exists and delete it manually", urnName(urn)))
+	}
+	sort.Strings(lines)
+
+	msg := "the stack could not be completely deleted:\n" + strings.Join(lines, "\n")
+
+	_, state, err := exportState(ctx, s)
+	if err == nil {
+		kept := 0
+		for _, r := range state.Resources {
+			if _, ok := failed[string(r.URN)]; !ok && isDeletable(r) {
+				kept++
+			}
+		}
+		if kept > 0 {
+			msg += fmt.Sprintf("\n%d other resources were kept as the failed resources depend on them, run down again once the failures are resolved", kept)
+		}
+	}
+	return errors.New(msg)
+}
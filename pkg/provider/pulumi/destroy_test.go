@@ -0,0 +1,93 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+const (
+	stackURN  = "urn:pulumi:prod::shop::pulumi:pulumi:Stack::shop-prod"
+	bucketURN = "urn:pulumi:prod::shop::nitric:bucket:AwsS3Bucket$aws:s3/bucket:Bucket::images"
+	compURN   = "urn:pulumi:prod::shop::nitric:bucket:AwsS3Bucket::images"
+	policyURN = "urn:pulumi:prod::shop::aws:iam/rolePolicy:RolePolicy::ordersImages"
+	topicURN  = "urn:pulumi:prod::shop::aws:sns/topic:Topic::orders"
+)
+
+func TestRemainingTargets(t *testing.T) {
+	state := &apitype.DeploymentV3{
+		Resources: []apitype.ResourceV3{
+			{URN: stackURN, Type: "pulumi:pulumi:Stack"},
+			{URN: "urn:pulumi:prod::shop::pulumi:providers:aws::default", Type: "pulumi:providers:aws"},
+			{URN: compURN, Type: "nitric:bucket:AwsS3Bucket", Parent: stackURN},
+			{URN: bucketURN, Type: "aws:s3/bucket:Bucket", Parent: compURN},
+			{URN: policyURN, Type: "aws:iam/rolePolicy:RolePolicy", Parent: stackURN, Dependencies: []resource.URN{topicURN}},
+			{URN: topicURN, Type: "aws:sns/topic:Topic", Parent: stackURN},
+		},
+	}
+
+	got := remainingTargets(state, map[string]string{bucketURN: "BucketNotEmpty"})
+	want := []string{policyURN, topicURN}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("remainingTargets() = %v, want %v", got, want)
+	}
+
+	got = remainingTargets(state, map[string]string{policyURN: "AccessDenied"})
+	if len(got) != 0 {
+		t.Errorf("expected the policy's dependencies to be kept, got %v", got)
+	}
+}
+
+func TestCollectFailures(t *testing.T) {
+	eventChannel := make(chan events.EngineEvent)
+	done := make(chan map[string]string)
+	go collectFailures(eventChannel, done)
+
+	eventChannel <- events.EngineEvent{EngineEvent: apitype.EngineEvent{
+		DiagnosticEvent: &apitype.DiagnosticEvent{URN: bucketURN, Severity: "error", Message: "BucketNotEmpty: the bucket is not empty\n"},
+	}}
+	eventChannel <- events.EngineEvent{EngineEvent: apitype.EngineEvent{
+		ResOpFailedEvent: &apitype.ResOpFailedEvent{Metadata: apitype.StepEventMetadata{URN: bucketURN}},
+	}}
+	eventChannel <- events.EngineEvent{EngineEvent: apitype.EngineEvent{
+		ResOpFailedEvent: &apitype.ResOpFailedEvent{Metadata: apitype.StepEventMetadata{URN: topicURN}},
+	}}
+	close(eventChannel)
+
+	got := <-done
+	want := map[string]string{
+		bucketURN: "BucketNotEmpty: the bucket is not empty",
+		topicURN:  "failed to delete",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectFailures() = %v, want %v", got, want)
+	}
+}
+
+func TestUrnName(t *testing.T) {
+	if got := urnName(bucketURN); got != "Bucket/images" {
+		t.Errorf("urnName() = %s", got)
+	}
+	if got := urnName(topicURN); got != "Topic/orders" {
+		t.Errorf("urnName() = %s", got)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/monitoring"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/pubsub"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+type AlertsArgs struct {
+	ProjectId          string
+	Alerts             *stack.Alerts
+	CloudRunners       map[string]*CloudRunner
+	QueueSubscriptions map[string]*pubsub.Subscription
+	Gateways           map[string]*ApiGateway
+}
+
+type Alerts struct {
+	pulumi.ResourceState
+
+	Name         string
+	Channel      *monitoring.NotificationChannel
+	Policies     map[string]*monitoring.AlertPolicy
+	UptimeChecks map[string]*monitoring.UptimeCheckConfig
+}
+
+// newAlerts creates an alerting policy for each rule and the uptime checks, notifying the email address through
+// a notification channel.
+func newAlerts(ctx *pulumi.Context, name string, args *AlertsArgs, opts ...pulumi.ResourceOption) (*Alerts, error) {
+	res := &Alerts{Name: name, Policies: map[string]*monitoring.AlertPolicy{}, UptimeChecks: map[string]*monitoring.UptimeCheckConfig{}}
+	err := ctx.RegisterComponentResource("nitric:alerts:GCPMonitoring", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	res.Channel, err = monitoring.NewNotificationChannel(ctx, name+"-email", &monitoring.NotificationChannelArgs{
+		Project:     pulumi.String(args.ProjectId),
+		DisplayName: pulumi.String(ctx.Stack() + " alerts"),
+		Type:        pulumi.String("email"),
+		Labels:      pulumi.StringMap{"email_address": pulumi.String(args.Alerts.Email)},
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "alerts notification channel")
+	}
+
+	for k, r := range args.Alerts.Rules {
+		threshold := r.Threshold
+		var filter pulumi.StringOutput
+		aggregation := monitoring.AlertPolicyConditionConditionThresholdAggregationArgs{
+			AlignmentPeriod: pulumi.String("60s"),
+		}
+
+		switch r.Metric {
+		case stack.AlertErrorRate:
+			filter = pulumi.Sprintf(`metric.type="run.googleapis.com/request_count" AND resource.type="cloud_run_revision" AND resource.label.service_name="%s" AND metric.label.response_code_class="5xx"`, args.CloudRunners[r.Function].Service.Name)
+			// the rate is aligned per second, the threshold is per minute
+			threshold = r.Threshold / 60
+			aggregation.PerSeriesAligner = pulumi.String("ALIGN_RATE")
+			aggregation.CrossSeriesReducer = pulumi.String("REDUCE_SUM")
+		case stack.AlertLatency:
+			filter = pulumi.Sprintf(`metric.type="run.googleapis.com/request_latencies" AND resource.type="cloud_run_revision" AND resource.label.service_name="%s"`, args.CloudRunners[r.Function].Service.Name)
+			aggregation.PerSeriesAligner = pulumi.String("ALIGN_PERCENTILE_95")
+		case stack.AlertQueueDepth:
+			filter = pulumi.Sprintf(`metric.type="pubsub.googleapis.com/subscription/num_undelivered_messages" AND resource.type="pubsub_subscription" AND resource.label.subscription_id="%s"`, args.QueueSubscriptions[r.Queue].Name)
+			aggregation.PerSeriesAligner = pulumi.String("ALIGN_MAX")
+		}
+
+		res.Policies[k], err = monitoring.NewAlertPolicy(ctx, k+"-alert", &monitoring.AlertPolicyArgs{
+			Project:     pulumi.String(args.ProjectId),
+			DisplayName: pulumi.String(k),
+			Combiner:    pulumi.String("OR"),
+			Conditions: monitoring.AlertPolicyConditionArray{
+				monitoring.AlertPolicyConditionArgs{
+					DisplayName: pulumi.String(fmt.Sprintf("%s %s above %v", r.Function+r.Queue, r.Metric, r.Threshold)),
+					ConditionThreshold: monitoring.AlertPolicyConditionConditionThresholdArgs{
+						Filter:         filter,
+						Comparison:     pulumi.String("COMPARISON_GT"),
+						ThresholdValue: pulumi.Float64(threshold),
+						Duration:       pulumi.String(fmt.Sprintf("%ds", r.MinutesOrDefault()*60)),
+						Aggregations:   monitoring.AlertPolicyConditionConditionThresholdAggregationArray{aggregation},
+					},
+				},
+			},
+			NotificationChannels: pulumi.StringArray{res.Channel.Name},
+		}, opts...)
+		if err != nil {
+			return nil, errors.WithMessage(err, "alert policy "+k)
+		}
+	}
+
+	if args.Alerts.Uptime != nil {
+		if err := res.newUptimeChecks(ctx, args, opts...); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":    pulumi.String(res.Name),
+		"channel": res.Channel.Name,
+	})
+}
@@ -0,0 +1,37 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+var _ common.StateArchiver = &gcpProvider{}
+
+// ArchiveState writes the snapshot to the Cloud Storage bucket. Objects are retained by the retention policy
+// of the bucket, so retainUntil is not set on them.
+func (g *gcpProvider) ArchiveState(bucket, key string, body []byte, retainUntil time.Time) error {
+	if err := g.setToken(); err != nil {
+		return err
+	}
+
+	b := &gcsBuckets{g: g, names: map[string]string{bucket: bucket}}
+	return b.Write(bucket, key, bytes.NewReader(body))
+}
@@ -0,0 +1,133 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/cloudscheduler"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/projects"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/serviceaccount"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/storage"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+type FirestoreBackupArgs struct {
+	Location  string
+	ProjectId string
+	Backups   *stack.Backups
+}
+
+type FirestoreBackup struct {
+	pulumi.ResourceState
+
+	Name    string
+	Bucket  *storage.Bucket
+	Account *serviceaccount.Account
+	Job     *cloudscheduler.Job
+}
+
+// newFirestoreBackup schedules exports of the firestore database to a bucket, exports older than the
+// retention are deleted by the bucket lifecycle.
+func newFirestoreBackup(ctx *pulumi.Context, name string, args *FirestoreBackupArgs, opts ...pulumi.ResourceOption) (*FirestoreBackup, error) {
+	res := &FirestoreBackup{Name: name}
+	err := ctx.RegisterComponentResource("nitric:backup:FirestoreExport", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	res.Bucket, err = storage.NewBucket(ctx, name, &storage.BucketArgs{
+		Location: pulumi.String(args.Location),
+		Project:  pulumi.String(args.ProjectId),
+		Labels:   common.Tags(ctx, name),
+		LifecycleRules: storage.BucketLifecycleRuleArray{
+			storage.BucketLifecycleRuleArgs{
+				Action: storage.BucketLifecycleRuleActionArgs{
+					Type: pulumi.String("Delete"),
+				},
+				Condition: storage.BucketLifecycleRuleConditionArgs{
+					Age: pulumi.Int(args.Backups.RetentionDaysOrDefault()),
+				},
+			},
+		},
+	}, append(opts, pulumi.Protect(true))...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "backup bucket")
+	}
+
+	res.Account, err = serviceaccount.NewAccount(ctx, name+"-acct", &serviceaccount.AccountArgs{
+		AccountId: pulumi.String(name + "-acct"),
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "backup serviceaccount")
+	}
+
+	_, err = projects.NewIAMMember(ctx, name+"-export-admin", &projects.IAMMemberArgs{
+		Project: pulumi.String(args.ProjectId),
+		Member:  pulumi.Sprintf("serviceAccount:%s", res.Account.Email),
+		Role:    pulumi.String("roles/datastore.importExportAdmin"),
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "backup export permission")
+	}
+
+	_, err = storage.NewBucketIAMMember(ctx, name+"-bucket-writer", &storage.BucketIAMMemberArgs{
+		Bucket: res.Bucket.Name,
+		Member: pulumi.Sprintf("serviceAccount:%s", res.Account.Email),
+		Role:   pulumi.String("roles/storage.admin"),
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "backup bucket permission")
+	}
+
+	body := res.Bucket.Name.ApplyT(func(bucket string) (string, error) {
+		b, err := json.Marshal(map[string]string{"outputUriPrefix": "gs://" + bucket})
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(b), nil
+	}).(pulumi.StringOutput)
+
+	res.Job, err = cloudscheduler.NewJob(ctx, name, &cloudscheduler.JobArgs{
+		TimeZone: pulumi.String("UTC"),
+		Schedule: pulumi.String(args.Backups.ScheduleOrDefault()),
+		HttpTarget: cloudscheduler.JobHttpTargetArgs{
+			Uri:        pulumi.String(fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default):exportDocuments", args.ProjectId)),
+			HttpMethod: pulumi.String("POST"),
+			Body:       body,
+			OauthToken: cloudscheduler.JobHttpTargetOauthTokenArgs{
+				ServiceAccountEmail: res.Account.Email,
+			},
+		},
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "backup schedule")
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":   pulumi.String(res.Name),
+		"bucket": res.Bucket.Name,
+	})
+}
@@ -0,0 +1,173 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+type gcsBuckets struct {
+	g     *gcpProvider
+	names map[string]string
+}
+
+var (
+	_ common.BucketStorer = &gcpProvider{}
+	_ types.BucketStore   = &gcsBuckets{}
+)
+
+type gcsObjects struct {
+	Items []struct {
+		Name    string    `json:"name"`
+		Size    string    `json:"size"`
+		Updated time.Time `json:"updated"`
+	} `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+func (g *gcpProvider) Buckets(names map[string]string) (types.BucketStore, error) {
+	if err := g.setToken(); err != nil {
+		return nil, err
+	}
+	return &gcsBuckets{g: g, names: names}, nil
+}
+
+func (b *gcsBuckets) name(bucket string) (string, error) {
+	n, ok := b.names[bucket]
+	if !ok {
+		return "", fmt.Errorf("bucket %s not found, has it been deployed?", bucket)
+	}
+	return n, nil
+}
+
+// do calls the Cloud Storage JSON API, returning the response body when it succeeds.
+func (b *gcsBuckets) do(method, u string, body io.Reader) (io.ReadCloser, error) {
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	b.g.token.SetAuthHeader(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("cloud storage %s failed with %s: %s", method, resp.Status, msg)
+	}
+	return resp.Body, nil
+}
+
+func (b *gcsBuckets) List(bucket, prefix string) ([]*types.BucketFile, error) {
+	name, err := b.name(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []*types.BucketFile{}
+	pageToken := ""
+	for {
+		q := url.Values{"prefix": {prefix}}
+		if pageToken != "" {
+			q.Set("pageToken", pageToken)
+		}
+
+		body, err := b.do(http.MethodGet, fmt.Sprintf("https://storage.googleapis.com/storage/v1/b/%s/o?%s", name, q.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+		objects := &gcsObjects{}
+		err = json.NewDecoder(body).Decode(objects)
+		body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, o := range objects.Items {
+			size, _ := strconv.ParseInt(o.Size, 10, 64)
+			files = append(files, &types.BucketFile{Key: o.Name, Size: size, LastModified: o.Updated})
+		}
+
+		if objects.NextPageToken == "" {
+			return files, nil
+		}
+		pageToken = objects.NextPageToken
+	}
+}
+
+func (b *gcsBuckets) Read(bucket, key string, w io.Writer) error {
+	name, err := b.name(bucket)
+	if err != nil {
+		return err
+	}
+
+	body, err := b.do(http.MethodGet, fmt.Sprintf("https://storage.googleapis.com/storage/v1/b/%s/o/%s?alt=media", name, url.PathEscape(key)), nil)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	_, err = io.Copy(w, body)
+	return err
+}
+
+func (b *gcsBuckets) Write(bucket, key string, r io.ReadSeeker) error {
+	name, err := b.name(bucket)
+	if err != nil {
+		return err
+	}
+
+	q := url.Values{"uploadType": {"media"}, "name": {key}}
+	body, err := b.do(http.MethodPost, fmt.Sprintf("https://storage.googleapis.com/upload/storage/v1/b/%s/o?%s", name, q.Encode()), r)
+	if err != nil {
+		return err
+	}
+	return body.Close()
+}
+
+func (b *gcsBuckets) Empty(bucket string) (int, error) {
+	name, err := b.name(bucket)
+	if err != nil {
+		return 0, err
+	}
+
+	files, err := b.List(bucket, "")
+	if err != nil {
+		return 0, err
+	}
+
+	for i, f := range files {
+		body, err := b.do(http.MethodDelete, fmt.Sprintf("https://storage.googleapis.com/storage/v1/b/%s/o/%s", name, url.PathEscape(f.Key)), nil)
+		if err != nil {
+			return i, err
+		}
+		body.Close()
+	}
+	return len(files), nil
+}
@@ -0,0 +1,128 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/billing"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/monitoring"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+type BudgetArgs struct {
+	ProjectId      string
+	ProjectNumber  string
+	BillingAccount string
+	Budget         *stack.Budget
+}
+
+type Budget struct {
+	pulumi.ResourceState
+
+	Name     string
+	Channels []*monitoring.NotificationChannel
+	Budget   *billing.Budget
+}
+
+// budgetAmount splits the amount into the whole units and nanos of the budget's money.
+func budgetAmount(amount float64) (string, int) {
+	units, frac := math.Modf(amount)
+	return fmt.Sprint(int64(units)), int(math.Round(frac * 1e9))
+}
+
+// newBudget creates a monthly cost budget of the project's resources labelled with the stack, notifying
+// the emails and webhook through notification channels.
+func newBudget(ctx *pulumi.Context, name string, args *BudgetArgs, opts ...pulumi.ResourceOption) (*Budget, error) {
+	res := &Budget{Name: name, Channels: []*monitoring.NotificationChannel{}}
+	err := ctx.RegisterComponentResource("nitric:budget:GCPBudget", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	channels := pulumi.StringArray{}
+	for i, email := range args.Budget.Emails {
+		ch, err := monitoring.NewNotificationChannel(ctx, fmt.Sprintf("%s-email%d", name, i), &monitoring.NotificationChannelArgs{
+			Project:     pulumi.String(args.ProjectId),
+			DisplayName: pulumi.String(ctx.Stack() + " budget"),
+			Type:        pulumi.String("email"),
+			Labels:      pulumi.StringMap{"email_address": pulumi.String(email)},
+		}, opts...)
+		if err != nil {
+			return nil, errors.WithMessage(err, "budget notification channel")
+		}
+		res.Channels = append(res.Channels, ch)
+		channels = append(channels, ch.Name)
+	}
+
+	if args.Budget.Webhook != "" {
+		ch, err := monitoring.NewNotificationChannel(ctx, name+"-webhook", &monitoring.NotificationChannelArgs{
+			Project:     pulumi.String(args.ProjectId),
+			DisplayName: pulumi.String(ctx.Stack() + " budget"),
+			Type:        pulumi.String("webhook_tokenauth"),
+			Labels:      pulumi.StringMap{"url": pulumi.String(args.Budget.Webhook)},
+		}, opts...)
+		if err != nil {
+			return nil, errors.WithMessage(err, "budget notification channel")
+		}
+		res.Channels = append(res.Channels, ch)
+		channels = append(channels, ch.Name)
+	}
+
+	rules := billing.BudgetThresholdRuleArray{}
+	for _, t := range args.Budget.ThresholdsOrDefault() {
+		rules = append(rules, billing.BudgetThresholdRuleArgs{
+			ThresholdPercent: pulumi.Float64(t / 100),
+		})
+	}
+
+	units, nanos := budgetAmount(args.Budget.Amount)
+
+	res.Budget, err = billing.NewBudget(ctx, name, &billing.BudgetArgs{
+		BillingAccount: pulumi.String(args.BillingAccount),
+		DisplayName:    pulumi.Sprintf("%s-%s", ctx.Project(), ctx.Stack()),
+		Amount: billing.BudgetAmountArgs{
+			SpecifiedAmount: billing.BudgetAmountSpecifiedAmountArgs{
+				Units: pulumi.String(units),
+				Nanos: pulumi.Int(nanos),
+			},
+		},
+		BudgetFilter: billing.BudgetBudgetFilterArgs{
+			Projects: pulumi.StringArray{pulumi.String("projects/" + args.ProjectNumber)},
+			Labels:   pulumi.StringMap{"x-nitric-stack": pulumi.String(ctx.Stack())},
+		},
+		ThresholdRules: rules,
+		AllUpdatesRule: billing.BudgetAllUpdatesRuleArgs{
+			MonitoringNotificationChannels: channels,
+			DisableDefaultIamRecipients:    pulumi.Bool(true),
+		},
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "budget")
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":   pulumi.String(res.Name),
+		"budget": res.Budget.Name,
+	})
+}
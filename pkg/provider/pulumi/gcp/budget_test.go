@@ -0,0 +1,37 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import "testing"
+
+func Test_budgetAmount(t *testing.T) {
+	tests := []struct {
+		amount    float64
+		wantUnits string
+		wantNanos int
+	}{
+		{amount: 100, wantUnits: "100"},
+		{amount: 99.5, wantUnits: "99", wantNanos: 500000000},
+		{amount: 0.01, wantUnits: "0", wantNanos: 10000000},
+	}
+	for _, tt := range tests {
+		units, nanos := budgetAmount(tt.amount)
+		if units != tt.wantUnits || nanos != tt.wantNanos {
+			t.Errorf("budgetAmount(%v) = %v, %v, want %v, %v", tt.amount, units, nanos, tt.wantUnits, tt.wantNanos)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/redis"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/vpcaccess"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+// cacheConnectorRange is the range used by the serverless VPC connector, it must not overlap
+// other subnets in the default network.
+const cacheConnectorRange = "10.8.0.0/28"
+
+type MemorystoreCacheArgs struct {
+	Location  string
+	ProjectId string
+	Cache     project.Cache
+}
+
+type MemorystoreCache struct {
+	pulumi.ResourceState
+
+	Name     string
+	Instance *redis.Instance
+	URL      pulumi.StringOutput
+}
+
+// newMemorystoreCache creates a Memorystore redis instance on the default network, cloud run
+// services reach it through the cache VPC connector.
+func newMemorystoreCache(ctx *pulumi.Context, name string, args *MemorystoreCacheArgs, opts ...pulumi.ResourceOption) (*MemorystoreCache, error) {
+	res := &MemorystoreCache{Name: name}
+	err := ctx.RegisterComponentResource("nitric:cache:Memorystore", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	res.Instance, err = redis.NewInstance(ctx, name, &redis.InstanceArgs{
+		Region:       pulumi.String(args.Location),
+		Project:      pulumi.String(args.ProjectId),
+		Tier:         pulumi.String("BASIC"),
+		MemorySizeGb: pulumi.Int(1),
+		RedisVersion: pulumi.String("REDIS_6_X"),
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "redis instance")
+	}
+
+	res.URL = pulumi.Sprintf("redis://%s:%d", res.Instance.Host, res.Instance.Port)
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":     pulumi.String(res.Name),
+		"instance": res.Instance.Name,
+	})
+}
+
+// newCacheConnector creates the serverless VPC connector shared by all cloud run services using a cache.
+func newCacheConnector(ctx *pulumi.Context, name string, location string, projectId string, opts ...pulumi.ResourceOption) (*vpcaccess.Connector, error) {
+	return vpcaccess.NewConnector(ctx, name, &vpcaccess.ConnectorArgs{
+		Name:        pulumi.String(name),
+		Region:      pulumi.String(location),
+		Project:     pulumi.String(projectId),
+		Network:     pulumi.String("default"),
+		IpCidrRange: pulumi.String(cacheConnectorRange),
+	}, opts...)
+}
+
+// cacheEnv are the env vars used to connect to a cache.
+func cacheEnv(name string) []types.EnvVar {
+	return []types.EnvVar{
+		{Name: project.CacheEnvPrefix(name) + "_URL", Value: types.DeployTimeValue, Source: types.EnvSourceProvider},
+	}
+}
@@ -18,15 +18,20 @@ package gcp
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/cloudrun"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/projects"
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/pubsub"
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/serviceaccount"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/vpcaccess"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
 	"github.com/nitrictech/cli/pkg/project"
 	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/stack"
 	"github.com/nitrictech/cli/pkg/utils"
 )
 
@@ -38,6 +43,10 @@ type CloudRunnerArgs struct {
 	EnvMap         map[string]string
 	ServiceAccount *serviceaccount.Account
 	Topics         map[string]*pubsub.Topic
+	Databases      map[string]*CloudSqlDatabase
+	Caches         map[string]*MemorystoreCache
+	CacheConnector *vpcaccess.Connector
+	Emails         map[string]project.Email
 }
 
 type CloudRunner struct {
@@ -57,56 +66,136 @@ func (g *gcpProvider) newCloudRunner(ctx *pulumi.Context, name string, args *Clo
 		return nil, err
 	}
 
-	env := cloudrun.ServiceTemplateSpecContainerEnvArray{
-		cloudrun.ServiceTemplateSpecContainerEnvArgs{
-			Name:  pulumi.String("MIN_WORKERS"),
-			Value: pulumi.String(fmt.Sprint(args.Compute.Workers())),
-		},
-	}
-	for k, v := range args.EnvMap {
+	env := cloudrun.ServiceTemplateSpecContainerEnvArray{}
+	provided := append(cloudRunEnv(ctx.Stack(), args.Compute), common.ObservabilityEnv(g.sc.Observability, name, g.sc.Name)...)
+	provided = append(provided, common.UnitEnv(args.Compute)...)
+	for _, e := range common.MergeEnv(provided, args.EnvMap) {
 		env = append(env, cloudrun.ServiceTemplateSpecContainerEnvArgs{
-			Name:  pulumi.String(k),
-			Value: pulumi.String(v),
+			Name:  pulumi.String(e.Name),
+			Value: pulumi.String(e.Value),
 		})
 	}
 
+	annotations := pulumi.StringMap{}
+	if len(args.Databases) > 0 {
+		instances := pulumi.StringArray{}
+		for k, db := range args.Databases {
+			prefix := project.DatabaseEnvPrefix(k)
+			env = append(env,
+				cloudrun.ServiceTemplateSpecContainerEnvArgs{
+					Name:  pulumi.String(prefix + "_URL"),
+					Value: db.URL,
+				},
+				cloudrun.ServiceTemplateSpecContainerEnvArgs{
+					Name:  pulumi.String(prefix + "_NAME"),
+					Value: pulumi.String(project.DatabaseName(k)),
+				})
+			instances = append(instances, db.Instance.ConnectionName)
+		}
+		annotations["run.googleapis.com/cloudsql-instances"] = instances.ToStringArrayOutput().ApplyT(func(names []string) string {
+			return strings.Join(names, ",")
+		}).(pulumi.StringOutput)
+
+		_, err = projects.NewIAMMember(ctx, name+"-cloudsql-client", &projects.IAMMemberArgs{
+			Project: pulumi.String(args.ProjectId),
+			Member:  pulumi.Sprintf("serviceAccount:%s", args.ServiceAccount.Email),
+			Role:    pulumi.String("roles/cloudsql.client"),
+		}, append(opts, pulumi.Parent(res))...)
+		if err != nil {
+			return nil, errors.WithMessage(err, "cloudsql client "+name)
+		}
+	}
+
+	if len(args.Caches) > 0 {
+		for k, c := range args.Caches {
+			env = append(env, cloudrun.ServiceTemplateSpecContainerEnvArgs{
+				Name:  pulumi.String(project.CacheEnvPrefix(k) + "_URL"),
+				Value: c.URL,
+			})
+		}
+		annotations["run.googleapis.com/vpc-access-connector"] = args.CacheConnector.Name
+		annotations["run.googleapis.com/vpc-access-egress"] = pulumi.String("private-ranges-only")
+	}
+
+	for k, e := range args.Emails {
+		emailVars, err := emailContainerEnv(ctx, k, e, args.ProjectId, args.ServiceAccount, append(opts, pulumi.Parent(res))...)
+		if err != nil {
+			return nil, errors.WithMessage(err, "email "+k)
+		}
+		env = append(env, emailVars...)
+	}
+
 	// Deploy the func
-	memory := common.IntValueOrDefault(args.Compute.Unit().Memory, 512)
-	maxScale := common.IntValueOrDefault(args.Compute.Unit().MaxScale, 10)
+	// the tmp filesystem is held in memory on cloud run, so the memory limit makes room for it
+	memory := args.Compute.Unit().MemoryOrDefault(512) + args.Compute.Unit().TmpSize
+	limits := pulumi.StringMap{"memory": pulumi.Sprintf("%dMi", memory)}
+	if class := args.Compute.Unit().ComputeClass(); class != nil {
+		limits["cpu"] = pulumi.Sprintf("%dm", int(class.Cpu*1000))
+	}
+	maxScale := cloudRunMaxScale(g.sc, args.Compute)
 	minScale := common.IntValueOrDefault(args.Compute.Unit().MinScale, 0)
+	annotations["autoscaling.knative.dev/minScale"] = pulumi.Sprintf("%d", minScale)
+	annotations["autoscaling.knative.dev/maxScale"] = pulumi.Sprintf("%d", maxScale)
+	if args.Compute.Unit().AlwaysOn {
+		// services keep working between requests, so their CPU is always allocated
+		annotations["run.googleapis.com/cpu-throttling"] = pulumi.String("false")
+	}
+	containers := cloudrun.ServiceTemplateSpecContainerArray{
+		cloudrun.ServiceTemplateSpecContainerArgs{
+			Name:  pulumi.String("function"),
+			Envs:  env,
+			Image: args.Image.DockerImage.ImageName, // TODO check
+			Ports: cloudrun.ServiceTemplateSpecContainerPortArray{
+				cloudrun.ServiceTemplateSpecContainerPortArgs{
+					Name:          portName(args.Compute),
+					ContainerPort: pulumi.Int(common.IntValueOrDefault(args.Compute.Unit().Port, 9001)),
+				},
+			},
+			Resources: cloudrun.ServiceTemplateSpecContainerResourcesArgs{
+				Limits: limits,
+			},
+		},
+	}
+	if g.sc.Observability != nil {
+		collector, err := g.collectorSidecar()
+		if err != nil {
+			return nil, err
+		}
+		containers = append(containers, collector)
+		annotations["run.googleapis.com/container-dependencies"] = pulumi.String(`{"function":["collector"]}`)
+	}
+
+	spec := cloudrun.ServiceTemplateSpecArgs{
+		ServiceAccountName: args.ServiceAccount.Email,
+		Containers:         containers,
+	}
+	// the service must not time out before its apis' gateways, it defaults to 5 minutes
+	if t := common.ApiTimeout(g.proj, g.sc, name); t > 300 {
+		spec.TimeoutSeconds = pulumi.Int(t)
+	}
+
 	res.Service, err = cloudrun.NewService(ctx, name, &cloudrun.ServiceArgs{
 		Location: pulumi.String(g.sc.Region),
 		Project:  pulumi.String(args.ProjectId),
 		Template: cloudrun.ServiceTemplateArgs{
 			Metadata: cloudrun.ServiceTemplateMetadataArgs{
-				Annotations: pulumi.StringMap{
-					"autoscaling.knative.dev/minScale": pulumi.Sprintf("%d", minScale),
-					"autoscaling.knative.dev/maxScale": pulumi.Sprintf("%d", maxScale),
-				},
-			},
-			Spec: cloudrun.ServiceTemplateSpecArgs{
-				ServiceAccountName: args.ServiceAccount.Email,
-				Containers: cloudrun.ServiceTemplateSpecContainerArray{
-					cloudrun.ServiceTemplateSpecContainerArgs{
-						Envs:  env,
-						Image: args.Image.DockerImage.ImageName, // TODO check
-						Ports: cloudrun.ServiceTemplateSpecContainerPortArray{
-							cloudrun.ServiceTemplateSpecContainerPortArgs{
-								ContainerPort: pulumi.Int(9001),
-							},
-						},
-						Resources: cloudrun.ServiceTemplateSpecContainerResourcesArgs{
-							Limits: pulumi.StringMap{"memory": pulumi.Sprintf("%dMi", memory)},
-						},
-					},
-				},
+				Annotations: annotations,
 			},
+			Spec: spec,
 		},
 	}, append(opts, pulumi.Parent(res))...)
 	if err != nil {
 		return nil, errors.WithMessage(err, "iam member "+name)
 	}
 
+	// nitric stack update --verify reads the readiness of the service with its name
+	ctx.Export("function:"+name, res.Service.Name)
+
+	if minScale > 0 {
+		// nitric stack sleep and wake find the service with its name
+		ctx.Export("service:"+name, res.Service.Name)
+	}
+
 	res.Url = res.Service.Statuses.ApplyT(func(ss []cloudrun.ServiceStatus) (string, error) {
 		if len(ss) == 0 {
 			return "", errors.New("serviceStatus is empty")
@@ -143,7 +232,7 @@ func (g *gcpProvider) newCloudRunner(ctx *pulumi.Context, name string, args *Clo
 			if ok {
 				_, err = pubsub.NewSubscription(ctx, name+"-"+t+"-sub", &pubsub.SubscriptionArgs{
 					Topic:              topic.Name,
-					AckDeadlineSeconds: pulumi.Int(0),
+					AckDeadlineSeconds: pulumi.Int(g.sc.Subscription(name, t).VisibilityTimeout),
 					RetryPolicy: pubsub.SubscriptionRetryPolicyArgs{
 						MinimumBackoff: pulumi.String("15s"),
 						MaximumBackoff: pulumi.String("600s"),
@@ -168,3 +257,95 @@ func (g *gcpProvider) newCloudRunner(ctx *pulumi.Context, name string, args *Clo
 		"url":     res.Url,
 	})
 }
+
+// collectorSidecar runs the OpenTelemetry collector next to the function, it exports what the
+// function sends to localhost.
+func (g *gcpProvider) collectorSidecar() (cloudrun.ServiceTemplateSpecContainerArgs, error) {
+	config, err := common.CollectorConfig(g.sc.Observability, g.envMap)
+	if err != nil {
+		return cloudrun.ServiceTemplateSpecContainerArgs{}, errors.WithMessage(err, "collector config")
+	}
+
+	return cloudrun.ServiceTemplateSpecContainerArgs{
+		Name:  pulumi.String("collector"),
+		Image: pulumi.String(common.CollectorImage),
+		Args:  pulumi.StringArray{pulumi.String("--config=env:" + common.CollectorConfigEnv)},
+		Envs: cloudrun.ServiceTemplateSpecContainerEnvArray{
+			cloudrun.ServiceTemplateSpecContainerEnvArgs{
+				Name:  pulumi.String(common.CollectorConfigEnv),
+				Value: pulumi.String(config),
+			},
+		},
+	}, nil
+}
+
+func (g *gcpProvider) Env(c project.Compute) []types.EnvVar {
+	env := append(cloudRunEnv(g.proj.Name+"-"+g.sc.Name, c), common.ObservabilityEnv(g.sc.Observability, c.Unit().Name, g.sc.Name)...)
+	for _, k := range g.proj.DatabasesFor(c.Unit().Name) {
+		env = append(env, databaseEnv(k)...)
+	}
+	for _, k := range g.proj.CachesFor(c.Unit().Name) {
+		env = append(env, cacheEnv(k)...)
+	}
+	for _, k := range g.proj.EmailsFor(c.Unit().Name) {
+		env = append(env, emailEnv(k, g.proj.Emails[k])...)
+	}
+	env = append(env, common.UnitEnv(c)...)
+	return common.MergeEnv(env, g.envMap)
+}
+
+// cloudRunReservedEnv are set by Cloud Run.
+var cloudRunReservedEnv = []string{"PORT", "K_SERVICE", "K_REVISION", "K_CONFIGURATION"}
+
+// cloudRunEnv is the environment injected into every service, before the user's env files are applied.
+func cloudRunEnv(stackName string, c project.Compute) []types.EnvVar {
+	return append(common.DeploymentEnv(stackName, c),
+		types.EnvVar{Name: "MIN_WORKERS", Value: fmt.Sprint(c.Workers()), Source: types.EnvSourceProvider},
+	)
+}
+
+// cloudRunMaxScale is the most instances of the compute unit cloud run will start.
+func cloudRunMaxScale(sc *stack.Config, c project.Compute) int {
+	maxScale := common.IntValueOrDefault(c.Unit().MaxScale, 10)
+	if maxConcurrency := sc.MaxConcurrency(c.Unit().Name); maxConcurrency > 0 && maxConcurrency < maxScale {
+		// pub/sub pushes to as many instances as there are, so the instances limit the concurrency
+		maxScale = maxConcurrency
+	}
+	return maxScale
+}
+
+// portName is h2c for compute units serving grpc or h2c, cloud run sends HTTP/1.1 requests to other ports.
+func portName(c project.Compute) pulumi.StringPtrInput {
+	if !c.Unit().Http2() {
+		return nil
+	}
+	return pulumi.StringPtr("h2c")
+}
+
+// maxCloudRunMemory is the most memory in MB a cloud run instance can have.
+const maxCloudRunMemory = 32768
+
+// validateTmpSizes checks the memory of each compute unit, with its tmp size added, fits in a cloud run instance.
+func validateTmpSizes(proj *project.Project) error {
+	for _, c := range proj.Computes() {
+		u := c.Unit()
+		if u.TmpSize > 0 && u.MemoryOrDefault(512)+u.TmpSize > maxCloudRunMemory {
+			return fmt.Errorf("the memory and tmpSize of %s add up to more than the %dMB a cloud run instance can have, /tmp is held in memory", u.Name, maxCloudRunMemory)
+		}
+	}
+	return nil
+}
+
+// validateSubscriptions checks the delivery settings are supported by pub/sub push subscriptions, which
+// push one message per request.
+func validateSubscriptions(subs stack.Subscriptions) error {
+	for _, s := range common.SortedSubscriptions(subs) {
+		if s.BatchSize > 0 {
+			return utils.NewNotSupportedErr(fmt.Sprintf("the batchSize of subscription %s:%s is not supported on gcp", s.Function, s.Topic))
+		}
+		if s.VisibilityTimeout != 0 && (s.VisibilityTimeout < 10 || s.VisibilityTimeout > 600) {
+			return fmt.Errorf("the visibilityTimeout of subscription %s:%s must be between 10 and 600 seconds", s.Function, s.Topic)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi-docker/sdk/v3/go/docker"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"golang.org/x/oauth2"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/test"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func TestConformance(t *testing.T) {
+	g := New(test.Project(), &stack.Config{Name: "deploy", Provider: stack.Gcp, Region: "mock"}, map[string]string{}).(*gcpProvider)
+	g.token = &oauth2.Token{AccessToken: "testing-token"}
+	g.projectId = "test-project-id"
+	g.projectNumber = "test-project-number"
+	g.images[test.Function] = &common.Image{
+		DockerImage: &docker.Image{
+			ImageName: pulumi.Sprintf("docker.io/nitrictech/%s:latest", test.Function),
+		},
+	}
+	defer g.CleanUp()
+
+	test.Run(t, test.Conformance{
+		Deploy: g.Deploy,
+		Mocks: &test.Mocks{
+			Outputs: func(typ, name string) map[string]interface{} {
+				if typ == "gcp:cloudrun/service:Service" {
+					return map[string]interface{}{"statuses": []map[string]string{{"url": "test/url"}}}
+				}
+				return nil
+			},
+		},
+		Types: map[string]string{
+			"topic":  "gcp:pubsub/topic:Topic",
+			"bucket": "gcp:storage/bucket:Bucket",
+			"queue":  "gcp:pubsub/topic:Topic",
+			"secret": "gcp:secretmanager/secret:Secret",
+		},
+		Compute: "gcp:cloudrun/service:Service",
+		Env: func(r test.Resource) map[string]interface{} {
+			env := map[string]interface{}{}
+			template, _ := r.Inputs["template"].(map[string]interface{})
+			spec, _ := template["spec"].(map[string]interface{})
+			containers, _ := spec["containers"].([]interface{})
+			for _, c := range containers {
+				container, _ := c.(map[string]interface{})
+				envs, _ := container["envs"].([]interface{})
+				for _, e := range envs {
+					if e, ok := e.(map[string]interface{}); ok {
+						if name, ok := e["name"].(string); ok {
+							env[name] = e["value"]
+						}
+					}
+				}
+			}
+			return env
+		},
+		Grants: func(r test.Resource, function, bucket string) bool {
+			return r.Type == "gcp:storage/bucketIAMMember:BucketIAMMember" && r.Name == function+"-"+bucket
+		},
+	})
+}
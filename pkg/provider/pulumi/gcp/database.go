@@ -0,0 +1,111 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/sql"
+	"github.com/pulumi/pulumi-random/sdk/v4/go/random"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+const databaseUser = "nitric"
+
+type CloudSqlDatabaseArgs struct {
+	Location  string
+	ProjectId string
+	Database  project.Database
+}
+
+type CloudSqlDatabase struct {
+	pulumi.ResourceState
+
+	Name     string
+	Instance *sql.DatabaseInstance
+	URL      pulumi.StringOutput
+}
+
+// newCloudSqlDatabase creates a Cloud SQL postgres instance, cloud run services connect to it
+// through the Cloud SQL unix socket.
+func newCloudSqlDatabase(ctx *pulumi.Context, name string, args *CloudSqlDatabaseArgs, opts ...pulumi.ResourceOption) (*CloudSqlDatabase, error) {
+	res := &CloudSqlDatabase{Name: name}
+	err := ctx.RegisterComponentResource("nitric:database:CloudSql", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	password, err := random.NewRandomPassword(ctx, name+"-password", &random.RandomPasswordArgs{
+		Length:  pulumi.Int(32),
+		Special: pulumi.Bool(false),
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "database password")
+	}
+
+	res.Instance, err = sql.NewDatabaseInstance(ctx, project.DatabaseName(name), &sql.DatabaseInstanceArgs{
+		DatabaseVersion:    pulumi.String("POSTGRES_14"),
+		Region:             pulumi.String(args.Location),
+		Project:            pulumi.String(args.ProjectId),
+		DeletionProtection: pulumi.Bool(false),
+		Settings: sql.DatabaseInstanceSettingsArgs{
+			Tier: pulumi.String("db-f1-micro"),
+		},
+	}, append(opts, pulumi.Protect(true))...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "database instance")
+	}
+
+	db, err := sql.NewDatabase(ctx, name, &sql.DatabaseArgs{
+		Instance: res.Instance.Name,
+		Project:  pulumi.String(args.ProjectId),
+		Name:     pulumi.String(project.DatabaseName(name)),
+	}, append(opts, pulumi.Protect(true))...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "database")
+	}
+
+	user, err := sql.NewUser(ctx, name+"-user", &sql.UserArgs{
+		Instance: res.Instance.Name,
+		Project:  pulumi.String(args.ProjectId),
+		Name:     pulumi.String(databaseUser),
+		Password: password.Result,
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "database user")
+	}
+
+	res.URL = pulumi.Sprintf("postgres://%s:%s@/%s?host=/cloudsql/%s", user.Name, password.Result, db.Name, res.Instance.ConnectionName)
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":     pulumi.String(res.Name),
+		"instance": res.Instance.ConnectionName,
+	})
+}
+
+// databaseEnv are the env vars used to connect to a database.
+func databaseEnv(name string) []types.EnvVar {
+	prefix := project.DatabaseEnvPrefix(name)
+	return []types.EnvVar{
+		{Name: prefix + "_URL", Value: types.DeployTimeValue, Source: types.EnvSourceProvider},
+		{Name: prefix + "_NAME", Value: project.DatabaseName(name), Source: types.EnvSourceProvider},
+	}
+}
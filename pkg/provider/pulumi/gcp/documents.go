@@ -0,0 +1,242 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+type firestoreDocuments struct {
+	g *gcpProvider
+}
+
+var (
+	_ common.DocumentStorer = &gcpProvider{}
+	_ types.DocumentStore   = &firestoreDocuments{}
+)
+
+type firestoreDocument struct {
+	Name   string                 `json:"name,omitempty"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+func (g *gcpProvider) Documents(stackName string) (types.DocumentStore, error) {
+	if err := g.setToken(); err != nil {
+		return nil, err
+	}
+	return &firestoreDocuments{g: g}, nil
+}
+
+// toFirestoreValue converts a JSON value to a Firestore REST API value.
+func toFirestoreValue(v interface{}) map[string]interface{} {
+	switch t := v.(type) {
+	case nil:
+		return map[string]interface{}{"nullValue": nil}
+	case bool:
+		return map[string]interface{}{"booleanValue": t}
+	case float64:
+		if t == float64(int64(t)) {
+			return map[string]interface{}{"integerValue": strconv.FormatInt(int64(t), 10)}
+		}
+		return map[string]interface{}{"doubleValue": t}
+	case string:
+		return map[string]interface{}{"stringValue": t}
+	case []interface{}:
+		values := []interface{}{}
+		for _, e := range t {
+			values = append(values, toFirestoreValue(e))
+		}
+		return map[string]interface{}{"arrayValue": map[string]interface{}{"values": values}}
+	case map[string]interface{}:
+		return map[string]interface{}{"mapValue": map[string]interface{}{"fields": toFirestoreFields(t)}}
+	default:
+		return map[string]interface{}{"stringValue": fmt.Sprint(t)}
+	}
+}
+
+func toFirestoreFields(content map[string]interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for k, v := range content {
+		fields[k] = toFirestoreValue(v)
+	}
+	return fields
+}
+
+// fromFirestoreValue converts a Firestore REST API value to a JSON value.
+func fromFirestoreValue(v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for k, val := range m {
+		switch k {
+		case "integerValue":
+			i, err := strconv.ParseInt(fmt.Sprint(val), 10, 64)
+			if err != nil {
+				return val
+			}
+			return float64(i)
+		case "arrayValue":
+			values := []interface{}{}
+			if av, ok := val.(map[string]interface{}); ok {
+				if elems, ok := av["values"].([]interface{}); ok {
+					for _, e := range elems {
+						values = append(values, fromFirestoreValue(e))
+					}
+				}
+			}
+			return values
+		case "mapValue":
+			fields := map[string]interface{}{}
+			if mv, ok := val.(map[string]interface{}); ok {
+				fields, _ = mv["fields"].(map[string]interface{})
+			}
+			return fromFirestoreFields(fields)
+		default:
+			// stringValue, booleanValue, doubleValue, nullValue, timestampValue, bytesValue and referenceValue
+			return val
+		}
+	}
+	return nil
+}
+
+func fromFirestoreFields(fields map[string]interface{}) map[string]interface{} {
+	content := map[string]interface{}{}
+	for k, v := range fields {
+		content[k] = fromFirestoreValue(v)
+	}
+	return content
+}
+
+func (d *firestoreDocument) toDocument() *types.Document {
+	return &types.Document{
+		Id:      d.Name[strings.LastIndex(d.Name, "/")+1:],
+		Content: fromFirestoreFields(d.Fields),
+	}
+}
+
+// request calls the Firestore REST API of the stack's project, path is relative to its documents.
+func (f *firestoreDocuments) request(method, path string, in interface{}, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	url := fmt.Sprintf("https://firestore.googleapis.com/v1/projects/%s/databases/(default)/documents%s", f.g.gcpProject, path)
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	f.g.token.SetAuthHeader(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s not found in project %s", strings.TrimPrefix(path, "/"), f.g.gcpProject)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("firestore %s failed with %s: %s", method, resp.Status, respBody)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
+
+func (f *firestoreDocuments) Get(collection, id string) (*types.Document, error) {
+	doc := &firestoreDocument{}
+	if err := f.request(http.MethodGet, "/"+collection+"/"+id, nil, doc); err != nil {
+		return nil, err
+	}
+	return doc.toDocument(), nil
+}
+
+func (f *firestoreDocuments) Put(collection, id string, content map[string]interface{}) error {
+	// a patch without an update mask replaces the whole document, creating it if needed
+	return f.request(http.MethodPatch, "/"+collection+"/"+id, &firestoreDocument{Fields: toFirestoreFields(content)}, nil)
+}
+
+func (f *firestoreDocuments) Delete(collection, id string) error {
+	return f.request(http.MethodDelete, "/"+collection+"/"+id, nil, nil)
+}
+
+func (f *firestoreDocuments) Query(collection string, where map[string]interface{}, limit int) ([]*types.Document, error) {
+	filters := []interface{}{}
+	for k, v := range where {
+		filters = append(filters, map[string]interface{}{
+			"fieldFilter": map[string]interface{}{
+				"field": map[string]interface{}{"fieldPath": k},
+				"op":    "EQUAL",
+				"value": toFirestoreValue(v),
+			},
+		})
+	}
+
+	query := map[string]interface{}{
+		"from": []interface{}{map[string]interface{}{"collectionId": collection}},
+	}
+	if len(filters) > 0 {
+		query["where"] = map[string]interface{}{
+			"compositeFilter": map[string]interface{}{"op": "AND", "filters": filters},
+		}
+	}
+	if limit > 0 {
+		query["limit"] = limit
+	}
+
+	results := []struct {
+		Document *firestoreDocument `json:"document"`
+	}{}
+	err := f.request(http.MethodPost, ":runQuery", map[string]interface{}{"structuredQuery": query}, &results)
+	if err != nil {
+		return nil, errors.WithMessage(err, "query "+collection)
+	}
+
+	docs := []*types.Document{}
+	for _, r := range results {
+		// the results include one without a document when the query has read all of them
+		if r.Document != nil {
+			docs = append(docs, r.Document.toDocument())
+		}
+	}
+	return docs, nil
+}
@@ -0,0 +1,55 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFirestoreFields(t *testing.T) {
+	content := map[string]interface{}{
+		"name":    "pen",
+		"count":   float64(3),
+		"price":   1.5,
+		"instock": true,
+		"notes":   nil,
+		"tags":    []interface{}{"office", float64(7)},
+		"dims":    map[string]interface{}{"length": float64(14)},
+	}
+
+	fields := toFirestoreFields(content)
+	if got := fields["count"]; !cmp.Equal(got, map[string]interface{}{"integerValue": "3"}) {
+		t.Errorf("count = %v, want an integerValue", got)
+	}
+
+	// values are read back from the API's JSON
+	b, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := map[string]interface{}{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := fromFirestoreFields(decoded); !cmp.Equal(content, got) {
+		t.Error(cmp.Diff(content, got))
+	}
+}
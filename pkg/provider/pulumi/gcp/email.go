@@ -0,0 +1,79 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/cloudrun"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/secretmanager"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/serviceaccount"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+// validateEmails checks every email names the secret holding its SendGrid API key, GCP has no
+// email service of its own.
+func validateEmails(emails map[string]project.Email) error {
+	for k, e := range emails {
+		if e.ApiKeySecret == "" {
+			return fmt.Errorf("email %s requires \"apiKeySecret\", the Secret Manager secret holding a SendGrid API key", k)
+		}
+	}
+	return nil
+}
+
+// emailContainerEnv reads the SendGrid API key from its secret, the service account is allowed to access it.
+func emailContainerEnv(ctx *pulumi.Context, name string, e project.Email, projectId string, sa *serviceaccount.Account, opts ...pulumi.ResourceOption) (cloudrun.ServiceTemplateSpecContainerEnvArray, error) {
+	_, err := secretmanager.NewSecretIamMember(ctx, name+"-sendgrid-key", &secretmanager.SecretIamMemberArgs{
+		Project:  pulumi.String(projectId),
+		SecretId: pulumi.String(e.ApiKeySecret),
+		Member:   pulumi.Sprintf("serviceAccount:%s", sa.Email),
+		Role:     pulumi.String("roles/secretmanager.secretAccessor"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := project.EmailEnvPrefix(name)
+	return cloudrun.ServiceTemplateSpecContainerEnvArray{
+		cloudrun.ServiceTemplateSpecContainerEnvArgs{
+			Name:  pulumi.String(prefix + "_SENDER"),
+			Value: pulumi.String(e.Sender),
+		},
+		cloudrun.ServiceTemplateSpecContainerEnvArgs{
+			Name: pulumi.String(prefix + "_SENDGRID_API_KEY"),
+			ValueFrom: cloudrun.ServiceTemplateSpecContainerEnvValueFromArgs{
+				SecretKeyRef: cloudrun.ServiceTemplateSpecContainerEnvValueFromSecretKeyRefArgs{
+					Name: pulumi.String(e.ApiKeySecret),
+					Key:  pulumi.String("latest"),
+				},
+			},
+		},
+	}, nil
+}
+
+// emailEnv are the env vars used to send email.
+func emailEnv(name string, e project.Email) []types.EnvVar {
+	prefix := project.EmailEnvPrefix(name)
+	return []types.EnvVar{
+		{Name: prefix + "_SENDER", Value: e.Sender, Source: types.EnvSourceProvider},
+		{Name: prefix + "_SENDGRID_API_KEY", Value: e.ApiKeySecret, Source: types.EnvSourceSecret},
+	}
+}
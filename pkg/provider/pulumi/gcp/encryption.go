@@ -0,0 +1,73 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+var _ common.Encrypter = &gcpProvider{}
+
+// kms calls the encrypt or decrypt method of the stack's encryptionKey, a Cloud KMS key named
+// projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>.
+func (g *gcpProvider) kms(method string, req, resp interface{}) error {
+	if err := g.setToken(); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	body, err := g.runDo(http.MethodPost, "https://cloudkms.googleapis.com/v1/"+g.sc.EncryptionKey+":"+method, bytes.NewReader(b))
+	if err != nil {
+		return errors.WithMessage(err, "kms "+method)
+	}
+	defer body.Close()
+
+	return json.NewDecoder(body).Decode(resp)
+}
+
+func (g *gcpProvider) Encrypt(plaintext string) (string, error) {
+	resp := struct {
+		Ciphertext string `json:"ciphertext"`
+	}{}
+	err := g.kms("encrypt", map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString([]byte(plaintext)),
+	}, &resp)
+	return resp.Ciphertext, err
+}
+
+func (g *gcpProvider) Decrypt(ciphertext string) (string, error) {
+	resp := struct {
+		Plaintext string `json:"plaintext"`
+	}{}
+	if err := g.kms("decrypt", map[string]string{"ciphertext": ciphertext}, &resp); err != nil {
+		return "", err
+	}
+
+	plaintext, err := base64.StdEncoding.DecodeString(resp.Plaintext)
+	return string(plaintext), err
+}
@@ -19,15 +19,22 @@ package gcp
 import (
 	"encoding/base64"
 	"fmt"
+	"math"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/apigateway"
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/cloudrun"
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/serviceaccount"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
 	"github.com/nitrictech/cli/pkg/utils"
 )
 
@@ -35,8 +42,17 @@ type ApiGatewayArgs struct {
 	ProjectId   pulumi.StringInput
 	OpenAPISpec *openapi2.T
 	Functions   map[string]*CloudRunner
+	// Limits are applied as per minute quotas
+	Limits *common.ApiLimits
+	// Jwt requires a bearer token on every operation, nil when the api is open
+	Jwt *stack.JwtAuth
+	// Timeout is the deadline of the backends in seconds, the gateway's default when 0
+	Timeout int
 }
 
+// jwtScheme is the name of the security definition that validates the stack's JWTs.
+const jwtScheme = "nitric-jwt"
+
 type ApiGateway struct {
 	pulumi.ResourceState
 
@@ -108,15 +124,22 @@ func newApiGateway(ctx *pulumi.Context, name string, args *ApiGatewayArgs, opts
 		}
 
 		for k, p := range args.OpenAPISpec.Paths {
-			p.Get = gcpOperation(p.Get, naps)
-			p.Post = gcpOperation(p.Post, naps)
-			p.Patch = gcpOperation(p.Patch, naps)
-			p.Put = gcpOperation(p.Put, naps)
-			p.Delete = gcpOperation(p.Delete, naps)
-			p.Options = gcpOperation(p.Options, naps)
+			p.Get = gcpOperation(p.Get, naps, args.Timeout)
+			p.Post = gcpOperation(p.Post, naps, args.Timeout)
+			p.Patch = gcpOperation(p.Patch, naps, args.Timeout)
+			p.Put = gcpOperation(p.Put, naps, args.Timeout)
+			p.Delete = gcpOperation(p.Delete, naps, args.Timeout)
+			p.Options = gcpOperation(p.Options, naps, args.Timeout)
 			args.OpenAPISpec.Paths[k] = p
 		}
 
+		if args.Limits != nil {
+			addQuotas(args.OpenAPISpec, args.Limits)
+		}
+		if args.Jwt != nil {
+			addJwt(args.OpenAPISpec, args.Jwt)
+		}
+
 		b, err := args.OpenAPISpec.MarshalJSON()
 		if err != nil {
 			return "", err
@@ -128,6 +151,10 @@ func newApiGateway(ctx *pulumi.Context, name string, args *ApiGatewayArgs, opts
 		return nil, err
 	}
 
+	if args.Limits != nil && args.Limits.RateLimited() {
+		_ = ctx.Log.Warn(fmt.Sprintf("api %s: API Gateway counts route rate limits for each API key, requests without a key are not limited", name), &pulumi.LogArgs{})
+	}
+
 	res.Api, err = apigateway.NewApi(ctx, name, &apigateway.ApiArgs{
 		ApiId: pulumi.String(name),
 	}, opts...)
@@ -215,7 +242,7 @@ func keepOperation(opExt map[string]interface{}) (string, bool) {
 	return name, true
 }
 
-func gcpOperation(op *openapi2.Operation, urls map[string]string) *openapi2.Operation {
+func gcpOperation(op *openapi2.Operation, urls map[string]string, timeout int) *openapi2.Operation {
 	if op == nil {
 		return nil
 	}
@@ -234,9 +261,114 @@ func gcpOperation(op *openapi2.Operation, urls map[string]string) *openapi2.Oper
 		}
 	}
 
-	op.Extensions["x-google-backend"] = map[string]string{
+	backend := map[string]interface{}{
 		"address":          urls[name],
 		"path_translation": "APPEND_PATH_TO_ADDRESS",
 	}
+	if timeout > 0 {
+		backend["deadline"] = float64(timeout)
+	}
+	op.Extensions["x-google-backend"] = backend
 	return op
 }
+
+// addQuotas limits the rate of each route with a per minute quota on a metric of its requests.
+func addQuotas(doc *openapi2.T, limits *common.ApiLimits) {
+	metrics := []map[string]interface{}{}
+	quotas := []map[string]interface{}{}
+	for path, pi := range doc.Paths {
+		for method, op := range pi.Operations() {
+			l := limits.Route(method, path)
+			if l == nil || l.Rate == 0 {
+				continue
+			}
+
+			metric := op.OperationID + "-requests"
+			metrics = append(metrics, map[string]interface{}{
+				"name":        metric,
+				"displayName": common.RouteKey(method, path),
+				"valueType":   "INT64",
+				"metricKind":  "DELTA",
+			})
+			quotas = append(quotas, map[string]interface{}{
+				"name":   op.OperationID + "-limit",
+				"metric": metric,
+				"unit":   "1/min/{project}",
+				"values": map[string]int{"STANDARD": int(math.Ceil(l.Rate * 60))},
+			})
+
+			if op.Extensions == nil {
+				op.Extensions = map[string]interface{}{}
+			}
+			op.Extensions["x-google-quota"] = map[string]interface{}{
+				"metricCosts": map[string]int{metric: 1},
+			}
+		}
+	}
+	if len(metrics) == 0 {
+		return
+	}
+
+	// paths are a map, sort so the document doesn't change between deployments
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i]["name"].(string) < metrics[j]["name"].(string) })
+	sort.Slice(quotas, func(i, j int) bool { return quotas[i]["name"].(string) < quotas[j]["name"].(string) })
+
+	if doc.Extensions == nil {
+		doc.Extensions = map[string]interface{}{}
+	}
+	doc.Extensions["x-google-management"] = map[string]interface{}{
+		"metrics": metrics,
+		"quota": map[string]interface{}{
+			"limits": quotas,
+		},
+	}
+}
+
+// addJwt requires a bearer token from the issuer on every operation, API Gateway validates the token
+// before the request reaches the function.
+func addJwt(doc *openapi2.T, jwt *stack.JwtAuth) {
+	ext := map[string]interface{}{
+		"x-google-issuer": jwt.Issuer,
+	}
+	if jwt.JwksUri != "" {
+		ext["x-google-jwks_uri"] = jwt.JwksUri
+	}
+	if len(jwt.Audiences) > 0 {
+		ext["x-google-audiences"] = strings.Join(jwt.Audiences, ",")
+	}
+
+	if doc.SecurityDefinitions == nil {
+		doc.SecurityDefinitions = map[string]*openapi2.SecurityScheme{}
+	}
+	doc.SecurityDefinitions[jwtScheme] = &openapi2.SecurityScheme{
+		ExtensionProps: openapi3.ExtensionProps{Extensions: ext},
+		Type:           "oauth2",
+		Flow:           "implicit",
+	}
+	doc.Security = append(doc.Security, map[string][]string{jwtScheme: {}})
+}
+
+// validateJwt checks each api's tokens have an issuer, and that their key set is an https URL.
+func validateJwt(jwts map[string]stack.JwtAuth) error {
+	for api, jwt := range jwts {
+		if jwt.Issuer == "" {
+			return fmt.Errorf("the jwt of api %s requires an issuer", api)
+		}
+		if jwt.JwksUri == "" {
+			continue
+		}
+		if u, err := url.Parse(jwt.JwksUri); err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("the jwt of api %s has a jwksUri of %s, it should be an https URL", api, jwt.JwksUri)
+		}
+	}
+	return nil
+}
+
+// stackJwt returns the stack's jwt of the api, nil when it has none.
+func stackJwt(sc *stack.Config, api string) *stack.JwtAuth {
+	jwt, ok := sc.Jwt[api]
+	if !ok {
+		return nil
+	}
+	return &jwt
+}
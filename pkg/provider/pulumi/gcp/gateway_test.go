@@ -0,0 +1,66 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func Test_addJwt(t *testing.T) {
+	doc := &openapi2.T{}
+	addJwt(doc, &stack.JwtAuth{
+		Issuer:    "https://example.auth0.com/",
+		Audiences: []string{"orders", "payments"},
+	})
+
+	want := map[string]interface{}{
+		"x-google-issuer":    "https://example.auth0.com/",
+		"x-google-audiences": "orders,payments",
+	}
+	s, ok := doc.SecurityDefinitions[jwtScheme]
+	if !ok || s.Type != "oauth2" || !reflect.DeepEqual(s.Extensions, want) {
+		t.Errorf("addJwt() security definition = %+v, want the issuer and audiences %v", s, want)
+	}
+	if len(doc.Security) != 1 || doc.Security[0][jwtScheme] == nil {
+		t.Errorf("addJwt() security = %v, want %s required", doc.Security, jwtScheme)
+	}
+}
+
+func Test_validateJwt(t *testing.T) {
+	tests := []struct {
+		name    string
+		jwt     stack.JwtAuth
+		wantErr bool
+	}{
+		{name: "issuer", jwt: stack.JwtAuth{Issuer: "https://example.auth0.com/"}},
+		{name: "key set", jwt: stack.JwtAuth{Issuer: "orders@example.iam.gserviceaccount.com", JwksUri: "https://www.googleapis.com/service_accounts/v1/jwk/orders@example.iam.gserviceaccount.com"}},
+		{name: "no issuer", jwt: stack.JwtAuth{Audiences: []string{"orders"}}, wantErr: true},
+		{name: "http key set", jwt: stack.JwtAuth{Issuer: "https://example.auth0.com/", JwksUri: "http://example.auth0.com/.well-known/jwks.json"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateJwt(map[string]stack.JwtAuth{"main": tt.jwt}); (err != nil) != tt.wantErr {
+				t.Errorf("validateJwt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
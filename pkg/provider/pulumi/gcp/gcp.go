@@ -40,6 +40,7 @@ import (
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/secretmanager"
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/serviceaccount"
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/storage"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/vpcaccess"
 	"github.com/pulumi/pulumi-random/sdk/v4/go/random"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
@@ -60,10 +61,15 @@ type gcpProvider struct {
 	envMap     map[string]string
 	tmpDir     string
 	gcpProject string
+	// names are the names of the deployed resources when the stack uses stable naming
+	names *common.StableNames
+	// deployedImages are the images pushed by the last update, keyed by source image, when they are reused
+	deployedImages map[string]string
 
-	token         *oauth2.Token
-	projectNumber string
-	projectId     string
+	token          *oauth2.Token
+	projectNumber  string
+	projectId      string
+	billingAccount string
 
 	buckets            map[string]*storage.Bucket
 	topics             map[string]*pubsub.Topic
@@ -72,6 +78,9 @@ type gcpProvider struct {
 	images             map[string]*common.Image
 	secrets            map[string]*secretmanager.Secret
 	cloudRunners       map[string]*CloudRunner
+	databases          map[string]*CloudSqlDatabase
+	caches             map[string]*MemorystoreCache
+	cacheConnector     *vpcaccess.Connector
 }
 
 //go:embed pulumi-gcp-version.txt
@@ -91,6 +100,9 @@ func New(s *project.Project, t *stack.Config, envMap map[string]string) common.P
 		queueSubscriptions: map[string]*pubsub.Subscription{},
 		images:             map[string]*common.Image{},
 		cloudRunners:       map[string]*CloudRunner{},
+		databases:          map[string]*CloudSqlDatabase{},
+		caches:             map[string]*MemorystoreCache{},
+		secrets:            map[string]*secretmanager.Secret{},
 	}
 }
 
@@ -174,6 +186,48 @@ func (a *gcpProvider) Ask() (*stack.Config, error) {
 	return sc, nil
 }
 
+func (g *gcpProvider) SetStableNames(names *common.StableNames) {
+	g.names = names
+}
+
+var _ common.ImageReuser = &gcpProvider{}
+
+func (g *gcpProvider) ReuseImages(images map[string]string) {
+	g.deployedImages = images
+}
+
+func (g *gcpProvider) Capabilities() common.Capabilities {
+	return common.CapabilitiesExcept(
+		common.CapabilityCdn,
+		// cloud armor attaches to load balancers, there is none in front of API Gateway
+		common.CapabilityWaf,
+		// cloud run, API Gateway and storage are served with google's own TLS settings
+		common.CapabilityTls,
+		common.CapabilityWorkflows,
+		common.CapabilityDapr,
+		common.CapabilityCosmos,
+		common.CapabilityLayers,
+		common.CapabilityBucketEvents,
+		// the v1 API only mounts secrets, GCS and filestore volumes need the second generation environment
+		common.CapabilityVolumes,
+		// cloud run jobs have no resource in the version of the gcp provider we use
+		common.CapabilityJobs,
+		// cloud run has no scheduled scaling, use nitric stack sleep and wake instead
+		common.CapabilitySleep,
+		// topics are always pub/sub topics
+		common.CapabilityEventBridge,
+		// API Gateway quotas are counted per minute and per consumer project, not per scheme
+		common.CapabilityQuotas,
+		common.CapabilitySchemeLimits,
+		// API Gateway and cloud run accept bodies up to their 32MB request limit
+		common.CapabilityApiBodyLimits,
+		// existing topics are only read from event grid so far
+		common.CapabilityExistingTopics,
+		// API Gateway routes every operation to a backend, it can't return a response itself
+		common.CapabilityApiMocks,
+	)
+}
+
 func (g *gcpProvider) Validate() error {
 	errList := utils.NewErrorList()
 
@@ -189,6 +243,20 @@ func (g *gcpProvider) Validate() error {
 		g.gcpProject = proj.(string)
 	}
 
+	errList.Add(common.ValidateEnv(g.sc.Provider, cloudRunReservedEnv, g.envMap))
+	errList.Add(common.ValidateObservability(g.sc.Observability))
+	errList.Add(common.ValidateAlerts(g.sc.Alerts, g.proj))
+	errList.Add(common.ValidateBudget(g.sc.Budget))
+	errList.Add(common.ValidateSleep(g.sc.Sleep))
+	// cloud scheduler pings on a cron schedule, which can't repeat a minute interval over an hour
+	errList.Add(common.ValidateKeepWarm(g.sc.KeepWarm, g.proj, 59))
+	errList.Add(validateEmails(g.proj.Emails))
+	errList.Add(validateSubscriptions(g.sc.Subscriptions))
+	errList.Add(validateTmpSizes(g.proj))
+	errList.Add(validateJwt(g.sc.Jwt))
+	// cloud run requests time out after an hour at most
+	errList.Add(common.ValidateApiRequestLimits(g.sc.Provider, g.sc.ApiRequests, 3600, 0))
+
 	return errList.Aggregate()
 }
 
@@ -257,6 +325,14 @@ func (g *gcpProvider) setToken() error {
 	return nil
 }
 
+var _ common.CredentialRefresher = &gcpProvider{}
+
+// RefreshCredentials fetches a new access token, they expire after an hour which a long build can outlast.
+func (g *gcpProvider) RefreshCredentials() error {
+	g.token = nil
+	return g.setToken()
+}
+
 func (g *gcpProvider) Deploy(ctx *pulumi.Context) error {
 	var err error
 	g.tmpDir, err = ioutil.TempDir("", ctx.Stack()+"-*")
@@ -268,6 +344,10 @@ func (g *gcpProvider) Deploy(ctx *pulumi.Context) error {
 		return err
 	}
 
+	if err := common.ValidateSubscriptions(g.sc.Subscriptions, g.proj); err != nil {
+		return err
+	}
+
 	if g.projectId == "" {
 		project, err := organizations.LookupProject(ctx, &organizations.LookupProjectArgs{
 			ProjectId: &g.gcpProject,
@@ -277,6 +357,7 @@ func (g *gcpProvider) Deploy(ctx *pulumi.Context) error {
 		}
 		g.projectId = *project.ProjectId
 		g.projectNumber = project.Number
+		g.billingAccount = project.BillingAccount
 	}
 
 	nitricProj, err := newProject(ctx, "project", &ProjectArgs{
@@ -291,13 +372,27 @@ func (g *gcpProvider) Deploy(ctx *pulumi.Context) error {
 
 	for key := range g.proj.Buckets {
 		g.buckets[key], err = storage.NewBucket(ctx, key, &storage.BucketArgs{
+			// bucket names are global, so the suffix keeps them unique
+			Name:     g.names.Name(ctx, "gcp:storage/bucket:Bucket", key, strings.ToLower(utils.StringTrunc(key, 55))+"-"+common.StableSuffix(ctx, key)),
 			Location: pulumi.String(g.sc.Region),
 			Project:  pulumi.String(g.projectId),
 			Labels:   common.Tags(ctx, key),
-		}, defaultResourceOptions)
+		}, defaultResourceOptions, pulumi.Protect(true))
 		if err != nil {
 			return err
 		}
+		ctx.Export("bucket:"+key, g.buckets[key].Name)
+	}
+
+	if g.sc.Backups != nil && len(g.proj.Collections) > 0 {
+		_, err = newFirestoreBackup(ctx, "firestore-backup", &FirestoreBackupArgs{
+			Location:  g.sc.Region,
+			ProjectId: g.projectId,
+			Backups:   g.sc.Backups,
+		}, defaultResourceOptions)
+		if err != nil {
+			return errors.WithMessage(err, "firestore backup")
+		}
 	}
 
 	for key := range g.proj.Topics {
@@ -364,7 +459,7 @@ func (g *gcpProvider) Deploy(ctx *pulumi.Context) error {
 			Project:  pulumi.String(g.projectId),
 			SecretId: secId,
 			Labels:   common.Tags(ctx, name),
-		})
+		}, pulumi.Protect(true))
 
 		if err != nil {
 			return err
@@ -403,6 +498,35 @@ func (g *gcpProvider) Deploy(ctx *pulumi.Context) error {
 		return errors.WithMessage(err, "base customRole")
 	}
 
+	for k, db := range g.proj.Databases {
+		g.databases[k], err = newCloudSqlDatabase(ctx, k, &CloudSqlDatabaseArgs{
+			Location:  g.sc.Region,
+			ProjectId: g.projectId,
+			Database:  db,
+		}, defaultResourceOptions)
+		if err != nil {
+			return errors.WithMessage(err, "database "+k)
+		}
+	}
+
+	for k, c := range g.proj.Caches {
+		g.caches[k], err = newMemorystoreCache(ctx, k, &MemorystoreCacheArgs{
+			Location:  g.sc.Region,
+			ProjectId: g.projectId,
+			Cache:     c,
+		}, defaultResourceOptions)
+		if err != nil {
+			return errors.WithMessage(err, "cache "+k)
+		}
+	}
+
+	if len(g.caches) > 0 {
+		g.cacheConnector, err = newCacheConnector(ctx, "nitric-cache", g.sc.Region, g.projectId, defaultResourceOptions)
+		if err != nil {
+			return errors.WithMessage(err, "cache vpc connector")
+		}
+	}
+
 	for _, c := range g.proj.Computes() {
 		if _, ok := g.images[c.Unit().Name]; !ok {
 			g.images[c.Unit().Name], err = common.NewImage(ctx, c.Unit().Name+"Image", &common.ImageArgs{
@@ -413,6 +537,7 @@ func (g *gcpProvider) Deploy(ctx *pulumi.Context) error {
 				Password:        pulumi.String(g.token.AccessToken),
 				Server:          pulumi.String("https://gcr.io"),
 				TempDir:         g.tmpDir,
+				Deployed:        g.deployedImages[c.ImageTagName(g.proj, g.sc.Provider)],
 			}, defaultResourceOptions)
 			if err != nil {
 				return errors.WithMessage(err, "function image tag "+c.Unit().Name)
@@ -438,7 +563,24 @@ func (g *gcpProvider) Deploy(ctx *pulumi.Context) error {
 			return errors.WithMessage(err, "function project membership "+c.Unit().Name)
 		}
 
+		databases := map[string]*CloudSqlDatabase{}
+		for _, k := range g.proj.DatabasesFor(c.Unit().Name) {
+			databases[k] = g.databases[k]
+		}
+		caches := map[string]*MemorystoreCache{}
+		for _, k := range g.proj.CachesFor(c.Unit().Name) {
+			caches[k] = g.caches[k]
+		}
+		emails := map[string]project.Email{}
+		for _, k := range g.proj.EmailsFor(c.Unit().Name) {
+			emails[k] = g.proj.Emails[k]
+		}
+
 		g.cloudRunners[c.Unit().Name], err = g.newCloudRunner(ctx, c.Unit().Name, &CloudRunnerArgs{
+			Databases:      databases,
+			Caches:         caches,
+			Emails:         emails,
+			CacheConnector: g.cacheConnector,
 			Location:       pulumi.String(g.sc.Region),
 			ProjectId:      g.projectId,
 			Topics:         g.topics,
@@ -454,21 +596,92 @@ func (g *gcpProvider) Deploy(ctx *pulumi.Context) error {
 		principalMap[v1.ResourceType_Function][c.Unit().Name] = sa
 	}
 
+	for k := range g.sc.Jwt {
+		if _, ok := g.proj.ApiDocs[k]; !ok {
+			return fmt.Errorf("jwt configured for api %s, but the api does not exist", k)
+		}
+	}
+
+	gateways := map[string]*ApiGateway{}
 	for k, doc := range g.proj.ApiDocs {
 		v2doc, err := openapi2conv.FromV3(doc)
 		if err != nil {
 			return err
 		}
-		_, err = newApiGateway(ctx, k, &ApiGatewayArgs{
+		limits, err := common.ApiRateLimits(doc, common.StackThrottling(g.sc, k))
+		if err != nil {
+			return err
+		}
+		gateways[k], err = newApiGateway(ctx, k, &ApiGatewayArgs{
 			Functions:   g.cloudRunners,
 			OpenAPISpec: v2doc,
 			ProjectId:   pulumi.String(g.projectId),
+			Limits:      limits,
+			Jwt:         stackJwt(g.sc, k),
+			Timeout:     g.sc.ApiRequests[k].Timeout,
 		}, defaultResourceOptions)
 		if err != nil {
 			return err
 		}
 	}
 
+	if len(g.proj.Sites) > 0 {
+		endpoints := map[string]pulumi.StringInput{}
+		for k, gw := range gateways {
+			endpoints[k] = pulumi.Sprintf("https://%s", gw.Gateway.DefaultHostname)
+		}
+		for k, site := range g.proj.Sites {
+			_, err = newSite(ctx, k, &SiteArgs{
+				ProjectId:  pulumi.String(g.projectId),
+				Location:   pulumi.String(g.sc.Region),
+				ProjectDir: g.proj.Dir,
+				Site:       site,
+				Env:        common.SiteEnv(endpoints),
+			}, defaultResourceOptions)
+			if err != nil {
+				return errors.WithMessage(err, "site "+k)
+			}
+		}
+	}
+
+	for k, minutes := range g.sc.KeepWarm {
+		runner, ok := g.cloudRunners[k]
+		if !ok {
+			return fmt.Errorf("keepWarm configured for function %s, but the function does not exist", k)
+		}
+		if _, err = newKeepWarm(ctx, k+"-keepwarm", &KeepWarmArgs{Minutes: minutes, Runner: runner}, defaultResourceOptions); err != nil {
+			return errors.WithMessage(err, "keepWarm "+k)
+		}
+	}
+
+	if g.sc.Alerts != nil && (len(g.sc.Alerts.Rules) > 0 || g.sc.Alerts.Uptime != nil) {
+		_, err = newAlerts(ctx, "alerts", &AlertsArgs{
+			ProjectId:          g.projectId,
+			Alerts:             g.sc.Alerts,
+			CloudRunners:       g.cloudRunners,
+			QueueSubscriptions: g.queueSubscriptions,
+			Gateways:           gateways,
+		}, defaultResourceOptions)
+		if err != nil {
+			return errors.WithMessage(err, "alerts")
+		}
+	}
+
+	if g.sc.Budget != nil {
+		if g.billingAccount == "" {
+			return fmt.Errorf("the budget requires the project %s to have a billing account", g.projectId)
+		}
+		_, err = newBudget(ctx, "budget", &BudgetArgs{
+			ProjectId:      g.projectId,
+			ProjectNumber:  g.projectNumber,
+			BillingAccount: g.billingAccount,
+			Budget:         g.sc.Budget,
+		}, defaultResourceOptions)
+		if err != nil {
+			return errors.WithMessage(err, "budget")
+		}
+	}
+
 	uniquePolicies := map[string]*v1.PolicyResource{}
 	for _, p := range g.proj.Policies {
 		if len(p.Actions) == 0 {
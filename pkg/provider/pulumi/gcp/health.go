@@ -0,0 +1,84 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+var _ common.HealthChecker = &gcpProvider{}
+
+// runServiceStatus is the part of a Cloud Run service that describes whether it is serving.
+type runServiceStatus struct {
+	Status struct {
+		LatestCreatedRevisionName string `json:"latestCreatedRevisionName"`
+		LatestReadyRevisionName   string `json:"latestReadyRevisionName"`
+		Conditions                []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+// Health reads the readiness of each cloud run service, which is ready once its latest revision is serving.
+func (g *gcpProvider) Health(ids map[string]string) ([]types.FunctionHealth, error) {
+	if err := g.setToken(); err != nil {
+		return nil, err
+	}
+
+	return common.CheckHealth(ids, func(name, id string) types.FunctionHealth {
+		u := fmt.Sprintf("https://%s-run.googleapis.com/apis/serving.knative.dev/v1/namespaces/%s/services/%s", g.sc.Region, g.gcpProject, id)
+		body, err := g.runDo(http.MethodGet, u, nil)
+		if err != nil {
+			return types.FunctionHealth{Function: name, Reason: err.Error()}
+		}
+		defer body.Close()
+
+		svc := &runServiceStatus{}
+		if err := json.NewDecoder(body).Decode(svc); err != nil {
+			return types.FunctionHealth{Function: name, Reason: err.Error()}
+		}
+		return serviceHealth(name, svc)
+	}), nil
+}
+
+// serviceHealth is healthy when the service is Ready with the revision that was last created.
+func serviceHealth(name string, svc *runServiceStatus) types.FunctionHealth {
+	for _, c := range svc.Status.Conditions {
+		if c.Type != "Ready" {
+			continue
+		}
+		if c.Status != "True" {
+			reason := c.Message
+			if reason == "" {
+				reason = "the service is not ready"
+			}
+			return types.FunctionHealth{Function: name, Reason: reason}
+		}
+		if svc.Status.LatestReadyRevisionName != svc.Status.LatestCreatedRevisionName {
+			return types.FunctionHealth{Function: name, Reason: "revision " + svc.Status.LatestCreatedRevisionName + " is not ready"}
+		}
+		return types.FunctionHealth{Function: name, Healthy: true}
+	}
+	return types.FunctionHealth{Function: name, Reason: "the service has no Ready condition"}
+}
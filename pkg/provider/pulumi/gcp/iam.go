@@ -0,0 +1,79 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+var _ common.Bootstrapper = &gcpProvider{}
+
+// deployerRoles are the predefined roles used to create, update and destroy the resources of a stack.
+var deployerRoles = []string{
+	"roles/run.admin",
+	"roles/pubsub.admin",
+	"roles/storage.admin",
+	"roles/datastore.owner",
+	"roles/secretmanager.admin",
+	"roles/iam.serviceAccountAdmin",
+	"roles/iam.serviceAccountUser",
+	"roles/iam.roleAdmin",
+	"roles/resourcemanager.projectIamAdmin",
+	"roles/apigateway.admin",
+	"roles/cloudscheduler.admin",
+	"roles/cloudsql.admin",
+	"roles/redis.admin",
+	"roles/vpcaccess.admin",
+	"roles/monitoring.admin",
+	"roles/workflows.admin",
+	"roles/serviceusage.serviceUsageAdmin",
+	// decrypts the encrypted values of the stack
+	"roles/cloudkms.cryptoKeyDecrypter",
+}
+
+// DeployerRole returns the roles to grant a service account that deploys the stacks.
+func (g *gcpProvider) DeployerRole(name string) (*types.DeployerRole, error) {
+	roles, err := json.MarshalIndent(map[string]interface{}{
+		"roles": deployerRoles,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	account := fmt.Sprintf("%s@<project-id>.iam.gserviceaccount.com", name)
+	instructions := []string{
+		fmt.Sprintf("gcloud iam service-accounts create %s --project <project-id>", name),
+	}
+	for _, r := range deployerRoles {
+		instructions = append(instructions, fmt.Sprintf("gcloud projects add-iam-policy-binding <project-id> --member serviceAccount:%s --role %s", account, r))
+	}
+
+	return &types.DeployerRole{
+		Name:         name,
+		Policy:       string(roles),
+		Instructions: instructions,
+	}, nil
+}
+
+func (g *gcpProvider) CreateDeployerRole(role *types.DeployerRole) error {
+	return utils.NewNotSupportedErr("gcp deployer service accounts are created with gcloud, run 'nitric iam bootstrap -t gcp' without --create")
+}
@@ -0,0 +1,79 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+func TestDeployerRole(t *testing.T) {
+	g := New(&project.Project{Name: "shop"}, &stack.Config{Name: "deployer", Provider: stack.Gcp}, map[string]string{}).(*gcpProvider)
+
+	role, err := g.DeployerRole("shop-deployer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy := struct {
+		Roles []string
+	}{}
+	if err := json.Unmarshal([]byte(role.Policy), &policy); err != nil {
+		t.Fatalf("policy is not json: %v", err)
+	}
+	roles := map[string]bool{}
+	for _, r := range policy.Roles {
+		roles[r] = true
+	}
+	tests := []struct {
+		role string
+		want bool
+	}{
+		{role: "roles/run.admin", want: true},
+		{role: "roles/iam.serviceAccountUser", want: true},
+		{role: "roles/cloudkms.cryptoKeyDecrypter", want: true},
+		{role: "roles/owner", want: false},
+		{role: "roles/editor", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			if roles[tt.role] != tt.want {
+				t.Errorf("policy grants %s = %v, want %v", tt.role, roles[tt.role], tt.want)
+			}
+		})
+	}
+
+	// the service account is created, then each role is granted to it
+	if len(role.Instructions) != len(policy.Roles)+1 {
+		t.Fatalf("instructions = %v, want one for the account and each of the %d roles", role.Instructions, len(policy.Roles))
+	}
+	for i, r := range policy.Roles {
+		grant := role.Instructions[i+1]
+		if !strings.Contains(grant, "serviceAccount:shop-deployer@") || !strings.HasSuffix(grant, "--role "+r) {
+			t.Errorf("instruction %q does not grant %s to the service account", grant, r)
+		}
+	}
+
+	if _, ok := g.CreateDeployerRole(role).(*utils.NotSupportedError); !ok {
+		t.Error("CreateDeployerRole() expected a not supported error")
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/cloudrun"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/cloudscheduler"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/serviceaccount"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+type KeepWarmArgs struct {
+	Minutes int
+	Runner  *CloudRunner
+}
+
+type KeepWarm struct {
+	pulumi.ResourceState
+
+	Name    string
+	Account *serviceaccount.Account
+	Job     *cloudscheduler.Job
+}
+
+// newKeepWarm pings the cloud run service on a schedule so an instance is kept warm.
+func newKeepWarm(ctx *pulumi.Context, name string, args *KeepWarmArgs, opts ...pulumi.ResourceOption) (*KeepWarm, error) {
+	res := &KeepWarm{Name: name}
+	err := ctx.RegisterComponentResource("nitric:func:GCPKeepWarm", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	res.Account, err = serviceaccount.NewAccount(ctx, name+"-acct", &serviceaccount.AccountArgs{
+		// accountId accepts a max of 30 chars, limit our generated name to this length
+		AccountId: pulumi.String(utils.StringTrunc(name, 30-5) + "-acct"),
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "keepWarm serviceaccount")
+	}
+
+	_, err = cloudrun.NewIamMember(ctx, name+"-invoker", &cloudrun.IamMemberArgs{
+		Member:   pulumi.Sprintf("serviceAccount:%s", res.Account.Email),
+		Role:     pulumi.String("roles/run.invoker"),
+		Service:  args.Runner.Service.Name,
+		Location: args.Runner.Service.Location,
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "keepWarm invoker")
+	}
+
+	res.Job, err = cloudscheduler.NewJob(ctx, name, &cloudscheduler.JobArgs{
+		Schedule: pulumi.String(fmt.Sprintf("*/%d * * * *", args.Minutes)),
+		HttpTarget: cloudscheduler.JobHttpTargetArgs{
+			Uri:        args.Runner.Url,
+			HttpMethod: pulumi.String("GET"),
+			OidcToken: cloudscheduler.JobHttpTargetOidcTokenArgs{
+				ServiceAccountEmail: res.Account.Email,
+			},
+		},
+	}, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "keepWarm schedule")
+	}
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name": pulumi.String(res.Name),
+	})
+}
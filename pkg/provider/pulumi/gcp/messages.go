@@ -0,0 +1,103 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+var _ common.Messenger = &gcpProvider{}
+
+type pubsubMessage struct {
+	Data       []byte            `json:"data"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+type publishRequest struct {
+	Messages []pubsubMessage `json:"messages"`
+}
+
+type publishResponse struct {
+	MessageIds []string `json:"messageIds"`
+}
+
+// publish publishes the payload to a Pub/Sub topic of the project, topics and queues are both named after their nitric name.
+func (g *gcpProvider) publish(topic string, attributes map[string]string, payload map[string]interface{}) (string, error) {
+	if err := g.setToken(); err != nil {
+		return "", err
+	}
+
+	data, err := common.MessageBody(payload)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := json.Marshal(&publishRequest{Messages: []pubsubMessage{{Data: data, Attributes: attributes}}})
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("https://pubsub.googleapis.com/v1/projects/%s/topics/%s:publish", g.gcpProject, topic)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	g.token.SetAuthHeader(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", errors.WithMessage(err, "publish to "+topic)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("%s not found in project %s, has it been deployed?", topic, g.gcpProject)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("publish to %s failed with %s: %s", topic, resp.Status, respBody)
+	}
+
+	out := &publishResponse{}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return "", err
+	}
+	if len(out.MessageIds) == 0 {
+		return "", nil
+	}
+	return out.MessageIds[0], nil
+}
+
+func (g *gcpProvider) Publish(stackName, topic string, payload map[string]interface{}) (string, error) {
+	return g.publish(topic, map[string]string{"x-nitric-topic": topic}, payload)
+}
+
+func (g *gcpProvider) Send(stackName, queue string, payload map[string]interface{}) (string, error) {
+	return g.publish(queue, nil, payload)
+}
@@ -19,6 +19,7 @@ package gcp
 import (
 	"fmt"
 
+	"github.com/golangci/golangci-lint/pkg/sliceutil"
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/projects"
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/pubsub"
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/secretmanager"
@@ -27,6 +28,7 @@ import (
 	random "github.com/pulumi/pulumi-random/sdk/v4/go/random"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
 	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
 )
 
@@ -182,6 +184,23 @@ func actionsToGcpActions(actions []v1.Action) pulumi.StringArray {
 	return gcpActions
 }
 
+var _ common.PermissionMapper = &gcpProvider{}
+
+// Grants returns the permissions of the custom role bound to the resource, collections are granted on
+// the project with only the datastore permissions.
+func (g *gcpProvider) Grants(action v1.Action, resource *v1.Resource) []string {
+	if resource.Type != v1.ResourceType_Collection {
+		return gcpActionsMap[action]
+	}
+	grants := []string{}
+	for _, a := range gcpActionsMap[action] {
+		if sliceutil.Contains(getCollectionActions(), a) {
+			grants = append(grants, a+" (project)")
+		}
+	}
+	return grants
+}
+
 func newPolicy(ctx *pulumi.Context, name string, args *PolicyArgs, opts ...pulumi.ResourceOption) (*Policy, error) {
 	res := &Policy{Name: name, RolePolicies: make([]*projects.IAMMember, 0)}
 	err := ctx.RegisterComponentResource("nitric:func:GCPPolicy", name, res, opts...)
@@ -53,6 +53,16 @@ var (
 		"firestore.googleapis.com",
 		// Enable ApiGateway API
 		"apigateway.googleapis.com",
+		// Enable Cloud SQL Admin API
+		"sqladmin.googleapis.com",
+		// Enable Memorystore for Redis API
+		"redis.googleapis.com",
+		// Enable Serverless VPC Access API
+		"vpcaccess.googleapis.com",
+		// Enable Cloud Monitoring API for alerts
+		"monitoring.googleapis.com",
+		// Enable Cloud Billing Budget API for the budget
+		"billingbudgets.googleapis.com",
 	}
 )
 
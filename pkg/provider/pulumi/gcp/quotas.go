@@ -0,0 +1,130 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+// cloudRunCpuMetric is the quota of the vCPUs, in thousandths, the cloud run instances of a region can have.
+const cloudRunCpuMetric = "run.googleapis.com/cpu_allocation"
+
+var _ common.QuotaChecker = &gcpProvider{}
+
+type consumerQuotaMetrics struct {
+	Metrics []struct {
+		Metric              string `json:"metric"`
+		ConsumerQuotaLimits []struct {
+			QuotaBuckets []struct {
+				EffectiveLimit string            `json:"effectiveLimit"`
+				Dimensions     map[string]string `json:"dimensions"`
+			} `json:"quotaBuckets"`
+		} `json:"consumerQuotaLimits"`
+	} `json:"metrics"`
+}
+
+// Quotas reads the cloud run CPU quota of the stack's region, which the instances of every service
+// can use at once when scaled out.
+func (g *gcpProvider) Quotas() ([]types.Quota, error) {
+	needed := 0.0
+	for _, c := range g.proj.Computes() {
+		cpu := 1.0
+		if class := c.Unit().ComputeClass(); class != nil {
+			cpu = class.Cpu
+		}
+		needed += cpu * 1000 * float64(cloudRunMaxScale(g.sc, c))
+	}
+	if needed == 0 {
+		return []types.Quota{}, nil
+	}
+
+	if err := g.setToken(); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://serviceusage.googleapis.com/v1beta1/projects/%s/services/run.googleapis.com/consumerQuotaMetrics", g.gcpProject), nil)
+	if err != nil {
+		return nil, err
+	}
+	g.token.SetAuthHeader(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("reading the cloud run quotas failed with %s: %s", resp.Status, body)
+	}
+
+	metrics := &consumerQuotaMetrics{}
+	if err := json.Unmarshal(body, metrics); err != nil {
+		return nil, errors.WithMessage(err, "cloud run quotas")
+	}
+	limit, ok := regionLimit(metrics, cloudRunCpuMetric, g.sc.Region)
+	if !ok {
+		return []types.Quota{}, nil
+	}
+
+	return []types.Quota{{
+		Name:        "Cloud Run CPU allocation (milli vCPU) in " + g.sc.Region,
+		Limit:       limit,
+		Needed:      needed,
+		IncreaseUrl: "https://console.cloud.google.com/iam-admin/quotas?project=" + g.gcpProject + "&service=run.googleapis.com",
+	}}, nil
+}
+
+// regionLimit returns the effective limit of the metric in the region, or its limit for every region when it
+// has none for the region. An unlimited quota, -1, is not returned.
+func regionLimit(metrics *consumerQuotaMetrics, metric, region string) (float64, bool) {
+	limit := ""
+	for _, m := range metrics.Metrics {
+		if m.Metric != metric {
+			continue
+		}
+		for _, l := range m.ConsumerQuotaLimits {
+			for _, b := range l.QuotaBuckets {
+				switch b.Dimensions["region"] {
+				case region:
+					limit = b.EffectiveLimit
+				case "":
+					if limit == "" {
+						limit = b.EffectiveLimit
+					}
+				}
+			}
+		}
+	}
+
+	v, err := strconv.ParseFloat(limit, 64)
+	if err != nil || v < 0 {
+		return 0, false
+	}
+	return v, true
+}
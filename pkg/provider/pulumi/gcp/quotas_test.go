@@ -0,0 +1,57 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_regionLimit(t *testing.T) {
+	body := `{"metrics":[
+		{"metric":"run.googleapis.com/requests","consumerQuotaLimits":[{"quotaBuckets":[{"effectiveLimit":"1000"}]}]},
+		{"metric":"run.googleapis.com/cpu_allocation","consumerQuotaLimits":[{"quotaBuckets":[
+			{"effectiveLimit":"20000"},
+			{"effectiveLimit":"100000","dimensions":{"region":"us-central1"}}
+		]}]}
+	]}`
+	metrics := &consumerQuotaMetrics{}
+	if err := json.Unmarshal([]byte(body), metrics); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		region string
+		want   float64
+		wantOk bool
+	}{
+		{region: "us-central1", want: 100000, wantOk: true},
+		{region: "europe-west1", want: 20000, wantOk: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			got, ok := regionLimit(metrics, cloudRunCpuMetric, tt.region)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("regionLimit() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+
+	if _, ok := regionLimit(metrics, "run.googleapis.com/unknown", "us-central1"); ok {
+		t.Error("regionLimit() found a limit for an unknown metric")
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package gcp
+
+import (
+	"path/filepath"
+
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/storage"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+type SiteArgs struct {
+	ProjectId  pulumi.StringInput
+	Location   pulumi.StringInput
+	ProjectDir string
+	Site       project.Site
+	Env        pulumi.StringOutput
+}
+
+type Site struct {
+	pulumi.ResourceState
+
+	Name   string
+	Bucket *storage.Bucket
+}
+
+func newSite(ctx *pulumi.Context, name string, args *SiteArgs, opts ...pulumi.ResourceOption) (*Site, error) {
+	res := &Site{Name: name}
+	err := ctx.RegisterComponentResource("nitric:site:GcpSite", name, res, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, pulumi.Parent(res))
+
+	index := args.Site.IndexDocument
+	if index == "" {
+		index = "index.html"
+	}
+
+	website := &storage.BucketWebsiteArgs{
+		MainPageSuffix: pulumi.String(index),
+	}
+	if args.Site.ErrorDocument != "" {
+		website.NotFoundPage = pulumi.String(args.Site.ErrorDocument)
+	}
+
+	res.Bucket, err = storage.NewBucket(ctx, name+"-site", &storage.BucketArgs{
+		Location:                 args.Location,
+		Project:                  args.ProjectId,
+		Website:                  website,
+		UniformBucketLevelAccess: pulumi.Bool(true),
+		Labels:                   common.Tags(ctx, name),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = storage.NewBucketIAMMember(ctx, name+"-site-public", &storage.BucketIAMMemberArgs{
+		Bucket: res.Bucket.Name,
+		Role:   pulumi.String("roles/storage.objectViewer"),
+		Member: pulumi.String("allUsers"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := common.SiteFiles(filepath.Join(args.ProjectDir, args.Site.Path))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		_, err = storage.NewBucketObject(ctx, name+"-"+f.Key, &storage.BucketObjectArgs{
+			Bucket:      res.Bucket.Name,
+			Name:        pulumi.String(f.Key),
+			Source:      pulumi.NewFileAsset(f.Path),
+			ContentType: pulumi.String(f.ContentType),
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = storage.NewBucketObject(ctx, name+"-"+common.SiteEnvFile, &storage.BucketObjectArgs{
+		Bucket:      res.Bucket.Name,
+		Name:        pulumi.String(common.SiteEnvFile),
+		Content:     args.Env,
+		ContentType: pulumi.String("application/json"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Export("site:"+name, pulumi.Sprintf("https://storage.googleapis.com/%s/%s", res.Bucket.Name, index))
+
+	return res, ctx.RegisterResourceOutputs(res, pulumi.Map{
+		"name":   pulumi.String(res.Name),
+		"bucket": res.Bucket,
+	})
+}
@@ -0,0 +1,116 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+)
+
+var _ common.Sleeper = &gcpProvider{}
+
+// Sleep sets the minScale of the cloud run services to zero, or back to the configured minScale.
+func (g *gcpProvider) Sleep(ids map[string]string, sleeping bool) error {
+	if err := g.setToken(); err != nil {
+		return err
+	}
+
+	for _, c := range g.proj.Computes() {
+		name, ok := ids[c.Unit().Name]
+		if !ok {
+			continue
+		}
+		minScale := c.Unit().MinScale
+		if sleeping {
+			minScale = 0
+		}
+		if err := g.setMinScale(name, minScale); err != nil {
+			return errors.WithMessage(err, "service "+c.Unit().Name)
+		}
+	}
+	return nil
+}
+
+// setMinScale replaces the service with a new revision that has the minScale annotation.
+func (g *gcpProvider) setMinScale(name string, minScale int) error {
+	u := fmt.Sprintf("https://%s-run.googleapis.com/apis/serving.knative.dev/v1/namespaces/%s/services/%s", g.sc.Region, g.gcpProject, name)
+
+	body, err := g.runDo(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	svc := map[string]interface{}{}
+	if err := json.NewDecoder(body).Decode(&svc); err != nil {
+		return err
+	}
+
+	spec, _ := svc["spec"].(map[string]interface{})
+	template, _ := spec["template"].(map[string]interface{})
+	metadata, _ := template["metadata"].(map[string]interface{})
+	if metadata == nil {
+		return fmt.Errorf("service %s has no revision template", name)
+	}
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	if annotations == nil {
+		annotations = map[string]interface{}{}
+		metadata["annotations"] = annotations
+	}
+	annotations["autoscaling.knative.dev/minScale"] = fmt.Sprint(minScale)
+	// a new revision is created, so the name of the current one can't be reused
+	delete(metadata, "name")
+
+	b, err := json.Marshal(svc)
+	if err != nil {
+		return err
+	}
+	resp, err := g.runDo(http.MethodPut, u, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	return resp.Close()
+}
+
+// runDo calls the Cloud Run admin API, returning the response body when it succeeds.
+func (g *gcpProvider) runDo(method, u string, body io.Reader) (io.ReadCloser, error) {
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	g.token.SetAuthHeader(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("cloud run %s failed with %s: %s", method, resp.Status, msg)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,90 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/monitoring"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// newUptimeChecks creates an uptime check of each checked api, with an alerting policy when it fails.
+func (res *Alerts) newUptimeChecks(ctx *pulumi.Context, args *AlertsArgs, opts ...pulumi.ResourceOption) error {
+	uptime := args.Alerts.Uptime
+	for k, gw := range args.Gateways {
+		if !uptime.Checks(k) {
+			continue
+		}
+
+		var err error
+		res.UptimeChecks[k], err = monitoring.NewUptimeCheckConfig(ctx, k+"-uptime", &monitoring.UptimeCheckConfigArgs{
+			Project:     pulumi.String(args.ProjectId),
+			DisplayName: pulumi.String(ctx.Stack() + "-" + k),
+			Period:      pulumi.String(fmt.Sprintf("%ds", uptime.MinutesOrDefault()*60)),
+			Timeout:     pulumi.String("10s"),
+			HttpCheck: monitoring.UptimeCheckConfigHttpCheckArgs{
+				Path:        pulumi.String(uptime.PathOrDefault()),
+				Port:        pulumi.Int(443),
+				UseSsl:      pulumi.Bool(true),
+				ValidateSsl: pulumi.Bool(true),
+			},
+			MonitoredResource: monitoring.UptimeCheckConfigMonitoredResourceArgs{
+				Type: pulumi.String("uptime_url"),
+				Labels: pulumi.StringMap{
+					"project_id": pulumi.String(args.ProjectId),
+					"host":       gw.Gateway.DefaultHostname,
+				},
+			},
+		}, opts...)
+		if err != nil {
+			return errors.WithMessage(err, "uptime check "+k)
+		}
+
+		res.Policies[k+"-uptime"], err = monitoring.NewAlertPolicy(ctx, k+"-uptime-alert", &monitoring.AlertPolicyArgs{
+			Project:     pulumi.String(args.ProjectId),
+			DisplayName: pulumi.String(k + "-uptime"),
+			Combiner:    pulumi.String("OR"),
+			Conditions: monitoring.AlertPolicyConditionArray{
+				monitoring.AlertPolicyConditionArgs{
+					DisplayName: pulumi.String(fmt.Sprintf("api %s is not responding", k)),
+					ConditionThreshold: monitoring.AlertPolicyConditionConditionThresholdArgs{
+						Filter:         pulumi.Sprintf(`metric.type="monitoring.googleapis.com/uptime_check/check_passed" AND resource.type="uptime_url" AND metric.label.check_id="%s"`, res.UptimeChecks[k].UptimeCheckId),
+						Comparison:     pulumi.String("COMPARISON_GT"),
+						ThresholdValue: pulumi.Float64(1),
+						Duration:       pulumi.String("60s"),
+						Aggregations: monitoring.AlertPolicyConditionConditionThresholdAggregationArray{
+							// the number of regions the check failed from
+							monitoring.AlertPolicyConditionConditionThresholdAggregationArgs{
+								AlignmentPeriod:    pulumi.String(fmt.Sprintf("%ds", uptime.MinutesOrDefault()*60*2)),
+								PerSeriesAligner:   pulumi.String("ALIGN_NEXT_OLDER"),
+								CrossSeriesReducer: pulumi.String("REDUCE_COUNT_FALSE"),
+								GroupByFields:      pulumi.StringArray{pulumi.String("resource.label.*")},
+							},
+						},
+					},
+				},
+			},
+			NotificationChannels: pulumi.StringArray{res.Channel.Name},
+		}, opts...)
+		if err != nil {
+			return errors.WithMessage(err, "uptime alert policy "+k)
+		}
+	}
+	return nil
+}
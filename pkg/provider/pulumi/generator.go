@@ -19,11 +19,15 @@ package pulumi
 import (
 	"context"
 	"fmt"
+	"io"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/pterm/pterm"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optup"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 
@@ -39,9 +43,17 @@ import (
 )
 
 type pulumiDeployment struct {
-	proj *project.Project
-	sc   *stack.Config
-	prov common.PulumiProvider
+	proj         *project.Project
+	sc           *stack.Config
+	prov         common.PulumiProvider
+	listener     types.EventListener
+	deleteData   bool
+	emptyBuckets bool
+	plan         *types.Plan
+	readOnly     bool
+	showSecrets  bool
+	overrideLock bool
+	configOnly   bool
 }
 
 type stackSummary struct {
@@ -51,6 +63,10 @@ type stackSummary struct {
 	UpdateInProgress bool   `json:"updateInProgress"`
 	ResourceCount    *int   `json:"resourceCount,omitempty"`
 	URL              string `json:"url,omitempty"`
+	Project          string `json:"project,omitempty"`
+	Target           string `json:"target,omitempty"`
+	GitCommit        string `json:"gitCommit,omitempty"`
+	CLIVersion       string `json:"cliVersion,omitempty"`
 }
 
 var (
@@ -67,6 +83,10 @@ func New(p *project.Project, sc *stack.Config, envMap map[string]string) (types.
 		return nil, err
 	}
 
+	if err := p.FilterFunctions(sc); err != nil {
+		return nil, err
+	}
+
 	var prov common.PulumiProvider
 	switch sc.Provider {
 	case stack.Aws:
@@ -94,7 +114,366 @@ func (p *pulumiDeployment) TryPullImages() error {
 	return p.prov.TryPullImages()
 }
 
+func (p *pulumiDeployment) Env(function string) ([]types.EnvVar, error) {
+	for _, c := range p.proj.Computes() {
+		if c.Unit().Name == function {
+			return p.prov.Env(c), nil
+		}
+	}
+	return nil, fmt.Errorf("function %s not found in project %s", function, p.proj.Name)
+}
+
+func (p *pulumiDeployment) messenger() (common.Messenger, error) {
+	m, ok := p.prov.(common.Messenger)
+	if !ok {
+		return nil, utils.NewNotSupportedErr("messages can not be sent to " + p.sc.Provider + " stacks")
+	}
+	return m, p.prov.Validate()
+}
+
+func (p *pulumiDeployment) Publish(topic string, payload map[string]interface{}) (string, error) {
+	m, err := p.messenger()
+	if err != nil {
+		return "", err
+	}
+	if err := p.proj.ValidateMessage(topic, payload); err != nil {
+		return "", err
+	}
+	return m.Publish(p.proj.Name+"-"+p.sc.Name, topic, payload)
+}
+
+func (p *pulumiDeployment) Send(queue string, payload map[string]interface{}) (string, error) {
+	m, err := p.messenger()
+	if err != nil {
+		return "", err
+	}
+	return m.Send(p.proj.Name+"-"+p.sc.Name, queue, payload)
+}
+
+func (p *pulumiDeployment) Documents() (types.DocumentStore, error) {
+	d, ok := p.prov.(common.DocumentStorer)
+	if !ok {
+		return nil, utils.NewNotSupportedErr("collections can not be accessed in " + p.sc.Provider + " stacks")
+	}
+	if err := p.prov.Validate(); err != nil {
+		return nil, err
+	}
+	return d.Documents(p.proj.Name + "-" + p.sc.Name)
+}
+
+func (p *pulumiDeployment) Buckets() (types.BucketStore, error) {
+	b, ok := p.prov.(common.BucketStorer)
+	if !ok {
+		return nil, utils.NewNotSupportedErr("buckets can not be accessed in " + p.sc.Provider + " stacks")
+	}
+	if err := p.prov.Validate(); err != nil {
+		return nil, err
+	}
+
+	names, err := p.outputs("bucket:")
+	if err != nil {
+		return nil, err
+	}
+	return b.Buckets(names)
+}
+
+func (p *pulumiDeployment) Deployer(create bool) (*types.DeployerRole, error) {
+	if create {
+		if err := p.writable("create the deployer role of"); err != nil {
+			return nil, err
+		}
+	}
+
+	b, ok := p.prov.(common.Bootstrapper)
+	if !ok {
+		return nil, utils.NewNotSupportedErr("deployer roles can not be bootstrapped for " + p.sc.Provider)
+	}
+
+	role, err := b.DeployerRole(p.proj.Name + "-deployer")
+	if err != nil || !create {
+		return role, err
+	}
+	return role, b.CreateDeployerRole(role)
+}
+
+func (p *pulumiDeployment) Permissions() ([]types.Permission, error) {
+	m, ok := p.prov.(common.PermissionMapper)
+	if !ok {
+		return nil, utils.NewNotSupportedErr("the permissions of " + p.sc.Provider + " stacks can not be reported")
+	}
+	return common.Permissions(p.proj.Policies, m), nil
+}
+
+func (p *pulumiDeployment) Health() ([]types.FunctionHealth, error) {
+	h, ok := p.prov.(common.HealthChecker)
+	if !ok {
+		return nil, utils.NewNotSupportedErr("the functions of " + p.sc.Provider + " stacks can not be health checked")
+	}
+	if err := p.prov.Validate(); err != nil {
+		return nil, err
+	}
+
+	ids, err := p.outputs("function:")
+	if err != nil {
+		return nil, err
+	}
+	// stacks deployed by earlier versions have no function outputs until they are updated
+	deployed := map[string]string{}
+	for name := range p.proj.Functions {
+		if id, ok := ids[name]; ok {
+			deployed[name] = id
+		}
+	}
+	return h.Health(deployed)
+}
+
+func (p *pulumiDeployment) Revisions(function string) ([]types.Revision, error) {
+	r, ok := p.prov.(common.RevisionLister)
+	if !ok {
+		return nil, utils.NewNotSupportedErr("the functions of " + p.sc.Provider + " stacks have no revisions to list")
+	}
+	if err := p.prov.Validate(); err != nil {
+		return nil, err
+	}
+
+	ids, err := p.outputs("function:")
+	if err != nil {
+		return nil, err
+	}
+	id, ok := ids[function]
+	if !ok {
+		return nil, fmt.Errorf("function %s is not deployed to stack %s", function, p.sc.Name)
+	}
+	return r.Revisions(id)
+}
+
+func (p *pulumiDeployment) Quotas() ([]types.Quota, error) {
+	q, ok := p.prov.(common.QuotaChecker)
+	if !ok {
+		return nil, utils.NewNotSupportedErr("the quotas of " + p.sc.Provider + " accounts can not be checked")
+	}
+	if err := p.prov.Validate(); err != nil {
+		return nil, err
+	}
+	return q.Quotas()
+}
+
+func (p *pulumiDeployment) encrypter() (common.Encrypter, error) {
+	e, ok := p.prov.(common.Encrypter)
+	if !ok {
+		return nil, utils.NewNotSupportedErr("stack values can not be encrypted for " + p.sc.Provider)
+	}
+	if p.sc.EncryptionKey == "" {
+		return nil, errors.New("the stack has no encryptionKey to encrypt values with")
+	}
+	return e, nil
+}
+
+func (p *pulumiDeployment) Encrypt(plaintext string) (string, error) {
+	e, err := p.encrypter()
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := e.Encrypt(plaintext)
+	if err != nil {
+		return "", err
+	}
+	return stack.EncryptedRef(ciphertext), nil
+}
+
+// decrypt replaces the encrypted values of the stack with their plaintext, in place as the provider
+// shares the stack.
+func (p *pulumiDeployment) decrypt() error {
+	encrypted, err := p.sc.Encrypted()
+	if err != nil || !encrypted {
+		return err
+	}
+	e, err := p.encrypter()
+	if err != nil {
+		return err
+	}
+
+	sc, err := p.sc.Decrypt(e.Decrypt)
+	if err != nil {
+		return err
+	}
+	*p.sc = *sc
+	return nil
+}
+
+// refreshCredentials fetches new registry credentials for the providers that hold them across the deployment,
+// the others fetch them each time the program runs.
+func (p *pulumiDeployment) refreshCredentials() error {
+	r, ok := p.prov.(common.CredentialRefresher)
+	if !ok {
+		return nil
+	}
+	return errors.WithMessage(r.RefreshCredentials(), "refreshing the registry credentials")
+}
+
+func (p *pulumiDeployment) Sleep(sleeping bool) error {
+	if err := p.writable("scale"); err != nil {
+		return err
+	}
+
+	s, ok := p.prov.(common.Sleeper)
+	if !ok {
+		return utils.NewNotSupportedErr(p.sc.Provider + " stacks can not be put to sleep")
+	}
+	if err := p.prov.Validate(); err != nil {
+		return err
+	}
+
+	ids, err := p.outputs("service:")
+	if err != nil {
+		return err
+	}
+	return s.Sleep(ids, sleeping)
+}
+
+func (p *pulumiDeployment) Domains() ([]types.DomainStatus, error) {
+	d, ok := p.prov.(common.DomainValidator)
+	if !ok {
+		return nil, utils.NewNotSupportedErr("the custom domains of " + p.sc.Provider + " stacks are validated when they are deployed")
+	}
+	if err := p.prov.Validate(); err != nil {
+		return nil, err
+	}
+
+	out, err := p.outputs("")
+	if err != nil {
+		return nil, err
+	}
+	return d.Domains(out)
+}
+
+// selectStack returns the deployed stack, without installing the plugins or refreshing it.
+func (p *pulumiDeployment) selectStack(ctx context.Context) (*auto.Stack, error) {
+	s, err := auto.SelectStackInlineSource(ctx, p.proj.Name+"-"+p.sc.Name, p.proj.Name, p.prov.Deploy,
+		auto.SecretsProvider("passphrase"),
+		auto.Project(workspace.Project{
+			Name:    tokens.PackageName(p.proj.Name),
+			Runtime: workspace.NewProjectRuntimeInfo("go", nil),
+			Main:    p.proj.Dir,
+		}))
+	if err != nil {
+		return nil, errors.WithMessage(err, "SelectStackInlineSource")
+	}
+	return &s, nil
+}
+
+// stackOutputs returns the outputs of the deployed stack, without refreshing it.
+func (p *pulumiDeployment) stackOutputs() (auto.OutputMap, error) {
+	ctx := context.Background()
+
+	s, err := p.selectStack(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s.Outputs(ctx)
+	return out, errors.WithMessage(err, "Outputs")
+}
+
+// outputs returns the deployed stack's outputs that have the prefix, keyed by the rest of their name.
+func (p *pulumiDeployment) outputs(prefix string) (map[string]string, error) {
+	out, err := p.stackOutputs()
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string]string{}
+	for k, v := range out {
+		if strings.HasPrefix(k, prefix) {
+			res[strings.TrimPrefix(k, prefix)] = fmt.Sprint(v.Value)
+		}
+	}
+	return res, nil
+}
+
+func (p *pulumiDeployment) SetEventListener(l types.EventListener) {
+	p.listener = l
+}
+
+func (p *pulumiDeployment) PluginVersions() map[string]string {
+	versions := map[string]string{}
+	for _, plug := range p.prov.Plugins() {
+		versions[plug.Name] = plug.Version
+	}
+	return versions
+}
+
+func (p *pulumiDeployment) SetDeleteData(deleteData bool) {
+	p.deleteData = deleteData
+}
+
+func (p *pulumiDeployment) SetPlan(plan *types.Plan) {
+	p.plan = plan
+}
+
+func (p *pulumiDeployment) SetShowSecrets(showSecrets bool) {
+	p.showSecrets = showSecrets
+}
+
+func (p *pulumiDeployment) SetReadOnly(readOnly bool) {
+	p.readOnly = readOnly
+}
+
+func (p *pulumiDeployment) SetOverrideLock(overrideLock bool) {
+	p.overrideLock = overrideLock
+}
+
+func (p *pulumiDeployment) SetConfigOnly(configOnly bool) {
+	p.configOnly = configOnly
+}
+
+// writable returns an error when the stack is read-only, saying the action can't be done to it.
+func (p *pulumiDeployment) writable(action string) error {
+	if p.readOnly {
+		return fmt.Errorf("can't %s the read-only stack %s, unset read_only in the settings or use --read-only=false to change it", action, p.sc.Name)
+	}
+	return nil
+}
+
+func (p *pulumiDeployment) SetEmptyBuckets(emptyBuckets bool) {
+	p.emptyBuckets = emptyBuckets
+}
+
+// history returns the file to record the operation's engine events in, or nil if it can't be created.
+func (p *pulumiDeployment) history(operation string, log output.Progress) io.WriteCloser {
+	f, err := newHistoryFile(p.proj.Dir, p.sc.Name, operation)
+	if err != nil {
+		log.Debugf("unable to record the deployment events: %v", err)
+		return nil
+	}
+	return f
+}
+
+// load returns the stack, refreshed so its state matches the resources that exist.
 func (p *pulumiDeployment) load(log output.Progress) (*auto.Stack, error) {
+	if err := utils.RequireNetwork(fmt.Sprintf("refreshing the %s stack %s", p.sc.Provider, p.sc.Name)); err != nil {
+		return nil, err
+	}
+
+	s, err := p.upsert(log)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.readOnly {
+		// refreshing writes the state, so it is read as it was last deployed
+		return s, nil
+	}
+
+	log.Busyf("Refreshing the Pulumi stack")
+	_, err = s.Refresh(context.Background())
+	return s, errors.WithMessage(err, "Refresh")
+}
+
+// upsert returns the stack with the provider's plugins installed and configured, creating it unless
+// the stack is read-only.
+func (p *pulumiDeployment) upsert(log output.Progress) (*auto.Stack, error) {
 	if err := p.prov.Validate(); err != nil {
 		return nil, err
 	}
@@ -102,7 +481,13 @@ func (p *pulumiDeployment) load(log output.Progress) (*auto.Stack, error) {
 	stackName := p.proj.Name + "-" + p.sc.Name
 	ctx := context.Background()
 
-	s, err := auto.UpsertStackInlineSource(ctx, stackName, p.proj.Name, p.prov.Deploy,
+	upsert := auto.UpsertStackInlineSource
+	if p.readOnly {
+		// a read-only stack is not created when it doesn't exist
+		upsert = auto.SelectStackInlineSource
+	}
+
+	s, err := upsert(ctx, stackName, p.proj.Name, p.prov.Deploy,
 		auto.SecretsProvider("passphrase"),
 		auto.Project(workspace.Project{
 			Name:    tokens.PackageName(p.proj.Name),
@@ -125,34 +510,209 @@ func (p *pulumiDeployment) load(log output.Progress) (*auto.Stack, error) {
 	if err != nil {
 		return nil, errors.WithMessage(err, "Configure")
 	}
+	return &s, nil
+}
+
+// validate decrypts the stack and checks the project can be deployed to it, before the stack is loaded.
+func (p *pulumiDeployment) validate() error {
+	if err := p.decrypt(); err != nil {
+		return err
+	}
 
-	log.Busyf("Refreshing the Pulumi stack")
-	_, err = s.Refresh(ctx)
-	return &s, errors.WithMessage(err, "Refresh")
+	if err := p.proj.ValidateDatabases(); err != nil {
+		return err
+	}
+
+	if err := p.proj.ValidateCaches(); err != nil {
+		return err
+	}
+
+	if err := p.proj.ValidateEmails(); err != nil {
+		return err
+	}
+
+	if err := p.proj.ValidateComputeClasses(); err != nil {
+		return err
+	}
+
+	if err := p.proj.ValidateTriggers(); err != nil {
+		return err
+	}
+
+	if err := p.proj.ValidateVolumes(); err != nil {
+		return err
+	}
+
+	if err := p.proj.ValidateTopicSchemas(); err != nil {
+		return err
+	}
+
+	if err := validateNaming(p.sc.Naming); err != nil {
+		return err
+	}
+
+	if err := common.ValidateThrottling(p.proj, p.sc); err != nil {
+		return err
+	}
+
+	if err := common.ValidateApiRequests(p.proj, p.sc); err != nil {
+		return err
+	}
+
+	if err := common.ValidateApis(p.proj, p.sc); err != nil {
+		return err
+	}
+
+	if err := p.validateArchive(); err != nil {
+		return err
+	}
+
+	if err := common.CheckCapabilities(p.sc.Provider, p.prov.Capabilities(), p.proj, p.sc); err != nil {
+		return err
+	}
+	for _, w := range common.SkippedCapabilities(p.sc.Provider, p.prov.Capabilities(), p.proj, p.sc) {
+		pterm.Warning.Println(w)
+	}
+
+	return nil
 }
 
 func (p *pulumiDeployment) Up(log output.Progress) (*types.Deployment, error) {
+	if err := p.writable("update"); err != nil {
+		return nil, err
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
 	s, err := p.load(log)
 	if err != nil {
 		return nil, errors.WithMessage(err, "loading pulumi stack")
 	}
 
-	res, err := s.Up(context.Background(), updateLoggingOpts(log)...)
+	if err := p.checkLock(context.Background(), s, "update", log); err != nil {
+		return nil, err
+	}
+
+	if err := p.setStableNames(context.Background(), s); err != nil {
+		return nil, errors.WithMessage(err, "reading the names of the deployed resources")
+	}
+
+	log.Busyf("Tagging the Pulumi stack")
+	if err := setStackTags(context.Background(), s, stackTags(p.proj, p.sc)); err != nil {
+		log.Debugf("unable to tag the stack: %v", err)
+	}
+
+	if p.deleteData {
+		log.Busyf("Removing protection from stateful resources")
+		if err := unprotect(context.Background(), s); err != nil {
+			return nil, err
+		}
+	}
+
 	defer p.prov.CleanUp()
+
+	if p.plan != nil {
+		if err := p.checkPlan(s, log); err != nil {
+			return nil, err
+		}
+	}
+
+	opts := func() []optup.Option {
+		return updateLoggingOpts(log, p.listener, p.history("up", log))
+	}
+	if p.configOnly {
+		log.Busyf("Checking only the configuration of the functions changes")
+		if err := p.reuseImages(context.Background(), s); err != nil {
+			return nil, err
+		}
+		targets, _, err := p.configChanges(s)
+		if err != nil {
+			return nil, err
+		}
+		if len(targets) == 0 {
+			out, err := s.Outputs(context.Background())
+			if err != nil {
+				return nil, errors.WithMessage(err, "Outputs")
+			}
+			return newDeployment(out, p.showSecrets), nil
+		}
+		opts = func() []optup.Option {
+			return append(updateLoggingOpts(log, p.listener, p.history("up", log)), optup.Target(targets))
+		}
+	}
+
+	archive := p.archiver("update")
+	archive.pre(context.Background(), s, log)
+
+	// retrying the update only changes the resources that failed, or were waiting on them.
+	res, err := s.Up(context.Background(), opts()...)
+	for retry := 1; retry <= maxUpRetries && (isTransient(err) || isRegistryAuth(err)); retry++ {
+		if isRegistryAuth(err) {
+			log.Busyf("Pushing an image failed as the registry credentials expired, refreshing them and resuming (%d/%d)", retry, maxUpRetries)
+			log.Debugf("%v", err)
+			if err := p.refreshCredentials(); err != nil {
+				return nil, err
+			}
+		} else {
+			delay := backoff(retry)
+			log.Busyf("Deployment failed with a transient error, retrying in %s (%d/%d)", delay, retry, maxUpRetries)
+			log.Debugf("%v", err)
+			time.Sleep(delay)
+		}
+
+		res, err = s.Up(context.Background(), opts()...)
+	}
+	archive.post(context.Background(), s, res.Summary.ResourceChanges, err, log)
+	if remediation := registryRemediation(err); remediation != "" {
+		return nil, errors.WithMessage(err, remediation)
+	}
 	if err != nil {
 		return nil, errors.WithMessage(err, "Updating pulumi stack "+res.Summary.Message)
 	}
 
+	return newDeployment(res.Outputs, p.showSecrets), nil
+}
+
+// secretMask is shown in place of the outputs marked as secret, unless they are revealed.
+const secretMask = "[secret]"
+
+// newDeployment returns the endpoints and images of the outputs, masking the secret outputs unless showSecrets.
+func newDeployment(outputs auto.OutputMap, showSecrets bool) *types.Deployment {
 	d := &types.Deployment{
-		ApiEndpoints: map[string]string{},
+		ApiEndpoints:  map[string]string{},
+		CdnEndpoints:  map[string]string{},
+		SiteEndpoints: map[string]string{},
+		Images:        map[string]string{},
 	}
 
-	for k, v := range res.Outputs {
+	for k, v := range outputs {
+		value := fmt.Sprint(v.Value)
+		if v.Secret && !showSecrets {
+			value = secretMask
+		}
 		if strings.HasPrefix(k, "api:") {
-			d.ApiEndpoints[strings.TrimPrefix(k, "api:")] = fmt.Sprint(v.Value)
+			d.ApiEndpoints[strings.TrimPrefix(k, "api:")] = value
 		}
+		if strings.HasPrefix(k, "cdn:") {
+			d.CdnEndpoints[strings.TrimPrefix(k, "cdn:")] = value
+		}
+		if strings.HasPrefix(k, "site:") {
+			d.SiteEndpoints[strings.TrimPrefix(k, "site:")] = value
+		}
+		if strings.HasPrefix(k, "image:") {
+			d.Images[strings.TrimPrefix(k, "image:")] = value
+		}
+	}
+	return d
+}
+
+func (p *pulumiDeployment) Deployment() (*types.Deployment, error) {
+	out, err := p.stackOutputs()
+	if err != nil {
+		return nil, err
 	}
-	return d, nil
+	return newDeployment(out, p.showSecrets), nil
 }
 
 func (p *pulumiDeployment) List() (interface{}, error) {
@@ -186,6 +746,16 @@ func (p *pulumiDeployment) List() (interface{}, error) {
 				ResourceCount:    st.ResourceCount,
 				URL:              st.URL,
 			}
+			if tags, err := getStackTags(context.Background(), ws, st.Name); err == nil {
+				stackListOutput.Project = tags[tagPrefix+"project"]
+				stackListOutput.Target = tagTarget(tags)
+				stackListOutput.GitCommit = tags[tagPrefix+"git-commit"]
+				stackListOutput.CLIVersion = tags[tagPrefix+"cli-version"]
+			}
+			if stackListOutput.Target == "" {
+				// stacks deployed before they were tagged
+				stackListOutput.Target = tagTarget(map[string]string{tagPrefix + "provider": p.sc.Provider, tagPrefix + "region": p.sc.Region})
+			}
 			result = append(result, stackListOutput)
 		}
 	}
@@ -193,14 +763,69 @@ func (p *pulumiDeployment) List() (interface{}, error) {
 }
 
 func (a *pulumiDeployment) Down(log output.Progress) error {
-	s, err := a.load(log)
+	if err := a.writable("delete"); err != nil {
+		return err
+	}
+
+	s, err := a.upsert(log)
 	if err != nil {
 		return err
 	}
 
-	res, err := s.Destroy(context.Background(), destroyLoggingOpts(log)...)
+	if err := a.checkLock(context.Background(), s, "delete", log); err != nil {
+		return err
+	}
+
+	// an interrupted update leaves pending operations that stop the stack being refreshed
+	interrupted, err := clearPendingOperations(context.Background(), s)
 	if err != nil {
-		return errors.WithMessage(err, res.Summary.Message)
+		return err
 	}
-	return nil
+
+	log.Busyf("Refreshing the Pulumi stack")
+	if _, err := s.Refresh(context.Background()); err != nil {
+		return errors.WithMessage(err, "Refresh")
+	}
+
+	if a.deleteData {
+		log.Busyf("Removing protection from stateful resources")
+		if err := unprotect(context.Background(), s); err != nil {
+			return err
+		}
+	} else {
+		protected, err := protectedResources(context.Background(), s)
+		if err != nil {
+			return err
+		}
+		if len(protected) > 0 {
+			return fmt.Errorf("the stack contains resources that hold data and are protected from deletion:\n  %s\nuse --delete-data to delete them along with the stack", strings.Join(protected, "\n  "))
+		}
+	}
+
+	archive := a.archiver("delete")
+	archive.pre(context.Background(), s, log)
+
+	a.emptyDeployedBuckets(log)
+
+	purger, purges := a.prov.(common.Purger)
+	var outputs map[string]string
+	if purges {
+		// the outputs are gone once the stack is deleted
+		outputs, err = a.outputs("")
+		if err != nil {
+			return err
+		}
+	}
+
+	failed, err := a.destroy(context.Background(), s, log)
+	archive.post(context.Background(), s, nil, destroyErr(failed, err), log)
+	if err != nil {
+		return err
+	}
+	if purges && len(failed) == 0 {
+		if err := purger.PurgeDeleted(outputs, log); err != nil {
+			return err
+		}
+	}
+	return leftoversError(context.Background(), s, failed, interrupted)
 }
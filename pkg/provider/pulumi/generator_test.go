@@ -0,0 +1,80 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+)
+
+func TestReadOnly(t *testing.T) {
+	p := &pulumiDeployment{
+		proj: &project.Project{Name: "app"},
+		sc:   &stack.Config{Name: "prod", Provider: stack.Aws},
+	}
+	p.SetReadOnly(true)
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{name: "update", run: func() error { _, err := p.Up(nil); return err }},
+		{name: "delete", run: func() error { return p.Down(nil) }},
+		{name: "preflight", run: p.Preflight},
+		{name: "sleep", run: func() error { return p.Sleep(true) }},
+		{name: "delete orphans", run: func() error { return p.DeleteOrphans(nil, nil) }},
+		{name: "create deployer", run: func() error { _, err := p.Deployer(true); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if err == nil || !strings.Contains(err.Error(), "read-only stack prod") {
+				t.Errorf("error = %v, want the stack to be read-only", err)
+			}
+		})
+	}
+
+	p.SetReadOnly(false)
+	if err := p.writable("update"); err != nil {
+		t.Errorf("writable() error = %v", err)
+	}
+}
+
+func TestNewDeploymentSecrets(t *testing.T) {
+	outputs := auto.OutputMap{
+		"api:public":  auto.OutputValue{Value: "https://public.example.com"},
+		"api:private": auto.OutputValue{Value: "https://key@private.example.com", Secret: true},
+	}
+
+	d := newDeployment(outputs, false)
+	if d.ApiEndpoints["public"] != "https://public.example.com" {
+		t.Errorf("public endpoint = %q, want it shown", d.ApiEndpoints["public"])
+	}
+	if d.ApiEndpoints["private"] != secretMask {
+		t.Errorf("secret endpoint = %q, want it masked", d.ApiEndpoints["private"])
+	}
+
+	d = newDeployment(outputs, true)
+	if d.ApiEndpoints["private"] != "https://key@private.example.com" {
+		t.Errorf("secret endpoint = %q, want it shown with showSecrets", d.ApiEndpoints["private"])
+	}
+}
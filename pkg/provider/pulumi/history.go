@@ -0,0 +1,146 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
+
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// maxHistory is the number of deployment event logs kept for each stack.
+const maxHistory = 10
+
+// historyDir returns the directory the engine events of a stack's deployments are recorded in.
+func historyDir(projDir, stackName string) string {
+	return filepath.Join(utils.NitricLogDir(projDir), "history", stackName)
+}
+
+// newHistoryFile creates the file to record the engine events of an operation in,
+// removing the oldest records beyond maxHistory.
+func newHistoryFile(projDir, stackName, operation string) (*os.File, error) {
+	dir := historyDir(projDir, stackName)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	files, err := historyFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	for len(files) >= maxHistory {
+		_ = os.Remove(files[0])
+		files = files[1:]
+	}
+
+	name := fmt.Sprintf("%s-%s.jsonl", time.Now().UTC().Format("20060102T150405"), operation)
+	return os.Create(filepath.Join(dir, name))
+}
+
+// historyFiles returns the recorded event logs in dir, oldest first.
+func historyFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// recordEvents writes each engine event as a line of JSON until the channel is closed.
+func recordEvents(w io.WriteCloser, eventChannel <-chan events.EngineEvent) {
+	defer w.Close()
+
+	enc := json.NewEncoder(w)
+	for event := range eventChannel {
+		_ = enc.Encode(event.EngineEvent)
+	}
+}
+
+// LastDeployLog returns the file holding the engine events of the last deployment of the stack.
+func LastDeployLog(projDir, stackName string) (string, error) {
+	files, err := historyFiles(historyDir(projDir, stackName))
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no deployments of stack %s have been recorded, run 'nitric stack update -s %s' first", stackName, stackName)
+	}
+	return files[len(files)-1], nil
+}
+
+// ReplayDeployLog re-renders the engine events recorded in file as they were shown during the deployment,
+// followed by any errors reported by the engine.
+func ReplayDeployLog(file string, log output.Progress) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	prefix := "Deploying.. "
+	if strings.HasSuffix(file, "-down.jsonl") {
+		prefix = "Deleting.. "
+	}
+
+	eventChannel := make(chan events.EngineEvent)
+	diagnostics := []string{}
+	scanErr := make(chan error, 1)
+
+	go func() {
+		defer close(eventChannel)
+
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+		for scanner.Scan() {
+			event := events.EngineEvent{}
+			if err := json.Unmarshal(scanner.Bytes(), &event.EngineEvent); err != nil {
+				scanErr <- err
+				return
+			}
+			if event.DiagnosticEvent != nil && event.DiagnosticEvent.Severity == "error" {
+				diagnostics = append(diagnostics, strings.TrimSpace(event.DiagnosticEvent.Message))
+			}
+			eventChannel <- event
+		}
+		scanErr <- scanner.Err()
+	}()
+
+	collectEvents(log, newEventEmitter(nil, ""), eventChannel, prefix)
+	if err := <-scanErr; err != nil {
+		return err
+	}
+
+	for _, d := range diagnostics {
+		log.Failf("%s\n", d)
+	}
+	if len(diagnostics) > 0 {
+		return fmt.Errorf("the deployment reported %d error(s)", len(diagnostics))
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHistoryFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := LastDeployLog(dir, "aws"); err == nil {
+		t.Error("expected an error when no deployments have been recorded")
+	}
+
+	hd := historyDir(dir, "aws")
+	if err := os.MkdirAll(hd, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < maxHistory; i++ {
+		name := filepath.Join(hd, fmt.Sprintf("20220101T0000%02d-up.jsonl", i))
+		if err := os.WriteFile(name, []byte{}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := newHistoryFile(dir, "aws", "down")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	files, err := historyFiles(hd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != maxHistory {
+		t.Errorf("expected %d files, got %d", maxHistory, len(files))
+	}
+	if filepath.Base(files[0]) != "20220101T000001-up.jsonl" {
+		t.Errorf("expected the oldest file to be removed, got %s", files[0])
+	}
+
+	last, err := LastDeployLog(dir, "aws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last != f.Name() {
+		t.Errorf("expected %s, got %s", f.Name(), last)
+	}
+}
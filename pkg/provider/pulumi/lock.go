@@ -0,0 +1,131 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// The lock is kept in the stack's tags, so it is seen by everyone deploying the stack from the backend.
+const (
+	lockReasonTag = tagPrefix + "lock-reason"
+	lockOwnerTag  = tagPrefix + "lock-owner"
+	lockTimeTag   = tagPrefix + "lock-time"
+)
+
+// stackLock returns the lock recorded in the stack's tags, nil when it isn't locked.
+func stackLock(tags map[string]string) *types.StackLock {
+	if tags[lockReasonTag] == "" && tags[lockOwnerTag] == "" {
+		return nil
+	}
+	return &types.StackLock{
+		Reason: tags[lockReasonTag],
+		Owner:  tags[lockOwnerTag],
+		Time:   tags[lockTimeTag],
+	}
+}
+
+// lockedError says who locked the stack and why, and how to change it anyway.
+func lockedError(action, stackName string, lock *types.StackLock) error {
+	return fmt.Errorf("can't %s the stack %s, it was locked by %s at %s: %s\nunlock it with 'nitric stack unlock -s %s' or use --override-lock",
+		action, stackName, lock.Owner, lock.Time, lock.Reason, stackName)
+}
+
+// checkLock returns an error when the stack is locked, unless the lock is overridden. Backends that can't
+// tag stacks can't be locked either, so failing to read the tags is not an error.
+func (p *pulumiDeployment) checkLock(ctx context.Context, s *auto.Stack, action string, log output.Progress) error {
+	tags, err := getStackTags(ctx, s.Workspace(), s.Name())
+	if err != nil {
+		log.Debugf("unable to read the stack's lock: %v", err)
+		return nil
+	}
+
+	lock := stackLock(tags)
+	if lock == nil {
+		return nil
+	}
+	if p.overrideLock {
+		log.Debugf("overriding the lock of %s by %s: %s", p.sc.Name, lock.Owner, lock.Reason)
+		return nil
+	}
+	return lockedError(action, p.sc.Name, lock)
+}
+
+func (p *pulumiDeployment) Lock(reason string) (*types.StackLock, error) {
+	if err := p.writable("lock"); err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		return nil, errors.New("a reason is required to lock the stack")
+	}
+
+	ctx := context.Background()
+	s, err := p.selectStack(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &types.StackLock{
+		Reason: reason,
+		Owner:  utils.GitUser(p.proj.Dir),
+		Time:   time.Now().UTC().Format(time.RFC3339),
+	}
+	tags := map[string]string{
+		lockReasonTag: lock.Reason,
+		lockOwnerTag:  lock.Owner,
+		lockTimeTag:   lock.Time,
+	}
+	if err := setStackTags(ctx, s, tags); err != nil {
+		return nil, errors.WithMessage(err, "locking the stack")
+	}
+	return lock, nil
+}
+
+func (p *pulumiDeployment) Unlock() error {
+	if err := p.writable("unlock"); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	s, err := p.selectStack(ctx)
+	if err != nil {
+		return err
+	}
+
+	tags, err := getStackTags(ctx, s.Workspace(), s.Name())
+	if err != nil {
+		return errors.WithMessage(err, "reading the stack's lock")
+	}
+	for _, k := range []string{lockReasonTag, lockOwnerTag, lockTimeTag} {
+		if _, ok := tags[k]; !ok {
+			continue
+		}
+		if _, err := pulumiStackTag(ctx, s.Workspace(), s.Name(), "rm", k); err != nil {
+			return errors.WithMessage(err, "removing stack tag "+k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+func TestStackLock(t *testing.T) {
+	if lock := stackLock(map[string]string{tagPrefix + "project": "app"}); lock != nil {
+		t.Errorf("stackLock() = %v, want nil when unlocked", lock)
+	}
+
+	tags := map[string]string{
+		tagPrefix + "project": "app",
+		lockReasonTag:         "release freeze",
+		lockOwnerTag:          "dev@example.com",
+		lockTimeTag:           "2022-06-01T10:00:00Z",
+	}
+	want := &types.StackLock{Reason: "release freeze", Owner: "dev@example.com", Time: "2022-06-01T10:00:00Z"}
+	lock := stackLock(tags)
+	if !reflect.DeepEqual(lock, want) {
+		t.Errorf("stackLock() = %v, want %v", lock, want)
+	}
+
+	err := lockedError("update", "prod", lock)
+	for _, s := range []string{"prod", "release freeze", "dev@example.com", "--override-lock"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("lockedError() = %q, missing %q", err, s)
+		}
+	}
+}
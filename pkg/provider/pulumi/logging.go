@@ -29,56 +29,71 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 
 	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/provider/types"
 )
 
-func updateLoggingOpts(log output.Progress) []optup.Option {
+func updateLoggingOpts(log output.Progress, listener types.EventListener, history io.WriteCloser) []optup.Option {
 	upChannel := make(chan events.EngineEvent)
 	opts := []optup.Option{
-		optup.EventStreams(upChannel),
+		optup.EventStreams(eventStreams(upChannel, history)...),
 	}
-	go collectEvents(log, upChannel, "Deploying.. ")
+	go collectEvents(log, newEventEmitter(listener, "up"), upChannel, "Deploying.. ")
 
-	if output.VerboseLevel >= 2 {
+	if output.Verbose(output.VerboseDiagnostics) {
+		// the resource diagnostics are in the progress output
 		piper, pipew := io.Pipe()
 		go output.StdoutToPtermDebug(piper, log, "Deploying.. ")
 
 		opts = append(opts, optup.ProgressStreams(pipew))
 	}
-	if output.VerboseLevel > 2 {
-		var loglevel uint = uint(output.VerboseLevel)
-		opts = append(opts, optup.DebugLogging(debug.LoggingOptions{
-			LogLevel:      &loglevel,
-			LogToStdErr:   true,
-			FlowToPlugins: true,
-		}))
+	if output.Verbose(output.VerboseDebug) {
+		opts = append(opts, optup.DebugLogging(debugLogging()))
 	}
 	return opts
 }
 
-func destroyLoggingOpts(log output.Progress) []optdestroy.Option {
+func destroyLoggingOpts(log output.Progress, listener types.EventListener, history io.WriteCloser, extra ...chan<- events.EngineEvent) []optdestroy.Option {
 	upChannel := make(chan events.EngineEvent)
 	opts := []optdestroy.Option{
-		optdestroy.EventStreams(upChannel),
+		optdestroy.EventStreams(append(eventStreams(upChannel, history), extra...)...),
 	}
-	go collectEvents(log, upChannel, "Deleting.. ")
+	go collectEvents(log, newEventEmitter(listener, "down"), upChannel, "Deleting.. ")
 
-	if output.VerboseLevel >= 2 {
+	if output.Verbose(output.VerboseDiagnostics) {
+		// the resource diagnostics are in the progress output
 		piper, pipew := io.Pipe()
 		go output.StdoutToPtermDebug(piper, log, "Deleting.. ")
 
 		opts = append(opts, optdestroy.ProgressStreams(pipew))
 	}
-	if output.VerboseLevel > 2 {
-		var loglevel uint = uint(output.VerboseLevel)
-		opts = append(opts, optdestroy.DebugLogging(debug.LoggingOptions{
-			LogLevel:      &loglevel,
-			LogToStdErr:   true,
-			FlowToPlugins: true,
-		}))
+	if output.Verbose(output.VerboseDebug) {
+		opts = append(opts, optdestroy.DebugLogging(debugLogging()))
 	}
 	return opts
 }
 
+// debugLogging adds the debug messages of pulumi and the providers to the progress output, rather than
+// stderr, so they are printed with the rest of the output.
+func debugLogging() debug.LoggingOptions {
+	var loglevel uint = uint(output.VerboseLevel)
+	return debug.LoggingOptions{
+		LogLevel:      &loglevel,
+		FlowToPlugins: true,
+		Debug:         true,
+	}
+}
+
+// eventStreams returns the channels the engine events are sent to, adding one to record them in history if set.
+func eventStreams(upChannel chan events.EngineEvent, history io.WriteCloser) []chan<- events.EngineEvent {
+	streams := []chan<- events.EngineEvent{upChannel}
+	if history != nil {
+		historyChannel := make(chan events.EngineEvent)
+		go recordEvents(history, historyChannel)
+		streams = append(streams, historyChannel)
+	}
+	return streams
+}
+
 func stepEventToString(eType string, evt *apitype.StepEventMetadata) string {
 	urnSplit := strings.Split(evt.URN, "::")
 	name := urnSplit[len(urnSplit)-1]
@@ -89,21 +104,43 @@ func stepEventToString(eType string, evt *apitype.StepEventMetadata) string {
 	return fmt.Sprintf("%s/%s", rType, name)
 }
 
-const busyMsg = "%s %d/%d resources (%d failed)"
+const busyMsg = "%s resources (%d failed)"
+
+// eventEmitter forwards structured progress to an optional types.EventListener.
+type eventEmitter struct {
+	listener  types.EventListener
+	operation string
+}
+
+func newEventEmitter(listener types.EventListener, operation string) *eventEmitter {
+	return &eventEmitter{listener: listener, operation: operation}
+}
 
-func collectEvents(log output.Progress, eventChannel <-chan events.EngineEvent, prefix string) {
+func (e *eventEmitter) emit(evt types.Event) {
+	if e.listener == nil {
+		return
+	}
+	evt.Operation = e.operation
+	evt.Time = time.Now()
+	e.listener.OnEvent(evt)
+}
+
+func collectEvents(log output.Progress, emitter *eventEmitter, eventChannel <-chan events.EngineEvent, prefix string) {
 	busyList := map[string]time.Time{}
 
 	busy := 0
 	done := 0
 	failed := 0
 
+	emitter.emit(types.Event{Type: types.EventStarted})
+
 	for {
 		var event events.EngineEvent
 		var ok bool
 
 		event, ok = <-eventChannel
 		if !ok {
+			emitter.emit(types.Event{Type: types.EventFinished, Done: done, Total: busy, Failed: failed})
 			return
 		}
 
@@ -111,25 +148,28 @@ func collectEvents(log output.Progress, eventChannel <-chan events.EngineEvent,
 			busy++
 			lastCreating := stepEventToString("ResourcePreEvent", &event.ResourcePreEvent.Metadata)
 			busyList[lastCreating] = time.Now()
-			log.Busyf(busyMsg, prefix, done, busy, failed)
+			log.Progressf(done, busy, busyMsg, prefix, failed)
+			emitter.emit(types.Event{Type: types.EventResourceBusy, Resource: lastCreating, Done: done, Total: busy, Failed: failed})
 		}
 		if event.ResOutputsEvent != nil {
 			lc := stepEventToString("ResOutputsEvent", &event.ResOutputsEvent.Metadata)
 
+			var elapsed time.Duration
 			if event.ResOutputsEvent.Metadata.Op == apitype.OpSame {
 				log.Debugf("%s\n", lc)
 			} else {
 				if st, ok := busyList[lc]; ok {
 					// if possible print out how long it took
-					d := time.Since(st).Round(time.Second)
-					log.Successf("%s (%s)\n", lc, d.String())
+					elapsed = time.Since(st).Round(time.Second)
+					log.Successf("%s (%s)\n", lc, elapsed.String())
 				} else {
 					log.Successf("%s %t\n", lc, busyList[lc])
 				}
 			}
 
 			done++
-			log.Busyf(busyMsg, prefix, done, busy, failed)
+			log.Progressf(done, busy, busyMsg, prefix, failed)
+			emitter.emit(types.Event{Type: types.EventResourceDone, Resource: lc, Elapsed: elapsed, Done: done, Total: busy, Failed: failed})
 		}
 		if event.ResOpFailedEvent != nil {
 			lc := stepEventToString("ResOpFailedEvent", &event.ResOpFailedEvent.Metadata)
@@ -139,9 +179,10 @@ func collectEvents(log output.Progress, eventChannel <-chan events.EngineEvent,
 
 			done++
 			failed++
+			emitter.emit(types.Event{Type: types.EventResourceFailed, Resource: lc, Done: done, Total: busy, Failed: failed})
 
 			if len(busyList) > 0 {
-				log.Busyf(busyMsg, prefix, done, busy, failed)
+				log.Progressf(done, busy, busyMsg, prefix, failed)
 			}
 		}
 	}
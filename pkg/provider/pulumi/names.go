@@ -0,0 +1,96 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package pulumi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// namedOutputs are the outputs holding the physical names of resources that don't name them "name".
+var namedOutputs = map[string]string{
+	"aws:s3/bucket:Bucket": "bucket",
+}
+
+func validateNaming(naming string) error {
+	switch naming {
+	case "", stack.NamingAuto, stack.NamingStable:
+		return nil
+	default:
+		return fmt.Errorf("naming %s is not supported, use %s or %s", naming, stack.NamingAuto, stack.NamingStable)
+	}
+}
+
+// setStableNames gives the provider the names of the deployed resources, when the stack uses stable naming.
+func (p *pulumiDeployment) setStableNames(ctx context.Context, s *auto.Stack) error {
+	if p.sc.Naming != stack.NamingStable {
+		return nil
+	}
+	namer, ok := p.prov.(common.StableNamer)
+	if !ok {
+		return utils.NewNotSupportedErr("stable naming is not supported on " + p.sc.Provider)
+	}
+
+	_, state, err := exportState(ctx, s)
+	if err != nil {
+		return err
+	}
+	outputs, err := s.Outputs(ctx)
+	if err != nil {
+		return err
+	}
+
+	exported := map[string]string{}
+	for k, v := range outputs {
+		if strings.HasPrefix(k, "name:") {
+			exported[strings.TrimPrefix(k, "name:")] = fmt.Sprint(v.Value)
+		}
+	}
+
+	namer.SetStableNames(common.NewStableNames(deployedNames(state, exported)))
+	return nil
+}
+
+// deployedNames are the physical names of the deployed resources, keyed by common.StableNameKey.
+// The exported names are kept, the names of resources deployed before stable naming was turned on are
+// read from the state, so they are not renamed.
+func deployedNames(state *apitype.DeploymentV3, exported map[string]string) map[string]string {
+	names := map[string]string{}
+	for _, r := range state.Resources {
+		if !r.Custom {
+			continue
+		}
+		output, ok := namedOutputs[string(r.Type)]
+		if !ok {
+			output = "name"
+		}
+		if name, ok := r.Outputs[output].(string); ok && name != "" {
+			names[common.StableNameKey(string(r.Type), r.URN.Name().String())] = name
+		}
+	}
+	for k, v := range exported {
+		names[k] = v
+	}
+	return names
+}
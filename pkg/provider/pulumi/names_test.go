@@ -0,0 +1,55 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package pulumi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+func TestDeployedNames(t *testing.T) {
+	state := &apitype.DeploymentV3{
+		Resources: []apitype.ResourceV3{
+			{URN: stackURN, Type: "pulumi:pulumi:Stack"},
+			{URN: compURN, Type: "nitric:bucket:AwsS3Bucket"},
+			{URN: bucketURN, Type: "aws:s3/bucket:Bucket", Custom: true, Outputs: map[string]interface{}{"bucket": "images-4be01c2"}},
+			{URN: topicURN, Type: "aws:sns/topic:Topic", Custom: true, Outputs: map[string]interface{}{"name": "orders-91ad3f0"}},
+		},
+	}
+	exported := map[string]string{"aws:sns/topic:Topic::orders": "orders"}
+
+	got := deployedNames(state, exported)
+	want := map[string]string{
+		"aws:s3/bucket:Bucket::images": "images-4be01c2",
+		"aws:sns/topic:Topic::orders":  "orders",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("deployedNames() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateNaming(t *testing.T) {
+	for _, naming := range []string{"", "auto", "stable"} {
+		if err := validateNaming(naming); err != nil {
+			t.Errorf("validateNaming(%q) error = %v", naming, err)
+		}
+	}
+	if err := validateNaming("random"); err == nil {
+		t.Error("expected an error for an unknown naming mode")
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+func (p *pulumiDeployment) collector() (common.Collector, error) {
+	c, ok := p.prov.(common.Collector)
+	if !ok {
+		return nil, utils.NewNotSupportedErr("the orphaned resources of " + p.sc.Provider + " stacks can not be found")
+	}
+	return c, nil
+}
+
+// stateIds returns the ids of the resources in the state, along with their arn and id outputs, which is how
+// some providers identify their resources.
+func stateIds(state *apitype.DeploymentV3) []string {
+	ids := []string{}
+	for _, r := range state.Resources {
+		if r.ID != "" {
+			ids = append(ids, string(r.ID))
+		}
+		for _, k := range []string{"arn", "id"} {
+			if v, ok := r.Outputs[k].(string); ok && v != "" {
+				ids = append(ids, v)
+			}
+		}
+	}
+	return ids
+}
+
+func (p *pulumiDeployment) Orphans(log output.Progress) ([]types.Orphan, error) {
+	c, err := p.collector()
+	if err != nil {
+		return nil, err
+	}
+
+	// the state is refreshed first, so resources that were deleted by hand are not counted as in it
+	s, err := p.load(log)
+	if err != nil {
+		return nil, err
+	}
+
+	_, state, err := exportState(context.Background(), s)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Busyf("Finding the resources tagged with the stack")
+	tagged, err := c.TaggedResources(p.proj.Name + "-" + p.sc.Name)
+	if err != nil {
+		return nil, errors.WithMessage(err, "finding the tagged resources")
+	}
+	return common.Orphans(tagged, stateIds(state)), nil
+}
+
+func (p *pulumiDeployment) DeleteOrphans(orphans []types.Orphan, log output.Progress) error {
+	if err := p.writable("delete the orphaned resources of"); err != nil {
+		return err
+	}
+
+	c, err := p.collector()
+	if err != nil {
+		return err
+	}
+	if err := p.prov.Validate(); err != nil {
+		return err
+	}
+
+	errList := utils.NewErrorList()
+	for i, o := range orphans {
+		log.Progressf(i, len(orphans), "Deleting %s", o.ID)
+		if err := c.DeleteOrphan(o); err != nil {
+			errList.Add(errors.WithMessage(err, o.ID))
+		}
+	}
+	return errList.Aggregate()
+}
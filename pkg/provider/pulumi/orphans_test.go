@@ -0,0 +1,40 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+func TestStateIds(t *testing.T) {
+	state := &apitype.DeploymentV3{
+		Resources: []apitype.ResourceV3{
+			{URN: stackURN, Type: "pulumi:pulumi:Stack"},
+			{URN: compURN, Type: "nitric:bucket:AwsS3Bucket", Parent: stackURN},
+			{URN: bucketURN, Type: "aws:s3/bucket:Bucket", ID: "images-1234", Outputs: map[string]interface{}{"arn": "arn:aws:s3:::images-1234"}},
+			{URN: topicURN, Type: "aws:sns/topic:Topic", ID: "arn:aws:sns:us-east-1:123456789012:orders", Outputs: map[string]interface{}{"arn": "arn:aws:sns:us-east-1:123456789012:orders"}},
+		},
+	}
+
+	want := []string{"images-1234", "arn:aws:s3:::images-1234", "arn:aws:sns:us-east-1:123456789012:orders", "arn:aws:sns:us-east-1:123456789012:orders"}
+	if got := stateIds(state); !reflect.DeepEqual(got, want) {
+		t.Errorf("stateIds() = %v, want %v", got, want)
+	}
+}
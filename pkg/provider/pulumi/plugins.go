@@ -0,0 +1,84 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/aws"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/azure"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/common"
+	"github.com/nitrictech/cli/pkg/provider/pulumi/gcp"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// Plugins returns the plugins the providers deploy with, each once, sorted by name.
+func Plugins() []common.Plugin {
+	proj := &project.Project{}
+	provs := []common.PulumiProvider{
+		aws.New(proj, &stack.Config{Provider: stack.Aws}, map[string]string{}),
+		azure.New(proj, &stack.Config{Provider: stack.Azure}, map[string]string{}),
+		gcp.New(proj, &stack.Config{Provider: stack.Gcp}, map[string]string{}),
+	}
+
+	plugins := []common.Plugin{}
+	seen := map[string]bool{}
+	for _, prov := range provs {
+		for _, plug := range prov.Plugins() {
+			if !seen[plug.String()] {
+				seen[plug.String()] = true
+				plugins = append(plugins, plug)
+			}
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+	return plugins
+}
+
+// InstallPlugin installs the plugin when it is missing, as the first deployment to a provider would, so a new
+// machine can be checked before then. Offline, it only checks the plugin is installed.
+func InstallPlugin(plug common.Plugin) error {
+	ctx := context.Background()
+	ws, err := auto.NewLocalWorkspace(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "NewLocalWorkspace")
+	}
+
+	if utils.Offline {
+		installed, err := ws.ListPlugins(ctx)
+		if err != nil {
+			return errors.WithMessage(err, "ListPlugins")
+		}
+		for _, p := range installed {
+			if p.Name == plug.Name && p.Version != nil && p.Version.String() == strings.TrimPrefix(plug.Version, "v") {
+				return nil
+			}
+		}
+		return utils.RequireNetwork("installing the Pulumi plugin " + plug.String())
+	}
+
+	return errors.WithMessage(ws.InstallPlugin(ctx, plug.Name, plug.Version), "InstallPlugin "+plug.String())
+}
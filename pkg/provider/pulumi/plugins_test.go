@@ -0,0 +1,45 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestPlugins(t *testing.T) {
+	plugins := Plugins()
+
+	names := []string{}
+	count := map[string]int{}
+	for _, plug := range plugins {
+		if plug.Version == "" {
+			t.Errorf("plugin %s has no version", plug.Name)
+		}
+		names = append(names, plug.Name)
+		count[plug.Name]++
+	}
+
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("Plugins() = %v, want them sorted by name", names)
+	}
+	for _, name := range []string{"aws", "azure-native", "gcp", "random"} {
+		if count[name] != 1 {
+			t.Errorf("Plugins() has %s %d times, want it once", name, count[name])
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// maxNameLen is the longest project or stack name the Pulumi backends accept.
+const maxNameLen = 100
+
+var pulumiName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// validateNames checks the Pulumi project and stack names computed from the nitric project and stack.
+func validateNames(projectName, stackName string) error {
+	for _, n := range []struct{ kind, name string }{{"project", projectName}, {"stack", stackName}} {
+		if len(n.name) > maxNameLen {
+			return fmt.Errorf("the Pulumi %s name %q is longer than %d characters, please use a shorter name", n.kind, n.name, maxNameLen)
+		}
+		if !pulumiName.MatchString(n.name) {
+			return fmt.Errorf("the Pulumi %s name %q may only contain alphanumerics, hyphens, underscores and periods", n.kind, n.name)
+		}
+	}
+	return nil
+}
+
+// Preflight checks the stack can be changed, and the names, passphrase and backend login, so these fail
+// before the slow gather and build steps rather than at the final deploy step.
+func (p *pulumiDeployment) Preflight() error {
+	if err := p.writable("update"); err != nil {
+		return err
+	}
+
+	stackName := p.proj.Name + "-" + p.sc.Name
+	if err := validateNames(p.proj.Name, stackName); err != nil {
+		return err
+	}
+
+	_, hasPassphrase := os.LookupEnv("PULUMI_CONFIG_PASSPHRASE")
+	_, hasPassphraseFile := os.LookupEnv("PULUMI_CONFIG_PASSPHRASE_FILE")
+	if !hasPassphrase && !hasPassphraseFile {
+		return errors.New("PULUMI_CONFIG_PASSPHRASE or PULUMI_CONFIG_PASSPHRASE_FILE must be set, it is used to encrypt the stack's secrets")
+	}
+
+	ctx := context.Background()
+	ws, err := auto.NewLocalWorkspace(ctx,
+		auto.SecretsProvider("passphrase"),
+		auto.Project(workspace.Project{
+			Name:    tokens.PackageName(p.proj.Name),
+			Runtime: workspace.NewProjectRuntimeInfo("go", nil),
+			Main:    p.proj.Dir,
+		}))
+	if err != nil {
+		return errors.WithMessage(err, "NewLocalWorkspace")
+	}
+
+	user, err := ws.WhoAmI(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "not logged in to a Pulumi backend, please run 'pulumi login' (or 'pulumi login --local' to keep the state on this machine)")
+	}
+
+	// listing the project's stacks checks the user can access the organization the stack will be created in
+	_, err = ws.ListStacks(ctx)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("user %s can't access the stacks of project %s, please check the organization membership of the logged in user", user, p.proj.Name))
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		project string
+		stack   string
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			project: "my-app",
+			stack:   "my-app-aws.prod_1",
+		},
+		{
+			name:    "invalid project",
+			project: "my app",
+			stack:   "my app-aws",
+			wantErr: `the Pulumi project name "my app" may only contain alphanumerics, hyphens, underscores and periods`,
+		},
+		{
+			name:    "stack too long",
+			project: "app",
+			stack:   "app-" + strings.Repeat("s", maxNameLen),
+			wantErr: "is longer than 100 characters",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNames(tt.project, tt.stack)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateNames() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateNames() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optpreview"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+
+	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+// securityTypes match the resource types that grant identities permissions, for reviewing them separately.
+var securityTypes = []*regexp.Regexp{
+	// aws roles, policies and the resource policies that allow other services in
+	regexp.MustCompile(`^aws:iam/`),
+	regexp.MustCompile(`^aws:lambda/permission:`),
+	regexp.MustCompile(`^aws:(s3/bucketPolicy|sns/topicPolicy|sqs/queuePolicy|ecr/repositoryPolicy|secretsmanager/secretPolicy|kms/grant):`),
+	// azure role assignments and definitions, managed identities and service principals
+	regexp.MustCompile(`^azure-native:authorization:`),
+	regexp.MustCompile(`^azure-native:managedidentity:`),
+	regexp.MustCompile(`^azuread:`),
+	// gcp service accounts and the IAM members, bindings and policies of every resource
+	regexp.MustCompile(`^gcp:serviceAccount/`),
+	regexp.MustCompile(`(?i)^gcp:.*:[a-z]*iam(member|binding|policy|customrole)$`),
+}
+
+// previewOps are the operations that change a resource, the others only read it.
+var previewOps = map[apitype.OpType]bool{
+	apitype.OpCreate:  true,
+	apitype.OpUpdate:  true,
+	apitype.OpReplace: true,
+	apitype.OpDelete:  true,
+}
+
+// isSecurityType returns true if resources of the type grant permissions.
+func isSecurityType(resourceType string) bool {
+	for _, re := range securityTypes {
+		if re.MatchString(resourceType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Preview returns the changes an update would make to the stack, without making them.
+func (p *pulumiDeployment) Preview(log output.Progress) ([]types.Change, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	s, err := p.load(log)
+	if err != nil {
+		return nil, errors.WithMessage(err, "loading pulumi stack")
+	}
+
+	if err := p.setStableNames(context.Background(), s); err != nil {
+		return nil, errors.WithMessage(err, "reading the names of the deployed resources")
+	}
+
+	defer p.prov.CleanUp()
+
+	if p.configOnly {
+		log.Busyf("Previewing the configuration changes to the functions")
+		if err := p.reuseImages(context.Background(), s); err != nil {
+			return nil, err
+		}
+		_, changes, err := p.configChanges(s)
+		return changes, err
+	}
+
+	log.Busyf("Previewing the changes to the stack")
+	return p.preview(s)
+}
+
+// preview returns the changes an update of the loaded stack would make.
+func (p *pulumiDeployment) preview(s *auto.Stack) ([]types.Change, error) {
+	previewEvents := make(chan events.EngineEvent)
+	done := make(chan []types.Change)
+	go collectChanges(previewEvents, done)
+
+	_, err := s.Preview(context.Background(), optpreview.EventStreams(previewEvents))
+	changes := <-done
+	if err != nil {
+		return nil, errors.WithMessage(err, "Preview")
+	}
+	return changes, nil
+}
+
+// checkPlan returns an error when updating the loaded stack would no longer make the planned changes.
+func (p *pulumiDeployment) checkPlan(s *auto.Stack, log output.Progress) error {
+	log.Busyf("Checking the stack still matches the plan")
+	changes, err := p.preview(s)
+	if err != nil {
+		return err
+	}
+	if diverged := p.plan.Diverged(changes); len(diverged) > 0 {
+		return fmt.Errorf("the stack has changed since the plan was saved %s, preview it again:\n  %s", p.plan.Created.Format(time.RFC3339), strings.Join(diverged, "\n  "))
+	}
+	return nil
+}
+
+// collectChanges sends the resources that the preview would change to done once the channel is closed.
+func collectChanges(eventChannel <-chan events.EngineEvent, done chan<- []types.Change) {
+	changes := []types.Change{}
+	for event := range eventChannel {
+		if event.ResourcePreEvent == nil {
+			continue
+		}
+		md := event.ResourcePreEvent.Metadata
+		if !previewOps[md.Op] || strings.HasPrefix(md.Type, "pulumi:") {
+			continue
+		}
+
+		urnSplit := strings.Split(md.URN, "::")
+		changes = append(changes, types.Change{
+			Op:       string(md.Op),
+			Type:     md.Type,
+			Name:     urnSplit[len(urnSplit)-1],
+			Diffs:    md.Diffs,
+			Security: isSecurityType(md.Type),
+		})
+	}
+	done <- changes
+}
@@ -0,0 +1,87 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+func Test_isSecurityType(t *testing.T) {
+	tests := []struct {
+		resourceType string
+		want         bool
+	}{
+		{resourceType: "aws:iam/role:Role", want: true},
+		{resourceType: "aws:iam/rolePolicyAttachment:RolePolicyAttachment", want: true},
+		{resourceType: "aws:lambda/permission:Permission", want: true},
+		{resourceType: "aws:sns/topicPolicy:TopicPolicy", want: true},
+		{resourceType: "aws:lambda/function:Function", want: false},
+		{resourceType: "aws:appautoscaling/policy:Policy", want: false},
+		{resourceType: "azure-native:authorization:RoleAssignment", want: true},
+		{resourceType: "azuread:index/servicePrincipal:ServicePrincipal", want: true},
+		{resourceType: "azure-native:apimanagement:ApiPolicy", want: false},
+		{resourceType: "gcp:serviceAccount/account:Account", want: true},
+		{resourceType: "gcp:projects/iAMMember:IAMMember", want: true},
+		{resourceType: "gcp:cloudrun/iamMember:IamMember", want: true},
+		{resourceType: "gcp:pubsub/topicIAMMember:TopicIAMMember", want: true},
+		{resourceType: "gcp:pubsub/topic:Topic", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resourceType, func(t *testing.T) {
+			if got := isSecurityType(tt.resourceType); got != tt.want {
+				t.Errorf("isSecurityType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_collectChanges(t *testing.T) {
+	pre := func(op apitype.OpType, typ, name string, diffs ...string) events.EngineEvent {
+		return events.EngineEvent{EngineEvent: apitype.EngineEvent{
+			ResourcePreEvent: &apitype.ResourcePreEvent{Metadata: apitype.StepEventMetadata{
+				Op:    op,
+				Type:  typ,
+				URN:   "urn:pulumi:dev::shop::" + typ + "::" + name,
+				Diffs: diffs,
+			}},
+		}}
+	}
+
+	eventChannel := make(chan events.EngineEvent)
+	done := make(chan []types.Change)
+	go collectChanges(eventChannel, done)
+
+	eventChannel <- pre(apitype.OpSame, "aws:lambda/function:Function", "orders")
+	eventChannel <- pre(apitype.OpCreate, "pulumi:pulumi:Stack", "shop-dev")
+	eventChannel <- pre(apitype.OpUpdate, "aws:iam/rolePolicy:RolePolicy", "orders-policy", "policy")
+	eventChannel <- pre(apitype.OpCreate, "aws:sqs/queue:Queue", "jobs")
+	close(eventChannel)
+
+	want := []types.Change{
+		{Op: "update", Type: "aws:iam/rolePolicy:RolePolicy", Name: "orders-policy", Diffs: []string{"policy"}, Security: true},
+		{Op: "create", Type: "aws:sqs/queue:Queue", Name: "jobs"},
+	}
+	if got := <-done; !reflect.DeepEqual(got, want) {
+		t.Errorf("collectChanges() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package pulumi
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+func exportState(ctx context.Context, s *auto.Stack) (*apitype.UntypedDeployment, *apitype.DeploymentV3, error) {
+	dep, err := s.Export(ctx)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "exporting stack state")
+	}
+
+	state := &apitype.DeploymentV3{}
+	if len(dep.Deployment) > 0 {
+		if err := json.Unmarshal(dep.Deployment, state); err != nil {
+			return nil, nil, errors.WithMessage(err, "decoding stack state")
+		}
+	}
+	return &dep, state, nil
+}
+
+// protectedResources returns the URNs of the resources that pulumi will refuse to delete.
+func protectedResources(ctx context.Context, s *auto.Stack) ([]string, error) {
+	_, state, err := exportState(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	return protectedURNs(state), nil
+}
+
+func protectedURNs(state *apitype.DeploymentV3) []string {
+	urns := []string{}
+	for _, r := range state.Resources {
+		if r.Protect {
+			urns = append(urns, string(r.URN))
+		}
+	}
+	return urns
+}
+
+// unprotect clears the protect flag on every resource in the stack's state so that
+// stateful resources (and their data) can be deleted or replaced.
+func unprotect(ctx context.Context, s *auto.Stack) error {
+	dep, state, err := exportState(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	if !clearProtect(state) {
+		return nil
+	}
+
+	dep.Deployment, err = json.Marshal(state)
+	if err != nil {
+		return errors.WithMessage(err, "encoding stack state")
+	}
+	return errors.WithMessage(s.Import(ctx, *dep), "importing stack state")
+}
+
+// clearProtect clears the protect flag of the resources in state, returning false when none had it.
+func clearProtect(state *apitype.DeploymentV3) bool {
+	changed := false
+	for i := range state.Resources {
+		if state.Resources[i].Protect {
+			state.Resources[i].Protect = false
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,103 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+func TestProtectedURNs(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources []apitype.ResourceV3
+		want      []string
+	}{
+		{
+			name: "empty state",
+			want: []string{},
+		},
+		{
+			name: "none protected",
+			resources: []apitype.ResourceV3{
+				{URN: stackURN},
+				{URN: topicURN},
+			},
+			want: []string{},
+		},
+		{
+			name: "protected in state order",
+			resources: []apitype.ResourceV3{
+				{URN: stackURN},
+				{URN: topicURN, Protect: true},
+				{URN: bucketURN, Protect: true},
+			},
+			want: []string{topicURN, bucketURN},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := protectedURNs(&apitype.DeploymentV3{Resources: tt.resources})
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
+
+func TestClearProtect(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources []apitype.ResourceV3
+		want      bool
+	}{
+		{
+			name: "empty state",
+			want: false,
+		},
+		{
+			name: "none protected",
+			resources: []apitype.ResourceV3{
+				{URN: stackURN},
+				{URN: topicURN},
+			},
+			want: false,
+		},
+		{
+			name: "some protected",
+			resources: []apitype.ResourceV3{
+				{URN: stackURN},
+				{URN: bucketURN, Protect: true},
+				{URN: topicURN},
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &apitype.DeploymentV3{Resources: tt.resources}
+			if got := clearProtect(state); got != tt.want {
+				t.Errorf("clearProtect() = %v, want %v", got, tt.want)
+			}
+			if urns := protectedURNs(state); len(urns) != 0 {
+				t.Errorf("resources still protected %v", urns)
+			}
+		})
+	}
+}
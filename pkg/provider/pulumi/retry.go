@@ -0,0 +1,120 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"regexp"
+	"time"
+)
+
+const maxUpRetries = 3
+
+// retryDelay is the wait before the first retry, doubling for each one after.
+var retryDelay = 15 * time.Second
+
+// transientErrors match provider errors that are expected to succeed if tried again, such as
+// rate limiting and IAM changes that haven't propagated yet.
+var transientErrors = []*regexp.Regexp{
+	// rate limiting (e.g. ACR, ARM and AWS API throttling)
+	regexp.MustCompile(`(?i)too ?many ?requests`),
+	regexp.MustCompile(`\b429\b`),
+	regexp.MustCompile(`(?i)throttl(ed|ing)`),
+	regexp.MustCompile(`(?i)rate exceeded`),
+	regexp.MustCompile(`RequestLimitExceeded`),
+	// service hiccups
+	regexp.MustCompile(`(?i)service ?unavailable`),
+	regexp.MustCompile(`(?i)connection reset by peer`),
+	// eventually consistent IAM
+	regexp.MustCompile(`role defined for the function cannot be assumed`),
+	regexp.MustCompile(`InvalidParameterValueException: The role`),
+	regexp.MustCompile(`PrincipalNotFound`),
+	regexp.MustCompile(`does not exist in the directory`),
+	regexp.MustCompile(`(?i)service account .* does not exist`),
+}
+
+// registryAuthErrors match image pushes that failed because the registry credentials expired,
+// they pass on a retry once the credentials are refreshed.
+var registryAuthErrors = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)authorization token has expired`),
+	regexp.MustCompile(`(?i)no basic auth credentials`),
+	regexp.MustCompile(`(?i)unauthorized: authentication required`),
+	regexp.MustCompile(`(?i)invalid authentication credentials`),
+	regexp.MustCompile(`(?i)denied: .*(expired|token)`),
+}
+
+// registryQuotaErrors match image pushes that failed on a registry limit that a retry won't fix, with how to fix it.
+var registryQuotaErrors = []struct {
+	re          *regexp.Regexp
+	remediation string
+}{
+	{
+		re:          regexp.MustCompile(`LimitExceededException`),
+		remediation: "the ECR repository has reached its image limit, expire old images with a lifecycle policy or request a quota increase",
+	},
+	{
+		re:          regexp.MustCompile(`(?is)azurecr\.io.*quota`),
+		remediation: "the ACR registry has run out of storage, delete old images or move the registry to a larger sku",
+	},
+	{
+		re:          regexp.MustCompile(`(?is)gcr\.io.*quota`),
+		remediation: "the GCR project has reached its storage quota, delete old images or request a quota increase",
+	},
+}
+
+// isTransient returns true if the deployment failed with an error that is likely to pass on a retry.
+func isTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, re := range transientErrors {
+		if re.MatchString(err.Error()) {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns how long to wait before the given retry (starting at 1).
+func backoff(retry int) time.Duration {
+	return retryDelay * time.Duration(1<<(retry-1))
+}
+
+// isRegistryAuth returns true if the deployment failed pushing an image with expired registry credentials.
+func isRegistryAuth(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, re := range registryAuthErrors {
+		if re.MatchString(err.Error()) {
+			return true
+		}
+	}
+	return false
+}
+
+// registryRemediation returns how to fix a push that failed on a registry limit, or an empty string for other errors.
+func registryRemediation(err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, q := range registryQuotaErrors {
+		if q.re.MatchString(err.Error()) {
+			return q.remediation
+		}
+	}
+	return ""
+}
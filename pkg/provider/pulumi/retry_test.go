@@ -0,0 +1,120 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_isTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil",
+			want: false,
+		},
+		{
+			name: "acr",
+			err:  errors.New("error: pushing image: received unexpected HTTP status: 429 Too Many Requests"),
+			want: true,
+		},
+		{
+			name: "aws throttling",
+			err:  errors.New("creating Lambda Function: ThrottlingException: Rate exceeded"),
+			want: true,
+		},
+		{
+			name: "iam race",
+			err:  errors.New("InvalidParameterValueException: The role defined for the function cannot be assumed by Lambda."),
+			want: true,
+		},
+		{
+			name: "invalid config",
+			err:  errors.New("ValidationError: Member must have length less than or equal to 64"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransient(tt.err); got != tt.want {
+				t.Errorf("isTransient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isRegistryAuth(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "ecr",
+			err:  errors.New("error: denied: Your authorization token has expired. Reauthenticate and try again."),
+			want: true,
+		},
+		{
+			name: "gcr",
+			err:  errors.New("unauthorized: You don't have the needed permissions, Request had invalid authentication credentials."),
+			want: true,
+		},
+		{
+			name: "throttled",
+			err:  errors.New("received unexpected HTTP status: 429 Too Many Requests"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRegistryAuth(tt.err); got != tt.want {
+				t.Errorf("isRegistryAuth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_registryRemediation(t *testing.T) {
+	err := errors.New("pushing 1234.dkr.ecr.us-east-1.amazonaws.com/shop-orders: LimitExceededException: image limit reached")
+	if got := registryRemediation(err); !strings.Contains(got, "lifecycle policy") {
+		t.Errorf("registryRemediation() = %q, want the ECR remediation", got)
+	}
+
+	err = errors.New("pushing shop.azurecr.io/shop-orders: denied: storage\nquota exceeded")
+	if got := registryRemediation(err); !strings.Contains(got, "ACR") {
+		t.Errorf("registryRemediation() = %q, want the ACR remediation", got)
+	}
+
+	if got := registryRemediation(errors.New("ValidationError")); got != "" {
+		t.Errorf("registryRemediation() = %q, want none", got)
+	}
+}
+
+func Test_backoff(t *testing.T) {
+	want := []time.Duration{retryDelay, 2 * retryDelay, 4 * retryDelay}
+	for i, w := range want {
+		if got := backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
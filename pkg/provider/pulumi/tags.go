@@ -0,0 +1,109 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+const tagPrefix = "nitric:"
+
+// stackTags describe where a deployment came from, so the backend's stack listing is self-describing.
+func stackTags(p *project.Project, sc *stack.Config) map[string]string {
+	tags := map[string]string{
+		tagPrefix + "project":     p.Name,
+		tagPrefix + "stack":       sc.Name,
+		tagPrefix + "provider":    sc.Provider,
+		tagPrefix + "cli-version": utils.Version,
+	}
+	if sc.Region != "" {
+		tags[tagPrefix+"region"] = sc.Region
+	}
+	if commit := utils.GitCommit(p.Dir); commit != "" {
+		tags[tagPrefix+"git-commit"] = commit
+	}
+	return tags
+}
+
+// tagTarget is where the stack is deployed to, e.g. aws/us-east-1.
+func tagTarget(tags map[string]string) string {
+	target := tags[tagPrefix+"provider"]
+	if r := tags[tagPrefix+"region"]; r != "" {
+		target += "/" + r
+	}
+	return target
+}
+
+// The automation api of the pulumi sdk we use can't manage stack tags, so the cli is used.
+func pulumiStackTag(ctx context.Context, ws auto.Workspace, stackName string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "pulumi", append(append([]string{"stack", "tag"}, args...), "--stack", stackName, "--non-interactive")...)
+	cmd.Dir = ws.WorkDir()
+	out, err := cmd.Output()
+	if ee, ok := err.(*exec.ExitError); ok {
+		return nil, errors.WithMessage(err, strings.TrimSpace(string(ee.Stderr)))
+	}
+	return out, err
+}
+
+// setStackTags sets the tags that have changed on the stack.
+func setStackTags(ctx context.Context, s *auto.Stack, tags map[string]string) error {
+	current, err := getStackTags(ctx, s.Workspace(), s.Name())
+	if err != nil {
+		return err
+	}
+
+	for k, v := range tags {
+		if current[k] == v {
+			continue
+		}
+		if _, err := pulumiStackTag(ctx, s.Workspace(), s.Name(), "set", k, v); err != nil {
+			return errors.WithMessage(err, "setting stack tag "+k)
+		}
+	}
+	return nil
+}
+
+// getStackTags returns the nitric tags of the stack.
+func getStackTags(ctx context.Context, ws auto.Workspace, stackName string) (map[string]string, error) {
+	out, err := pulumiStackTag(ctx, ws, stackName, "ls", "--json")
+	if err != nil {
+		return nil, err
+	}
+
+	all := map[string]string{}
+	if err := json.Unmarshal(out, &all); err != nil {
+		return nil, errors.WithMessage(err, "reading stack tags")
+	}
+
+	tags := map[string]string{}
+	for k, v := range all {
+		if strings.HasPrefix(k, tagPrefix) {
+			tags[k] = v
+		}
+	}
+	return tags, nil
+}
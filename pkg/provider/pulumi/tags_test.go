@@ -0,0 +1,51 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/stack"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+func TestStackTags(t *testing.T) {
+	p := &project.Project{Name: "shop", Dir: t.TempDir()}
+	sc := &stack.Config{Name: "prod", Provider: "aws", Region: "us-east-1"}
+
+	want := map[string]string{
+		"nitric:project":     "shop",
+		"nitric:stack":       "prod",
+		"nitric:provider":    "aws",
+		"nitric:region":      "us-east-1",
+		"nitric:cli-version": utils.Version,
+	}
+	got := stackTags(p, sc)
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+
+	if target := tagTarget(got); target != "aws/us-east-1" {
+		t.Errorf("expected target aws/us-east-1, got %s", target)
+	}
+	if target := tagTarget(map[string]string{"nitric:provider": "azure"}); target != "azure" {
+		t.Errorf("expected target azure, got %s", target)
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package test is a conformance suite for the pulumi providers, each provider deploys the same canonical
+// project with pulumi mocks and the suite checks the resources, env vars and permissions it creates, so a
+// feature added to one provider can't silently regress another.
+//
+// Providers run it from their own tests, run them all with:
+//
+//	go test ./pkg/provider/... -run Conformance
+package test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/nitrictech/cli/pkg/project"
+	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
+)
+
+// The names of the canonical project and its resources.
+const (
+	ProjectName = "conformance"
+	StackName   = "conformance-deploy"
+	Function    = "runner"
+	Topic       = "sales"
+	Bucket      = "money"
+	Queue       = "checkout"
+	Collection  = "customer"
+	Secret      = "api-key"
+	Schedule    = "daily"
+)
+
+// RequiredEnv are the env vars every provider gives the functions.
+var RequiredEnv = []string{"NITRIC_STACK", "NITRIC_FUNCTION"}
+
+// Project is the canonical project, a function triggered by a topic that reads from a bucket, with a queue,
+// collection, secret and a schedule publishing to the topic.
+func Project() *project.Project {
+	s := project.New(&project.Config{Name: ProjectName, Dir: "."})
+	s.Topics = map[string]project.Topic{Topic: {}}
+	s.Buckets = map[string]project.Bucket{Bucket: {}}
+	s.Queues = map[string]project.Queue{Queue: {}}
+	s.Collections = map[string]project.Collection{Collection: {}}
+	s.Secrets = map[string]project.Secret{Secret: {}}
+	s.Schedules = map[string]project.Schedule{
+		Schedule: {
+			Expression: "@daily",
+			Target:     project.ScheduleTarget{Type: "topic", Name: Topic},
+			Event:      project.ScheduleEvent{PayloadType: "?"},
+		},
+	}
+	s.Functions = map[string]project.Function{
+		Function: {
+			Handler: "functions/" + Function + "/main.go",
+			ComputeUnit: project.ComputeUnit{
+				Name:     Function,
+				Triggers: project.Triggers{Topics: []string{Topic}},
+			},
+		},
+	}
+	s.Policies = []*v1.PolicyResource{
+		{
+			Principals: []*v1.Resource{{Type: v1.ResourceType_Function, Name: Function}},
+			Actions:    []v1.Action{v1.Action_BucketFileGet, v1.Action_BucketFileList},
+			Resources:  []*v1.Resource{{Type: v1.ResourceType_Bucket, Name: Bucket}},
+		},
+	}
+	return s
+}
+
+// Resource is a resource the provider registered with the mocks.
+type Resource struct {
+	Type   string
+	Name   string
+	Inputs map[string]interface{}
+}
+
+// NitricName is the name of the project resource the resource is tagged with.
+func (r Resource) NitricName() string {
+	for _, k := range []string{"tags", "labels"} {
+		if tags, ok := r.Inputs[k].(map[string]interface{}); ok {
+			if name, ok := tags["x-nitric-name"].(string); ok {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// Mocks are pulumi mocks that record the resources registered.
+type Mocks struct {
+	// Outputs are added to the outputs of each resource, e.g. the url of a service
+	Outputs func(typ, name string) map[string]interface{}
+
+	lock      sync.Mutex
+	resources []Resource
+}
+
+var _ pulumi.MockResourceMonitor = &Mocks{}
+
+func (m *Mocks) NewResource(args pulumi.MockResourceArgs) (string, resource.PropertyMap, error) {
+	m.lock.Lock()
+	m.resources = append(m.resources, Resource{Type: args.TypeToken, Name: args.Name, Inputs: args.Inputs.Mappable()})
+	m.lock.Unlock()
+
+	outputs := args.Inputs.Mappable()
+	if m.Outputs != nil {
+		for k, v := range m.Outputs(args.TypeToken, args.Name) {
+			outputs[k] = v
+		}
+	}
+	outputs["name"] = args.Name
+	return args.Name + "_id", resource.NewPropertyMapFromMap(outputs), nil
+}
+
+func (m *Mocks) Call(args pulumi.MockCallArgs) (resource.PropertyMap, error) {
+	return resource.NewPropertyMapFromMap(map[string]interface{}{}), nil
+}
+
+// Resources returns the resources registered of the type.
+func (m *Mocks) Resources(typ string) []Resource {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	found := []Resource{}
+	for _, r := range m.resources {
+		if r.Type == typ {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+// Conformance is how a provider represents the canonical project.
+type Conformance struct {
+	// Deploy deploys the canonical project with the provider
+	Deploy func(ctx *pulumi.Context) error
+	// Mocks the deployment runs with
+	Mocks *Mocks
+	// Types are the resource types created for the project's topics, buckets, queues, collections and secrets,
+	// keyed by topic, bucket, queue, collection or secret, kinds without resources of their own are left out
+	Types map[string]string
+	// Compute is the resource type that runs the function
+	Compute string
+	// Env returns the env vars of a compute resource
+	Env func(r Resource) map[string]interface{}
+	// Grants is true when the resource grants the function access to the bucket
+	Grants func(r Resource, function, bucket string) bool
+}
+
+// Run deploys the canonical project and checks the provider created its resources, gave the function the
+// required env vars and granted it the project's permissions.
+func Run(t *testing.T, c Conformance) {
+	if err := pulumi.RunErr(c.Deploy, pulumi.WithMocks(ProjectName, StackName, c.Mocks)); err != nil {
+		t.Fatalf("deploying the canonical project: %v", err)
+	}
+
+	t.Run("resources", func(t *testing.T) {
+		names := map[string]string{"topic": Topic, "bucket": Bucket, "queue": Queue, "collection": Collection, "secret": Secret}
+		for kind, typ := range c.Types {
+			if _, ok := find(c.Mocks.Resources(typ), names[kind]); !ok {
+				t.Errorf("no %s created for the %s %s", typ, kind, names[kind])
+			}
+		}
+	})
+
+	t.Run("env", func(t *testing.T) {
+		r, ok := find(c.Mocks.Resources(c.Compute), Function)
+		if !ok {
+			t.Fatalf("no %s created for the function %s", c.Compute, Function)
+		}
+		env := c.Env(r)
+		for _, k := range RequiredEnv {
+			if _, ok := env[k]; !ok {
+				t.Errorf("the function %s is missing the env var %s", Function, k)
+			}
+		}
+	})
+
+	t.Run("permissions", func(t *testing.T) {
+		c.Mocks.lock.Lock()
+		defer c.Mocks.lock.Unlock()
+
+		for _, r := range c.Mocks.resources {
+			if c.Grants(r, Function, Bucket) {
+				return
+			}
+		}
+		t.Errorf("the function %s is not granted access to the bucket %s", Function, Bucket)
+	})
+}
+
+// find returns the resource tagged with or named after the project resource.
+func find(resources []Resource, name string) (Resource, bool) {
+	for _, r := range resources {
+		if r.NitricName() == name || r.Name == name {
+			return r, true
+		}
+	}
+	return Resource{}, false
+}
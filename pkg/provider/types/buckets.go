@@ -0,0 +1,39 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"io"
+	"time"
+)
+
+// BucketFile is a file stored in a bucket.
+type BucketFile struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// BucketStore reads and writes the files of a project's buckets, either locally or in a deployed stack.
+// Buckets are referred to by their name in the project, not the name they were deployed with.
+type BucketStore interface {
+	List(bucket, prefix string) ([]*BucketFile, error)
+	Read(bucket, key string, w io.Writer) error
+	Write(bucket, key string, r io.ReadSeeker) error
+	// Empty deletes every file in the bucket, returning the number deleted.
+	Empty(bucket string) (int, error)
+}
@@ -0,0 +1,97 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Change is a resource that updating the stack would create, update, replace or delete.
+type Change struct {
+	Op   string `json:"op"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// The properties that differ, for updates and replacements
+	Diffs []string `json:"diffs,omitempty"`
+	// Security changes are to identities and permissions, e.g. roles, policies and role assignments
+	Security bool `json:"security"`
+}
+
+// Plan is a preview saved so the same changes can be applied later, e.g. once they have been approved.
+type Plan struct {
+	Stack   string    `json:"stack"`
+	Created time.Time `json:"created"`
+	Changes []Change  `json:"changes"`
+}
+
+// ReadPlan reads a plan saved by stack preview --save-plan.
+func ReadPlan(file string) (*Plan, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.WithMessage(err, "reading the plan")
+	}
+	p := &Plan{}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, errors.WithMessage(err, file)
+	}
+	return p, nil
+}
+
+func (p *Plan) ToFile(file string) error {
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, b, 0644)
+}
+
+func (c Change) String() string {
+	s := c.Op + " " + c.Type + " " + c.Name
+	if len(c.Diffs) > 0 {
+		diffs := append([]string{}, c.Diffs...)
+		sort.Strings(diffs)
+		s += " (" + strings.Join(diffs, ", ") + ")"
+	}
+	return s
+}
+
+// Diverged returns how the changes differ from those planned, it is empty when they are the same.
+func (p *Plan) Diverged(changes []Change) []string {
+	planned := map[string]bool{}
+	for _, c := range p.Changes {
+		planned[c.String()] = true
+	}
+
+	diverged := []string{}
+	for _, c := range changes {
+		if !planned[c.String()] {
+			diverged = append(diverged, "not planned: "+c.String())
+		}
+		delete(planned, c.String())
+	}
+	for c := range planned {
+		diverged = append(diverged, "no longer needed: "+c)
+	}
+	sort.Strings(diverged)
+	return diverged
+}
@@ -0,0 +1,52 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlan_Diverged(t *testing.T) {
+	plan := &Plan{
+		Stack: "aws",
+		Changes: []Change{
+			{Op: "update", Type: "aws:iam/rolePolicy:RolePolicy", Name: "orders-policy", Diffs: []string{"policy", "name"}},
+			{Op: "create", Type: "aws:sqs/queue:Queue", Name: "jobs"},
+		},
+	}
+
+	same := []Change{
+		{Op: "create", Type: "aws:sqs/queue:Queue", Name: "jobs"},
+		{Op: "update", Type: "aws:iam/rolePolicy:RolePolicy", Name: "orders-policy", Diffs: []string{"name", "policy"}},
+	}
+	if got := plan.Diverged(same); len(got) != 0 {
+		t.Errorf("Diverged() = %v, want none", got)
+	}
+
+	changed := []Change{
+		{Op: "replace", Type: "aws:sqs/queue:Queue", Name: "jobs", Diffs: []string{"fifoQueue"}},
+		{Op: "update", Type: "aws:iam/rolePolicy:RolePolicy", Name: "orders-policy", Diffs: []string{"name", "policy"}},
+	}
+	want := []string{
+		"no longer needed: create aws:sqs/queue:Queue jobs",
+		"not planned: replace aws:sqs/queue:Queue jobs (fifoQueue)",
+	}
+	if got := plan.Diverged(changed); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diverged() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// DeployerRole is a role with only the permissions a provider needs to deploy stacks, so they are not
+// deployed with owner credentials.
+type DeployerRole struct {
+	Name string `json:"name"`
+	// The permissions in the provider's format: an IAM policy, a custom role definition or the roles to grant
+	Policy string `json:"policy"`
+	// The id of the role, once it is created
+	ID string `json:"id,omitempty"`
+	// The commands that create the role, when it is not created by the cli
+	Instructions []string `json:"instructions,omitempty"`
+}
@@ -0,0 +1,32 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// Document is a document of a top level collection.
+type Document struct {
+	Id      string                 `json:"id"`
+	Content map[string]interface{} `json:"content"`
+}
+
+// DocumentStore reads and writes the documents of a project's collections, either locally or in a deployed stack.
+type DocumentStore interface {
+	Get(collection, id string) (*Document, error)
+	Put(collection, id string, content map[string]interface{}) error
+	Delete(collection, id string) error
+	// Query returns up to limit documents whose fields equal the values in where.
+	Query(collection string, where map[string]interface{}, limit int) ([]*Document, error)
+}
@@ -0,0 +1,35 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package types
+
+type EnvSource string
+
+const (
+	EnvSourceProvider EnvSource = "provider"
+	EnvSourceEnvFile  EnvSource = "env file"
+	EnvSourceSecret   EnvSource = "secret"
+	EnvSourceProject  EnvSource = "nitric.yaml"
+)
+
+// DeployTimeValue is shown for values that are only known once the stack's resources exist.
+const DeployTimeValue = "<resolved at deploy time>"
+
+// EnvVar is an environment variable that a deployed function receives.
+type EnvVar struct {
+	Name   string    `json:"name"`
+	Value  string    `json:"value"`
+	Source EnvSource `json:"source"`
+}
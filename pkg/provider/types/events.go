@@ -0,0 +1,84 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+type EventType string
+
+const (
+	EventStarted        EventType = "started"
+	EventResourceBusy   EventType = "resource:busy"
+	EventResourceDone   EventType = "resource:done"
+	EventResourceFailed EventType = "resource:failed"
+	EventFinished       EventType = "finished"
+)
+
+// Event is a structured progress update emitted while a provider applies or deletes a stack.
+type Event struct {
+	Type      EventType     `json:"type"`
+	Operation string        `json:"operation"`
+	Resource  string        `json:"resource,omitempty"`
+	Elapsed   time.Duration `json:"elapsed,omitempty"`
+	Done      int           `json:"done"`
+	Total     int           `json:"total"`
+	Failed    int           `json:"failed"`
+	Time      time.Time     `json:"time"`
+}
+
+// EventListener receives deployment progress events, e.g. for IDE plugins and dashboards.
+type EventListener interface {
+	OnEvent(evt Event)
+}
+
+type webhookListener struct {
+	url    string
+	client *http.Client
+}
+
+var _ EventListener = &webhookListener{}
+
+// NewWebhookListener returns an EventListener that POSTs each event as JSON to url.
+func NewWebhookListener(url string) EventListener {
+	return &webhookListener{
+		url:    url,
+		client: &http.Client{Timeout: 2 * time.Second},
+	}
+}
+
+func (w *webhookListener) OnEvent(evt Event) {
+	b, err := json.Marshal(evt)
+	if err != nil {
+		pterm.Debug.Println("event marshal: " + err.Error())
+		return
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		// never fail a deployment because a listener went away
+		pterm.Debug.Println("event webhook: " + err.Error())
+		return
+	}
+	resp.Body.Close()
+}
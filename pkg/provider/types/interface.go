@@ -22,14 +22,157 @@ import (
 )
 
 type Deployment struct {
-	ApiEndpoints map[string]string `json:"apiEndpoints,omitempty"`
+	ApiEndpoints  map[string]string `json:"apiEndpoints,omitempty"`
+	CdnEndpoints  map[string]string `json:"cdnEndpoints,omitempty"`
+	SiteEndpoints map[string]string `json:"siteEndpoints,omitempty"`
+	// Images are the pushed image names by the local image they were built from
+	Images map[string]string `json:"images,omitempty"`
+}
+
+// DomainStatus is the validation state of a custom domain of the deployed stack.
+type DomainStatus struct {
+	Domain string `json:"domain"`
+	// State is the provider's validation state, e.g. Pending or Approved
+	State string `json:"state"`
+	// Ready is true once the domain is validated and can serve requests
+	Ready bool `json:"ready"`
+	// Records are the DNS records the domain needs before it can be validated
+	Records []DnsRecord `json:"records,omitempty"`
+}
+
+type DnsRecord struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Orphan is a resource tagged with the stack that is no longer in its state, e.g. left over from a failed
+// delete or a rename.
+type Orphan struct {
+	// Type is the provider's kind of resource, e.g. sqs:queue or Microsoft.Storage/storageAccounts
+	Type string `json:"type"`
+	// ID is the provider's id of the resource, e.g. its ARN
+	ID string `json:"id"`
+}
+
+// Permission is what a function's policies allow it to do to a resource, and the grants of the
+// provider that allow it.
+type Permission struct {
+	Function string `json:"function"`
+	// Resource is the type and name of the resource, e.g. bucket:images
+	Resource string `json:"resource"`
+	// Actions are the nitric actions of the policies, e.g. BucketFileGet
+	Actions []string `json:"actions"`
+	// Grants are the provider's permissions or roles, e.g. s3:GetObject
+	Grants []string `json:"grants"`
+}
+
+// FunctionHealth is whether a deployed function is serving requests.
+type FunctionHealth struct {
+	Function string `json:"function"`
+	Healthy  bool   `json:"healthy"`
+	// Reason is why the function is not serving, e.g. its revision failed to start
+	Reason string `json:"reason,omitempty"`
+}
+
+// Quota is a limit of the provider's account that commonly blocks deploys, with how much of it the stack needs.
+type Quota struct {
+	// Name is the provider's name of the quota, e.g. Lambda concurrent executions
+	Name  string  `json:"name"`
+	Limit float64 `json:"limit"`
+	// Needed is how much of the quota the stack needs, along with what is used by others where that is known
+	Needed float64 `json:"needed"`
+	// IncreaseUrl is where an increase of the quota is requested
+	IncreaseUrl string `json:"increaseUrl"`
+}
+
+// Exceeded is true when the stack needs more than the quota allows.
+func (q Quota) Exceeded() bool {
+	return q.Needed > q.Limit
+}
+
+// Revision is a deployed version of a function, with the share of its requests that it serves.
+type Revision struct {
+	Name    string `json:"name"`
+	Created string `json:"created"`
+	Active  bool   `json:"active"`
+	// Traffic is the percentage of the function's requests the revision serves
+	Traffic  int    `json:"traffic"`
+	Replicas int    `json:"replicas"`
+	State    string `json:"state"`
+}
+
+// StackLock freezes a stack, updating or deleting it fails until it is unlocked.
+type StackLock struct {
+	Reason string `json:"reason"`
+	Owner  string `json:"owner"`
+	// Time the stack was locked, in RFC 3339
+	Time string `json:"time"`
 }
 
 type Provider interface {
 	Up(log output.Progress) (*Deployment, error)
+	// Preview returns the changes an update would make to the stack, without making them.
+	Preview(log output.Progress) ([]Change, error)
 	Down(log output.Progress) error
+	// Deployment returns the endpoints of the deployed stack.
+	Deployment() (*Deployment, error)
 	List() (interface{}, error)
 	Ask() (*stack.Config, error)
 	TryPullImages() error
+	// Preflight checks the deployment backend can be used, it is cheap and run before gathering and building.
+	Preflight() error
+	SetEventListener(l EventListener)
+	// PluginVersions returns the version of each deployment plugin, keyed by name
+	PluginVersions() map[string]string
+	// SetDeleteData allows buckets, collections and secrets to be deleted or replaced, losing their data.
+	SetDeleteData(deleteData bool)
+	// SetPlan makes Up fail, before changing anything, when it would not make the changes of the plan.
+	SetPlan(plan *Plan)
+	// SetEmptyBuckets deletes the files in every bucket on down, so the buckets can be deleted.
+	SetEmptyBuckets(emptyBuckets bool)
+	// SetShowSecrets reveals the deployment outputs marked as secret, which are masked otherwise.
+	SetShowSecrets(showSecrets bool)
+	// SetReadOnly stops the stack being changed, so it can be inspected with credentials that can only read it.
+	SetReadOnly(readOnly bool)
+	// SetOverrideLock lets a locked stack be updated or deleted.
+	SetOverrideLock(overrideLock bool)
+	// SetConfigOnly makes Preview and Up only change the configuration of the functions, reusing their deployed images,
+	// they fail if the update would make any other change.
+	SetConfigOnly(configOnly bool)
+	// Lock freezes the deployed stack with the reason, so updating or deleting it fails until it is unlocked.
+	Lock(reason string) (*StackLock, error)
+	// Unlock removes the lock of the deployed stack, it is not an error when the stack isn't locked.
+	Unlock() error
+	Env(function string) ([]EnvVar, error)
+	// Publish publishes the payload to a topic of the deployed stack, returning the message id.
+	Publish(topic string, payload map[string]interface{}) (string, error)
+	// Send sends the payload to a queue of the deployed stack, returning the message id.
+	Send(queue string, payload map[string]interface{}) (string, error)
+	// Documents returns the document store of the deployed stack.
+	Documents() (DocumentStore, error)
+	// Sleep scales the always running compute of the deployed stack to zero, or back up when sleeping is false.
+	Sleep(sleeping bool) error
+	// Buckets returns the bucket store of the deployed stack.
+	Buckets() (BucketStore, error)
+	// Deployer returns the role to deploy the project's stacks with, creating it when create is true.
+	Deployer(create bool) (*DeployerRole, error)
+	// Encrypt returns the value encrypted with the stack's encryptionKey, as a reference for the stack file.
+	Encrypt(plaintext string) (string, error)
+	// Domains returns the validation state of the custom domains of the deployed stack, which can be
+	// pending for some minutes after it is updated.
+	Domains() ([]DomainStatus, error)
+	// Orphans returns the resources tagged with the stack that are no longer in its refreshed state.
+	Orphans(log output.Progress) ([]Orphan, error)
+	// DeleteOrphans deletes the orphaned resources, continuing past those that fail.
+	DeleteOrphans(orphans []Orphan, log output.Progress) error
+	// Permissions returns the permissions the project's policies grant its functions on the stack's provider.
+	Permissions() ([]Permission, error)
+	// Health checks each deployed function is serving requests, e.g. by invoking it with a health check.
+	Health() ([]FunctionHealth, error)
+	// Revisions returns the deployed revisions of the function, the newest first.
+	Revisions(function string) ([]Revision, error)
+	// Quotas returns the account quotas that commonly block deploys, it is run after gathering and before building.
+	Quotas() ([]Quota, error)
 	//Status()
 }
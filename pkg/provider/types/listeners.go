@@ -0,0 +1,70 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package types
+
+import (
+	"sync"
+	"time"
+)
+
+type multiListener struct {
+	listeners []EventListener
+}
+
+// NewMultiListener forwards every event to each of the listeners.
+func NewMultiListener(listeners ...EventListener) EventListener {
+	return &multiListener{listeners: listeners}
+}
+
+func (m *multiListener) OnEvent(evt Event) {
+	for _, l := range m.listeners {
+		l.OnEvent(evt)
+	}
+}
+
+// ResourceTimings records how long each resource took to create, update or delete.
+type ResourceTimings struct {
+	lock    sync.Mutex
+	elapsed map[string]time.Duration
+}
+
+var _ EventListener = &ResourceTimings{}
+
+func NewResourceTimings() *ResourceTimings {
+	return &ResourceTimings{elapsed: map[string]time.Duration{}}
+}
+
+func (r *ResourceTimings) OnEvent(evt Event) {
+	if evt.Type != EventResourceDone || evt.Elapsed == 0 {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.elapsed[evt.Resource] = evt.Elapsed
+}
+
+// Elapsed returns the time taken per resource, keyed by "<type>/<name>".
+func (r *ResourceTimings) Elapsed() map[string]time.Duration {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	elapsed := map[string]time.Duration{}
+	for k, v := range r.elapsed {
+		elapsed[k] = v
+	}
+	return elapsed
+}
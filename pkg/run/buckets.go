@@ -0,0 +1,53 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+	"github.com/nitrictech/cli/pkg/storage"
+)
+
+// NewLocalBuckets returns the bucket store of the locally running minio server.
+func NewLocalBuckets(status *LocalServicesStatus) (types.BucketStore, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:         aws.String("http://" + status.MinioEndpoint),
+		Region:           aws.String("us-east-1"),
+		Credentials:      credentials.NewStaticCredentials("minioadmin", "minioadmin", ""),
+		S3ForcePathStyle: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	client := s3.New(sess)
+
+	// locally buckets have the same name as in the project
+	out, err := client.ListBuckets(&s3.ListBucketsInput{})
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]string{}
+	for _, b := range out.Buckets {
+		names[aws.StringValue(b.Name)] = aws.StringValue(b.Name)
+	}
+
+	return storage.NewS3Store(client, names), nil
+}
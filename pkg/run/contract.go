@@ -0,0 +1,193 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/nitric/pkg/triggers"
+	"github.com/nitrictech/nitric/pkg/worker"
+)
+
+type TriggerKind string
+
+const (
+	TriggerKind_Api      TriggerKind = "api"
+	TriggerKind_Topic    TriggerKind = "topic"
+	TriggerKind_Schedule TriggerKind = "schedule"
+)
+
+// ContractTrigger is a sample trigger synthesized from the project, used to check its handlers respond.
+type ContractTrigger struct {
+	Kind TriggerKind
+	Name string
+
+	// api requests
+	Api    string
+	Method string
+	Path   string
+
+	// topic messages and schedule events
+	Topic string
+
+	Payload []byte
+}
+
+var pathParam = regexp.MustCompile(`{[^}]+}`)
+
+// ContractTriggers synthesizes an api request for each api operation, a message for each topic
+// with subscribers and an event for each schedule.
+func ContractTriggers(p *project.Project) ([]ContractTrigger, error) {
+	ts := []ContractTrigger{}
+
+	for apiName, doc := range p.ApiDocs {
+		for path, item := range doc.Paths {
+			for method := range item.Operations() {
+				t := ContractTrigger{
+					Kind:   TriggerKind_Api,
+					Name:   fmt.Sprintf("%s %s %s", apiName, method, path),
+					Api:    apiName,
+					Method: method,
+					Path:   pathParam.ReplaceAllString(path, "test"),
+				}
+				switch method {
+				case "POST", "PUT", "PATCH":
+					t.Payload = []byte("{}")
+				}
+				ts = append(ts, t)
+			}
+		}
+	}
+
+	topics := map[string]bool{}
+	for _, f := range p.Functions {
+		for _, topic := range f.Triggers.Topics {
+			topics[topic] = true
+		}
+	}
+	for topic := range topics {
+		ts = append(ts, ContractTrigger{
+			Kind:    TriggerKind_Topic,
+			Name:    topic,
+			Topic:   topic,
+			Payload: []byte("{}"),
+		})
+	}
+
+	for name, s := range p.Schedules {
+		payload, err := json.Marshal(s.Event.Payload)
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, ContractTrigger{
+			Kind:    TriggerKind_Schedule,
+			Name:    name,
+			Topic:   s.Target.Name,
+			Payload: payload,
+		})
+	}
+
+	sort.SliceStable(ts, func(i, j int) bool {
+		if ts[i].Kind != ts[j].Kind {
+			return ts[i].Kind < ts[j].Kind
+		}
+		return ts[i].Name < ts[j].Name
+	})
+
+	return ts, nil
+}
+
+func (t ContractTrigger) httpRequest() *triggers.HttpRequest {
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(t.Method)
+	ctx.Request.SetRequestURI("/" + strings.TrimPrefix(t.Path, "/"))
+	if len(t.Payload) > 0 {
+		ctx.Request.Header.SetContentType("application/json")
+		ctx.Request.SetBody(t.Payload)
+	}
+	return triggers.FromHttpRequest(ctx)
+}
+
+func (t ContractTrigger) event() *triggers.Event {
+	return &triggers.Event{
+		ID:      "contract-test",
+		Topic:   t.Topic,
+		Payload: t.Payload,
+	}
+}
+
+// Ready is true once a worker that handles the trigger has registered with the pool.
+func (t ContractTrigger) Ready(pool worker.WorkerPool) bool {
+	if t.Kind == TriggerKind_Api {
+		_, err := pool.GetWorker(&worker.GetWorkerOptions{
+			Http:   t.httpRequest(),
+			Filter: apiWorkerFilter(t.Api),
+		})
+		return err == nil
+	}
+	return len(pool.GetWorkers(&worker.GetWorkerOptions{Event: t.event()})) > 0
+}
+
+// Invoke delivers the trigger to its handlers, api requests fail when the handler responds with
+// a server error and events fail when any subscriber does not ack.
+func (t ContractTrigger) Invoke(pool worker.WorkerPool) error {
+	if t.Kind == TriggerKind_Api {
+		req := t.httpRequest()
+		w, err := pool.GetWorker(&worker.GetWorkerOptions{
+			Http:   req,
+			Filter: apiWorkerFilter(t.Api),
+		})
+		if err != nil {
+			return fmt.Errorf("no handler found for %s %s", t.Method, t.Path)
+		}
+
+		resp, err := w.HandleHttpRequest(req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode >= 500 {
+			return fmt.Errorf("responded with status %d", resp.StatusCode)
+		}
+		return nil
+	}
+
+	evt := t.event()
+	ws := pool.GetWorkers(&worker.GetWorkerOptions{Event: evt})
+	if len(ws) == 0 {
+		return fmt.Errorf("no subscribers found for topic %s", t.Topic)
+	}
+
+	failed := 0
+	var lastErr error
+	for _, w := range ws {
+		if err := w.HandleEvent(evt); err != nil {
+			failed++
+			lastErr = err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d subscribers failed: %v", failed, len(ws), lastErr)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/nitrictech/cli/pkg/project"
+)
+
+func TestContractTriggers(t *testing.T) {
+	p := &project.Project{
+		ApiDocs: map[string]*openapi3.T{
+			"main": {
+				Paths: openapi3.Paths{
+					"/orders": &openapi3.PathItem{
+						Post: &openapi3.Operation{},
+					},
+					"/orders/{id}": &openapi3.PathItem{
+						Get: &openapi3.Operation{},
+					},
+				},
+			},
+		},
+		Functions: map[string]project.Function{
+			"a": {ComputeUnit: project.ComputeUnit{Triggers: project.Triggers{Topics: []string{"orders", "audit"}}}},
+			"b": {ComputeUnit: project.ComputeUnit{Triggers: project.Triggers{Topics: []string{"orders"}}}},
+		},
+		Schedules: map[string]project.Schedule{
+			"nightly": {
+				Target: project.ScheduleTarget{Type: "topic", Name: "nightly"},
+				Event:  project.ScheduleEvent{Payload: map[string]interface{}{"x": "y"}},
+			},
+		},
+	}
+
+	want := []ContractTrigger{
+		{Kind: TriggerKind_Api, Name: "main GET /orders/{id}", Api: "main", Method: "GET", Path: "/orders/test"},
+		{Kind: TriggerKind_Api, Name: "main POST /orders", Api: "main", Method: "POST", Path: "/orders", Payload: []byte("{}")},
+		{Kind: TriggerKind_Schedule, Name: "nightly", Topic: "nightly", Payload: []byte(`{"x":"y"}`)},
+		{Kind: TriggerKind_Topic, Name: "audit", Topic: "audit", Payload: []byte("{}")},
+		{Kind: TriggerKind_Topic, Name: "orders", Topic: "orders", Payload: []byte("{}")},
+	}
+
+	got, err := ContractTriggers(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
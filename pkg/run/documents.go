@@ -0,0 +1,131 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+type localDocuments struct {
+	address string
+}
+
+var _ types.DocumentStore = &localDocuments{}
+
+// NewLocalDocuments returns the document store of the locally running membrane.
+func NewLocalDocuments(address string) types.DocumentStore {
+	return &localDocuments{address: address}
+}
+
+func documentKey(collection, id string) *v1.Key {
+	return &v1.Key{Collection: &v1.Collection{Name: collection}, Id: id}
+}
+
+func expressionValue(v interface{}) (*v1.ExpressionValue, error) {
+	switch t := v.(type) {
+	case string:
+		return &v1.ExpressionValue{Kind: &v1.ExpressionValue_StringValue{StringValue: t}}, nil
+	case bool:
+		return &v1.ExpressionValue{Kind: &v1.ExpressionValue_BoolValue{BoolValue: t}}, nil
+	case float64:
+		if t == float64(int64(t)) {
+			return &v1.ExpressionValue{Kind: &v1.ExpressionValue_IntValue{IntValue: int64(t)}}, nil
+		}
+		return &v1.ExpressionValue{Kind: &v1.ExpressionValue_DoubleValue{DoubleValue: t}}, nil
+	default:
+		return nil, fmt.Errorf("can not query by value %v, use a string, number or boolean", v)
+	}
+}
+
+func (l *localDocuments) Get(collection, id string) (*types.Document, error) {
+	conn, err := dialMembrane(l.address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	resp, err := v1.NewDocumentServiceClient(conn).Get(context.Background(), &v1.DocumentGetRequest{Key: documentKey(collection, id)})
+	if err != nil {
+		return nil, err
+	}
+	return &types.Document{Id: id, Content: resp.Document.Content.AsMap()}, nil
+}
+
+func (l *localDocuments) Put(collection, id string, content map[string]interface{}) error {
+	c, err := structpb.NewStruct(content)
+	if err != nil {
+		return err
+	}
+
+	conn, err := dialMembrane(l.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = v1.NewDocumentServiceClient(conn).Set(context.Background(), &v1.DocumentSetRequest{Key: documentKey(collection, id), Content: c})
+	return err
+}
+
+func (l *localDocuments) Delete(collection, id string) error {
+	conn, err := dialMembrane(l.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = v1.NewDocumentServiceClient(conn).Delete(context.Background(), &v1.DocumentDeleteRequest{Key: documentKey(collection, id)})
+	return err
+}
+
+func (l *localDocuments) Query(collection string, where map[string]interface{}, limit int) ([]*types.Document, error) {
+	expressions := []*v1.Expression{}
+	for k, v := range where {
+		ev, err := expressionValue(v)
+		if err != nil {
+			return nil, err
+		}
+		expressions = append(expressions, &v1.Expression{Operand: k, Operator: "==", Value: ev})
+	}
+
+	conn, err := dialMembrane(l.address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	resp, err := v1.NewDocumentServiceClient(conn).Query(context.Background(), &v1.DocumentQueryRequest{
+		Collection:  &v1.Collection{Name: collection},
+		Expressions: expressions,
+		Limit:       int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	docs := []*types.Document{}
+	for _, d := range resp.Documents {
+		docs = append(docs, &types.Document{Id: d.Key.Id, Content: d.Content.AsMap()})
+	}
+	return docs, nil
+}
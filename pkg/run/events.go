@@ -18,10 +18,16 @@ package run
 import (
 	"encoding/json"
 	"fmt"
+	"log"
+	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/nitrictech/cli/pkg/project"
 	"github.com/nitrictech/nitric/pkg/plugins/errors"
 	"github.com/nitrictech/nitric/pkg/plugins/errors/codes"
 	"github.com/nitrictech/nitric/pkg/plugins/events"
+	"github.com/nitrictech/nitric/pkg/plugins/queue"
 	"github.com/nitrictech/nitric/pkg/triggers"
 	"github.com/nitrictech/nitric/pkg/worker"
 )
@@ -29,6 +35,13 @@ import (
 type WorkerPoolEventService struct {
 	events.UnimplementedeventsPlugin
 	pool worker.WorkerPool
+	// schemas of the topics that have one, messages that don't match them are rejected
+	schemas map[string]*openapi3.Schema
+	tracer  *Tracer
+	// redelivery retries the subscribers that fail, sending the message to the topic's dead letter queue
+	// after the last attempt
+	redelivery  Redelivery
+	deadLetters queue.QueueService
 }
 
 // Publish a message to a given topic
@@ -45,6 +58,16 @@ func (s *WorkerPoolEventService) Publish(topic string, event *events.NitricEvent
 	// payloadType := event.PayloadType
 	payload := event.Payload
 
+	if schema, ok := s.schemas[topic]; ok {
+		if err := project.ValidatePayload(topic, schema, payload); err != nil {
+			return newErr(
+				codes.InvalidArgument,
+				"event payload rejected",
+				err,
+			)
+		}
+	}
+
 	marshaledPayload, err := json.Marshal(payload)
 	if err != nil {
 		return newErr(
@@ -65,22 +88,75 @@ func (s *WorkerPoolEventService) Publish(topic string, event *events.NitricEvent
 		Event: evt,
 	})
 
-	fmt.Printf("Publishing event to: %s\n", targets)
+	log.Printf("Publishing event to: %s\n", targets)
+	failed := deliver(s.tracer.StartSpan("publish "+topic, spanKindProducer, ""), evt, targets)
+	if len(failed) > 0 && s.redelivery.enabled() {
+		go s.retry(evt, event, failed)
+	}
+
+	return nil
+}
+
+// retry delivers the event again to the subscribers that failed, until they succeed or every attempt
+// has been made, when the event is sent to the topic's dead letter queue.
+func (s *WorkerPoolEventService) retry(evt *triggers.Event, event *events.NitricEvent, failed []worker.Worker) {
+	for attempt := 2; attempt <= s.redelivery.MaxAttempts && len(failed) > 0; attempt++ {
+		time.Sleep(retryDelay(attempt - 1))
+		log.Printf("Redelivering event %s of topic %s, attempt %d\n", evt.ID, evt.Topic, attempt)
+		failed = deliver(s.tracer.StartSpan("redeliver "+evt.Topic, spanKindProducer, ""), evt, failed)
+	}
+	if len(failed) == 0 {
+		return
+	}
+
+	log.Printf("Dead-lettering event %s of topic %s to %s after %d attempts\n", evt.ID, evt.Topic, DeadLetterQueue(evt.Topic), s.redelivery.MaxAttempts)
+	err := s.deadLetters.Send(DeadLetterQueue(evt.Topic), queue.NitricTask{
+		ID:          event.ID,
+		PayloadType: event.PayloadType,
+		Payload:     event.Payload,
+	})
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// deliver the event to each of the targets, recording a span for each delivery under the publish span,
+// returning the targets that failed.
+func deliver(span *Span, evt *triggers.Event, targets []worker.Worker) []worker.Worker {
+	span.SetAttribute("nitric.topic", evt.Topic)
+	span.SetAttribute("nitric.subscribers", fmt.Sprint(len(targets)))
+
+	failed := []worker.Worker{}
 	for _, target := range targets {
-		err = target.HandleEvent(evt)
+		delivery := span.StartChild("deliver "+evt.Topic, spanKindConsumer)
+		delivery.SetAttribute("nitric.topic", evt.Topic)
+
+		err := target.HandleEvent(evt)
 		if err != nil {
 			// this is likely an error in the user's handler, we don't want it to bring the server down.
 			// just log and move on.
-			fmt.Println(err)
+			log.Println(err)
+			failed = append(failed, target)
 		}
+		delivery.End(err)
 	}
 
-	return nil
+	span.End(nil)
+	return failed
 }
 
-// Create new Dev EventService
-func NewEvents(pool worker.WorkerPool) (events.EventService, error) {
+// Create new Dev EventService, the messages that fail every attempt are sent to the dead letter queues
+// of the queue service.
+func NewEvents(pool worker.WorkerPool, proj *project.Project, tracer *Tracer, deadLetters queue.QueueService, r Redelivery) (events.EventService, error) {
+	schemas, err := proj.TopicSchemas()
+	if err != nil {
+		return nil, err
+	}
 	return &WorkerPoolEventService{
-		pool: pool,
+		pool:        pool,
+		schemas:     schemas,
+		tracer:      tracer,
+		redelivery:  r,
+		deadLetters: deadLetters,
 	}, nil
 }
@@ -18,7 +18,6 @@ package run
 import (
 	"fmt"
 	goruntime "runtime"
-	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types/container"
@@ -30,17 +29,19 @@ import (
 )
 
 type Function struct {
+	name        string
 	projectName string
 	handler     string
 	runCtx      string
 	rt          runtime.Runtime
 	ce          containerengine.ContainerEngine
+	env         map[string]string
 	// Container id populated after a call to Start
 	cid string
 }
 
 func (f *Function) Name() string {
-	return f.rt.ContainerName()
+	return f.name
 }
 
 func (f *Function) Start(envMap map[string]string) error {
@@ -51,7 +52,7 @@ func (f *Function) Start(envMap map[string]string) error {
 
 	hc := &container.HostConfig{
 		AutoRemove: true,
-		Mounts:     launchOpts.Mounts,
+		Mounts:     containerengine.HostMounts(launchOpts.Mounts),
 		LogConfig:  *f.ce.Logger(f.runCtx).Config(),
 	}
 
@@ -61,10 +62,15 @@ func (f *Function) Start(envMap map[string]string) error {
 		hc.ExtraHosts = []string{"host.docker.internal:172.17.0.1"}
 	}
 
+	// the membrane is served by the CLI, which may be in a dev container next to this one
+	cliHost := containerengine.CLIHost()
 	env := []string{
-		fmt.Sprintf("SERVICE_ADDRESS=host.docker.internal:%d", 50051),
+		fmt.Sprintf("SERVICE_ADDRESS=%s:%d", cliHost, 50051),
 		fmt.Sprintf("NITRIC_SERVICE_PORT=%d", 50051),
-		fmt.Sprintf("NITRIC_SERVICE_HOST=%s", "host.docker.internal"),
+		fmt.Sprintf("NITRIC_SERVICE_HOST=%s", cliHost),
+	}
+	for k, v := range f.env {
+		env = append(env, k+"="+v)
 	}
 	for k, v := range envMap {
 		env = append(env, k+"="+v)
@@ -81,7 +87,7 @@ func (f *Function) Start(envMap map[string]string) error {
 
 	pterm.Debug.Print(containerengine.Cli(cc, hc))
 
-	cID, err := f.ce.ContainerCreate(cc, hc, nil, strings.Join([]string{f.projectName, "run", f.Name()}, "-"))
+	cID, err := f.ce.ContainerCreate(cc, hc, nil, functionContainerName(f.projectName, f.Name()))
 	if err != nil {
 		return err
 	}
@@ -97,10 +103,12 @@ func (f *Function) Stop() error {
 }
 
 type FunctionOpts struct {
+	Name            string
 	ProjectName     string
 	Handler         string
 	RunCtx          string
 	ContainerEngine containerengine.ContainerEngine
+	Env             map[string]string
 }
 
 func newFunction(opts FunctionOpts) (*Function, error) {
@@ -110,32 +118,46 @@ func newFunction(opts FunctionOpts) (*Function, error) {
 	}
 
 	return &Function{
+		name:        opts.Name,
 		rt:          rt,
 		projectName: opts.ProjectName,
 		handler:     opts.Handler,
 		runCtx:      opts.RunCtx,
 		ce:          opts.ContainerEngine,
+		env:         opts.Env,
 	}, nil
 }
 
-func FunctionsFromHandlers(p *project.Project) ([]*Function, error) {
+func FunctionsFromHandlers(p *project.Project, status *LocalServicesStatus) ([]*Function, error) {
 	funcs := make([]*Function, 0, len(p.Functions))
 	ce, err := containerengine.Discover()
 	if err != nil {
 		return nil, err
 	}
 
-	for _, f := range p.Functions {
+	for name, f := range p.Functions {
+		env := map[string]string{}
+		if status.RedisPort > 0 {
+			env = cacheEnv(p, name, status.RedisPort)
+		}
+		if status.OtlpPort > 0 {
+			for k, v := range tracingEnv(name, status.OtlpPort) {
+				env[k] = v
+			}
+		}
+
 		relativeHandlerPath, err := f.RelativeHandlerPath(p)
 		if err != nil {
 			return nil, err
 		}
 
 		if f, err := newFunction(FunctionOpts{
+			Name:            name,
 			RunCtx:          p.Dir,
 			Handler:         relativeHandlerPath,
 			ContainerEngine: ce,
 			ProjectName:     p.Name,
+			Env:             env,
 		}); err != nil {
 			return nil, err
 		} else {
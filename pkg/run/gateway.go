@@ -18,6 +18,7 @@ package run
 
 import (
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
@@ -37,7 +38,10 @@ type BaseHttpGateway struct {
 	server  *fasthttp.Server
 	gateway.UnimplementedGatewayPlugin
 
-	pool worker.WorkerPool
+	pool      worker.WorkerPool
+	workflows map[string][]string
+	recorder  *Recorder
+	tracer    *Tracer
 }
 
 func apiWorkerFilter(apiName string) func(w worker.Worker) bool {
@@ -63,6 +67,22 @@ func (s *BaseHttpGateway) api(ctx *fasthttp.RequestCtx) {
 	// Rewrite the path
 	ctx.URI().SetPath(newPath)
 
+	// the function continues the trace of the request when it is traced
+	span := s.tracer.StartSpan(string(ctx.Method())+" /"+strings.TrimPrefix(newPath, "/"), spanKindServer, string(ctx.Request.Header.Peek("traceparent")))
+	if span != nil {
+		span.SetAttribute("nitric.api", apiName)
+		ctx.Request.Header.Set("traceparent", span.Traceparent())
+		defer func() {
+			code := ctx.Response.StatusCode()
+			span.SetAttribute("http.status_code", fmt.Sprint(code))
+			var err error
+			if code >= 500 {
+				err = fmt.Errorf("%d %s", code, ctx.Response.Body())
+			}
+			span.End(err)
+		}()
+	}
+
 	httpReq := triggers.FromHttpRequest(ctx)
 
 	worker, err := s.pool.GetWorker(&worker.GetWorkerOptions{
@@ -109,15 +129,26 @@ func (s *BaseHttpGateway) topic(ctx *fasthttp.RequestCtx) {
 		ctx.Error("no subscribers found for topic", 404)
 	}
 
-	errList := make([]error, 0)
+	failed := deliver(s.tracer.StartSpan("publish "+topicName, spanKindProducer, string(ctx.Request.Header.Peek("traceparent"))), evt, ws)
 
-	for _, w := range ws {
-		if err := w.HandleEvent(evt); err != nil {
-			errList = append(errList, err)
-		}
+	ctx.Success("text/plain", []byte(fmt.Sprintf("%d successful & %d failed deliveries", len(ws)-len(failed), len(failed))))
+}
+
+func (s *BaseHttpGateway) workflow(ctx *fasthttp.RequestCtx) {
+	workflowName := ctx.UserValue("name").(string)
+
+	stepTopics, ok := s.workflows[workflowName]
+	if !ok {
+		ctx.Error("workflow not found", 404)
+		return
+	}
+
+	if err := runWorkflow(s.pool, workflowName, stepTopics, ctx.Request.Body()); err != nil {
+		ctx.Error(fmt.Sprintf("workflow failed: %v", err), 500)
+		return
 	}
 
-	ctx.Success("text/plain", []byte(fmt.Sprintf("%d successful & %d failed deliveries", len(ws)-len(errList), len(errList))))
+	ctx.Success("text/plain", []byte(fmt.Sprintf("%d steps completed", len(stepTopics))))
 }
 
 func (s *BaseHttpGateway) Start(pool worker.WorkerPool) error {
@@ -129,12 +160,25 @@ func (s *BaseHttpGateway) Start(pool worker.WorkerPool) error {
 	r.ANY("/apis/{name}/{any?:*}", s.api)
 	// Publish to a topic
 	r.POST("/topic/{name}", s.topic)
+	// Start a workflow execution
+	r.POST("/workflow/{name}", s.workflow)
+
+	handler := r.Handler
+	if s.recorder != nil {
+		// record before routing, the api handler rewrites the request path
+		handler = func(ctx *fasthttp.RequestCtx) {
+			if err := s.recorder.Record(ctx); err != nil {
+				log.Println("recording request:", err)
+			}
+			r.Handler(ctx)
+		}
+	}
 
 	s.server = &fasthttp.Server{
 		ReadTimeout:     time.Second * 1,
 		IdleTimeout:     time.Second * 1,
 		CloseOnShutdown: true,
-		Handler:         r.Handler,
+		Handler:         handler,
 	}
 
 	return s.server.ListenAndServe(s.address)
@@ -149,8 +193,11 @@ func (s *BaseHttpGateway) Stop() error {
 
 // Create new HTTP gateway
 // XXX: No External Args for function atm (currently the plugin loader does not pass any argument information)
-func NewGateway(address string) (gateway.GatewayService, error) {
+func NewGateway(address string, workflows map[string][]string, recorder *Recorder, tracer *Tracer) (gateway.GatewayService, error) {
 	return &BaseHttpGateway{
-		address: address,
+		address:   address,
+		workflows: workflows,
+		recorder:  recorder,
+		tracer:    tracer,
 	}, nil
 }
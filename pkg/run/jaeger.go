@@ -0,0 +1,156 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+	"github.com/pterm/pterm"
+
+	"github.com/nitrictech/cli/pkg/containerengine"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// JaegerServer collects traces with its OTLP receiver and shows them in the Jaeger UI.
+type JaegerServer struct {
+	dir      string
+	name     string
+	cid      string
+	ce       containerengine.ContainerEngine
+	otlpPort int // external OTLP/HTTP port from the jaeger container
+	uiPort   int // external UI port from the jaeger container
+}
+
+const (
+	jaegerImage    = "jaegertracing/all-in-one:1.41"
+	jaegerOtlpPort = 4318  // internal OTLP/HTTP receiver port
+	jaegerUIPort   = 16686 // internal UI port
+)
+
+// Start - Start the local Jaeger server
+func (j *JaegerServer) Start() error {
+	ports, err := utils.Take(2)
+	if err != nil {
+		return errors.WithMessage(err, "freeport.Take")
+	}
+
+	otlpPort := uint16(ports[0])
+	uiPort := uint16(ports[1])
+
+	err = j.ce.ImagePull(jaegerImage, types.ImagePullOptions{})
+	if err != nil {
+		return err
+	}
+
+	cc := &container.Config{
+		Image: jaegerImage,
+		Env:   []string{"COLLECTOR_OTLP_ENABLED=true"},
+		ExposedPorts: nat.PortSet{
+			nat.Port(fmt.Sprintf("%d/tcp", jaegerOtlpPort)): struct{}{},
+			nat.Port(fmt.Sprintf("%d/tcp", jaegerUIPort)):   struct{}{},
+		},
+		Labels: map[string]string{
+			labelStackName: j.name,
+			labelType:      "jaeger",
+		},
+	}
+
+	hc := &container.HostConfig{
+		AutoRemove: true,
+		PortBindings: nat.PortMap{
+			nat.Port(fmt.Sprintf("%d/tcp", jaegerOtlpPort)): []nat.PortBinding{
+				{
+					HostPort: fmt.Sprintf("%d", otlpPort),
+				},
+			},
+			nat.Port(fmt.Sprintf("%d/tcp", jaegerUIPort)): []nat.PortBinding{
+				{
+					HostPort: fmt.Sprintf("%d", uiPort),
+				},
+			},
+		},
+		LogConfig:   *j.ce.Logger(j.dir).Config(),
+		NetworkMode: container.NetworkMode("bridge"),
+	}
+
+	cID, err := j.ce.ContainerCreate(cc, hc, &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{},
+	}, "jaeger-"+j.name)
+	if err != nil {
+		return err
+	}
+	j.cid = cID
+	j.otlpPort = int(otlpPort)
+	j.uiPort = int(uiPort)
+
+	pterm.Debug.Print(containerengine.Cli(cc, hc))
+
+	return j.ce.Start(cID)
+}
+
+func (j *JaegerServer) GetOtlpPort() int {
+	return j.otlpPort
+}
+
+func (j *JaegerServer) GetUIPort() int {
+	return j.uiPort
+}
+
+func (j *JaegerServer) Stop() error {
+	timeout := time.Second * 5
+	return j.ce.Stop(j.cid, &timeout)
+}
+
+func NewJaeger(dir string, name string) (*JaegerServer, error) {
+	ce, err := containerengine.Discover()
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove any existing containers with this label.
+	err = ce.RemoveByLabel(map[string]string{
+		labelStackName: name,
+		labelType:      "jaeger",
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not remove existing jaeger container")
+	}
+
+	return &JaegerServer{
+		ce:   ce,
+		dir:  dir,
+		name: name,
+	}, nil
+}
+
+// tracingEnv returns the env vars that configure the OpenTelemetry SDK of a function to export its
+// traces to the local collector.
+func tracingEnv(function string, port int) map[string]string {
+	return map[string]string{
+		"OTEL_SERVICE_NAME":           function,
+		"OTEL_TRACES_EXPORTER":        "otlp",
+		"OTEL_EXPORTER_OTLP_ENDPOINT": fmt.Sprintf("http://host.docker.internal:%d", port),
+		"OTEL_EXPORTER_OTLP_PROTOCOL": "http/protobuf",
+		"OTEL_PROPAGATORS":            "tracecontext,baggage",
+	}
+}
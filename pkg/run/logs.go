@@ -0,0 +1,108 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pterm/pterm"
+
+	"github.com/nitrictech/cli/pkg/project"
+)
+
+// AllFunctions selects the logs of every function.
+const AllFunctions = "all"
+
+var logColors = []pterm.Color{
+	pterm.FgCyan,
+	pterm.FgMagenta,
+	pterm.FgYellow,
+	pterm.FgGreen,
+	pterm.FgBlue,
+	pterm.FgLightRed,
+	pterm.FgLightCyan,
+	pterm.FgLightMagenta,
+}
+
+// LogPrinter prints the lines logged by the project's functions, each prefixed with the name of the function.
+// Lines logged by the membrane and the local services are not printed, they remain in the log file.
+type LogPrinter struct {
+	out io.Writer
+	// prefixes by container name, for the selected functions only
+	prefixes map[string]string
+	lock     sync.Mutex
+}
+
+func functionContainerName(projectName, function string) string {
+	return strings.Join([]string{projectName, "run", function}, "-")
+}
+
+// NewLogPrinter returns a printer of the logs of the given functions, or of every function when given AllFunctions.
+func NewLogPrinter(p *project.Project, functions []string, out io.Writer) (*LogPrinter, error) {
+	selected := map[string]bool{}
+	for _, f := range functions {
+		if f == AllFunctions {
+			for name := range p.Functions {
+				selected[name] = true
+			}
+			continue
+		}
+		if _, ok := p.Functions[f]; !ok {
+			return nil, fmt.Errorf("function %s not found in project %s", f, p.Name)
+		}
+		selected[f] = true
+	}
+
+	names := []string{}
+	width := 0
+	for name := range p.Functions {
+		names = append(names, name)
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	sort.Strings(names)
+
+	l := &LogPrinter{out: out, prefixes: map[string]string{}}
+	for i, name := range names {
+		// colors are assigned over all the functions so they don't change with the selection
+		if selected[name] {
+			l.prefixes[functionContainerName(p.Name, name)] = logColors[i%len(logColors)].Sprintf("%-*s |", width, name)
+		}
+	}
+
+	return l, nil
+}
+
+// Print is a containerengine.LogHandler that prints the line when it was logged by a selected function.
+func (l *LogPrinter) Print(container, line string) {
+	prefix, ok := l.prefixes[container]
+	if !ok {
+		return
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for _, s := range strings.Split(strings.TrimRight(line, "\n"), "\n") {
+		fmt.Fprintln(l.out, prefix, s)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pterm/pterm"
+
+	"github.com/nitrictech/cli/pkg/project"
+)
+
+func TestLogPrinter(t *testing.T) {
+	pterm.DisableColor()
+	defer pterm.EnableColor()
+
+	p := &project.Project{
+		Name: "shop",
+		Functions: map[string]project.Function{
+			"orders":   {},
+			"payments": {},
+			"mail":     {},
+		},
+	}
+
+	if _, err := NewLogPrinter(p, []string{"missing"}, &bytes.Buffer{}); err == nil {
+		t.Error("expected an error for an unknown function")
+	}
+
+	buf := &bytes.Buffer{}
+	l, err := NewLogPrinter(p, []string{"orders", "mail"}, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Print("shop-run-orders", "order created\n")
+	l.Print("shop-run-payments", "payment taken")
+	l.Print("shop-run-mail", "sent\nqueued")
+	l.Print("minio-shop", "bucket created")
+	l.Print("", "Publishing event to: orders")
+
+	want := "orders   | order created\nmail     | sent\nmail     | queued\n"
+	if buf.String() != want {
+		t.Errorf("Print() wrote %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	l, err = NewLogPrinter(p, []string{AllFunctions}, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Print("shop-run-payments", "payment taken")
+	if !strings.HasPrefix(buf.String(), "payments |") {
+		t.Errorf("Print() wrote %q, want the payments prefix", buf.String())
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "github.com/nitrictech/nitric/pkg/api/nitric/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// messageId identifies messages sent from the CLI, so they can be told apart in subscriber logs.
+func messageId() string {
+	return fmt.Sprintf("cli-%d", time.Now().UnixNano())
+}
+
+func dialMembrane(address string) (*grpc.ClientConn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock())
+}
+
+// Publish publishes the payload to a topic of the locally running membrane.
+func Publish(address, topic string, payload map[string]interface{}) (string, error) {
+	p, err := structpb.NewStruct(payload)
+	if err != nil {
+		return "", err
+	}
+
+	conn, err := dialMembrane(address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	resp, err := v1.NewEventServiceClient(conn).Publish(context.Background(), &v1.EventPublishRequest{
+		Topic: topic,
+		Event: &v1.NitricEvent{Id: messageId(), PayloadType: "cli", Payload: p},
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Id, nil
+}
+
+// Send sends the payload to a queue of the locally running membrane.
+func Send(address, queue string, payload map[string]interface{}) (string, error) {
+	p, err := structpb.NewStruct(payload)
+	if err != nil {
+		return "", err
+	}
+
+	conn, err := dialMembrane(address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	id := messageId()
+	_, err = v1.NewQueueServiceClient(conn).Send(context.Background(), &v1.QueueSendRequest{
+		Queue: queue,
+		Task:  &v1.NitricTask{Id: id, PayloadType: "cli", Payload: p},
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
@@ -0,0 +1,145 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+)
+
+// RecordedRequest is a request received by the local gateway, either an api call or a topic message.
+type RecordedRequest struct {
+	Time   time.Time         `json:"time"`
+	Method string            `json:"method"`
+	URI    string            `json:"uri"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   []byte            `json:"body,omitempty"`
+}
+
+// Recorder writes the requests received by the gateway as JSON lines.
+type Recorder struct {
+	lck sync.Mutex
+	enc *json.Encoder
+}
+
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w)}
+}
+
+// skipHeaders are set by the client when the request is replayed.
+var skipHeaders = map[string]bool{
+	"Host":           true,
+	"Content-Length": true,
+	"Connection":     true,
+}
+
+func (r *Recorder) Record(ctx *fasthttp.RequestCtx) error {
+	rr := RecordedRequest{
+		Time:   time.Now(),
+		Method: string(ctx.Method()),
+		URI:    string(ctx.RequestURI()),
+		Header: map[string]string{},
+		Body:   append([]byte(nil), ctx.Request.Body()...),
+	}
+	ctx.Request.Header.VisitAll(func(k, v []byte) {
+		if !skipHeaders[string(k)] {
+			rr.Header[string(k)] = string(v)
+		}
+	})
+
+	r.lck.Lock()
+	defer r.lck.Unlock()
+	return r.enc.Encode(rr)
+}
+
+// ReadRecording reads the requests recorded by 'nitric run --record'.
+func ReadRecording(file string) ([]RecordedRequest, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.WithMessage(err, "reading the recording")
+	}
+	defer f.Close()
+
+	reqs := []RecordedRequest{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 32*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		rr := RecordedRequest{}
+		if err := json.Unmarshal(scanner.Bytes(), &rr); err != nil {
+			return nil, errors.WithMessagef(err, "%s line %d", file, line)
+		}
+		reqs = append(reqs, rr)
+	}
+	return reqs, scanner.Err()
+}
+
+// ReplayResult is the outcome of a replayed request.
+type ReplayResult struct {
+	Request    RecordedRequest
+	StatusCode int
+	Err        error
+}
+
+func (r ReplayResult) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%s %s: %v", r.Request.Method, r.Request.URI, r.Err)
+	}
+	return fmt.Sprintf("%s %s: %d", r.Request.Method, r.Request.URI, r.StatusCode)
+}
+
+// Replay sends the recorded requests, in order, to the gateway at baseURL.
+func Replay(baseURL string, reqs []RecordedRequest) []ReplayResult {
+	client := &http.Client{Timeout: 30 * time.Second}
+	results := make([]ReplayResult, 0, len(reqs))
+
+	for _, rr := range reqs {
+		res := ReplayResult{Request: rr}
+
+		req, err := http.NewRequest(rr.Method, strings.TrimSuffix(baseURL, "/")+rr.URI, bytes.NewReader(rr.Body))
+		if err == nil {
+			for k, v := range rr.Header {
+				req.Header.Set(k, v)
+			}
+
+			var resp *http.Response
+			resp, err = client.Do(req)
+			if err == nil {
+				_, _ = io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				res.StatusCode = resp.StatusCode
+			}
+		}
+		res.Err = err
+
+		results = append(results, res)
+	}
+	return results
+}
@@ -0,0 +1,105 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	buf := &bytes.Buffer{}
+	rec := NewRecorder(buf)
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod("POST")
+	ctx.Request.SetRequestURI("/apis/main/orders?id=1")
+	ctx.Request.Header.Set("X-Test", "yes")
+	ctx.Request.SetBody([]byte(`{"name":"a"}`))
+	if err := rec.Record(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod("POST")
+	ctx.Request.SetRequestURI("/topic/orders")
+	ctx.Request.SetBody([]byte(`hello`))
+	if err := rec.Record(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(t.TempDir(), "requests.jsonl")
+	if err := ioutil.WriteFile(file, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs, err := ReadRecording(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 recorded requests, got %d", len(reqs))
+	}
+	if reqs[0].Method != "POST" || reqs[0].URI != "/apis/main/orders?id=1" || reqs[0].Header["X-Test"] != "yes" || string(reqs[0].Body) != `{"name":"a"}` {
+		t.Errorf("unexpected recorded request %+v", reqs[0])
+	}
+	if reqs[1].URI != "/topic/orders" || string(reqs[1].Body) != "hello" || reqs[1].Time.IsZero() {
+		t.Errorf("unexpected recorded request %+v", reqs[1])
+	}
+
+	got := []RecordedRequest{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		got = append(got, RecordedRequest{
+			Method: r.Method,
+			URI:    r.URL.RequestURI(),
+			Header: map[string]string{"X-Test": r.Header.Get("X-Test")},
+			Body:   body,
+		})
+		if r.URL.Path == "/topic/orders" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	results := Replay(srv.URL, reqs)
+	if len(results) != 2 || results[0].StatusCode != 200 || results[1].StatusCode != 404 {
+		t.Errorf("unexpected results %v", results)
+	}
+	if len(got) != 2 || got[0].URI != "/apis/main/orders?id=1" || got[0].Header["X-Test"] != "yes" || string(got[1].Body) != "hello" {
+		t.Errorf("unexpected replayed requests %v", got)
+	}
+}
+
+func TestReadRecordingBadLine(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "requests.jsonl")
+	if err := ioutil.WriteFile(file, []byte("{\"method\":\"GET\",\"uri\":\"/\"}\n\nnot json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ReadRecording(file)
+	if err == nil || !bytes.Contains([]byte(err.Error()), []byte("line 3")) {
+		t.Errorf("expected an error on line 3, got %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nitrictech/nitric/pkg/plugins/errors"
+	"github.com/nitrictech/nitric/pkg/plugins/errors/codes"
+	"github.com/nitrictech/nitric/pkg/plugins/queue"
+)
+
+// Redelivery emulates how the cloud queues and subscriptions redeliver the messages that fail, and
+// dead-letter them once every attempt has failed.
+type Redelivery struct {
+	// MaxAttempts is how many times a message is delivered before it is dead-lettered, messages are
+	// delivered once and never dead-lettered when it is 0
+	MaxAttempts int
+	// Lease is how long a received task has to be completed before it is delivered again
+	Lease time.Duration
+}
+
+func (r Redelivery) enabled() bool {
+	return r.MaxAttempts > 0
+}
+
+// retryDelay is how long a subscriber is waited for before the message is delivered again, doubling
+// after each attempt.
+func retryDelay(attempt int) time.Duration {
+	return time.Second << (attempt - 1)
+}
+
+// DeadLetterQueue is the local queue the messages of a topic or queue are sent to once every attempt
+// to deliver them has failed.
+func DeadLetterQueue(name string) string {
+	return name + "-dlq"
+}
+
+type taskLease struct {
+	queue   string
+	task    queue.NitricTask
+	expires time.Time
+}
+
+// localQueueService leases the tasks it receives, a task that isn't completed before its lease
+// expires is sent to its queue again, or to the dead letter queue after the last attempt.
+type localQueueService struct {
+	queue.QueueService
+	redelivery Redelivery
+
+	lck      sync.Mutex
+	leases   map[string]*taskLease
+	attempts map[string]int
+	now      func() time.Time
+}
+
+func newLocalQueueService(qs queue.QueueService, r Redelivery) queue.QueueService {
+	if !r.enabled() {
+		return qs
+	}
+	return &localQueueService{
+		QueueService: qs,
+		redelivery:   r,
+		leases:       map[string]*taskLease{},
+		attempts:     map[string]int{},
+		now:          time.Now,
+	}
+}
+
+// redeliver sends the tasks whose lease has expired to their queue again, or to its dead letter queue.
+func (s *localQueueService) redeliver() error {
+	for id, l := range s.leases {
+		if s.now().Before(l.expires) {
+			continue
+		}
+		delete(s.leases, id)
+
+		key := l.queue + "/" + l.task.ID
+		task := l.task
+		task.LeaseID = ""
+		if s.attempts[key] < s.redelivery.MaxAttempts {
+			log.Printf("Redelivering task %s of queue %s, attempt %d\n", task.ID, l.queue, s.attempts[key]+1)
+			if err := s.QueueService.Send(l.queue, task); err != nil {
+				return err
+			}
+			continue
+		}
+
+		delete(s.attempts, key)
+		log.Printf("Dead-lettering task %s of queue %s to %s after %d attempts\n", task.ID, l.queue, DeadLetterQueue(l.queue), s.redelivery.MaxAttempts)
+		if err := s.QueueService.Send(DeadLetterQueue(l.queue), task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *localQueueService) Receive(options queue.ReceiveOptions) ([]queue.NitricTask, error) {
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	if err := s.redeliver(); err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.QueueService.Receive(options)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, t := range tasks {
+		if t.ID == "" {
+			t.ID = messageId()
+		}
+		key := options.QueueName + "/" + t.ID
+		s.attempts[key]++
+
+		t.LeaseID = fmt.Sprintf("%s-%d", t.ID, s.attempts[key])
+		s.leases[t.LeaseID] = &taskLease{
+			queue:   options.QueueName,
+			task:    t,
+			expires: s.now().Add(s.redelivery.Lease),
+		}
+		tasks[i] = t
+	}
+	return tasks, nil
+}
+
+func (s *localQueueService) Complete(q string, leaseId string) error {
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	l, ok := s.leases[leaseId]
+	if !ok || l.queue != q {
+		newErr := errors.ErrorsWithScope(
+			"localQueueService.Complete",
+			map[string]interface{}{
+				"queue":   q,
+				"leaseId": leaseId,
+			},
+		)
+		return newErr(
+			codes.NotFound,
+			"lease not found, it may have expired and the task been delivered again",
+			nil,
+		)
+	}
+
+	delete(s.leases, leaseId)
+	delete(s.attempts, q+"/"+l.task.ID)
+	return nil
+}
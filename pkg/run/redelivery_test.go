@@ -0,0 +1,98 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nitrictech/nitric/pkg/plugins/queue"
+)
+
+// fakeQueues removes the tasks from a queue when they are received, like the dev queue plugin.
+type fakeQueues struct {
+	queue.QueueService
+	queues map[string][]queue.NitricTask
+}
+
+func (f *fakeQueues) Send(q string, task queue.NitricTask) error {
+	f.queues[q] = append(f.queues[q], task)
+	return nil
+}
+
+func (f *fakeQueues) Receive(options queue.ReceiveOptions) ([]queue.NitricTask, error) {
+	tasks := f.queues[options.QueueName]
+	delete(f.queues, options.QueueName)
+	return tasks, nil
+}
+
+func TestLocalQueueServiceRedelivery(t *testing.T) {
+	fake := &fakeQueues{queues: map[string][]queue.NitricTask{}}
+	now := time.Now()
+	s := newLocalQueueService(fake, Redelivery{MaxAttempts: 2, Lease: time.Minute}).(*localQueueService)
+	s.now = func() time.Time { return now }
+
+	receive := func() []queue.NitricTask {
+		tasks, err := s.Receive(queue.ReceiveOptions{QueueName: "orders"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tasks
+	}
+
+	if err := s.Send("orders", queue.NitricTask{ID: "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send("orders", queue.NitricTask{ID: "2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	first := receive()
+	if len(first) != 2 {
+		t.Fatalf("expected 2 tasks, got %v", first)
+	}
+	if err := s.Complete("orders", first[1].LeaseID); err != nil {
+		t.Fatal(err)
+	}
+
+	// the lease of the first task expires, it is delivered a second time
+	now = now.Add(2 * time.Minute)
+	second := receive()
+	if len(second) != 1 || second[0].ID != "1" || second[0].LeaseID == first[0].LeaseID {
+		t.Fatalf("expected task 1 to be delivered again with a new lease, got %v", second)
+	}
+	if err := s.Complete("orders", first[0].LeaseID); err == nil {
+		t.Error("expected an error completing an expired lease")
+	}
+
+	// the second attempt was the last, the task is dead-lettered
+	now = now.Add(2 * time.Minute)
+	if third := receive(); len(third) != 0 {
+		t.Fatalf("expected no more deliveries, got %v", third)
+	}
+	dead := fake.queues[DeadLetterQueue("orders")]
+	if len(dead) != 1 || dead[0].ID != "1" {
+		t.Errorf("expected task 1 to be dead-lettered, got %v", dead)
+	}
+}
+
+func TestNewLocalQueueServiceDisabled(t *testing.T) {
+	fake := &fakeQueues{}
+	if s := newLocalQueueService(fake, Redelivery{}); s != fake {
+		t.Errorf("expected the queue service to be used as is without max attempts, got %T", s)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+	"github.com/pterm/pterm"
+
+	"github.com/nitrictech/cli/pkg/containerengine"
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+type RedisServer struct {
+	dir     string
+	name    string
+	cid     string
+	ce      containerengine.ContainerEngine
+	apiPort int // external port from the redis container
+}
+
+const (
+	redisImage = "redis:7-alpine"
+	redisPort  = 6379 // internal redis port
+)
+
+// Start - Start the local Redis server
+func (r *RedisServer) Start() error {
+	ports, err := utils.Take(1)
+	if err != nil {
+		return errors.WithMessage(err, "freeport.Take")
+	}
+
+	port := uint16(ports[0])
+
+	err = r.ce.ImagePull(redisImage, types.ImagePullOptions{})
+	if err != nil {
+		return err
+	}
+
+	cc := &container.Config{
+		Image: redisImage,
+		ExposedPorts: nat.PortSet{
+			nat.Port(fmt.Sprintf("%d/tcp", redisPort)): struct{}{},
+		},
+		Labels: map[string]string{
+			labelStackName: r.name,
+			labelType:      "redis",
+		},
+	}
+
+	hc := &container.HostConfig{
+		AutoRemove: true,
+		PortBindings: nat.PortMap{
+			nat.Port(fmt.Sprintf("%d/tcp", redisPort)): []nat.PortBinding{
+				{
+					HostPort: fmt.Sprintf("%d", port),
+				},
+			},
+		},
+		LogConfig:   *r.ce.Logger(r.dir).Config(),
+		NetworkMode: container.NetworkMode("bridge"),
+	}
+
+	cID, err := r.ce.ContainerCreate(cc, hc, &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{},
+	}, "redis-"+r.name)
+	if err != nil {
+		return err
+	}
+	r.cid = cID
+	r.apiPort = int(port)
+
+	pterm.Debug.Print(containerengine.Cli(cc, hc))
+
+	return r.ce.Start(cID)
+}
+
+func (r *RedisServer) GetApiPort() int {
+	return r.apiPort
+}
+
+func (r *RedisServer) Stop() error {
+	timeout := time.Second * 5
+	return r.ce.Stop(r.cid, &timeout)
+}
+
+func NewRedis(dir string, name string) (*RedisServer, error) {
+	ce, err := containerengine.Discover()
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove any existing containers with this label.
+	err = ce.RemoveByLabel(map[string]string{
+		labelStackName: name,
+		labelType:      "redis",
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not remove existing redis container")
+	}
+
+	return &RedisServer{
+		ce:   ce,
+		dir:  dir,
+		name: name,
+	}, nil
+}
+
+// cacheEnv returns the env vars for the caches used by a function, each cache uses its own
+// database of the local server, in order of the cache names.
+func cacheEnv(p *project.Project, function string, port int) map[string]string {
+	names := []string{}
+	for k := range p.Caches {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	env := map[string]string{}
+	for _, k := range p.CachesFor(function) {
+		db := sort.SearchStrings(names, k)
+		env[project.CacheEnvPrefix(k)+"_URL"] = fmt.Sprintf("redis://host.docker.internal:%d/%d", port, db)
+	}
+	return env
+}
@@ -18,11 +18,17 @@ package run
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/nitrictech/cli/pkg/containerengine"
 	"github.com/nitrictech/cli/pkg/project"
 	"github.com/nitrictech/cli/pkg/utils"
 	"github.com/nitrictech/nitric/pkg/membrane"
@@ -39,6 +45,13 @@ type LocalServices interface {
 	Stop() error
 	Running() bool
 	Status() *LocalServicesStatus
+	// Record the requests received by the gateway, must be called before Start.
+	Record(rec *Recorder)
+	// Trace starts a local collector and trace viewer, must be called before Start.
+	Trace()
+	// Redeliver the queue tasks and topic messages that fail, dead-lettering them after the last attempt,
+	// must be called before Start.
+	Redeliver(r Redelivery)
 }
 
 type LocalServicesStatus struct {
@@ -46,13 +59,37 @@ type LocalServicesStatus struct {
 	GatewayAddress  string `yaml:"gatewayAddress"`
 	MembraneAddress string `yaml:"membraneAddress"`
 	MinioEndpoint   string `yaml:"minioEndpoint"`
+	RedisPort       int    `yaml:"redisPort,omitempty"`
+	// The OTLP/HTTP port of the local collector and the URL of its trace viewer, when tracing
+	OtlpPort  int    `yaml:"otlpPort,omitempty"`
+	TracesURL string `yaml:"tracesUrl,omitempty"`
+}
+
+// GatewayURL is the base URL of the local gateway.
+func (s *LocalServicesStatus) GatewayURL() string {
+	host, port, err := net.SplitHostPort(s.GatewayAddress)
+	if err != nil || host == "" || host == "0.0.0.0" {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// ApiEndpoint is the URL the gateway serves the api from.
+func (s *LocalServicesStatus) ApiEndpoint(api string) string {
+	return fmt.Sprintf("%s/apis/%s", s.GatewayURL(), api)
 }
 
 type localServices struct {
 	s      *project.Project
 	mio    *MinioServer
+	rds    *RedisServer
+	jgr    *JaegerServer
 	mem    *membrane.Membrane
 	status *LocalServicesStatus
+	rec    *Recorder
+	trace  bool
+	tracer *Tracer
+	redel  Redelivery
 }
 
 func NewLocalServices(s *project.Project) LocalServices {
@@ -67,7 +104,15 @@ func NewLocalServices(s *project.Project) LocalServices {
 }
 
 func (l *localServices) Stop() error {
+	_ = os.Remove(statusFile(l.status.RunDir))
 	l.mem.Stop()
+	if l.rds != nil {
+		_ = l.rds.Stop()
+	}
+	if l.jgr != nil {
+		l.tracer.Flush()
+		_ = l.jgr.Stop()
+	}
 	return l.mio.Stop()
 }
 
@@ -84,6 +129,18 @@ func (l *localServices) Status() *LocalServicesStatus {
 	return l.status
 }
 
+func (l *localServices) Record(rec *Recorder) {
+	l.rec = rec
+}
+
+func (l *localServices) Trace() {
+	l.trace = true
+}
+
+func (l *localServices) Redeliver(r Redelivery) {
+	l.redel = r
+}
+
 func (l *localServices) Start(pool worker.WorkerPool) error {
 	var err error
 
@@ -102,7 +159,37 @@ func (l *localServices) Start(pool worker.WorkerPool) error {
 	if err != nil {
 		return err
 	}
-	l.status.MinioEndpoint = fmt.Sprintf("localhost:%d", l.mio.GetApiPort())
+	l.status.MinioEndpoint = net.JoinHostPort(containerengine.PublishedHost(), strconv.Itoa(l.mio.GetApiPort()))
+
+	// start redis, only needed when the project declares caches
+	if len(l.s.Caches) > 0 {
+		l.rds, err = NewRedis(l.status.RunDir, l.s.Name)
+		if err != nil {
+			return err
+		}
+
+		err = l.rds.Start()
+		if err != nil {
+			return err
+		}
+		l.status.RedisPort = l.rds.GetApiPort()
+	}
+
+	// start jaeger, which receives the traces of the gateway, topics and functions
+	if l.trace {
+		l.jgr, err = NewJaeger(l.status.RunDir, l.s.Name)
+		if err != nil {
+			return err
+		}
+
+		err = l.jgr.Start()
+		if err != nil {
+			return err
+		}
+		l.status.OtlpPort = l.jgr.GetOtlpPort()
+		l.status.TracesURL = fmt.Sprintf("http://localhost:%d", l.jgr.GetUIPort())
+		l.tracer = NewTracer(fmt.Sprintf("http://%s", net.JoinHostPort(containerengine.PublishedHost(), strconv.Itoa(l.status.OtlpPort))))
+	}
 
 	// Connect dev storage
 	os.Setenv(minio.MINIO_ENDPOINT_ENV, l.status.MinioEndpoint)
@@ -126,6 +213,10 @@ func (l *localServices) Start(pool worker.WorkerPool) error {
 	if err != nil {
 		return err
 	}
+	secretValues, err := ReadLocalSecrets(l.s)
+	if err != nil {
+		return err
+	}
 
 	// Connect queue plugin
 	os.Setenv("LOCAL_QUEUE_DIR", l.status.RunDir)
@@ -133,14 +224,20 @@ func (l *localServices) Start(pool worker.WorkerPool) error {
 	if err != nil {
 		return err
 	}
+	queues := newLocalQueueService(qp, l.redel)
+
+	ev, err := NewEvents(pool, l.s, l.tracer, queues, l.redel)
+	if err != nil {
+		return err
+	}
 
-	ev, err := NewEvents(pool)
+	workflows, err := workflowTopics(l.s)
 	if err != nil {
 		return err
 	}
 
 	// Start a new gateway plugin
-	gw, err := NewGateway(l.status.GatewayAddress)
+	gw, err := NewGateway(l.status.GatewayAddress, workflows, l.rec, l.tracer)
 	if err != nil {
 		return err
 	}
@@ -150,8 +247,8 @@ func (l *localServices) Start(pool worker.WorkerPool) error {
 	// running functions will connect to
 	l.mem, err = membrane.New(&membrane.MembraneOptions{
 		ServiceAddress:          "0.0.0.0:50051",
-		SecretPlugin:            secp,
-		QueuePlugin:             qp,
+		SecretPlugin:            newLocalSecretService(secp, secretValues),
+		QueuePlugin:             queues,
 		StoragePlugin:           sp,
 		DocumentPlugin:          dp,
 		GatewayPlugin:           gw,
@@ -163,5 +260,33 @@ func (l *localServices) Start(pool worker.WorkerPool) error {
 		return err
 	}
 
+	if err := writeStatus(l.status); err != nil {
+		return err
+	}
+
 	return l.mem.Start()
 }
+
+func statusFile(runDir string) string {
+	return filepath.Join(runDir, "status.yaml")
+}
+
+// writeStatus records the running services, so other commands can find them.
+func writeStatus(status *LocalServicesStatus) error {
+	b, err := yaml.Marshal(status)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(statusFile(status.RunDir), b, 0644)
+}
+
+// ReadLocalStatus returns the status of the project's services recorded by 'nitric run'.
+func ReadLocalStatus(s *project.Project) (*LocalServicesStatus, error) {
+	b, err := ioutil.ReadFile(statusFile(filepath.Join(utils.NitricRunDir(), s.Name)))
+	if err != nil {
+		return nil, errors.WithMessage(err, "reading the local services status")
+	}
+
+	status := &LocalServicesStatus{}
+	return status, yaml.Unmarshal(b, status)
+}
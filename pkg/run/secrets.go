@@ -0,0 +1,205 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/cli/pkg/utils"
+	"github.com/nitrictech/nitric/pkg/plugins/secret"
+)
+
+// localSecretVersion is the version 'nitric run' gives the values of the local secrets file.
+const localSecretVersion = "local"
+
+// LocalSecretsFile is the file of the project's local secret values, they are encrypted with a key
+// kept in the user's config dir so the file can't be read if it is committed by mistake.
+func LocalSecretsFile(dir string) string {
+	return filepath.Join(utils.NitricLogDir(dir), "secrets.yaml")
+}
+
+func localSecretsKeyFile() string {
+	return filepath.Join(utils.NitricConfigDir(), "local-secrets.key")
+}
+
+// localSecretsKey reads the key the local secrets are encrypted with, creating it the first time.
+func localSecretsKey() ([]byte, error) {
+	b, err := ioutil.ReadFile(localSecretsKeyFile())
+	if err == nil {
+		return base64.StdEncoding.DecodeString(string(b))
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(utils.NitricConfigDir(), 0700); err != nil {
+		return nil, err
+	}
+	return key, ioutil.WriteFile(localSecretsKeyFile(), []byte(base64.StdEncoding.EncodeToString(key)), 0600)
+}
+
+func encryptLocalSecret(key []byte, value []byte) (string, error) {
+	gcm, err := localSecretsCipher(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, value, nil)), nil
+}
+
+func decryptLocalSecret(key []byte, ciphertext string) ([]byte, error) {
+	gcm, err := localSecretsCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	b, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < gcm.NonceSize() {
+		return nil, errors.New("the value is too short")
+	}
+	return gcm.Open(nil, b[:gcm.NonceSize()], b[gcm.NonceSize():], nil)
+}
+
+func localSecretsCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func readLocalSecretsFile(dir string) (map[string]string, error) {
+	ciphertexts := map[string]string{}
+	b, err := ioutil.ReadFile(LocalSecretsFile(dir))
+	if os.IsNotExist(err) {
+		return ciphertexts, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ciphertexts, yaml.Unmarshal(b, &ciphertexts)
+}
+
+// SetLocalSecret encrypts the value of the secret into the local secrets file of the project in dir, replacing
+// any previous value. Secrets are only declared by running the handlers, so values for secrets the project
+// doesn't declare are kept but not served.
+func SetLocalSecret(dir string, name string, value []byte) error {
+	if name == "" {
+		return errors.New("the secret name is required")
+	}
+
+	key, err := localSecretsKey()
+	if err != nil {
+		return errors.WithMessage(err, "reading the local secrets key")
+	}
+
+	ciphertexts, err := readLocalSecretsFile(dir)
+	if err != nil {
+		return err
+	}
+
+	ciphertexts[name], err = encryptLocalSecret(key, value)
+	if err != nil {
+		return err
+	}
+
+	b, err := yaml.Marshal(ciphertexts)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(utils.NitricLogDir(dir), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(LocalSecretsFile(dir), b, 0600)
+}
+
+// ReadLocalSecrets returns the values of the local secrets file for the secrets the project declares.
+func ReadLocalSecrets(s *project.Project) (map[string][]byte, error) {
+	ciphertexts, err := readLocalSecretsFile(s.Dir)
+	if err != nil || len(ciphertexts) == 0 {
+		return map[string][]byte{}, err
+	}
+
+	key, err := localSecretsKey()
+	if err != nil {
+		return nil, errors.WithMessage(err, "reading the local secrets key")
+	}
+
+	values := map[string][]byte{}
+	for name, ciphertext := range ciphertexts {
+		if _, ok := s.Secrets[name]; !ok {
+			continue
+		}
+		values[name], err = decryptLocalSecret(key, ciphertext)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "decrypting the local secret %s, set it again with 'nitric secrets set --local'", name)
+		}
+	}
+	return values, nil
+}
+
+// localSecretService serves the values of the local secrets file for secrets that have not been put while running.
+type localSecretService struct {
+	secret.SecretService
+	values map[string][]byte
+}
+
+func newLocalSecretService(sec secret.SecretService, values map[string][]byte) secret.SecretService {
+	return &localSecretService{SecretService: sec, values: values}
+}
+
+func (s *localSecretService) Access(sv *secret.SecretVersion) (*secret.SecretAccessResponse, error) {
+	value, ok := s.values[sv.Secret.Name]
+	if !ok || (sv.Version != "latest" && sv.Version != localSecretVersion) {
+		return s.SecretService.Access(sv)
+	}
+
+	// values put while running take precedence over the local file
+	if sv.Version == "latest" {
+		if resp, err := s.SecretService.Access(sv); err == nil {
+			return resp, nil
+		}
+	}
+
+	return &secret.SecretAccessResponse{
+		SecretVersion: &secret.SecretVersion{
+			Secret:  &secret.Secret{Name: sv.Secret.Name},
+			Version: localSecretVersion,
+		},
+		Value: value,
+	}, nil
+}
@@ -0,0 +1,85 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/nitric/pkg/plugins/secret"
+)
+
+func TestLocalSecrets(t *testing.T) {
+	old := os.Getenv("XDG_CONFIG_HOME")
+	defer os.Setenv("XDG_CONFIG_HOME", old)
+	os.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	s := &project.Project{Dir: t.TempDir(), Secrets: map[string]project.Secret{"api-key": {}}}
+	if err := SetLocalSecret(s.Dir, "api-key", []byte("first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetLocalSecret(s.Dir, "api-key", []byte("s3cret")); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetLocalSecret(s.Dir, "other", []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := ReadLocalSecrets(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(values["api-key"]) != "s3cret" || len(values) != 1 {
+		t.Errorf("ReadLocalSecrets() = %v", values)
+	}
+
+	b, err := ioutil.ReadFile(LocalSecretsFile(s.Dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) == 0 || strings.Contains(string(b), "s3cret") {
+		t.Errorf("expected the local secrets file to be encrypted, got %s", b)
+	}
+}
+
+type emptySecrets struct {
+	secret.SecretService
+}
+
+func (emptySecrets) Access(sv *secret.SecretVersion) (*secret.SecretAccessResponse, error) {
+	return nil, errors.New("not found")
+}
+
+func TestLocalSecretService(t *testing.T) {
+	sec := newLocalSecretService(emptySecrets{}, map[string][]byte{"api-key": []byte("s3cret")})
+
+	resp, err := sec.Access(&secret.SecretVersion{Secret: &secret.Secret{Name: "api-key"}, Version: "latest"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.Value) != "s3cret" || resp.SecretVersion.Version != localSecretVersion {
+		t.Errorf("Access() = %+v", resp)
+	}
+
+	if _, err := sec.Access(&secret.SecretVersion{Secret: &secret.Secret{Name: "other"}, Version: "latest"}); err == nil {
+		t.Error("expected secrets without a local value to be accessed from the dev service")
+	}
+}
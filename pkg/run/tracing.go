@@ -0,0 +1,203 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+// OTLP span kinds
+const (
+	spanKindServer   = 2
+	spanKindProducer = 4
+	spanKindConsumer = 5
+)
+
+var traceparentFormat = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-[0-9a-f]{2}$`)
+
+// Tracer exports spans of the local gateway and topics to an OTLP/HTTP collector, so the requests and
+// messages handled by 'nitric run' are seen alongside the spans of the functions. A nil Tracer
+// starts nil spans, which do nothing.
+type Tracer struct {
+	endpoint string
+	client   *http.Client
+	wg       sync.WaitGroup
+}
+
+// Span is a unit of work in a trace, exported when it ends.
+type Span struct {
+	t          *Tracer
+	traceID    string
+	spanID     string
+	parentID   string
+	name       string
+	kind       int
+	start      time.Time
+	attributes map[string]string
+}
+
+// NewTracer exports to the OTLP/HTTP collector at endpoint, e.g. http://localhost:4318
+func NewTracer(endpoint string) *Tracer {
+	return &Tracer{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func randomID(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// StartSpan starts a span, continuing the trace of the W3C traceparent when it is valid.
+func (t *Tracer) StartSpan(name string, kind int, traceparent string) *Span {
+	if t == nil {
+		return nil
+	}
+
+	s := &Span{
+		t:          t,
+		traceID:    randomID(16),
+		spanID:     randomID(8),
+		name:       name,
+		kind:       kind,
+		start:      time.Now(),
+		attributes: map[string]string{},
+	}
+	if m := traceparentFormat.FindStringSubmatch(traceparent); m != nil {
+		s.traceID, s.parentID = m[1], m[2]
+	}
+	return s
+}
+
+// StartChild starts a span caused by this one.
+func (s *Span) StartChild(name string, kind int) *Span {
+	if s == nil {
+		return nil
+	}
+	return s.t.StartSpan(name, kind, s.Traceparent())
+}
+
+// Traceparent is the W3C traceparent of the span, passed on so the work it causes joins its trace.
+func (s *Span) Traceparent() string {
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", s.traceID, s.spanID)
+}
+
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.attributes[key] = value
+}
+
+// End exports the span, it failed when err is not nil.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+
+	attrs := []map[string]interface{}{}
+	keys := []string{}
+	for k := range s.attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attrs = append(attrs, map[string]interface{}{"key": k, "value": map[string]string{"stringValue": s.attributes[k]}})
+	}
+
+	span := map[string]interface{}{
+		"traceId":           s.traceID,
+		"spanId":            s.spanID,
+		"name":              s.name,
+		"kind":              s.kind,
+		"startTimeUnixNano": strconv.FormatInt(s.start.UnixNano(), 10),
+		"endTimeUnixNano":   strconv.FormatInt(time.Now().UnixNano(), 10),
+		"attributes":        attrs,
+		"status":            map[string]interface{}{"code": 1},
+	}
+	if s.parentID != "" {
+		span["parentSpanId"] = s.parentID
+	}
+	if err != nil {
+		span["status"] = map[string]interface{}{"code": 2, "message": err.Error()}
+	}
+
+	s.t.export(map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": []interface{}{
+						map[string]interface{}{"key": "service.name", "value": map[string]string{"stringValue": "nitric"}},
+					},
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]string{"name": "nitric-run"},
+						"spans": []interface{}{span},
+					},
+				},
+			},
+		},
+	})
+}
+
+// export posts the spans in the background, failures are only logged as tracing is best effort.
+func (t *Tracer) export(body interface{}) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		pterm.Debug.Println("tracing:", err)
+		return
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		resp, err := t.client.Post(t.endpoint+"/v1/traces", "application/json", bytes.NewReader(b))
+		if err != nil {
+			pterm.Debug.Println("tracing:", err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			pterm.Debug.Println("tracing: collector responded with", resp.Status)
+		}
+	}()
+}
+
+// Flush waits for the spans being exported.
+func (t *Tracer) Flush() {
+	if t != nil {
+		t.wg.Wait()
+	}
+}
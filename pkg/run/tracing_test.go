@@ -0,0 +1,89 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestTracer(t *testing.T) {
+	lck := sync.Mutex{}
+	spans := []map[string]interface{}{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		body := struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []map[string]interface{} `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		lck.Lock()
+		defer lck.Unlock()
+		spans = append(spans, body.ResourceSpans[0].ScopeSpans[0].Spans...)
+	}))
+	defer srv.Close()
+
+	tracer := NewTracer(srv.URL)
+	parent := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	span := tracer.StartSpan("GET /orders", spanKindServer, parent)
+	child := span.StartChild("deliver orders", spanKindConsumer)
+	child.End(errors.New("handler failed"))
+	span.End(nil)
+	tracer.Flush()
+
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	byName := map[string]map[string]interface{}{}
+	for _, s := range spans {
+		byName[s["name"].(string)] = s
+	}
+
+	root := byName["GET /orders"]
+	if root["traceId"] != "0af7651916cd43dd8448eb211c80319c" || root["parentSpanId"] != "b7ad6b7169203331" {
+		t.Errorf("expected the span to continue the traceparent, got %v", root)
+	}
+	delivery := byName["deliver orders"]
+	if delivery["traceId"] != root["traceId"] || delivery["parentSpanId"] != root["spanId"] {
+		t.Errorf("expected the child to be in the span's trace, got %v", delivery)
+	}
+	if status := delivery["status"].(map[string]interface{}); status["code"] != float64(2) {
+		t.Errorf("expected the failed delivery to have an error status, got %v", status)
+	}
+}
+
+func TestNilTracer(t *testing.T) {
+	var tracer *Tracer
+	span := tracer.StartSpan("GET /orders", spanKindServer, "")
+	span.SetAttribute("nitric.api", "main")
+	span.StartChild("deliver orders", spanKindConsumer).End(nil)
+	span.End(nil)
+	if span.Traceparent() != "" {
+		t.Errorf("expected no traceparent without a tracer, got %s", span.Traceparent())
+	}
+}
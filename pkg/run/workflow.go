@@ -0,0 +1,71 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/project"
+	"github.com/nitrictech/nitric/pkg/triggers"
+	"github.com/nitrictech/nitric/pkg/worker"
+)
+
+// workflowTopics resolves the topic used to deliver each workflow step.
+func workflowTopics(p *project.Project) (map[string][]string, error) {
+	workflows := map[string][]string{}
+	for name, w := range p.Workflows {
+		if len(w.Steps) == 0 {
+			return nil, fmt.Errorf("workflow %s has no steps", name)
+		}
+		for _, step := range w.Steps {
+			topic, err := p.WorkflowStepTopic(step)
+			if err != nil {
+				return nil, errors.WithMessage(err, "workflow "+name)
+			}
+			workflows[name] = append(workflows[name], topic)
+		}
+	}
+	return workflows, nil
+}
+
+// runWorkflow simulates a deployed workflow by delivering the payload to the subscribers of each step's
+// topic in turn, stopping at the first step that fails.
+func runWorkflow(pool worker.WorkerPool, name string, stepTopics []string, payload []byte) error {
+	for i, topic := range stepTopics {
+		evt := &triggers.Event{
+			ID:      name,
+			Topic:   topic,
+			Payload: payload,
+		}
+
+		ws := pool.GetWorkers(&worker.GetWorkerOptions{
+			Event: evt,
+		})
+		if len(ws) == 0 {
+			return fmt.Errorf("step %d: no subscribers found for topic %s", i+1, topic)
+		}
+
+		for _, w := range ws {
+			if err := w.HandleEvent(evt); err != nil {
+				return errors.WithMessagef(err, "step %d", i+1)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"bytes"
+	"embed"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// DockerfileVars are the variables of the Dockerfile templates.
+type DockerfileVars struct {
+	// Handler is the path of the function's handler in the project, with forward slashes
+	Handler string
+	// HandlerDir is the directory of the handler in the project, with forward slashes
+	HandlerDir string
+	// Version of the membrane
+	Version string
+	// Provider the image is built for, e.g. aws
+	Provider string
+	// MembraneURL is where the membrane of the provider is downloaded from
+	MembraneURL string
+	// PomFiles are the maven pom.xml files of the project, only set for java
+	PomFiles []string
+}
+
+// dockerfileFuncs are the functions the Dockerfile templates can use, as well as {{ template "membrane" . }}
+// which adds the membrane and makes it the entrypoint.
+var dockerfileFuncs = template.FuncMap{
+	"dir":  path.Dir,
+	"join": path.Join,
+}
+
+// DockerfileTemplate is the file in the project that overrides the Dockerfile template of the runtime.
+func DockerfileTemplate(dir string, rte RuntimeExt) string {
+	return filepath.Join(utils.NitricLogDir(dir), "templates", string(rte)+".dockerfile.tmpl")
+}
+
+// DefaultDockerfileTemplate is the Dockerfile template used when the project doesn't override it.
+func DefaultDockerfileTemplate(rte RuntimeExt) ([]byte, error) {
+	return templates.ReadFile("templates/" + string(rte) + ".dockerfile.tmpl")
+}
+
+func newDockerfileVars(handler, version, provider string) DockerfileVars {
+	return DockerfileVars{
+		Handler:     filepath.ToSlash(handler),
+		HandlerDir:  filepath.ToSlash(filepath.Dir(handler)),
+		Version:     version,
+		Provider:    provider,
+		MembraneURL: membraneURL(version, provider),
+	}
+}
+
+// functionDockerfile renders the runtime's Dockerfile template, the project's own when it has one.
+func functionDockerfile(funcCtxDir string, rte RuntimeExt, vars DockerfileVars, w io.Writer) error {
+	file := DockerfileTemplate(funcCtxDir, rte)
+	body, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		file = string(rte) + ".dockerfile.tmpl"
+		body, err = DefaultDockerfileTemplate(rte)
+	}
+	if err != nil {
+		return err
+	}
+
+	membrane, err := templates.ReadFile("templates/membrane.tmpl")
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("membrane").Funcs(dockerfileFuncs).Parse(string(membrane))
+	if err != nil {
+		return err
+	}
+	tmpl, err = tmpl.New(file).Parse(string(body))
+	if err != nil {
+		return errors.WithMessagef(err, "parsing the Dockerfile template %s", file)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, vars); err != nil {
+		return errors.WithMessagef(err, "rendering the Dockerfile template %s", file)
+	}
+
+	_, err = w.Write(bytes.TrimRight(buf.Bytes(), "\n"))
+	return err
+}
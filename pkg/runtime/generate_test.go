@@ -18,6 +18,8 @@ package runtime
 
 import (
 	"bytes"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -154,6 +156,41 @@ ENTRYPOINT ["/usr/local/bin/membrane"]`,
 	}
 }
 
+func TestGenerateFromProjectTemplate(t *testing.T) {
+	dir := t.TempDir()
+	file := DockerfileTemplate(dir, RuntimePython)
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	tmpl := `FROM python:3.10-slim
+COPY . .
+{{ template "membrane" . }}
+CMD ["python", "{{ .Handler }}", "{{ .Provider }}"]
+`
+	if err := ioutil.WriteFile(file, []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rt, err := NewRunTimeFromHandler("functions/list.py")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fwriter := &bytes.Buffer{}
+	if err := rt.FunctionDockerfile(dir, "v1.1.7", "gcp", fwriter); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `FROM python:3.10-slim
+COPY . .
+ADD https://github.com/nitrictech/nitric/releases/download/v1.1.7/membrane-gcp /usr/local/bin/membrane
+RUN chmod +x-rw /usr/local/bin/membrane
+ENTRYPOINT ["/usr/local/bin/membrane"]
+CMD ["python", "functions/list.py", "gcp"]`
+	if !cmp.Equal(fwriter.String(), want) {
+		t.Error(cmp.Diff(want, fwriter.String()))
+	}
+}
+
 func TestGeneralFuncs(t *testing.T) {
 	tests := []struct {
 		handler       string
@@ -30,6 +30,11 @@ import (
 	"github.com/nitrictech/cli/pkg/utils"
 )
 
+const (
+	golangBuildImage   = "golang:alpine"
+	golangRuntimeImage = "alpine"
+)
+
 type golang struct {
 	rte     RuntimeExt
 	handler string
@@ -37,6 +42,10 @@ type golang struct {
 
 var _ Runtime = &golang{}
 
+func (t *golang) BaseImages() []string {
+	return []string{golangBuildImage, golangRuntimeImage}
+}
+
 func (t *golang) DevImageName() string {
 	return fmt.Sprintf("nitric-%s-dev", t.rte)
 }
@@ -56,68 +65,12 @@ func (t *golang) ContainerName() string {
 }
 
 func (t *golang) FunctionDockerfile(funcCtxDir, version, provider string, w io.Writer) error {
-	buildCon, err := dockerfile.NewContainer(dockerfile.NewContainerOpts{
-		From:   "golang:alpine",
-		As:     "build",
-		Ignore: t.BuildIgnore(),
-	})
-	if err != nil {
-		return err
-	}
-
-	buildCon.Run(dockerfile.RunOptions{Command: []string{"apk", "update"}})
-	buildCon.Run(dockerfile.RunOptions{Command: []string{"apk", "upgrade"}})
-	buildCon.Run(dockerfile.RunOptions{Command: []string{"apk", "add", "--no-cache", "git", "gcc", "g++", "make"}})
-	buildCon.Config(dockerfile.ConfigOptions{
-		WorkingDir: "/app/",
-	})
-
-	err = buildCon.Copy(dockerfile.CopyOptions{Src: "go.mod *.sum", Dest: "./"})
-	if err != nil {
-		return err
-	}
-	buildCon.Run(dockerfile.RunOptions{Command: []string{"go", "mod", "download"}})
-	err = buildCon.Copy(dockerfile.CopyOptions{Src: ".", Dest: "."})
-	if err != nil {
-		return err
-	}
-
-	buildCon.Run(dockerfile.RunOptions{
-		Command: []string{
-			"go", "build", "-o", "/bin/main", "./" + filepath.Dir(t.handler) + "/...",
-		},
-	})
-
-	con, err := dockerfile.NewContainer(dockerfile.NewContainerOpts{
-		From:   "alpine",
-		Ignore: []string{},
-	})
-	if err != nil {
-		return err
-	}
-
-	withMembrane(con, version, provider)
-
-	err = con.Copy(dockerfile.CopyOptions{Src: "/bin/main", Dest: "/bin/main", From: "build"})
-	if err != nil {
-		return err
-	}
-	con.Run(dockerfile.RunOptions{Command: []string{"chmod", "+x-rw", "/bin/main"}})
-	con.Run(dockerfile.RunOptions{Command: []string{"apk", "add", "--no-cache", "tzdata"}})
-
-	con.Config(dockerfile.ConfigOptions{
-		Ports:      []int32{9001},
-		WorkingDir: "/",
-		Cmd:        []string{"/bin/main"},
-	})
-
-	_, err = w.Write([]byte(strings.Join(append(buildCon.Lines(), con.Lines()...), "\n")))
-	return err
+	return functionDockerfile(funcCtxDir, t.rte, newDockerfileVars(t.handler, version, provider), w)
 }
 
 func (t *golang) FunctionDockerfileForCodeAsConfig(w io.Writer) error {
 	con, err := dockerfile.NewContainer(dockerfile.NewContainerOpts{
-		From:   "golang:alpine",
+		From:   golangBuildImage,
 		Ignore: t.BuildIgnore(),
 	})
 	if err != nil {
@@ -130,6 +83,10 @@ func (t *golang) FunctionDockerfileForCodeAsConfig(w io.Writer) error {
 	return err
 }
 
+func (t *golang) NativeCommandForFunctionCollect(runCtx string) ([]string, error) {
+	return nativeCommand("go", "run", "./"+filepath.ToSlash(filepath.Dir(t.handler))+"/...")
+}
+
 func (t *golang) LaunchOptsForFunctionCollect(runCtx string) (LaunchOpts, error) {
 	module, err := utils.GoModule(runCtx)
 	if err != nil {
@@ -19,11 +19,8 @@ package runtime
 import (
 	"fmt"
 	"io"
-	"path"
 	"path/filepath"
-	"strings"
 
-	"github.com/nitrictech/boxygen/pkg/backend/dockerfile"
 	"github.com/nitrictech/cli/pkg/utils"
 )
 
@@ -39,6 +36,10 @@ type java struct {
 
 var _ Runtime = &java{}
 
+func (t *java) BaseImages() []string {
+	return []string{mavenOpenJDKImage, jvmRuntimeBaseImage}
+}
+
 func (t *java) DevImageName() string {
 	return fmt.Sprintf("nitric-%s-dev", t.rte)
 }
@@ -55,6 +56,10 @@ func (t *java) LaunchOptsForFunctionCollect(runCtx string) (LaunchOpts, error) {
 	return LaunchOpts{}, utils.NewNotSupportedErr("code-as-config not supported on " + string(t.rte))
 }
 
+func (t *java) NativeCommandForFunctionCollect(runCtx string) ([]string, error) {
+	return nil, utils.NewNotSupportedErr("code-as-config not supported on " + string(t.rte))
+}
+
 func (t *java) LaunchOptsForFunction(runCtx string) (LaunchOpts, error) {
 	return LaunchOpts{}, utils.NewNotSupportedErr("code-as-config not supported on " + string(t.rte))
 }
@@ -64,63 +69,16 @@ func (t *java) BuildIgnore() []string {
 }
 
 func (t *java) FunctionDockerfile(funcCtxDir, version, provider string, w io.Writer) error {
-	buildCon, err := dockerfile.NewContainer(dockerfile.NewContainerOpts{
-		From:   mavenOpenJDKImage,
-		As:     "build",
-		Ignore: []string{},
-	})
-	if err != nil {
-		return err
-	}
-	err = mavenBuild(buildCon, funcCtxDir)
-	if err != nil {
-		return err
-	}
-
-	con, err := dockerfile.NewContainer(dockerfile.NewContainerOpts{
-		From:   jvmRuntimeBaseImage,
-		Ignore: []string{},
-	})
-	if err != nil {
-		return err
-	}
+	vars := newDockerfileVars(t.handler, version, provider)
 
-	err = con.Copy(dockerfile.CopyOptions{Src: t.handler, Dest: "function.jar", From: "build"})
+	pomFiles, err := utils.FindFilesInDir(funcCtxDir, "pom.xml")
 	if err != nil {
 		return err
 	}
-	con.Config(dockerfile.ConfigOptions{
-		WorkingDir: "/",
-		Ports:      []int32{9001},
-		Cmd:        []string{"java", "-jar", "function.jar"},
-	})
-	withMembrane(con, version, provider)
-
-	_, err = w.Write([]byte(strings.Join(append(buildCon.Lines(), con.Lines()...), "\n")))
-	return err
-}
-
-func mavenBuild(con dockerfile.ContainerState, contextDir string) error {
-	pomFiles, err := utils.FindFilesInDir(contextDir, "pom.xml")
-	if err != nil {
-		return err
-	}
-
-	moduleDirs := []string{}
 	for _, p := range pomFiles {
-		moduleDirs = append(moduleDirs, path.Dir(p))
-		err = con.Copy(dockerfile.CopyOptions{Src: p, Dest: filepath.Join("./", p)})
-		if err != nil {
-			return err
-		}
+		// Dockerfile paths always use forward slashes
+		vars.PomFiles = append(vars.PomFiles, filepath.ToSlash(p))
 	}
-	con.Run(dockerfile.RunOptions{Command: []string{"mvn", "de.qaware.maven:go-offline-maven-plugin:resolve-dependencies"}})
-	for _, d := range moduleDirs {
-		err = con.Copy(dockerfile.CopyOptions{Src: d, Dest: filepath.Join("./", d)})
-		if err != nil {
-			return err
-		}
-	}
-	con.Run(dockerfile.RunOptions{Command: []string{"mvn", "clean", "package"}})
-	return nil
+
+	return functionDockerfile(funcCtxDir, t.rte, vars, w)
 }
@@ -29,6 +29,8 @@ import (
 	"github.com/nitrictech/boxygen/pkg/backend/dockerfile"
 )
 
+const nodeImage = "node:alpine"
+
 type javascript struct {
 	rte     RuntimeExt
 	handler string
@@ -39,6 +41,10 @@ var (
 	javascriptIgnoreList         = []string{"node_modules/", ".nitric/", ".git/", ".idea/"}
 )
 
+func (t *javascript) BaseImages() []string {
+	return []string{nodeImage}
+}
+
 func (t *javascript) DevImageName() string {
 	return fmt.Sprintf("nitric-%s-dev", t.rte)
 }
@@ -52,40 +58,12 @@ func (t *javascript) BuildIgnore() []string {
 }
 
 func (t *javascript) FunctionDockerfile(funcCtxDir, version, provider string, w io.Writer) error {
-	con, err := dockerfile.NewContainer(dockerfile.NewContainerOpts{
-		From:   "node:alpine",
-		Ignore: javascriptIgnoreList,
-	})
-	if err != nil {
-		return err
-	}
-	withMembrane(con, version, provider)
-
-	err = con.Copy(dockerfile.CopyOptions{Src: "package.json *.lock *-lock.json", Dest: "/"})
-	if err != nil {
-		return err
-	}
-	con.Run(dockerfile.RunOptions{Command: []string{"yarn", "import", "||", "echo", "Lockfile already exists"}})
-	con.Run(dockerfile.RunOptions{Command: []string{
-		"set", "-ex;",
-		"yarn", "install", "--production", "--frozen-lockfile", "--cache-folder", "/tmp/.cache;",
-		"rm", "-rf", "/tmp/.cache;"}})
-
-	err = con.Copy(dockerfile.CopyOptions{Src: ".", Dest: "."})
-	if err != nil {
-		return err
-	}
-	con.Config(dockerfile.ConfigOptions{
-		Cmd: []string{"node", t.handler},
-	})
-
-	_, err = w.Write([]byte(strings.Join(con.Lines(), "\n")))
-	return err
+	return functionDockerfile(funcCtxDir, t.rte, newDockerfileVars(t.handler, version, provider), w)
 }
 
 func (t *javascript) FunctionDockerfileForCodeAsConfig(w io.Writer) error {
 	con, err := dockerfile.NewContainer(dockerfile.NewContainerOpts{
-		From:   "node:alpine",
+		From:   nodeImage,
 		Ignore: javascriptIgnoreList,
 	})
 	if err != nil {
@@ -118,6 +96,10 @@ func (t *javascript) LaunchOptsForFunctionCollect(runCtx string) (LaunchOpts, er
 	}, nil
 }
 
+func (t *javascript) NativeCommandForFunctionCollect(runCtx string) ([]string, error) {
+	return nativeCommand("node", t.handler)
+}
+
 func (t *javascript) LaunchOptsForFunction(runCtx string) (LaunchOpts, error) {
 	var cmd []string
 
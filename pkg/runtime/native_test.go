@@ -0,0 +1,77 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+func TestNativeCommandForFunctionCollect(t *testing.T) {
+	dir := t.TempDir()
+	tsNode := filepath.Join(dir, "node_modules", ".bin", "ts-node")
+	if err := os.MkdirAll(filepath.Dir(tsNode), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(tsNode, []byte{}, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		handler      string
+		want         []string
+		notSupported bool
+	}{
+		{
+			name:    "project ts-node",
+			handler: "functions/list.ts",
+			want:    []string{tsNode, "-T", "functions/list.ts"},
+		},
+		{
+			name:         "python",
+			handler:      "functions/list.py",
+			notSupported: true,
+		},
+		{
+			name:         "java",
+			handler:      "functions/list.java",
+			notSupported: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt, err := NewRunTimeFromHandler(tt.handler)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := rt.NativeCommandForFunctionCollect(dir)
+			if _, ok := err.(*utils.NotSupportedError); ok != tt.notSupported {
+				t.Fatalf("NativeCommandForFunctionCollect() error = %v, notSupported %v", err, tt.notSupported)
+			}
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
@@ -22,10 +22,11 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/nitrictech/boxygen/pkg/backend/dockerfile"
 	"github.com/nitrictech/cli/pkg/utils"
 )
 
+const pythonImage = "python:3.7-slim"
+
 type python struct {
 	rte     RuntimeExt
 	handler string
@@ -33,6 +34,10 @@ type python struct {
 
 var _ Runtime = &python{}
 
+func (t *python) BaseImages() []string {
+	return []string{pythonImage}
+}
+
 func (t *python) DevImageName() string {
 	return fmt.Sprintf("nitric-%s-dev", t.rte)
 }
@@ -53,42 +58,14 @@ func (t *python) LaunchOptsForFunctionCollect(runCtx string) (LaunchOpts, error)
 	return LaunchOpts{}, utils.NewNotSupportedErr("code-as-config not supported on " + string(t.rte))
 }
 
+func (t *python) NativeCommandForFunctionCollect(runCtx string) ([]string, error) {
+	return nil, utils.NewNotSupportedErr("code-as-config not supported on " + string(t.rte))
+}
+
 func (t *python) LaunchOptsForFunction(runCtx string) (LaunchOpts, error) {
 	return LaunchOpts{}, utils.NewNotSupportedErr("code-as-config not supported on " + string(t.rte))
 }
 
 func (t *python) FunctionDockerfile(funcCtxDir, version, provider string, w io.Writer) error {
-	con, err := dockerfile.NewContainer(dockerfile.NewContainerOpts{
-		From:   "python:3.7-slim",
-		Ignore: t.BuildIgnore(),
-	})
-	if err != nil {
-		return err
-	}
-
-	con.Run(dockerfile.RunOptions{Command: []string{"pip", "install", "--upgrade", "pip"}})
-	con.Config(dockerfile.ConfigOptions{
-		WorkingDir: "/",
-	})
-	err = con.Copy(dockerfile.CopyOptions{Src: "requirements.txt", Dest: "requirements.txt"})
-	if err != nil {
-		return err
-	}
-	con.Run(dockerfile.RunOptions{Command: []string{"pip", "install", "--no-cache-dir", "-r", "requirements.txt"}})
-	err = con.Copy(dockerfile.CopyOptions{Src: ".", Dest: "."})
-	if err != nil {
-		return err
-	}
-
-	withMembrane(con, version, provider)
-
-	con.Config(dockerfile.ConfigOptions{
-		Env: map[string]string{
-			"PYTHONPATH": "/app/:${PYTHONPATH}",
-		},
-		Ports: []int32{9001},
-		Cmd:   []string{"python", t.handler},
-	})
-	_, err = w.Write([]byte(strings.Join(con.Lines(), "\n")))
-	return err
+	return functionDockerfile(funcCtxDir, t.rte, newDockerfileVars(t.handler, version, provider), w)
 }
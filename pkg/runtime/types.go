@@ -21,15 +21,18 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/docker/docker/api/types/mount"
 
-	"github.com/nitrictech/boxygen/pkg/backend/dockerfile"
+	"github.com/nitrictech/cli/pkg/utils"
 )
 
 type Runtime interface {
+	// BaseImages are the images the function images are built from
+	BaseImages() []string
 	DevImageName() string
 	ContainerName() string
 	BuildIgnore() []string
@@ -37,6 +40,9 @@ type Runtime interface {
 	FunctionDockerfileForCodeAsConfig(w io.Writer) error // FunctionDockerfileForCodeAsConfig generates a base image for code-as-config
 	LaunchOptsForFunction(stackDir string) (LaunchOpts, error)
 	LaunchOptsForFunctionCollect(stackDir string) (LaunchOpts, error)
+	// NativeCommandForFunctionCollect is the command that runs the handler on the host to collect
+	// its code-as-config, it returns a NotSupportedError when the toolchain isn't installed.
+	NativeCommandForFunctionCollect(stackDir string) ([]string, error)
 }
 
 type RuntimeExt string
@@ -78,18 +84,23 @@ func NewRunTimeFromHandler(handler string) (Runtime, error) {
 	}
 }
 
-func withMembrane(con dockerfile.ContainerState, version, provider string) {
+// membraneURL is where the membrane of the provider is downloaded from.
+func membraneURL(version, provider string) string {
 	membraneName := "membrane-" + provider
-	fetchFrom := fmt.Sprintf("https://github.com/nitrictech/nitric/releases/download/%s/%s", version, membraneName)
+	if os.Getenv("LOCAL_MEMBRANE") != "" {
+		return os.Getenv("LOCAL_MEMBRANE") + "/" + membraneName
+	}
 	if version == "latest" {
-		fetchFrom = fmt.Sprintf("https://github.com/nitrictech/nitric/releases/%s/download/%s", version, membraneName)
+		return fmt.Sprintf("https://github.com/nitrictech/nitric/releases/%s/download/%s", version, membraneName)
 	}
-	if os.Getenv("LOCAL_MEMBRANE") != "" {
-		fetchFrom = os.Getenv("LOCAL_MEMBRANE") + "/" + membraneName
+	return fmt.Sprintf("https://github.com/nitrictech/nitric/releases/download/%s/%s", version, membraneName)
+}
+
+// nativeCommand finds tool on the PATH and returns it with args.
+func nativeCommand(tool string, args ...string) ([]string, error) {
+	path, err := exec.LookPath(tool)
+	if err != nil {
+		return nil, utils.NewNotSupportedErr(tool + " is not installed, native code-as-config not supported")
 	}
-	con.Add(dockerfile.AddOptions{Src: fetchFrom, Dest: "/usr/local/bin/membrane"})
-	con.Run(dockerfile.RunOptions{Command: []string{"chmod", "+x-rw", "/usr/local/bin/membrane"}})
-	con.Config(dockerfile.ConfigOptions{
-		Entrypoint: []string{"/usr/local/bin/membrane"},
-	})
+	return append([]string{path}, args...), nil
 }
@@ -19,6 +19,7 @@ package runtime
 import (
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 	osruntime "runtime"
 	"strings"
@@ -36,6 +37,10 @@ type typescript struct {
 
 var _ Runtime = &typescript{}
 
+func (t *typescript) BaseImages() []string {
+	return []string{nodeImage}
+}
+
 func (t *typescript) DevImageName() string {
 	return fmt.Sprintf("nitric-%s-dev", t.rte)
 }
@@ -49,74 +54,12 @@ func (t *typescript) BuildIgnore() []string {
 }
 
 func (t *typescript) FunctionDockerfile(funcCtxDir, version, provider string, w io.Writer) error {
-	css := dockerfile.NewStateStore()
-
-	// Start build stage
-	buildstage, err := css.NewContainer(dockerfile.NewContainerOpts{
-		From:   "node:alpine",
-		As:     "build",
-		Ignore: javascriptIgnoreList,
-	})
-	if err != nil {
-		return err
-	}
-
-	buildstage.Run(dockerfile.RunOptions{Command: []string{"yarn", "global", "add", "typescript", "@vercel/ncc"}})
-	if err := buildstage.Copy(dockerfile.CopyOptions{Src: "package.json *.lock *-lock.json", Dest: "/"}); err != nil {
-		return err
-	}
-	buildstage.Run(dockerfile.RunOptions{Command: []string{"yarn", "import", "||", "echo", "Lockfile already exists"}})
-	buildstage.Run(dockerfile.RunOptions{Command: []string{
-		"set", "-ex;",
-		"yarn", "install", "--production", "--frozen-lockfile", "--cache-folder", "/tmp/.cache;",
-		"rm", "-rf", "/tmp/.cache;"}})
-	err = buildstage.Copy(dockerfile.CopyOptions{Src: ".", Dest: "."})
-	if err != nil {
-		return err
-	}
-
-	buildstage.Run(dockerfile.RunOptions{Command: []string{"ncc", "build", filepath.ToSlash(t.handler), "-m", "--v8-cache", "-o", "lib/"}})
-
-	// start final stage
-	con, err := css.NewContainer(dockerfile.NewContainerOpts{
-		From:   "node:alpine",
-		As:     "final",
-		Ignore: javascriptIgnoreList,
-	})
-
-	if err != nil {
-		return err
-	}
-
-	if err := con.Copy(dockerfile.CopyOptions{From: buildstage.Name(), Src: "package.json", Dest: "package.json"}); err != nil {
-		return err
-	}
-	if err := con.Copy(dockerfile.CopyOptions{From: buildstage.Name(), Src: "node_modules/", Dest: "node_modules/"}); err != nil {
-		return err
-	}
-	if err := con.Copy(dockerfile.CopyOptions{From: buildstage.Name(), Src: "lib/", Dest: "/"}); err != nil {
-		return err
-	}
-
-	withMembrane(con, version, provider)
-
-	con.Config(dockerfile.ConfigOptions{
-		Cmd: []string{"node", "index.js"},
-	})
-
-	lines, err := css.Compile(con.Name(), nil)
-
-	if err != nil {
-		return err
-	}
-
-	_, err = w.Write([]byte(strings.Join(lines, "\n")))
-	return err
+	return functionDockerfile(funcCtxDir, t.rte, newDockerfileVars(t.handler, version, provider), w)
 }
 
 func (t *typescript) FunctionDockerfileForCodeAsConfig(w io.Writer) error {
 	con, err := dockerfile.NewContainer(dockerfile.NewContainerOpts{
-		From:   "node:alpine",
+		From:   nodeImage,
 		Ignore: javascriptIgnoreList,
 	})
 	if err != nil {
@@ -149,6 +92,15 @@ func (t *typescript) LaunchOptsForFunctionCollect(runCtx string) (LaunchOpts, er
 	}, nil
 }
 
+func (t *typescript) NativeCommandForFunctionCollect(runCtx string) ([]string, error) {
+	// prefer the project's own ts-node to a global one
+	local := filepath.Join(runCtx, "node_modules", ".bin", "ts-node")
+	if _, err := os.Stat(local); err == nil {
+		return []string{local, "-T", t.handler}, nil
+	}
+	return nativeCommand("ts-node", "-T", t.handler)
+}
+
 func (t *typescript) LaunchOptsForFunction(runCtx string) (LaunchOpts, error) {
 	var cmd []string
 
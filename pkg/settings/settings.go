@@ -0,0 +1,133 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v2"
+
+	"github.com/nitrictech/cli/pkg/utils"
+)
+
+// Settings change the behaviour of the CLI. They are read from the user's config.yaml in
+// the nitric config directory, then from .nitric/config.yaml in the project, so teams can
+// commit shared settings. Values in the project override the user's, and flags override both.
+type Settings struct {
+	// BuildTimeout limits how long an image build can take, e.g. 30m
+	BuildTimeout time.Duration `yaml:"build_timeout,omitempty"`
+
+	// DefaultStack is used by commands that need a stack when --stack is not given
+	DefaultStack string `yaml:"default_stack,omitempty"`
+
+	// HandlerGlobs are written to the nitric.yaml created by 'nitric init', rather than the
+	// handlers discovered in the project
+	HandlerGlobs []string `yaml:"handler_globs,omitempty"`
+
+	// NativeCollect runs the handlers on the host to gather their code-as-config when their
+	// toolchain is installed, rather than in the runtime's dev container
+	NativeCollect bool `yaml:"native_collect,omitempty"`
+
+	// CollectServer configures the gRPC server the handlers declare their resources to
+	CollectServer CollectServer `yaml:"collect_server,omitempty"`
+
+	// ReadOnly stops the stacks being changed, so developers with credentials that can only read them
+	// can still list and inspect them
+	ReadOnly bool `yaml:"read_only,omitempty"`
+
+	// Locale selects the catalog the CLI's messages are read from, e.g. de or pt_BR. By default
+	// it comes from NITRIC_LOCALE, LC_ALL, LC_MESSAGES or LANG
+	Locale string `yaml:"locale,omitempty"`
+}
+
+// CollectServer is where the server that gathers code-as-config listens, and how the handlers reach it.
+type CollectServer struct {
+	// Host the server binds to, all interfaces by default
+	Host string `yaml:"host,omitempty"`
+
+	// Port is the first port tried for each handler, so handlers gathered at the same time each get
+	// the next free one. A random port is used by default
+	Port int `yaml:"port,omitempty"`
+
+	// Address the handlers connect to the server on, e.g. from a remote dev container. By default
+	// this is the docker host for containers and localhost for native handlers
+	Address string `yaml:"address,omitempty"`
+
+	// TLSCert and TLSKey are the PEM files the server uses to serve TLS
+	TLSCert string `yaml:"tls_cert,omitempty"`
+	TLSKey  string `yaml:"tls_key,omitempty"`
+}
+
+var (
+	current *Settings
+	once    sync.Once
+)
+
+// UserFile is the user's settings file.
+func UserFile() string {
+	return filepath.Join(utils.NitricConfigDir(), "config.yaml")
+}
+
+// ProjectFile is the project's settings file.
+func ProjectFile(dir string) string {
+	return filepath.Join(dir, ".nitric", "config.yaml")
+}
+
+// Load merges the user's settings with the settings of the project in dir.
+func Load(dir string) (*Settings, error) {
+	s := &Settings{}
+	for _, file := range []string{UserFile(), ProjectFile(dir)} {
+		b, err := ioutil.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// fields missing from the file keep their current value
+		if err := yaml.Unmarshal(b, s); err != nil {
+			return nil, errors.WithMessage(err, file)
+		}
+	}
+	return s, nil
+}
+
+// Get returns the settings of the project in the current directory, warning about settings
+// that can't be read.
+func Get() *Settings {
+	once.Do(func() {
+		current = &Settings{}
+
+		wd, err := os.Getwd()
+		if err != nil {
+			return
+		}
+		s, err := Load(wd)
+		if err != nil {
+			pterm.Warning.Println("Ignoring the CLI settings:", err)
+			return
+		}
+		current = s
+	})
+	return current
+}
@@ -0,0 +1,69 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, file, content string) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	os.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	projectDir := t.TempDir()
+
+	got, err := Load(projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, &Settings{}) {
+		t.Errorf("expected no settings, got %+v", got)
+	}
+
+	writeFile(t, UserFile(), "build_timeout: 30m\ndefault_stack: dev\nhandler_globs: [functions/*.ts]\n")
+	writeFile(t, ProjectFile(projectDir), "default_stack: prod\n")
+
+	want := &Settings{
+		BuildTimeout: 30 * time.Minute,
+		DefaultStack: "prod",
+		HandlerGlobs: []string{"functions/*.ts"},
+	}
+	got, err = Load(projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+
+	writeFile(t, ProjectFile(projectDir), "build_timeout: [1]\n")
+	if _, err := Load(projectDir); err == nil {
+		t.Error("expected an error for an invalid project file")
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+var encryptedRef = regexp.MustCompile(`^\$\{encrypted:([^}]+)\}$`)
+
+// EncryptedRef is the reference to a value encrypted with the stack's key, written in the stack file
+// in place of the value.
+func EncryptedRef(ciphertext string) string {
+	return "${encrypted:" + ciphertext + "}"
+}
+
+// Encrypted is true when the stack has values encrypted with its key.
+func (c *Config) Encrypted() (bool, error) {
+	found := false
+	_, err := c.walkStrings(func(s string) (string, error) {
+		found = found || encryptedRef.MatchString(s)
+		return s, nil
+	})
+	return found, err
+}
+
+// Decrypt returns a copy of the stack with its encrypted values replaced by their plaintext, they are
+// decrypted when deploying so the stack file can be committed.
+func (c *Config) Decrypt(decrypt func(ciphertext string) (string, error)) (*Config, error) {
+	tree, err := c.walkStrings(func(s string) (string, error) {
+		m := encryptedRef.FindStringSubmatch(s)
+		if m == nil {
+			return s, nil
+		}
+		return decrypt(m[1])
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "decrypting the stack")
+	}
+
+	b, err := yaml.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+	d := &Config{}
+	return d, yaml.Unmarshal(b, d)
+}
+
+// walkStrings calls fn with each string value of the stack, returning the stack as a yaml tree with the
+// values replaced by those returned.
+func (c *Config) walkStrings(fn func(string) (string, error)) (interface{}, error) {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var tree interface{}
+	if err := yaml.Unmarshal(b, &tree); err != nil {
+		return nil, err
+	}
+
+	var walk func(v interface{}) (interface{}, error)
+	walk = func(v interface{}) (interface{}, error) {
+		switch t := v.(type) {
+		case string:
+			return fn(t)
+		case map[interface{}]interface{}:
+			for k, e := range t {
+				r, err := walk(e)
+				if err != nil {
+					return nil, err
+				}
+				t[k] = r
+			}
+		case []interface{}:
+			for i, e := range t {
+				r, err := walk(e)
+				if err != nil {
+					return nil, err
+				}
+				t[i] = r
+			}
+		}
+		return v, nil
+	}
+	return walk(tree)
+}
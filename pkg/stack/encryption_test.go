@@ -0,0 +1,65 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConfig_Decrypt(t *testing.T) {
+	c := &Config{
+		Name:     "prod",
+		Provider: Aws,
+		Alerts: &Alerts{
+			Email: EncryptedRef("b3BzQGV4YW1wbGUuY29t"),
+		},
+		Extra: map[string]interface{}{
+			"apiKey": EncryptedRef("a2V5"),
+		},
+	}
+	encrypted, err := c.Encrypted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !encrypted {
+		t.Fatal("expected the stack to have encrypted values")
+	}
+
+	got, err := c.Decrypt(func(ciphertext string) (string, error) {
+		return "plain-" + ciphertext, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Alerts.Email != "plain-b3BzQGV4YW1wbGUuY29t" || got.Extra["apiKey"] != "plain-a2V5" {
+		t.Errorf("Decrypt() = %+v, %v", got.Alerts, got.Extra)
+	}
+	if encrypted, err := got.Encrypted(); err != nil || encrypted {
+		t.Errorf("expected no encrypted values once decrypted, got %v, %v", encrypted, err)
+	}
+	if c.Alerts.Email != EncryptedRef("b3BzQGV4YW1wbGUuY29t") {
+		t.Error("expected the stack to be unchanged")
+	}
+
+	_, err = c.Decrypt(func(ciphertext string) (string, error) {
+		return "", errors.New("access denied")
+	})
+	if err == nil {
+		t.Error("expected decrypt errors to be returned")
+	}
+}
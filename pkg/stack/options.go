@@ -21,18 +21,114 @@ import (
 	"io/ioutil"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 
 	"github.com/nitrictech/cli/pkg/pflagext"
+	"github.com/nitrictech/cli/pkg/settings"
 	"github.com/nitrictech/cli/pkg/utils"
 )
 
-var stack string
+var (
+	stack  string
+	params map[string]string
+)
 
-// Assume the project is in the currentDirectory
+// ConfigFromOptions reads the stack chosen with --stack, interpolating ${env:VAR}, ${stack:name},
+// ${target:region} and ${param:key} references, parameters are given with --param key=value.
 func ConfigFromOptions() (*Config, error) {
-	return configFromFile("nitric-" + stack + ".yaml")
+	name, err := chosenStack()
+	if err != nil {
+		return nil, err
+	}
+	return ConfigFromName(name)
+}
+
+// ConfigFromName reads the named stack, interpolating references like ConfigFromOptions.
+func ConfigFromName(name string) (*Config, error) {
+	return configFromFile("nitric-"+name+".yaml", true)
+}
+
+// RawConfigFromOptions reads the stack chosen with --stack without interpolation, for when the
+// config will be written back.
+func RawConfigFromOptions() (*Config, error) {
+	file, err := ChosenFile()
+	if err != nil {
+		return nil, err
+	}
+	return configFromFile(file, false)
+}
+
+// ChosenFile is the file of the stack chosen with --stack, or the default_stack setting.
+func ChosenFile() (string, error) {
+	name, err := chosenStack()
+	if err != nil {
+		return "", err
+	}
+	return "nitric-" + name + ".yaml", nil
+}
+
+// chosenStack is the stack chosen with --stack, or the default_stack setting. It is checked against the
+// stack files here rather than when the flag is parsed, as -C changes to the project's directory after that.
+func chosenStack() (string, error) {
+	name := stack
+	if name == "" {
+		name = settings.Get().DefaultStack
+	}
+	if name == "" {
+		return "", errors.New(`required flag(s) "stack" not set`)
+	}
+
+	stacks, err := Names()
+	if err != nil {
+		return "", err
+	}
+	for _, s := range stacks {
+		if s == name {
+			return name, nil
+		}
+	}
+	if len(stacks) == 0 {
+		return "", fmt.Errorf("stack %s not found, there are no nitric-<stackname>.yaml files in this directory", name)
+	}
+	return "", fmt.Errorf("stack %s not found, the stacks are %s", name, strings.Join(stacks, ", "))
+}
+
+// Vars are the values available to ${stack:...}, ${target:...} and ${param:...} references.
+func (p *Config) Vars() map[string]string {
+	vars := paramVars(p.Params)
+	vars["stack:name"] = p.Name
+	vars["target:provider"] = p.Provider
+	vars["target:region"] = p.Region
+	return vars
+}
+
+func paramVars(params map[string]string) map[string]string {
+	vars := map[string]string{}
+	for k, v := range params {
+		vars["param:"+k] = v
+	}
+	return vars
+}
+
+// stackParams are the default values of the parameters in the stack file, overridden by those given.
+func stackParams(yamlFile []byte, given map[string]string) (map[string]string, error) {
+	defaults := struct {
+		Params map[string]string `yaml:"params"`
+	}{}
+	if err := yaml.Unmarshal(yamlFile, &defaults); err != nil {
+		return nil, err
+	}
+
+	res := map[string]string{}
+	for k, v := range defaults.Params {
+		res[k] = v
+	}
+	for k, v := range given {
+		res[k] = v
+	}
+	return res, nil
 }
 
 func (p *Config) ToFile(file string) error {
@@ -44,7 +140,34 @@ func (p *Config) ToFile(file string) error {
 	return ioutil.WriteFile(file, b, 0644)
 }
 
-func configFromFile(file string) (*Config, error) {
+// Promote copies the settings of this stack to a new stack, taking the target specific values
+// (name, provider, region and provider settings) from target.
+func (p *Config) Promote(target *Config) (*Config, error) {
+	b, err := yaml.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Config{}
+	if err := yaml.Unmarshal(b, s); err != nil {
+		return nil, err
+	}
+
+	s.Name = target.Name
+	s.Provider = target.Provider
+	s.Region = target.Region
+	if s.Provider != p.Provider || s.Extra == nil {
+		// provider settings don't carry between providers
+		s.Extra = map[string]interface{}{}
+	}
+	for k, v := range target.Extra {
+		s.Extra[k] = v
+	}
+
+	return s, nil
+}
+
+func configFromFile(file string, interpolate bool) (*Config, error) {
 	s := &Config{}
 
 	yamlFile, err := ioutil.ReadFile(file)
@@ -52,27 +175,85 @@ func configFromFile(file string) (*Config, error) {
 		return nil, fmt.Errorf("no nitric stack found (unable to find %s). If you haven't created a stack yet, run `nitric stack new` to get started", file)
 	}
 
+	var merged map[string]string
+	if interpolate {
+		// parameters come first, so the stack's name can be made from them, e.g. one stack per tenant
+		merged, err = stackParams(yamlFile, params)
+		if err != nil {
+			return nil, err
+		}
+		yamlFile, err = utils.Interpolate(yamlFile, paramVars(merged))
+		if err != nil {
+			return nil, errors.WithMessage(err, file)
+		}
+
+		// the stack's own name, provider and region can be referenced in the rest of the file
+		if err := yaml.Unmarshal(yamlFile, s); err != nil {
+			return nil, err
+		}
+		s.Params = merged
+		yamlFile, err = utils.Interpolate(yamlFile, s.Vars())
+		if err != nil {
+			return nil, errors.WithMessage(err, file)
+		}
+		s = &Config{}
+	}
+
 	err = yaml.Unmarshal(yamlFile, s)
+	if len(merged) > 0 {
+		s.Params = merged
+	}
 	return s, err
 }
 
 func AddOptions(cmd *cobra.Command, providerOnly bool) error {
+	addParamFlag(cmd)
+	return addStackFlag(cmd, true)
+}
+
+// AddOptionalOptions adds --stack for commands that act locally when no stack is chosen.
+func AddOptionalOptions(cmd *cobra.Command) error {
+	addParamFlag(cmd)
+	return addStackFlag(cmd, false)
+}
+
+// addParamFlag adds --param, the values of the stack's ${param:key} references.
+func addParamFlag(cmd *cobra.Command) {
+	cmd.Flags().Var(pflagext.NewStringMapVar(&params, nil), "param", "set a parameter referenced as ${param:key} in the stack, e.g. --param tenant=acme, can be repeated")
+}
+
+// OptionsChosen is true when a stack was chosen with --stack.
+func OptionsChosen() bool {
+	return stack != ""
+}
+
+// Names are the names of the stacks configured in the current directory.
+func Names() ([]string, error) {
 	stackFiles, err := utils.GlobInDir(".", "nitric-*.yaml")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	stacks := []string{}
 	for _, sf := range stackFiles {
 		stacks = append(stacks, strings.TrimSuffix(strings.TrimPrefix(sf, "nitric-"), ".yaml"))
 	}
+	return stacks, nil
+}
 
-	cmd.Flags().VarP(pflagext.NewStringEnumVar(&stack, stacks, ""), "stack", "s", "use this to refer to a stack configuration nitric-<stackname>.yaml")
-
-	if err = cobra.MarkFlagRequired(cmd.Flags(), "stack"); err != nil {
-		return err
+// addStackFlag adds --stack, when it is required and not given the default_stack setting is used.
+func addStackFlag(cmd *cobra.Command, required bool) error {
+	usage := "use this to refer to a stack configuration nitric-<stackname>.yaml"
+	if required {
+		usage += ", defaults to the default_stack setting"
 	}
+	cmd.Flags().StringVarP(&stack, "stack", "s", "", usage)
 
+	// the stacks are listed when completing, the flag is added before -C changes the directory
 	return cmd.RegisterFlagCompletionFunc("stack", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return stacks, cobra.ShellCompDirectiveDefault
+		stacks, err := Names()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return stacks, cobra.ShellCompDirectiveNoFileComp
 	})
 }
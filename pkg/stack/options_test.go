@@ -32,7 +32,7 @@ func Test_configFromFile(t *testing.T) {
 		},
 	}
 
-	got, err := configFromFile("data/nitric-x.yaml")
+	got, err := configFromFile("data/nitric-x.yaml", true)
 	if err != nil {
 		t.Errorf("configFromFile() error = %v", err)
 		return
@@ -41,3 +41,76 @@ func Test_configFromFile(t *testing.T) {
 		t.Errorf("configFromFile() = %v, want %v", got, want)
 	}
 }
+
+func TestPromote(t *testing.T) {
+	staging := &Config{
+		Name:     "staging",
+		Provider: Azure,
+		Region:   "eastus2",
+		Backups:  &Backups{RetentionDays: 7},
+		Extra: map[string]interface{}{
+			"adminemail": "admin@example.com",
+			"org":        "example.com",
+		},
+	}
+
+	got, err := staging.Promote(&Config{
+		Name:     "prod",
+		Provider: Azure,
+		Region:   "westus",
+		Extra:    map[string]interface{}{"org": "prod.example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Config{
+		Name:     "prod",
+		Provider: Azure,
+		Region:   "westus",
+		Backups:  &Backups{RetentionDays: 7},
+		Extra: map[string]interface{}{
+			"adminemail": "admin@example.com",
+			"org":        "prod.example.com",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Promote() = %v, want %v", got, want)
+	}
+
+	got, err = staging.Promote(&Config{Name: "prod", Provider: Aws, Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Extra) != 0 {
+		t.Errorf("Promote() to a new provider kept extra settings %v", got.Extra)
+	}
+}
+
+func Test_configFromFileParams(t *testing.T) {
+	params = map[string]string{"tenant": "acme"}
+	defer func() { params = nil }()
+
+	got, err := configFromFile("data/nitric-tenant.yaml", true)
+	if err != nil {
+		t.Fatalf("configFromFile() error = %v", err)
+	}
+
+	want := &Config{
+		Name:     "shop-acme",
+		Provider: Aws,
+		Region:   "us-east-1",
+		Params:   map[string]string{"tenant": "acme", "region": "us-east-1"},
+		Cdn: &Cdn{
+			Apis: map[string]CdnTarget{"main": {Domains: []string{"acme.example.com"}}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("configFromFile() = %+v, want %+v", got, want)
+	}
+
+	params = nil
+	if _, err := configFromFile("data/nitric-tenant.yaml", true); err == nil {
+		t.Error("configFromFile() expected an error when the tenant parameter is not set")
+	}
+}
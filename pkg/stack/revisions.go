@@ -0,0 +1,83 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"fmt"
+	"regexp"
+)
+
+const (
+	// RevisionsSingle keeps only the latest revision of an app active
+	RevisionsSingle = "single"
+	// RevisionsMultiple keeps the revisions given traffic active alongside the latest
+	RevisionsMultiple = "multiple"
+	// LatestRevision is the traffic key of the revision deployed by the update
+	LatestRevision = "latest"
+)
+
+// Revisions control the revisions of a function's Azure container app, e.g. to send part of its traffic
+// to a new revision before all of it.
+type Revisions struct {
+	// Mode is "single" (the default), where each update replaces the active revision, or "multiple"
+	// where the revisions given traffic stay active
+	Mode string `yaml:"mode,omitempty"`
+
+	// Suffix names the revision deployed by the update, e.g. v2 for orders--v2. It must change whenever
+	// the app does
+	Suffix string `yaml:"suffix,omitempty"`
+
+	// Traffic is the percentage of requests each revision gets, by revision name or "latest" for the
+	// revision deployed by the update. The weights add up to 100
+	Traffic map[string]int `yaml:"traffic,omitempty"`
+}
+
+var revisionSuffix = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// Validate checks the mode, suffix and traffic weights of the revisions.
+func (r Revisions) Validate() error {
+	switch r.Mode {
+	case "", RevisionsSingle, RevisionsMultiple:
+	default:
+		return fmt.Errorf("revisions mode must be %s or %s, not %s", RevisionsSingle, RevisionsMultiple, r.Mode)
+	}
+	if r.Suffix != "" && !revisionSuffix.MatchString(r.Suffix) {
+		return fmt.Errorf("revision suffix %s may only contain lowercase letters, numbers and hyphens", r.Suffix)
+	}
+	if len(r.Traffic) == 0 {
+		return nil
+	}
+
+	total := 0
+	others := 0
+	for name, weight := range r.Traffic {
+		if weight < 0 || weight > 100 {
+			return fmt.Errorf("the traffic weight of revision %s must be between 0 and 100, not %d", name, weight)
+		}
+		total += weight
+		if name != LatestRevision {
+			others++
+		}
+	}
+	if total != 100 {
+		return fmt.Errorf("the traffic weights of the revisions add up to %d, not 100", total)
+	}
+	if r.Mode != RevisionsMultiple && others > 0 {
+		return fmt.Errorf("traffic can only be sent to revisions other than the latest in %s mode", RevisionsMultiple)
+	}
+	return nil
+}
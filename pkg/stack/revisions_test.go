@@ -0,0 +1,82 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRevisionsValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		revisions Revisions
+		wantErr   string
+	}{
+		{
+			name:      "defaults",
+			revisions: Revisions{},
+		},
+		{
+			name:      "split",
+			revisions: Revisions{Mode: RevisionsMultiple, Suffix: "v2", Traffic: map[string]int{"latest": 10, "orders--v1": 90}},
+		},
+		{
+			name:      "all to latest",
+			revisions: Revisions{Traffic: map[string]int{"latest": 100}},
+		},
+		{
+			name:      "unknown mode",
+			revisions: Revisions{Mode: "canary"},
+			wantErr:   "revisions mode must be single or multiple, not canary",
+		},
+		{
+			name:      "invalid suffix",
+			revisions: Revisions{Suffix: "V2_beta"},
+			wantErr:   "revision suffix V2_beta may only contain",
+		},
+		{
+			name:      "weights not 100",
+			revisions: Revisions{Mode: RevisionsMultiple, Traffic: map[string]int{"latest": 10, "orders--v1": 80}},
+			wantErr:   "add up to 90, not 100",
+		},
+		{
+			name:      "negative weight",
+			revisions: Revisions{Mode: RevisionsMultiple, Traffic: map[string]int{"latest": 110, "orders--v1": -10}},
+			wantErr:   "must be between 0 and 100",
+		},
+		{
+			name:      "split in single mode",
+			revisions: Revisions{Traffic: map[string]int{"latest": 50, "orders--v1": 50}},
+			wantErr:   "in multiple mode",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.revisions.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -25,9 +25,563 @@ const (
 
 var Providers = []string{Aws, Azure, Gcp, Digitalocean}
 
+type CdnTarget struct {
+	// Custom domains to serve the content from
+	Domains []string `yaml:"domains,omitempty"`
+
+	// The certificate to use for the custom domains (an ACM certificate ARN on AWS)
+	Certificate string `yaml:"certificate,omitempty"`
+
+	// The default number of seconds to cache responses, 0 disables caching
+	DefaultTTL int `yaml:"defaultTtl,omitempty"`
+
+	// The DNS zone the domains' records are created in, a Route 53 hosted zone id on AWS or an Azure DNS
+	// zone resource id on Azure. On AWS the zone is looked up by the domains' names when not set
+	Zone string `yaml:"zone,omitempty"`
+
+	// Leave the domains' DNS records to be created by hand, see nitric stack outputs
+	ManualDns bool `yaml:"manualDns,omitempty"`
+}
+
+type Cdn struct {
+	Apis    map[string]CdnTarget `yaml:"apis,omitempty"`
+	Buckets map[string]CdnTarget `yaml:"buckets,omitempty"`
+	Sites   map[string]CdnTarget `yaml:"sites,omitempty"`
+}
+
+// Waf attaches a web application firewall to the apis served from the cdn.
+type Waf struct {
+	// Managed rule sets, e.g. AWSManagedRulesCommonRuleSet on AWS or Microsoft_DefaultRuleSet:2.0
+	// (type:version) on Azure, where they need the premium Front Door tier
+	ManagedRules []string `yaml:"managedRules,omitempty"`
+
+	// The requests an IP address can make in 5 minutes before it is blocked, 0 has no limit
+	RateLimit int `yaml:"rateLimit,omitempty"`
+
+	// Count the requests the rules match, rather than blocking them
+	Monitor bool `yaml:"monitor,omitempty"`
+}
+
+// SiteCdnTarget returns the CDN settings for a static site, sites are always served from a CDN.
+func (c *Config) SiteCdnTarget(site string) CdnTarget {
+	if c.Cdn != nil {
+		if t, ok := c.Cdn.Sites[site]; ok {
+			return t
+		}
+	}
+	return CdnTarget{DefaultTTL: 3600}
+}
+
+type DaprApp struct {
+	// The Dapr app id, defaults to the function name
+	AppID string `yaml:"appId,omitempty"`
+
+	// The port the function listens on for Dapr, defaults to 9001
+	AppPort int `yaml:"appPort,omitempty"`
+}
+
+type DaprComponent struct {
+	Name     string            `yaml:"name"`
+	Type     string            `yaml:"type"`
+	Version  string            `yaml:"version"`
+	Metadata map[string]string `yaml:"metadata,omitempty"`
+}
+
+// Dapr enables the Dapr sidecar for functions, currently only on Azure Container Apps
+type Dapr struct {
+	// The functions to run with a sidecar, keyed by function name
+	Functions map[string]DaprApp `yaml:"functions,omitempty"`
+
+	// Components available to all the functions with a sidecar
+	Components []DaprComponent `yaml:"components,omitempty"`
+}
+
+// Backups enables scheduled backups of the stack's collections
+type Backups struct {
+	// Cron expression for when backups are taken, defaults to daily at 02:00 UTC
+	Schedule string `yaml:"schedule,omitempty"`
+
+	// The number of days backups are kept, defaults to 30
+	RetentionDays int `yaml:"retentionDays,omitempty"`
+}
+
+func (b *Backups) ScheduleOrDefault() string {
+	if b.Schedule == "" {
+		return "0 2 * * *"
+	}
+	return b.Schedule
+}
+
+func (b *Backups) RetentionDaysOrDefault() int {
+	if b.RetentionDays <= 0 {
+		return 30
+	}
+	return b.RetentionDays
+}
+
+// Tls sets the oldest TLS version the stack's buckets, storage and apis accept, and whether they also
+// accept plain HTTP. By default they only accept HTTPS with TLS 1.2 or above.
+type Tls struct {
+	// The oldest version accepted, 1.0, 1.1 or 1.2 (the default), services that only support 1.2 keep it
+	MinVersion string `yaml:"minVersion,omitempty"`
+
+	// Accept plain HTTP requests as well as HTTPS
+	AllowHttp bool `yaml:"allowHttp,omitempty"`
+}
+
+// TlsVersions are the minimum TLS versions a stack can have.
+var TlsVersions = []string{"1.0", "1.1", "1.2"}
+
+// TlsOrDefault returns the stack's TLS settings, HTTPS only with TLS 1.2 or above when it has none.
+func (c *Config) TlsOrDefault() Tls {
+	t := Tls{MinVersion: "1.2"}
+	if c.Tls != nil {
+		t.AllowHttp = c.Tls.AllowHttp
+		if c.Tls.MinVersion != "" {
+			t.MinVersion = c.Tls.MinVersion
+		}
+	}
+	return t
+}
+
+// CosmosNetwork restricts network access to the Azure Cosmos DB account holding the collections.
+// By default only connections from within Azure are accepted.
+type CosmosNetwork struct {
+	// IP addresses or CIDR ranges allowed to connect, e.g. office or CI egress addresses
+	AllowedIPs []string `yaml:"allowedIps,omitempty"`
+
+	// Resource IDs of VNet subnets allowed to connect through service endpoints
+	Subnets []string `yaml:"subnets,omitempty"`
+
+	// Resource IDs of VNet subnets to create private endpoints in
+	PrivateEndpointSubnets []string `yaml:"privateEndpointSubnets,omitempty"`
+
+	// Disable public network access, so the account is only reachable through private endpoints
+	DisablePublicAccess bool `yaml:"disablePublicAccess,omitempty"`
+}
+
+// KeyVault configures the soft delete of the Azure Key Vault holding the stack's secrets.
+type KeyVault struct {
+	// Keep a deleted vault, and its secrets, recoverable for 7 to 90 days. Soft delete is off when 0,
+	// when on the vault has the same name each time the stack is created.
+	SoftDeleteRetentionDays int `yaml:"softDeleteRetentionDays,omitempty"`
+
+	// Stop a deleted vault being purged before its retention ends, this can't be turned off once enabled
+	PurgeProtection bool `yaml:"purgeProtection,omitempty"`
+
+	// What happens to the soft deleted vault, "recover" (the default) it with its secrets when the stack
+	// is created again, or "purge" it when the stack is deleted
+	Deleted string `yaml:"deleted,omitempty"`
+}
+
+const (
+	KeyVaultRecover = "recover"
+	KeyVaultPurge   = "purge"
+)
+
+// LambdaLayers adds Lambda layers and extensions to a function on AWS. The functions are
+// container images, so the content is copied into /opt of the image rather than attached.
+type LambdaLayers struct {
+	// Layer version ARNs, {region} is replaced with the stack's region,
+	// e.g. arn:aws:lambda:{region}:464622532012:layer:Datadog-Node16-x:85
+	Layers []string `yaml:"layers,omitempty"`
+
+	// Images containing Lambda extensions under /opt, e.g. public.ecr.aws/datadog/lambda-extension:latest
+	Extensions []string `yaml:"extensions,omitempty"`
+}
+
+// Observability runs an OpenTelemetry collector next to each function, exporting the function's
+// traces and metrics to an APM such as Datadog, Honeycomb or an in-house collector.
+type Observability struct {
+	// The OTLP endpoint the collector exports to, e.g. https://api.honeycomb.io
+	Endpoint string `yaml:"endpoint"`
+
+	// The OTLP protocol of the endpoint, grpc or http/protobuf (the default)
+	Protocol string `yaml:"protocol,omitempty"`
+
+	// Headers sent with each export, e.g. the API key, ${VAR} is replaced with VAR from the env files
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// The collector Lambda layer used on AWS, {region} is replaced with the stack's region
+	CollectorLayer string `yaml:"collectorLayer,omitempty"`
+}
+
+const (
+	// AlertErrorRate is the number of failed invocations of a function per minute
+	AlertErrorRate = "error-rate"
+	// AlertLatency is the 95th percentile duration of a function's invocations in milliseconds
+	AlertLatency = "p95-latency"
+	// AlertQueueDepth is the number of messages waiting in a queue
+	AlertQueueDepth = "queue-depth"
+)
+
+// AlertRule fires when the metric of a function or queue is above the threshold.
+type AlertRule struct {
+	// The function watched by error-rate and p95-latency rules
+	Function string `yaml:"function,omitempty"`
+
+	// The queue watched by queue-depth rules
+	Queue string `yaml:"queue,omitempty"`
+
+	// One of error-rate, p95-latency or queue-depth
+	Metric string `yaml:"metric"`
+
+	Threshold float64 `yaml:"threshold"`
+
+	// The number of minutes the metric is above the threshold before the alert fires, defaults to 5
+	Minutes int `yaml:"minutes,omitempty"`
+}
+
+func (r AlertRule) MinutesOrDefault() int {
+	if r.Minutes <= 0 {
+		return 5
+	}
+	return r.Minutes
+}
+
+// Alerts provisions the provider's metric alarms, notifying an email address when they fire.
+type Alerts struct {
+	// The address notified when an alert fires
+	Email string `yaml:"email"`
+
+	// The alert rules, keyed by name
+	Rules map[string]AlertRule `yaml:"rules,omitempty"`
+
+	// Checks the APIs are responding from several locations, alerting when they are not
+	Uptime *Uptime `yaml:"uptime,omitempty"`
+}
+
+// Uptime requests a path of each API, alerting when it doesn't respond with a 2xx status. It uses Application
+// Insights availability tests on Azure, Route 53 health checks on AWS and uptime checks on GCP.
+type Uptime struct {
+	// The path requested, defaults to /
+	Path string `yaml:"path,omitempty"`
+
+	// The APIs checked, all of them when empty
+	Apis []string `yaml:"apis,omitempty"`
+
+	// The minutes between checks, 5, 10 or 15, defaults to 5. Route 53 checks every 30 seconds.
+	Minutes int `yaml:"minutes,omitempty"`
+}
+
+func (u *Uptime) PathOrDefault() string {
+	if u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+func (u *Uptime) MinutesOrDefault() int {
+	if u.Minutes <= 0 {
+		return 5
+	}
+	return u.Minutes
+}
+
+// Checks returns true if the API is checked.
+func (u *Uptime) Checks(api string) bool {
+	return len(u.Apis) == 0 || contains(u.Apis, api)
+}
+
+// Budget notifies when the monthly cost of the stack's resources, found by their x-nitric-stack tag,
+// reaches a percentage of the amount.
+type Budget struct {
+	// The monthly amount, in USD on AWS and in the billing account's currency on Azure and GCP
+	Amount float64 `yaml:"amount"`
+
+	// The percentages of the amount that notify when reached, defaults to 80 and 100
+	Thresholds []float64 `yaml:"thresholds,omitempty"`
+
+	// The addresses emailed when a threshold is reached
+	Emails []string `yaml:"emails,omitempty"`
+
+	// An https URL posted to when a threshold is reached, on AWS it must confirm the SNS subscription
+	Webhook string `yaml:"webhook,omitempty"`
+}
+
+func (b Budget) ThresholdsOrDefault() []float64 {
+	if len(b.Thresholds) == 0 {
+		return []float64{80, 100}
+	}
+	return b.Thresholds
+}
+
+// Sleep scales the stack's always running compute to zero outside working hours, e.g. for dev and
+// test stacks. Functions without a minScale already scale to zero when they are idle.
+type Sleep struct {
+	// Cron expression for when the stack wakes up, e.g. "0 8 * * 1-5"
+	Wake string `yaml:"wake"`
+
+	// Cron expression for when the stack goes to sleep, e.g. "0 18 * * 1-5"
+	Sleep string `yaml:"sleep"`
+
+	// The IANA time zone of the expressions, defaults to UTC
+	TimeZone string `yaml:"timeZone,omitempty"`
+}
+
+func (s *Sleep) TimeZoneOrDefault() string {
+	if s.TimeZone == "" {
+		return "UTC"
+	}
+	return s.TimeZone
+}
+
+const (
+	// NamingAuto lets pulumi add a random suffix to the names of new resources
+	NamingAuto = "auto"
+	// NamingStable keeps the names stateful resources were first deployed with
+	NamingStable = "stable"
+)
+
+const (
+	EventingSns         = "sns"
+	EventingEventBridge = "eventbridge"
+)
+
+// EventBridge configures the custom event bus that carries the topics' events when eventing is eventbridge.
+type EventBridge struct {
+	// Archive keeps the published events so they can be replayed
+	Archive bool `yaml:"archive,omitempty"`
+
+	// The days archived events are kept, they are kept forever when 0
+	ArchiveRetentionDays int `yaml:"archiveRetentionDays,omitempty"`
+
+	// Content filters on the event payloads, keyed by function then topic. A function only receives the
+	// events of the topic that match, e.g. {"status": ["paid"]}
+	Filters map[string]map[string]map[string]interface{} `yaml:"filters,omitempty"`
+
+	// EnforceSchemas only delivers the events of topics with a schema that have the fields it requires
+	EnforceSchemas bool `yaml:"enforceSchemas,omitempty"`
+}
+
+// Subscription configures how the messages of a topic are delivered to a subscribed function.
+type Subscription struct {
+	// The most messages delivered to one invocation, messages are delivered one at a time when 0
+	BatchSize int `yaml:"batchSize,omitempty"`
+
+	// The most instances of the function handling messages at once
+	MaxConcurrency int `yaml:"maxConcurrency,omitempty"`
+
+	// The seconds a delivered message is hidden from other instances before it is delivered again
+	VisibilityTimeout int `yaml:"visibilityTimeout,omitempty"`
+}
+
+// Subscriptions configure the delivery of messages, keyed by function then topic.
+type Subscriptions map[string]map[string]Subscription
+
+// Subscription returns the delivery settings of the function's subscription to the topic.
+func (c *Config) Subscription(function, topic string) Subscription {
+	return c.Subscriptions[function][topic]
+}
+
+// MaxConcurrency is the lowest maxConcurrency of the function's subscriptions, 0 when none is set.
+func (c *Config) MaxConcurrency(function string) int {
+	max := 0
+	for _, s := range c.Subscriptions[function] {
+		if s.MaxConcurrency > 0 && (max == 0 || s.MaxConcurrency < max) {
+			max = s.MaxConcurrency
+		}
+	}
+	return max
+}
+
+// RateLimit limits the requests made to a route, or by each caller using a security scheme.
+type RateLimit struct {
+	// The requests per second allowed
+	Rate float64 `yaml:"rate,omitempty" json:"rate,omitempty"`
+
+	// The requests allowed at once above the rate, aws only, defaults to the rate
+	Burst int `yaml:"burst,omitempty" json:"burst,omitempty"`
+
+	// The requests allowed each period
+	Quota int `yaml:"quota,omitempty" json:"quota,omitempty"`
+
+	// The period of the quota, day, week or month
+	Period string `yaml:"period,omitempty" json:"period,omitempty"`
+}
+
+// QuotaPeriods are the periods a quota can be counted over.
+var QuotaPeriods = []string{"day", "week", "month"}
+
+// Throttling declares the rate limits of an api, they replace the x-nitric-throttling extensions of the
+// api's operations and security schemes.
+type Throttling struct {
+	// The limit of each route without its own
+	Default *RateLimit `yaml:"default,omitempty"`
+
+	// Routes are keyed by method and path, e.g. GET /orders/{id}
+	Routes map[string]RateLimit `yaml:"routes,omitempty"`
+
+	// SecuritySchemes are keyed by the name of the scheme in the api, each caller has its own limit
+	SecuritySchemes map[string]RateLimit `yaml:"securitySchemes,omitempty"`
+}
+
+// JwtAuth requires the requests to an api to carry a JWT bearer token from the issuer.
+type JwtAuth struct {
+	// The issuer of the tokens, their iss claim, e.g. https://example.auth0.com/
+	Issuer string `yaml:"issuer"`
+
+	// The audiences accepted, a token's aud claim must be one of them
+	Audiences []string `yaml:"audiences,omitempty"`
+
+	// The issuer's JSON Web Key Set, found through its OpenID configuration when not set
+	JwksUri string `yaml:"jwksUri,omitempty"`
+}
+
+// ApiLogs writes the access logs of an api's gateway to a log group of its own, aws only.
+// HTTP APIs have no execution logs, so the default format includes the errors of the gateway and the
+// function behind it.
+type ApiLogs struct {
+	// The JSON format of each line, using $context variables, it must include $context.requestId
+	Format string `yaml:"format,omitempty"`
+
+	// Days the logs are kept, defaults to 30
+	RetentionDays int `yaml:"retentionDays,omitempty"`
+
+	// Publish the metrics of each route, not only those of the api
+	DetailedMetrics bool `yaml:"detailedMetrics,omitempty"`
+}
+
+// RetentionDaysOrDefault returns the days the logs are kept.
+func (l *ApiLogs) RetentionDaysOrDefault() int {
+	if l.RetentionDays <= 0 {
+		return 30
+	}
+	return l.RetentionDays
+}
+
+// ApiRequests bounds the requests to an api's gateway.
+type ApiRequests struct {
+	// Seconds the gateway waits for the function to respond, the provider's default when not set
+	Timeout int `yaml:"timeout,omitempty"`
+
+	// The largest request body accepted in KB, only enforced by the API Management gateways on azure
+	MaxBodyKB int `yaml:"maxBodyKb,omitempty"`
+}
+
+// ApiClient regenerates the typed clients of the project's APIs each time the stack is deployed.
+type ApiClient struct {
+	// The language of the clients, ts
+	Lang string `yaml:"lang"`
+
+	// The directory the clients are written to
+	Dir string `yaml:"dir"`
+}
+
+// FunctionFilter chooses which of the project's functions are deployed to a stack.
+type FunctionFilter struct {
+	// Only these functions are deployed, all functions are deployed when empty
+	Include []string `yaml:"include,omitempty"`
+
+	// These functions are not deployed, e.g. an admin api that is only deployed to staging
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// DeploysFunction returns true if the function is deployed to the stack.
+func (c *Config) DeploysFunction(name string) bool {
+	if c.Functions == nil {
+		return true
+	}
+	if len(c.Functions.Include) > 0 && !contains(c.Functions.Include, name) {
+		return false
+	}
+	return !contains(c.Functions.Exclude, name)
+}
+
+// EmptiesBucket returns true if the bucket's files are deleted by stack down, so the bucket can be deleted.
+func (c *Config) EmptiesBucket(name string) bool {
+	return contains(c.EmptyBuckets, name)
+}
+
+// Archive writes snapshots of the stack's state and a summary of each update and delete to a bucket
+// outside the stack, before and after the change, e.g. for compliance teams. Secret values are redacted.
+type Archive struct {
+	// The bucket the snapshots are written to, an S3 bucket on aws, a storage account and container
+	// (account/container) on azure or a Cloud Storage bucket on gcp
+	Bucket string `yaml:"bucket"`
+
+	// The prefix of the snapshot keys, defaults to nitric/
+	Prefix string `yaml:"prefix,omitempty"`
+
+	// The number of days the snapshots can't be deleted or overwritten, with S3 object lock or Azure
+	// version-level immutability which the bucket must have enabled. On gcp the bucket's retention policy applies.
+	RetentionDays int `yaml:"retentionDays,omitempty"`
+}
+
+func (a *Archive) PrefixOrDefault() string {
+	if a.Prefix == "" {
+		return "nitric/"
+	}
+	return a.Prefix
+}
+
+// SupplyChain records what the images deployed to a stack are built from and who built them.
+type SupplyChain struct {
+	// Generate an SBOM of each built image with syft
+	SBOM bool `yaml:"sbom,omitempty"`
+
+	// Sign the pushed images with cosign using this key, a file or KMS URI (e.g. awskms:///alias/signing).
+	// The SBOMs are attached as attestations, a password protected key file reads COSIGN_PASSWORD.
+	SigningKey string `yaml:"signingKey,omitempty"`
+}
+
+// GeneratesSBOM returns true if an SBOM is generated for each built image, signed images always have one.
+func (c *Config) GeneratesSBOM() bool {
+	return c.SupplyChain != nil && (c.SupplyChain.SBOM || c.SupplyChain.SigningKey != "")
+}
+
+// SigningKey returns the cosign key that pushed images are signed with, or an empty string when they are not signed.
+func (c *Config) SigningKey() string {
+	if c.SupplyChain == nil {
+		return ""
+	}
+	return c.SupplyChain.SigningKey
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
 type Config struct {
-	Name     string                 `yaml:"name,omitempty"`
-	Provider string                 `yaml:"provider,omitempty"`
-	Region   string                 `yaml:"region,omitempty"`
-	Extra    map[string]interface{} `yaml:",inline,omitempty"`
+	Name            string                  `yaml:"name,omitempty"`
+	Provider        string                  `yaml:"provider,omitempty"`
+	Region          string                  `yaml:"region,omitempty"`
+	MembraneVersion string                  `yaml:"membraneVersion,omitempty"`
+	Cdn             *Cdn                    `yaml:"cdn,omitempty"`
+	Waf             *Waf                    `yaml:"waf,omitempty"`
+	Tls             *Tls                    `yaml:"tls,omitempty"`
+	Dapr            *Dapr                   `yaml:"dapr,omitempty"`
+	Backups         *Backups                `yaml:"backups,omitempty"`
+	KeepWarm        map[string]int          `yaml:"keepWarm,omitempty"`
+	Cosmos          *CosmosNetwork          `yaml:"cosmos,omitempty"`
+	KeyVault        *KeyVault               `yaml:"keyVault,omitempty"`
+	Layers          map[string]LambdaLayers `yaml:"layers,omitempty"`
+	Observability   *Observability          `yaml:"observability,omitempty"`
+	Alerts          *Alerts                 `yaml:"alerts,omitempty"`
+	Budget          *Budget                 `yaml:"budget,omitempty"`
+	Sleep           *Sleep                  `yaml:"sleep,omitempty"`
+	Eventing        string                  `yaml:"eventing,omitempty"`
+	EventBridge     *EventBridge            `yaml:"eventBridge,omitempty"`
+	Subscriptions   Subscriptions           `yaml:"subscriptions,omitempty"`
+	Naming          string                  `yaml:"naming,omitempty"`
+	ApiClient       *ApiClient              `yaml:"apiClient,omitempty"`
+	Functions       *FunctionFilter         `yaml:"functions,omitempty"`
+	EmptyBuckets    []string                `yaml:"emptyBuckets,omitempty"`
+	Throttling      map[string]Throttling   `yaml:"throttling,omitempty"`
+	ApiLogs         map[string]ApiLogs      `yaml:"apiLogs,omitempty"`
+	ApiRequests     map[string]ApiRequests  `yaml:"apiRequests,omitempty"`
+	Jwt             map[string]JwtAuth      `yaml:"jwt,omitempty"`
+	EncryptionKey   string                  `yaml:"encryptionKey,omitempty"`
+	SupplyChain     *SupplyChain            `yaml:"supplyChain,omitempty"`
+	Dev             bool                    `yaml:"dev,omitempty"`
+	Params          map[string]string       `yaml:"params,omitempty"`
+	Revisions       map[string]Revisions    `yaml:"revisions,omitempty"`
+	ExistingTopics  map[string]string       `yaml:"existingTopics,omitempty"`
+	Archive         *Archive                `yaml:"archive,omitempty"`
+	Extra           map[string]interface{}  `yaml:",inline,omitempty"`
 }
@@ -0,0 +1,91 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSiteCdnTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   CdnTarget
+	}{
+		{
+			name: "no cdn",
+			want: CdnTarget{DefaultTTL: 3600},
+		},
+		{
+			name:   "other site",
+			config: Config{Cdn: &Cdn{Sites: map[string]CdnTarget{"admin": {DefaultTTL: 60}}}},
+			want:   CdnTarget{DefaultTTL: 3600},
+		},
+		{
+			name:   "configured",
+			config: Config{Cdn: &Cdn{Sites: map[string]CdnTarget{"web": {Domains: []string{"example.com"}}}}},
+			want:   CdnTarget{Domains: []string{"example.com"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.config.SiteCdnTarget("web")
+			if !cmp.Equal(got, tt.want) {
+				t.Error(cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
+
+func TestBackupsDefaults(t *testing.T) {
+	tests := []struct {
+		name          string
+		backups       Backups
+		wantSchedule  string
+		wantRetention int
+	}{
+		{
+			name:          "defaults",
+			wantSchedule:  "0 2 * * *",
+			wantRetention: 30,
+		},
+		{
+			name:          "configured",
+			backups:       Backups{Schedule: "0 */6 * * *", RetentionDays: 7},
+			wantSchedule:  "0 */6 * * *",
+			wantRetention: 7,
+		},
+		{
+			name:          "negative retention",
+			backups:       Backups{RetentionDays: -1},
+			wantSchedule:  "0 2 * * *",
+			wantRetention: 30,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.backups.ScheduleOrDefault(); got != tt.wantSchedule {
+				t.Errorf("ScheduleOrDefault() = %s, want %s", got, tt.wantSchedule)
+			}
+			if got := tt.backups.RetentionDaysOrDefault(); got != tt.wantRetention {
+				t.Errorf("RetentionDaysOrDefault() = %d, want %d", got, tt.wantRetention)
+			}
+		})
+	}
+}
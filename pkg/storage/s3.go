@@ -0,0 +1,141 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package storage accesses the files of buckets through S3 compatible APIs.
+package storage
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+
+	"github.com/nitrictech/cli/pkg/provider/types"
+)
+
+type s3Store struct {
+	client s3iface.S3API
+	names  map[string]string
+}
+
+var _ types.BucketStore = &s3Store{}
+
+// NewS3Store returns a bucket store using the client, names maps the project's bucket names to
+// the names they were deployed with.
+func NewS3Store(client s3iface.S3API, names map[string]string) types.BucketStore {
+	return &s3Store{client: client, names: names}
+}
+
+func (s *s3Store) name(bucket string) (*string, error) {
+	n, ok := s.names[bucket]
+	if !ok {
+		return nil, fmt.Errorf("bucket %s not found, has it been deployed?", bucket)
+	}
+	return aws.String(n), nil
+}
+
+func (s *s3Store) List(bucket, prefix string) ([]*types.BucketFile, error) {
+	name, err := s.name(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []*types.BucketFile{}
+	err = s.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: name,
+		Prefix: aws.String(prefix),
+	}, func(out *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, o := range out.Contents {
+			files = append(files, &types.BucketFile{
+				Key:          aws.StringValue(o.Key),
+				Size:         aws.Int64Value(o.Size),
+				LastModified: aws.TimeValue(o.LastModified),
+			})
+		}
+		return true
+	})
+	return files, err
+}
+
+func (s *s3Store) Read(bucket, key string, w io.Writer) error {
+	name, err := s.name(bucket)
+	if err != nil {
+		return err
+	}
+
+	out, err := s.client.GetObject(&s3.GetObjectInput{Bucket: name, Key: aws.String(key)})
+	if err != nil {
+		return err
+	}
+	defer out.Body.Close()
+
+	_, err = io.Copy(w, out.Body)
+	return err
+}
+
+func (s *s3Store) Write(bucket, key string, r io.ReadSeeker) error {
+	name, err := s.name(bucket)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutObject(&s3.PutObjectInput{Bucket: name, Key: aws.String(key), Body: r})
+	return err
+}
+
+func (s *s3Store) Empty(bucket string) (int, error) {
+	name, err := s.name(bucket)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	var deleteErr error
+	err = s.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: name,
+		Prefix: aws.String(""),
+	}, func(out *s3.ListObjectsV2Output, lastPage bool) bool {
+		if len(out.Contents) == 0 {
+			return true
+		}
+
+		// a page holds at most 1000 keys, the most DeleteObjects accepts
+		ids := []*s3.ObjectIdentifier{}
+		for _, o := range out.Contents {
+			ids = append(ids, &s3.ObjectIdentifier{Key: o.Key})
+		}
+		res, err := s.client.DeleteObjects(&s3.DeleteObjectsInput{
+			Bucket: name,
+			Delete: &s3.Delete{Objects: ids, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			deleteErr = err
+			return false
+		}
+		if len(res.Errors) > 0 {
+			deleteErr = fmt.Errorf("deleting %s: %s", aws.StringValue(res.Errors[0].Key), aws.StringValue(res.Errors[0].Message))
+			return false
+		}
+		deleted += len(ids)
+		return true
+	})
+	if err == nil {
+		err = deleteErr
+	}
+	return deleted, err
+}
@@ -0,0 +1,105 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// fakeS3 keeps objects in memory, keyed by bucket and then key.
+type fakeS3 struct {
+	s3iface.S3API
+	objects map[string]map[string][]byte
+}
+
+func (f *fakeS3) PutObject(in *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+	b, err := ioutil.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.objects[*in.Bucket][*in.Key] = b
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (f *fakeS3) GetObject(in *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	return &s3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(f.objects[*in.Bucket][*in.Key]))}, nil
+}
+
+func (f *fakeS3) ListObjectsV2Pages(in *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+	out := &s3.ListObjectsV2Output{}
+	for k, v := range f.objects[*in.Bucket] {
+		if strings.HasPrefix(k, *in.Prefix) {
+			out.Contents = append(out.Contents, &s3.Object{Key: aws.String(k), Size: aws.Int64(int64(len(v)))})
+		}
+	}
+	fn(out, true)
+	return nil
+}
+
+func (f *fakeS3) DeleteObjects(in *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+	for _, o := range in.Delete.Objects {
+		delete(f.objects[*in.Bucket], *o.Key)
+	}
+	return &s3.DeleteObjectsOutput{}, nil
+}
+
+func TestS3Store(t *testing.T) {
+	client := &fakeS3{objects: map[string]map[string][]byte{"images-a1b2c3": {}}}
+	store := NewS3Store(client, map[string]string{"images": "images-a1b2c3"})
+
+	if err := store.Write("images", "thumbs/logo.png", strings.NewReader("png")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := client.objects["images-a1b2c3"]["thumbs/logo.png"]; !ok {
+		t.Error("Write() did not use the deployed bucket name")
+	}
+
+	files, err := store.List("images", "thumbs/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Key != "thumbs/logo.png" || files[0].Size != 3 {
+		t.Errorf("List() = %v", files)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := store.Read("images", "thumbs/logo.png", buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "png" {
+		t.Errorf("Read() = %s, want png", buf.String())
+	}
+
+	if err := store.Write("videos", "intro.mp4", strings.NewReader("")); err == nil {
+		t.Error("Write() to an undeployed bucket should fail")
+	}
+
+	n, err := store.Empty("images")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || len(client.objects["images-a1b2c3"]) != 0 {
+		t.Errorf("Empty() deleted %d files, %d remain", n, len(client.objects["images-a1b2c3"]))
+	}
+}
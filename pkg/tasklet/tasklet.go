@@ -20,11 +20,14 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pterm/pterm"
 
 	"github.com/nitrictech/cli/pkg/output"
+	"github.com/nitrictech/cli/pkg/utils"
 )
 
 var defaultSequence = []string{"⠟", "⠯", "⠷", "⠾", "⠽", "⠻"}
@@ -37,14 +40,162 @@ type Runner struct {
 	StopMsg  string
 }
 
+// Group is a set of Runners that are run concurrently under a single spinner.
+type Group struct {
+	Runners  []Runner
+	StartMsg string
+	StopMsg  string
+}
+
 type Opts struct {
 	Signal        chan os.Signal
 	Timeout       time.Duration
 	SuccessPrefix string
+	// Timings records how long the tasklet took under Stage, if set
+	Timings *Timings
+	Stage   string
 }
 
-type taskletContext struct {
+// display is the spinner shared by the running tasks. In CI mode there is no spinner,
+// status changes are printed as lines instead.
+type display struct {
+	lock    sync.Mutex
 	spinner *pterm.SpinnerPrinter
+	success pterm.TextPrinter
+	tasks   []string
+	texts   map[string]string
+	percent map[string]int
+}
+
+func newDisplay(startMsg string, opts Opts) (*display, error) {
+	d := &display{
+		success: &pterm.Success,
+		texts:   map[string]string{},
+		percent: map[string]int{},
+	}
+
+	if opts.SuccessPrefix != "" {
+		d.success = &pterm.PrefixPrinter{
+			MessageStyle: &pterm.ThemeDefault.SuccessMessageStyle,
+			Prefix: pterm.Prefix{
+				Style: &pterm.ThemeDefault.SuccessPrefixStyle,
+				Text:  opts.SuccessPrefix,
+			},
+		}
+	}
+
+	if output.CI {
+		pterm.Info.Println(startMsg)
+		return d, nil
+	}
+
+	spinner, err := pterm.DefaultSpinner.WithShowTimer().WithSequence(defaultSequence...).Start(startMsg)
+	if err != nil {
+		return nil, err
+	}
+	spinner.SuccessPrinter = d.success
+	d.spinner = spinner
+
+	return d, nil
+}
+
+// label is how a task's status is shown, tasks of a group are named by their StartMsg.
+func label(task, text string) string {
+	if task == "" || task == text {
+		return text
+	}
+	return task + ": " + text
+}
+
+func (d *display) update(task, text string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.setText(task, text)
+}
+
+// progress updates the task text, in CI mode it is only printed every 10%.
+func (d *display) progress(task, text string, percent int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	last, ok := d.percent[task]
+	d.percent[task] = percent
+	if d.spinner == nil && ok && last/10 == percent/10 && percent != 100 {
+		d.texts[task] = text
+		return
+	}
+	d.setText(task, text)
+}
+
+func (d *display) setText(task, text string) {
+	old, ok := d.texts[task]
+	if !ok {
+		d.tasks = append(d.tasks, task)
+	}
+	d.texts[task] = text
+
+	if d.spinner == nil {
+		if old != text {
+			pterm.Info.Println(label(task, text))
+		}
+		return
+	}
+
+	labels := []string{}
+	for _, t := range d.tasks {
+		labels = append(labels, label(t, d.texts[t]))
+	}
+	d.spinner.UpdateText(strings.Join(labels, " | "))
+}
+
+func (d *display) remove(task string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	for i, t := range d.tasks {
+		if t == task {
+			d.tasks = append(d.tasks[:i], d.tasks[i+1:]...)
+			break
+		}
+	}
+	delete(d.texts, task)
+	delete(d.percent, task)
+}
+
+func (d *display) successf(format string, a ...interface{}) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.success.Printf(format, a...)
+}
+
+func (d *display) failf(format string, a ...interface{}) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	pterm.Error.Printf(format, a...)
+}
+
+func (d *display) fail(err error) {
+	if d.spinner == nil {
+		pterm.Error.Println(err)
+		return
+	}
+	d.spinner.Fail(err)
+}
+
+func (d *display) stop() {
+	if d.spinner != nil {
+		_ = d.spinner.Stop()
+	}
+}
+
+type taskletContext struct {
+	d      *display
+	task   string
+	prefix string
+	depth  int
 }
 
 var _ output.Progress = &taskletContext{}
@@ -54,80 +205,131 @@ func (c *taskletContext) Debugf(format string, a ...interface{}) {
 }
 
 func (c *taskletContext) Busyf(format string, a ...interface{}) {
-	c.spinner.UpdateText(fmt.Sprintf(format, a...))
+	c.d.update(c.task, c.prefix+fmt.Sprintf(format, a...))
+}
+
+func (c *taskletContext) Progressf(done, total int, format string, a ...interface{}) {
+	percent := 100
+	if total > 0 {
+		percent = done * 100 / total
+	}
+	c.d.progress(c.task, fmt.Sprintf("%s%s [%d/%d %d%%]", c.prefix, fmt.Sprintf(format, a...), done, total, percent), percent)
 }
 
 func (c *taskletContext) Successf(format string, a ...interface{}) {
-	c.spinner.SuccessPrinter.Printf(format, a...)
+	c.d.successf(strings.Repeat("  ", c.depth)+format, a...)
 }
 
 func (c *taskletContext) Failf(format string, a ...interface{}) {
-	pterm.Error.Printf(format, a...)
+	c.d.failf(strings.Repeat("  ", c.depth)+format, a...)
 }
 
-func MustRun(runner Runner, opts Opts) {
-	if Run(runner, opts) != nil {
-		os.Exit(1)
+func (c *taskletContext) SubTask(name string) output.Progress {
+	return &taskletContext{
+		d:      c.d,
+		task:   c.task,
+		prefix: c.prefix + name + " › ",
+		depth:  c.depth + 1,
 	}
 }
 
+// Run runs the runner showing its progress, a failure is shown and returned as already reported.
 func Run(runner Runner, opts Opts) error {
-	spinner, err := pterm.DefaultSpinner.WithShowTimer().WithSequence(defaultSequence...).Start(runner.StartMsg)
+	d, err := newDisplay(runner.StartMsg, opts)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		_ = spinner.Stop()
-	}()
+	defer d.stop()
 
-	if opts.SuccessPrefix != "" {
-		spinner.SuccessPrinter = &pterm.PrefixPrinter{
-			MessageStyle: &pterm.ThemeDefault.SuccessMessageStyle,
-			Prefix: pterm.Prefix{
-				Style: &pterm.ThemeDefault.SuccessPrefixStyle,
-				Text:  opts.SuccessPrefix,
-			},
+	tCtx := &taskletContext{d: d}
+
+	elapsed, err := execute(func() error { return runner.Runner(tCtx) }, opts)
+	if err != nil {
+		d.fail(err)
+		return utils.NewReportedErr(err)
+	}
+
+	d.success.Printf("%s (%s)", runner.StopMsg, elapsed.Round(time.Second).String())
+
+	return nil
+}
+
+// RunConcurrently runs the group's Runners in parallel, the spinner shows each busy runner and
+// a runner's StopMsg is printed as it finishes. The first error is returned once all have finished.
+func RunConcurrently(group Group, opts Opts) error {
+	d, err := newDisplay(group.StartMsg, opts)
+	if err != nil {
+		return err
+	}
+	defer d.stop()
+
+	elapsed, err := execute(func() error {
+		errs := make(chan error, len(group.Runners))
+		for _, r := range group.Runners {
+			go func(r Runner) {
+				start := time.Now()
+				d.update(r.StartMsg, r.StartMsg)
+
+				err := r.Runner(&taskletContext{d: d, task: r.StartMsg})
+				d.remove(r.StartMsg)
+				if err != nil {
+					d.failf("%s: %v\n", r.StartMsg, err)
+				} else {
+					d.successf("%s (%s)\n", r.StopMsg, time.Since(start).Round(time.Second).String())
+				}
+				errs <- err
+			}(r)
 		}
+
+		var first error
+		for range group.Runners {
+			if err := <-errs; err != nil && first == nil {
+				first = err
+			}
+		}
+		return first
+	}, opts)
+	if err != nil {
+		d.fail(err)
+		return utils.NewReportedErr(err)
 	}
 
-	tCtx := &taskletContext{spinner: spinner}
+	d.success.Printf("%s (%s)", group.StopMsg, elapsed.Round(time.Second).String())
 
+	return nil
+}
+
+// execute runs fn until it returns, times out or is interrupted by opts.Signal.
+func execute(fn func() error, opts Opts) (time.Duration, error) {
 	start := time.Now()
-	done := make(chan bool, 1)
-	doErr := make(chan error, 1)
+	done := make(chan error, 1)
 
 	if opts.Timeout == 0 {
 		opts.Timeout = time.Hour // our infinite
 	}
 	timer := time.NewTimer(opts.Timeout)
+	defer timer.Stop()
 
 	go func() {
-		err = runner.Runner(tCtx)
-		if err != nil {
-			doErr <- err
-		}
-		done <- true
+		done <- fn()
 	}()
+
+	var err error
 	select {
-	case err = <-doErr:
+	case err = <-done:
 	case <-timer.C:
 		err = errors.New("tasklet timedout after " + opts.Timeout.String())
-	case <-done:
 	case <-opts.Signal:
 		fmt.Println("Shutting down services - exiting")
 	}
 
 	elapsed := time.Since(start)
+	if opts.Timings != nil && opts.Stage != "" {
+		opts.Timings.Add(opts.Stage, elapsed)
+	}
 	if elapsed < time.Second {
 		time.Sleep(time.Second - elapsed)
 	}
 
-	if err != nil {
-		spinner.Fail(err)
-		return err
-	}
-
-	spinner.SuccessPrinter.Printf("%s (%s)", runner.StopMsg, elapsed.Round(time.Second).String())
-
-	return nil
+	return elapsed, err
 }
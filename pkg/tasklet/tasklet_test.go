@@ -62,3 +62,45 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+func TestRunTimings(t *testing.T) {
+	timings := NewTimings()
+	runner := Runner{
+		Runner: func(log output.Progress) error { return nil },
+	}
+
+	if err := Run(runner, Opts{Timings: timings, Stage: "build"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Run(runner, Opts{Timings: timings}); err != nil {
+		t.Fatal(err)
+	}
+
+	stages := timings.Stages()
+	if len(stages) != 1 || stages[0].Name != "build" {
+		t.Errorf("Stages() = %v, want a single build stage", stages)
+	}
+}
+
+func TestRunConcurrently(t *testing.T) {
+	busy := func(log output.Progress) error {
+		sub := log.SubTask("step")
+		for i := 1; i <= 3; i++ {
+			sub.Progressf(i, 3, "working")
+		}
+		return nil
+	}
+	group := Group{
+		StartMsg: "Building",
+		Runners: []Runner{
+			{StartMsg: "a", Runner: busy},
+			{StartMsg: "b", Runner: func(log output.Progress) error { return errors.New("bang!") }},
+			{StartMsg: "c", Runner: busy},
+		},
+	}
+
+	err := RunConcurrently(group, Opts{})
+	if err == nil || err.Error() != "bang!" {
+		t.Errorf("RunConcurrently() error = %v, want bang!", err)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package tasklet
+
+import (
+	"sync"
+	"time"
+)
+
+// Timing is how long a named stage (or resource) took.
+type Timing struct {
+	Name    string        `json:"name"`
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// Timings collects the elapsed time of the tasklets run with it in their Opts.
+type Timings struct {
+	lock   sync.Mutex
+	stages []Timing
+}
+
+func NewTimings() *Timings {
+	return &Timings{stages: []Timing{}}
+}
+
+func (t *Timings) Add(name string, elapsed time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.stages = append(t.stages, Timing{Name: name, Elapsed: elapsed})
+}
+
+// Stages returns the recorded timings in the order they were added.
+func (t *Timings) Stages() []Timing {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return append([]Timing{}, t.stages...)
+}
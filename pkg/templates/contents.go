@@ -109,6 +109,33 @@ func officialStackName(name string) string {
 }
 
 func (d *downloader) repository() error {
+	if utils.Offline {
+		// use the list from the last time the templates were downloaded
+		if _, err := os.Stat(d.configPath); err != nil {
+			return utils.RequireNetwork("listing the templates for the first time")
+		}
+	} else if err := d.downloadRepository(); err != nil {
+		return err
+	}
+
+	list, err := d.readTemplatesConfig()
+	if err != nil {
+		return err
+	}
+
+	d.repo = []TemplateInfo{}
+	for _, template := range list {
+		d.repo = append(d.repo, TemplateInfo{
+			Name: officialStackName(template.Name),
+			Path: filepath.Clean(template.Path),
+		})
+	}
+
+	return nil
+}
+
+// downloadRepository downloads the list of templates to configPath.
+func (d *downloader) downloadRepository() error {
 	src := rawGitHubURL + "/" + filepath.Join(templatesRepo, "main/repository.yaml")
 	client := d.newGetter(&getter.Client{
 		Ctx: context.Background(),
@@ -127,22 +154,8 @@ func (d *downloader) repository() error {
 
 	// download file
 	if err := client.Get(); err != nil {
-		return fmt.Errorf("error getting path %s: %w", src, err)
-	}
-
-	list, err := d.readTemplatesConfig()
-	if err != nil {
-		return err
-	}
-
-	d.repo = []TemplateInfo{}
-	for _, template := range list {
-		d.repo = append(d.repo, TemplateInfo{
-			Name: officialStackName(template.Name),
-			Path: filepath.Clean(template.Path),
-		})
+		return utils.NetworkErr(fmt.Errorf("error getting path %s: %w", src, err), "listing the templates")
 	}
-
 	return nil
 }
 
@@ -157,6 +170,10 @@ func (d *downloader) DownloadDirectoryContents(name string, destDir string, forc
 		return fmt.Errorf("template %s not found", name)
 	}
 
+	if err := utils.RequireNetwork("downloading the template " + name); err != nil {
+		return err
+	}
+
 	client := d.newGetter(&getter.Client{
 		Ctx: context.Background(),
 		//define the destination to where the directory will be stored. This will create the directory if it doesnt exist
@@ -176,5 +193,5 @@ func (d *downloader) DownloadDirectoryContents(name string, destDir string, forc
 	})
 
 	err = client.Get()
-	return errors.WithMessagef(err, "error getting path %s", templatesRepoGitURL+"//"+template.Path)
+	return utils.NetworkErr(errors.WithMessagef(err, "error getting path %s", templatesRepoGitURL+"//"+template.Path), "downloading the template "+name)
 }
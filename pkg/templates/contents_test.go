@@ -17,7 +17,9 @@
 package templates
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -83,3 +85,52 @@ func TestRepository(t *testing.T) {
 		t.Errorf("downloader.repository() = %v, want %v", d.repo, wantRepo)
 	}
 }
+
+func TestRepositoryOffline(t *testing.T) {
+	utils.Offline = true
+	t.Cleanup(func() { utils.Offline = false })
+
+	configPath := filepath.Join(t.TempDir(), "repositories.yml")
+	d := &downloader{
+		configPath: configPath,
+		newGetter: func(c *getter.Client) utils.GetterClient {
+			t.Fatal("the templates were downloaded while offline")
+			return nil
+		},
+	}
+	if err := d.repository(); !errors.Is(err, utils.ErrOffline) {
+		t.Errorf("downloader.repository() error = %v, want ErrOffline without a cached list", err)
+	}
+
+	b, err := yaml.Marshal(&repository{Templates: []TemplateInfo{{Name: "Go Stack", Path: "./go-stack"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.repository(); err != nil {
+		t.Fatalf("downloader.repository() error = %v", err)
+	}
+	wantRepo := []TemplateInfo{{Name: "official/Go Stack", Path: "go-stack"}}
+	if !reflect.DeepEqual(d.repo, wantRepo) {
+		t.Errorf("downloader.repository() = %v, want %v", d.repo, wantRepo)
+	}
+}
+
+func TestWriteExamples(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := WriteExamples("functions/*/*.go", dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"api/main.go", "schedule/main.go", "subscriber/main.go", "worker/main.go"} {
+		if _, err := os.Stat(filepath.Join(dir, "functions", f)); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if err := WriteExamples("functions/*.java", dir); err == nil {
+		t.Error("expected an error for java examples")
+	}
+}
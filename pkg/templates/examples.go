@@ -0,0 +1,77 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templates
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// examples are stored with a .tmpl suffix so they are not built as part of the CLI
+//
+//go:embed examples
+var examples embed.FS
+
+// examplesDir returns the directory the handler glob is rooted in, e.g. functions for functions/*/*.go
+func examplesDir(handlerGlob string) string {
+	parts := strings.Split(filepath.ToSlash(handlerGlob), "/")
+	for i, p := range parts {
+		if strings.ContainsAny(p, "*?[") {
+			return filepath.Join(parts[:i]...)
+		}
+	}
+	return filepath.Dir(handlerGlob)
+}
+
+// WriteExamples writes example handlers using an api, topic, schedule, bucket and queue
+// in the language of the handler glob.
+func WriteExamples(handlerGlob, projectDir string) error {
+	lang := strings.TrimPrefix(filepath.Ext(handlerGlob), ".")
+	root := "examples/" + lang
+
+	if _, err := fs.Stat(examples, root); err != nil {
+		return fmt.Errorf("examples are not available for %s handlers", lang)
+	}
+
+	destDir := filepath.Join(projectDir, examplesDir(handlerGlob))
+
+	return fs.WalkDir(examples, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		b, err := examples.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		dest := filepath.Join(destDir, strings.TrimSuffix(strings.TrimPrefix(path, root+"/"), ".tmpl"))
+		if _, err := os.Stat(dest); err == nil {
+			// don't replace the template's handlers
+			return nil
+		}
+
+		if err := os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+			return err
+		}
+		return os.WriteFile(dest, b, 0644)
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Difference is a setting that differs between two configurations, a missing setting is empty.
+type Difference struct {
+	Key    string `yaml:"key"`
+	Change string `yaml:"change"`
+	From   string `yaml:"from"`
+	To     string `yaml:"to"`
+}
+
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// Diff compares the yaml of from and to setting by setting, returning the differences sorted by key.
+func Diff(from, to interface{}) ([]Difference, error) {
+	f, err := Flatten(from)
+	if err != nil {
+		return nil, err
+	}
+	t, err := Flatten(to)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]bool{}
+	for k := range f {
+		keys[k] = true
+	}
+	for k := range t {
+		keys[k] = true
+	}
+
+	diffs := []Difference{}
+	for k := range keys {
+		fv, inFrom := f[k]
+		tv, inTo := t[k]
+		switch {
+		case !inFrom:
+			diffs = append(diffs, Difference{Key: k, Change: DiffAdded, To: tv})
+		case !inTo:
+			diffs = append(diffs, Difference{Key: k, Change: DiffRemoved, From: fv})
+		case fv != tv:
+			diffs = append(diffs, Difference{Key: k, Change: DiffChanged, From: fv, To: tv})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+	return diffs, nil
+}
+
+// Flatten returns the settings in the yaml of v keyed by their path, e.g. functions.hello.memory or cdn.domains[0].
+func Flatten(v interface{}) (map[string]string, error) {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := yaml.Unmarshal(b, &generic); err != nil {
+		return nil, err
+	}
+
+	res := map[string]string{}
+	flatten("", generic, res)
+	return res, nil
+}
+
+func flatten(prefix string, v interface{}, res map[string]string) {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		for k, child := range t {
+			key := fmt.Sprint(k)
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			flatten(key, child, res)
+		}
+	case []interface{}:
+		for i, child := range t {
+			flatten(fmt.Sprintf("%s[%d]", prefix, i), child, res)
+		}
+	case nil:
+		// empty settings are the same as missing ones
+	default:
+		res[prefix] = fmt.Sprint(t)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	type settings struct {
+		Region  string            `yaml:"region,omitempty"`
+		Memory  int               `yaml:"memory,omitempty"`
+		Domains []string          `yaml:"domains,omitempty"`
+		Extra   map[string]string `yaml:"extra,omitempty"`
+	}
+
+	staging := settings{
+		Region:  "us-east-1",
+		Memory:  128,
+		Domains: []string{"staging.example.com"},
+		Extra:   map[string]string{"debug": "true"},
+	}
+	prod := settings{
+		Region:  "us-east-1",
+		Memory:  1024,
+		Domains: []string{"example.com", "www.example.com"},
+	}
+
+	got, err := Diff(staging, prod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Difference{
+		{Key: "domains[0]", Change: DiffChanged, From: "staging.example.com", To: "example.com"},
+		{Key: "domains[1]", Change: DiffAdded, To: "www.example.com"},
+		{Key: "extra.debug", Change: DiffRemoved, From: "true"},
+		{Key: "memory", Change: DiffChanged, From: "128", To: "1024"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+}
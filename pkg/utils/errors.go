@@ -18,6 +18,7 @@ package utils
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"sync"
 )
@@ -94,3 +95,35 @@ func NewNotSupportedErr(message string) error {
 func (*NotSupportedError) Is(err error) bool {
 	return strings.Contains(err.Error(), "unsupported") || strings.Contains(err.Error(), "not supported")
 }
+
+// ExitError ends the cli with Code. Reported is true when Err has already been shown to the user,
+// e.g. by a failed tasklet, so it isn't shown again.
+type ExitError struct {
+	Err      error
+	Code     int
+	Reported bool
+}
+
+// NewExitErr ends the cli with code, showing err when it is not nil.
+func NewExitErr(code int, err error) error {
+	return &ExitError{Err: err, Code: code, Reported: err == nil}
+}
+
+// NewReportedErr marks an error that has already been shown to the user.
+func NewReportedErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitError{Err: err, Code: 1, Reported: true}
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
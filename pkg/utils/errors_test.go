@@ -60,3 +60,56 @@ func TestErrorList_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestExitError(t *testing.T) {
+	cause := errors.New("bang")
+	tests := []struct {
+		name         string
+		err          error
+		wantError    string
+		wantCode     int
+		wantReported bool
+	}{
+		{
+			name:         "reported",
+			err:          NewReportedErr(cause),
+			wantError:    "bang",
+			wantCode:     1,
+			wantReported: true,
+		},
+		{
+			name:      "exit code",
+			err:       NewExitErr(2, cause),
+			wantError: "bang",
+			wantCode:  2,
+		},
+		{
+			name:         "no error to show",
+			err:          NewExitErr(1, nil),
+			wantError:    "exit status 1",
+			wantCode:     1,
+			wantReported: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var exitErr *ExitError
+			if !errors.As(tt.err, &exitErr) {
+				t.Fatalf("errors.As() = false, want an ExitError")
+			}
+			if got := tt.err.Error(); got != tt.wantError {
+				t.Errorf("ExitError.Error() = %v, want %v", got, tt.wantError)
+			}
+			if exitErr.Code != tt.wantCode || exitErr.Reported != tt.wantReported {
+				t.Errorf("ExitError = %d %v, want %d %v", exitErr.Code, exitErr.Reported, tt.wantCode, tt.wantReported)
+			}
+			if exitErr.Err != nil && !errors.Is(tt.err, cause) {
+				t.Errorf("errors.Is() = false, want the cause")
+			}
+		})
+	}
+
+	if NewReportedErr(nil) != nil {
+		t.Errorf("NewReportedErr(nil) != nil")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var interpolation = regexp.MustCompile(`\$\{([a-zA-Z]+):([^}]+)\}`)
+
+// Interpolate replaces ${env:VAR} with the environment variable and ${kind:name} with vars["kind:name"]
+// (e.g. ${stack:name}, ${target:region} or ${param:tenant}). References to stack and target values are
+// left in place when they are not in vars, so the config can be interpolated again once the stack is
+// known, as are ${encrypted:ciphertext} values. Parameters must be in vars.
+func Interpolate(in []byte, vars map[string]string) ([]byte, error) {
+	errs := NewErrorList()
+
+	out := interpolation.ReplaceAllFunc(in, func(ref []byte) []byte {
+		m := interpolation.FindSubmatch(ref)
+		kind, name := string(m[1]), strings.TrimSpace(string(m[2]))
+
+		switch kind {
+		case "env":
+			if v, ok := os.LookupEnv(name); ok {
+				return []byte(v)
+			}
+			errs.Add(fmt.Errorf("environment variable %s is not set", name))
+		case "stack", "target":
+			if v, ok := vars[kind+":"+name]; ok {
+				return []byte(v)
+			}
+		case "param":
+			if v, ok := vars[kind+":"+name]; ok {
+				return []byte(v)
+			}
+			errs.Add(fmt.Errorf("parameter %s is not set, set it with --param %s=<value> or give it a default in params", name, name))
+		case "encrypted":
+			// decrypted with the stack's key when it is deployed
+		default:
+			errs.Add(fmt.Errorf("unknown interpolation %s", ref))
+		}
+		return ref
+	})
+
+	return out, errs.Aggregate()
+}
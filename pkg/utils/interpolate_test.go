@@ -0,0 +1,92 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"os"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	os.Setenv("NITRIC_TEST_PREFIX", "acme")
+	defer os.Unsetenv("NITRIC_TEST_PREFIX")
+
+	tests := []struct {
+		name    string
+		in      string
+		vars    map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "env",
+			in:   "prefix: ${env:NITRIC_TEST_PREFIX}-files",
+			want: "prefix: acme-files",
+		},
+		{
+			name: "stack and target",
+			in:   "image: ${stack:name}-${target:region}",
+			vars: map[string]string{"stack:name": "prod", "target:region": "us-east-1"},
+			want: "image: prod-us-east-1",
+		},
+		{
+			name: "stack unknown",
+			in:   "image: ${stack:name}",
+			want: "image: ${stack:name}",
+		},
+		{
+			name: "encrypted",
+			in:   "webhook: ${encrypted:AQICAHh0c2VjcmV0}",
+			want: "webhook: ${encrypted:AQICAHh0c2VjcmV0}",
+		},
+		{
+			name: "param",
+			in:   "domains: [${param:tenant}.example.com]",
+			vars: map[string]string{"param:tenant": "acme"},
+			want: "domains: [acme.example.com]",
+		},
+		{
+			name:    "param missing",
+			in:      "name: shop-${param:tenant}",
+			want:    "name: shop-${param:tenant}",
+			wantErr: true,
+		},
+		{
+			name:    "env missing",
+			in:      "prefix: ${env:NITRIC_TEST_MISSING}",
+			want:    "prefix: ${env:NITRIC_TEST_MISSING}",
+			wantErr: true,
+		},
+		{
+			name:    "unknown kind",
+			in:      "prefix: ${file:x}",
+			want:    "prefix: ${file:x}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Interpolate([]byte(tt.in), tt.vars)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Interpolate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Interpolate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Offline avoids downloads, using what is cached instead, set by --offline or NITRIC_OFFLINE.
+var Offline = os.Getenv("NITRIC_OFFLINE") != ""
+
+// ErrOffline is returned by the operations that need the network when nitric is offline.
+var ErrOffline = errors.New("nitric is running with --offline")
+
+// RequireNetwork returns an error naming what needs the network when nitric is offline.
+func RequireNetwork(what string) error {
+	if Offline {
+		return fmt.Errorf("%s needs network access: %w", what, ErrOffline)
+	}
+	return nil
+}
+
+// proxyVars are the proxy settings docker passes to builds without them being declared with ARG.
+var proxyVars = []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "ALL_PROXY"}
+
+// ProxyEnv returns the proxy settings of the environment in upper and lower case, as tools differ in
+// which they read, for the builds and containers that don't inherit the environment.
+func ProxyEnv() map[string]string {
+	env := map[string]string{}
+	for _, k := range proxyVars {
+		v := os.Getenv(k)
+		if v == "" {
+			v = os.Getenv(strings.ToLower(k))
+		}
+		if v != "" {
+			env[k] = v
+			env[strings.ToLower(k)] = v
+		}
+	}
+	return env
+}
+
+// redactProxy removes the password from a proxy URL.
+func redactProxy(proxy string) string {
+	u, err := url.Parse(proxy)
+	if err != nil || u.User == nil {
+		return proxy
+	}
+	return u.Redacted()
+}
+
+// NetworkErr adds the proxy to network errors, which are otherwise hard to tell apart from the
+// remote being down when a corporate proxy is needed or is rejecting the request.
+func NetworkErr(err error, what string) error {
+	var netErr net.Error
+	if err == nil || !errors.As(err, &netErr) {
+		return err
+	}
+	if proxy, ok := ProxyEnv()["HTTPS_PROXY"]; ok {
+		return fmt.Errorf("%s through the proxy %s: %w", what, redactProxy(proxy), err)
+	}
+	return fmt.Errorf("%s: %w, set HTTPS_PROXY when the network needs a proxy", what, err)
+}
@@ -80,8 +80,11 @@ func NitricTemplatesDir() string {
 	return filepath.Join(homeDir(), "store")
 }
 
-// NitricConfigDir returns the directory to find configuration.
+// NitricConfigDir returns the directory to find configuration, $XDG_CONFIG_HOME/nitric when it is set.
 func NitricConfigDir() string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "nitric")
+	}
 	if runtime.GOOS == "linux" {
 		dirname, err := os.UserHomeDir()
 		if err != nil {
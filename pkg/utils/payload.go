@@ -0,0 +1,51 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// ReadPayload reads a JSON object from the argument, the file or, when neither is given (or the file is "-"), stdin.
+func ReadPayload(args []string, file string, stdin io.Reader) (map[string]interface{}, error) {
+	var data []byte
+	var err error
+
+	switch {
+	case len(args) > 0 && file != "":
+		return nil, errors.New("give the payload as an argument or with --file, not both")
+	case len(args) > 0:
+		data = []byte(args[0])
+	case file != "" && file != "-":
+		data, err = ioutil.ReadFile(file)
+	default:
+		data, err = ioutil.ReadAll(stdin)
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "reading payload")
+	}
+
+	payload := map[string]interface{}{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, errors.WithMessage(err, "the payload must be a JSON object")
+	}
+	return payload, nil
+}
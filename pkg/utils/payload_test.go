@@ -0,0 +1,86 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadPayload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "payload.json")
+	if err := os.WriteFile(file, []byte(`{"from": "file"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		file    string
+		stdin   string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "argument",
+			args: []string{`{"from": "arg", "count": 2}`},
+			want: map[string]interface{}{"from": "arg", "count": float64(2)},
+		},
+		{
+			name: "file",
+			file: file,
+			want: map[string]interface{}{"from": "file"},
+		},
+		{
+			name:  "stdin",
+			stdin: `{"from": "stdin"}`,
+			want:  map[string]interface{}{"from": "stdin"},
+		},
+		{
+			name:  "dash reads stdin",
+			file:  "-",
+			stdin: `{"from": "stdin"}`,
+			want:  map[string]interface{}{"from": "stdin"},
+		},
+		{
+			name:    "argument and file",
+			args:    []string{`{}`},
+			file:    file,
+			wantErr: true,
+		},
+		{
+			name:    "not an object",
+			args:    []string{`[1, 2]`},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadPayload(tt.args, tt.file, strings.NewReader(tt.stdin))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadPayload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !cmp.Equal(tt.want, got) {
+				t.Error(cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
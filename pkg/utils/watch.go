@@ -0,0 +1,147 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Watcher polls a directory for changed files. Polling works the same on every platform and on
+// mounted or network file systems, where change notifications are unreliable.
+type Watcher struct {
+	Dir string
+	// Interval is the time between polls
+	Interval time.Duration
+	// Debounce is how long the files must stay unchanged before the changes are reported
+	Debounce time.Duration
+	// Skip are the names of directories that aren't watched
+	Skip []string
+
+	files map[string]time.Time
+}
+
+// NewWatcher watches dir, skipping the directories of version control, build output and dependencies.
+func NewWatcher(dir string, debounce time.Duration) *Watcher {
+	return &Watcher{
+		Dir:      dir,
+		Interval: time.Second,
+		Debounce: debounce,
+		Skip:     []string{".git", ".nitric", "node_modules", "__pycache__", ".venv"},
+	}
+}
+
+// snapshot returns the modification time of each file by path relative to Dir.
+func (w *Watcher) snapshot() (map[string]time.Time, error) {
+	files := map[string]time.Time{}
+	err := filepath.Walk(w.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// files can be removed while walking
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			for _, s := range w.Skip {
+				if info.Name() == s {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(w.Dir, path)
+		if err != nil {
+			return err
+		}
+		files[rel] = info.ModTime()
+		return nil
+	})
+	return files, err
+}
+
+// Changed returns the files created, modified or deleted since it was last called, the first call
+// records the files and returns none.
+func (w *Watcher) Changed() ([]string, error) {
+	files, err := w.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	if w.files == nil {
+		w.files = files
+		return nil, nil
+	}
+
+	changed := []string{}
+	for f, t := range files {
+		if prev, ok := w.files[f]; !ok || !prev.Equal(t) {
+			changed = append(changed, f)
+		}
+	}
+	for f := range w.files {
+		if _, ok := files[f]; !ok {
+			changed = append(changed, f)
+		}
+	}
+	sort.Strings(changed)
+	w.files = files
+	return changed, nil
+}
+
+// Watch calls onChange with the changed files once they have stayed unchanged for Debounce, until stop
+// is closed. Changes made while onChange runs are reported by the following call.
+func (w *Watcher) Watch(stop <-chan struct{}, onChange func(files []string)) error {
+	if _, err := w.Changed(); err != nil {
+		return err
+	}
+
+	pending := map[string]bool{}
+	var last time.Time
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+
+		changed, err := w.Changed()
+		if err != nil {
+			return err
+		}
+		for _, f := range changed {
+			pending[f] = true
+		}
+		if len(changed) > 0 {
+			last = time.Now()
+		}
+		if len(pending) == 0 || time.Since(last) < w.Debounce {
+			continue
+		}
+
+		files := []string{}
+		for f := range pending {
+			files = append(files, f)
+		}
+		sort.Strings(files)
+		pending = map[string]bool{}
+		onChange(files)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright Nitric Pty Ltd.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatcherChanged(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string) {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("main.go")
+	w := NewWatcher(dir, 0)
+	if got, err := w.Changed(); err != nil || len(got) != 0 {
+		t.Fatalf("Changed() = %v, %v, want no changes on the first call", got, err)
+	}
+
+	write("functions/orders.go")
+	write("node_modules/dep/index.js")
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, "main.go"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	got, err := w.Changed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join("functions", "orders.go"), "main.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Changed() = %v, want %v", got, want)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "main.go")); err != nil {
+		t.Fatal(err)
+	}
+	got, err = w.Changed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"main.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Changed() = %v, want %v", got, want)
+	}
+}
+
+func TestWatcherWatch(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWatcher(dir, 50*time.Millisecond)
+	w.Interval = 10 * time.Millisecond
+
+	stop := make(chan struct{})
+	calls := [][]string{}
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		for _, f := range []string{"a.go", "b.go"} {
+			if err := os.WriteFile(filepath.Join(dir, f), []byte(f), 0o644); err != nil {
+				t.Error(err)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}()
+
+	err := w.Watch(stop, func(files []string) {
+		calls = append(calls, files)
+		close(stop)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]string{{"a.go", "b.go"}}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Watch() calls = %v, want %v", calls, want)
+	}
+}